@@ -0,0 +1,506 @@
+// Code generated by go run ./cmd/openapi; DO NOT EDIT.
+// Article Assistant API 1.0.0 request and response types.
+
+export interface APIError {
+  code: string;
+  details?: unknown;
+  message: string;
+  request_id?: string;
+}
+
+export interface Alert {
+  article_id: string;
+  created_at: string;
+  id: string;
+  search_id: string;
+  sentiment?: string;
+  sentiment_score: number;
+  title: string;
+  url: string;
+}
+
+export interface AlertList {
+  alerts: Alert[] | null;
+}
+
+export interface AnalysisVersionCount {
+  articles: number;
+  version: string;
+}
+
+export interface Article {
+  analysis_model?: string;
+  analysis_version?: string;
+  author?: string;
+  canonical_id?: string;
+  content?: string;
+  content_hash?: string;
+  created_at: string;
+  embedding: number[] | null;
+  embedding_model?: string;
+  entities: SemanticEntity[] | null;
+  id: string;
+  imported?: boolean;
+  keywords: SemanticKeyword[] | null;
+  language?: string;
+  last_refreshed_at?: string;
+  published_at?: string;
+  score?: number;
+  section?: string;
+  sentiment: string;
+  sentiment_score: number;
+  source_domain?: string;
+  status: string;
+  status_error?: string;
+  summary: string;
+  summary_model?: string;
+  summary_version?: string;
+  title: string;
+  tone: string;
+  topic_ids?: string[];
+  topics: SemanticTopic[] | null;
+  updated_at: string;
+  url: string;
+  url_hash: string;
+}
+
+export interface ArticleListItem {
+  analysis_model?: string;
+  analysis_version?: string;
+  author?: string;
+  created_at: string;
+  id: string;
+  published_at?: string;
+  section?: string;
+  sentiment: string;
+  sentiment_score: number;
+  status: string;
+  status_error?: string;
+  summary_model?: string;
+  summary_version?: string;
+  title: string;
+  url: string;
+}
+
+export interface ArticleNote {
+  article_id: string;
+  created_at: string;
+  id: string;
+  text: string;
+}
+
+export interface ArticlePage {
+  articles: ArticleListItem[] | null;
+  limit: number;
+  offset: number;
+  total: number;
+}
+
+export interface ArticleSummary {
+  id: string;
+  level?: string;
+  summary: string;
+  url: string;
+}
+
+export interface ArticleTags {
+  article_id?: string;
+  tags: string[] | null;
+}
+
+export interface AuditLog {
+  entries: LLMAuditEntry[] | null;
+}
+
+export interface ChaosConfig {
+  db_error_rate: number;
+  ingest_failure_rate: number;
+  llm_error_rate: number;
+  llm_latency_ms: number;
+  llm_malformed_json: boolean;
+}
+
+export interface ChaosState {
+  config: ChaosConfig;
+  stats: ChaosStats;
+}
+
+export interface ChaosStats {
+  db_errors: number;
+  ingest_failures: number;
+  llm_errors: number;
+  llm_malformed: number;
+}
+
+export interface ChatCommand {
+  args: ChatCommandArg[] | null;
+  description: string;
+  filters: boolean;
+  name: string;
+  partial: boolean;
+  relative: boolean;
+}
+
+export interface ChatCommandArg {
+  description: string;
+  name: string;
+  required: boolean;
+  type: string;
+}
+
+export interface ChatRequest {
+  date_from?: string;
+  date_to?: string;
+  limit?: number;
+  prompt_version?: string;
+  query?: string;
+  session_id?: string;
+  task?: string;
+  topic?: string;
+  urls?: string[];
+}
+
+export interface ChatResponse {
+  answer: string;
+  articles?: Article[];
+  cached: boolean;
+  data?: unknown;
+  degraded?: string[];
+  error?: APIError;
+  grounding?: Grounding;
+  pagination?: Pagination;
+  plan?: Plan;
+  prompt_version?: string;
+  response_type: string;
+  sources: Source[] | null;
+  task: string;
+  usage: Usage;
+}
+
+export interface Collection {
+  articles: number;
+  created_at: string;
+  description?: string;
+  id: string;
+  name: string;
+  updated_at: string;
+}
+
+export interface CollectionArticles {
+  article_ids?: string[];
+  urls?: string[];
+}
+
+export interface CollectionList {
+  collections: Collection[] | null;
+}
+
+export interface CommandList {
+  commands: ChatCommand[] | null;
+  common_args: ChatCommandArg[] | null;
+  filter_args: ChatCommandArg[] | null;
+}
+
+export interface DayTotal {
+  calls: number;
+  completion_tokens: number;
+  cost: number;
+  date: string;
+  prompt_tokens: number;
+  tokens: number;
+}
+
+export interface EntityDetail {
+  articles: number;
+  average_score: number;
+  category: string;
+  co_occurring: EntityNode[] | null;
+  id: string;
+  name: string;
+  negative: number;
+  neutral: number;
+  positive: number;
+  timeline: SentimentBucket[] | null;
+}
+
+export interface EntityNode {
+  articles: number;
+  category: string;
+  id: string;
+  name: string;
+}
+
+export interface EntityPage {
+  articles: ArticleListItem[] | null;
+  entity: EntityDetail | null;
+  limit: number;
+  offset: number;
+  total: number;
+}
+
+export interface FailureList {
+  failures: IngestFailure[] | null;
+}
+
+export interface Grounding {
+  low: boolean;
+  method: string;
+  score: number;
+  unsupported?: string[];
+}
+
+export interface Health {
+  status: string;
+}
+
+export interface ImportResult {
+  canonical_id?: string;
+  id?: string;
+  status: string;
+}
+
+export interface ImportedArticle {
+  analysis?: SemanticAnalysis;
+  author?: string;
+  published_at?: string;
+  section?: string;
+  summary?: string;
+  text: string;
+  title: string;
+  url: string;
+}
+
+export interface IngestAccepted {
+  id: string;
+  phase?: string;
+  status: string;
+  status_url: string;
+}
+
+export interface IngestBatchItem {
+  code?: string;
+  error?: string;
+  id?: string;
+  status: string;
+  status_url?: string;
+  url: string;
+}
+
+export interface IngestBatchRequest {
+  urls: string[] | null;
+}
+
+export interface IngestBatchResult {
+  accepted: number;
+  items: IngestBatchItem[] | null;
+  rejected: number;
+}
+
+export interface IngestFailure {
+  attempts: number;
+  error: string;
+  error_category?: string;
+  failures: number;
+  first_failed_at: string;
+  id: string;
+  last_failed_at: string;
+  url: string;
+}
+
+export interface IngestRequest {
+  url: string;
+}
+
+export interface IngestStatus {
+  attempts: number;
+  enrich_attempts?: number;
+  error?: string;
+  error_category?: string;
+  id: string;
+  phase?: string;
+  started_at: string;
+  state: string;
+  updated_at: string;
+  url: string;
+}
+
+export interface LLMAuditEntry {
+  completion_tokens: number;
+  cost: number;
+  error?: string;
+  latency_ms: number;
+  method: string;
+  model: string;
+  prompt_chars: number;
+  prompt_hash: string;
+  prompt_tokens: number;
+  prompt_version?: string;
+  provider: string;
+  request_id?: string;
+  response?: string;
+  tenant?: string;
+  time: string;
+  truncated?: boolean;
+}
+
+export interface LLMHealth {
+  targets: TargetHealth[] | null;
+}
+
+export interface NoteList {
+  notes: ArticleNote[] | null;
+}
+
+export interface Pagination {
+  limit: number;
+  offset: number;
+  total: number;
+}
+
+export interface Plan {
+  args: Record<string, unknown> | null;
+  command: string;
+  confidence?: number;
+  router?: string;
+}
+
+export interface ReanalyzeProgress {
+  done: number;
+  error?: string;
+  failed: number;
+  finished_at?: string;
+  running: boolean;
+  started_at: string;
+  total: number;
+  version: string;
+}
+
+export interface ReanalyzeStatus {
+  pending: number;
+  run?: ReanalyzeProgress;
+  version: string;
+  versions: AnalysisVersionCount[] | null;
+}
+
+export interface SavedSearch {
+  checked_at: string;
+  created_at: string;
+  id: string;
+  max_sentiment?: number;
+  min_sentiment?: number;
+  topic: string;
+}
+
+export interface SearchList {
+  searches: SavedSearch[] | null;
+}
+
+export interface SemanticAnalysis {
+  entities: SemanticEntity[] | null;
+  keywords: SemanticKeyword[] | null;
+  sentiment: string;
+  sentiment_score: number;
+  tone: string;
+  topics: SemanticTopic[] | null;
+}
+
+export interface SemanticEntity {
+  canonical_name?: string;
+  category: string;
+  confidence: number;
+  name: string;
+}
+
+export interface SemanticKeyword {
+  context: string;
+  relevance: number;
+  term: string;
+}
+
+export interface SemanticTopic {
+  description: string;
+  name: string;
+  score: number;
+}
+
+export interface SentimentBucket {
+  articles: number;
+  average_score: number;
+  negative: number;
+  neutral: number;
+  positive: number;
+  start: string;
+}
+
+export interface Source {
+  id: string;
+  reason?: string;
+  score?: number;
+  title: string;
+  url: string;
+}
+
+export interface StatusMessage {
+  message: string;
+  status: string;
+}
+
+export interface TagCount {
+  articles: number;
+  tag: string;
+}
+
+export interface TagList {
+  tags: TagCount[] | null;
+}
+
+export interface TargetHealth {
+  calls: number;
+  error_rate: number;
+  errors: Record<string, number> | null;
+  last_error?: string;
+  last_error_at?: string;
+  model: string;
+  provider: string;
+  status: string;
+  window_calls: number;
+  window_errors: number;
+}
+
+export interface TaxonomyTopic {
+  created_at: string;
+  description?: string;
+  id: string;
+  name: string;
+  parent_id?: string;
+  updated_at: string;
+}
+
+export interface TopicList {
+  topics: TaxonomyTopic[] | null;
+}
+
+export interface Usage {
+  completion_tokens: number;
+  cost: number;
+  models?: string[];
+  prompt_tokens: number;
+  tokens: number;
+}
+
+export interface UsageReport {
+  days: DayTotal[] | null;
+}
+
+export interface VectorIndex {
+  definition?: string;
+  method: string;
+  name: string;
+  rows: number;
+  size_bytes: number;
+  table: string;
+}
+
+export interface VectorIndexList {
+  indexes: VectorIndex[] | null;
+}
@@ -0,0 +1,96 @@
+// Command bot answers questions from Telegram and Discord with a running server's POST /chat.
+// Each platform with its settings present is served; answers list their sources as links.
+//
+//	TELEGRAM_BOT_TOKEN=123:abc go run ./cmd/bot
+//	DISCORD_APPLICATION_ID=... DISCORD_BOT_TOKEN=... go run ./cmd/bot -register   # create /ask once
+//	DISCORD_APPLICATION_ID=... DISCORD_PUBLIC_KEY=... DISCORD_LISTEN_ADDR=:8081 go run ./cmd/bot
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/chatbot"
+	"article-assistant/internal/cli"
+	"article-assistant/internal/config"
+)
+
+func main() {
+	register := flag.Bool("register", false, "register the Discord /ask command and exit")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	discord := &chatbot.DiscordAdapter{
+		ApplicationID: cfg.Get("DISCORD_APPLICATION_ID"),
+		PublicKey:     cfg.Get("DISCORD_PUBLIC_KEY"),
+		Addr:          cfg.Get("DISCORD_LISTEN_ADDR"),
+	}
+	if discord.Addr == "" {
+		discord.Addr = ":8081"
+	}
+	if *register {
+		token := cfg.Get("DISCORD_BOT_TOKEN")
+		if discord.ApplicationID == "" || token == "" {
+			log.Fatalf("DISCORD_APPLICATION_ID and DISCORD_BOT_TOKEN are required to register the command")
+		}
+		if err := discord.RegisterCommand(ctx, token); err != nil {
+			log.Fatalf("Failed to register the Discord command: %v", err)
+		}
+		log.Printf("Registered /%s for Discord application %s", chatbot.DiscordCommand, discord.ApplicationID)
+		return
+	}
+
+	serverURL := cfg.Get("ARTICLE_ASSISTANT_URL")
+	if serverURL == "" {
+		serverURL = cli.DefaultServerURL
+	}
+	client := api.NewClient(serverURL)
+	client.APIKey = cfg.Get("ARTICLE_ASSISTANT_API_KEY")
+
+	timeout := chatbot.DefaultTimeout
+	if v := cfg.Get("BOT_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= time.Second {
+			timeout = d
+		} else {
+			log.Printf("⚠️  Invalid BOT_TIMEOUT %q, using default %v", v, timeout)
+		}
+	}
+
+	var adapters []chatbot.Adapter
+	if token := cfg.Get("TELEGRAM_BOT_TOKEN"); token != "" {
+		adapters = append(adapters, &chatbot.TelegramAdapter{Token: token})
+	}
+	if discord.PublicKey != "" {
+		adapters = append(adapters, discord)
+	}
+	if len(adapters) == 0 {
+		log.Fatalf("No chat platform configured; set TELEGRAM_BOT_TOKEN or DISCORD_PUBLIC_KEY")
+	}
+
+	log.Printf("💬 Asking %s", serverURL)
+	var wg sync.WaitGroup
+	for _, adapter := range adapters {
+		bot := chatbot.New(adapter, client)
+		bot.Timeout = timeout
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bot.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
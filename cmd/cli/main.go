@@ -0,0 +1,35 @@
+// Command cli manages the article corpus from the shell: ingest URLs, ask questions, list and
+// export articles. It talks to a running server, or with -offline to the database directly.
+//
+//	go run ./cmd/cli ingest https://example.com/article
+//	go run ./cmd/cli chat "What is the sentiment of https://example.com/article?"
+//	go run ./cmd/cli list -limit 50
+//	go run ./cmd/cli -offline export -format csv -o articles.csv
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"article-assistant/internal/cli"
+	"article-assistant/internal/config"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := cli.Run(ctx, os.Args[1:], os.Stdout, os.Stderr, func(ctx context.Context) (cli.Backend, io.Closer, error) {
+		cfg, err := config.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		return cli.OpenOffline(ctx, cfg)
+	})
+	stop()
+	os.Exit(code)
+}
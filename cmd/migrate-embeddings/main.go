@@ -0,0 +1,118 @@
+// Command migrate-embeddings re-embeds stored articles with the configured embedding model
+// (OPENAI_EMBEDDING_MODEL, or the provider's default) after switching models. Articles already
+// embedded with it are skipped, so an interrupted run can be started again.
+//
+//	go run ./cmd/migrate-embeddings -check      # articles per embedding model
+//	go run ./cmd/migrate-embeddings -batch 100
+//	go run ./cmd/migrate-embeddings -resize     # Postgres: change the vector column dimension first
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"article-assistant/internal/cli"
+	"article-assistant/internal/config"
+	"article-assistant/internal/repository"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	batch := flag.Int("batch", 50, "articles to re-embed per batch")
+	check := flag.Bool("check", false, "only report how many articles each embedding model produced")
+	resize := flag.Bool("resize", false, "on Postgres, change the vector columns to the new model's dimension; drops every stored embedding")
+	flag.Parse()
+	if *batch < 1 {
+		log.Fatalf("-batch must be at least 1")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	offline, closer, err := cli.OpenOffline(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open the article store: %v", err)
+	}
+	defer closer.Close()
+	repo, ingester := offline.Repo, offline.Ingester
+	model := ingester.EmbeddingModel
+
+	counts, err := repo.CountEmbeddingModels(ctx)
+	if err != nil {
+		log.Fatalf("Failed to count embedding models: %v", err)
+	}
+	pending := 0
+	for _, c := range counts {
+		name := c.Model
+		if name == "" {
+			name = "(not recorded)"
+		}
+		log.Printf("%-28s %d articles", name, c.Articles)
+		if c.Model != model {
+			pending += c.Articles
+		}
+	}
+	log.Printf("%d articles to re-embed with %s", pending, model)
+	if *check || pending == 0 {
+		return
+	}
+
+	// A model with another dimension does not fit the Postgres vector columns
+	if pg, ok := repo.(*repository.Repo); ok {
+		probe, err := offline.LLM.Embed(ctx, "dimension probe")
+		if err != nil {
+			log.Fatalf("Failed to embed with %s: %v", model, err)
+		}
+		dims, err := pg.EmbeddingDimensions(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read the embedding column dimension: %v", err)
+		}
+		if dims != len(probe) {
+			if !*resize {
+				log.Fatalf("The embedding columns hold %d dimensions but %s returns %d; rerun with -resize to change them (this drops every stored embedding)", dims, model, len(probe))
+			}
+			if err := pg.ResizeEmbeddings(ctx, len(probe)); err != nil {
+				log.Fatalf("%v", err)
+			}
+			log.Printf("Resized the embedding columns from %d to %d dimensions", dims, len(probe))
+		}
+	}
+
+	done, failed, afterID := 0, 0, ""
+	for {
+		articles, err := repo.ListArticlesToReembed(ctx, model, afterID, *batch)
+		if err != nil {
+			log.Fatalf("Failed to list articles: %v", err)
+		}
+		if len(articles) == 0 {
+			break
+		}
+		for i := range articles {
+			if err := ingester.Reembed(ctx, &articles[i]); err != nil {
+				if ctx.Err() != nil {
+					log.Fatalf("Interrupted after %d of %d articles; run again to continue", done, pending)
+				}
+				log.Printf("⚠️  %s: %v", articles[i].URL, err)
+				failed++
+				continue
+			}
+			done++
+		}
+		afterID = articles[len(articles)-1].ID
+		log.Printf("Re-embedded %d/%d articles (%d%%), %d failed", done, pending, done*100/pending, failed)
+	}
+	if failed > 0 {
+		log.Fatalf("%d articles failed; run again to retry them", failed)
+	}
+	log.Printf("All articles are embedded with %s", model)
+}
@@ -0,0 +1,32 @@
+// Command openapi writes the OpenAPI document and the TypeScript types generated from it.
+//
+//	go run ./cmd/openapi [-out api]
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"article-assistant/internal/api"
+)
+
+func main() {
+	out := flag.String("out", "api", "directory to write openapi.json and types.ts to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatalf("Failed to create %s: %v", *out, err)
+	}
+	for name, data := range map[string][]byte{
+		"openapi.json": api.SpecJSON(),
+		"types.ts":     api.TypeScript(),
+	} {
+		path := filepath.Join(*out, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		log.Printf("Wrote %s", path)
+	}
+}
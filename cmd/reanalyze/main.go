@@ -0,0 +1,84 @@
+// Command reanalyze re-runs the semantic extraction (entities, keywords, topics, sentiment and
+// tone) over stored articles analyzed with an older version of it, after its prompt or parsing
+// improved. Articles already at the current version (llm.SemanticsVersion) and imported
+// articles with a supplied analysis are skipped, so an interrupted run can be started again.
+//
+//	go run ./cmd/reanalyze -check           # articles per analysis version
+//	go run ./cmd/reanalyze -batch 100 -rate 2
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"article-assistant/internal/cli"
+	"article-assistant/internal/config"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	batch := flag.Int("batch", ingest.DefaultReanalyzeBatch, "articles to list per batch")
+	rate := flag.Float64("rate", ingest.DefaultReanalyzeRate, "articles to re-analyze per second at most; 0 for no limit")
+	check := flag.Bool("check", false, "only report how many articles each analysis version produced")
+	flag.Parse()
+	if *batch < 1 {
+		log.Fatalf("-batch must be at least 1")
+	}
+	if *rate < 0 {
+		log.Fatalf("-rate must not be negative")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	offline, closer, err := cli.OpenOffline(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open the article store: %v", err)
+	}
+	defer closer.Close()
+
+	reanalyzer := &ingest.Reanalyzer{Service: offline.Ingester, Batch: *batch, Rate: *rate}
+	pending, counts, err := reanalyzer.Pending(ctx)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	for _, c := range counts {
+		name := c.Version
+		if name == "" {
+			name = "(not recorded)"
+		}
+		log.Printf("%-16s %d articles", name, c.Articles)
+	}
+	log.Printf("%d articles to re-analyze with %s", pending, llm.SemanticsVersion)
+	if *check || pending == 0 {
+		return
+	}
+
+	progress, err := reanalyzer.Run(ctx, func(p ingest.ReanalyzeProgress) {
+		if p.Running && p.Total > 0 {
+			log.Printf("Re-analyzed %d/%d articles (%d%%), %d failed", p.Done, p.Total, p.Done*100/p.Total, p.Failed)
+		}
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Fatalf("Interrupted after %d of %d articles; run again to continue", progress.Done, progress.Total)
+		}
+		log.Fatalf("%v", err)
+	}
+	if progress.Failed > 0 {
+		log.Fatalf("%d articles failed; run again to retry them", progress.Failed)
+	}
+	log.Printf("All articles are analyzed with %s", llm.SemanticsVersion)
+}
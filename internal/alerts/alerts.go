@@ -0,0 +1,152 @@
+// Package alerts evaluates saved searches against newly ingested articles and records the
+// matches as alerts.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/repository"
+)
+
+// DefaultInterval is how often saved searches are evaluated
+const DefaultInterval = 5 * time.Minute
+
+// Evaluator checks every saved search against the articles ingested since it was last checked
+type Evaluator struct {
+	Repo repository.ArticleStore
+
+	// OnMatch, if set, runs for every new alert, e.g. to push it to webhooks
+	OnMatch func(ctx context.Context, search domain.SavedSearch, alert domain.Alert)
+}
+
+// NewEvaluator creates an evaluator over repo
+func NewEvaluator(repo repository.ArticleStore) *Evaluator {
+	return &Evaluator{Repo: repo}
+}
+
+// Matches reports whether article mentions the search topic and its sentiment score is within
+// the search's bounds. The topic is matched case-insensitively against the title, summary and
+// extracted topics, keywords and entities.
+func Matches(search domain.SavedSearch, article domain.Article) bool {
+	if search.MinSentiment != nil && article.SentimentScore < *search.MinSentiment {
+		return false
+	}
+	if search.MaxSentiment != nil && article.SentimentScore > *search.MaxSentiment {
+		return false
+	}
+
+	topic := strings.ToLower(strings.TrimSpace(search.Topic))
+	if topic == "" {
+		return false
+	}
+	fields := []string{article.Title, article.Summary}
+	for _, t := range article.Topics {
+		fields = append(fields, t.Name)
+	}
+	for _, k := range article.Keywords {
+		fields = append(fields, k.Term)
+	}
+	for _, e := range article.Entities {
+		fields = append(fields, e.Name)
+	}
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunOnce evaluates every saved search and returns the number of new alerts
+func (e *Evaluator) RunOnce(ctx context.Context) (int, error) {
+	searches, err := e.Repo.ListSavedSearches(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load saved searches: %w", err)
+	}
+
+	logger := logging.FromContext(ctx)
+	matched := 0
+	for _, search := range searches {
+		if ctx.Err() != nil {
+			break
+		}
+		n, err := e.evaluate(ctx, search)
+		if err != nil {
+			logger.Warn("failed to evaluate saved search", "search_id", search.ID, "error", err)
+		}
+		matched += n
+	}
+	return matched, nil
+}
+
+// evaluate records alerts for the articles ingested after search.CheckedAt and advances it to
+// the newest of them
+func (e *Evaluator) evaluate(ctx context.Context, search domain.SavedSearch) (int, error) {
+	urls, err := e.Repo.GetArticleURLs(ctx, nil, domain.ArticleFilter{IngestedAfter: search.CheckedAt})
+	if err != nil || len(urls) == 0 {
+		return 0, err
+	}
+	articles, err := e.Repo.GetArticlesByURLs(ctx, urls)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	checked := search.CheckedAt
+	for _, a := range articles {
+		if a.CreatedAt.After(checked) {
+			checked = a.CreatedAt
+		}
+		if !Matches(search, a) {
+			continue
+		}
+		alert := domain.Alert{
+			SearchID:       search.ID,
+			ArticleID:      a.ID,
+			URL:            a.URL,
+			Title:          a.Title,
+			Sentiment:      a.Sentiment,
+			SentimentScore: a.SentimentScore,
+		}
+		added, err := e.Repo.AddAlert(ctx, &alert)
+		if err != nil {
+			return matched, fmt.Errorf("failed to record alert: %w", err)
+		}
+		if !added {
+			continue
+		}
+		matched++
+		if e.OnMatch != nil {
+			e.OnMatch(ctx, search, alert)
+		}
+	}
+	return matched, e.Repo.MarkSavedSearchChecked(ctx, search.ID, checked)
+}
+
+// Run calls RunOnce every interval until ctx is cancelled
+func (e *Evaluator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("🔔 Started saved search evaluation every %v", interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Saved search evaluation stopped")
+			return
+		case <-ticker.C:
+			matched, err := e.RunOnce(ctx)
+			if err != nil {
+				log.Printf("❌ Saved search evaluation failed: %v", err)
+			} else if matched > 0 {
+				log.Printf("🔔 Recorded %d new alerts", matched)
+			}
+		}
+	}
+}
@@ -164,7 +164,7 @@ Respond in JSON format:
 		Matches []TopicMatch `json:"matches"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(response)), &result); err != nil {
 		return nil, fmt.Errorf("failed to parse topic matches: %w", err)
 	}
 
@@ -0,0 +1,413 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llmaudit"
+	"article-assistant/internal/processing"
+)
+
+// Error is a failed API call; the embedded APIError is the decoded error envelope
+type Error struct {
+	StatusCode int
+	domain.APIError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Client calls the Article Assistant HTTP API
+type Client struct {
+	BaseURL string // e.g. http://localhost:8080
+	APIKey  string // Sent as a bearer token when set; required by servers with API_KEYS
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for the server at baseURL. Calls are bounded by their context and
+// the server's per-route timeouts; exports may stream for several minutes.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTP: &http.Client{}}
+}
+
+// Chat answers a natural-language query
+func (c *Client) Chat(ctx context.Context, req domain.ChatRequest) (*domain.ChatResponse, error) {
+	return call[domain.ChatResponse](ctx, c, "POST", "/chat", nil, req)
+}
+
+// Ingest fetches and stores articleURL. It returns nil once the article is stored, or the status
+// location when ingestion is still running; poll IngestStatus with its ID.
+func (c *Client) Ingest(ctx context.Context, articleURL string) (*IngestAccepted, error) {
+	var out IngestAccepted
+	status, err := c.do(ctx, "POST", "/ingest", nil, IngestRequest{URL: articleURL}, &out)
+	if err != nil || status != http.StatusAccepted {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IngestBatch queues articleURLs for ingestion; poll IngestStatus with each accepted item's ID
+func (c *Client) IngestBatch(ctx context.Context, articleURLs []string) (*IngestBatchResult, error) {
+	return call[IngestBatchResult](ctx, c, "POST", "/ingest/batch", nil, IngestBatchRequest{URLs: articleURLs})
+}
+
+// IngestStatus returns the state of an ingest request that was accepted
+func (c *Client) IngestStatus(ctx context.Context, id string) (*processing.Status, error) {
+	return call[processing.Status](ctx, c, "GET", "/ingest/status", url.Values{"id": {id}}, nil)
+}
+
+// ListArticles returns one page of stored articles; sort is created_at or sentiment_score
+func (c *Client) ListArticles(ctx context.Context, limit, offset int, sort string, ascending bool) (*ArticlePage, error) {
+	q := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+	if sort != "" {
+		q.Set("sort", sort)
+	}
+	if ascending {
+		q.Set("order", "asc")
+	}
+	return call[ArticlePage](ctx, c, "GET", "/articles", q, nil)
+}
+
+// DeleteArticle deletes the article stored at articleURL
+func (c *Client) DeleteArticle(ctx context.Context, articleURL string) error {
+	_, err := c.do(ctx, "DELETE", "/articles", url.Values{"url": {articleURL}}, nil, nil)
+	return err
+}
+
+// ArticleSummary returns an article's summary, rewritten for level unless it is empty
+func (c *Client) ArticleSummary(ctx context.Context, id, level string) (*ArticleSummary, error) {
+	var q url.Values
+	if level != "" {
+		q = url.Values{"level": {level}}
+	}
+	return call[ArticleSummary](ctx, c, "GET", "/articles/"+url.PathEscape(id)+"/summary", q, nil)
+}
+
+// Reingest fetches articleURL again, replacing its stored analysis
+func (c *Client) Reingest(ctx context.Context, articleURL string) error {
+	_, err := c.do(ctx, "POST", "/articles/reingest", nil, IngestRequest{URL: articleURL}, nil)
+	return err
+}
+
+// Entity returns an entity's aggregates, bucketed by interval (day or week), and one page of
+// the articles mentioning it
+func (c *Client) Entity(ctx context.Context, name, interval string, limit, offset int) (*EntityPage, error) {
+	q := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+	if interval != "" {
+		q.Set("interval", interval)
+	}
+	return call[EntityPage](ctx, c, "GET", "/entities/"+url.PathEscape(name), q, nil)
+}
+
+// Import stores already-extracted article content
+func (c *Client) Import(ctx context.Context, article ingest.ImportedArticle) (*ImportResult, error) {
+	return call[ImportResult](ctx, c, "POST", "/import", nil, article)
+}
+
+// Collections lists the collections with their article counts, by name
+func (c *Client) Collections(ctx context.Context) ([]domain.Collection, error) {
+	var out CollectionList
+	_, err := c.do(ctx, "GET", "/collections", nil, nil, &out)
+	return out.Collections, err
+}
+
+// CreateCollection creates a collection and returns it with its ID
+func (c *Client) CreateCollection(ctx context.Context, name, description string) (*domain.Collection, error) {
+	return call[domain.Collection](ctx, c, "POST", "/collections", nil, domain.Collection{Name: name, Description: description})
+}
+
+// AddToCollection adds stored articles to a collection by URL and returns the collection
+func (c *Client) AddToCollection(ctx context.Context, collectionID string, urls ...string) (*domain.Collection, error) {
+	return call[domain.Collection](ctx, c, "POST", "/collections/"+url.PathEscape(collectionID)+"/articles", nil, CollectionArticles{URLs: urls})
+}
+
+// Tags lists the user-defined tags in use, most used first
+func (c *Client) Tags(ctx context.Context) ([]domain.TagCount, error) {
+	var out TagList
+	_, err := c.do(ctx, "GET", "/tags", nil, nil, &out)
+	return out.Tags, err
+}
+
+// TagArticle adds tags to an article and returns all of its tags
+func (c *Client) TagArticle(ctx context.Context, articleID string, tags ...string) ([]string, error) {
+	var out ArticleTags
+	_, err := c.do(ctx, "POST", "/articles/"+url.PathEscape(articleID)+"/tags", nil, ArticleTags{Tags: tags}, &out)
+	return out.Tags, err
+}
+
+// UntagArticle removes a tag from an article and returns its remaining tags
+func (c *Client) UntagArticle(ctx context.Context, articleID, tag string) ([]string, error) {
+	var out ArticleTags
+	_, err := c.do(ctx, "DELETE", "/articles/"+url.PathEscape(articleID)+"/tags", url.Values{"tag": {tag}}, nil, &out)
+	return out.Tags, err
+}
+
+// ArticleNotes lists an article's notes, oldest first
+func (c *Client) ArticleNotes(ctx context.Context, articleID string) ([]domain.ArticleNote, error) {
+	var out NoteList
+	_, err := c.do(ctx, "GET", "/articles/"+url.PathEscape(articleID)+"/notes", nil, nil, &out)
+	return out.Notes, err
+}
+
+// AddArticleNote adds a note to an article and returns it with its ID
+func (c *Client) AddArticleNote(ctx context.Context, articleID, text string) (*domain.ArticleNote, error) {
+	return call[domain.ArticleNote](ctx, c, "POST", "/articles/"+url.PathEscape(articleID)+"/notes", nil, domain.ArticleNote{Text: text})
+}
+
+// SavedSearches lists the saved searches
+func (c *Client) SavedSearches(ctx context.Context) ([]domain.SavedSearch, error) {
+	var out SearchList
+	_, err := c.do(ctx, "GET", "/searches", nil, nil, &out)
+	return out.Searches, err
+}
+
+// CreateSavedSearch saves search and returns it with its ID
+func (c *Client) CreateSavedSearch(ctx context.Context, search domain.SavedSearch) (*domain.SavedSearch, error) {
+	return call[domain.SavedSearch](ctx, c, "POST", "/searches", nil, search)
+}
+
+// DeleteSavedSearch deletes a saved search
+func (c *Client) DeleteSavedSearch(ctx context.Context, id string) error {
+	_, err := c.do(ctx, "DELETE", "/searches", url.Values{"id": {id}}, nil, nil)
+	return err
+}
+
+// Alerts lists articles that matched saved searches, all of them when searchID is empty
+func (c *Client) Alerts(ctx context.Context, searchID string, limit int) ([]domain.Alert, error) {
+	q := url.Values{"limit": {strconv.Itoa(limit)}}
+	if searchID != "" {
+		q.Set("search_id", searchID)
+	}
+	var out AlertList
+	_, err := c.do(ctx, "GET", "/alerts", q, nil, &out)
+	return out.Alerts, err
+}
+
+// Topics lists the topic taxonomy
+func (c *Client) Topics(ctx context.Context) ([]domain.TaxonomyTopic, error) {
+	var out TopicList
+	_, err := c.do(ctx, "GET", "/topics", nil, nil, &out)
+	return out.Topics, err
+}
+
+// CreateTopic adds a taxonomy topic; the server assigns an ID when topic.ID is empty
+func (c *Client) CreateTopic(ctx context.Context, topic domain.TaxonomyTopic) (*domain.TaxonomyTopic, error) {
+	return call[domain.TaxonomyTopic](ctx, c, "POST", "/topics", nil, topic)
+}
+
+// UpdateTopic replaces the name, description and parent of the topic with topic.ID
+func (c *Client) UpdateTopic(ctx context.Context, topic domain.TaxonomyTopic) (*domain.TaxonomyTopic, error) {
+	return call[domain.TaxonomyTopic](ctx, c, "PUT", "/topics", url.Values{"id": {topic.ID}}, topic)
+}
+
+// DeleteTopic removes a taxonomy topic
+func (c *Client) DeleteTopic(ctx context.Context, id string) error {
+	_, err := c.do(ctx, "DELETE", "/topics", url.Values{"id": {id}}, nil, nil)
+	return err
+}
+
+// Export streams stored articles, oldest first, to w in format (jsonl or csv). Only
+// filter's Topic, TopicID, Tag, Collection, Status, AnalysisVersion, SummaryVersion,
+// SourceDomain and published bounds apply.
+func (c *Client) Export(ctx context.Context, w io.Writer, format string, embeddings bool, filter domain.ArticleFilter) error {
+	q := url.Values{"format": {format}}
+	if embeddings {
+		q.Set("embeddings", "true")
+	}
+	for name, value := range map[string]string{"topic": filter.Topic, "topic_id": filter.TopicID, "tag": filter.Tag, "collection": filter.Collection, "status": filter.Status,
+		"analysis_version": filter.AnalysisVersion, "summary_version": filter.SummaryVersion, "source": filter.SourceDomain} {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	if !filter.PublishedAfter.IsZero() {
+		q.Set("published_after", filter.PublishedAfter.Format(time.RFC3339))
+	}
+	if !filter.PublishedBefore.IsZero() {
+		q.Set("published_before", filter.PublishedBefore.Format(time.RFC3339))
+	}
+
+	resp, err := c.send(ctx, "GET", "/export", q, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		// The server drops the connection when an export fails part-way
+		return fmt.Errorf("export interrupted: %w", err)
+	}
+	return nil
+}
+
+// IngestFailures lists failed ingests, most recently failed first, with the given error
+// category or all when category is empty
+func (c *Client) IngestFailures(ctx context.Context, category string, limit int) ([]domain.IngestFailure, error) {
+	q := url.Values{"limit": {strconv.Itoa(limit)}}
+	if category != "" {
+		q.Set("category", category)
+	}
+	var out FailureList
+	_, err := c.do(ctx, "GET", "/admin/failures", q, nil, &out)
+	return out.Failures, err
+}
+
+// RetryIngestFailure ingests a failed URL again; like Ingest, it returns the accepted job when
+// the ingest is still running and nil when it finished
+func (c *Client) RetryIngestFailure(ctx context.Context, id string) (*IngestAccepted, error) {
+	var out IngestAccepted
+	status, err := c.do(ctx, "POST", "/admin/failures/"+url.PathEscape(id)+"/retry", nil, nil, &out)
+	if err != nil || status != http.StatusAccepted {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LLMAudit returns audited LLM calls matching filter, newest first
+func (c *Client) LLMAudit(ctx context.Context, filter llmaudit.Filter) ([]llmaudit.Entry, error) {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"method": filter.Method, "model": filter.Model, "prompt_hash": filter.PromptHash,
+		"prompt_version": filter.PromptVersion, "request_id": filter.RequestID,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var out AuditLog
+	_, err := c.do(ctx, "GET", "/admin/llm-audit", q, nil, &out)
+	return out.Entries, err
+}
+
+// VectorIndexes describes the server's embedding indexes
+func (c *Client) VectorIndexes(ctx context.Context) ([]domain.VectorIndex, error) {
+	var out VectorIndexList
+	_, err := c.do(ctx, "GET", "/admin/vector-index", nil, nil, &out)
+	return out.Indexes, err
+}
+
+// Reindex rebuilds the server's embedding indexes and describes the new ones
+func (c *Client) Reindex(ctx context.Context) ([]domain.VectorIndex, error) {
+	var out VectorIndexList
+	_, err := c.do(ctx, "POST", "/admin/vector-index", nil, nil, &out)
+	return out.Indexes, err
+}
+
+// ReanalyzeStatus counts articles per semantic extraction version and reports the running or
+// last re-analysis
+func (c *Client) ReanalyzeStatus(ctx context.Context) (*ReanalyzeStatus, error) {
+	return call[ReanalyzeStatus](ctx, c, "GET", "/admin/reanalyze", nil, nil)
+}
+
+// Reanalyze starts re-analyzing outdated articles on the server; batch and rate 0 use its defaults
+func (c *Client) Reanalyze(ctx context.Context, batch int, rate float64) (*ReanalyzeStatus, error) {
+	q := url.Values{}
+	if batch > 0 {
+		q.Set("batch", strconv.Itoa(batch))
+	}
+	if rate > 0 {
+		q.Set("rate", strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+	return call[ReanalyzeStatus](ctx, c, "POST", "/admin/reanalyze", q, nil)
+}
+
+// Commands lists the chat commands the planner can choose
+func (c *Client) Commands(ctx context.Context) (*CommandList, error) {
+	return call[CommandList](ctx, c, "GET", "/commands", nil, nil)
+}
+
+// Usage returns daily LLM usage, newest day first
+func (c *Client) Usage(ctx context.Context) (*UsageReport, error) {
+	return call[UsageReport](ctx, c, "GET", "/usage", nil, nil)
+}
+
+// Health checks that the server is up
+func (c *Client) Health(ctx context.Context) error {
+	_, err := c.do(ctx, "GET", "/health", nil, nil, nil)
+	return err
+}
+
+// call is do for a JSON response body, returning nil on failure
+func call[T any](ctx context.Context, c *Client, method, path string, query url.Values, body interface{}) (*T, error) {
+	var out T
+	if _, err := c.do(ctx, method, path, query, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// do sends a JSON request and decodes a 2xx body into out; other statuses become an *Error
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (int, error) {
+	resp, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// send issues a request and returns a 2xx response for the caller to close; other statuses
+// become an *Error
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(data, &apiErr.APIError) != nil || apiErr.Code == "" {
+			// Not an error envelope, e.g. from a proxy in front of the server
+			apiErr.Code = domain.ErrCodeInternal
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
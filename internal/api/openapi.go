@@ -0,0 +1,538 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"article-assistant/internal/chaos"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/middleware"
+	"article-assistant/internal/processing"
+)
+
+// Version is the API version reported in the OpenAPI document
+const Version = "1.0.0"
+
+// param is an operation parameter
+type param struct {
+	name        string
+	in          string // query or path
+	kind        string // string, integer, number or boolean
+	description string
+	required    bool
+}
+
+func query(name, kind, description string) param {
+	return param{name: name, in: "query", kind: kind, description: description}
+}
+
+func requiredQuery(name, kind, description string) param {
+	return param{name: name, in: "query", kind: kind, description: description, required: true}
+}
+
+func pathParam(name, description string) param {
+	return param{name: name, in: "path", kind: "string", description: description, required: true}
+}
+
+// rawBody is a non-JSON response body in one of the media types
+type rawBody struct {
+	description string
+	mediaTypes  []string
+}
+
+// operation describes one method of a path
+type operation struct {
+	summary   string
+	params    []param
+	body      interface{}         // Request body type; nil for none
+	responses map[int]interface{} // Success bodies by status; nil for an empty body
+	errors    []int               // Statuses answered with an APIError
+	public    bool                // Served without an API key
+}
+
+// endpoints lists every route with its operations by method
+func endpoints() map[string]map[string]operation {
+	paging := []param{
+		query("limit", "integer", "Page size, 1-100 (default 20)"),
+		query("offset", "integer", "Articles to skip (default 0)"),
+	}
+	articleFilters := []param{
+		query("author", "string", "Substring of the author"),
+		query("section", "string", "Substring of the section"),
+		query("topic", "string", "Substring of an extracted topic"),
+		query("topic_id", "string", "Taxonomy topic, including subtopics"),
+		query("tag", "string", "User-defined tag"),
+		query("collection", "string", "Collection ID or name"),
+		query("status", "string", "Ingest status: pending, enriched or failed"),
+		query("analysis_version", "string", "Semantic extraction version, e.g. v1, or supplied for imported analyses"),
+		query("summary_version", "string", "Summarization prompt version, e.g. v1, or supplied for imported summaries"),
+		query("source", "string", "Source domain"),
+		query("published_after", "string", "Date, RFC 3339 time or relative date such as last_week or 3d"),
+		query("published_before", "string", "Date or relative date"),
+	}
+	ok := func(body interface{}) map[int]interface{} { return map[int]interface{}{200: body} }
+	done := ok(StatusMessage{})
+
+	return map[string]map[string]operation{
+		"/chat": {"post": {
+			summary:   "Answer a natural-language query about the stored articles",
+			body:      domain.ChatRequest{},
+			responses: ok(domain.ChatResponse{}),
+			errors:    []int{400, 500, 503, 504},
+		}},
+		"/ingest": {"post": {
+			summary: "Fetch, analyze and store an article",
+			body:    IngestRequest{},
+			responses: map[int]interface{}{
+				200: StatusMessage{},
+				202: IngestAccepted{},
+			},
+			errors: []int{400, 500, 503, 504},
+		}},
+		"/ingest/batch": {"post": {
+			summary:   "Queue up to 100 articles for ingestion without waiting",
+			body:      IngestBatchRequest{},
+			responses: map[int]interface{}{202: IngestBatchResult{}},
+			errors:    []int{400, 504},
+		}},
+		"/ingest/status": {"get": {
+			summary:   "Processing state of an ingest request that returned 202",
+			params:    []param{requiredQuery("id", "string", "ID from the 202 response")},
+			responses: ok(processing.Status{}),
+			errors:    []int{404},
+		}},
+		"/articles": {
+			"get": {
+				summary: "List stored articles",
+				params: append(append(paging,
+					query("sort", "string", "created_at (default) or sentiment_score"),
+					query("order", "string", "desc (default) or asc")), articleFilters...),
+				responses: ok(ArticlePage{}),
+				errors:    []int{400, 500},
+			},
+			"delete": {
+				summary:   "Delete an article by URL",
+				params:    []param{requiredQuery("url", "string", "Article URL")},
+				responses: done,
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/articles/{id}/summary": {"get": {
+			summary: "An article's summary, optionally rewritten for a reading level",
+			params: []param{
+				pathParam("id", "Article ID"),
+				query("level", "string", "eli5, high_school or expert; omit for the stored summary"),
+			},
+			responses: ok(ArticleSummary{}),
+			errors:    []int{400, 404, 500, 503, 504},
+		}},
+		"/articles/{id}/tags": {
+			"get": {
+				summary:   "An article's user-defined tags",
+				params:    []param{pathParam("id", "Article ID")},
+				responses: ok(ArticleTags{}),
+				errors:    []int{404, 500},
+			},
+			"post": {
+				summary:   "Add tags to an article; tags are stored lowercase",
+				params:    []param{pathParam("id", "Article ID")},
+				body:      ArticleTags{},
+				responses: ok(ArticleTags{}),
+				errors:    []int{400, 404, 500},
+			},
+			"delete": {
+				summary:   "Remove a tag from an article",
+				params:    []param{pathParam("id", "Article ID"), requiredQuery("tag", "string", "Tag to remove")},
+				responses: ok(ArticleTags{}),
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/articles/{id}/notes": {
+			"get": {
+				summary:   "An article's notes, oldest first",
+				params:    []param{pathParam("id", "Article ID")},
+				responses: ok(NoteList{}),
+				errors:    []int{404, 500},
+			},
+			"post": {
+				summary:   "Add a note to an article",
+				params:    []param{pathParam("id", "Article ID")},
+				body:      domain.ArticleNote{},
+				responses: map[int]interface{}{201: domain.ArticleNote{}},
+				errors:    []int{400, 404, 500},
+			},
+			"delete": {
+				summary:   "Delete a note from an article",
+				params:    []param{pathParam("id", "Article ID"), requiredQuery("note_id", "string", "Note ID")},
+				responses: done,
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/tags": {"get": {
+			summary:   "User-defined tags in use, with their article counts",
+			responses: ok(TagList{}),
+			errors:    []int{500},
+		}},
+		"/articles/reingest": {"post": {
+			summary:   "Fetch an article again, replacing its stored analysis",
+			body:      IngestRequest{},
+			responses: map[int]interface{}{200: StatusMessage{}},
+			errors:    []int{400, 500},
+		}},
+		"/entities/{name}": {"get": {
+			summary: "Aggregates for an entity and the articles mentioning it",
+			params: append([]param{
+				pathParam("name", "Entity name or alias"),
+				query("interval", "string", "Timeline bucket: week (default) or day"),
+			}, paging...),
+			responses: ok(EntityPage{}),
+			errors:    []int{400, 404, 500},
+		}},
+		"/import": {"post": {
+			summary: "Store already-extracted article content without fetching the URL",
+			body:    ingest.ImportedArticle{},
+			responses: map[int]interface{}{
+				200: ImportResult{},
+				201: ImportResult{},
+			},
+			errors: []int{400, 422, 500},
+		}},
+		"/export": {"get": {
+			summary: "Stream stored articles, oldest first; gzip-compressed when accepted",
+			params: append([]param{
+				query("format", "string", "jsonl (default) or csv"),
+				query("embeddings", "boolean", "Include embeddings"),
+			}, articleFilters...),
+			responses: ok(rawBody{"One Article per line (jsonl) or row (csv)", []string{"application/x-ndjson", "text/csv"}}),
+			errors:    []int{400, 500},
+		}},
+		"/searches": {
+			"get": {
+				summary:   "List saved searches",
+				responses: ok(SearchList{}),
+				errors:    []int{500},
+			},
+			"post": {
+				summary:   "Save a search; newly ingested matching articles raise alerts",
+				body:      domain.SavedSearch{},
+				responses: map[int]interface{}{201: domain.SavedSearch{}},
+				errors:    []int{400, 500},
+			},
+			"delete": {
+				summary:   "Delete a saved search",
+				params:    []param{requiredQuery("id", "string", "Saved search ID")},
+				responses: done,
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/topics": {
+			"get": {
+				summary:   "List the topic taxonomy",
+				responses: ok(TopicList{}),
+				errors:    []int{500},
+			},
+			"post": {
+				summary:   "Create a taxonomy topic",
+				body:      domain.TaxonomyTopic{},
+				responses: map[int]interface{}{201: domain.TaxonomyTopic{}},
+				errors:    []int{400, 409, 500},
+			},
+			"put": {
+				summary:   "Update a taxonomy topic",
+				params:    []param{requiredQuery("id", "string", "Topic ID")},
+				body:      domain.TaxonomyTopic{},
+				responses: ok(domain.TaxonomyTopic{}),
+				errors:    []int{400, 404, 500},
+			},
+			"delete": {
+				summary:   "Delete a taxonomy topic; its subtopics become top-level",
+				params:    []param{requiredQuery("id", "string", "Topic ID")},
+				responses: done,
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/collections": {
+			"get": {
+				summary:   "List collections with their article counts",
+				responses: ok(CollectionList{}),
+				errors:    []int{500},
+			},
+			"post": {
+				summary:   "Create a collection",
+				body:      domain.Collection{},
+				responses: map[int]interface{}{201: domain.Collection{}},
+				errors:    []int{400, 409, 500},
+			},
+			"put": {
+				summary:   "Rename a collection or change its description",
+				params:    []param{requiredQuery("id", "string", "Collection ID")},
+				body:      domain.Collection{},
+				responses: ok(domain.Collection{}),
+				errors:    []int{400, 404, 409, 500},
+			},
+			"delete": {
+				summary:   "Delete a collection; its articles are kept",
+				params:    []param{requiredQuery("id", "string", "Collection ID")},
+				responses: done,
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/collections/{id}/articles": {
+			"post": {
+				summary:   "Add stored articles to a collection by ID or URL",
+				params:    []param{pathParam("id", "Collection ID")},
+				body:      CollectionArticles{},
+				responses: ok(domain.Collection{}),
+				errors:    []int{400, 404, 500},
+			},
+			"delete": {
+				summary:   "Remove an article from a collection",
+				params:    []param{pathParam("id", "Collection ID"), requiredQuery("article_id", "string", "Article ID")},
+				responses: ok(domain.Collection{}),
+				errors:    []int{400, 404, 500},
+			},
+		},
+		"/alerts": {"get": {
+			summary: "Articles that matched saved searches, newest first",
+			params: []param{
+				query("search_id", "string", "Only alerts of this saved search"),
+				query("limit", "integer", "1-500 (default 50)"),
+			},
+			responses: ok(AlertList{}),
+			errors:    []int{400, 500},
+		}},
+		"/commands": {"get": {
+			summary:   "Chat commands the planner can choose, with the args each reads",
+			responses: ok(CommandList{}),
+		}},
+		"/usage": {"get": {
+			summary:   "Daily LLM token usage and estimated cost of the caller's tenant",
+			responses: ok(UsageReport{}),
+		}},
+		"/metrics": {"get": {
+			summary:   "Prometheus metrics; the per-tenant series need the operator key",
+			responses: ok(rawBody{"Prometheus text exposition format", []string{"text/plain"}}),
+			public:    true,
+		}},
+		"/admin/failures": {"get": {
+			summary: "Ingests that failed after all retries, most recently failed first",
+			params: []param{
+				query("category", "string", "Only failures with this error category"),
+				query("limit", "integer", "1-500 (default 50)"),
+			},
+			responses: ok(FailureList{}),
+			errors:    []int{400, 500},
+		}},
+		"/admin/failures/{id}/retry": {"post": {
+			summary: "Ingest a failed URL again; responds like POST /ingest",
+			params:  []param{pathParam("id", "Ingest failure ID")},
+			responses: map[int]interface{}{
+				200: StatusMessage{},
+				202: IngestAccepted{},
+			},
+			errors: []int{404, 500, 503, 504},
+		}},
+		"/admin/llm-audit": {"get": {
+			summary: "The tenant's audited LLM calls, newest first (only when LLM_AUDIT is set)",
+			params: []param{
+				query("method", "string", "Client method, e.g. PlanQuery"),
+				query("model", "string", "Model name"),
+				query("prompt_hash", "string", "SHA-256 of the method and its inputs"),
+				query("prompt_version", "string", "Planner prompt version"),
+				query("request_id", "string", "Only calls made for this request"),
+				query("since", "string", "RFC 3339 time or a duration back from now, e.g. 1h"),
+				query("limit", "integer", "1-500 (default 50)"),
+			},
+			responses: ok(AuditLog{}),
+			errors:    []int{400, 404, 500},
+		}},
+		"/admin/vector-index": {
+			"get": {
+				summary:   "Article and passage embedding indexes (only with the postgres driver)",
+				responses: ok(VectorIndexList{}),
+				errors:    []int{404, 500},
+			},
+			"post": {
+				summary:   "Rebuild the embedding indexes with the VECTOR_INDEX settings",
+				responses: ok(VectorIndexList{}),
+				errors:    []int{404, 500, 504},
+			},
+		},
+		"/admin/reanalyze": {
+			"get": {
+				summary:   "Articles per semantic extraction version, and the running or last re-analysis",
+				responses: ok(ReanalyzeStatus{}),
+				errors:    []int{500},
+			},
+			"post": {
+				summary: "Re-run the semantic extraction in the background over articles analyzed with an older version",
+				params: []param{
+					query("batch", "integer", "Articles listed per batch (default 50)"),
+					query("rate", "number", "Articles per second at most (default 1); 0 for no limit"),
+				},
+				responses: map[int]interface{}{202: ReanalyzeStatus{}},
+				errors:    []int{400, 409, 500, 503},
+			},
+		},
+		"/admin/llm-health": {"get": {
+			summary:   "LLM error breakdown per provider and model (needs OPERATOR_API_KEY when API keys are set)",
+			responses: ok(LLMHealth{}),
+		}},
+		"/admin/chaos": {
+			"get": {
+				summary:   "Fault injection settings and counters (only when CHAOS_ENABLED=true; needs OPERATOR_API_KEY when API keys are set)",
+				responses: ok(ChaosState{}),
+			},
+			"post": {
+				summary:   "Set fault injection",
+				body:      chaos.Config{},
+				responses: ok(ChaosState{}),
+			},
+			"delete": {
+				summary:   "Turn fault injection off and reset the counters",
+				responses: ok(ChaosState{}),
+			},
+		},
+		"/health": {"get": {
+			summary:   "Liveness check",
+			responses: ok(Health{}),
+			public:    true,
+		}},
+		"/": {"get": {
+			summary:   "Web dashboard: chat, ingest and the article list",
+			responses: ok(rawBody{"HTML page; its assets are served under /ui/", []string{"text/html"}}),
+			public:    true,
+		}},
+		"/openapi.json": {"get": {
+			summary:   "This document",
+			responses: ok(rawBody{"OpenAPI 3.0 document", []string{"application/json"}}),
+			public:    true,
+		}},
+	}
+}
+
+// Spec builds the OpenAPI 3.0 document describing every endpoint
+func Spec() map[string]interface{} {
+	schemas := newSchemaSet()
+	apiError := schemas.of(domain.APIError{})
+
+	paths := map[string]interface{}{}
+	for path, methods := range endpoints() {
+		item := map[string]interface{}{}
+		for method, op := range methods {
+			item[method] = op.build(schemas, apiError)
+		}
+		paths[path] = item
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Article Assistant API",
+			"version": Version,
+			"description": "Ingest news articles and ask questions about them. Failed requests return an APIError with a stable code. " +
+				"When the server has API_KEYS, requests carry a key as a bearer token or in X-API-Key and see only their tenant's data. " +
+				"Reader keys may chat and read, editor keys may also change articles, topics, collections and searches, and admin keys may also use /admin.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerKey": map[string]interface{}{"type": "http", "scheme": "bearer"},
+				"headerKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": middleware.APIKeyHeader},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearerKey": []string{}},
+			map[string]interface{}{"headerKey": []string{}},
+		},
+	}
+}
+
+func (op operation) build(schemas *schemaSet, apiError map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{"summary": op.summary}
+	if len(op.params) > 0 {
+		params := make([]interface{}, 0, len(op.params))
+		for _, p := range op.params {
+			params = append(params, map[string]interface{}{
+				"name":        p.name,
+				"in":          p.in,
+				"required":    p.required,
+				"description": p.description,
+				"schema":      map[string]interface{}{"type": p.kind},
+			})
+		}
+		out["parameters"] = params
+	}
+	if op.body != nil {
+		out["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": schemas.of(op.body)}},
+		}
+	}
+
+	responses := map[string]interface{}{}
+	for status, body := range op.responses {
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		switch body := body.(type) {
+		case nil:
+		case rawBody:
+			content := map[string]interface{}{}
+			for _, mediaType := range body.mediaTypes {
+				content[mediaType] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+			}
+			response["description"] = body.description
+			response["content"] = content
+		default:
+			response["content"] = map[string]interface{}{"application/json": map[string]interface{}{"schema": schemas.of(body)}}
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+	errors := op.errors
+	if op.public {
+		out["security"] = []interface{}{}
+	} else {
+		errors = append(errors[:len(errors):len(errors)], http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests)
+	}
+	for _, status := range errors {
+		responses[strconv.Itoa(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": apiError}},
+		}
+	}
+	out["responses"] = responses
+	return out
+}
+
+// SpecJSON is Spec encoded with stable key order, as served and written to api/openapi.json
+func SpecJSON() []byte {
+	specOnce.Do(func() {
+		data, err := json.MarshalIndent(Spec(), "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		specJSON = append(data, '\n')
+	})
+	return specJSON
+}
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+)
+
+// Handler serves the OpenAPI document
+func Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method != http.MethodGet {
+			middleware.WriteError(w, r, 405, domain.ErrCodeMethodNotAllowed, "Method not allowed")
+			return
+		}
+		w.Write(SpecJSON())
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"article-assistant/internal/chaos"
+	"article-assistant/internal/commands"
+	"article-assistant/internal/llmaudit"
+	"article-assistant/internal/processing"
+)
+
+// schemaNames renames component schemas whose Go name is ambiguous outside its package
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeOf(processing.Status{}): "IngestStatus",
+	reflect.TypeOf(chaos.Config{}):      "ChaosConfig",
+	reflect.TypeOf(chaos.Stats{}):       "ChaosStats",
+	reflect.TypeOf(llmaudit.Entry{}):    "LLMAuditEntry",
+	reflect.TypeOf(commands.Command{}):  "ChatCommand",
+	reflect.TypeOf(commands.Arg{}):      "ChatCommandArg",
+}
+
+// schemaSet derives OpenAPI schemas from Go types, following encoding/json rules. Named
+// structs become components referenced by $ref.
+type schemaSet struct {
+	components map[string]interface{}
+	names      map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{components: map[string]interface{}{}, names: map[reflect.Type]string{}}
+}
+
+// of returns the schema of v's type
+func (s *schemaSet) of(v interface{}) map[string]interface{} {
+	return s.schema(reflect.TypeOf(v))
+}
+
+func (s *schemaSet) schema(t reflect.Type) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.schema(t.Elem())
+	case reflect.Interface:
+		return map[string]interface{}{}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + s.component(t)}
+	}
+	panic(fmt.Sprintf("api: no schema for %v", t))
+}
+
+// component registers a named struct once and returns its component name
+func (s *schemaSet) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := schemaNames[t]
+	if name == "" {
+		name = t.Name()
+	}
+	if _, taken := s.components[name]; taken {
+		panic(fmt.Sprintf("api: schema name %s used by %v and another type", name, t))
+	}
+	s.names[t] = name
+	s.components[name] = nil // Reserved while the fields are walked, for recursive types
+	s.components[name] = s.object(t)
+	return name
+}
+
+// object describes a struct's JSON fields; fields without omitempty are required
+func (s *schemaSet) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	s.fields(t, properties, &required)
+	out := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out
+}
+
+func (s *schemaSet) fields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			s.fields(f.Type, properties, required) // Embedded fields are promoted
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema := s.schema(f.Type)
+		switch f.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			// Nil values encode as null unless omitted
+			if !strings.Contains(opts, "omitempty") {
+				schema = nullable(schema)
+			}
+		}
+		properties[name] = schema
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// nullable marks a schema as accepting null; a $ref cannot carry siblings in OpenAPI 3.0
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if _, ok := schema["$ref"]; ok {
+		return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+	}
+	schema["nullable"] = true
+	return schema
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"article-assistant/internal/chaos"
+	"article-assistant/internal/commands"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llmaudit"
+	"article-assistant/internal/llmhealth"
+	"article-assistant/internal/usage"
+)
+
+// Request and response bodies of the HTTP API that are not domain types. The server encodes
+// these, Spec describes them and Client decodes them, so the three cannot drift apart.
+// LLMHealth and ChaosState mirror the bodies written by llmhealth.HealthHandler and chaos.Handler.
+
+// IngestRequest is the body of POST /ingest and POST /articles/reingest
+type IngestRequest struct {
+	URL string `json:"url"`
+}
+
+// StatusMessage acknowledges a completed write
+type StatusMessage struct {
+	Status  string `json:"status"` // Always "success"
+	Message string `json:"message"`
+}
+
+// IngestAccepted is returned with 202 when ingestion outlives the request; poll StatusURL
+type IngestAccepted struct {
+	Status    string `json:"status"`          // processing
+	Phase     string `json:"phase,omitempty"` // enrich once the page is fetched and only LLM enrichment is left
+	ID        string `json:"id"`
+	StatusURL string `json:"status_url"`
+}
+
+// IngestBatchRequest is the body of POST /ingest/batch
+type IngestBatchRequest struct {
+	URLs []string `json:"urls"` // At most MaxIngestBatch
+}
+
+// MaxIngestBatch is the most URLs one POST /ingest/batch takes
+const MaxIngestBatch = 100
+
+// IngestBatchItem is the outcome of one URL of a batch: queued with an ID to poll, or refused
+// with an error code
+type IngestBatchItem struct {
+	URL       string `json:"url"`
+	Status    string `json:"status"` // processing or rejected
+	ID        string `json:"id,omitempty"`
+	StatusURL string `json:"status_url,omitempty"`
+	Code      string `json:"code,omitempty"` // URL_NOT_ALLOWED or INGEST_UNAVAILABLE when rejected
+	Error     string `json:"error,omitempty"`
+}
+
+// IngestBatchResult lists the outcome of each URL of a batch, in request order
+type IngestBatchResult struct {
+	Items    []IngestBatchItem `json:"items"`
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+}
+
+// ImportResult reports a stored import, or the article it duplicates
+type ImportResult struct {
+	Status      string `json:"status"`                 // success or duplicate
+	ID          string `json:"id,omitempty"`           // Set on success
+	CanonicalID string `json:"canonical_id,omitempty"` // Set on duplicate
+}
+
+// ArticlePage is one page of GET /articles
+type ArticlePage struct {
+	Articles []domain.ArticleListItem `json:"articles"`
+	Total    int                      `json:"total"`
+	Limit    int                      `json:"limit"`
+	Offset   int                      `json:"offset"`
+}
+
+// ArticleSummary is an article's stored summary, or a rewrite of it at Level
+type ArticleSummary struct {
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+	Level   string `json:"level,omitempty"` // eli5, high_school or expert; empty for the stored summary
+	Summary string `json:"summary"`
+}
+
+// EntityPage is an entity's aggregates with one page of the articles mentioning it
+type EntityPage struct {
+	Entity   *domain.EntityDetail     `json:"entity"`
+	Articles []domain.ArticleListItem `json:"articles"`
+	Total    int                      `json:"total"`
+	Limit    int                      `json:"limit"`
+	Offset   int                      `json:"offset"`
+}
+
+// ArticleTags is an article's user-defined tags; POST /articles/{id}/tags takes the tags to add
+type ArticleTags struct {
+	ArticleID string   `json:"article_id,omitempty"`
+	Tags      []string `json:"tags"`
+}
+
+// TagList is returned by GET /tags, most used first
+type TagList struct {
+	Tags []domain.TagCount `json:"tags"`
+}
+
+// NoteList is returned by GET /articles/{id}/notes, oldest first
+type NoteList struct {
+	Notes []domain.ArticleNote `json:"notes"`
+}
+
+// CollectionList is returned by GET /collections, by name
+type CollectionList struct {
+	Collections []domain.Collection `json:"collections"`
+}
+
+// CollectionArticles is the body of POST /collections/{id}/articles: stored articles to add,
+// by ID or URL
+type CollectionArticles struct {
+	ArticleIDs []string `json:"article_ids,omitempty"`
+	URLs       []string `json:"urls,omitempty"`
+}
+
+// SearchList is returned by GET /searches
+type SearchList struct {
+	Searches []domain.SavedSearch `json:"searches"`
+}
+
+// TopicList is returned by GET /topics
+type TopicList struct {
+	Topics []domain.TaxonomyTopic `json:"topics"`
+}
+
+// AlertList is returned by GET /alerts
+type AlertList struct {
+	Alerts []domain.Alert `json:"alerts"`
+}
+
+// CommandList is returned by GET /commands
+type CommandList struct {
+	Commands   []commands.Command `json:"commands"`
+	FilterArgs []commands.Arg     `json:"filter_args"` // Accepted by every command with filters set
+	CommonArgs []commands.Arg     `json:"common_args"` // Accepted by every command
+}
+
+// FailureList is returned by GET /admin/failures
+type FailureList struct {
+	Failures []domain.IngestFailure `json:"failures"`
+}
+
+// AuditLog is returned by GET /admin/llm-audit
+type AuditLog struct {
+	Entries []llmaudit.Entry `json:"entries"`
+}
+
+// VectorIndexList is returned by /admin/vector-index
+type VectorIndexList struct {
+	Indexes []domain.VectorIndex `json:"indexes"`
+}
+
+// ReanalyzeStatus is returned by /admin/reanalyze
+type ReanalyzeStatus struct {
+	Version  string                        `json:"version"` // Current semantic extraction version
+	Pending  int                           `json:"pending"` // Enriched articles analyzed with another version
+	Versions []domain.AnalysisVersionCount `json:"versions"`
+	Run      *ingest.ReanalyzeProgress     `json:"run,omitempty"` // The running or last re-analysis since the server started
+}
+
+// UsageReport is returned by GET /usage, newest day first
+type UsageReport struct {
+	Days []usage.DayTotal `json:"days"`
+}
+
+// LLMHealth is returned by GET /admin/llm-health
+type LLMHealth struct {
+	Targets []llmhealth.TargetHealth `json:"targets"`
+}
+
+// ChaosState is returned by /admin/chaos
+type ChaosState struct {
+	Config chaos.Config `json:"config"`
+	Stats  chaos.Stats  `json:"stats"`
+}
+
+// Health is returned by GET /health
+type Health struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TypeScript renders the component schemas of Spec as TypeScript interfaces, written to
+// api/types.ts for front-end clients
+func TypeScript() []byte {
+	schemas := Spec()["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by go run ./cmd/openapi; DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Article Assistant API %s request and response types.\n", Version)
+	for _, name := range sortedKeys(schemas) {
+		fmt.Fprintf(&b, "\nexport interface %s ", name)
+		b.WriteString(tsObject(schemas[name].(map[string]interface{}), ""))
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+func tsObject(schema map[string]interface{}, indent string) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]string)
+	if len(properties) == 0 {
+		return "{}"
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(properties) {
+		optional := "?"
+		if slices.Contains(required, name) {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, name, optional, tsType(properties[name].(map[string]interface{}), indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func tsType(schema map[string]interface{}, indent string) string {
+	var t string
+	switch {
+	case schema["$ref"] != nil:
+		t = strings.TrimPrefix(schema["$ref"].(string), "#/components/schemas/")
+	case schema["allOf"] != nil:
+		t = tsType(schema["allOf"].([]interface{})[0].(map[string]interface{}), indent)
+	case schema["type"] == "array":
+		t = tsType(schema["items"].(map[string]interface{}), indent)
+		if strings.Contains(t, " ") {
+			t = "(" + t + ")"
+		}
+		t += "[]"
+	case schema["type"] == "object" && schema["additionalProperties"] != nil:
+		t = "Record<string, " + tsType(schema["additionalProperties"].(map[string]interface{}), indent) + ">"
+	case schema["type"] == "object":
+		t = tsObject(schema, indent)
+	case schema["type"] == "integer", schema["type"] == "number":
+		t = "number"
+	case schema["type"] == "string", schema["type"] == "boolean":
+		t = schema["type"].(string)
+	default:
+		t = "unknown"
+	}
+	if schema["nullable"] == true {
+		t += " | null"
+	}
+	return t
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
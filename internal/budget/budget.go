@@ -0,0 +1,80 @@
+// Package budget carries a chat request's overall time budget in its context. Optional LLM
+// steps, such as validating search results or labelling clusters, check the time left before
+// they start and are skipped when it is too short; skipped steps are recorded so the response
+// can say it was degraded.
+package budget
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Steps that are skipped when the budget runs low
+const (
+	StepPlanner         = "llm_planner"      // The keyword classifier plans the query instead
+	StepTopicValidation = "topic_validation" // Search results are kept without an LLM relevance check
+	StepClusterLabels   = "cluster_labels"   // Clusters are labelled with an article title
+	StepGrounding       = "grounding_check"  // The answer is returned without a grounding score
+)
+
+// Budget records the steps a request skipped for lack of time. It is safe for concurrent use.
+type Budget struct {
+	mu      sync.Mutex
+	skipped []string
+}
+
+type contextKey struct{}
+
+// NewContext returns a context whose deadline is d from now, or the parent's if earlier, and
+// that carries a budget recording skipped steps
+func NewContext(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, d)
+	return context.WithValue(ctx, contextKey{}, &Budget{}), cancel
+}
+
+// Remaining returns the time until ctx's deadline; ok is false when it has none
+func Remaining(ctx context.Context) (d time.Duration, ok bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// Allow reports whether at least need is left before ctx's deadline, so an optional step can
+// start. A context without a deadline always allows it. A refused step is recorded as skipped.
+func Allow(ctx context.Context, step string, need time.Duration) bool {
+	if left, ok := Remaining(ctx); !ok || left >= need {
+		return true
+	}
+	Skip(ctx, step)
+	return false
+}
+
+// Skip records step as skipped in ctx's budget, once; contexts without a budget ignore it
+func Skip(ctx context.Context, step string) {
+	b, _ := ctx.Value(contextKey{}).(*Budget)
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.skipped {
+		if s == step {
+			return
+		}
+	}
+	b.skipped = append(b.skipped, step)
+}
+
+// Skipped returns the steps skipped so far, in the order they were skipped
+func Skipped(ctx context.Context) []string {
+	b, _ := ctx.Value(contextKey{}).(*Budget)
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.skipped...)
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"article-assistant/internal/repository"
+)
+
+// Supported cache backends
+const (
+	BackendPostgres = "postgres"
+	BackendRedis    = "redis"
+)
+
+// Key prefixes separating what is cached in a shared backend
+const (
+	chatPrefix      = "chat:"
+	embeddingPrefix = "emb:"
+)
+
+// Backend stores JSON cache entries with an expiry. Implementations must be safe for
+// concurrent use; Postgres and Redis backends can be shared by several server replicas.
+type Backend interface {
+	// Get returns the value for key, or nil if it is missing or expired
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix removes every entry whose key starts with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+	// CleanExpired removes expired entries; a no-op for backends that expire keys themselves
+	CleanExpired(ctx context.Context) error
+}
+
+// PostgresBackend stores entries in the application store's chat cache (the chat_cache table on Postgres)
+type PostgresBackend struct {
+	Repo repository.ArticleStore
+}
+
+var _ Backend = (*PostgresBackend)(nil)
+
+// NewPostgresBackend creates a backend on the application database
+func NewPostgresBackend(repo repository.ArticleStore) *PostgresBackend {
+	return &PostgresBackend{Repo: repo}
+}
+
+func (b *PostgresBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	entry, err := b.Repo.GetChatCache(ctx, key)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	value, err := json.Marshal(entry.ResponseJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cached value: %w", err)
+	}
+	return value, nil
+}
+
+func (b *PostgresBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.Repo.SetChatCache(ctx, key, nil, json.RawMessage(value), ttl)
+}
+
+func (b *PostgresBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	return b.Repo.ClearChatCache(ctx, prefix)
+}
+
+func (b *PostgresBackend) CleanExpired(ctx context.Context) error {
+	return b.Repo.CleanExpiredChatCache(ctx)
+}
@@ -9,17 +9,41 @@ import (
 	"time"
 
 	"article-assistant/internal/domain"
-	"article-assistant/internal/repository"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/tenant"
 )
 
+// DefaultTTL is how long chat responses stay cached unless configured otherwise
+const DefaultTTL = 24 * time.Hour
+
+// BypassHeader, when set to a true value on a chat request, skips the cache lookup;
+// the fresh response still replaces the cached one
+const BypassHeader = "X-Cache-Bypass"
+
 // Service handles chat request/response caching
 type Service struct {
-	Repo *repository.Repo
+	Backend Backend
+	TTL     time.Duration // Lifetime of cached responses; zero or negative disables caching
+}
+
+// NewService creates a new cache service with the default TTL
+func NewService(backend Backend) *Service {
+	return &Service{Backend: backend, TTL: DefaultTTL}
+}
+
+// Enabled reports whether responses are cached
+func (s *Service) Enabled() bool {
+	return s.TTL > 0
 }
 
-// NewService creates a new cache service
-func NewService(repo *repository.Repo) *Service {
-	return &Service{Repo: repo}
+// chatKeyPrefix returns the prefix of ctx's tenant's chat keys, so tenants sharing a backend
+// never see each other's answers. The default tenant keeps the original keys; other tenants' keys
+// do not start with chatPrefix, so clearing one tenant's answers leaves the others'.
+func chatKeyPrefix(ctx context.Context) string {
+	if id := tenant.FromContext(ctx); id != tenant.Default {
+		return "tenant:" + id + ":" + chatPrefix
+	}
+	return chatPrefix
 }
 
 // calculateRequestHash computes SHA-256 hash of the request for caching
@@ -41,27 +65,20 @@ func (s *Service) GetCachedResponse(ctx context.Context, request interface{}) (*
 		return nil, fmt.Errorf("failed to calculate request hash: %w", err)
 	}
 
-	cache, err := s.Repo.GetChatCache(ctx, requestHash)
+	responseJSON, err := s.Backend.Get(ctx, chatKeyPrefix(ctx)+requestHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cache: %w", err)
 	}
 
-	if cache == nil {
-		log.Printf("💾 Cache miss for request hash: %s", requestHash[:8])
+	if responseJSON == nil {
+		logging.FromContext(ctx).Debug("cache miss", "request_hash", requestHash[:8])
 		return nil, nil // Cache miss
 	}
 
-	log.Printf("💾 Cache hit for request hash: %s", requestHash[:8])
+	logging.FromContext(ctx).Info("cache hit", "request_hash", requestHash[:8])
 
-	// Convert cached response back to ChatResponse
 	var response domain.ChatResponse
-	responseJSON, err := json.Marshal(cache.ResponseJSON)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal cached response: %w", err)
-	}
-
-	err = json.Unmarshal(responseJSON, &response)
-	if err != nil {
+	if err := json.Unmarshal(responseJSON, &response); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached response: %w", err)
 	}
 
@@ -75,18 +92,23 @@ func (s *Service) SetCachedResponse(ctx context.Context, request interface{}, re
 		return fmt.Errorf("failed to calculate request hash: %w", err)
 	}
 
-	err = s.Repo.SetChatCache(ctx, requestHash, request, response)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	err = s.Backend.Set(ctx, chatKeyPrefix(ctx)+requestHash, responseJSON, s.TTL)
 	if err != nil {
 		return fmt.Errorf("failed to set cache: %w", err)
 	}
 
-	log.Printf("💾 Cached response for request hash: %s", requestHash[:8])
+	logging.FromContext(ctx).Debug("cached response", "request_hash", requestHash[:8])
 	return nil
 }
 
 // CleanExpiredCache removes expired cache entries
 func (s *Service) CleanExpiredCache(ctx context.Context) error {
-	err := s.Repo.CleanExpiredChatCache(ctx)
+	err := s.Backend.CleanExpired(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to clean expired cache: %w", err)
 	}
@@ -95,24 +117,32 @@ func (s *Service) CleanExpiredCache(ctx context.Context) error {
 	return nil
 }
 
-// StartCacheCleanup starts a background goroutine to clean expired cache entries
-func (s *Service) StartCacheCleanup(ctx context.Context, interval time.Duration) {
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("🛑 Cache cleanup stopped")
-				return
-			case <-ticker.C:
-				if err := s.CleanExpiredCache(ctx); err != nil {
-					log.Printf("❌ Failed to clean expired cache: %v", err)
-				}
-			}
-		}
-	}()
+// InvalidateAll removes every cached response of ctx's tenant; used when articles are deleted or
+// re-ingested
+func (s *Service) InvalidateAll(ctx context.Context) error {
+	if err := s.Backend.DeletePrefix(ctx, chatKeyPrefix(ctx)); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("cleared chat cache")
+	return nil
+}
+
+// RunCacheCleanup cleans expired cache entries every interval until ctx is cancelled
+func (s *Service) RunCacheCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	log.Printf("🔄 Started cache cleanup with interval: %v", interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Cache cleanup stopped")
+			return
+		case <-ticker.C:
+			if err := s.CleanExpiredCache(ctx); err != nil {
+				log.Printf("❌ Failed to clean expired cache: %v", err)
+			}
+		}
+	}
 }
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+)
+
+// DefaultEmbeddingTTL is how long embeddings stay cached; they only change with the embedding model
+const DefaultEmbeddingTTL = 7 * 24 * time.Hour
+
+// maxCachedEmbeddingText limits caching to short texts such as search topics; article
+// summaries are embedded once at ingest and not worth storing twice
+const maxCachedEmbeddingText = 512
+
+// EmbeddingClient wraps an llm.Client and caches Embed results in a backend, so replicas
+// share embeddings of repeated texts such as common search topics. Other calls pass through.
+type EmbeddingClient struct {
+	llm.Client
+	Backend Backend
+	TTL     time.Duration
+}
+
+var _ llm.Client = (*EmbeddingClient)(nil)
+
+// WrapEmbeddings adds a shared embedding cache to an LLM client
+func WrapEmbeddings(inner llm.Client, backend Backend, ttl time.Duration) *EmbeddingClient {
+	return &EmbeddingClient{Client: inner, Backend: backend, TTL: ttl}
+}
+
+func (c *EmbeddingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if len(text) > maxCachedEmbeddingText {
+		return c.Client.Embed(ctx, text)
+	}
+	key := fmt.Sprintf("%s%x", embeddingPrefix, sha256.Sum256([]byte(text)))
+	logger := logging.FromContext(ctx)
+
+	if cached, err := c.Backend.Get(ctx, key); err != nil {
+		logger.Warn("embedding cache lookup failed", "error", err)
+	} else if cached != nil {
+		var embedding []float32
+		if err := json.Unmarshal(cached, &embedding); err == nil {
+			return embedding, nil
+		}
+	}
+
+	embedding, err := c.Client.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if value, err := json.Marshal(embedding); err == nil {
+		if err := c.Backend.Set(ctx, key, value, c.TTL); err != nil {
+			logger.Warn("failed to cache embedding", "error", err)
+		}
+	}
+	return embedding, nil
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend stores entries in Redis with native key expiry
+type RedisBackend struct {
+	Client    *redis.Client
+	Namespace string // Prepended to every key so several applications can share a Redis
+}
+
+var _ Backend = (*RedisBackend)(nil)
+
+// NewRedisBackend connects to the Redis server at url (redis://[:password@]host:port/db)
+func NewRedisBackend(ctx context.Context, url, namespace string) (*RedisBackend, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &RedisBackend{Client: client, Namespace: namespace}, nil
+}
+
+func (b *RedisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := b.Client.Get(ctx, b.Namespace+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (b *RedisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.Client.Set(ctx, b.Namespace+key, value, ttl).Err()
+}
+
+func (b *RedisBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	iter := b.Client.Scan(ctx, 0, b.Namespace+prefix+"*", 500).Iterator()
+	var batch []string
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == 500 {
+			if err := b.Client.Unlink(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return b.Client.Unlink(ctx, batch...).Err()
+	}
+	return nil
+}
+
+// CleanExpired is a no-op: Redis expires keys itself
+func (b *RedisBackend) CleanExpired(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package chaos provides failure injection hooks for resilience testing.
+// It is wired in only when CHAOS_ENABLED=true and must never be enabled in production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrInjected marks errors produced by the injector
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config describes which failures to inject. Rates are probabilities in [0, 1].
+type Config struct {
+	LLMLatencyMS      int     `json:"llm_latency_ms"`      // Added before every LLM call
+	LLMErrorRate      float64 `json:"llm_error_rate"`      // Fraction of LLM calls that fail
+	LLMMalformedJSON  bool    `json:"llm_malformed_json"`  // Corrupt structured LLM responses
+	DBErrorRate       float64 `json:"db_error_rate"`       // Fraction of DB queries/execs that fail
+	IngestFailureRate float64 `json:"ingest_failure_rate"` // Fraction of ingested URLs that fail
+}
+
+// Stats counts injected failures since the last reset
+type Stats struct {
+	LLMErrors      int `json:"llm_errors"`
+	LLMMalformed   int `json:"llm_malformed"`
+	DBErrors       int `json:"db_errors"`
+	IngestFailures int `json:"ingest_failures"`
+}
+
+// Injector holds the active chaos configuration
+type Injector struct {
+	mu    sync.Mutex
+	cfg   Config
+	stats Stats
+	rng   *rand.Rand
+}
+
+// NewInjector creates an injector with no failures configured
+func NewInjector() *Injector {
+	return &Injector{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Config returns the active configuration
+func (i *Injector) Config() Config {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.cfg
+}
+
+// SetConfig replaces the active configuration
+func (i *Injector) SetConfig(cfg Config) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cfg = cfg
+}
+
+// Stats returns injected failure counts
+func (i *Injector) Stats() Stats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.stats
+}
+
+// Reset clears configuration and counters
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cfg = Config{}
+	i.stats = Stats{}
+}
+
+// roll returns true with the given probability; the caller must hold mu
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	return rate >= 1 || i.rng.Float64() < rate
+}
+
+// BeforeLLM applies configured latency and possibly returns an injected error
+func (i *Injector) BeforeLLM(ctx context.Context) error {
+	i.mu.Lock()
+	latency := time.Duration(i.cfg.LLMLatencyMS) * time.Millisecond
+	fail := i.roll(i.cfg.LLMErrorRate)
+	if fail {
+		i.stats.LLMErrors++
+	}
+	i.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fail {
+		return ErrInjected
+	}
+	return nil
+}
+
+// MalformJSON reports whether the next structured LLM response should be corrupted
+func (i *Injector) MalformJSON() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.cfg.LLMMalformedJSON {
+		i.stats.LLMMalformed++
+		return true
+	}
+	return false
+}
+
+// DBError possibly returns an injected database error
+func (i *Injector) DBError() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.roll(i.cfg.DBErrorRate) {
+		i.stats.DBErrors++
+		return ErrInjected
+	}
+	return nil
+}
+
+// IngestHook possibly fails ingestion of a URL; it matches ingest.Service.FailureHook
+func (i *Injector) IngestHook(_ context.Context, _ string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.roll(i.cfg.IngestFailureRate) {
+		i.stats.IngestFailures++
+		return ErrInjected
+	}
+	return nil
+}
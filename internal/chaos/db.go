@@ -0,0 +1,124 @@
+package chaos
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+)
+
+var registerMu sync.Mutex
+
+// RegisterDriver registers a sql driver named name that wraps base and injects DB errors.
+// Open the database with sql.Open(name, dsn) to route queries through the injector.
+func RegisterDriver(name string, base driver.Driver, injector *Injector) {
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	for _, d := range sql.Drivers() {
+		if d == name {
+			return
+		}
+	}
+	sql.Register(name, &chaosDriver{base: base, injector: injector})
+}
+
+type chaosDriver struct {
+	base     driver.Driver
+	injector *Injector
+}
+
+func (d *chaosDriver) Open(dsn string) (driver.Conn, error) {
+	if err := d.injector.DBError(); err != nil {
+		return nil, err
+	}
+	conn, err := d.base.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &chaosConn{Conn: conn, injector: d.injector}, nil
+}
+
+// chaosConn injects errors before queries and execs, delegating everything else
+type chaosConn struct {
+	driver.Conn
+	injector *Injector
+}
+
+func (c *chaosConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.injector.DBError(); err != nil {
+		return nil, err
+	}
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *chaosConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.injector.DBError(); err != nil {
+		return nil, err
+	}
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *chaosConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.injector.DBError(); err != nil {
+		return nil, err
+	}
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &chaosStmt{Stmt: stmt, injector: c.injector}, nil
+}
+
+// chaosStmt injects errors before each use of a prepared statement, which is otherwise
+// prepared once and executed many times
+type chaosStmt struct {
+	driver.Stmt
+	injector *Injector
+}
+
+func (s *chaosStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.injector.DBError(); err != nil {
+		return nil, err
+	}
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return q.QueryContext(ctx, args)
+	}
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return s.Stmt.Query(values) //nolint:staticcheck // fallback for drivers without QueryContext
+}
+
+func (s *chaosStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.injector.DBError(); err != nil {
+		return nil, err
+	}
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return e.ExecContext(ctx, args)
+	}
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return s.Stmt.Exec(values) //nolint:staticcheck // fallback for drivers without ExecContext
+}
+
+func (c *chaosConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+}
@@ -0,0 +1,38 @@
+package chaos
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler serves the admin endpoint for inspecting and changing the injector:
+//
+//	GET    -> current config and stats
+//	POST   -> replace config with the JSON body
+//	DELETE -> reset config and stats
+func Handler(injector *Injector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var cfg Config
+			if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+				http.Error(w, "Invalid request body", 400)
+				return
+			}
+			injector.SetConfig(cfg)
+		case http.MethodDelete:
+			injector.Reset()
+		default:
+			http.Error(w, "Method not allowed", 405)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"config": injector.Config(),
+			"stats":  injector.Stats(),
+		})
+	}
+}
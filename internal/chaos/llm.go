@@ -0,0 +1,83 @@
+package chaos
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"context"
+	"fmt"
+)
+
+// malformedJSON is returned in place of structured LLM output when corruption is enabled
+const malformedJSON = `{"command": "summary", "args": {"urls": [`
+
+// LLMClient wraps an llm.Client and injects latency, errors and malformed output
+type LLMClient struct {
+	Inner    llm.Client
+	Injector *Injector
+}
+
+var _ llm.Client = (*LLMClient)(nil)
+
+// WrapLLM wraps an LLM client with the injector
+func WrapLLM(inner llm.Client, injector *Injector) *LLMClient {
+	return &LLMClient{Inner: inner, Injector: injector}
+}
+
+func (c *LLMClient) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return "", err
+	}
+	return c.Inner.Summarize(ctx, text, opts)
+}
+
+func (c *LLMClient) SentimentScore(ctx context.Context, text string) (float64, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return 0, err
+	}
+	return c.Inner.SentimentScore(ctx, text)
+}
+
+func (c *LLMClient) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return "", err
+	}
+	return c.Inner.ToneCompare(ctx, text1, text2)
+}
+
+func (c *LLMClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return nil, err
+	}
+	return c.Inner.Embed(ctx, text)
+}
+
+func (c *LLMClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return "", err
+	}
+	if c.Injector.MalformJSON() {
+		return malformedJSON, nil
+	}
+	return c.Inner.GenerateText(ctx, prompt)
+}
+
+func (c *LLMClient) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return nil, err
+	}
+	if c.Injector.MalformJSON() {
+		return nil, fmt.Errorf("failed to parse plan JSON: %w", ErrInjected)
+	}
+	return c.Inner.PlanQuery(ctx, query)
+}
+
+func (c *LLMClient) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	if err := c.Injector.BeforeLLM(ctx); err != nil {
+		return nil, err
+	}
+	if c.Injector.MalformJSON() {
+		// Mirrors the real client's fallback when the response is not valid JSON
+		return &domain.SemanticAnalysis{Sentiment: "neutral", SentimentScore: 0.5, Tone: "neutral"}, nil
+	}
+	return c.Inner.ExtractAllSemantics(ctx, text)
+}
@@ -0,0 +1,140 @@
+// Package chatbot lets the assistant be used from messaging apps. An Adapter receives messages
+// from a platform, the Bot turns each into a ChatRequest for the API, and the adapter sends the
+// ChatResponse back as the platform's rich message, sources included. Telegram is polled over
+// the Bot API and Discord answers slash commands on an interactions endpoint; neither needs a
+// client library.
+package chatbot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+)
+
+// Message is a question received on a messaging platform
+type Message struct {
+	Platform string // e.g. "telegram"
+	ChatID   string // Conversation the answer goes to; also scopes the chat session
+	UserID   string
+	Text     string // The question, without the command or bot mention
+}
+
+// Handler answers one message
+type Handler func(ctx context.Context, msg Message) (*domain.ChatResponse, error)
+
+// Adapter connects to a messaging platform. Run passes each question to handle and sends its
+// answer, or the error, back until ctx is done or the connection fails.
+type Adapter interface {
+	Run(ctx context.Context, handle Handler) error
+	// Name labels the adapter in logs, e.g. "telegram"
+	Name() string
+}
+
+// Chatter answers chat requests, e.g. *api.Client calling POST /chat
+type Chatter interface {
+	Chat(ctx context.Context, req domain.ChatRequest) (*domain.ChatResponse, error)
+}
+
+var _ Chatter = (*api.Client)(nil)
+
+// DefaultTimeout bounds answering one message
+const DefaultTimeout = 2 * time.Minute
+
+// HelpText answers empty questions and the platforms' help commands
+const HelpText = "Ask me about the stored news articles, e.g. \"What are the top entities?\", " +
+	"\"Summarize https://example.com/article\" or \"Most positive article about AI regulation\"."
+
+// Reconnect backoff after an adapter fails
+const (
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// Bot answers the messages of one adapter with Chat
+type Bot struct {
+	Adapter Adapter
+	Chat    Chatter
+	Timeout time.Duration // Per message; DefaultTimeout when 0
+}
+
+// New creates a bot answering adapter's messages with chat
+func New(adapter Adapter, chat Chatter) *Bot {
+	return &Bot{Adapter: adapter, Chat: chat}
+}
+
+// Run serves the adapter until ctx is cancelled, reconnecting with exponential backoff when it fails
+func (b *Bot) Run(ctx context.Context) {
+	log.Printf("💬 Answering %s messages", b.Adapter.Name())
+	backoff := minBackoff
+	for ctx.Err() == nil {
+		started := time.Now()
+		err := b.Adapter.Run(ctx, b.Handle)
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+		log.Printf("⚠️  %s bot stopped: %v; reconnecting in %v", b.Adapter.Name(), err, backoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+	log.Printf("💬 %s bot stopped", b.Adapter.Name())
+}
+
+// Handle answers one message; an empty question gets HelpText without calling Chat
+func (b *Bot) Handle(ctx context.Context, msg Message) (*domain.ChatResponse, error) {
+	req := ChatRequest(msg)
+	if req.Query == "" {
+		return &domain.ChatResponse{Answer: HelpText, Sources: []domain.Source{}}, nil
+	}
+	timeout := b.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := b.Chat.Chat(ctx, req)
+	if err != nil {
+		logging.FromContext(ctx).Warn("chat bot question failed", "platform", msg.Platform, "chat", msg.ChatID, "error", err)
+	}
+	return resp, err
+}
+
+// ChatRequest translates a message to a chat request. Each conversation is its own session, so
+// follow-up questions reuse the intermediate results of earlier ones.
+func ChatRequest(msg Message) domain.ChatRequest {
+	return domain.ChatRequest{
+		Query:     strings.TrimSpace(msg.Text),
+		SessionID: msg.Platform + ":" + msg.ChatID,
+	}
+}
+
+// ErrorText describes a failed question to the person who asked it, without internal details
+func ErrorText(err error) string {
+	var apiErr *api.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "That took too long to answer. Please try again or ask something narrower."
+	case errors.As(err, &apiErr):
+		switch apiErr.Code {
+		case domain.ErrCodeLLMBusy, domain.ErrCodeRateLimited, domain.ErrCodeLLMTimeout, domain.ErrCodeTimeout:
+			return "The assistant is busy right now. Please try again in a minute."
+		case domain.ErrCodeBadRequest:
+			return "I could not read that question: " + apiErr.Message
+		case domain.ErrCodeUnauthorized, domain.ErrCodeForbidden:
+			return "This bot is not allowed to ask the assistant; check its API key."
+		}
+	}
+	return "Sorry, something went wrong answering that."
+}
@@ -0,0 +1,283 @@
+package chatbot
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"article-assistant/internal/logging"
+)
+
+// DefaultDiscordAPIURL is the Discord HTTP API
+const DefaultDiscordAPIURL = "https://discord.com/api/v10"
+
+// DiscordCommand is the slash command the bot answers: /ask question:<text>
+const DiscordCommand = "ask"
+
+// Discord limits and interaction types
+const (
+	discordMaxDescription = 4096
+	discordMaxField       = 1024
+	discordMaxFooter      = 2048
+
+	discordPing            = 1
+	discordCommand         = 2
+	discordPong            = 1
+	discordDeferredMessage = 5
+
+	// discordFollowupTimeout is how long an interaction token stays valid
+	discordFollowupTimeout = 15 * time.Minute
+
+	discordColor       = 0x2456c9
+	discordFailedColor = 0xb42318
+)
+
+// DiscordAdapter answers the /ask slash command on a Discord interactions endpoint, so no
+// gateway connection is needed. Set the application's Interactions Endpoint URL to the address
+// Run listens on, and register the command once with RegisterCommand.
+type DiscordAdapter struct {
+	ApplicationID string
+	PublicKey     string // Hex public key from the developer portal; verifies each interaction
+	Addr          string // Listen address of the interactions endpoint, e.g. :8081
+	APIURL        string // DefaultDiscordAPIURL when empty
+
+	Client *http.Client // Default client with a 30s timeout when nil
+}
+
+// Name labels the adapter as discord
+func (a *DiscordAdapter) Name() string { return "discord" }
+
+type discordInteraction struct {
+	Type          int    `json:"type"`
+	ApplicationID string `json:"application_id"`
+	Token         string `json:"token"`
+	ChannelID     string `json:"channel_id"`
+	Data          struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"` // Set instead of Member in direct messages
+}
+
+type discordUser struct {
+	ID string `json:"id"`
+}
+
+type discordEmbed struct {
+	Description string              `json:"description"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// Run serves the interactions endpoint on Addr until ctx is done, then waits for the answers
+// still being written
+func (a *DiscordAdapter) Run(ctx context.Context, handle Handler) error {
+	handler, wait, err := a.handler(ctx, handle)
+	if err != nil {
+		return err
+	}
+	defer wait()
+
+	server := &http.Server{Addr: a.Addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	stop := context.AfterFunc(ctx, func() {
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	})
+	defer stop()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("discord interactions endpoint: %w", err)
+	}
+	return ctx.Err()
+}
+
+// Endpoint returns the interactions endpoint. Verified /ask commands are acknowledged at once
+// and answered in the background, within ctx, by editing the acknowledgement.
+func (a *DiscordAdapter) Endpoint(ctx context.Context, handle Handler) (http.Handler, error) {
+	h, _, err := a.handler(ctx, handle)
+	return h, err
+}
+
+func (a *DiscordAdapter) handler(ctx context.Context, handle Handler) (http.Handler, func(), error) {
+	key, err := hex.DecodeString(a.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, nil, errors.New("the Discord public key must be 64 hex characters")
+	}
+	var wg sync.WaitGroup
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		sig, err := hex.DecodeString(r.Header.Get("X-Signature-Ed25519"))
+		signed := append([]byte(r.Header.Get("X-Signature-Timestamp")), body...)
+		if err != nil || !ed25519.Verify(key, signed, sig) {
+			http.Error(w, "invalid request signature", http.StatusUnauthorized)
+			return
+		}
+
+		var in discordInteraction
+		if err := json.Unmarshal(body, &in); err != nil {
+			http.Error(w, "invalid interaction", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case in.Type == discordPing:
+			json.NewEncoder(w).Encode(map[string]int{"type": discordPong})
+		case in.Type == discordCommand && in.Data.Name == DiscordCommand:
+			json.NewEncoder(w).Encode(map[string]int{"type": discordDeferredMessage})
+			msg := a.question(in)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				answerCtx, cancel := context.WithTimeout(ctx, discordFollowupTimeout)
+				defer cancel()
+				resp, err := handle(answerCtx, msg)
+				if err := a.editOriginal(answerCtx, in, NewReply(resp, err)); err != nil {
+					logging.FromContext(ctx).Warn("failed to send Discord answer", "channel", in.ChannelID, "error", err)
+				}
+			}()
+		default:
+			http.Error(w, "unsupported interaction", http.StatusBadRequest)
+		}
+	})
+	return h, wg.Wait, nil
+}
+
+// question translates an /ask interaction to a message
+func (a *DiscordAdapter) question(in discordInteraction) Message {
+	msg := Message{Platform: a.Name(), ChatID: in.ChannelID}
+	if in.Member != nil {
+		msg.UserID = in.Member.User.ID
+	} else if in.User != nil {
+		msg.UserID = in.User.ID
+	}
+	for _, o := range in.Data.Options {
+		if o.Name == "question" {
+			json.Unmarshal(o.Value, &msg.Text)
+		}
+	}
+	return msg
+}
+
+// DiscordEmbed renders a reply as a Discord embed: the answer, a field of source links and
+// the notes as footer, within Discord's limits
+func DiscordEmbed(r Reply) map[string]interface{} {
+	embed := discordEmbed{Description: truncate(r.Text, discordMaxDescription), Color: discordColor}
+	if r.Failed {
+		embed.Color = discordFailedColor
+	}
+	if len(r.Sources) > 0 {
+		links := strings.NewReplacer("[", "(", "]", ")")
+		var lines []string
+		for i, s := range r.Sources {
+			line := fmt.Sprintf("%d. [%s](%s)", i+1, links.Replace(truncate(sourceTitle(s), 80)), strings.ReplaceAll(s.URL, ")", "%29"))
+			if len(strings.Join(append(lines, line), "\n")) > discordMaxField {
+				break
+			}
+			lines = append(lines, line)
+		}
+		embed.Fields = []discordEmbedField{{Name: "Sources", Value: strings.Join(lines, "\n")}}
+	}
+	if len(r.Notes) > 0 {
+		embed.Footer = &discordEmbedFooter{Text: truncate(strings.Join(r.Notes, " "), discordMaxFooter)}
+	}
+	// Answers quote article text; nobody gets pinged by it
+	return map[string]interface{}{
+		"embeds":           []discordEmbed{embed},
+		"allowed_mentions": map[string][]string{"parse": {}},
+	}
+}
+
+// editOriginal replaces the deferred acknowledgement of an interaction with the answer
+func (a *DiscordAdapter) editOriginal(ctx context.Context, in discordInteraction, r Reply) error {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", in.ApplicationID, in.Token)
+	return a.send(ctx, http.MethodPatch, path, "", DiscordEmbed(r))
+}
+
+// RegisterCommand creates or updates the /ask command for the application, authorized by the
+// bot token. Global commands can take a while to appear in clients.
+func (a *DiscordAdapter) RegisterCommand(ctx context.Context, botToken string) error {
+	commands := []map[string]interface{}{{
+		"name":        DiscordCommand,
+		"description": "Ask about the stored news articles",
+		"options": []map[string]interface{}{{
+			"type": 3, "name": "question", "description": "Your question", "required": true,
+		}},
+	}}
+	return a.send(ctx, http.MethodPut, "/applications/"+a.ApplicationID+"/commands", botToken, commands)
+}
+
+func (a *DiscordAdapter) send(ctx context.Context, method, path, botToken string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	base := a.APIURL
+	if base == "" {
+		base = DefaultDiscordAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(base, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if botToken != "" {
+		req.Header.Set("Authorization", "Bot "+botToken)
+	}
+	client := a.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// Webhook URLs carry the interaction token; keep it out of errors and logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s %s: %w", method, strings.SplitN(path, "/", 3)[1], err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&e)
+		return fmt.Errorf("discord api returned %d: %s", resp.StatusCode, e.Message)
+	}
+	return nil
+}
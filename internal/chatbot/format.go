@@ -0,0 +1,67 @@
+package chatbot
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"article-assistant/internal/domain"
+)
+
+// MaxSources bounds the sources listed under an answer
+const MaxSources = 5
+
+// Reply is a chat response reduced to what a messaging app shows; adapters render it in the
+// platform's markup
+type Reply struct {
+	Text    string          // The answer, or what went wrong
+	Sources []domain.Source // At most MaxSources, in the response's order
+	Notes   []string        // Caveats shown after the sources, e.g. a weakly grounded answer
+	Failed  bool
+}
+
+// NewReply reduces the outcome of a question to a Reply
+func NewReply(resp *domain.ChatResponse, err error) Reply {
+	if err != nil || resp == nil {
+		return Reply{Text: ErrorText(err), Failed: true}
+	}
+	r := Reply{Text: strings.TrimSpace(resp.Answer)}
+	if r.Text == "" {
+		r.Text = "I found nothing to answer that with."
+	}
+	for _, s := range resp.Sources {
+		if len(r.Sources) == MaxSources {
+			break
+		}
+		if s.URL != "" {
+			r.Sources = append(r.Sources, s)
+		}
+	}
+	if resp.Error != nil && resp.Error.Code == domain.ErrCodeNoAnswer {
+		r.Notes = append(r.Notes, "No stored article answers this; ingest one first.")
+	}
+	if resp.Grounding != nil && resp.Grounding.Low {
+		r.Notes = append(r.Notes, fmt.Sprintf("Only %.0f%% of this answer is backed by its sources.", resp.Grounding.Score*100))
+	}
+	if len(resp.Degraded) > 0 {
+		r.Notes = append(r.Notes, "Partial answer: some steps were skipped to answer in time.")
+	}
+	return r
+}
+
+// sourceTitle is a source's title, or its URL when it has none
+func sourceTitle(s domain.Source) string {
+	if t := strings.TrimSpace(s.Title); t != "" {
+		return t
+	}
+	return s.URL
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis when cut
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
@@ -0,0 +1,252 @@
+package chatbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"article-assistant/internal/logging"
+)
+
+// DefaultTelegramAPIURL is the Telegram Bot API
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// telegramMaxMessage is the longest message text Telegram accepts, in characters
+const telegramMaxMessage = 4096
+
+// TelegramAdapter answers Telegram messages, long polling the Bot API. Private chats may ask
+// in plain text; in groups the bot answers /ask <question> and replies to its own messages.
+type TelegramAdapter struct {
+	Token  string // Bot token from @BotFather
+	APIURL string // DefaultTelegramAPIURL when empty
+
+	Client        *http.Client  // Default client with a timeout above PollTimeout when nil
+	PollTimeout   time.Duration // How long one poll waits for updates; default 30s
+	MaxConcurrent int           // Questions answered at once; default 4
+
+	username string // The bot's @username, learned with getMe to recognize mentions
+}
+
+// Name labels the adapter as telegram
+func (a *TelegramAdapter) Name() string { return "telegram" }
+
+type telegramUpdate struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *telegramMessage `json:"message"`
+}
+
+type telegramMessage struct {
+	MessageID int64 `json:"message_id"`
+	From      *struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"from"`
+	Chat struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"` // private, group, supergroup or channel
+	} `json:"chat"`
+	Text           string           `json:"text"`
+	ReplyToMessage *telegramMessage `json:"reply_to_message"`
+}
+
+// Run polls for updates and answers each question in the background, up to MaxConcurrent at
+// once, until ctx is done or a poll fails. Answers still being written are waited for.
+func (a *TelegramAdapter) Run(ctx context.Context, handle Handler) error {
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := a.call(ctx, "getMe", nil, &me); err != nil {
+		return fmt.Errorf("failed to reach the Telegram Bot API: %w", err)
+	}
+	a.username = me.Username
+
+	poll := a.PollTimeout
+	if poll <= 0 {
+		poll = 30 * time.Second
+	}
+	limit := a.MaxConcurrent
+	if limit <= 0 {
+		limit = 4
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	var offset int64
+	for ctx.Err() == nil {
+		var updates []telegramUpdate
+		params := map[string]interface{}{"offset": offset, "timeout": int(poll.Seconds()), "allowed_updates": []string{"message"}}
+		if err := a.call(ctx, "getUpdates", params, &updates); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("failed to poll Telegram: %w", err)
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			msg, ok := a.question(u.Message)
+			if !ok {
+				continue
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			wg.Add(1)
+			go func(reply int64) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				a.answer(ctx, msg, reply, handle)
+			}(u.Message.MessageID)
+		}
+	}
+	return ctx.Err()
+}
+
+// question extracts the question a message asks the bot, if it asks one
+func (a *TelegramAdapter) question(m *telegramMessage) (Message, bool) {
+	if m == nil || m.Text == "" {
+		return Message{}, false
+	}
+	text := strings.TrimSpace(m.Text)
+	msg := Message{Platform: a.Name(), ChatID: strconv.FormatInt(m.Chat.ID, 10)}
+	if m.From != nil {
+		msg.UserID = strconv.FormatInt(m.From.ID, 10)
+	}
+
+	if strings.HasPrefix(text, "/") {
+		command, rest, _ := strings.Cut(text, " ")
+		command, bot, addressed := strings.Cut(command, "@")
+		if addressed && !strings.EqualFold(bot, a.username) {
+			return Message{}, false // A command for another bot in the group
+		}
+		switch command {
+		case "/ask":
+			msg.Text = rest
+		case "/start", "/help":
+			msg.Text = ""
+		default:
+			return Message{}, false
+		}
+		return msg, true
+	}
+
+	if m.Chat.Type == "private" {
+		msg.Text = text
+		return msg, true
+	}
+	if r := m.ReplyToMessage; r != nil && r.From != nil && a.username != "" && strings.EqualFold(r.From.Username, a.username) {
+		msg.Text = text
+		return msg, true
+	}
+	if a.username != "" {
+		mention := "@" + a.username
+		if i := strings.Index(strings.ToLower(text), strings.ToLower(mention)); i >= 0 {
+			msg.Text = strings.TrimSpace(text[:i] + text[i+len(mention):])
+			return msg, true
+		}
+	}
+	return Message{}, false
+}
+
+// answer shows the bot typing while handle runs, then replies with the answer
+func (a *TelegramAdapter) answer(ctx context.Context, msg Message, replyTo int64, handle Handler) {
+	a.call(ctx, "sendChatAction", map[string]interface{}{"chat_id": msg.ChatID, "action": "typing"}, nil)
+	resp, err := handle(ctx, msg)
+	if ctx.Err() != nil {
+		return
+	}
+	params := map[string]interface{}{
+		"chat_id":                  msg.ChatID,
+		"text":                     TelegramHTML(NewReply(resp, err)),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+		"reply_to_message_id":      replyTo,
+	}
+	if err := a.call(ctx, "sendMessage", params, nil); err != nil {
+		logging.FromContext(ctx).Warn("failed to send Telegram answer", "chat", msg.ChatID, "error", err)
+	}
+}
+
+// TelegramHTML renders a reply in Telegram's HTML markup: the answer, numbered source links
+// and notes in italics, within Telegram's message length
+func TelegramHTML(r Reply) string {
+	var tail strings.Builder
+	if len(r.Sources) > 0 {
+		tail.WriteString("\n\n<b>Sources</b>")
+		for i, s := range r.Sources {
+			fmt.Fprintf(&tail, "\n%d. <a href=\"%s\">%s</a>", i+1, html.EscapeString(s.URL), html.EscapeString(truncate(sourceTitle(s), 120)))
+		}
+	}
+	for _, n := range r.Notes {
+		fmt.Fprintf(&tail, "\n\n<i>%s</i>", html.EscapeString(n))
+	}
+
+	// Telegram counts the visible text, which the markup of the tail overstates; the answer is
+	// cut before escaping so no entity is split
+	room := telegramMaxMessage - utf8.RuneCountInString(tail.String())
+	return html.EscapeString(truncate(r.Text, max(room, 100))) + tail.String()
+}
+
+// call invokes a Bot API method with JSON params and decodes its result into out, if set
+func (a *TelegramAdapter) call(ctx context.Context, method string, params, out interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	base := a.APIURL
+	if base == "" {
+		base = DefaultTelegramAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/bot"+a.Token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := a.Client
+	if client == nil {
+		client = &http.Client{Timeout: a.PollTimeout + time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The token is part of the URL; keep it out of errors and logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		Description string          `json:"description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 8<<20)).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s: invalid response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("%s: %s (status %d)", method, envelope.Description, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, out)
+}
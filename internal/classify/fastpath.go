@@ -0,0 +1,50 @@
+package classify
+
+import (
+	"article-assistant/internal/domain"
+	"regexp"
+	"strings"
+)
+
+// urlList matches one or more URL placeholders joined by commas and "and"
+const urlList = `<url>(?:(?:,|,? and) <url>)*`
+
+// fastPaths are the query shapes that need no LLM planning call: a plain verb and URLs, with
+// nothing else the planner would turn into arguments (language, style, length, ...). Patterns
+// match the lower-cased query with each URL replaced by <url> and end punctuation removed.
+var fastPaths = []struct {
+	command string
+	re      *regexp.Regexp
+}{
+	{"summary", regexp.MustCompile(`^(?:(?:give me |show me )?(?:a |the )?summary of|summarize|summarise)(?: the article| this article| the articles)? ` + urlList + `$`)},
+	{"keywords_or_topics", regexp.MustCompile(`^(?:what are |extract |list |get )?(?:the )?(?:main |key )?(?:keywords|topics)(?: of| in| from| for) ` + urlList + `$`)},
+	{"get_sentiment", regexp.MustCompile(`^(?:(?:what is |get )?(?:the )?sentiment of ` + urlList + `|is <url> positive or negative)$`)},
+	{"ton_key_differences", regexp.MustCompile(`^compare the tones? of <url>(?:(?:,|,? and| with| to| vs\.?) <url>)+$`)},
+	{"compare_articles", regexp.MustCompile(`^compare <url>(?:(?:,|,? and| with| to| vs\.?) <url>)+$`)},
+}
+
+// FastPlan returns a plan for queries that are only an obvious command verb and URLs, such as
+// "Summary of https://example.com/", or nil when the query needs the planner
+func FastPlan(query string) *domain.Plan {
+	urls := ExtractURLs(query)
+	if len(urls) == 0 {
+		return nil
+	}
+	shape := urlRe.ReplaceAllStringFunc(query, func(u string) string {
+		return "<url>" + u[len(strings.TrimRight(u, ".,;:!?)")):]
+	})
+	shape = strings.Join(strings.Fields(strings.ToLower(shape)), " ")
+	shape = strings.TrimRight(shape, "?.! ")
+
+	for _, p := range fastPaths {
+		if !p.re.MatchString(shape) {
+			continue
+		}
+		list := make([]interface{}, len(urls))
+		for i, u := range urls {
+			list[i] = u
+		}
+		return &domain.Plan{Command: p.command, Args: map[string]interface{}{"urls": list}, Router: domain.RouterFastPath}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package classify
+
+import (
+	"article-assistant/internal/domain"
+	"regexp"
+	"strings"
+)
+
+// DefaultMinConfidence is the planner confidence below which Route uses the heuristics
+const DefaultMinConfidence = 0.5
+
+// commandsByQueryType maps heuristic query types to planner commands
+var commandsByQueryType = map[string]string{
+	domain.QuerySummary:      "summary",
+	domain.QueryKeywords:     "keywords_or_topics",
+	domain.QuerySentiment:    "get_sentiment",
+	domain.QueryCompare:      "compare_articles",
+	domain.QueryTone:         "ton_key_differences",
+	domain.QuerySearch:       "filter_by_specific_topic",
+	domain.QueryMorePositive: "most_positive_article_for_filter",
+	domain.QueryTopEntities:  "get_top_entities",
+}
+
+var urlRe = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// ExtractURLs returns the URLs in query as written, without trailing sentence punctuation
+func ExtractURLs(query string) []string {
+	var urls []string
+	for _, u := range urlRe.FindAllString(query, -1) {
+		urls = append(urls, strings.TrimRight(u, ".,;:!?)"))
+	}
+	return urls
+}
+
+// PlanQuery builds a plan from the keyword heuristics, or returns nil when they do not
+// recognise the query
+func PlanQuery(query string) *domain.Plan {
+	analysis := AnalyzeQuery(query)
+	command, ok := commandsByQueryType[analysis.QueryType]
+	if !ok {
+		return nil
+	}
+	args := map[string]interface{}{}
+	if urls := ExtractURLs(query); len(urls) > 0 {
+		list := make([]interface{}, len(urls))
+		for i, u := range urls {
+			list[i] = u
+		}
+		args["urls"] = list
+	}
+	if analysis.FilterTopic != "" {
+		args["filter"] = analysis.FilterTopic
+	}
+	return &domain.Plan{Command: command, Args: args, Router: domain.RouterClassifier}
+}
+
+// Route returns the planner's plan unless it reported a confidence below minConfidence, in
+// which case the heuristic plan for query is used instead. The planner's plan is kept when the
+// heuristics do not recognise the query either. The returned plan records its router; plans
+// that already name one were not made by the planner and are returned unchanged.
+func Route(plan *domain.Plan, query string, minConfidence float64) *domain.Plan {
+	if plan.Router != "" {
+		return plan
+	}
+	plan.Router = domain.RouterLLM
+	if plan.Confidence == nil || *plan.Confidence >= minConfidence {
+		return plan
+	}
+	fallback := PlanQuery(query)
+	if fallback == nil {
+		return plan
+	}
+	fallback.Confidence = plan.Confidence
+	return fallback
+}
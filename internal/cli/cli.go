@@ -0,0 +1,271 @@
+// Package cli implements the article-assistant command-line tool. Commands talk to a running
+// server through the HTTP API, or in offline mode directly to the configured store and LLM.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/export"
+)
+
+// DefaultServerURL is used when neither -server nor ARTICLE_ASSISTANT_URL is set
+const DefaultServerURL = "http://localhost:8080"
+
+// Backend runs commands against a server or against local services
+type Backend interface {
+	// Ingest stores url and returns a one-line outcome
+	Ingest(ctx context.Context, url string) (string, error)
+	Chat(ctx context.Context, query string) (*domain.ChatResponse, error)
+	List(ctx context.Context, limit, offset int, sort string, ascending bool) (*api.ArticlePage, error)
+	Export(ctx context.Context, w io.Writer, format string, embeddings bool, filter domain.ArticleFilter) error
+}
+
+const usage = `Usage: cli [flags] <command> [arguments]
+
+Commands:
+  ingest <url>...    Fetch, analyze and store articles
+  chat "<query>"     Ask a question about the stored articles
+  list               List stored articles (-limit, -offset, -sort, -asc)
+  export             Write the corpus as JSON Lines or CSV (-format, -o, -embeddings, -topic, -topic-id, -tag, -collection, -source, -after, -before)
+
+Flags:
+`
+
+// Run executes the command line args (without the program name) and returns the exit code.
+// newOffline builds the backend for -offline; nil disables offline mode.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer, newOffline func(ctx context.Context) (Backend, io.Closer, error)) int {
+	fs := flag.NewFlagSet("cli", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	server := fs.String("server", "", "server URL (default $ARTICLE_ASSISTANT_URL or "+DefaultServerURL+")")
+	apiKey := fs.String("api-key", "", "API key for servers with API_KEYS (default $ARTICLE_ASSISTANT_API_KEY)")
+	offline := fs.Bool("offline", false, "use the configured database and LLM directly instead of a server")
+	asJSON := fs.Bool("json", false, "print raw JSON responses")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var backend Backend
+	if *offline {
+		if newOffline == nil {
+			fmt.Fprintln(stderr, "offline mode is not available")
+			return 2
+		}
+		b, closer, err := newOffline(ctx)
+		if err != nil {
+			fmt.Fprintf(stderr, "Failed to start offline mode: %v\n", err)
+			return 1
+		}
+		defer closer.Close()
+		backend = b
+	} else {
+		url := *server
+		if url == "" {
+			url = os.Getenv("ARTICLE_ASSISTANT_URL")
+		}
+		if url == "" {
+			url = DefaultServerURL
+		}
+		client := api.NewClient(url)
+		client.APIKey = *apiKey
+		if client.APIKey == "" {
+			client.APIKey = os.Getenv("ARTICLE_ASSISTANT_API_KEY")
+		}
+		backend = NewRemote(client)
+	}
+
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	var err error
+	switch cmd {
+	case "ingest":
+		err = runIngest(ctx, backend, cmdArgs, stdout)
+	case "chat":
+		err = runChat(ctx, backend, cmdArgs, stdout, *asJSON)
+	case "list":
+		err = runList(ctx, backend, cmdArgs, stdout, stderr, *asJSON)
+	case "export":
+		err = runExport(ctx, backend, cmdArgs, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "Unknown command %q\n\n", cmd)
+		fs.Usage()
+		return 2
+	}
+
+	var usageErr usageError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &usageErr):
+		fmt.Fprintln(stderr, err)
+		return 2
+	default:
+		fmt.Fprintf(stderr, "%s failed: %v\n", cmd, err)
+		return 1
+	}
+}
+
+// usageError is a malformed command line; Run exits with 2
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func runIngest(ctx context.Context, backend Backend, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("ingest needs at least one URL")
+	}
+	failed := 0
+	for _, url := range args {
+		outcome, err := backend.Ingest(ctx, url)
+		if err != nil {
+			fmt.Fprintf(stdout, "✗ %s: %v\n", url, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "✓ %s: %s\n", url, outcome)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d URLs failed", failed, len(args))
+	}
+	return nil
+}
+
+func runChat(ctx context.Context, backend Backend, args []string, stdout io.Writer, asJSON bool) error {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return usageError(`chat needs a query, e.g. chat "What is the sentiment of https://example.com/a?"`)
+	}
+	resp, err := backend.Chat(ctx, query)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return writeJSON(stdout, resp)
+	}
+
+	fmt.Fprintln(stdout, resp.Answer)
+	if len(resp.Sources) > 0 {
+		fmt.Fprintln(stdout, "\nSources:")
+		for _, s := range resp.Sources {
+			fmt.Fprintf(stdout, "  - %s (%s)\n", s.Title, s.URL)
+		}
+	}
+	return nil
+}
+
+func runList(ctx context.Context, backend Backend, args []string, stdout, stderr io.Writer, asJSON bool) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	limit := fs.Int("limit", 20, "page size, 1-100")
+	offset := fs.Int("offset", 0, "articles to skip")
+	sort := fs.String("sort", "created_at", "created_at or sentiment_score")
+	ascending := fs.Bool("asc", false, "oldest or lowest score first")
+	if err := fs.Parse(args); err != nil {
+		return usageError("invalid list flags")
+	}
+	if *limit < 1 || *limit > 100 || *offset < 0 {
+		return usageError("-limit must be between 1 and 100 and -offset non-negative")
+	}
+	if *sort != "created_at" && *sort != "sentiment_score" {
+		return usageError("-sort must be created_at or sentiment_score")
+	}
+
+	page, err := backend.List(ctx, *limit, *offset, *sort, *ascending)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return writeJSON(stdout, page)
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tINGESTED\tSENTIMENT\tTITLE\tURL")
+	for _, a := range page.Articles {
+		fmt.Fprintf(tw, "%s\t%s\t%s %.2f\t%s\t%s\n", a.ID, a.CreatedAt.Format("2006-01-02"), a.Sentiment, a.SentimentScore, a.Title, a.URL)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%d-%d of %d\n", min(page.Offset+1, page.Total), page.Offset+len(page.Articles), page.Total)
+	return nil
+}
+
+func runExport(ctx context.Context, backend Backend, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", export.FormatJSONL, "jsonl or csv")
+	output := fs.String("o", "", "output file (default stdout)")
+	embeddings := fs.Bool("embeddings", false, "include embeddings")
+	topic := fs.String("topic", "", "substring of an extracted topic")
+	topicID := fs.String("topic-id", "", "taxonomy topic, including subtopics")
+	tag := fs.String("tag", "", "user-defined tag")
+	collection := fs.String("collection", "", "collection ID or name")
+	source := fs.String("source", "", "source domain")
+	after := fs.String("after", "", "published at or after (date, RFC 3339 or relative such as last_week)")
+	before := fs.String("before", "", "published before")
+	if err := fs.Parse(args); err != nil {
+		return usageError("invalid export flags")
+	}
+	if *format != export.FormatJSONL && *format != export.FormatCSV {
+		return usageError("-format must be jsonl or csv")
+	}
+
+	filter := domain.ArticleFilter{
+		Topic:        strings.TrimSpace(*topic),
+		TopicID:      strings.TrimSpace(*topicID),
+		Tag:          domain.NormalizeTag(*tag),
+		Collection:   strings.TrimSpace(*collection),
+		SourceDomain: strings.TrimPrefix(strings.ToLower(strings.TrimSpace(*source)), "www."),
+	}
+	now := time.Now()
+	for _, d := range []struct {
+		flag, value string
+		dest        *time.Time
+	}{{"-after", *after, &filter.PublishedAfter}, {"-before", *before, &filter.PublishedBefore}} {
+		if d.value == "" {
+			continue
+		}
+		t, ok := executor.ParseDate(d.value, now)
+		if !ok {
+			return usageError(fmt.Sprintf("invalid %s %q", d.flag, d.value))
+		}
+		*d.dest = t
+	}
+
+	if *output == "" {
+		return backend.Export(ctx, stdout, *format, *embeddings, filter)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := backend.Export(ctx, f, *format, *embeddings, filter); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/processing"
+)
+
+// Remote runs commands through a server's HTTP API
+type Remote struct {
+	Client       *api.Client
+	PollInterval time.Duration // Between ingest status checks while an ingest runs in the background
+}
+
+// NewRemote returns a backend using client
+func NewRemote(client *api.Client) *Remote {
+	return &Remote{Client: client, PollInterval: 2 * time.Second}
+}
+
+var _ Backend = (*Remote)(nil)
+
+// Ingest waits for ingestion to finish, polling its status when the server answers 202
+func (r *Remote) Ingest(ctx context.Context, url string) (string, error) {
+	accepted, err := r.Client.Ingest(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	if accepted == nil {
+		return "ingested", nil
+	}
+
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("still processing (id %s): %w", accepted.ID, ctx.Err())
+		case <-ticker.C:
+		}
+		status, err := r.Client.IngestStatus(ctx, accepted.ID)
+		if err != nil {
+			return "", err
+		}
+		switch status.State {
+		case processing.StatusComplete:
+			return fmt.Sprintf("ingested after %d attempt(s)", status.Attempts), nil
+		case processing.StatusFailed:
+			if status.ErrorCategory != "" {
+				return "", fmt.Errorf("%s (%s)", status.Error, status.ErrorCategory)
+			}
+			return "", fmt.Errorf("%s", status.Error)
+		}
+	}
+}
+
+func (r *Remote) Chat(ctx context.Context, query string) (*domain.ChatResponse, error) {
+	return r.Client.Chat(ctx, domain.ChatRequest{Query: query})
+}
+
+func (r *Remote) List(ctx context.Context, limit, offset int, sort string, ascending bool) (*api.ArticlePage, error) {
+	return r.Client.ListArticles(ctx, limit, offset, sort, ascending)
+}
+
+func (r *Remote) Export(ctx context.Context, w io.Writer, format string, embeddings bool, filter domain.ArticleFilter) error {
+	return r.Client.Export(ctx, w, format, embeddings, filter)
+}
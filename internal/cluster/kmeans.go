@@ -0,0 +1,153 @@
+// Package cluster groups article embeddings with spherical k-means (cosine similarity).
+package cluster
+
+import (
+	"math"
+	"math/rand"
+)
+
+// DefaultK picks a cluster count for n items: sqrt(n/2), clamped to [2, 10]
+func DefaultK(n int) int {
+	k := int(math.Round(math.Sqrt(float64(n) / 2)))
+	if k < 2 {
+		k = 2
+	}
+	if k > 10 {
+		k = 10
+	}
+	if k > n {
+		k = n
+	}
+	return k
+}
+
+// KMeans assigns each vector to one of k clusters and returns the assignment per vector.
+// Vectors are L2-normalized so distance follows cosine similarity. Initialization uses
+// k-means++ with the given seed, so results are deterministic for the same input.
+func KMeans(vectors [][]float32, k, maxIter int, seed int64) []int {
+	n := len(vectors)
+	assign := make([]int, n)
+	if n == 0 || k <= 1 {
+		return assign
+	}
+	if k > n {
+		k = n
+	}
+
+	points := make([][]float64, n)
+	for i, v := range vectors {
+		points[i] = normalize(v)
+	}
+	centroids := initCentroids(points, k, rand.New(rand.NewSource(seed)))
+
+	for iter := 0; iter < maxIter; iter++ {
+		changed := iter == 0
+		for i, p := range points {
+			best, bestSim := 0, math.Inf(-1)
+			for c, centroid := range centroids {
+				if sim := dot(p, centroid); sim > bestSim {
+					best, bestSim = c, sim
+				}
+			}
+			if assign[i] != best {
+				assign[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		centroids = recompute(points, assign, centroids)
+	}
+	return assign
+}
+
+// initCentroids chooses k starting centroids with k-means++ seeding
+func initCentroids(points [][]float64, k int, rng *rand.Rand) [][]float64 {
+	centroids := [][]float64{points[rng.Intn(len(points))]}
+	dist := make([]float64, len(points))
+	for len(centroids) < k {
+		var total float64
+		for i, p := range points {
+			d := math.Inf(1)
+			for _, c := range centroids {
+				d = math.Min(d, 1-dot(p, c))
+			}
+			dist[i] = d * d
+			total += dist[i]
+		}
+		if total == 0 {
+			// All remaining points coincide with a centroid
+			centroids = append(centroids, points[len(centroids)%len(points)])
+			continue
+		}
+		r := rng.Float64() * total
+		next := len(points) - 1
+		for i, d := range dist {
+			if r -= d; r <= 0 {
+				next = i
+				break
+			}
+		}
+		centroids = append(centroids, points[next])
+	}
+	return centroids
+}
+
+// recompute sets each centroid to the normalized mean of its members; empty clusters keep
+// their previous centroid
+func recompute(points [][]float64, assign []int, prev [][]float64) [][]float64 {
+	dim := len(points[0])
+	sums := make([][]float64, len(prev))
+	counts := make([]int, len(prev))
+	for i := range sums {
+		sums[i] = make([]float64, dim)
+	}
+	for i, p := range points {
+		c := assign[i]
+		counts[c]++
+		for j, x := range p {
+			sums[c][j] += x
+		}
+	}
+	out := make([][]float64, len(prev))
+	for c := range sums {
+		if counts[c] == 0 {
+			out[c] = prev[c]
+			continue
+		}
+		out[c] = normalize64(sums[c])
+	}
+	return out
+}
+
+func normalize(v []float32) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = float64(x)
+	}
+	return normalize64(out)
+}
+
+func normalize64(v []float64) []float64 {
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return v
+	}
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
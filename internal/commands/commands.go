@@ -0,0 +1,214 @@
+// Package commands is the registry of chat commands: the name the planner chooses, what the
+// command does and the args it reads. The executor implements every entry, the planner prompt
+// and the create_plan tool list them, and GET /commands returns them.
+package commands
+
+// Command describes a chat command
+type Command struct {
+	Name        string `json:"name"`
+	Description string `json:"description"` // Shown to the planner; mentions the main args
+	Args        []Arg  `json:"args"`
+	Filters     bool   `json:"filters"`  // Also accepts the article metadata filters in FilterArgs
+	Partial     bool   `json:"partial"`  // Answers from the URLs found when others are missing or fail to ingest
+	Relative    bool   `json:"relative"` // Answers depend on the current time or the session's history, so are not cached
+}
+
+// Arg is a plan arg a command reads
+type Arg struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // JSON type: string, number, integer or array (of URLs)
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+// FilterArgs restrict the articles a command with Filters set works on
+var FilterArgs = []Arg{
+	{Name: "author", Type: "string", Description: "Article author"},
+	{Name: "section", Type: "string", Description: "Publication section or category"},
+	{Name: "source", Type: "string", Description: "Publisher domain, e.g. techcrunch.com"},
+	{Name: "topic_id", Type: "string", Description: "Taxonomy topic ID, including its subtopics"},
+	{Name: "tag", Type: "string", Description: "User-defined article tag"},
+	{Name: "collection", Type: "string", Description: "Name of a collection of articles"},
+	{Name: "published_after", Type: "string", Description: "today, yesterday, last_week, last_month, 3d or YYYY-MM-DD"},
+	{Name: "published_before", Type: "string", Description: "Same formats as published_after"},
+}
+
+// CommonArgs are accepted by every command
+var CommonArgs = []Arg{
+	{Name: "model", Type: "string", Description: "OpenAI model to answer with, e.g. gpt-4; must be an allowed model"},
+}
+
+// Shared args
+var (
+	urls          = Arg{Name: "urls", Type: "array", Required: true, Description: "Article URLs exactly as written in the query"}
+	optionalURLs  = Arg{Name: "urls", Type: "array", Description: "Article URLs to restrict to"}
+	topic         = Arg{Name: "filter", Type: "string", Required: true, Description: "Topic to search for"}
+	optionalTopic = Arg{Name: "filter", Type: "string", Description: "Topic to restrict to"}
+	since         = Arg{Name: "since", Type: "string", Description: `today, yesterday, last_week, 6h, 3d, a date, or "last_asked"`}
+	limit         = Arg{Name: "limit", Type: "integer", Description: "Number of articles to return"}
+)
+
+// Registry lists every command in the order the planner sees them. Adding an entry here, an
+// implementation in the executor and examples in the planner prompt templates is all a new
+// command needs.
+var Registry = []Command{
+	{
+		Name:        "summary",
+		Description: `Get summary of specific articles (requires URLs, optional language: the language to answer in, optional style: "bullets", "one_liner", "executive" or "eli5", optional bullets: number of bullet points, optional max_words)`,
+		Args: []Arg{
+			urls,
+			{Name: "language", Type: "string", Description: "Language to answer in, e.g. Spanish"},
+			{Name: "style", Type: "string", Description: "bullets, one_liner, executive or eli5"},
+			{Name: "bullets", Type: "integer", Description: "Number of bullet points in a bullets summary"},
+			{Name: "max_words", Type: "integer", Description: "Maximum summary length in words"},
+		},
+	},
+	{
+		Name:        "keywords_or_topics",
+		Description: "Extract keywords/topics from articles (requires URLs)",
+		Args:        []Arg{urls},
+	},
+	{
+		Name:        "get_sentiment",
+		Description: "Get sentiment of articles (requires URLs)",
+		Args:        []Arg{urls},
+		Partial:     true,
+	},
+	{
+		Name:        "compare_articles",
+		Description: "Compare two or more articles: shared themes, unique points and disagreements (requires URLs)",
+		Args:        []Arg{{Name: "urls", Type: "array", Required: true, Description: "URLs of two or more articles"}},
+		Partial:     true,
+	},
+	{
+		Name:        "ton_key_differences",
+		Description: "Analyze tone differences between articles (requires URLs)",
+		Args:        []Arg{{Name: "urls", Type: "array", Required: true, Description: "URLs of two or more articles"}},
+	},
+	{
+		Name:        "filter_by_specific_topic",
+		Description: "Find articles by topic/filter (uses filter argument; optional limit, and offset to page through more results)",
+		Args: []Arg{
+			topic,
+			limit,
+			{Name: "offset", Type: "integer", Description: "Number of ranked articles to skip, for the next page"},
+		},
+		Filters: true,
+	},
+	{
+		Name:        "most_positive_article_for_filter",
+		Description: "Find most positive article about a topic (uses filter argument)",
+		Args:        []Arg{topic},
+		Filters:     true,
+	},
+	{
+		Name:        "sentiment_filter",
+		Description: `Find the most negative/critical or most positive articles, or articles in a sentiment score range (optional filter: topic, direction: "positive" or "negative", min_score/max_score between 0.0 and 1.0, limit)`,
+		Args: []Arg{
+			optionalTopic,
+			{Name: "direction", Type: "string", Description: "positive or negative"},
+			{Name: "min_score", Type: "number", Description: "Lowest sentiment score, 0.0 to 1.0"},
+			{Name: "max_score", Type: "number", Description: "Highest sentiment score, 0.0 to 1.0"},
+			limit,
+			optionalURLs,
+		},
+		Filters: true,
+	},
+	{
+		Name:        "filter_by_tone",
+		Description: "List articles with a tone, e.g. critical, optimistic, analytical (tone argument; without it, count articles per tone)",
+		Args: []Arg{
+			{Name: "tone", Type: "string", Description: "Article tone; without it, articles are counted per tone"},
+			optionalURLs,
+		},
+		Filters: true,
+	},
+	{
+		Name:        "get_top_entities",
+		Description: "Get the most prominent entities across all articles, ranked by article count and confidence (optional category: person, organization, location, technology)",
+		Args: []Arg{
+			{Name: "category", Type: "string", Description: "person, organization, location, technology or other"},
+			optionalURLs,
+		},
+		Filters: true,
+	},
+	{
+		Name:        "compare_framing",
+		Description: "Contrast how different sources frame the same story (URLs of 2+ articles, or filter: the story topic)",
+		Args: []Arg{
+			{Name: "urls", Type: "array", Description: "URLs of two or more articles; otherwise articles are found by filter"},
+			{Name: "filter", Type: "string", Description: "Story topic"},
+		},
+		Filters: true,
+	},
+	{
+		Name:        "simplify",
+		Description: `Explain an article to a non-expert (requires URLs, optional level: "eli5", "high_school", "expert")`,
+		Args: []Arg{
+			urls,
+			{Name: "level", Type: "string", Description: "eli5, high_school or expert"},
+		},
+	},
+	{
+		Name:        "whats_new",
+		Description: `What's new on a topic since a point in time (uses filter, optional since: "today", "yesterday", "last_week", "6h", "3d", a date, or "last_asked")`,
+		Args:        []Arg{topic, since},
+		Filters:     true,
+		Relative:    true,
+	},
+	{
+		Name:        "cluster_articles",
+		Description: "Group all stored articles into labeled topic clusters for an overview (optional k: number of groups)",
+		Args:        []Arg{{Name: "k", Type: "integer", Description: "Number of groups"}},
+		Filters:     true,
+	},
+	{
+		Name:        "sentiment_trend",
+		Description: `How sentiment changed over time (optional filter: topic, optional URLs, optional since like whats_new, optional interval: "day" or "week")`,
+		Args: []Arg{
+			optionalTopic,
+			optionalURLs,
+			since,
+			{Name: "interval", Type: "string", Description: "day or week"},
+		},
+		Filters:  true,
+		Relative: true,
+	},
+	{
+		Name:        "entity_graph",
+		Description: "Which entities are mentioned together, as a co-occurrence graph (optional URLs; otherwise all articles)",
+		Args:        []Arg{optionalURLs},
+		Filters:     true,
+	},
+	{
+		Name:        "digest",
+		Description: "Write a news digest (intro, themed highlights and a one-liner per article with links) of articles on a topic and/or in a date range (optional filter: topic, published_after/published_before, source)",
+		Args:        []Arg{optionalTopic, optionalURLs},
+		Filters:     true,
+	},
+	{
+		Name:        "ask",
+		Description: "Answer an open-ended factual question from article content, e.g. what someone said, why something happened, details of an event (optional URLs to restrict the articles)",
+		Args:        []Arg{optionalURLs, {Name: "filter", Type: "string", Description: "Question to answer when the query itself is empty"}},
+		Filters:     true,
+	},
+}
+
+// Names lists the registered command names in registry order
+func Names() []string {
+	names := make([]string, len(Registry))
+	for i, c := range Registry {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Lookup returns the registered command called name
+func Lookup(name string) (Command, bool) {
+	for _, c := range Registry {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
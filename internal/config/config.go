@@ -0,0 +1,62 @@
+// Package config reads server settings from environment variables and an optional YAML
+// file, validates them, and reloads the reloadable ones on SIGHUP.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the config file path
+const FileEnv = "CONFIG_FILE"
+
+// Config looks up settings by environment variable name. Environment variables override
+// values from the file.
+type Config struct {
+	Path   string            // Config file, empty when settings come from the environment only
+	values map[string]string // File values keyed by upper-case variable name
+}
+
+// New loads the file named by CONFIG_FILE, if set
+func New() (*Config, error) {
+	return Load(os.Getenv(FileEnv))
+}
+
+// Load reads a YAML file of top-level settings keyed by environment variable name, e.g.
+// "OPENAI_MODEL: gpt-4" (keys are case-insensitive). An empty path yields an empty config.
+func Load(path string) (*Config, error) {
+	c := &Config{Path: path, values: map[string]string{}}
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	for k, v := range raw {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("config file %s: %s must be a single value", path, k)
+		case nil:
+			continue
+		}
+		c.values[strings.ToUpper(k)] = fmt.Sprint(v)
+	}
+	return c, nil
+}
+
+// Get returns the environment variable key if set, else the file value, else ""
+func (c *Config) Get(key string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return c.values[key]
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+)
+
+// Reloadable are the settings applied again on SIGHUP; changes to others need a restart
+var Reloadable = map[string]bool{
+	"LOG_LEVEL":            true,
+	"HYBRID_VECTOR_WEIGHT": true,
+}
+
+// ChangedKeys returns the file settings that differ between c and next, sorted
+func (c *Config) ChangedKeys(next *Config) []string {
+	var changed []string
+	for k, v := range next.values {
+		if c.values[k] != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range c.values {
+		if _, ok := next.values[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// WatchReload re-reads the config file on SIGHUP until ctx is done. A valid file is passed
+// to apply; an invalid one is logged and the running settings are kept.
+func WatchReload(ctx context.Context, current *Config, apply func(*Config)) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Stopping config reload watcher")
+				return
+			case <-sig:
+			}
+
+			next, err := Load(current.Path)
+			if err == nil {
+				err = next.Validate()
+			}
+			if err != nil {
+				log.Printf("⚠️  Config reload failed, keeping current settings: %v", err)
+				continue
+			}
+			for _, key := range current.ChangedKeys(next) {
+				if !Reloadable[key] {
+					log.Printf("⚠️  %s changed; restart to apply it", key)
+				}
+			}
+			apply(next)
+			current = next
+			log.Printf("🔄 Config reloaded from %s", current.Path)
+		}
+	}()
+}
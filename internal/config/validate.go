@@ -0,0 +1,207 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// check validates one setting's value
+type check func(value string) error
+
+// rules are the value checks for known settings; unset settings are not checked
+var rules = map[string]check{
+	"LOG_FORMAT":                 oneOf("json", "text"),
+	"LOG_LEVEL":                  oneOf("debug", "info", "warn", "warning", "error"),
+	"LLM_PROVIDER":               oneOf("openai", "anthropic", "gemini", "scenario"),
+	"DATABASE_DRIVER":            oneOf("postgres", "sqlite", "memory"),
+	"DB_MAX_OPEN_CONNS":          intAtLeast(0),
+	"DB_MAX_IDLE_CONNS":          intAtLeast(0),
+	"DB_CONN_MAX_LIFETIME":       durationAtLeast(0),
+	"DB_CONN_MAX_IDLE_TIME":      durationAtLeast(0),
+	"DB_PREPARE_STATEMENTS":      boolean,
+	"VECTOR_STORE":               oneOf("database", "weaviate"),
+	"VECTOR_INDEX":               oneOf("ivfflat", "hnsw"),
+	"IVFFLAT_LISTS":              intAtLeast(0),
+	"HNSW_M":                     intAtLeast(2),
+	"HNSW_EF_CONSTRUCTION":       intAtLeast(4),
+	"CACHE_BACKEND":              oneOf("postgres", "redis"),
+	"CHAT_CACHE_TTL":             durationAtLeast(0),
+	"CHAT_TIME_BUDGET":           durationAtLeast(time.Second),
+	"CHAOS_ENABLED":              boolean,
+	"AUTO_INGEST":                boolean,
+	"AUTO_INGEST_WAIT":           durationAtLeast(time.Second),
+	"REFRESH_MAX_AGE":            durationAtLeast(time.Minute),
+	"REFRESH_INTERVAL":           durationAtLeast(time.Minute),
+	"HYBRID_VECTOR_WEIGHT":       floatBetween(0, 1),
+	"HYBRID_MIN_SIMILARITY":      floatBetween(0, 1),
+	"DEDUP_SIMILARITY_THRESHOLD": floatBetween(0, 1),
+	"PLANNER_MIN_CONFIDENCE":     floatBetween(0, 1),
+	"PLANNER_FAST_PATH":          boolean,
+	"GROUNDING_CHECK":            oneOf("off", "embedding", "llm"),
+	"GROUNDING_MIN_SCORE":        floatBetween(0, 1),
+	"OPENAI_EMBEDDING_MODEL":     oneOf("text-embedding-3-small", "text-embedding-3-large", "text-embedding-ada-002"),
+	"LLM_RETRY_MAX_ATTEMPTS":     intAtLeast(1),
+	"LLM_BREAKER_THRESHOLD":      intAtLeast(0),
+	"LLM_BREAKER_COOLDOWN":       durationAtLeast(0),
+	"LLM_MAX_CONCURRENCY":        intAtLeast(0),
+	"LLM_QUEUE_TIMEOUT":          durationAtLeast(0),
+	"LLM_ALERT_ERROR_RATE":       floatBetween(0, 1),
+	"LLM_ALERT_WINDOW":           durationAtLeast(time.Second),
+	"LLM_ALERT_MIN_CALLS":        intAtLeast(1),
+	"LLM_AUDIT":                  oneOf("postgres", "file"),
+	"LLM_AUDIT_RESPONSE_CHARS":   intAtLeast(1),
+	"SHUTDOWN_TIMEOUT":           durationAtLeast(time.Second),
+	"INGEST_CONCURRENCY":         intAtLeast(1),
+	"INGEST_QUEUE_SIZE":          intAtLeast(0),
+	"INGEST_URL_TIMEOUT":         durationAtLeast(time.Second),
+	"ENRICH_CONCURRENCY":         intAtLeast(1),
+	"ENRICH_QUEUE_SIZE":          intAtLeast(0),
+	"ALERT_INTERVAL":             durationAtLeast(time.Second),
+	"FETCH_MAX_PER_HOST":         intAtLeast(1),
+	"FETCH_CRAWL_DELAY":          durationAtLeast(0),
+	"FETCH_ROBOTS_TTL":           durationAtLeast(time.Minute),
+	"FETCH_TIMEOUT":              durationAtLeast(time.Second),
+	"FETCH_MAX_REDIRECTS":        intAtLeast(1),
+	"FETCH_MAX_ATTEMPTS":         intAtLeast(1),
+	"FETCH_MAX_BYTES":            intAtLeast(1024),
+	"FETCH_ALLOW_PRIVATE":        boolean,
+	"FETCH_FALLBACKS":            listOf("amp", "google_cache", "archive"),
+	"FETCH_MIN_TEXT_LENGTH":      intAtLeast(1),
+	"ENTITY_LLM_CANONICALIZE":    boolean,
+	"TENANT_RATE_LIMIT":          intAtLeast(0),
+	"INGEST_CONSUMER":            oneOf("nats", "kafka"),
+	"CONTENT_SANITIZE":           oneOf("off", "standard", "strict"),
+	"CONTENT_MODERATION":         oneOf("openai"),
+	"URL_ALLOWLIST":              urlPatterns,
+	"URL_DENYLIST":               urlPatterns,
+	"BOT_TIMEOUT":                durationAtLeast(time.Second),
+}
+
+// providerKeys are the settings each LLM provider requires: its API key, or the fixture for scenario
+var providerKeys = map[string]string{
+	"openai":    "OPENAI_API_KEY",
+	"anthropic": "ANTHROPIC_API_KEY",
+	"gemini":    "GEMINI_API_KEY",
+	"scenario":  "LLM_SCENARIO",
+}
+
+// consumerKeys are the settings required by each ingest consumer
+var consumerKeys = map[string][]string{
+	"nats":  {"NATS_SUBJECT"},
+	"kafka": {"KAFKA_REST_URL", "KAFKA_TOPIC"},
+}
+
+// Validate checks every set value against its rule and that the selected LLM provider's
+// API key and the ingest consumer's settings are present. All problems are reported together.
+func (c *Config) Validate() error {
+	var errs []error
+	for key, chk := range rules {
+		if v := c.Get(key); v != "" {
+			if err := chk(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s=%q: %w", key, v, err))
+			}
+		}
+	}
+
+	provider := c.Get("LLM_PROVIDER")
+	if provider == "" {
+		provider = "openai"
+	}
+	if key, ok := providerKeys[provider]; ok && c.Get(key) == "" {
+		errs = append(errs, fmt.Errorf("%s is required for the %s provider", key, provider))
+	}
+	if strings.EqualFold(c.Get("CONTENT_MODERATION"), "openai") && c.Get("OPENAI_API_KEY") == "" {
+		errs = append(errs, fmt.Errorf("OPENAI_API_KEY is required for CONTENT_MODERATION=openai"))
+	}
+	consumer := strings.ToLower(c.Get("INGEST_CONSUMER"))
+	for _, key := range consumerKeys[consumer] {
+		if c.Get(key) == "" {
+			errs = append(errs, fmt.Errorf("%s is required for the %s ingest consumer", key, consumer))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func oneOf(allowed ...string) check {
+	return func(v string) error {
+		for _, a := range allowed {
+			if strings.EqualFold(v, a) {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+// listOf accepts a comma-separated list of allowed values
+func listOf(allowed ...string) check {
+	one := oneOf(allowed...)
+	return func(v string) error {
+		for _, item := range strings.Split(v, ",") {
+			if err := one(strings.TrimSpace(item)); err != nil {
+				return fmt.Errorf("each item %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// urlPatterns accepts comma-separated hosts, host wildcards and re: regular expressions, as
+// ingest.ParseURLPolicy reads them
+func urlPatterns(v string) error {
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if expr, ok := strings.CutPrefix(item, "re:"); ok {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("has an invalid regular expression %q: %w", expr, err)
+			}
+		} else if strings.ContainsAny(item, "/: ") {
+			return fmt.Errorf("has %q, which is not a host such as example.com", item)
+		}
+	}
+	return nil
+}
+
+func boolean(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return fmt.Errorf("must be true or false")
+	}
+	return nil
+}
+
+func durationAtLeast(min time.Duration) check {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 30s or 1h")
+		}
+		if d < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+		return nil
+	}
+}
+
+func floatBetween(min, max float64) check {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < min || f > max {
+			return fmt.Errorf("must be a number from %g to %g", min, max)
+		}
+		return nil
+	}
+}
+
+func intAtLeast(min int) check {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min {
+			return fmt.Errorf("must be an integer of at least %d", min)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,199 @@
+// Package consumer feeds URLs and article payloads published on a message broker into
+// ingestion, so existing crawling infrastructure can hand its output to the assistant. NATS
+// subjects are read over the NATS client protocol and Kafka topics through a Kafka REST Proxy;
+// neither needs a client library.
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/processing"
+)
+
+// Source delivers broker messages. Run calls handle for each message until ctx is done or the
+// connection fails; an error from handle stops Run before the message is acknowledged, so
+// brokers that redeliver will offer it again.
+type Source interface {
+	Run(ctx context.Context, handle func(ctx context.Context, data []byte) error) error
+	// Name labels the source in logs and metrics, e.g. "nats:crawler.urls"
+	Name() string
+}
+
+// Queue accepts URLs for background ingestion, waiting for room when it is full
+type Queue interface {
+	Enqueue(ctx context.Context, url string) (processing.Status, error)
+}
+
+// Importer stores already-extracted articles
+type Importer interface {
+	Import(ctx context.Context, in ingest.ImportedArticle) (*domain.Article, error)
+}
+
+var (
+	_ Queue    = (*processing.Facade)(nil)
+	_ Importer = (*ingest.Service)(nil)
+)
+
+// Message outcomes counted per source
+const (
+	ResultQueued   = "queued"   // URL handed to the ingestion queue
+	ResultImported = "imported" // Article payload stored
+	ResultInvalid  = "invalid"  // Not a URL or article payload; dropped
+	ResultFailed   = "failed"   // Import failed; dropped
+)
+
+// Reconnect backoff after a source fails
+const (
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// Consumer reads messages from Source and ingests them. A message is either a bare http(s)
+// URL, a JSON object with a "url", or a JSON article payload as accepted by POST /import
+// ("url", "title", "text" and optional metadata), which is stored without fetching.
+type Consumer struct {
+	Source   Source
+	Queue    Queue
+	Importer Importer
+
+	// OnImport, if set, runs after an article payload is stored, e.g. to invalidate caches
+	OnImport func(ctx context.Context)
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// New creates a consumer of source feeding queue and importer
+func New(source Source, queue Queue, importer Importer) *Consumer {
+	return &Consumer{Source: source, Queue: queue, Importer: importer}
+}
+
+// Run consumes until ctx is cancelled, reconnecting with exponential backoff when the source fails
+func (c *Consumer) Run(ctx context.Context) {
+	log.Printf("📨 Consuming ingest messages from %s", c.Source.Name())
+	backoff := minBackoff
+	for ctx.Err() == nil {
+		started := time.Now()
+		err := c.Source.Run(ctx, c.Handle)
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+		log.Printf("⚠️  Ingest consumer %s stopped: %v; reconnecting in %v", c.Source.Name(), err, backoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+	log.Printf("📨 Ingest consumer %s stopped", c.Source.Name())
+}
+
+// Handle ingests one message. Malformed messages and failed imports are logged and dropped;
+// the only error is a URL the queue did not accept, e.g. at shutdown.
+func (c *Consumer) Handle(ctx context.Context, data []byte) error {
+	logger := logging.FromContext(ctx).With("source", c.Source.Name())
+	msg, err := parse(data)
+	if err != nil {
+		c.count(ResultInvalid)
+		logger.Warn("dropping ingest message", "error", err)
+		return nil
+	}
+
+	if msg.Text == "" {
+		if _, err := c.Queue.Enqueue(ctx, msg.URL); err != nil {
+			return fmt.Errorf("failed to queue %s: %w", msg.URL, err)
+		}
+		c.count(ResultQueued)
+		return nil
+	}
+
+	if _, err := c.Importer.Import(ctx, msg); err != nil {
+		c.count(ResultFailed)
+		logger.Warn("failed to import article from message", "url", msg.URL, "error", err)
+		return nil
+	}
+	c.count(ResultImported)
+	if c.OnImport != nil {
+		c.OnImport(ctx)
+	}
+	return nil
+}
+
+// parse reads a bare URL or a JSON object with a URL and, for article payloads, the text
+func parse(data []byte) (ingest.ImportedArticle, error) {
+	var msg ingest.ImportedArticle
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return msg, fmt.Errorf("invalid JSON payload: %w", err)
+		}
+	} else {
+		msg.URL = string(data)
+	}
+	msg.URL, msg.Text = strings.TrimSpace(msg.URL), strings.TrimSpace(msg.Text)
+
+	u, err := neturl.Parse(msg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return msg, fmt.Errorf("%q is not an http(s) URL", truncate(msg.URL, 200))
+	}
+	if msg.Text != "" && strings.TrimSpace(msg.Title) == "" {
+		return msg, errors.New("article payloads need a title")
+	}
+	return msg, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+func (c *Consumer) count(result string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int64)
+	}
+	c.counts[result]++
+}
+
+// WritePrometheus writes the message counters of consumers, labelled by source and result, in
+// the Prometheus text format
+func WritePrometheus(w io.Writer, consumers ...*Consumer) error {
+	if _, err := io.WriteString(w, `# HELP ingest_consumer_messages_total Broker messages by outcome.
+# TYPE ingest_consumer_messages_total counter
+`); err != nil {
+		return err
+	}
+	for _, c := range consumers {
+		c.mu.Lock()
+		counts := make(map[string]int64, len(c.counts))
+		for k, v := range c.counts {
+			counts[k] = v
+		}
+		c.mu.Unlock()
+		for _, result := range []string{ResultQueued, ResultImported, ResultInvalid, ResultFailed} {
+			if _, err := fmt.Fprintf(w, "ingest_consumer_messages_total{source=%q,result=%q} %d\n", c.Source.Name(), result, counts[result]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
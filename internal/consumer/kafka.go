@@ -0,0 +1,147 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// kafkaContentType is the Kafka REST Proxy v2 media type for requests without records
+const kafkaContentType = "application/vnd.kafka.v2+json"
+
+// KafkaSource consumes a Kafka topic through a Kafka REST Proxy (v2 API) as a member of a
+// consumer group. Offsets are committed after every record of a batch was handled, so records
+// in a batch cut short by shutdown or an error are delivered again.
+type KafkaSource struct {
+	URL   string // REST Proxy base URL, e.g. http://kafka-rest:8082
+	Topic string
+	Group string // Consumer group shared by replicas, so each record is ingested once
+
+	Client      *http.Client  // Default client with a 60s timeout when nil
+	PollTimeout time.Duration // How long one poll waits for records; default 5s
+}
+
+// Name labels the source as kafka:<topic>
+func (s *KafkaSource) Name() string { return "kafka:" + s.Topic }
+
+type kafkaRecord struct {
+	Topic     string `json:"topic"`
+	Value     string `json:"value"` // Base64, as the binary embedded format returns it
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// Run creates a consumer instance, subscribes to the topic and passes each record value to
+// handle until ctx is done, a request fails or handle returns an error. The instance is
+// deleted on return so the group rebalances right away.
+func (s *KafkaSource) Run(ctx context.Context, handle func(ctx context.Context, data []byte) error) error {
+	var created struct {
+		InstanceID string `json:"instance_id"`
+		BaseURI    string `json:"base_uri"`
+	}
+	err := s.call(ctx, http.MethodPost, strings.TrimRight(s.URL, "/")+"/consumers/"+s.Group, map[string]string{
+		"name":               "article-assistant-" + uuid.New().String()[:8],
+		"format":             "binary",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}, &created)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+	instance := created.BaseURI
+	defer func() {
+		// The request context may be done; removal gets its own short deadline
+		cleanup, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.call(cleanup, http.MethodDelete, instance, nil, nil)
+	}()
+
+	if err := s.call(ctx, http.MethodPost, instance+"/subscription", map[string][]string{"topics": {s.Topic}}, nil); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", s.Topic, err)
+	}
+
+	poll := s.PollTimeout
+	if poll <= 0 {
+		poll = 5 * time.Second
+	}
+	for ctx.Err() == nil {
+		var records []kafkaRecord
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/records?timeout=%d", instance, poll.Milliseconds()), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/vnd.kafka.binary.v2+json")
+		if err := s.do(req, &records); err != nil {
+			return fmt.Errorf("failed to poll %s: %w", s.Topic, err)
+		}
+		if len(records) == 0 {
+			continue
+		}
+
+		for _, rec := range records {
+			data, err := base64.StdEncoding.DecodeString(rec.Value)
+			if err != nil {
+				data = nil // Handled, and counted, as an invalid message
+			}
+			if err := handle(ctx, data); err != nil {
+				return err
+			}
+		}
+
+		// An empty body commits every record fetched by this instance
+		if err := s.call(ctx, http.MethodPost, instance+"/offsets", nil, nil); err != nil {
+			return fmt.Errorf("failed to commit offsets: %w", err)
+		}
+	}
+	return ctx.Err()
+}
+
+// call sends a JSON request to the REST Proxy and decodes the JSON response into out, if set
+func (s *KafkaSource) call(ctx context.Context, method, url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", kafkaContentType)
+	return s.do(req, out)
+}
+
+func (s *KafkaSource) do(req *http.Request, out interface{}) error {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&e)
+		return fmt.Errorf("kafka rest proxy returned %d: %s", resp.StatusCode, e.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
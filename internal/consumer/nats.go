@@ -0,0 +1,168 @@
+package consumer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultNATSURL is the NATS server used when NATS_URL is not set
+const DefaultNATSURL = "nats://localhost:4222"
+
+// natsMaxPayload bounds the messages accepted from the server
+const natsMaxPayload = 8 << 20
+
+// NATSSource subscribes to a NATS subject over the plain-text client protocol. Core NATS
+// delivers at most once: messages published while the consumer is down, or dropped at
+// shutdown, are not redelivered.
+type NATSSource struct {
+	URL     string // nats://[user:password@ or token@]host[:port]; TLS is not supported
+	Subject string // May contain wildcards, e.g. "crawler.urls.>"
+	Queue   string // Queue group shared by replicas, so each message is ingested once; empty for none
+
+	DialTimeout time.Duration // Default 10s
+}
+
+// Name labels the source as nats:<subject>
+func (s *NATSSource) Name() string { return "nats:" + s.Subject }
+
+// Run connects, subscribes and passes each message payload to handle until ctx is done, the
+// connection fails or handle returns an error
+func (s *NATSSource) Run(ctx context.Context, handle func(ctx context.Context, data []byte) error) error {
+	u, err := neturl.Parse(s.URL)
+	if err != nil || u.Scheme != "nats" || u.Host == "" {
+		return fmt.Errorf("invalid NATS URL %q; use nats://host:port", s.URL)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	timeout := s.DialTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(timeout))
+	line, err := readLine(r)
+	if err != nil {
+		return fmt.Errorf("failed to read NATS greeting: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting %q", line)
+	}
+
+	// PING after CONNECT makes the server answer PONG once it accepted the credentials
+	connect, err := json.Marshal(natsConnect(u))
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		return err
+	}
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return fmt.Errorf("NATS handshake failed: %w", err)
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			return fmt.Errorf("NATS refused the connection: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		if line == "PONG" {
+			break
+		}
+	}
+	sub := "SUB " + s.Subject
+	if s.Queue != "" {
+		sub += " " + s.Queue
+	}
+	if _, err := fmt.Fprintf(conn, "%s 1\r\n", sub); err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Time{})
+
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("NATS connection lost: %w", err)
+		}
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			payload, err := readPayload(r, line)
+			if err != nil {
+				return err
+			}
+			if err := handle(ctx, payload); err != nil {
+				return err
+			}
+		case line == "PING":
+			if _, err := io.WriteString(conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// natsConnect builds the CONNECT options, with the credentials in u
+func natsConnect(u *neturl.URL) map[string]interface{} {
+	opts := map[string]interface{}{"verbose": false, "pedantic": false, "name": "article-assistant", "lang": "go", "protocol": 0}
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			opts["user"], opts["pass"] = u.User.Username(), password
+		} else {
+			opts["auth_token"] = u.User.Username()
+		}
+	}
+	return opts
+}
+
+// readLine reads a CRLF-terminated protocol line without its terminator
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPayload reads the payload announced by a "MSG <subject> <sid> [reply-to] <#bytes>" line
+func readPayload(r *bufio.Reader, line string) ([]byte, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields) > 5 {
+		return nil, fmt.Errorf("malformed NATS message line %q", line)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 || size > natsMaxPayload {
+		return nil, fmt.Errorf("malformed NATS message size in %q", line)
+	}
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("failed to read NATS message: %w", err)
+	}
+	if string(payload[size:]) != "\r\n" {
+		return nil, errors.New("NATS message payload is not terminated by CRLF")
+	}
+	return payload[:size], nil
+}
@@ -1,12 +1,51 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // SemanticEntity represents an extracted entity with metadata
 type SemanticEntity struct {
-	Name       string  `json:"name"`
-	Category   string  `json:"category"` // person, organization, location, technology, etc.
-	Confidence float64 `json:"confidence"`
+	Name          string  `json:"name"`
+	CanonicalName string  `json:"canonical_name,omitempty"` // Normalized name that aliases share, set at ingest
+	Category      string  `json:"category"`                 // person, organization, location, technology, etc.
+	Confidence    float64 `json:"confidence"`
+}
+
+// EntityCategories are the entity categories the semantic extraction assigns
+var EntityCategories = []string{"person", "organization", "location", "technology", "other"}
+
+// entityCategoryAliases map plural and informal category names to EntityCategories
+var entityCategoryAliases = map[string]string{
+	"people": "person", "persons": "person", "organisation": "organization", "organizations": "organization",
+	"organisations": "organization", "company": "organization", "companies": "organization",
+	"locations": "location", "place": "location", "places": "location", "tech": "technology",
+	"technologies": "technology",
+}
+
+// NormalizeEntityCategory returns the EntityCategories entry category names, in any case or
+// as a plural, and false when it names none
+func NormalizeEntityCategory(category string) (string, bool) {
+	c := strings.ToLower(strings.TrimSpace(category))
+	if alias, ok := entityCategoryAliases[c]; ok {
+		c = alias
+	}
+	for _, known := range EntityCategories {
+		if c == known {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// TopEntity is an entity ranked across articles by Score
+type TopEntity struct {
+	Name          string  `json:"name"`
+	Category      string  `json:"category"`       // Most common category of its mentions; "other" when none was extracted
+	Count         int     `json:"count"`          // Articles mentioning it
+	AvgConfidence float64 `json:"avg_confidence"` // Mean extraction confidence of its mentions
+	Score         float64 `json:"score"`          // Count × AvgConfidence
 }
 
 // SemanticKeyword represents an extracted keyword with metadata
@@ -23,6 +62,13 @@ type SemanticTopic struct {
 	Description string  `json:"description"`
 }
 
+// TermAggregate ranks a keyword or topic across articles
+type TermAggregate struct {
+	Term      string  `json:"term"`
+	Relevance float64 `json:"relevance"` // Sum of the per-article relevance (keywords) or score (topics)
+	Articles  int     `json:"articles"`  // Number of articles it was extracted from
+}
+
 // SemanticAnalysis contains all semantic data extracted in one call
 type SemanticAnalysis struct {
 	Entities       []SemanticEntity  `json:"entities"`
@@ -34,20 +80,98 @@ type SemanticAnalysis struct {
 }
 
 type Article struct {
-	ID             string            `json:"id"`
-	URL            string            `json:"url"`
-	Title          string            `json:"title"`
-	Summary        string            `json:"summary"`
-	Embedding      []float32         `json:"embedding"`
-	Sentiment      string            `json:"sentiment"`
-	SentimentScore float64           `json:"sentiment_score"`
-	Tone           string            `json:"tone"`
-	Entities       []SemanticEntity  `json:"entities"`
-	Keywords       []SemanticKeyword `json:"keywords"`
-	Topics         []SemanticTopic   `json:"topics"`
-	URLHash        string            `json:"url_hash"` // SHA-256 hash of the URL for caching
-	CreatedAt      time.Time         `json:"created_at"`
-	UpdatedAt      time.Time         `json:"updated_at"`
+	ID              string            `json:"id"`
+	URL             string            `json:"url"`
+	Title           string            `json:"title"`
+	Summary         string            `json:"summary"`
+	Content         string            `json:"content,omitempty"` // Full extracted article text
+	Embedding       []float32         `json:"embedding"`
+	EmbeddingModel  string            `json:"embedding_model,omitempty"`  // Model that produced Embedding; empty for articles stored before it was recorded
+	SummaryVersion  string            `json:"summary_version,omitempty"`  // Summarization prompt version that produced Summary; see AnalysisSupplied
+	SummaryModel    string            `json:"summary_model,omitempty"`    // Model that produced Summary; empty when supplied or not recorded
+	AnalysisVersion string            `json:"analysis_version,omitempty"` // Semantic extraction that produced the entities, keywords, topics, sentiment and tone; see AnalysisSupplied
+	AnalysisModel   string            `json:"analysis_model,omitempty"`   // Model that ran the semantic extraction; empty when supplied or not recorded
+	Sentiment       string            `json:"sentiment"`
+	SentimentScore  float64           `json:"sentiment_score"`
+	Tone            string            `json:"tone"`
+	Entities        []SemanticEntity  `json:"entities"`
+	Keywords        []SemanticKeyword `json:"keywords"`
+	Topics          []SemanticTopic   `json:"topics"`
+	TopicIDs        []string          `json:"topic_ids,omitempty"` // Taxonomy topics the extracted topics map to
+	URLHash         string            `json:"url_hash"`            // SHA-256 hash of the URL for caching
+	Author          string            `json:"author,omitempty"`
+	Section         string            `json:"section,omitempty"`
+	PublishedAt     *time.Time        `json:"published_at,omitempty"`
+	SourceDomain    string            `json:"source_domain,omitempty"` // Publisher host without "www.", e.g. "techcrunch.com"
+	Language        string            `json:"language,omitempty"`      // Detected ISO 639-1 code, e.g. "en"
+	CanonicalID     string            `json:"canonical_id,omitempty"`  // Set on near-duplicates: ID of the stored article they duplicate
+	ContentHash     string            `json:"content_hash,omitempty"`  // SHA-256 of Content; detects changes on refresh
+	ETag            string            `json:"-"`                       // ETag of the last fetch
+	LastRefreshedAt *time.Time        `json:"last_refreshed_at,omitempty"`
+	Imported        bool              `json:"imported,omitempty"`     // Loaded through /import rather than fetched; never refreshed
+	Status          string            `json:"status"`                 // ArticlePending, ArticleEnriched or ArticleFailed
+	StatusError     string            `json:"status_error,omitempty"` // Why enrichment failed
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	Score           float64           `json:"score,omitempty"` // Relevance from the search that returned it
+}
+
+// Article ingest states; only enriched articles have a summary, embedding and semantics
+const (
+	ArticlePending  = "pending"  // Fetched and stored, waiting for LLM enrichment
+	ArticleEnriched = "enriched" // Summarized, embedded and analyzed
+	ArticleFailed   = "failed"   // Enrichment failed after all retries; StatusError says why
+)
+
+// AnalysisSupplied is the AnalysisVersion, or SummaryVersion, of imported articles whose
+// analysis, or summary, came with them; re-analysis leaves those alone. Articles whose
+// extraction failed have no version.
+const AnalysisSupplied = "supplied"
+
+// ValidArticleStatus reports whether status is one of the article ingest states
+func ValidArticleStatus(status string) bool {
+	return status == ArticlePending || status == ArticleEnriched || status == ArticleFailed
+}
+
+// ArticleListItem is lightweight article metadata for listings (no content or embedding)
+type ArticleListItem struct {
+	ID              string     `json:"id"`
+	URL             string     `json:"url"`
+	Title           string     `json:"title"`
+	Sentiment       string     `json:"sentiment"`
+	SentimentScore  float64    `json:"sentiment_score"`
+	Author          string     `json:"author,omitempty"`
+	Section         string     `json:"section,omitempty"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	Status          string     `json:"status"`
+	StatusError     string     `json:"status_error,omitempty"`
+	SummaryVersion  string     `json:"summary_version,omitempty"`
+	SummaryModel    string     `json:"summary_model,omitempty"`
+	AnalysisVersion string     `json:"analysis_version,omitempty"`
+	AnalysisModel   string     `json:"analysis_model,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// EmbeddingModelCount is the number of stored articles embedded with one model
+type EmbeddingModelCount struct {
+	Model    string `json:"model"` // Empty for articles without a recorded model
+	Articles int    `json:"articles"`
+}
+
+// AnalysisVersionCount is the number of enriched articles analyzed with one semantic extraction version
+type AnalysisVersionCount struct {
+	Version  string `json:"version"` // Empty for articles without a recorded version
+	Articles int    `json:"articles"`
+}
+
+// VectorIndex describes a pgvector index over a table's embeddings
+type VectorIndex struct {
+	Name       string `json:"name"`
+	Table      string `json:"table"`
+	Method     string `json:"method"` // ivfflat or hnsw; empty when the index does not exist
+	Definition string `json:"definition,omitempty"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Rows       int    `json:"rows"` // Rows in the table
 }
 
 // ChatCache represents a cached chat request/response
@@ -60,9 +184,215 @@ type ChatCache struct {
 	ExpiresAt    time.Time   `json:"expires_at"`
 }
 
+// ArticleFilter narrows article queries by extracted metadata
+type ArticleFilter struct {
+	Author          string    `json:"author,omitempty"`
+	Section         string    `json:"section,omitempty"`
+	IngestedAfter   time.Time `json:"ingested_after,omitempty"`   // Only articles created after this time
+	UpdatedAfter    time.Time `json:"updated_after,omitempty"`    // Only articles stored after this time, including re-ingests and refreshes with changed content
+	PublishedAfter  time.Time `json:"published_after,omitempty"`  // Publication date, or ingestion date when unknown, at or after this time
+	PublishedBefore time.Time `json:"published_before,omitempty"` // Publication date, or ingestion date when unknown, before this time
+	SourceDomain    string    `json:"source_domain,omitempty"`    // Substring of the source domain, e.g. "techcrunch"
+	Topic           string    `json:"topic,omitempty"`            // Substring of an extracted topic name
+	TopicID         string    `json:"topic_id,omitempty"`         // Taxonomy topic, including its subtopics
+	Tag             string    `json:"tag,omitempty"`              // User-defined tag, as returned by NormalizeTag
+	Collection      string    `json:"collection,omitempty"`       // Collection ID, or its name in any case
+	Status          string    `json:"status,omitempty"`           // Article ingest state, e.g. ArticleFailed
+	AnalysisVersion string    `json:"analysis_version,omitempty"` // Semantic extraction version, e.g. "v1" or AnalysisSupplied
+	SummaryVersion  string    `json:"summary_version,omitempty"`  // Summarization prompt version
+}
+
+// IsEmpty reports whether no filter fields are set
+func (f ArticleFilter) IsEmpty() bool {
+	return f.Author == "" && f.Section == "" && f.IngestedAfter.IsZero() && f.UpdatedAfter.IsZero() &&
+		f.PublishedAfter.IsZero() && f.PublishedBefore.IsZero() && f.SourceDomain == "" && f.Topic == "" && f.TopicID == "" && f.Tag == "" && f.Collection == "" && f.Status == "" &&
+		f.AnalysisVersion == "" && f.SummaryVersion == ""
+}
+
+// ArticleListOptions selects the articles and fields ListAllArticles returns
+type ArticleListOptions struct {
+	Filter ArticleFilter
+	Fields []string // JSON names of the Article fields to set; empty for the ArticleListItem fields. The embedding is never read.
+	Sort   string   // created_at (default) or sentiment_score
+	Desc   bool
+	Limit  int // 0 for no limit
+	Offset int
+}
+
+// SentimentQuery ranks articles by sentiment score, optionally within a score range
+type SentimentQuery struct {
+	Negative bool     `json:"negative"` // Lowest scores first
+	MinScore *float64 `json:"min_score,omitempty"`
+	MaxScore *float64 `json:"max_score,omitempty"`
+	Limit    int      `json:"limit"`
+}
+
+// TaxonomyTopic is a node of the managed topic taxonomy. Extracted topics are mapped to
+// taxonomy topics at ingest, so articles can be filtered by a stable ID.
+type TaxonomyTopic struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ParentID    string    `json:"parent_id,omitempty"` // Empty for top-level topics
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NormalizeTag returns the stored form of a user-defined tag: trimmed, lowercase, with runs
+// of spaces collapsed. Tags differing only in case are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// TagCount is a user-defined tag with the number of articles carrying it
+type TagCount struct {
+	Tag      string `json:"tag"`
+	Articles int    `json:"articles"`
+}
+
+// ArticleNote is a user's note on an article
+type ArticleNote struct {
+	ID        string    `json:"id"`
+	ArticleID string    `json:"article_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Collection is a named set of articles, such as a workspace for one research question.
+// Queries scoped to it with the collection filter only see its articles.
+type Collection struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Articles    int       `json:"articles"` // Number of articles in the collection
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SavedSearch is a stored query evaluated against newly ingested articles; matches are
+// recorded as alerts
+type SavedSearch struct {
+	ID           string    `json:"id"`
+	Topic        string    `json:"topic"`                   // Matched against title, summary, topics, keywords and entities
+	MinSentiment *float64  `json:"min_sentiment,omitempty"` // Only articles scoring at least this
+	MaxSentiment *float64  `json:"max_sentiment,omitempty"` // Only articles scoring at most this
+	CreatedAt    time.Time `json:"created_at"`
+	CheckedAt    time.Time `json:"checked_at"` // Articles ingested after this have not been evaluated yet
+}
+
+// Alert records an article that matched a saved search
+type Alert struct {
+	ID             string    `json:"id"`
+	SearchID       string    `json:"search_id"`
+	ArticleID      string    `json:"article_id"`
+	URL            string    `json:"url"`
+	Title          string    `json:"title"`
+	Sentiment      string    `json:"sentiment,omitempty"`
+	SentimentScore float64   `json:"sentiment_score"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// IngestFailure records a URL whose ingest failed after all retries; it is cleared once the
+// URL ingests successfully
+type IngestFailure struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url"`
+	Error         string    `json:"error"`
+	ErrorCategory string    `json:"error_category,omitempty"` // Fetch error category such as not_found or timeout
+	Attempts      int       `json:"attempts"`                 // Fetch attempts across every failed ingest of the URL
+	Failures      int       `json:"failures"`                 // Ingests of the URL that failed
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+}
+
+// PendingArticle is the fetched text of a new article waiting for LLM enrichment (summary,
+// embedding and semantics); it becomes an Article once enriched
+type PendingArticle struct {
+	URL         string
+	Title       string
+	Author      string
+	Section     string
+	PublishedAt *time.Time
+	Content     string // Extracted article text
+	ContentHash string
+	ETag        string
+	FetchedAt   time.Time
+}
+
 type ChatRequest struct {
-	Query string `json:"query,omitempty"`
-	Task  string `json:"task"` // summary, sentiment, compare, tone, search, more_positive, top_entities
+	Query     string `json:"query,omitempty"`
+	Task      string `json:"task,omitempty"`       // Optional: command to run, from GET /commands; skips the planner
+	SessionID string `json:"session_id,omitempty"` // Optional: reuse intermediate results across requests in a session
+
+	// Optional structured args; they fill a task's args, or override the planner's
+	URLs     []string `json:"urls,omitempty"`
+	Topic    string   `json:"topic,omitempty"`
+	DateFrom string   `json:"date_from,omitempty"` // Published on or after: today, last_week, 3d or YYYY-MM-DD
+	DateTo   string   `json:"date_to,omitempty"`   // Published before, same formats
+	Limit    int      `json:"limit,omitempty"`
+
+	PromptVersion string `json:"prompt_version,omitempty"` // Optional: planner prompt version instead of the configured one
+}
+
+// ArticleChunk is a passage of article text retrieved for question answering
+type ArticleChunk struct {
+	ArticleID  string  `json:"article_id"`
+	URL        string  `json:"url"`
+	Title      string  `json:"title"`
+	Index      int     `json:"index"`
+	Text       string  `json:"text"`
+	Similarity float64 `json:"similarity"`
+}
+
+// EntityNode is an entity in the co-occurrence graph; ID is its case-folded name
+type EntityNode struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Articles int    `json:"articles"` // Number of articles mentioning the entity
+}
+
+// EntityEdge links two entities mentioned in the same articles
+type EntityEdge struct {
+	Source   string `json:"source"` // Node IDs
+	Target   string `json:"target"`
+	Articles int    `json:"articles"` // Number of articles mentioning both
+}
+
+// EntityGraph is an entity co-occurrence graph for visualization clients
+type EntityGraph struct {
+	Nodes []EntityNode `json:"nodes"`
+	Edges []EntityEdge `json:"edges"`
+}
+
+// EntityDetail aggregates the articles mentioning one entity
+type EntityDetail struct {
+	ID           string            `json:"id"` // Folded canonical name, as in EntityNode
+	Name         string            `json:"name"`
+	Category     string            `json:"category"`
+	Articles     int               `json:"articles"`
+	AverageScore float64           `json:"average_score"` // Mean sentiment score of the mentioning articles
+	Positive     int               `json:"positive"`
+	Negative     int               `json:"negative"`
+	Neutral      int               `json:"neutral"`
+	CoOccurring  []EntityNode      `json:"co_occurring"` // Articles counts the articles shared with this entity
+	Timeline     []SentimentBucket `json:"timeline"`     // Mentioning articles per day or week of publication
+}
+
+// SentimentBucket aggregates the sentiment of articles published in one day or week
+type SentimentBucket struct {
+	Start        time.Time `json:"start"`
+	Articles     int       `json:"articles"`
+	AverageScore float64   `json:"average_score"`
+	Positive     int       `json:"positive"`
+	Negative     int       `json:"negative"`
+	Neutral      int       `json:"neutral"`
+}
+
+// ToneCount is the number of articles stored with one tone
+type ToneCount struct {
+	Tone     string `json:"tone"`
+	Articles int    `json:"articles"`
 }
 
 type ChatResponse struct {
@@ -71,33 +401,127 @@ type ChatResponse struct {
 	Usage        Usage       `json:"usage"`
 	Task         string      `json:"task"`
 	ResponseType string      `json:"response_type"`
-	Articles     []Article   `json:"articles,omitempty"` // For article list responses
-	Data         interface{} `json:"data,omitempty"`     // For structured data responses
-	Plan         *Plan       `json:"plan,omitempty"`     // Debug: LLM execution plan
+	Articles     []Article   `json:"articles,omitempty"`   // For article list responses
+	Data         interface{} `json:"data,omitempty"`       // For structured data responses
+	Plan         *Plan       `json:"plan,omitempty"`       // Debug: LLM execution plan
+	Cached       bool        `json:"cached"`               // Served from the chat cache
+	Pagination   *Pagination `json:"pagination,omitempty"` // For paged search results
+	Error        *APIError   `json:"error,omitempty"`      // Set when the command could not answer; Answer explains why
+	Degraded     []string    `json:"degraded,omitempty"`   // Optional steps skipped because the time budget ran low
+	Grounding    *Grounding  `json:"grounding,omitempty"`  // Set when the answer's grounding was checked
+
+	PromptVersion string `json:"prompt_version,omitempty"` // Planner prompt version that produced the plan
+}
+
+// Grounding reports how well an answer is supported by the articles it was generated from
+type Grounding struct {
+	Method      string   `json:"method"`                // "embedding" or "llm"
+	Score       float64  `json:"score"`                 // From 0 (unsupported) to 1 (fully supported)
+	Low         bool     `json:"low"`                   // Score is below the configured minimum
+	Unsupported []string `json:"unsupported,omitempty"` // Sentences or claims the articles do not support
+}
+
+// Lookup states of the URLs a query names, for commands that answer with the articles found
+const (
+	URLFound      = "found"
+	URLMissing    = "missing"     // Not stored
+	URLFetchError = "fetch_error" // Could not be ingested
+)
+
+// URLStatus is what became of one URL a query named
+type URLStatus struct {
+	Status string `json:"status"`          // URLFound, URLMissing or URLFetchError
+	Error  string `json:"error,omitempty"` // Why a fetch_error URL could not be ingested
+}
+
+// APIError is the body of every JSON error response. Code is one of the ErrCode constants
+// and stays stable across releases; Message is for people.
+type APIError struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// Error codes
+const (
+	ErrCodeBadRequest        = "BAD_REQUEST"
+	ErrCodeUnauthorized      = "UNAUTHORIZED" // The API key is missing or unknown
+	ErrCodeForbidden         = "FORBIDDEN"    // The API key's role may not use the route
+	ErrCodeMethodNotAllowed  = "METHOD_NOT_ALLOWED"
+	ErrCodeNotFound          = "NOT_FOUND"
+	ErrCodeArticleNotFound   = "ARTICLE_NOT_FOUND"
+	ErrCodeConflict          = "CONFLICT"         // The resource already exists
+	ErrCodePlanFailed        = "PLAN_FAILED"      // The query could not be planned
+	ErrCodeExecutionFailed   = "EXECUTION_FAILED" // The plan failed to run
+	ErrCodeNoAnswer          = "NO_ANSWER"        // The plan ran but found nothing to answer with
+	ErrCodeLLMTimeout        = "LLM_TIMEOUT"
+	ErrCodeLLMBusy           = "LLM_BUSY" // Every LLM slot stayed taken; retry after Retry-After
+	ErrCodeIngestFailed      = "INGEST_FAILED"
+	ErrCodeContentRejected   = "CONTENT_REJECTED"   // Moderation flagged the article's content
+	ErrCodeURLNotAllowed     = "URL_NOT_ALLOWED"    // The URL allowlist or denylist rejects the URL
+	ErrCodeIngestUnavailable = "INGEST_UNAVAILABLE" // The ingestion queue is full or shutting down
+	ErrCodeRateLimited       = "RATE_LIMITED"       // The tenant's request rate limit was reached; retry after Retry-After
+	ErrCodeTimeout           = "TIMEOUT"            // The route's time limit passed
+	ErrCodeInternal          = "INTERNAL_ERROR"
+)
+
+// Pagination locates a page of search results; Total counts every ranked match
+type Pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
 }
 
 type Source struct {
-	ID    string `json:"id"`
-	URL   string `json:"url"`
-	Title string `json:"title"`
+	ID     string  `json:"id"`
+	URL    string  `json:"url"`
+	Title  string  `json:"title"`
+	Score  float64 `json:"score,omitempty"`  // Relevance to the query, for search results
+	Reason string  `json:"reason,omitempty"` // Why it is part of the answer, e.g. SourceRequested
 }
 
+// Source reasons: why an article is among a response's sources
+const (
+	SourceRequested     = "requested"      // The query named its URL
+	SourceCompared      = "compared"       // One of the articles compared
+	SourceMatchedTopic  = "matched_topic"  // Found by searching for the query's topic
+	SourceMatchedTone   = "matched_tone"   // Has the tone the query asked for
+	SourceSentimentRank = "sentiment_rank" // Ranked by sentiment among the matching articles
+	SourceCitedPassage  = "cited_passage"  // Its passages were given to answer the question
+	SourceMatchedFilter = "matched_filter" // Selected by the query's metadata filters
+)
+
 type Usage struct {
-	Tokens int     `json:"tokens"`
-	Cost   float64 `json:"cost"`
+	Tokens           int      `json:"tokens"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	Cost             float64  `json:"cost"`             // Estimated USD
+	Models           []string `json:"models,omitempty"` // Models called, sorted
 }
 
 // Plan represents a command-based execution plan from LLM
 type Plan struct {
-	Command string                 `json:"command"`
-	Args    map[string]interface{} `json:"args"`
+	Command    string                 `json:"command"`
+	Args       map[string]interface{} `json:"args"`
+	Confidence *float64               `json:"confidence,omitempty"` // Planner's confidence in the plan, 0.0 to 1.0, when reported
+	Router     string                 `json:"router,omitempty"`     // Router* constant: what chose the command
 }
 
+// Routers that can choose a chat command
+const (
+	RouterLLM        = "llm"        // The LLM planner
+	RouterClassifier = "classifier" // Keyword heuristics, used when the planner is not confident
+	RouterFastPath   = "fast_path"  // Plain "verb + URLs" queries, planned without an LLM call
+	RouterRequest    = "request"    // The task named in the request
+)
+
 const (
 	// Response types
-	ResponseText        = "text"         // Single text response
-	ResponseArticleList = "article_list" // List of articles with URLs
-	ResponseData        = "data"         // Structured data (entities, keywords, etc.)
+	ResponseText          = "text"          // Single text response
+	ResponseArticleList   = "article_list"  // List of articles with URLs
+	ResponseData          = "data"          // Structured data (entities, keywords, etc.)
+	ResponseClarification = "clarification" // Follow-up question with suggested commands
 
 	// Query types
 	QuerySummary      = "summary"       // Single article summary
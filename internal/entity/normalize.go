@@ -0,0 +1,165 @@
+// Package entity normalizes extracted entity names so spelling variants and aliases of the
+// same entity ("OpenAI", "Open AI", "openai") are counted together.
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+)
+
+// Key folds an entity name for grouping: case-insensitive and ignoring spaces and punctuation.
+// Aggregation queries fold canonical names the same way.
+func Key(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// Resolver canonicalizes names the alias dictionary does not cover, returning a canonical
+// name for each name it recognizes
+type Resolver interface {
+	Resolve(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// Normalizer sets the canonical name of extracted entities from an alias dictionary and,
+// optionally, a Resolver. Resolved names are remembered so each is only resolved once.
+type Normalizer struct {
+	Resolver Resolver
+
+	mu        sync.RWMutex
+	canonical map[string]string // Key of an alias or canonical name -> canonical name
+}
+
+// NewNormalizer creates a normalizer from a dictionary of canonical names to their aliases
+func NewNormalizer(aliases map[string][]string) *Normalizer {
+	n := &Normalizer{canonical: make(map[string]string)}
+	for canonical, names := range aliases {
+		canonical = strings.TrimSpace(canonical)
+		n.canonical[Key(canonical)] = canonical
+		for _, alias := range names {
+			if k := Key(alias); k != "" {
+				n.canonical[k] = canonical
+			}
+		}
+	}
+	return n
+}
+
+// LoadAliases reads an alias dictionary from a JSON file of canonical names to alias lists,
+// e.g. {"United States": ["US", "USA", "U.S."]}
+func LoadAliases(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var aliases map[string][]string
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid alias file %s: %w", path, err)
+	}
+	return aliases, nil
+}
+
+// Canonical returns the dictionary's canonical name for name, or the trimmed name
+func (n *Normalizer) Canonical(name string) string {
+	name = strings.TrimSpace(name)
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if c, ok := n.canonical[Key(name)]; ok {
+		return c
+	}
+	return name
+}
+
+// Normalize sets CanonicalName on each entity and merges entities that share a canonical
+// name, keeping the first one's raw name and the highest confidence. Resolver failures are
+// logged and leave the names as they are.
+func (n *Normalizer) Normalize(ctx context.Context, entities []domain.SemanticEntity) []domain.SemanticEntity {
+	if n.Resolver != nil {
+		n.resolve(ctx, entities)
+	}
+
+	out := make([]domain.SemanticEntity, 0, len(entities))
+	seen := make(map[string]int)
+	for _, e := range entities {
+		e.Name = strings.TrimSpace(e.Name)
+		if e.Name == "" {
+			continue
+		}
+		e.CanonicalName = n.Canonical(e.Name)
+		key := Key(e.CanonicalName)
+		if i, ok := seen[key]; ok {
+			out[i].Confidence = max(out[i].Confidence, e.Confidence)
+			continue
+		}
+		seen[key] = len(out)
+		out = append(out, e)
+	}
+	return out
+}
+
+// resolve asks the resolver about names not yet known and remembers the answers
+func (n *Normalizer) resolve(ctx context.Context, entities []domain.SemanticEntity) {
+	var unknown []string
+	n.mu.RLock()
+	for _, e := range entities {
+		name := strings.TrimSpace(e.Name)
+		if _, ok := n.canonical[Key(name)]; !ok && name != "" {
+			unknown = append(unknown, name)
+		}
+	}
+	n.mu.RUnlock()
+	if len(unknown) == 0 {
+		return
+	}
+
+	resolved, err := n.Resolver.Resolve(ctx, unknown)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to canonicalize entity names", "error", err)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, name := range unknown {
+		canonical := strings.TrimSpace(resolved[name])
+		if canonical == "" {
+			canonical = name
+		}
+		// An existing dictionary entry for the canonical name wins over the resolver's spelling
+		if c, ok := n.canonical[Key(canonical)]; ok {
+			canonical = c
+		} else {
+			n.canonical[Key(canonical)] = canonical
+		}
+		n.canonical[Key(name)] = canonical
+	}
+}
+
+// LLMResolver canonicalizes entity names with one LLM call per batch
+type LLMResolver struct {
+	LLM llm.Client
+}
+
+func (r LLMResolver) Resolve(ctx context.Context, names []string) (map[string]string, error) {
+	raw, err := r.LLM.GenerateText(ctx, llm.EntityCanonicalizationPrompt(names))
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]string
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(raw)), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse canonical names: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	askPassages         = 6 // Passages given to the model
+	askFallbackArticles = 4 // Articles whose summaries are used when no passages are indexed
+)
+
+// Ask Command answers open-ended questions from retrieved article passages
+type AskCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *AskCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	question := strings.TrimSpace(query)
+	if question == "" {
+		filter, _ := plan.Args["filter"].(string)
+		question = strings.TrimSpace(filter)
+	}
+	if question == "" {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Question required"), nil
+	}
+
+	embedding, err := embedWithMemo(ctx, c.LLM, question)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	targetURLs, ok, err := scopeURLs(ctx, c.Repo, plan)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error applying filters"), nil
+	}
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, noFilterMatches), nil
+	}
+	passages, err := c.Repo.SearchArticleChunks(ctx, embedding, askPassages, targetURLs)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving passages"), nil
+	}
+	if len(passages) == 0 {
+		// Articles ingested before passage indexing: answer from their summaries
+		articles, err := c.Repo.GetArticlesByVectorSearchWithFilter(ctx, embedding, askFallbackArticles, targetURLs, extractArticleFilter(plan))
+		if err != nil {
+			return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving articles"), nil
+		}
+		for _, a := range articles {
+			passages = append(passages, domain.ArticleChunk{ArticleID: a.ID, URL: a.URL, Title: a.Title, Text: a.Summary})
+		}
+	}
+	if len(passages) == 0 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "No articles to answer from"), nil
+	}
+
+	answer, err := c.LLM.GenerateText(ctx, AskPrompt(question, passages))
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error generating answer"), nil
+	}
+
+	return &domain.ChatResponse{
+		Answer:       strings.TrimSpace(answer),
+		Sources:      passageSources(passages),
+		ResponseType: domain.ResponseText,
+		Task:         plan.Command,
+		Data:         passages,
+	}, nil
+}
+
+// AskPrompt builds a grounded question-answering prompt over passages numbered by their article,
+// in the order passageSources lists the articles, so the answer's citations index the sources
+func AskPrompt(question string, passages []domain.ArticleChunk) string {
+	var b strings.Builder
+	b.WriteString("Answer the question using only the passages below, which are numbered by the article they come from. " + citationInstruction + " ")
+	b.WriteString("Quote people exactly when asked what they said. If the passages do not contain the answer, say that the stored articles don't cover it.\n\n")
+	numbers := map[string]int{}
+	for _, p := range passages {
+		key := passageArticle(p)
+		if numbers[key] == 0 {
+			numbers[key] = len(numbers) + 1
+		}
+		b.WriteString(fmt.Sprintf("[%d] %s (%s)\n%s\n\n", numbers[key], p.Title, p.URL, p.Text))
+	}
+	b.WriteString("Question: " + question)
+	return b.String()
+}
+
+// passageSources lists each article once, in passage order, scored by its best passage
+func passageSources(passages []domain.ArticleChunk) []domain.Source {
+	sources := []domain.Source{}
+	seen := map[string]bool{}
+	for _, p := range passages {
+		if seen[passageArticle(p)] {
+			continue
+		}
+		seen[passageArticle(p)] = true
+		sources = append(sources, domain.Source{ID: p.ArticleID, URL: p.URL, Title: p.Title, Score: p.Similarity})
+	}
+	return sources
+}
+
+// passageArticle identifies a passage's article, by URL for passages without an article ID
+func passageArticle(p domain.ArticleChunk) string {
+	if p.ArticleID != "" {
+		return p.ArticleID
+	}
+	return p.URL
+}
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// citationInstruction asks a synthesis prompt's model to cite the numbered articles it was given
+const citationInstruction = "Cite the articles each statement comes from inline as [1], [2], etc., using only the numbers given."
+
+// maxCitationRange bounds how many citations a range such as [1-4] expands to
+const maxCitationRange = 20
+
+var (
+	// citationMarker matches inline citations as models write them: [1], [1, 2], [1-3], [Source 2]
+	citationMarker = regexp.MustCompile(`(?i)(\s?)\[(?:(?:source|article|passage)s?\s*)?(\d+(?:\s*(?:,|-|–|and)\s*\d+)*)\]`)
+	citationRun    = regexp.MustCompile(`(?:\[\d+\])+`)
+	citationNumber = regexp.MustCompile(`\d+`)
+	citationList   = regexp.MustCompile(`\s*(?:,|and)\s*`)
+	citationRange  = regexp.MustCompile(`^(\d+)\s*[-–]\s*(\d+)$`)
+)
+
+// RepairCitations rewrites an answer's inline citation markers as [n], where n is a 1-based index
+// into the response sources. renumber maps each cited number to its source index, or to 0 for a
+// number that cites no source, whose marker is dropped. Lists and ranges become one marker per
+// source, and a source cited twice in a row is cited once.
+func RepairCitations(answer string, renumber func(int) int) string {
+	answer = citationMarker.ReplaceAllStringFunc(answer, func(marker string) string {
+		m := citationMarker.FindStringSubmatch(marker)
+		var b strings.Builder
+		for _, n := range citedNumbers(m[2]) {
+			if i := renumber(n); i > 0 {
+				b.WriteString("[" + strconv.Itoa(i) + "]")
+			}
+		}
+		if b.Len() == 0 {
+			return ""
+		}
+		return m[1] + b.String()
+	})
+	return citationRun.ReplaceAllStringFunc(answer, func(run string) string {
+		seen := make(map[string]bool)
+		var b strings.Builder
+		for _, n := range citationNumber.FindAllString(run, -1) {
+			if !seen[n] {
+				seen[n] = true
+				b.WriteString("[" + n + "]")
+			}
+		}
+		return b.String()
+	})
+}
+
+// citedNumbers expands the inside of a citation marker, e.g. "1, 3-4", into its numbers
+func citedNumbers(list string) []int {
+	var numbers []int
+	for _, part := range citationList.Split(list, -1) {
+		if r := citationRange.FindStringSubmatch(part); r != nil {
+			from, _ := strconv.Atoi(r[1])
+			to, _ := strconv.Atoi(r[2])
+			for n := from; n <= to && n-from < maxCitationRange; n++ {
+				numbers = append(numbers, n)
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
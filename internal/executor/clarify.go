@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the commands offered in a clarification
+const maxSuggestions = 3
+
+// Clarification is the structured result returned when the planner's command is unknown
+type Clarification struct {
+	Question       string                `json:"question"`
+	UnknownCommand string                `json:"unknown_command,omitempty"`
+	Suggestions    []ClarificationOption `json:"suggestions"`
+}
+
+// ClarificationOption is a command suggested to the user, with an example query
+type ClarificationOption struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+}
+
+type clarificationCandidate struct {
+	ClarificationOption
+	minURLs  int
+	maxURLs  int      // -1 for no limit
+	keywords []string // Query or command words that make the option more likely
+}
+
+var clarificationCandidates = []clarificationCandidate{
+	{ClarificationOption{"summary", "a summary", "Summarize https://example.com/article"}, 1, 1, []string{"summar", "about", "tldr", "gist"}},
+	{ClarificationOption{"compare_articles", "a comparison", "Compare https://example.com/a and https://example.com/b"}, 2, -1, []string{"compar", "differ", " vs", "versus", "disagree"}},
+	{ClarificationOption{"get_sentiment", "the sentiment", "What is the sentiment of https://example.com/article?"}, 1, -1, []string{"sentiment", "positive", "negative", "feel"}},
+	{ClarificationOption{"keywords_or_topics", "the keywords and topics", "What are the keywords in https://example.com/article?"}, 1, -1, []string{"keyword", "topic", "tag"}},
+	{ClarificationOption{"filter_by_specific_topic", "articles about a topic", "What articles discuss AI?"}, 0, 0, []string{"find", "search", "articles", "discuss", "list"}},
+	{ClarificationOption{"ask", "an answer from the articles", "What did Sam Altman say about confidentiality?"}, 0, -1, []string{"why", "who", "said", "say", "quote"}},
+	{ClarificationOption{"cluster_articles", "an overview of the main topics", "Give me an overview of the main topics"}, 0, 0, []string{"overview", "group", "cluster", "main"}},
+}
+
+// Clarify asks the user a follow-up question when a query could not be mapped to a
+// supported command, suggesting the commands that fit the query best
+func Clarify(query, unknownCommand string) *domain.ChatResponse {
+	suggestions := SuggestCommands(query, unknownCommand)
+	descriptions := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		descriptions[i] = s.Description
+	}
+
+	question := "I couldn't tell what you're asking for. Did you mean " + joinOr(descriptions) + "?"
+	var answer strings.Builder
+	answer.WriteString(question + "\nFor example:\n")
+	for _, s := range suggestions {
+		answer.WriteString("- " + s.Example + "\n")
+	}
+
+	return &domain.ChatResponse{
+		Answer:       strings.TrimSpace(answer.String()),
+		Sources:      []domain.Source{},
+		ResponseType: domain.ResponseClarification,
+		Task:         unknownCommand,
+		Data: Clarification{
+			Question:       question,
+			UnknownCommand: unknownCommand,
+			Suggestions:    suggestions,
+		},
+	}
+}
+
+// SuggestCommands ranks the commands that accept as many URLs as the query contains by
+// how many of their keywords appear in the query or the unknown command name
+func SuggestCommands(query, unknownCommand string) []ClarificationOption {
+	urls := 0
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "http://") || strings.HasPrefix(field, "https://") {
+			urls++
+		}
+	}
+	text := strings.ToLower(query + " " + strings.ReplaceAll(unknownCommand, "_", " "))
+
+	type scored struct {
+		option ClarificationOption
+		score  int
+	}
+	var ranked []scored
+	for _, c := range clarificationCandidates {
+		if urls < c.minURLs || (c.maxURLs >= 0 && urls > c.maxURLs) {
+			continue
+		}
+		score := 0
+		for _, k := range c.keywords {
+			if strings.Contains(text, k) {
+				score++
+			}
+		}
+		ranked = append(ranked, scored{c.ClarificationOption, score})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	var out []ClarificationOption
+	for _, r := range ranked {
+		if len(out) == maxSuggestions {
+			break
+		}
+		out = append(out, r.option)
+	}
+	return out
+}
+
+// joinOr joins phrases as "a, b or c"
+func joinOr(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
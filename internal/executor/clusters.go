@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"article-assistant/internal/budget"
+	"article-assistant/internal/cluster"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxClusterArticles = 500 // Most recent articles considered
+	maxClusters        = 20
+	clusterIterations  = 50
+	titlesPerLabel     = 8 // Member titles shown to the model per cluster
+)
+
+// ArticleCluster is a labeled group of related articles
+type ArticleCluster struct {
+	Label    string          `json:"label"`
+	Size     int             `json:"size"`
+	Articles []domain.Source `json:"articles"`
+}
+
+// ClusterArticles Command
+type ClusterArticlesCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *ClusterArticlesCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	articles, err := c.Repo.GetArticleEmbeddings(ctx, maxClusterArticles)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving articles for clustering"), nil
+	}
+	// Filters apply within the most recent articles that are clustered
+	if !extractArticleFilter(plan).IsEmpty() {
+		scoped, _, err := scopeURLs(ctx, c.Repo, plan)
+		if err != nil {
+			return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error applying filters"), nil
+		}
+		articles = withURLs(articles, scoped)
+	}
+	articles = withEmbeddingDim(articles)
+	if len(articles) < 2 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "At least 2 ingested articles are required to find topic groups"), nil
+	}
+
+	k := cluster.DefaultK(len(articles))
+	if v, ok := plan.Args["k"].(float64); ok && v >= 2 {
+		k = int(v)
+	}
+	if k > maxClusters {
+		k = maxClusters
+	}
+
+	clusters := GroupArticles(articles, k)
+	labels := c.labelClusters(ctx, clusters)
+	for i := range clusters {
+		clusters[i].Label = labels[i]
+	}
+
+	return &domain.ChatResponse{
+		Answer:       formatClusters(clusters, len(articles)),
+		Sources:      []domain.Source{},
+		ResponseType: domain.ResponseText,
+		Task:         plan.Command,
+		Data:         clusters,
+	}, nil
+}
+
+// withURLs keeps the articles whose URL is in urls
+func withURLs(articles []domain.Article, urls []string) []domain.Article {
+	keep := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		keep[u] = true
+	}
+	var out []domain.Article
+	for _, a := range articles {
+		if keep[a.URL] {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// withEmbeddingDim drops articles whose embedding size differs from the most recent one
+func withEmbeddingDim(articles []domain.Article) []domain.Article {
+	if len(articles) == 0 {
+		return articles
+	}
+	dim := len(articles[0].Embedding)
+	out := articles[:0]
+	for _, a := range articles {
+		if dim > 0 && len(a.Embedding) == dim {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// GroupArticles clusters articles by embedding into at most k groups, largest first.
+// Labels are left empty.
+func GroupArticles(articles []domain.Article, k int) []ArticleCluster {
+	vectors := make([][]float32, len(articles))
+	for i, a := range articles {
+		vectors[i] = a.Embedding
+	}
+	assign := cluster.KMeans(vectors, k, clusterIterations, 1)
+
+	groups := make(map[int]*ArticleCluster)
+	var order []int
+	for i, a := range articles {
+		g, ok := groups[assign[i]]
+		if !ok {
+			g = &ArticleCluster{}
+			groups[assign[i]] = g
+			order = append(order, assign[i])
+		}
+		g.Articles = append(g.Articles, domain.Source{ID: a.ID, URL: a.URL, Title: a.Title})
+		g.Size++
+	}
+
+	out := make([]ArticleCluster, 0, len(order))
+	for _, id := range order {
+		out = append(out, *groups[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Size > out[j].Size })
+	return out
+}
+
+// labelClusters names every cluster in a single LLM call from member titles, falling back
+// to the first member title when the reply can't be used or the time budget is too low
+func (c *ClusterArticlesCommand) labelClusters(ctx context.Context, clusters []ArticleCluster) []string {
+	labels := make([]string, len(clusters))
+	for i, cl := range clusters {
+		labels[i] = cl.Articles[0].Title
+	}
+	if !budget.Allow(ctx, budget.StepClusterLabels, minLabelTime) {
+		return labels
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("Each group below lists titles of related news articles. Give each group a short topic label (2-5 words).\n")
+	prompt.WriteString(fmt.Sprintf("Respond with only a JSON array of %d strings, one label per group in order.\n\n", len(clusters)))
+	for i, cl := range clusters {
+		prompt.WriteString(fmt.Sprintf("Group %d:\n", i+1))
+		for j, a := range cl.Articles {
+			if j == titlesPerLabel {
+				break
+			}
+			prompt.WriteString("- " + a.Title + "\n")
+		}
+		prompt.WriteString("\n")
+	}
+
+	raw, err := c.LLM.GenerateText(ctx, prompt.String())
+	if err != nil {
+		return labels
+	}
+	parsed, err := ParseClusterLabels(raw, len(clusters))
+	if err != nil {
+		return labels
+	}
+	for i, l := range parsed {
+		if l != "" {
+			labels[i] = l
+		}
+	}
+	return labels
+}
+
+// ParseClusterLabels decodes the model's JSON array of labels; it must hold one label per cluster
+func ParseClusterLabels(raw string, n int) ([]string, error) {
+	var labels []string
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(raw)), &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster labels: %w", err)
+	}
+	if len(labels) != n {
+		return nil, fmt.Errorf("expected %d cluster labels, got %d", n, len(labels))
+	}
+	for i := range labels {
+		labels[i] = strings.TrimSpace(labels[i])
+	}
+	return labels, nil
+}
+
+func formatClusters(clusters []ArticleCluster, total int) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d articles in %d topic groups:\n", total, len(clusters)))
+	for i, cl := range clusters {
+		b.WriteString(fmt.Sprintf("%d. %s (%d articles)\n", i+1, cl.Label, cl.Size))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -2,7 +2,11 @@ package executor
 
 import (
 	"article-assistant/internal/domain"
+	"article-assistant/internal/tracing"
 	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // TaskCommand is the command interface for all query types
@@ -13,6 +17,8 @@ type TaskCommand interface {
 // Executor with Registry
 type Executor struct {
 	commands map[string]TaskCommand
+
+	Grounding *GroundingChecker // Optional; checks that answers are supported by their sources
 }
 
 func NewExecutor() *Executor {
@@ -23,13 +29,29 @@ func (e *Executor) Register(name string, cmd TaskCommand) {
 	e.commands[name] = cmd
 }
 
+// Names lists the registered command names, sorted
+func (e *Executor) Names() []string {
+	names := make([]string, 0, len(e.commands))
+	for name := range e.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (e *Executor) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
 	cmd, ok := e.commands[plan.Command]
 	if !ok {
-		return &domain.ChatResponse{
-			Answer: "Command not supported: " + plan.Command,
-			Task:   plan.Command,
-		}, nil
+		return Clarify(query, plan.Command), nil
+	}
+	ctx, span := tracing.Start(ctx, "executor."+plan.Command, attribute.String("plan.command", plan.Command))
+	resp, err := cmd.Execute(ctx, plan, query)
+	tracing.End(span, err)
+	if err == nil {
+		FinalizeSources(plan, resp)
+		if e.Grounding != nil {
+			e.Grounding.Apply(ctx, plan, resp)
+		}
 	}
-	return cmd.Execute(ctx, plan, query)
+	return resp, err
 }
@@ -2,16 +2,22 @@ package executor
 
 import (
 	"article-assistant/internal/domain"
+	"article-assistant/internal/language"
 	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
 	"article-assistant/internal/repository"
 	"context"
 	"fmt"
+	"math"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Summary Command
 type SummaryCommand struct {
-	Repo              *repository.Repo
+	Repo              repository.ArticleStore
+	LLM               llm.Client
 	ResponseGenerator *ResponseGenerator
 }
 
@@ -38,9 +44,99 @@ func (c *SummaryCommand) Execute(ctx context.Context, plan *domain.Plan, query s
 		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Article not found: "+targetURL), nil
 	}
 
+	opts, err := ParseSummaryOptions(plan.Args)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, err.Error()), nil
+	}
+
+	// Optional target language; the stored summary is in the article's own language
+	var lang string
+	if langArg, _ := plan.Args["language"].(string); strings.TrimSpace(langArg) != "" {
+		code, ok := language.Normalize(langArg)
+		if !ok {
+			return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Unsupported language: "+langArg), nil
+		}
+		if code != articles[0].Language {
+			lang = code
+		}
+	}
+
+	// Styled or length-limited summaries are written from the article text on request
+	if opts.Style != "" || opts.MaxWords > 0 {
+		if lang != "" {
+			opts.Language = language.Name(lang)
+		}
+		text := articleTexts(ctx, c.Repo, articles[:1])[0]
+		summary, err := c.LLM.Summarize(ctx, text, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize: %w", err)
+		}
+		return c.ResponseGenerator.CreateSingleArticleResponse(ctx, summary, plan.Command, &articles[0])
+	}
+	if lang != "" {
+		summary, err := SummarizeInLanguage(ctx, c.LLM, &articles[0], lang)
+		if err != nil {
+			return nil, err
+		}
+		return c.ResponseGenerator.CreateSingleArticleResponse(ctx, summary, plan.Command, &articles[0])
+	}
+
 	return c.ResponseGenerator.CreateSingleArticleResponse(ctx, articles[0].Summary, plan.Command, &articles[0])
 }
 
+const (
+	maxSummaryBullets = 10
+	minSummaryWords   = 10 // Smaller limits are raised to this
+)
+
+// ParseSummaryOptions reads the optional style, bullets and max_words summary args.
+// A bullet count implies the bullets style.
+func ParseSummaryOptions(args map[string]interface{}) (llm.SummaryOptions, error) {
+	var opts llm.SummaryOptions
+	style, _ := args["style"].(string)
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "":
+	case "bullets", "bullet", "bullet_points", "bulleted":
+		opts.Style = "bullets"
+	case "one_liner", "one-liner", "oneliner", "tldr":
+		opts.Style = "one_liner"
+	case "executive", "executive_brief", "brief":
+		opts.Style = "executive"
+	case "eli5":
+		opts.Style = "eli5"
+	default:
+		return opts, fmt.Errorf("Unsupported summary style: %s (use %s)", style, strings.Join(llm.SummaryStyles, ", "))
+	}
+	if v, ok := args["bullets"].(float64); ok && v >= 1 {
+		opts.Style = "bullets"
+		opts.Bullets = min(int(v), maxSummaryBullets)
+	}
+	if v, ok := args["max_words"].(float64); ok && v >= 1 {
+		opts.MaxWords = max(int(v), minSummaryWords)
+	}
+	return opts, nil
+}
+
+// SummarizeInLanguage writes an article's summary in the target language (ISO 639-1 code)
+func SummarizeInLanguage(ctx context.Context, llmClient llm.Client, article *domain.Article, lang string) (string, error) {
+	source := "its original language"
+	if article.Language != "" {
+		source = language.Name(article.Language)
+	}
+	prompt := fmt.Sprintf("Rewrite this article summary in %s. It is written in %s. Keep names, figures and quotes accurate; answer with the summary only.\n\nTitle: %s\nSummary: %s",
+		language.Name(lang), source, article.Title, article.Summary)
+	text, err := llmClient.GenerateText(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize in %s: %w", language.Name(lang), err)
+	}
+	return text, nil
+}
+
+// PlanURLs returns the URLs in a plan's args
+func PlanURLs(plan *domain.Plan) []string {
+	return extractURLs(plan)
+}
+
 // Helper functions
 func extractURLs(plan *domain.Plan) []string {
 	var targetURLs []string
@@ -56,9 +152,89 @@ func extractURLs(plan *domain.Plan) []string {
 	return targetURLs
 }
 
+// extractArticleFilter reads optional metadata filters from plan args
+func extractArticleFilter(plan *domain.Plan) domain.ArticleFilter {
+	var f domain.ArticleFilter
+	if v, ok := plan.Args["author"].(string); ok {
+		f.Author = strings.TrimSpace(v)
+	}
+	if v, ok := plan.Args["section"].(string); ok {
+		f.Section = strings.TrimSpace(v)
+	}
+	if v, ok := plan.Args["source"].(string); ok {
+		f.SourceDomain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "www.")
+	}
+	if v, ok := plan.Args["topic_id"].(string); ok {
+		f.TopicID = strings.TrimSpace(v)
+	}
+	if v, ok := plan.Args["tag"].(string); ok {
+		f.Tag = domain.NormalizeTag(v)
+	}
+	if v, ok := plan.Args["collection"].(string); ok {
+		f.Collection = strings.TrimSpace(v)
+	}
+	// Unparseable dates are ignored rather than failing the query
+	now := time.Now()
+	if v, ok := plan.Args["published_after"].(string); ok {
+		f.PublishedAfter, _ = ParseDate(v, now)
+	}
+	if v, ok := plan.Args["published_before"].(string); ok {
+		f.PublishedBefore, _ = ParseDate(v, now)
+	}
+	return f
+}
+
+// scopeURLs narrows the plan's URLs, or the whole corpus when it names none, to articles
+// matching its metadata filters. ok is false when filters are set and nothing matches.
+func scopeURLs(ctx context.Context, repo repository.ArticleStore, plan *domain.Plan) (urls []string, ok bool, err error) {
+	urls = extractURLs(plan)
+	filter := extractArticleFilter(plan)
+	if filter.IsEmpty() {
+		return urls, true, nil
+	}
+	urls, err = repo.GetArticleURLs(ctx, urls, filter)
+	if err != nil {
+		return nil, false, err
+	}
+	return urls, len(urls) > 0, nil
+}
+
+// noFilterMatches is the answer when a plan's metadata filters match no articles
+const noFilterMatches = "No articles match the given date, source, author or section filters"
+
+// maxContentChars caps the amount of full text sent to the LLM per article
+const maxContentChars = 8000
+
+// articleTexts returns the full stored text for each article, falling back to the summary
+func articleTexts(ctx context.Context, repo repository.ArticleStore, articles []domain.Article) []string {
+	urls := make([]string, len(articles))
+	for i, a := range articles {
+		urls[i] = a.URL
+	}
+
+	contents, err := repo.GetArticleContentsByURLs(ctx, urls)
+	if err != nil {
+		contents = nil // Fall back to summaries
+	}
+
+	texts := make([]string, len(articles))
+	for i, a := range articles {
+		text, ok := contents[a.URL]
+		if !ok {
+			texts[i] = a.Summary
+			continue
+		}
+		if len(text) > maxContentChars {
+			text = text[:maxContentChars] + "..."
+		}
+		texts[i] = text
+	}
+	return texts
+}
+
 // KeywordsOrTopics Command
 type FetchKeywordsOrTopicsCommand struct {
-	Repo              *repository.Repo
+	Repo              repository.ArticleStore
 	ResponseGenerator *ResponseGenerator
 }
 
@@ -80,26 +256,44 @@ func (c *FetchKeywordsOrTopicsCommand) Execute(ctx context.Context, plan *domain
 	var result strings.Builder
 	if len(keywords) > 0 {
 		result.WriteString("Top Keywords:\n")
-		for i, k := range keywords {
-			result.WriteString(fmt.Sprintf("%d. %s\n", i+1, k.Term))
-		}
+		writeTermAggregates(&result, keywords)
 	}
 	if len(topics) > 0 {
 		if result.Len() > 0 {
 			result.WriteString("\n")
 		}
 		result.WriteString("Top Topics:\n")
-		for i, t := range topics {
-			result.WriteString(fmt.Sprintf("%d. %s\n", i+1, t.Name))
-		}
+		writeTermAggregates(&result, topics)
 	}
 
-	return c.ResponseGenerator.CreateTextResponse(ctx, result.String(), plan.Command, targetURLs)
+	response, err := c.ResponseGenerator.CreateTextResponse(ctx, result.String(), plan.Command, targetURLs)
+	if err != nil {
+		return nil, err
+	}
+	response.Data = KeywordsAndTopics{Keywords: keywords, Topics: topics}
+	return response, nil
+}
+
+// KeywordsAndTopics is the data of a keywords_or_topics answer, ranked by summed relevance
+type KeywordsAndTopics struct {
+	Keywords []domain.TermAggregate `json:"keywords"`
+	Topics   []domain.TermAggregate `json:"topics"`
+}
+
+// writeTermAggregates lists terms with the numbers that rank them
+func writeTermAggregates(b *strings.Builder, terms []domain.TermAggregate) {
+	for i, t := range terms {
+		articles := "articles"
+		if t.Articles == 1 {
+			articles = "article"
+		}
+		fmt.Fprintf(b, "%d. %s (relevance %.2f, %d %s)\n", i+1, t.Term, t.Relevance, t.Articles, articles)
+	}
 }
 
 // Sentiment Command
 type FetchSentimentCommand struct {
-	Repo              *repository.Repo
+	Repo              repository.ArticleStore
 	ResponseGenerator *ResponseGenerator
 }
 
@@ -117,13 +311,15 @@ func (c *FetchSentimentCommand) Execute(ctx context.Context, plan *domain.Plan,
 	}
 
 	if len(arts) == 0 {
-		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "No articles found for the provided URLs"), nil
+		return c.ResponseGenerator.partialErrorResponse(ctx, plan.Command, "No articles found for the provided URLs", targetURLs, arts), nil
 	}
 
+	data := SentimentResult{URLResults: URLResults{URLs: urlStatuses(ctx, targetURLs, arts)}}
 	var sentiments []string
 	var totalScore float64
 	for _, a := range arts {
 		sentiments = append(sentiments, fmt.Sprintf("%s: %s (%.2f)", a.URL, a.Sentiment, a.SentimentScore))
+		data.Articles = append(data.Articles, ArticleSentiment{URL: a.URL, Sentiment: a.Sentiment, Score: a.SentimentScore})
 		totalScore += a.SentimentScore
 	}
 
@@ -136,9 +332,13 @@ func (c *FetchSentimentCommand) Execute(ctx context.Context, plan *domain.Plan,
 	} else {
 		overallSentiment = "neutral"
 	}
+	data.Overall, data.Score = overallSentiment, avgScore
 
 	result := fmt.Sprintf("Overall sentiment: %s (%.2f)\nArticles:\n%s",
 		overallSentiment, avgScore, strings.Join(sentiments, "\n"))
+	if note := unusedURLs(targetURLs, data.URLs); note != "" {
+		result += "\n\n" + note
+	}
 
 	// Create sources from articles
 	var sources []domain.Source
@@ -155,88 +355,29 @@ func (c *FetchSentimentCommand) Execute(ctx context.Context, plan *domain.Plan,
 		Sources:      sources,
 		ResponseType: domain.ResponseText,
 		Task:         plan.Command,
+		Data:         data,
 	}, nil
 }
 
-// Compare Command
-type CompareCommand struct {
-	Repo              *repository.Repo
-	LLM               *llm.OpenAIClient
-	ResponseGenerator *ResponseGenerator
+// SentimentResult is the data of a get_sentiment answer, over the articles found
+type SentimentResult struct {
+	Overall    string             `json:"overall"` // positive, negative or neutral
+	Score      float64            `json:"score"`   // Average sentiment score
+	Articles   []ArticleSentiment `json:"articles"`
+	URLResults                    // Which requested URLs were found
 }
 
-func (c *CompareCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
-	// Extract URLs from args
-	var targetURLs []string
-	if urlsVal, ok := plan.Args["urls"]; ok {
-		if urlSlice, ok := urlsVal.([]interface{}); ok {
-			for _, u := range urlSlice {
-				if urlStr, ok := u.(string); ok {
-					targetURLs = append(targetURLs, urlStr)
-				}
-			}
-		}
-	}
-
-	if len(targetURLs) < 2 {
-		return &domain.ChatResponse{
-			Answer: "At least 2 URLs required for comparison",
-			Task:   plan.Command,
-		}, nil
-	}
-
-	// Get articles for comparison
-	articles, err := c.Repo.GetArticlesByURLs(ctx, targetURLs)
-	if err != nil {
-		return &domain.ChatResponse{
-			Answer: "Error retrieving articles for comparison",
-			Task:   plan.Command,
-		}, nil
-	}
-
-	if len(articles) < 2 {
-		return &domain.ChatResponse{
-			Answer: "Could not find at least 2 articles for comparison",
-			Task:   plan.Command,
-		}, nil
-	}
-
-	var summaries []string
-	for _, article := range articles {
-		summaries = append(summaries, article.Summary)
-	}
-
-	// Use LLM to compare summaries
-	comparison, err := c.LLM.GenerateText(ctx, fmt.Sprintf("Compare these articles:\n1. %s\n2. %s", summaries[0], summaries[1]))
-	if err != nil {
-		return &domain.ChatResponse{
-			Answer: "Error generating comparison",
-			Task:   plan.Command,
-		}, nil
-	}
-
-	// Create sources from articles
-	var sources []domain.Source
-	for _, article := range articles {
-		sources = append(sources, domain.Source{
-			ID:    article.ID,
-			URL:   article.URL,
-			Title: article.Title,
-		})
-	}
-
-	return &domain.ChatResponse{
-		Answer:       comparison,
-		Sources:      sources,
-		ResponseType: domain.ResponseText,
-		Task:         plan.Command,
-	}, nil
+// ArticleSentiment is one article's stored sentiment
+type ArticleSentiment struct {
+	URL       string  `json:"url"`
+	Sentiment string  `json:"sentiment"`
+	Score     float64 `json:"score"`
 }
 
 // Tone Command
 type ToneKeyDfferencesCommand struct {
-	Repo              *repository.Repo
-	LLM               *llm.OpenAIClient
+	Repo              repository.ArticleStore
+	LLM               llm.Client
 	ResponseGenerator *ResponseGenerator
 }
 
@@ -276,13 +417,10 @@ func (c *ToneKeyDfferencesCommand) Execute(ctx context.Context, plan *domain.Pla
 		}, nil
 	}
 
-	var summaries []string
-	for _, article := range articles {
-		summaries = append(summaries, article.Summary)
-	}
+	texts := articleTexts(ctx, c.Repo, articles)
 
 	// Use LLM to compare tone
-	toneDiff, err := c.LLM.ToneCompare(ctx, summaries[0], summaries[1])
+	toneDiff, err := c.LLM.ToneCompare(ctx, texts[0], texts[1])
 	if err != nil {
 		return &domain.ChatResponse{
 			Answer: "Error comparing tone",
@@ -310,8 +448,8 @@ func (c *ToneKeyDfferencesCommand) Execute(ctx context.Context, plan *domain.Pla
 
 // MorePositive Command
 type FetchMostPositivesByFilter struct {
-	Repo              *repository.Repo
-	LLM               *llm.OpenAIClient
+	Repo              repository.ArticleStore
+	LLM               llm.Client
 	ResponseGenerator *ResponseGenerator
 }
 
@@ -329,12 +467,12 @@ func (c *FetchMostPositivesByFilter) Execute(ctx context.Context, plan *domain.P
 	}
 
 	// Step 1: Embed the filter and find similar articles
-	embedding, err := c.LLM.Embed(ctx, filter)
+	embedding, err := embedWithMemo(ctx, c.LLM, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate embedding: %v", err)
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	candidates, err := c.Repo.GetArticlesByVectorSearch(ctx, embedding, 2, []string{})
+	candidates, err := vectorSearchWithMemo(ctx, c.Repo, filter, embedding, 2, []string{}, extractArticleFilter(plan))
 	if err != nil {
 		return nil, err
 	}
@@ -344,25 +482,8 @@ func (c *FetchMostPositivesByFilter) Execute(ctx context.Context, plan *domain.P
 	}
 
 	// Step 2: LLM validation - filter candidates that actually discuss the topic
-	var validatedCandidates []domain.Article
-	for _, article := range candidates {
-		prompt := fmt.Sprintf("Does this article explicitly discuss %s?\n\nTitle: %s\nSummary: %s\n\nAnswer with only 'YES' or 'NO'.",
-			filter, article.Title, article.Summary)
-
-		fmt.Printf("🔍 LLM Validation Prompt: %s\n", prompt)
-		response, err := c.LLM.GenerateText(ctx, prompt)
-		if err != nil {
-			fmt.Printf("❌ LLM Error: %v\n", err)
-			// Include article if LLM fails
-			validatedCandidates = append(validatedCandidates, article)
-			continue
-		}
-		fmt.Printf("🤖 LLM Response: %s\n", response)
-
-		if strings.Contains(strings.ToUpper(response), "YES") {
-			validatedCandidates = append(validatedCandidates, article)
-		}
-	}
+	logger := logging.FromContext(ctx).With("command", plan.Command)
+	validatedCandidates := validateTopic(ctx, c.LLM, logger, filter, candidates)
 
 	if len(validatedCandidates) == 0 {
 		return c.ResponseGenerator.CreateErrorResponse(plan.Command, fmt.Sprintf("No articles found that explicitly discuss '%s'", filter)), nil
@@ -402,9 +523,13 @@ func (c *FetchMostPositivesByFilter) Execute(ctx context.Context, plan *domain.P
 	}, nil
 }
 
-// TopEntities Command
+// maxTopEntities is the number of entities get_top_entities returns
+const maxTopEntities = 10
+
+// TopEntities Command ranks entities by the number of articles mentioning them times their
+// mean confidence, optionally within one category
 type FetchTopEntitiesFromDBCommand struct {
-	Repo              *repository.Repo
+	Repo              repository.ArticleStore
 	ResponseGenerator *ResponseGenerator
 }
 
@@ -421,24 +546,38 @@ func (c *FetchTopEntitiesFromDBCommand) Execute(ctx context.Context, plan *domai
 		}
 	}
 
-	entities, err := c.Repo.GetTopEntities(ctx, 10, targetURLs)
+	category := ""
+	if raw, _ := plan.Args["category"].(string); strings.TrimSpace(raw) != "" {
+		var ok bool
+		if category, ok = domain.NormalizeEntityCategory(raw); !ok {
+			return c.ResponseGenerator.CreateErrorResponse(plan.Command,
+				fmt.Sprintf("Unknown entity category %q; use one of %s", raw, strings.Join(domain.EntityCategories, ", "))), nil
+		}
+	}
+
+	scoped, ok, err := scopeURLs(ctx, c.Repo, plan)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, noFilterMatches), nil
+	}
+	entities, err := c.Repo.GetTopEntities(ctx, maxTopEntities, scoped, category)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(entities) == 0 {
+		answer := "No entities found"
+		if category != "" {
+			answer = fmt.Sprintf("No %s entities found", category)
+		}
 		return &domain.ChatResponse{
-			Answer: "No entities found",
+			Answer: answer,
 			Task:   plan.Command,
 		}, nil
 	}
 
-	var result strings.Builder
-	result.WriteString("Top entities:\n")
-	for i, e := range entities {
-		result.WriteString(fmt.Sprintf("%d. %s (confidence: %.2f)\n", i+1, e.Name, e.Confidence))
-	}
-
 	// For top entities, we don't have specific article sources, but we can indicate
 	// that this is aggregated data from all articles (or filtered articles)
 	var sources []domain.Source
@@ -459,18 +598,56 @@ func (c *FetchTopEntitiesFromDBCommand) Execute(ctx context.Context, plan *domai
 	// which indicates this is aggregated data from all articles
 
 	return &domain.ChatResponse{
-		Answer:       result.String(),
+		Answer:       FormatTopEntities(entities, category),
 		Sources:      sources,
-		ResponseType: domain.ResponseText,
+		ResponseType: domain.ResponseData,
 		Task:         plan.Command,
+		Data:         entities,
 	}, nil
 }
 
+// FormatTopEntities renders ranked entities as a numbered text answer
+func FormatTopEntities(entities []domain.TopEntity, category string) string {
+	var b strings.Builder
+	if category != "" {
+		b.WriteString(fmt.Sprintf("Top %s entities:\n", category))
+	} else {
+		b.WriteString("Top entities:\n")
+	}
+	for i, e := range entities {
+		articles := "articles"
+		if e.Count == 1 {
+			articles = "article"
+		}
+		b.WriteString(fmt.Sprintf("%d. %s (%s): %d %s, confidence %.2f\n", i+1, e.Name, e.Category, e.Count, articles, e.AvgConfidence))
+	}
+	return strings.TrimSpace(b.String())
+}
+
 // Search Command
 type FetchArticlesDiscussingSpecificTopic struct {
-	Repo              *repository.Repo
-	LLM               *llm.OpenAIClient
+	Repo              repository.ArticleStore
+	LLM               llm.Client
 	ResponseGenerator *ResponseGenerator
+	VectorWeight      float64 // Hybrid search fusion weight; 0 uses HybridVectorWeight()
+}
+
+// hybridVectorWeight holds the float64 bits of the default vector share of hybrid search
+// scores (HYBRID_VECTOR_WEIGHT); it can change at runtime on config reload
+var hybridVectorWeight atomic.Uint64
+
+func init() {
+	SetHybridVectorWeight(repository.DefaultHybridVectorWeight)
+}
+
+// HybridVectorWeight returns the default vector share of hybrid search scores
+func HybridVectorWeight() float64 {
+	return math.Float64frombits(hybridVectorWeight.Load())
+}
+
+// SetHybridVectorWeight sets the default vector share of hybrid search scores
+func SetHybridVectorWeight(w float64) {
+	hybridVectorWeight.Store(math.Float64bits(w))
 }
 
 func (c *FetchArticlesDiscussingSpecificTopic) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
@@ -489,62 +666,56 @@ func (c *FetchArticlesDiscussingSpecificTopic) Execute(ctx context.Context, plan
 		}, nil
 	}
 
-	// Embed filter and search vector DB
-	embedding, err := c.LLM.Embed(ctx, filter)
+	// Embed filter and combine vector similarity with full-text rank
+	embedding, err := embedWithMemo(ctx, c.LLM, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate embedding: %v", err)
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	arts, err := c.Repo.GetArticlesByVectorSearch(ctx, embedding, 2, []string{})
+	weight := c.VectorWeight
+	if weight == 0 {
+		weight = HybridVectorWeight()
+	}
+	limit, offset := topicPage(plan.Args)
+	arts, total, err := hybridSearchWithMemo(ctx, c.Repo, filter, embedding, limit, offset, extractArticleFilter(plan), weight)
 	if err != nil {
 		return nil, err
 	}
+	page := &domain.Pagination{Total: total, Limit: limit, Offset: offset}
 
-	fmt.Printf("🔍 Vector search found %d articles for filter: %s\n", len(arts), filter)
+	logger := logging.FromContext(ctx).With("command", plan.Command)
+	logger.Info("hybrid search complete", "filter", filter, "results", len(arts), "total", total, "offset", offset)
 
 	if len(arts) == 0 {
+		answer := "No articles found for the given filter"
+		if offset > 0 && total > 0 {
+			answer = fmt.Sprintf("No more articles for the given filter (%d in total)", total)
+		}
 		return &domain.ChatResponse{
-			Answer: "No articles found for the given filter",
-			Task:   plan.Command,
+			Answer:     answer,
+			Task:       plan.Command,
+			Pagination: page,
 		}, nil
 	}
 
 	// Filter articles using LLM to check if they actually discuss the topic
-	var filteredArticles []domain.Article
-	for _, article := range arts {
-		// Create prompt to check if article discusses the filter topic
-		prompt := fmt.Sprintf("Does this article explicitly discuss %s?\n\nTitle: %s\nSummary: %s\n\nAnswer with only 'YES' or 'NO'.",
-			filter, article.Title, article.Summary)
-
-		fmt.Printf("🔍 LLM Verification Prompt: %s\n", prompt)
-
-		response, err := c.LLM.GenerateText(ctx, prompt)
-		if err != nil {
-			fmt.Printf("❌ LLM Error: %v\n", err)
-			// If LLM call fails, include the article to be safe
-			filteredArticles = append(filteredArticles, article)
-			continue
-		}
-
-		fmt.Printf("🤖 LLM Response: %s\n", response)
-
-		// Check if LLM response indicates the article discusses the topic
-		if strings.Contains(strings.ToUpper(response), "YES") {
-			filteredArticles = append(filteredArticles, article)
-		}
-	}
+	filteredArticles := validateTopic(ctx, c.LLM, logger, filter, arts)
 
 	if len(filteredArticles) == 0 {
 		return &domain.ChatResponse{
-			Answer: fmt.Sprintf("No articles found that explicitly discuss %s", filter),
-			Task:   plan.Command,
+			Answer:     fmt.Sprintf("No articles found that explicitly discuss %s", filter),
+			Task:       plan.Command,
+			Pagination: page,
 		}, nil
 	}
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Articles about %s:\n", filter))
 	for i, a := range filteredArticles {
-		result.WriteString(fmt.Sprintf("%d. %s\n   %s\n", i+1, a.Title, a.URL))
+		result.WriteString(fmt.Sprintf("%d. %s (score: %.2f)\n   %s\n", offset+i+1, a.Title, a.Score, a.URL))
+	}
+	if next := offset + len(arts); next < total {
+		result.WriteString(fmt.Sprintf("\nShowing results %d-%d of %d; ask for more to see the next page.\n", offset+1, next, total))
 	}
 
 	// Convert articles to sources
@@ -554,6 +725,7 @@ func (c *FetchArticlesDiscussingSpecificTopic) Execute(ctx context.Context, plan
 			ID:    a.ID,
 			URL:   a.URL,
 			Title: a.Title,
+			Score: a.Score,
 		})
 	}
 
@@ -562,5 +734,24 @@ func (c *FetchArticlesDiscussingSpecificTopic) Execute(ctx context.Context, plan
 		ResponseType: domain.ResponseArticleList,
 		Task:         plan.Command,
 		Sources:      sources,
+		Pagination:   page,
 	}, nil
 }
+
+const (
+	defaultTopicResults = 2
+	maxTopicResults     = 20
+)
+
+// topicPage reads the limit and offset plan args of a topic search; out-of-range values
+// fall back to the defaults
+func topicPage(args map[string]interface{}) (limit, offset int) {
+	limit = defaultTopicResults
+	if v, ok := args["limit"].(float64); ok && v >= 1 {
+		limit = min(int(v), maxTopicResults)
+	}
+	if v, ok := args["offset"].(float64); ok && v >= 0 {
+		offset = int(v)
+	}
+	return limit, offset
+}
@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxComparedArticles = 8
+	compareTextBudget   = 24000 // Characters of article text shared by all compared articles
+)
+
+// Comparison is the structured result of the compare_articles command. Citations are
+// 1-based indexes into the response sources.
+type Comparison struct {
+	Summary       string                   `json:"summary"`
+	CommonThemes  []string                 `json:"common_themes"`
+	UniquePoints  []ComparisonPoints       `json:"unique_points"`
+	Disagreements []ComparisonDisagreement `json:"disagreements"`
+	URLResults                             // Which requested URLs were compared
+}
+
+// ComparisonPoints are the points only one article makes
+type ComparisonPoints struct {
+	Citation int      `json:"citation"`
+	Title    string   `json:"title"`
+	URL      string   `json:"url"`
+	Points   []string `json:"points"`
+}
+
+// ComparisonDisagreement is an issue the articles take different positions on
+type ComparisonDisagreement struct {
+	Issue     string               `json:"issue"`
+	Positions []ComparisonPosition `json:"positions"`
+}
+
+// ComparisonPosition is one article's position on a disagreement
+type ComparisonPosition struct {
+	Citation int    `json:"citation"`
+	Position string `json:"position"`
+}
+
+// Compare Command
+type CompareCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *CompareCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	targetURLs := extractURLs(plan)
+	if len(targetURLs) < 2 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "At least 2 URLs required for comparison"), nil
+	}
+
+	articles, err := c.Repo.GetArticlesByURLs(ctx, targetURLs)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving articles for comparison"), nil
+	}
+	if len(articles) < 2 {
+		return c.ResponseGenerator.partialErrorResponse(ctx, plan.Command, "Could not find at least 2 articles for comparison", targetURLs, articles), nil
+	}
+	statuses := urlStatuses(ctx, targetURLs, articles)
+	if len(articles) > maxComparedArticles {
+		articles = articles[:maxComparedArticles]
+	}
+
+	// Share the text budget so every article is represented
+	perArticle := compareTextBudget / len(articles)
+	texts := articleTexts(ctx, c.Repo, articles)
+	for i, a := range articles {
+		texts[i] = a.Title + "\n" + truncateText(texts[i], perArticle)
+	}
+
+	raw, err := generateTextWithMemo(ctx, c.LLM, llm.ComparePrompt(texts))
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error generating comparison"), nil
+	}
+
+	response, err := c.ResponseGenerator.CreateArticleListResponse(ctx, raw, plan.Command, articles)
+	if err != nil {
+		return nil, err
+	}
+	response.ResponseType = domain.ResponseText
+
+	// Compare the articles found, and say which URLs were left out
+	response.Data = URLResults{URLs: statuses}
+
+	// Keep the model's free-form answer if it did not return the requested JSON
+	if comparison, err := ParseComparison(raw, articles); err == nil {
+		comparison.URLs = statuses
+		response.Answer = FormatComparison(comparison)
+		response.Data = comparison
+	}
+	if note := unusedURLs(targetURLs, statuses); note != "" {
+		response.Answer += "\n\n" + note
+	}
+	return response, nil
+}
+
+// ParseComparison decodes the model's JSON reply, dropping entries with unknown
+// citations and attaching article details to unique points
+func ParseComparison(raw string, articles []domain.Article) (*Comparison, error) {
+	var parsed Comparison
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(raw)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse comparison: %w", err)
+	}
+	valid := func(citation int) bool { return citation >= 1 && citation <= len(articles) }
+
+	out := &Comparison{Summary: strings.TrimSpace(parsed.Summary), CommonThemes: parsed.CommonThemes}
+	for _, p := range parsed.UniquePoints {
+		if !valid(p.Citation) || len(p.Points) == 0 {
+			continue
+		}
+		a := articles[p.Citation-1]
+		p.Title, p.URL = a.Title, a.URL
+		out.UniquePoints = append(out.UniquePoints, p)
+	}
+	for _, d := range parsed.Disagreements {
+		var positions []ComparisonPosition
+		for _, pos := range d.Positions {
+			if valid(pos.Citation) {
+				positions = append(positions, pos)
+			}
+		}
+		if d.Issue == "" || len(positions) == 0 {
+			continue
+		}
+		d.Positions = positions
+		out.Disagreements = append(out.Disagreements, d)
+	}
+	if len(out.CommonThemes) == 0 && len(out.UniquePoints) == 0 && len(out.Disagreements) == 0 {
+		return nil, fmt.Errorf("no comparison in response")
+	}
+	return out, nil
+}
+
+// FormatComparison renders a comparison as a text answer
+func FormatComparison(c *Comparison) string {
+	var b strings.Builder
+	if c.Summary != "" {
+		b.WriteString(c.Summary + "\n\n")
+	}
+	if len(c.CommonThemes) > 0 {
+		b.WriteString("Common themes:\n")
+		for _, t := range c.CommonThemes {
+			b.WriteString("- " + t + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(c.UniquePoints) > 0 {
+		b.WriteString("Unique points:\n")
+		for _, p := range c.UniquePoints {
+			b.WriteString(fmt.Sprintf("[%d] %s\n", p.Citation, p.Title))
+			for _, point := range p.Points {
+				b.WriteString("  - " + point + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	if len(c.Disagreements) > 0 {
+		b.WriteString("Disagreements:\n")
+		for _, d := range c.Disagreements {
+			b.WriteString("- " + d.Issue + "\n")
+			for _, pos := range d.Positions {
+				b.WriteString(fmt.Sprintf("  [%d] %s\n", pos.Citation, pos.Position))
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// truncateText cuts s to at most maxChars bytes on a rune boundary
+func truncateText(s string, maxChars int) string {
+	if len(s) <= maxChars {
+		return s
+	}
+	cut := maxChars
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
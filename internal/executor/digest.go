@@ -0,0 +1,229 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxDigestArticles caps how many articles one digest covers
+const maxDigestArticles = 10
+
+// DigestHighlight is one bullet of a digest section, citing the articles it draws on
+type DigestHighlight struct {
+	Text      string `json:"text"`
+	Citations []int  `json:"citations"` // 1-based indexes into the response sources
+}
+
+// DigestSection groups highlights under a theme
+type DigestSection struct {
+	Heading    string            `json:"heading"`
+	Highlights []DigestHighlight `json:"highlights"`
+}
+
+// DigestEntry is the one-line takeaway of a covered article
+type DigestEntry struct {
+	Citation int    `json:"citation"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	OneLiner string `json:"one_liner"`
+}
+
+// Digest is a multi-article news digest
+type Digest struct {
+	Intro    string          `json:"intro"`
+	Sections []DigestSection `json:"sections"`
+	Articles []DigestEntry   `json:"articles"`
+}
+
+// Digest Command
+type DigestCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *DigestCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	topic, _ := plan.Args["filter"].(string)
+	topic = strings.TrimSpace(topic)
+	filter := extractArticleFilter(plan)
+	urls := extractURLs(plan)
+	if topic == "" && filter.IsEmpty() && len(urls) == 0 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "A topic or date range is required for a digest"), nil
+	}
+
+	articles, errMsg := c.gather(ctx, topic, urls, filter)
+	if errMsg != "" {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, errMsg), nil
+	}
+
+	entries := make([]string, len(articles))
+	for i, a := range articles {
+		entries[i] = fmt.Sprintf("%s (%s, %s)\n%s", a.Title, SourceName(a.URL), articleDate(a).Format("2006-01-02"), a.Summary)
+	}
+	raw, err := generateTextWithMemo(ctx, c.LLM, llm.DigestPrompt(digestScope(topic, filter), entries))
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error generating digest"), nil
+	}
+
+	response, err := c.ResponseGenerator.CreateArticleListResponse(ctx, raw, plan.Command, articles)
+	if err != nil {
+		return nil, err
+	}
+	response.ResponseType = domain.ResponseText
+
+	// Keep the model's free-form answer if it did not return the requested JSON
+	if digest, err := ParseDigest(raw, articles); err == nil {
+		response.Answer = FormatDigest(digest)
+		response.Data = digest
+	}
+	return response, nil
+}
+
+// gather selects the articles to cover: the closest matches for the topic within the filters,
+// or the most recently published articles matching the filters when there is no topic
+func (c *DigestCommand) gather(ctx context.Context, topic string, urls []string, filter domain.ArticleFilter) ([]domain.Article, string) {
+	if topic != "" {
+		embedding, err := embedWithMemo(ctx, c.LLM, topic)
+		if err != nil {
+			return nil, "Error searching for articles for the digest"
+		}
+		articles, err := vectorSearchWithMemo(ctx, c.Repo, topic, embedding, maxDigestArticles, urls, filter)
+		if err != nil {
+			return nil, "Error searching for articles for the digest"
+		}
+		if len(articles) == 0 {
+			return nil, fmt.Sprintf("No articles about '%s' match the digest filters", topic)
+		}
+		return articles, ""
+	}
+
+	matching, err := c.Repo.GetArticleURLs(ctx, urls, filter)
+	if err != nil {
+		return nil, "Error applying filters"
+	}
+	if len(matching) == 0 {
+		return nil, noFilterMatches
+	}
+	articles, err := c.Repo.GetArticlesByURLs(ctx, matching)
+	if err != nil {
+		return nil, "Error retrieving articles for the digest"
+	}
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articleDate(articles[i]).After(articleDate(articles[j]))
+	})
+	if len(articles) > maxDigestArticles {
+		articles = articles[:maxDigestArticles]
+	}
+	return articles, ""
+}
+
+// articleDate is when an article was published, or ingested when that is unknown
+func articleDate(a domain.Article) time.Time {
+	if a.PublishedAt != nil {
+		return *a.PublishedAt
+	}
+	return a.CreatedAt
+}
+
+// digestScope describes the selection for the prompt, e.g. "AI published since 2025-01-01"
+func digestScope(topic string, filter domain.ArticleFilter) string {
+	scope := topic
+	if scope == "" {
+		scope = "recent news"
+	}
+	if filter.SourceDomain != "" {
+		scope += " from " + filter.SourceDomain
+	}
+	switch {
+	case !filter.PublishedAfter.IsZero() && !filter.PublishedBefore.IsZero():
+		scope += fmt.Sprintf(" published between %s and %s", filter.PublishedAfter.Format("2006-01-02"), filter.PublishedBefore.Format("2006-01-02"))
+	case !filter.PublishedAfter.IsZero():
+		scope += " published since " + filter.PublishedAfter.Format("2006-01-02")
+	case !filter.PublishedBefore.IsZero():
+		scope += " published before " + filter.PublishedBefore.Format("2006-01-02")
+	}
+	return scope
+}
+
+// ParseDigest decodes the model's JSON reply. Citations outside the articles are dropped, and
+// every article gets an entry in order, with its title and URL, even if the model skipped it.
+func ParseDigest(raw string, articles []domain.Article) (*Digest, error) {
+	var reply struct {
+		Intro    string          `json:"intro"`
+		Sections []DigestSection `json:"sections"`
+		Articles []DigestEntry   `json:"articles"`
+	}
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(raw)), &reply); err != nil {
+		return nil, fmt.Errorf("failed to parse digest: %w", err)
+	}
+	if strings.TrimSpace(reply.Intro) == "" && len(reply.Sections) == 0 {
+		return nil, fmt.Errorf("empty digest in response")
+	}
+
+	digest := &Digest{Intro: strings.TrimSpace(reply.Intro)}
+	for _, s := range reply.Sections {
+		var highlights []DigestHighlight
+		for _, h := range s.Highlights {
+			if strings.TrimSpace(h.Text) == "" {
+				continue
+			}
+			var citations []int
+			for _, n := range h.Citations {
+				if n >= 1 && n <= len(articles) {
+					citations = append(citations, n)
+				}
+			}
+			highlights = append(highlights, DigestHighlight{Text: strings.TrimSpace(h.Text), Citations: citations})
+		}
+		if len(highlights) > 0 {
+			digest.Sections = append(digest.Sections, DigestSection{Heading: strings.TrimSpace(s.Heading), Highlights: highlights})
+		}
+	}
+
+	oneLiners := make(map[int]string)
+	for _, e := range reply.Articles {
+		oneLiners[e.Citation] = strings.TrimSpace(e.OneLiner)
+	}
+	for i, a := range articles {
+		digest.Articles = append(digest.Articles, DigestEntry{Citation: i + 1, Title: a.Title, URL: a.URL, OneLiner: oneLiners[i+1]})
+	}
+	return digest, nil
+}
+
+// FormatDigest renders a digest as text: the intro, a bulleted list per section with
+// citations, then one line per article with its link
+func FormatDigest(d *Digest) string {
+	var b strings.Builder
+	if d.Intro != "" {
+		b.WriteString(d.Intro + "\n\n")
+	}
+	for _, s := range d.Sections {
+		if s.Heading != "" {
+			b.WriteString(s.Heading + "\n")
+		}
+		for _, h := range s.Highlights {
+			b.WriteString("- " + h.Text)
+			for _, n := range h.Citations {
+				b.WriteString(fmt.Sprintf(" [%d]", n))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Articles:\n")
+	for _, e := range d.Articles {
+		line := fmt.Sprintf("[%d] %s", e.Citation, e.Title)
+		if e.OneLiner != "" {
+			line += " — " + e.OneLiner
+		}
+		b.WriteString(line + "\n    " + e.URL + "\n")
+	}
+	return strings.TrimSpace(b.String())
+}
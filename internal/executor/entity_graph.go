@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	maxGraphNodes    = 30 // Most-mentioned entities included in the graph
+	minGraphShared   = 1  // Articles two entities must share to be linked
+	graphEdgesInText = 5  // Strongest links listed in the text answer
+)
+
+// EntityGraph Command
+type EntityGraphCommand struct {
+	Repo              repository.ArticleStore
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *EntityGraphCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	scoped, ok, err := scopeURLs(ctx, c.Repo, plan)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error applying filters"), nil
+	}
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, noFilterMatches), nil
+	}
+	graph, err := c.Repo.GetEntityGraph(ctx, maxGraphNodes, minGraphShared, scoped)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error building entity graph"), nil
+	}
+	if len(graph.Nodes) == 0 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "No entities found"), nil
+	}
+
+	sources, err := c.ResponseGenerator.createSourcesFromURLs(ctx, extractURLs(plan))
+	if err != nil {
+		sources = []domain.Source{}
+	}
+	return &domain.ChatResponse{
+		Answer:       FormatEntityGraph(graph),
+		Sources:      sources,
+		ResponseType: domain.ResponseData,
+		Task:         plan.Command,
+		Data:         graph,
+	}, nil
+}
+
+// FormatEntityGraph summarizes a graph as text: its size and strongest links
+func FormatEntityGraph(graph *domain.EntityGraph) string {
+	names := make(map[string]string, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		names[n.ID] = n.Name
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Entity co-occurrence graph: %d entities, %d connections.", len(graph.Nodes), len(graph.Edges)))
+	if len(graph.Edges) == 0 {
+		return b.String()
+	}
+	b.WriteString("\nStrongest connections:\n")
+	for i, e := range graph.Edges {
+		if i == graphEdgesInText {
+			break
+		}
+		b.WriteString(fmt.Sprintf("%d. %s — %s (%d shared articles)\n", i+1, names[e.Source], names[e.Target], e.Articles))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxFramingSources caps how many sources are contrasted in one comparison
+const maxFramingSources = 5
+
+// FramingNote describes how one source frames a shared story
+type FramingNote struct {
+	Citation       int      `json:"citation"` // 1-based index into the response sources
+	Source         string   `json:"source"`
+	URL            string   `json:"url"`
+	Emphasis       string   `json:"emphasis"`
+	LoadedLanguage []string `json:"loaded_language"`
+	Omissions      []string `json:"omissions"`
+	Summary        string   `json:"summary"`
+}
+
+// CompareFraming Command
+type CompareFramingCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *CompareFramingCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	articles, errMsg := c.storyCluster(ctx, plan)
+	if errMsg != "" {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, errMsg), nil
+	}
+
+	texts := articleTexts(ctx, c.Repo, articles)
+
+	var prompt strings.Builder
+	prompt.WriteString(`These articles from different sources cover the same story. Contrast how each source frames it.
+For every source return what it emphasizes, loaded or emotive wording it uses (quoted), and relevant facts that other sources report but it omits.
+Respond with only a JSON array, one object per source, in the same order:
+[{"citation": 1, "emphasis": "...", "loaded_language": ["..."], "omissions": ["..."], "summary": "one sentence on the overall framing"}]
+
+`)
+	for i, a := range articles {
+		prompt.WriteString(fmt.Sprintf("[%d] %s — %s\n%s\n\n", i+1, SourceName(a.URL), a.Title, texts[i]))
+	}
+
+	raw, err := generateTextWithMemo(ctx, c.LLM, prompt.String())
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error comparing framing"), nil
+	}
+
+	response, err := c.ResponseGenerator.CreateArticleListResponse(ctx, raw, plan.Command, articles)
+	if err != nil {
+		return nil, err
+	}
+	response.ResponseType = domain.ResponseText
+
+	// Keep the model's free-form answer if it did not return the requested JSON
+	if notes, err := ParseFramingNotes(raw, articles); err == nil {
+		response.Answer = formatFramingNotes(notes)
+		response.Data = notes
+	}
+	return response, nil
+}
+
+// storyCluster resolves the articles to compare: explicit URLs, or the closest
+// matches for a topic with at most one article per source
+func (c *CompareFramingCommand) storyCluster(ctx context.Context, plan *domain.Plan) ([]domain.Article, string) {
+	if urls := extractURLs(plan); len(urls) > 0 {
+		articles, err := c.Repo.GetArticlesByURLs(ctx, urls)
+		if err != nil {
+			return nil, "Error retrieving articles for framing comparison"
+		}
+		if len(articles) < 2 {
+			return nil, "At least 2 ingested articles are required to compare framing"
+		}
+		if len(articles) > maxFramingSources {
+			articles = articles[:maxFramingSources]
+		}
+		return articles, ""
+	}
+
+	filter, _ := plan.Args["filter"].(string)
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, "A story (topic) or at least 2 URLs are required to compare framing"
+	}
+
+	embedding, err := embedWithMemo(ctx, c.LLM, filter)
+	if err != nil {
+		return nil, "Error searching for coverage of the story"
+	}
+	candidates, err := vectorSearchWithMemo(ctx, c.Repo, filter, embedding, maxFramingSources*3, []string{}, extractArticleFilter(plan))
+	if err != nil {
+		return nil, "Error searching for coverage of the story"
+	}
+
+	articles := OnePerSource(candidates, maxFramingSources)
+	if len(articles) < 2 {
+		return nil, fmt.Sprintf("Need coverage from at least 2 sources about '%s' to compare framing", filter)
+	}
+	return articles, ""
+}
+
+// SourceName returns the publishing source of a URL, its host without a leading "www."
+func SourceName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+}
+
+// OnePerSource keeps the first article from each source, preserving order, up to limit
+func OnePerSource(articles []domain.Article, limit int) []domain.Article {
+	seen := make(map[string]bool)
+	var out []domain.Article
+	for _, a := range articles {
+		src := SourceName(a.URL)
+		if seen[src] {
+			continue
+		}
+		seen[src] = true
+		out = append(out, a)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// ParseFramingNotes decodes the model's JSON reply and attaches source details by citation
+func ParseFramingNotes(raw string, articles []domain.Article) ([]FramingNote, error) {
+	var notes []FramingNote
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(raw)), &notes); err != nil {
+		return nil, fmt.Errorf("failed to parse framing notes: %w", err)
+	}
+
+	var out []FramingNote
+	for i, n := range notes {
+		if n.Citation < 1 || n.Citation > len(articles) {
+			n.Citation = i + 1
+		}
+		if n.Citation > len(articles) {
+			continue
+		}
+		a := articles[n.Citation-1]
+		n.Source = SourceName(a.URL)
+		n.URL = a.URL
+		out = append(out, n)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no framing notes in response")
+	}
+	return out, nil
+}
+
+func formatFramingNotes(notes []FramingNote) string {
+	var b strings.Builder
+	for _, n := range notes {
+		b.WriteString(fmt.Sprintf("[%d] %s: %s\n", n.Citation, n.Source, n.Summary))
+		if n.Emphasis != "" {
+			b.WriteString("  Emphasis: " + n.Emphasis + "\n")
+		}
+		if len(n.LoadedLanguage) > 0 {
+			b.WriteString("  Loaded language: " + strings.Join(n.LoadedLanguage, "; ") + "\n")
+		}
+		if len(n.Omissions) > 0 {
+			b.WriteString("  Omits: " + strings.Join(n.Omissions, "; ") + "\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"article-assistant/internal/budget"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Grounding check methods
+const (
+	GroundingEmbedding = "embedding" // Each answer sentence is compared with the sources by embedding similarity
+	GroundingLLM       = "llm"       // The LLM judges which claims the sources support
+)
+
+const (
+	DefaultGroundingMinScore = 0.5
+	maxGroundingSentences    = 10 // Answer sentences embedded; the rest are not scored
+	minGroundingSentence     = 20 // Shorter lines, such as headings, are not scored
+)
+
+// groundedCommands write their answers from their sources' content; the answers of other
+// commands list or count articles and are not checked
+var groundedCommands = map[string]bool{
+	"summary": true, "ask": true, "whats_new": true, "digest": true,
+	"compare_articles": true, "compare_framing": true, "simplify": true,
+}
+
+var answerSentence = regexp.MustCompile(`[^.!?\n]+[.!?]*`)
+
+// GroundingChecker verifies that generated answers are supported by the summaries of their
+// sources, and by the retrieved passages for ask
+type GroundingChecker struct {
+	Repo     repository.ArticleStore
+	LLM      llm.Client
+	Method   string  // GroundingEmbedding or GroundingLLM
+	MinScore float64 // Answers scoring below are flagged as low grounding
+}
+
+// Apply sets resp.Grounding for a successful answer of a grounded command. The check is
+// skipped when the time budget runs low, and a failed check is logged and leaves the
+// response as it was.
+func (g *GroundingChecker) Apply(ctx context.Context, plan *domain.Plan, resp *domain.ChatResponse) {
+	if resp == nil || resp.Error != nil || len(resp.Sources) == 0 || !groundedCommands[plan.Command] {
+		return
+	}
+	if !budget.Allow(ctx, budget.StepGrounding, minGroundingTime) {
+		return
+	}
+	grounding, err := g.Check(ctx, resp)
+	if err != nil {
+		logging.FromContext(ctx).Warn("grounding check failed", "command", plan.Command, "error", err)
+		return
+	}
+	resp.Grounding = grounding
+}
+
+// Check scores how well resp.Answer is supported by its sources
+func (g *GroundingChecker) Check(ctx context.Context, resp *domain.ChatResponse) (*domain.Grounding, error) {
+	evidence, err := g.evidence(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(evidence) == 0 {
+		return nil, fmt.Errorf("no source content to check against")
+	}
+	answer := RepairCitations(resp.Answer, func(int) int { return 0 })
+
+	var grounding *domain.Grounding
+	if g.Method == GroundingLLM {
+		grounding, err = g.checkWithLLM(ctx, answer, evidence)
+	} else {
+		grounding, err = g.checkWithEmbeddings(ctx, answer, evidence)
+	}
+	if err != nil {
+		return nil, err
+	}
+	grounding.Low = grounding.Score < g.MinScore
+	return grounding, nil
+}
+
+// groundingEvidence is one text the answer may draw on, with its embedding when stored
+type groundingEvidence struct {
+	title     string
+	text      string
+	embedding []float32
+}
+
+// evidence collects the summaries of resp's sources and, for ask, the passages it answered from
+func (g *GroundingChecker) evidence(ctx context.Context, resp *domain.ChatResponse) ([]groundingEvidence, error) {
+	urls := make([]string, len(resp.Sources))
+	for i, s := range resp.Sources {
+		urls[i] = s.URL
+	}
+	articles, err := g.Repo.GetArticlesByURLs(ctx, urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sources: %w", err)
+	}
+	var evidence []groundingEvidence
+	for _, a := range articles {
+		if a.Summary != "" {
+			evidence = append(evidence, groundingEvidence{title: a.Title, text: a.Summary, embedding: a.Embedding})
+		}
+	}
+	if passages, ok := resp.Data.([]domain.ArticleChunk); ok {
+		for _, p := range passages {
+			evidence = append(evidence, groundingEvidence{title: p.Title, text: p.Text})
+		}
+	}
+	return evidence, nil
+}
+
+// checkWithEmbeddings scores each answer sentence by its similarity to the closest evidence;
+// the answer's score is the mean, and sentences below MinScore are unsupported
+func (g *GroundingChecker) checkWithEmbeddings(ctx context.Context, answer string, evidence []groundingEvidence) (*domain.Grounding, error) {
+	var sentences []string
+	for _, s := range answerSentence.FindAllString(answer, -1) {
+		if s = strings.TrimSpace(s); len(s) >= minGroundingSentence && len(sentences) < maxGroundingSentences {
+			sentences = append(sentences, s)
+		}
+	}
+	if len(sentences) == 0 {
+		return &domain.Grounding{Method: GroundingEmbedding, Score: 1}, nil
+	}
+	for i := range evidence {
+		if len(evidence[i].embedding) > 0 {
+			continue
+		}
+		embedding, err := embedWithMemo(ctx, g.LLM, evidence[i].text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed source: %w", err)
+		}
+		evidence[i].embedding = embedding
+	}
+
+	grounding := &domain.Grounding{Method: GroundingEmbedding}
+	total := 0.0
+	for _, s := range sentences {
+		embedding, err := embedWithMemo(ctx, g.LLM, s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed answer: %w", err)
+		}
+		best := 0.0
+		for _, e := range evidence {
+			best = math.Max(best, cosine(embedding, e.embedding))
+		}
+		total += best
+		if best < g.MinScore {
+			grounding.Unsupported = append(grounding.Unsupported, s)
+		}
+	}
+	grounding.Score = total / float64(len(sentences))
+	return grounding, nil
+}
+
+// checkWithLLM asks the model for the share of the answer's claims the evidence supports
+func (g *GroundingChecker) checkWithLLM(ctx context.Context, answer string, evidence []groundingEvidence) (*domain.Grounding, error) {
+	raw, err := g.LLM.GenerateText(ctx, groundingPrompt(answer, evidence))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check grounding: %w", err)
+	}
+	return ParseGrounding(raw)
+}
+
+// groundingPrompt asks whether the answer's claims are supported by the source texts
+func groundingPrompt(answer string, evidence []groundingEvidence) string {
+	var b strings.Builder
+	b.WriteString(`Check whether the answer below is supported by the sources. A claim is supported when a source states it or directly implies it; general knowledge does not count.
+Respond with only a JSON object: {"score": 0.8, "unsupported": ["each unsupported claim, quoted briefly"]}, where score is the share of the answer's claims that are supported, from 0.0 to 1.0.
+
+Sources:
+`)
+	for i, e := range evidence {
+		b.WriteString(fmt.Sprintf("[%d] %s\n%s\n\n", i+1, e.title, e.text))
+	}
+	b.WriteString("Answer:\n" + answer)
+	return b.String()
+}
+
+// ParseGrounding decodes the model's grounding verdict, clamping its score to [0, 1]
+func ParseGrounding(raw string) (*domain.Grounding, error) {
+	var reply struct {
+		Score       *float64 `json:"score"`
+		Unsupported []string `json:"unsupported"`
+	}
+	if err := json.Unmarshal([]byte(llm.CleanJSONResponse(raw)), &reply); err != nil {
+		return nil, fmt.Errorf("failed to parse grounding verdict: %w", err)
+	}
+	if reply.Score == nil {
+		return nil, fmt.Errorf("grounding verdict has no score")
+	}
+	grounding := &domain.Grounding{Method: GroundingLLM, Score: math.Min(math.Max(*reply.Score, 0), 1)}
+	for _, claim := range reply.Unsupported {
+		if claim = strings.TrimSpace(claim); claim != "" {
+			grounding.Unsupported = append(grounding.Unsupported, claim)
+		}
+	}
+	return grounding, nil
+}
+
+// cosine returns the cosine similarity of two embeddings, 0 for empty or mismatched ones
+func cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
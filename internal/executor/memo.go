@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"article-assistant/internal/session"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// embedWithMemo embeds text, reusing a prior embedding from the session memo if present
+func embedWithMemo(ctx context.Context, llmClient llm.Client, text string) ([]float32, error) {
+	memo := session.FromContext(ctx)
+	if memo != nil {
+		if v, ok := memo.Get(session.KindEmbedding, text); ok {
+			return v.([]float32), nil
+		}
+	}
+
+	embedding, err := llmClient.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if memo != nil {
+		memo.Set(session.KindEmbedding, text, embedding)
+	}
+	return embedding, nil
+}
+
+// filterKey identifies an article filter in memo keys; every field is part of it, so filters
+// that differ in any way never share a retrieval set
+func filterKey(f domain.ArticleFilter) string {
+	key, _ := json.Marshal(f)
+	return string(key)
+}
+
+// vectorSearchWithMemo runs a vector search for a filter, reusing a prior retrieval set from the session memo
+func vectorSearchWithMemo(ctx context.Context, repo repository.ArticleStore, filter string, embedding []float32, limit int, urls []string, articleFilter domain.ArticleFilter) ([]domain.Article, error) {
+	key := fmt.Sprintf("%s|%d|%s|%s", filter, limit, strings.Join(urls, ","), filterKey(articleFilter))
+
+	memo := session.FromContext(ctx)
+	if memo != nil {
+		if v, ok := memo.Get(session.KindRetrieval, key); ok {
+			return v.([]domain.Article), nil
+		}
+	}
+
+	articles, err := repo.GetArticlesByVectorSearchWithFilter(ctx, embedding, limit, urls, articleFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if memo != nil {
+		memo.Set(session.KindRetrieval, key, articles)
+	}
+	return articles, nil
+}
+
+// hybridPage is a memoized page of hybrid search results
+type hybridPage struct {
+	articles []domain.Article
+	total    int
+}
+
+// hybridSearchWithMemo runs a hybrid vector + full-text search, reusing a prior retrieval set from the session memo
+func hybridSearchWithMemo(ctx context.Context, repo repository.ArticleStore, filter string, embedding []float32, limit, offset int, articleFilter domain.ArticleFilter, vectorWeight float64) ([]domain.Article, int, error) {
+	key := fmt.Sprintf("hybrid|%s|%d|%d|%s|%g", filter, limit, offset, filterKey(articleFilter), vectorWeight)
+
+	memo := session.FromContext(ctx)
+	if memo != nil {
+		if v, ok := memo.Get(session.KindRetrieval, key); ok {
+			page := v.(hybridPage)
+			return page.articles, page.total, nil
+		}
+	}
+
+	articles, total, err := repo.GetArticlesByHybridSearch(ctx, embedding, filter, limit, offset, []string{}, articleFilter, vectorWeight)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if memo != nil {
+		memo.Set(session.KindRetrieval, key, hybridPage{articles: articles, total: total})
+	}
+	return articles, total, nil
+}
+
+// generateTextWithMemo generates text for a prompt, reusing a prior answer from the session memo
+func generateTextWithMemo(ctx context.Context, llmClient llm.Client, prompt string) (string, error) {
+	memo := session.FromContext(ctx)
+	if memo != nil {
+		if v, ok := memo.Get(session.KindText, prompt); ok {
+			return v.(string), nil
+		}
+	}
+
+	text, err := llmClient.GenerateText(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if memo != nil {
+		memo.Set(session.KindText, prompt, text)
+	}
+	return text, nil
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type fetchErrorsKey struct{}
+
+// WithFetchErrors records URLs of the query that could not be ingested, with the reason, so
+// commands answering from the articles found can report them as fetch_error
+func WithFetchErrors(ctx context.Context, errs map[string]string) context.Context {
+	if len(errs) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fetchErrorsKey{}, errs)
+}
+
+// URLResults is the per-URL part of the data of commands that answer with the articles they
+// found when some URLs are missing
+type URLResults struct {
+	URLs map[string]domain.URLStatus `json:"urls"`
+}
+
+// urlStatuses reports each requested URL as found among articles, failed to ingest, or missing
+func urlStatuses(ctx context.Context, urls []string, articles []domain.Article) map[string]domain.URLStatus {
+	found := make(map[string]bool, len(articles))
+	for _, a := range articles {
+		found[a.URL] = true
+	}
+	fetchErrs, _ := ctx.Value(fetchErrorsKey{}).(map[string]string)
+
+	out := make(map[string]domain.URLStatus, len(urls))
+	for _, u := range urls {
+		switch msg, failed := fetchErrs[u]; {
+		case found[u]:
+			out[u] = domain.URLStatus{Status: domain.URLFound}
+		case failed:
+			out[u] = domain.URLStatus{Status: domain.URLFetchError, Error: msg}
+		default:
+			out[u] = domain.URLStatus{Status: domain.URLMissing}
+		}
+	}
+	return out
+}
+
+// unusedURLs lists the URLs left out of an answer and why, in the order they were requested;
+// it is empty when every URL was found
+func unusedURLs(urls []string, statuses map[string]domain.URLStatus) string {
+	var b strings.Builder
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		switch st := statuses[u]; st.Status {
+		case domain.URLMissing:
+			fmt.Fprintf(&b, "- %s: not stored; ingest it first\n", u)
+		case domain.URLFetchError:
+			fmt.Fprintf(&b, "- %s: could not be ingested: %s\n", u, st.Error)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "Not included:\n" + strings.TrimSpace(b.String())
+}
+
+// partialErrorResponse is the answer when too few URLs were found, with each URL's status as data
+func (rg *ResponseGenerator) partialErrorResponse(ctx context.Context, command, message string, urls []string, articles []domain.Article) *domain.ChatResponse {
+	statuses := urlStatuses(ctx, urls, articles)
+	if note := unusedURLs(urls, statuses); note != "" {
+		message += "\n\n" + note
+	}
+	response := rg.CreateErrorResponse(command, message)
+	response.Data = URLResults{URLs: statuses}
+	return response
+}
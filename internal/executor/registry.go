@@ -5,20 +5,31 @@ import (
 	"article-assistant/internal/repository"
 )
 
-// NewExecutorWithCommands creates a new executor with all commands registered
-func NewExecutorWithCommands(repo *repository.Repo, llmClient *llm.OpenAIClient) *Executor {
+// NewExecutorWithCommands creates a new executor with every command in commands.Registry
+// registered
+func NewExecutorWithCommands(repo repository.ArticleStore, llmClient llm.Client) *Executor {
 	executor := NewExecutor()
 	responseGenerator := NewResponseGenerator(repo)
 
 	// Register all commands
-	executor.Register("summary", &SummaryCommand{Repo: repo, ResponseGenerator: responseGenerator})
+	executor.Register("summary", &SummaryCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
 	executor.Register("keywords_or_topics", &FetchKeywordsOrTopicsCommand{Repo: repo, ResponseGenerator: responseGenerator})
 	executor.Register("get_sentiment", &FetchSentimentCommand{Repo: repo, ResponseGenerator: responseGenerator})
 	executor.Register("compare_articles", &CompareCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
 	executor.Register("ton_key_differences", &ToneKeyDfferencesCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
 	executor.Register("most_positive_article_for_filter", &FetchMostPositivesByFilter{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("sentiment_filter", &SentimentFilterCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("filter_by_tone", &FilterByToneCommand{Repo: repo, ResponseGenerator: responseGenerator})
 	executor.Register("get_top_entities", &FetchTopEntitiesFromDBCommand{Repo: repo, ResponseGenerator: responseGenerator})
 	executor.Register("filter_by_specific_topic", &FetchArticlesDiscussingSpecificTopic{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("compare_framing", &CompareFramingCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("simplify", &SimplifyCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("whats_new", &WhatsNewCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("cluster_articles", &ClusterArticlesCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("sentiment_trend", &SentimentTrendCommand{Repo: repo, ResponseGenerator: responseGenerator})
+	executor.Register("entity_graph", &EntityGraphCommand{Repo: repo, ResponseGenerator: responseGenerator})
+	executor.Register("ask", &AskCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
+	executor.Register("digest", &DigestCommand{Repo: repo, LLM: llmClient, ResponseGenerator: responseGenerator})
 
 	return executor
 }
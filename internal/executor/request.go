@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"article-assistant/internal/commands"
+	"article-assistant/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxQueryLength bounds a chat query and topic, in characters
+const MaxQueryLength = 2000
+
+// taskAliases maps the short task names ChatRequest.Task documented before it named registry
+// commands
+var taskAliases = map[string]string{
+	"sentiment":     "get_sentiment",
+	"compare":       "compare_articles",
+	"tone":          "ton_key_differences",
+	"search":        "filter_by_specific_topic",
+	"more_positive": "most_positive_article_for_filter",
+	"top_entities":  "get_top_entities",
+}
+
+// RequestArgs converts a chat request's structured fields to plan args: urls, topic as filter,
+// date_from and date_to as published_after and published_before, and limit
+func RequestArgs(req domain.ChatRequest) (map[string]interface{}, error) {
+	for _, f := range []struct{ name, value string }{{"query", req.Query}, {"topic", req.Topic}} {
+		if err := ValidateQuery(f.value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", f.name, err)
+		}
+	}
+	args := make(map[string]interface{})
+	if len(req.URLs) > 0 {
+		urls := make([]interface{}, len(req.URLs))
+		for i, u := range req.URLs {
+			urls[i] = u
+		}
+		args["urls"] = urls
+	}
+	if topic := strings.TrimSpace(req.Topic); topic != "" {
+		args["filter"] = topic
+	}
+	now := time.Now()
+	for _, d := range []struct{ field, value, arg string }{
+		{"date_from", req.DateFrom, "published_after"},
+		{"date_to", req.DateTo, "published_before"},
+	} {
+		if strings.TrimSpace(d.value) == "" {
+			continue
+		}
+		if _, ok := ParseDate(d.value, now); !ok {
+			return nil, fmt.Errorf("invalid %s %q (use today, yesterday, last_week, last_month, 3d or YYYY-MM-DD)", d.field, d.value)
+		}
+		args[d.arg] = d.value
+	}
+	if req.Limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative")
+	}
+	if req.Limit > 0 {
+		args["limit"] = float64(req.Limit) // As decoded from a JSON plan
+	}
+	return args, nil
+}
+
+// ValidateQuery checks only a chat query's length and characters: valid UTF-8, at most
+// MaxQueryLength characters and no control characters other than tabs and line breaks.
+// Anything else is a legitimate question, including apostrophes, quoted phrases and words
+// such as "select"; queries and the args planned from them reach the database only as bound
+// parameters.
+func ValidateQuery(query string) error {
+	if !utf8.ValidString(query) {
+		return fmt.Errorf("must be valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(query); n > MaxQueryLength {
+		return fmt.Errorf("%d characters is over the limit of %d", n, MaxQueryLength)
+	}
+	for _, r := range query {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return fmt.Errorf("must not contain control characters")
+		}
+	}
+	return nil
+}
+
+// RequestPlan returns the plan for a request that names its command in task, with args from
+// RequestArgs; the planner is not called. It fails when the command is unknown or an arg it
+// requires is missing.
+func RequestPlan(task string, args map[string]interface{}) (*domain.Plan, error) {
+	name := strings.TrimSpace(task)
+	if alias, ok := taskAliases[name]; ok {
+		name = alias
+	}
+	cmd, ok := commands.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown task %q (use one of %s)", task, strings.Join(commands.Names(), ", "))
+	}
+	for _, arg := range cmd.Args {
+		if _, set := args[arg.Name]; arg.Required && !set {
+			field := arg.Name
+			if field == "filter" {
+				field = "topic"
+			}
+			return nil, fmt.Errorf("task %s requires %s", name, field)
+		}
+	}
+	return &domain.Plan{Command: name, Args: args, Router: domain.RouterRequest}, nil
+}
+
+// ApplyArgs sets args on a plan, replacing the planner's values for the same args
+func ApplyArgs(plan *domain.Plan, args map[string]interface{}) {
+	if len(args) == 0 {
+		return
+	}
+	if plan.Args == nil {
+		plan.Args = make(map[string]interface{}, len(args))
+	}
+	for k, v := range args {
+		plan.Args[k] = v
+	}
+}
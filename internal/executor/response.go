@@ -8,11 +8,11 @@ import (
 
 // ResponseGenerator handles common response generation patterns
 type ResponseGenerator struct {
-	repo *repository.Repo
+	repo repository.ArticleStore
 }
 
 // NewResponseGenerator creates a new response generator
-func NewResponseGenerator(repo *repository.Repo) *ResponseGenerator {
+func NewResponseGenerator(repo repository.ArticleStore) *ResponseGenerator {
 	return &ResponseGenerator{repo: repo}
 }
 
@@ -49,6 +49,7 @@ func (rg *ResponseGenerator) CreateErrorResponse(command, message string) *domai
 		Answer:       message,
 		ResponseType: domain.ResponseText,
 		Task:         command,
+		Error:        &domain.APIError{Code: domain.ErrCodeNoAnswer, Message: message},
 	}
 }
 
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	maxSentimentResults     = 10
+	sentimentCandidatesMin  = 8 // Nearest articles to the topic that are ranked by sentiment
+	sentimentCandidatesMult = 3 // Candidates per requested result, so validation can drop some
+)
+
+// SentimentFilter Command finds the most positive or most negative articles, optionally
+// about a topic and within a sentiment score range
+type SentimentFilterCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *SentimentFilterCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	sq, err := ParseSentimentQuery(plan.Args)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, err.Error()), nil
+	}
+	var topic string
+	if filterVal, ok := plan.Args["filter"].(string); ok {
+		topic = strings.TrimSpace(filterVal)
+	}
+
+	var embedding []float32
+	candidates := sq.Limit
+	if topic != "" {
+		embedding, err = embedWithMemo(ctx, c.LLM, topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		}
+		candidates = sq.Limit * sentimentCandidatesMult
+		if candidates < sentimentCandidatesMin {
+			candidates = sentimentCandidatesMin
+		}
+	}
+
+	// Fetch every candidate in sentiment order; validation below keeps the first sq.Limit
+	ranked := sq
+	ranked.Limit = candidates
+	articles, err := c.Repo.GetArticlesBySentiment(ctx, embedding, candidates, extractURLs(plan), extractArticleFilter(plan), ranked)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving articles by sentiment"), nil
+	}
+	if topic != "" {
+		articles = validateTopic(ctx, c.LLM, logging.FromContext(ctx).With("command", plan.Command), topic, articles)
+	}
+	if len(articles) > sq.Limit {
+		articles = articles[:sq.Limit]
+	}
+	if len(articles) == 0 {
+		msg := "No articles match the sentiment filter"
+		if topic != "" {
+			msg = fmt.Sprintf("No articles found that explicitly discuss '%s' and match the sentiment filter", topic)
+		}
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, msg), nil
+	}
+
+	return c.ResponseGenerator.CreateArticleListResponse(ctx, FormatSentimentFilter(topic, sq, articles), plan.Command, articles)
+}
+
+// ParseSentimentQuery reads direction, min_score, max_score and limit from plan args.
+// Direction defaults to positive; limit defaults to 1, or 5 when a score range is given.
+func ParseSentimentQuery(args map[string]interface{}) (domain.SentimentQuery, error) {
+	var sq domain.SentimentQuery
+	direction, _ := args["direction"].(string)
+	switch strings.ToLower(strings.TrimSpace(direction)) {
+	case "", "positive", "most_positive", "favorable":
+	case "negative", "most_negative", "critical", "unfavorable":
+		sq.Negative = true
+	default:
+		return sq, fmt.Errorf("Unsupported direction: %s (use positive or negative)", direction)
+	}
+
+	for key, dst := range map[string]**float64{"min_score": &sq.MinScore, "max_score": &sq.MaxScore} {
+		v, ok := args[key].(float64)
+		if !ok {
+			continue
+		}
+		if v < 0 || v > 1 {
+			return sq, fmt.Errorf("%s must be between 0 and 1", key)
+		}
+		*dst = &v
+	}
+	if sq.MinScore != nil && sq.MaxScore != nil && *sq.MinScore > *sq.MaxScore {
+		return sq, fmt.Errorf("min_score must not exceed max_score")
+	}
+
+	sq.Limit = 1
+	if sq.MinScore != nil || sq.MaxScore != nil {
+		sq.Limit = 5
+	}
+	if v, ok := args["limit"].(float64); ok && v >= 1 {
+		sq.Limit = int(v)
+	}
+	if sq.Limit > maxSentimentResults {
+		sq.Limit = maxSentimentResults
+	}
+	return sq, nil
+}
+
+// FormatSentimentFilter renders the ranked articles as a short text answer
+func FormatSentimentFilter(topic string, sq domain.SentimentQuery, articles []domain.Article) string {
+	direction := "positive"
+	if sq.Negative {
+		direction = "negative"
+	}
+	noun := "article"
+	if len(articles) > 1 {
+		noun = "articles"
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Most %s %s", direction, noun))
+	if topic != "" {
+		b.WriteString(fmt.Sprintf(" about '%s'", topic))
+	}
+	switch {
+	case sq.MinScore != nil && sq.MaxScore != nil:
+		b.WriteString(fmt.Sprintf(" with sentiment score between %.2f and %.2f", *sq.MinScore, *sq.MaxScore))
+	case sq.MinScore != nil:
+		b.WriteString(fmt.Sprintf(" with sentiment score at least %.2f", *sq.MinScore))
+	case sq.MaxScore != nil:
+		b.WriteString(fmt.Sprintf(" with sentiment score at most %.2f", *sq.MaxScore))
+	}
+	b.WriteString(":\n")
+	for i, a := range articles {
+		b.WriteString(fmt.Sprintf("%d. %s\n   %s\n   Sentiment: %s (%.2f)\n", i+1, a.Title, a.URL, a.Sentiment, a.SentimentScore))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Reading levels for simplified summaries
+const (
+	LevelELI5       = "eli5"
+	LevelHighSchool = "high_school"
+	LevelExpert     = "expert"
+)
+
+var levelInstructions = map[string]string{
+	LevelELI5:       "Explain it like I'm five: very short sentences, everyday words, no jargon, and one simple analogy if it helps.",
+	LevelHighSchool: "Explain it for a high-school student: plain language, define any technical terms briefly, keep it to one short paragraph.",
+	LevelExpert:     "Explain it for a domain expert: precise terminology, emphasize specifics, implications and caveats.",
+}
+
+// NormalizeLevel maps user-facing level names to a supported reading level.
+// It returns false for unknown levels.
+func NormalizeLevel(level string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(strings.ReplaceAll(level, "-", "_"))) {
+	case "", "eli5", "simple", "simply", "child", "kid", "non_expert", "beginner":
+		return LevelELI5, true
+	case "high_school", "highschool", "teen", "intermediate":
+		return LevelHighSchool, true
+	case "expert", "advanced", "technical":
+		return LevelExpert, true
+	}
+	return "", false
+}
+
+// SimplifyArticle rewrites an article's summary at the given reading level, caching the result per level
+func SimplifyArticle(ctx context.Context, repo repository.ArticleStore, llmClient llm.Client, article *domain.Article, level string) (string, error) {
+	cached, err := repo.GetSimplifiedSummary(ctx, article.ID, level)
+	if err != nil {
+		logging.FromContext(ctx).Warn("simplification cache lookup failed", "article_id", article.ID, "error", err)
+	} else if cached != "" {
+		return cached, nil
+	}
+
+	prompt := fmt.Sprintf("Rewrite this article summary for a non-expert reader.\n%s\n\nTitle: %s\nSummary: %s",
+		levelInstructions[level], article.Title, article.Summary)
+	text, err := llmClient.GenerateText(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to simplify summary: %w", err)
+	}
+
+	if err := repo.SetSimplifiedSummary(ctx, article.ID, level, text); err != nil {
+		logging.FromContext(ctx).Warn("failed to cache simplified summary", "article_id", article.ID, "level", level, "error", err)
+	}
+	return text, nil
+}
+
+// Simplify Command
+type SimplifyCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *SimplifyCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	targetURLs := extractURLs(plan)
+	if len(targetURLs) == 0 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Article URL required for simplification"), nil
+	}
+
+	levelArg, _ := plan.Args["level"].(string)
+	level, ok := NormalizeLevel(levelArg)
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Unsupported reading level: "+levelArg+" (use eli5, high_school or expert)"), nil
+	}
+
+	articles, err := c.Repo.GetArticlesByURLs(ctx, targetURLs[:1])
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving article: "+targetURLs[0]), nil
+	}
+	if len(articles) == 0 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Article not found: "+targetURLs[0]), nil
+	}
+
+	text, err := SimplifyArticle(ctx, c.Repo, c.LLM, &articles[0], level)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error simplifying article"), nil
+	}
+
+	return c.ResponseGenerator.CreateSingleArticleResponse(ctx, text, plan.Command, &articles[0])
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"sort"
+)
+
+// citingCommands answer with citations that are 1-based indexes into their sources, so their
+// sources keep the order the command gave them and their answers' markers are repaired
+var citingCommands = map[string]bool{"compare_articles": true, "compare_framing": true, "digest": true, "ask": true, "whats_new": true}
+
+// comparingCommands set their sources side by side
+var comparingCommands = map[string]bool{"compare_articles": true, "ton_key_differences": true, "compare_framing": true}
+
+// searchReasons say how each command found the sources the query did not name
+var searchReasons = map[string]string{
+	"filter_by_specific_topic":         domain.SourceMatchedTopic,
+	"whats_new":                        domain.SourceMatchedTopic,
+	"digest":                           domain.SourceMatchedTopic,
+	"most_positive_article_for_filter": domain.SourceSentimentRank,
+	"sentiment_filter":                 domain.SourceSentimentRank,
+	"filter_by_tone":                   domain.SourceMatchedTone,
+	"ask":                              domain.SourceCitedPassage,
+}
+
+// FinalizeSources dedupes a response's sources by article ID, keeping each article's first
+// position and best score, and sets the Reason of those the command left without one. Sources
+// are then ordered by score, best first, unless the answer cites them by position; unscored
+// sources keep the command's order after the scored ones. The citation markers of an answer
+// citing its sources are renumbered after the dedupe and repaired with RepairCitations.
+func FinalizeSources(plan *domain.Plan, resp *domain.ChatResponse) {
+	if resp == nil || resp.Error != nil {
+		return
+	}
+	requested := make(map[string]bool)
+	for _, u := range extractURLs(plan) {
+		requested[u] = true
+	}
+
+	sources := make([]domain.Source, 0, len(resp.Sources))
+	seen := make(map[string]int, len(resp.Sources))
+	position := make([]int, len(resp.Sources)) // Each source's 1-based position after the dedupe
+	for j, s := range resp.Sources {
+		key := s.ID
+		if key == "" {
+			key = s.URL
+		}
+		if i, ok := seen[key]; ok {
+			position[j] = i + 1
+			if s.Score > sources[i].Score {
+				sources[i].Score = s.Score
+			}
+			if sources[i].Reason == "" {
+				sources[i].Reason = s.Reason
+			}
+			continue
+		}
+		seen[key] = len(sources)
+		sources = append(sources, s)
+		position[j] = len(sources)
+	}
+	for i := range sources {
+		if sources[i].Reason == "" {
+			sources[i].Reason = sourceReason(plan, sources[i], requested)
+		}
+	}
+	if citingCommands[plan.Command] {
+		resp.Answer = RepairCitations(resp.Answer, func(n int) int {
+			if n < 1 || n > len(position) {
+				return 0
+			}
+			return position[n-1]
+		})
+	} else {
+		sort.SliceStable(sources, func(i, j int) bool { return sources[i].Score > sources[j].Score })
+	}
+	if resp.Sources != nil {
+		resp.Sources = sources
+	}
+}
+
+// sourceReason says why a command included s: compared, named in the query, or found by the
+// command's search or the plan's filters
+func sourceReason(plan *domain.Plan, s domain.Source, requested map[string]bool) string {
+	if comparingCommands[plan.Command] {
+		return domain.SourceCompared
+	}
+	if requested[s.URL] {
+		return domain.SourceRequested
+	}
+	reason := searchReasons[plan.Command]
+	if reason == domain.SourceMatchedTopic {
+		// A digest can be asked for by filters alone
+		if topic, _ := plan.Args["filter"].(string); topic == "" {
+			return domain.SourceMatchedFilter
+		}
+	}
+	if reason == "" {
+		return domain.SourceMatchedFilter
+	}
+	return reason
+}
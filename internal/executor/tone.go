@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const maxToneArticles = 20
+
+// FilterByTone Command lists articles with a given tone, or counts articles per tone
+// when no tone is given. Tone is read from the stored analysis, without LLM calls.
+type FilterByToneCommand struct {
+	Repo              repository.ArticleStore
+	ResponseGenerator *ResponseGenerator
+}
+
+func (c *FilterByToneCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	targetURLs, ok, err := scopeURLs(ctx, c.Repo, plan)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error applying filters"), nil
+	}
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, noFilterMatches), nil
+	}
+	tone, _ := plan.Args["tone"].(string)
+	tone = strings.ToLower(strings.TrimSpace(tone))
+
+	if tone == "" {
+		counts, err := c.Repo.GetToneCounts(ctx, targetURLs)
+		if err != nil {
+			return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving article tones"), nil
+		}
+		if len(counts) == 0 {
+			return c.ResponseGenerator.CreateErrorResponse(plan.Command, "No articles with a stored tone"), nil
+		}
+		return &domain.ChatResponse{
+			Answer:       FormatToneCounts(counts),
+			Sources:      []domain.Source{},
+			ResponseType: domain.ResponseData,
+			Task:         plan.Command,
+			Data:         counts,
+		}, nil
+	}
+
+	articles, err := c.Repo.GetArticlesByTone(ctx, tone, targetURLs, maxToneArticles)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving articles by tone"), nil
+	}
+	if len(articles) == 0 {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, fmt.Sprintf("No articles with a %s tone", tone)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Articles with a %s tone:\n", tone))
+	for i, a := range articles {
+		b.WriteString(fmt.Sprintf("%d. %s\n   %s\n   Tone: %s\n", i+1, a.Title, a.URL, a.Tone))
+	}
+	return c.ResponseGenerator.CreateArticleListResponse(ctx, strings.TrimSpace(b.String()), plan.Command, articles)
+}
+
+// FormatToneCounts renders per-tone article counts as a short text answer
+func FormatToneCounts(counts []domain.ToneCount) string {
+	total := 0
+	for _, tc := range counts {
+		total += tc.Articles
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Tone of %d articles:\n", total))
+	for _, tc := range counts {
+		b.WriteString(fmt.Sprintf("- %s: %d\n", tc.Tone, tc.Articles))
+	}
+	return strings.TrimSpace(b.String())
+}
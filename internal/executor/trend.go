@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTrendWindow = 30 * 24 * time.Hour
+	maxDailyTrendDays  = 45   // Longer windows are bucketed by week
+	trendChangeMin     = 0.05 // Smaller moves in average score are reported as stable
+)
+
+// SentimentTrend is the structured result of the sentiment_trend command
+type SentimentTrend struct {
+	Topic     string                   `json:"topic,omitempty"`
+	Interval  string                   `json:"interval"`
+	Since     time.Time                `json:"since"`
+	Direction string                   `json:"direction"` // "rising", "falling" or "stable"
+	Buckets   []domain.SentimentBucket `json:"buckets"`
+}
+
+// SentimentTrend Command
+type SentimentTrendCommand struct {
+	Repo              repository.ArticleStore
+	ResponseGenerator *ResponseGenerator
+	Now               func() time.Time // Optional clock for tests
+}
+
+func (c *SentimentTrendCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	now := time.Now()
+	if c.Now != nil {
+		now = c.Now()
+	}
+
+	var topic string
+	if filterVal, ok := plan.Args["filter"].(string); ok {
+		topic = strings.TrimSpace(filterVal)
+	}
+	since := now.Add(-defaultTrendWindow)
+	if v, ok := plan.Args["since"].(string); ok && strings.TrimSpace(v) != "" {
+		since = ResolveSince(v, now, nil, topic)
+	}
+	intervalArg, _ := plan.Args["interval"].(string)
+	interval, ok := TrendInterval(intervalArg, now.Sub(since))
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Unsupported interval: "+intervalArg+" (use day or week)"), nil
+	}
+
+	scoped, ok, err := scopeURLs(ctx, c.Repo, plan)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error applying filters"), nil
+	}
+	if !ok {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, noFilterMatches), nil
+	}
+	buckets, err := c.Repo.GetSentimentTrend(ctx, topic, scoped, since, interval)
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error retrieving sentiment trend"), nil
+	}
+	if len(buckets) == 0 {
+		subject := "the stored articles"
+		if topic != "" {
+			subject = fmt.Sprintf("'%s'", topic)
+		}
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command,
+			fmt.Sprintf("No articles about %s since %s", subject, since.Format("2006-01-02"))), nil
+	}
+
+	trend := SentimentTrend{
+		Topic:     topic,
+		Interval:  interval,
+		Since:     since,
+		Direction: TrendDirection(buckets),
+		Buckets:   buckets,
+	}
+	return &domain.ChatResponse{
+		Answer:       FormatSentimentTrend(trend),
+		Sources:      []domain.Source{},
+		ResponseType: domain.ResponseData,
+		Task:         plan.Command,
+		Data:         trend,
+	}, nil
+}
+
+// TrendInterval validates the requested bucket size; without one, windows up to
+// maxDailyTrendDays are bucketed by day and longer ones by week
+func TrendInterval(interval string, window time.Duration) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(interval)) {
+	case "day", "daily", "days":
+		return "day", true
+	case "week", "weekly", "weeks":
+		return "week", true
+	case "":
+		if window > maxDailyTrendDays*24*time.Hour {
+			return "week", true
+		}
+		return "day", true
+	}
+	return "", false
+}
+
+// TrendDirection compares the average score of the first and last buckets
+func TrendDirection(buckets []domain.SentimentBucket) string {
+	if len(buckets) < 2 {
+		return "stable"
+	}
+	delta := buckets[len(buckets)-1].AverageScore - buckets[0].AverageScore
+	switch {
+	case delta >= trendChangeMin:
+		return "rising"
+	case delta <= -trendChangeMin:
+		return "falling"
+	}
+	return "stable"
+}
+
+// FormatSentimentTrend renders a trend as a short text answer
+func FormatSentimentTrend(t SentimentTrend) string {
+	var b strings.Builder
+	total := 0
+	for _, bucket := range t.Buckets {
+		total += bucket.Articles
+	}
+	subject := "all articles"
+	if t.Topic != "" {
+		subject = "'" + t.Topic + "'"
+	}
+	first, last := t.Buckets[0], t.Buckets[len(t.Buckets)-1]
+	b.WriteString(fmt.Sprintf("Sentiment about %s since %s is %s (%d articles, average score %.2f → %.2f):\n",
+		subject, t.Since.Format("2006-01-02"), t.Direction, total, first.AverageScore, last.AverageScore))
+	for _, bucket := range t.Buckets {
+		label := bucket.Start.Format("2006-01-02")
+		if t.Interval == "week" {
+			label = "week of " + label
+		}
+		b.WriteString(fmt.Sprintf("- %s: %.2f (%d articles: %d positive, %d negative, %d neutral)\n",
+			label, bucket.AverageScore, bucket.Articles, bucket.Positive, bucket.Negative, bucket.Neutral))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"article-assistant/internal/budget"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// validationTimeout bounds the relevance call; when it times out every article is kept
+const validationTimeout = 30 * time.Second
+
+// Time budget thresholds for optional LLM steps: a step starts only with its minimum time
+// plus answerReserve left, so the answer can still be written after it
+const (
+	answerReserve     = 5 * time.Second
+	minValidationTime = 3 * time.Second
+	minLabelTime      = 3 * time.Second
+	minGroundingTime  = 3 * time.Second
+)
+
+// validateTopic asks the LLM in one call which articles explicitly discuss topic and returns
+// those, in their original order. If the call fails or its answer cannot be read, or the
+// request's time budget is too low to make it, every article is kept.
+func validateTopic(ctx context.Context, llmClient llm.Client, logger *slog.Logger, topic string, articles []domain.Article) []domain.Article {
+	if len(articles) == 0 {
+		return articles
+	}
+	if !budget.Allow(ctx, budget.StepTopicValidation, minValidationTime+answerReserve) {
+		logger.Info("time budget low, keeping articles without topic validation", "articles", len(articles))
+		return articles
+	}
+	prompt := relevancePrompt(topic, articles)
+	logger.Debug("validating article topics", "articles", len(articles), "prompt", prompt)
+
+	timeout := validationTimeout
+	if left, ok := budget.Remaining(ctx); ok {
+		timeout = min(timeout, left-answerReserve)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	response, err := generateTextWithMemo(callCtx, llmClient, prompt)
+	if err != nil {
+		// Include the articles if the LLM fails, to be safe
+		logger.Warn("topic validation failed, keeping articles", "articles", len(articles), "error", err)
+		return articles
+	}
+	logger.Debug("topic validation response", "response", response)
+
+	relevant, err := ParseRelevantIndexes(response)
+	if err != nil {
+		logger.Warn("unreadable topic validation response, keeping articles", "response", response, "error", err)
+		return articles
+	}
+	var validated []domain.Article
+	for i, article := range articles {
+		if relevant[i] {
+			validated = append(validated, article)
+		}
+	}
+	return validated
+}
+
+// relevancePrompt lists the numbered candidates and asks for the numbers of the relevant ones
+func relevancePrompt(topic string, articles []domain.Article) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Which of these articles explicitly discuss %s?\n", topic)
+	for i, a := range articles {
+		fmt.Fprintf(&b, "\n[%d] Title: %s\nSummary: %s\n", i, a.Title, a.Summary)
+	}
+	b.WriteString("\nAnswer with only a JSON array of the numbers of the articles that do, e.g. [0, 2], or [] if none do.")
+	return b.String()
+}
+
+// ParseRelevantIndexes reads the JSON array of article numbers in a relevance answer, allowing
+// code fences or text around it; with several arrays, the last one is the answer
+func ParseRelevantIndexes(response string) (map[int]bool, error) {
+	cleaned := llm.CleanJSONResponse(response)
+	end := strings.LastIndex(cleaned, "]")
+	start := strings.LastIndex(cleaned[:max(end, 0)], "[")
+	if start < 0 || end < 0 {
+		return nil, fmt.Errorf("no JSON array in response")
+	}
+	var indexes []int
+	if err := json.Unmarshal([]byte(cleaned[start:end+1]), &indexes); err != nil {
+		return nil, fmt.Errorf("failed to parse relevant articles: %w", err)
+	}
+	relevant := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		relevant[i] = true
+	}
+	return relevant, nil
+}
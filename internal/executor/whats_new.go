@@ -0,0 +1,174 @@
+package executor
+
+import (
+	"article-assistant/internal/commands"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"article-assistant/internal/session"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultWhatsNewWindow is used when there is no explicit or session reference point
+const defaultWhatsNewWindow = 24 * time.Hour
+
+// WhatsNew Command
+type WhatsNewCommand struct {
+	Repo              repository.ArticleStore
+	LLM               llm.Client
+	ResponseGenerator *ResponseGenerator
+	Now               func() time.Time // Optional clock for tests
+}
+
+func (c *WhatsNewCommand) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+func (c *WhatsNewCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	var filter string
+	if filterVal, ok := plan.Args["filter"].(string); ok {
+		filter = strings.TrimSpace(filterVal)
+	}
+	if filter == "" {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Topic required for what's new"), nil
+	}
+
+	since, _ := plan.Args["since"].(string)
+	now := c.now()
+	memo := session.FromContext(ctx)
+	ref := ResolveSince(since, now, memo, filter)
+
+	embedding, err := embedWithMemo(ctx, c.LLM, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	// Articles re-ingested or refreshed with changed content since ref are new too
+	articleFilter := extractArticleFilter(plan)
+	articleFilter.UpdatedAfter = ref
+	articles, err := c.Repo.GetArticlesByVectorSearchWithFilter(ctx, embedding, 5, []string{}, articleFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(articles) == 0 {
+		markAsked(memo, filter, now)
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command,
+			fmt.Sprintf("Nothing new about '%s' since %s", filter, ref.Format(time.RFC1123))), nil
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(fmt.Sprintf("The user last checked on '%s' at %s. Write a short update covering only what is new in these articles; do not repeat background they would already know. %s\n\n",
+		filter, ref.Format(time.RFC1123), citationInstruction))
+	for i, a := range articles {
+		prompt.WriteString(fmt.Sprintf("[%d] %s (updated %s)\n%s\n\n", i+1, a.Title, a.UpdatedAt.Format(time.RFC1123), a.Summary))
+	}
+
+	update, err := c.LLM.GenerateText(ctx, prompt.String())
+	if err != nil {
+		return c.ResponseGenerator.CreateErrorResponse(plan.Command, "Error generating update"), nil
+	}
+
+	resp, err := c.ResponseGenerator.CreateArticleListResponse(ctx, update, plan.Command, articles)
+	if err != nil {
+		return nil, err
+	}
+	markAsked(memo, filter, now)
+	return resp, nil
+}
+
+// markAsked records a question that was answered, so the next follow-up only sees newer
+// articles. Failed answers leave the reference point where it was.
+func markAsked(memo *session.Memo, topic string, at time.Time) {
+	if memo != nil {
+		memo.MarkAsked(topic, at)
+	}
+}
+
+// ResolveSince turns a "since" argument into a reference time. Supported values are those of
+// ParseDate. An empty value or "last_asked" uses the session's last question on the topic,
+// falling back to the default window.
+func ResolveSince(since string, now time.Time, memo *session.Memo, topic string) time.Time {
+	switch strings.ToLower(strings.TrimSpace(since)) {
+	case "", "last_asked":
+		if memo != nil {
+			if t, ok := memo.LastAsked(topic); ok {
+				return t
+			}
+		}
+		return now.Add(-defaultWhatsNewWindow)
+	}
+	if t, ok := ParseDate(since, now); ok {
+		return t
+	}
+	return now.Add(-defaultWhatsNewWindow)
+}
+
+// TimeRelative reports whether a plan's answer depends on when it is asked or on the session's
+// history: commands marked Relative, and date args such as "last_week" that move with the
+// clock. Such answers must not be cached.
+func TimeRelative(plan *domain.Plan) bool {
+	if cmd, ok := commands.Lookup(plan.Command); ok && cmd.Relative {
+		return true
+	}
+	for _, arg := range []string{"since", "published_after", "published_before"} {
+		if v, ok := plan.Args[arg].(string); ok && strings.TrimSpace(v) != "" && !absoluteDate(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// absoluteDate reports whether a date arg names a fixed point in time
+func absoluteDate(value string) bool {
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// dateLayouts are the absolute date formats ParseDate accepts
+var dateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// ParseDate resolves a date argument relative to now. Supported values are "today",
+// "yesterday", "last_week", "last_month", durations ago such as "6h" or "3d", RFC 3339
+// timestamps and dates.
+func ParseDate(value string, now time.Time) (time.Time, bool) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "today":
+		return startOfDay, true
+	case "yesterday":
+		return startOfDay.AddDate(0, 0, -1), true
+	case "last_week", "week":
+		return now.AddDate(0, 0, -7), true
+	case "last_month", "month":
+		return now.AddDate(0, -1, 0), true
+	}
+
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil {
+			return now.AddDate(0, 0, -days), true
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), true
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
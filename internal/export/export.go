@@ -0,0 +1,165 @@
+// Package export writes the article corpus as JSON Lines or CSV for analysis outside the
+// service, e.g. in notebooks.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"article-assistant/internal/domain"
+)
+
+// Supported formats
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// Record is the exported form of an article: its metadata and analysis without the full text
+type Record struct {
+	ID             string                   `json:"id"`
+	URL            string                   `json:"url"`
+	Title          string                   `json:"title"`
+	SourceDomain   string                   `json:"source_domain"`
+	Author         string                   `json:"author"`
+	Section        string                   `json:"section"`
+	Language       string                   `json:"language"`
+	PublishedAt    *time.Time               `json:"published_at"`
+	CreatedAt      time.Time                `json:"created_at"`
+	Summary        string                   `json:"summary"`
+	Sentiment      string                   `json:"sentiment"`
+	SentimentScore float64                  `json:"sentiment_score"`
+	Tone           string                   `json:"tone"`
+	Entities       []domain.SemanticEntity  `json:"entities"`
+	Keywords       []domain.SemanticKeyword `json:"keywords"`
+	Topics         []domain.SemanticTopic   `json:"topics"`
+	Embedding      []float32                `json:"embedding,omitempty"`
+}
+
+// NewRecord converts an article, leaving out the embedding unless withEmbedding is true.
+// Missing lists are exported as empty rather than null.
+func NewRecord(a domain.Article, withEmbedding bool) Record {
+	r := Record{
+		ID: a.ID, URL: a.URL, Title: a.Title, SourceDomain: a.SourceDomain,
+		Author: a.Author, Section: a.Section, Language: a.Language,
+		PublishedAt: a.PublishedAt, CreatedAt: a.CreatedAt, Summary: a.Summary,
+		Sentiment: a.Sentiment, SentimentScore: a.SentimentScore, Tone: a.Tone,
+		Entities: a.Entities, Keywords: a.Keywords, Topics: a.Topics,
+	}
+	if r.Entities == nil {
+		r.Entities = []domain.SemanticEntity{}
+	}
+	if r.Keywords == nil {
+		r.Keywords = []domain.SemanticKeyword{}
+	}
+	if r.Topics == nil {
+		r.Topics = []domain.SemanticTopic{}
+	}
+	if withEmbedding {
+		r.Embedding = a.Embedding
+	}
+	return r
+}
+
+// Writer encodes articles one at a time; Flush must be called after the last one
+type Writer interface {
+	Write(a domain.Article) error
+	Flush() error
+}
+
+// NewWriter returns a writer for format that writes to w
+func NewWriter(w io.Writer, format string, withEmbeddings bool) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w), embeddings: withEmbeddings}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), embeddings: withEmbeddings}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q (use %s or %s)", format, FormatJSONL, FormatCSV)
+}
+
+// ContentType returns the MIME type of format
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// jsonlWriter writes one JSON record per line
+type jsonlWriter struct {
+	enc        *json.Encoder
+	embeddings bool
+}
+
+func (j *jsonlWriter) Write(a domain.Article) error {
+	return j.enc.Encode(NewRecord(a, j.embeddings))
+}
+
+func (j *jsonlWriter) Flush() error { return nil }
+
+// csvColumns is the CSV header; list columns hold JSON arrays
+var csvColumns = []string{"id", "url", "title", "source_domain", "author", "section", "language",
+	"published_at", "created_at", "summary", "sentiment", "sentiment_score", "tone", "entities", "keywords", "topics"}
+
+// csvWriter writes a header row followed by one row per article
+type csvWriter struct {
+	w             *csv.Writer
+	embeddings    bool
+	headerWritten bool
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	header := csvColumns
+	if c.embeddings {
+		header = append(append([]string{}, csvColumns...), "embedding")
+	}
+	return c.w.Write(header)
+}
+
+func (c *csvWriter) Write(a domain.Article) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	r := NewRecord(a, c.embeddings)
+	published := ""
+	if r.PublishedAt != nil {
+		published = r.PublishedAt.Format(time.RFC3339)
+	}
+	row := []string{r.ID, r.URL, r.Title, r.SourceDomain, r.Author, r.Section, r.Language,
+		published, r.CreatedAt.Format(time.RFC3339), r.Summary, r.Sentiment,
+		strconv.FormatFloat(r.SentimentScore, 'f', -1, 64), r.Tone}
+	lists := []interface{}{r.Entities, r.Keywords, r.Topics}
+	if c.embeddings {
+		embedding := r.Embedding
+		if embedding == nil {
+			embedding = []float32{}
+		}
+		lists = append(lists, embedding)
+	}
+	for _, l := range lists {
+		data, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		row = append(row, string(data))
+	}
+	return c.w.Write(row)
+}
+
+// Flush writes buffered rows, and the header if no article was written
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,95 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"article-assistant/internal/domain"
+)
+
+const (
+	// ChunkChars is the target size of a retrieval chunk: a few paragraphs
+	ChunkChars = 1200
+	// maxChunksPerArticle bounds embedding calls per article; later text is not indexed
+	maxChunksPerArticle = 30
+)
+
+// ChunkText splits extracted article text into chunks of about maxChars, keeping
+// paragraphs together where possible. Paragraphs longer than maxChars are split at
+// sentence ends, or at spaces when a sentence is itself too long.
+func ChunkText(text string, maxChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			chunks = append(chunks, s)
+		}
+		current.Reset()
+	}
+
+	for _, para := range strings.Split(text, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		for _, piece := range splitLong(para, maxChars) {
+			if current.Len() > 0 && current.Len()+len(piece)+2 > maxChars {
+				flush()
+			}
+			if current.Len() > 0 {
+				current.WriteString("\n\n")
+			}
+			current.WriteString(piece)
+		}
+	}
+	flush()
+	return chunks
+}
+
+// splitLong breaks a paragraph into pieces of at most maxChars
+func splitLong(para string, maxChars int) []string {
+	if len(para) <= maxChars {
+		return []string{para}
+	}
+
+	var pieces []string
+	for len(para) > maxChars {
+		cut := strings.LastIndexAny(para[:maxChars], ".!?")
+		if cut < maxChars/2 {
+			cut = strings.LastIndex(para[:maxChars], " ")
+		}
+		if cut <= 0 {
+			// No break point (e.g. CJK text): cut at the last rune boundary
+			cut = maxChars
+			for cut > 0 && !utf8.RuneStart(para[cut]) {
+				cut--
+			}
+			cut--
+		}
+		pieces = append(pieces, strings.TrimSpace(para[:cut+1]))
+		para = strings.TrimSpace(para[cut+1:])
+	}
+	if para != "" {
+		pieces = append(pieces, para)
+	}
+	return pieces
+}
+
+// indexChunks embeds the article's text in chunks and stores them for retrieval
+func (s *Service) indexChunks(ctx context.Context, a *domain.Article) error {
+	chunks := ChunkText(a.Content, ChunkChars)
+	if len(chunks) > maxChunksPerArticle {
+		chunks = chunks[:maxChunksPerArticle]
+	}
+	embeddings := make([][]float32, len(chunks))
+	for i, chunk := range chunks {
+		emb, err := s.LLM.Embed(ctx, chunk)
+		if err != nil {
+			return fmt.Errorf("failed to embed chunk %d: %w", i, err)
+		}
+		embeddings[i] = emb
+	}
+	return s.Repo.ReplaceArticleChunks(ctx, a.ID, chunks, embeddings)
+}
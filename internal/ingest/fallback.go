@@ -0,0 +1,288 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"article-assistant/internal/logging"
+)
+
+// DefaultMinTextLength is the extracted text length, in characters, below which a page is
+// treated as truncated and the fallback strategies are tried
+const DefaultMinTextLength = 500
+
+// FetchStrategy finds an alternate copy of an article when the page itself is paywalled or
+// truncated, e.g. its AMP version or an archived snapshot
+type FetchStrategy interface {
+	Name() string
+	// AlternateURL returns where to fetch the article instead, or "" if the strategy has no copy.
+	// primaryHTML is the page as first fetched.
+	AlternateURL(ctx context.Context, client *http.Client, articleURL, primaryHTML string) (string, error)
+}
+
+// DefaultFetchStrategies returns the strategies in the order they are tried:
+// the AMP version, then Google's cache, then the latest archive.org snapshot
+func DefaultFetchStrategies() []FetchStrategy {
+	return []FetchStrategy{AMPStrategy{}, GoogleCacheStrategy{}, ArchiveStrategy{}}
+}
+
+// FetchStrategyByName returns a default strategy by its name, or nil if there is none
+func FetchStrategyByName(name string) FetchStrategy {
+	for _, s := range DefaultFetchStrategies() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Fallback tries alternate copies of paywalled or truncated pages and counts per-strategy results
+type Fallback struct {
+	Strategies    []FetchStrategy
+	MinTextLength int // 0 uses DefaultMinTextLength
+
+	mu    sync.Mutex
+	stats map[string]*StrategyStats
+}
+
+// StrategyStats counts a strategy's attempts and the ones that replaced the page
+type StrategyStats struct {
+	Strategy  string `json:"strategy"`
+	Attempts  int64  `json:"attempts"`
+	Successes int64  `json:"successes"`
+}
+
+// NewFallback creates a fallback trying strategies in order
+func NewFallback(strategies ...FetchStrategy) *Fallback {
+	return &Fallback{Strategies: strategies}
+}
+
+var (
+	// schema.org marks paywalled articles with isAccessibleForFree: false
+	notFreeRe = regexp.MustCompile(`(?i)"isAccessibleForFree"\s*:\s*"?false`)
+	paywallRe = regexp.MustCompile(`(?i)(?:class|id)\s*=\s*["'][^"']*\bpaywall|subscribe (?:now )?to (?:continue|keep) reading|subscriber[- ]only`)
+)
+
+// Paywalled reports whether a page carries a paywall indicator
+func Paywalled(rawHTML string) bool {
+	return notFreeRe.MatchString(rawHTML) || paywallRe.MatchString(rawHTML)
+}
+
+// NeedsFallback reports whether a page looks paywalled or its extracted text is too short
+func (f *Fallback) NeedsFallback(rawHTML, text string) bool {
+	return Paywalled(rawHTML) || utf8.RuneCountInString(text) < f.minTextLength()
+}
+
+func (f *Fallback) minTextLength() int {
+	if f.MinTextLength > 0 {
+		return f.MinTextLength
+	}
+	return DefaultMinTextLength
+}
+
+// apply returns page, or the first alternate copy that is free of paywall indicators and
+// has more text. Copies at URLs policy refuses are skipped. Strategy failures are logged and
+// the next strategy is tried.
+func (f *Fallback) apply(ctx context.Context, client *http.Client, policy *URLPolicy, articleURL string, page *ContentInfo) *ContentInfo {
+	text := ExtractArticleText(page.HTML)
+	if !f.NeedsFallback(page.HTML, text) {
+		return page
+	}
+	logger := logging.FromContext(ctx).With("url", articleURL)
+	primaryLength := utf8.RuneCountInString(text)
+
+	for _, s := range f.Strategies {
+		alt, err := s.AlternateURL(ctx, client, articleURL, page.HTML)
+		if err != nil {
+			logger.Warn("fetch strategy failed", "strategy", s.Name(), "error", err)
+			f.record(s.Name(), false)
+			continue
+		}
+		if alt == "" {
+			continue
+		}
+		if err := policy.Check(alt); err != nil {
+			logger.Warn("fetch strategy failed", "strategy", s.Name(), "error", err)
+			f.record(s.Name(), false)
+			continue
+		}
+		candidate, err := fetchHTMLWithHeaders(ctx, client, alt, "")
+		if err != nil {
+			logger.Warn("fetch strategy failed", "strategy", s.Name(), "error", err)
+			f.record(s.Name(), false)
+			continue
+		}
+		if Paywalled(candidate.HTML) || utf8.RuneCountInString(ExtractArticleText(candidate.HTML)) <= primaryLength {
+			logger.Debug("fetch strategy returned no more text", "strategy", s.Name())
+			f.record(s.Name(), false)
+			continue
+		}
+
+		f.record(s.Name(), true)
+		logger.Info("using fallback copy of article", "strategy", s.Name(), "fallback_url", alt)
+		// The primary ETag still identifies the article for conditional refreshes
+		return &ContentInfo{
+			HTML:      candidate.HTML,
+			Title:     firstNonEmpty(page.Title, candidate.Title),
+			ETag:      page.ETag,
+			FetchedAt: candidate.FetchedAt,
+			Strategy:  s.Name(),
+		}
+	}
+	return page
+}
+
+func (f *Fallback) record(strategy string, success bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stats == nil {
+		f.stats = make(map[string]*StrategyStats)
+	}
+	st, ok := f.stats[strategy]
+	if !ok {
+		st = &StrategyStats{Strategy: strategy}
+		f.stats[strategy] = st
+	}
+	st.Attempts++
+	if success {
+		st.Successes++
+	}
+}
+
+// Stats returns the counters of each strategy tried so far, by name
+func (f *Fallback) Stats() []StrategyStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]StrategyStats, 0, len(f.stats))
+	for _, st := range f.stats {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Strategy < out[j].Strategy })
+	return out
+}
+
+// WritePrometheus writes per-strategy attempts and successes in Prometheus text format
+func (f *Fallback) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# HELP fetch_fallback_attempts_total Fallback fetches tried for paywalled or truncated pages.\n")
+	b.WriteString("# TYPE fetch_fallback_attempts_total counter\n")
+	stats := f.Stats()
+	for _, st := range stats {
+		fmt.Fprintf(&b, "fetch_fallback_attempts_total{strategy=%q} %d\n", st.Strategy, st.Attempts)
+	}
+	b.WriteString("# HELP fetch_fallback_successes_total Fallback fetches that replaced the page.\n")
+	b.WriteString("# TYPE fetch_fallback_successes_total counter\n")
+	for _, st := range stats {
+		fmt.Fprintf(&b, "fetch_fallback_successes_total{strategy=%q} %d\n", st.Strategy, st.Successes)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ---------- Strategies ----------
+
+var linkTagRe = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+
+// AMPStrategy follows the page's <link rel="amphtml">, which publishers often serve without
+// the paywall script
+type AMPStrategy struct{}
+
+func (AMPStrategy) Name() string { return "amp" }
+
+func (AMPStrategy) AlternateURL(ctx context.Context, client *http.Client, articleURL, primaryHTML string) (string, error) {
+	for _, tag := range linkTagRe.FindAllString(primaryHTML, -1) {
+		attrs := make(map[string]string)
+		for _, m := range metaAttrRe.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(strings.TrimSpace(m[2] + m[3]))
+		}
+		if !strings.EqualFold(attrs["rel"], "amphtml") || attrs["href"] == "" {
+			continue
+		}
+		base, err := url.Parse(articleURL)
+		if err != nil {
+			return "", err
+		}
+		ref, err := url.Parse(attrs["href"])
+		if err != nil {
+			return "", fmt.Errorf("invalid amphtml link: %w", err)
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+	return "", nil
+}
+
+// GoogleCacheStrategy fetches Google's cached copy of the page
+type GoogleCacheStrategy struct {
+	Endpoint string // Defaults to Google's cache URL prefix
+}
+
+func (GoogleCacheStrategy) Name() string { return "google_cache" }
+
+func (g GoogleCacheStrategy) AlternateURL(ctx context.Context, client *http.Client, articleURL, primaryHTML string) (string, error) {
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = "https://webcache.googleusercontent.com/search"
+	}
+	return endpoint + "?q=" + url.QueryEscape("cache:"+articleURL), nil
+}
+
+// ArchiveStrategy fetches the closest archive.org snapshot, without the Wayback toolbar
+type ArchiveStrategy struct {
+	Endpoint string // Availability API; defaults to archive.org's
+}
+
+func (ArchiveStrategy) Name() string { return "archive" }
+
+func (a ArchiveStrategy) AlternateURL(ctx context.Context, client *http.Client, articleURL, primaryHTML string) (string, error) {
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = "https://archive.org/wayback/available"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?url="+url.QueryEscape(articleURL), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "ArticleAssistant/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("archive availability returned %s", resp.Status)
+	}
+
+	var body struct {
+		ArchivedSnapshots struct {
+			Closest struct {
+				Available bool   `json:"available"`
+				URL       string `json:"url"`
+				Timestamp string `json:"timestamp"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid archive availability response: %w", err)
+	}
+	closest := body.ArchivedSnapshots.Closest
+	if !closest.Available || closest.URL == "" {
+		return "", nil
+	}
+	// The id_ flag serves the original page without the archive's rewriting and toolbar
+	if closest.Timestamp != "" {
+		if prefix, rest, ok := strings.Cut(closest.URL, "/"+closest.Timestamp+"/"); ok {
+			return prefix + "/" + closest.Timestamp + "id_/" + rest, nil
+		}
+	}
+	return closest.URL, nil
+}
@@ -0,0 +1,338 @@
+package ingest
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Fetch defaults
+const (
+	DefaultFetchTimeout      = 30 * time.Second
+	DefaultFetchMaxRedirects = 5
+	DefaultFetchMaxAttempts  = 3
+	DefaultFetchBackoff      = 500 * time.Millisecond
+	DefaultFetchMaxBytes     = 10 << 20
+
+	// maxRetryAfter caps how long a Retry-After header can delay a retry
+	maxRetryAfter = 30 * time.Second
+
+	// maxPageSize caps how much of a page is read, whatever FetchOptions.MaxBytes says
+	maxPageSize = 50 << 20
+)
+
+// Fetch error categories, reported in ingest job status
+const (
+	FetchNotFound         = "not_found"          // 404 or 410
+	FetchBlocked          = "blocked"            // 401, 403, 451 or disallowed by robots.txt
+	FetchRateLimited      = "rate_limited"       // 429
+	FetchServerError      = "server_error"       // 5xx
+	FetchHTTPError        = "http_error"         // Any other non-success status
+	FetchTimeout          = "timeout"            // No complete response in time
+	FetchNetwork          = "network"            // DNS, connection or TLS failure
+	FetchTooManyRedirects = "too_many_redirects" // Redirect chain longer than allowed
+	FetchTooLarge         = "too_large"          // Page longer than FetchOptions.MaxBytes
+	FetchModerated        = "moderated"          // Content flagged by moderation
+	FetchNotAllowed       = "not_allowed"        // URL policy, a non-public address or a non-http(s) redirect
+)
+
+// FetchError is a failed article fetch with its category
+type FetchError struct {
+	URL        string
+	Category   string
+	StatusCode int // Set for HTTP status failures
+	Err        error
+}
+
+func (e *FetchError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("fetching %s: %s (status %d)", e.URL, e.Category, e.StatusCode)
+	}
+	return fmt.Sprintf("fetching %s: %s: %v", e.URL, e.Category, e.Err)
+}
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// FetchErrorCategory returns the category of a fetch failure in err's chain, or "" if err
+// is not a fetch failure
+func FetchErrorCategory(err error) string {
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		return fetchErr.Category
+	}
+	return ""
+}
+
+// errTooManyRedirects is returned by the redirect policy when the chain is too long
+var errTooManyRedirects = errors.New("too many redirects")
+
+// FetchOptions configures NewFetchClient; zero values use the defaults
+type FetchOptions struct {
+	Timeout      time.Duration // Per attempt, from sending the request to reading the body
+	MaxRedirects int
+	MaxAttempts  int           // Total attempts on transient failures, including the first
+	Backoff      time.Duration // Delay before the first retry; doubles each retry
+	MaxBytes     int64         // Longest page read, up to 50 MB; longer ones fail with FetchTooLarge
+
+	// AllowPrivate permits fetches from loopback, private and link-local addresses, e.g.
+	// intranet sources; they are refused by default so submitted URLs cannot reach internal
+	// services or cloud metadata endpoints
+	AllowPrivate bool
+}
+
+// NewFetchClient returns the HTTP client used to fetch articles: transient failures (network
+// errors, timeouts, 429 and 5xx) are retried with backoff, redirects are limited to http(s)
+// URLs, pages are capped in size, non-public addresses are refused unless allowed, and
+// requests go through the Polite middleware. Waiting for a polite turn does not count toward
+// the timeout.
+func NewFetchClient(opts FetchOptions, politeness PolitenessOptions) *http.Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultFetchTimeout
+	}
+	if opts.MaxRedirects <= 0 {
+		opts.MaxRedirects = DefaultFetchMaxRedirects
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultFetchMaxAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultFetchBackoff
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultFetchMaxBytes
+	}
+	opts.MaxBytes = min(opts.MaxBytes, maxPageSize)
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.ResponseHeaderTimeout = opts.Timeout
+	if !opts.AllowPrivate {
+		// Checked on the resolved address of every connection; a proxy would resolve hosts
+		// itself, out of reach of the check
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialPublic}
+		base.DialContext = dialer.DialContext
+		base.Proxy = nil
+	}
+	retrying := &retryTransport{next: base, opts: opts}
+
+	return &http.Client{
+		Transport: Polite(retrying, politeness),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > opts.MaxRedirects {
+				return errTooManyRedirects
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errRedirectScheme
+			}
+			return nil
+		},
+	}
+}
+
+// retryTransport retries idempotent requests on transient failures, bounding each attempt
+type retryTransport struct {
+	next http.RoundTripper
+	opts FetchOptions
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	parent := req.Context()
+	backoff := t.opts.Backoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(parent, t.opts.Timeout)
+		resp, err := t.next.RoundTrip(req.WithContext(ctx))
+
+		last := attempt >= t.opts.MaxAttempts || req.Method != http.MethodGet
+		switch {
+		case err != nil:
+			cancel()
+			if last || parent.Err() != nil || !retryableNetError(err) {
+				return nil, err
+			}
+		case resp.StatusCode < 300 && resp.ContentLength > t.opts.MaxBytes:
+			resp.Body.Close()
+			cancel()
+			return nil, fmt.Errorf("%w: %d bytes", errTooLarge, resp.ContentLength)
+		case retryableStatus(resp.StatusCode):
+			if last {
+				resp.Body = &cancelingBody{ReadCloser: &limitedBody{ReadCloser: resp.Body, left: t.opts.MaxBytes}, cancel: cancel}
+				return resp, nil
+			}
+			if wait := retryAfter(resp); wait > backoff {
+				backoff = wait
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			cancel()
+		default:
+			// The attempt's deadline also bounds reading the body
+			resp.Body = &cancelingBody{ReadCloser: &limitedBody{ReadCloser: resp.Body, left: t.opts.MaxBytes}, cancel: cancel}
+			return resp, nil
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-parent.Done():
+			timer.Stop()
+			return nil, parent.Err()
+		}
+		backoff *= 2
+	}
+}
+
+type cancelingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelingBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryableNetError(err error) bool {
+	if errors.Is(err, ErrPrivateAddress) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryAfter reads a Retry-After header given in seconds, capped at maxRetryAfter
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return min(time.Duration(secs)*time.Second, maxRetryAfter)
+}
+
+// fetchHTMLWithHeaders fetches HTML content; a non-empty etag makes the request conditional.
+// Failures are returned as *FetchError. The page is decoded to UTF-8 from its declared charset.
+func fetchHTMLWithHeaders(ctx context.Context, client *http.Client, url, etag string) (*ContentInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "ArticleAssistant/1.0")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, classifyFetchError(url, err)
+	}
+	defer resp.Body.Close()
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return &ContentInfo{ETag: etag, NotModified: true, FetchedAt: time.Now()}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &FetchError{URL: url, Category: statusCategory(resp.StatusCode), StatusCode: resp.StatusCode}
+	}
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, classifyFetchError(url, err)
+	}
+
+	html := string(body)
+	title := ExtractBetween(html, "<title>", "</title>")
+
+	contentInfo := &ContentInfo{
+		HTML:      html,
+		Title:     strings.TrimSpace(title),
+		ETag:      resp.Header.Get("ETag"),
+		FetchedAt: time.Now(),
+	}
+
+	return contentInfo, nil
+}
+
+// readBody reads a page, decompressing gzip the transport left encoded (e.g. served without
+// being requested) and converting non-UTF-8 charsets
+func readBody(resp *http.Response) ([]byte, error) {
+	var r io.Reader = resp.Body
+	if enc := strings.ToLower(resp.Header.Get("Content-Encoding")); !resp.Uncompressed && (enc == "gzip" || enc == "x-gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	body, err := io.ReadAll(io.LimitReader(r, maxPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// Uses the Content-Type charset, then <meta> declarations, then content sniffing
+	encoding, name, _ := charset.DetermineEncoding(body, resp.Header.Get("Content-Type"))
+	if name == "utf-8" {
+		return body, nil
+	}
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, nil
+	}
+	return decoded, nil
+}
+
+func statusCategory(code int) string {
+	switch {
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return FetchNotFound
+	case code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusUnavailableForLegalReasons:
+		return FetchBlocked
+	case code == http.StatusTooManyRequests:
+		return FetchRateLimited
+	case code >= 500:
+		return FetchServerError
+	}
+	return FetchHTTPError
+}
+
+// classifyFetchError wraps a transport or body read failure; cancellation is returned as is
+func classifyFetchError(url string, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	category := FetchNetwork
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrDisallowed):
+		category = FetchBlocked
+	case errors.Is(err, errTooManyRedirects):
+		category = FetchTooManyRedirects
+	case errors.Is(err, ErrPrivateAddress), errors.Is(err, errRedirectScheme), errors.Is(err, ErrURLNotAllowed):
+		category = FetchNotAllowed
+	case errors.Is(err, errTooLarge):
+		category = FetchTooLarge
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		category = FetchTimeout
+	}
+	return &FetchError{URL: url, Category: category, Err: err}
+}
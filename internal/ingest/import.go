@@ -0,0 +1,159 @@
+package ingest
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/language"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/moderation"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportedArticle is already-extracted article content, e.g. from a paywalled or internal
+// source that cannot be scraped. Summary and Analysis are optional; missing parts are
+// generated as for a fetched article.
+type ImportedArticle struct {
+	URL         string                   `json:"url"`
+	Title       string                   `json:"title"`
+	Text        string                   `json:"text"`
+	Summary     string                   `json:"summary,omitempty"`
+	Author      string                   `json:"author,omitempty"`
+	Section     string                   `json:"section,omitempty"`
+	PublishedAt *time.Time               `json:"published_at,omitempty"`
+	Analysis    *domain.SemanticAnalysis `json:"analysis,omitempty"`
+}
+
+// Import stores an article from supplied content without fetching its URL, which must be an
+// http(s) URL the URL policy allows. An article already stored at the URL is replaced; a new
+// one is checked for duplicates like a fetched article. The returned article has CanonicalID
+// set when it was linked as a duplicate instead of stored. Imported articles are never
+// re-fetched by refresh.
+func (s *Service) Import(ctx context.Context, in ImportedArticle) (*domain.Article, error) {
+	in.URL, in.Title, in.Text = strings.TrimSpace(in.URL), strings.TrimSpace(in.Title), strings.TrimSpace(in.Text)
+	if in.URL == "" || in.Title == "" || in.Text == "" {
+		return nil, fmt.Errorf("url, title and text are required")
+	}
+	// The URL is stored and linked to as is, so it must be a web address like a fetched one
+	if u, err := url.Parse(in.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q is not an http(s) URL", ErrURLNotAllowed, in.URL)
+	}
+	if err := s.URLPolicy.Check(in.URL); err != nil {
+		return nil, err
+	}
+	logger := logging.FromContext(ctx).With("url", in.URL)
+
+	existing, err := s.Repo.GetArticleByURL(ctx, in.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing article: %w", err)
+	}
+	dedup := existing == nil && s.duplicateThreshold() > 0
+
+	hash := ContentHash(in.Text)
+	if in.Text, err = s.screen(ctx, in.URL, in.Text); err != nil {
+		return nil, err
+	}
+	in.Summary, _ = moderation.Sanitize(in.Summary, s.Sanitize)
+	if dedup {
+		same, err := s.Repo.GetArticleByContentHash(ctx, hash, in.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for identical content: %w", err)
+		}
+		if same != nil {
+			logger.Info("identical imported content, linking to canonical", "canonical_id", same.ID)
+			dup := &domain.Article{URL: in.URL, Title: in.Title, CanonicalID: same.ID}
+			return dup, s.Repo.AddDuplicate(ctx, dup, 1)
+		}
+	}
+
+	sum, summaryVersion, summaryModel := strings.TrimSpace(in.Summary), domain.AnalysisSupplied, ""
+	if sum == "" {
+		summaryVersion, summaryModel = llm.SummaryVersion, s.SummaryModel
+		if sum, err = s.LLM.Summarize(ctx, in.Text, llm.SummaryOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to summarize: %w", err)
+		}
+	}
+
+	emb, err := s.LLM.Embed(ctx, sum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed: %w", err)
+	}
+
+	if dedup {
+		canonical, similarity, err := s.Repo.FindNearDuplicate(ctx, emb, in.URL, s.duplicateThreshold())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicates: %w", err)
+		}
+		if canonical != nil {
+			logger.Info("near-duplicate imported article, linking to canonical", "canonical_id", canonical.ID, "similarity", similarity)
+			dup := &domain.Article{URL: in.URL, Title: in.Title, CanonicalID: canonical.ID}
+			return dup, s.Repo.AddDuplicate(ctx, dup, similarity)
+		}
+	}
+
+	analysis, version, model := in.Analysis, domain.AnalysisSupplied, ""
+	if analysis == nil {
+		version, model = llm.SemanticsVersion, s.AnalysisModel
+		if analysis, err = s.LLM.ExtractAllSemantics(ctx, sum); err != nil {
+			logger.Warn("failed to extract semantic data", "error", err)
+			analysis, version, model = &domain.SemanticAnalysis{Sentiment: "neutral", SentimentScore: 0.5}, "", ""
+		}
+	}
+
+	now := time.Now()
+	a := &domain.Article{
+		ID:              uuid.New().String(),
+		URL:             in.URL,
+		Title:           in.Title,
+		Summary:         sum,
+		SummaryVersion:  summaryVersion,
+		SummaryModel:    summaryModel,
+		Content:         in.Text,
+		Embedding:       emb,
+		EmbeddingModel:  s.EmbeddingModel,
+		Entities:        s.entities().Normalize(ctx, analysis.Entities),
+		Keywords:        nonNil(analysis.Keywords),
+		Topics:          nonNil(analysis.Topics),
+		TopicIDs:        s.mapTopics(ctx, sum, analysis.Topics),
+		Sentiment:       analysis.Sentiment,
+		SentimentScore:  analysis.SentimentScore,
+		Tone:            analysis.Tone,
+		AnalysisVersion: version,
+		AnalysisModel:   model,
+		URLHash:         calculateURLHash(in.URL),
+		Author:          strings.TrimSpace(in.Author),
+		Section:         strings.TrimSpace(in.Section),
+		PublishedAt:     in.PublishedAt,
+		SourceDomain:    SourceDomain(in.URL),
+		Language:        language.Detect(in.Text),
+		ContentHash:     hash,
+		LastRefreshedAt: &now,
+		Imported:        true,
+	}
+	if err := s.Repo.UpsertArticle(ctx, a); err != nil {
+		return nil, err
+	}
+
+	if err := s.indexChunks(ctx, a); err != nil {
+		logger.Warn("failed to index article passages", "error", err)
+	}
+	if existing != nil {
+		if err := s.Repo.ClearSimplifiedSummaries(ctx, in.URL); err != nil {
+			logger.Warn("failed to clear simplified summaries", "error", err)
+		}
+	}
+	return a, nil
+}
+
+// nonNil stores missing analysis lists as empty rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
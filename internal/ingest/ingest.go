@@ -1,13 +1,18 @@
 package ingest
 
 import (
+	"article-assistant/internal/analysis"
 	"article-assistant/internal/domain"
+	"article-assistant/internal/entity"
+	"article-assistant/internal/language"
 	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/moderation"
 	"article-assistant/internal/repository"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strings"
 
@@ -15,38 +20,346 @@ import (
 )
 
 type Service struct {
-	Repo *repository.Repo
+	Repo repository.ArticleStore
 	LLM  llm.Client
+
+	// Client fetches article pages; defaults to http.DefaultClient. NewFetchClient adds
+	// timeouts, retries and robots.txt politeness.
+	Client *http.Client
+
+	// Fallback, if set, tries alternate copies of paywalled or truncated pages
+	Fallback *Fallback
+
+	// Entities sets the canonical names of extracted entities; without it names are only trimmed
+	Entities *entity.Normalizer
+
+	// EmbeddingModel is recorded on stored articles as the model of their embeddings, so a
+	// corpus embedded with several models can be detected and migrated
+	EmbeddingModel string
+
+	// SummaryModel and AnalysisModel are recorded on stored articles next to the summary and
+	// semantic analysis versions, e.g. from llm.Config's SummaryModel and SemanticsModel
+	SummaryModel  string
+	AnalysisModel string
+
+	// Sanitize is the moderation strictness at which instruction-like text is removed from
+	// fetched and imported text before it is stored; empty means moderation.Standard
+	Sanitize string
+
+	// Moderator, if set, rejects fetched and imported articles it flags at the Sanitize strictness
+	Moderator moderation.Moderator
+
+	// URLPolicy, if set, limits the URLs fetched to its allowlist and denylist, including
+	// redirect targets and fallback copies
+	URLPolicy *URLPolicy
+
+	// TopicMatcher, if set, maps extracted topics to the managed topic taxonomy
+	TopicMatcher *analysis.AnalysisService
+
+	// MetadataExtractors is the extraction chain in priority order; defaults to DefaultMetadataExtractors()
+	MetadataExtractors []MetadataExtractor
+
+	// FailureHook, if set, runs before fetching a new article; a non-nil error aborts ingestion.
+	// Used for failure injection in resilience tests.
+	FailureHook func(ctx context.Context, url string) error
+
+	// OnContentChange, if set, runs after IngestURL re-analyzes a stored article whose content
+	// changed, e.g. to invalidate cached chat responses
+	OnContentChange func(ctx context.Context, url string)
+
+	// DuplicateThreshold is the embedding cosine similarity at or above which a new article is
+	// linked to an existing one as a near-duplicate instead of stored; 0 uses
+	// DefaultDuplicateThreshold and a negative value disables deduplication
+	DuplicateThreshold float64
+}
+
+// DefaultDuplicateThreshold catches syndicated copies and lightly edited reposts
+const DefaultDuplicateThreshold = 0.97
+
+func (s *Service) duplicateThreshold() float64 {
+	if s.DuplicateThreshold == 0 {
+		return DefaultDuplicateThreshold
+	}
+	return s.DuplicateThreshold
+}
+
+func (s *Service) entities() *entity.Normalizer {
+	if s.Entities != nil {
+		return s.Entities
+	}
+	return entity.NewNormalizer(nil)
+}
+
+// client returns the page fetching client. With a URL policy, redirects are checked against it
+// too, so an allowed URL cannot redirect to a denied one.
+func (s *Service) client() *http.Client {
+	c := s.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	if s.URLPolicy == nil {
+		return c
+	}
+	checked := *c
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := s.URLPolicy.Check(req.URL.String()); err != nil {
+			return err
+		}
+		if c.CheckRedirect != nil {
+			return c.CheckRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errTooManyRedirects
+		}
+		return nil
+	}
+	return &checked
+}
+
+// fetch fetches an article page, replacing a paywalled or truncated page with a fallback copy
+func (s *Service) fetch(ctx context.Context, url, etag string) (*ContentInfo, error) {
+	contentInfo, err := fetchHTMLWithHeaders(ctx, s.client(), url, etag)
+	if err != nil || contentInfo.NotModified || s.Fallback == nil {
+		return contentInfo, err
+	}
+	return s.Fallback.apply(ctx, s.client(), s.URLPolicy, url, contentInfo), nil
+}
+
+func (s *Service) metadataExtractors() []MetadataExtractor {
+	if len(s.MetadataExtractors) > 0 {
+		return s.MetadataExtractors
+	}
+	return DefaultMetadataExtractors()
 }
 
+// IngestURL ingests a new article. A stored article is re-fetched and re-analyzed only if
+// its extracted text changed; otherwise ingesting it again is a no-op.
 func (s *Service) IngestURL(ctx context.Context, url string) error {
-	// Calculate URL hash for caching
-	urlHash := calculateURLHash(url)
+	pending, err := s.FetchURL(ctx, url)
+	if err != nil || !pending {
+		return err
+	}
+	return s.EnrichURL(ctx, url)
+}
+
+// FetchURL is the fast first half of IngestURL: it fetches a new article and stores its text for
+// EnrichURL, without LLM calls. It reports whether the text awaits enrichment; stored articles
+// are refreshed as by IngestURL instead.
+func (s *Service) FetchURL(ctx context.Context, url string) (bool, error) {
+	logger := logging.FromContext(ctx).With("url", url)
+
+	if err := s.URLPolicy.Check(url); err != nil {
+		return false, &FetchError{URL: url, Category: FetchNotAllowed, Err: err}
+	}
 
 	// Check if article already exists
 	existingArticle, err := s.Repo.GetArticleByURL(ctx, url)
 	if err != nil {
-		return fmt.Errorf("failed to check existing article: %w", err)
+		return false, fmt.Errorf("failed to check existing article: %w", err)
+	}
+
+	// Articles still awaiting enrichment, or whose enrichment failed, are fetched again
+	if existingArticle != nil && existingArticle.Status != domain.ArticleEnriched {
+		existingArticle = nil
+	}
+
+	// Imported content has no fetchable source to compare against
+	if existingArticle != nil && existingArticle.Imported {
+		logger.Info("article was imported, skipping fetch")
+		return false, nil
 	}
 
-	// If article already exists, skip processing
+	// A stored article is only re-analyzed when its content hash changed
 	if existingArticle != nil {
-		log.Printf("📄 Article already processed, skipping: %s", url)
+		changed, err := s.Refresh(ctx, existingArticle)
+		if err != nil {
+			return false, err
+		}
+		if !changed {
+			logger.Info("article already processed and unchanged, skipping")
+			return false, nil
+		}
+		if s.OnContentChange != nil {
+			s.OnContentChange(ctx, url)
+		}
+		return false, nil
+	}
+
+	dup, err := s.Repo.GetDuplicateByURL(ctx, url)
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing duplicate: %w", err)
+	}
+	if dup != nil {
+		logger.Info("article already linked as duplicate, skipping", "canonical_id", dup.CanonicalID)
+		return false, nil
+	}
+
+	contentInfo, err := s.fetchNew(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	logger.Info("fetched new article")
+
+	// Keep only the article body so summaries and embeddings skip navigation, ads and comments
+	pending := s.pending(url, contentInfo, ExtractArticleText(contentInfo.HTML))
+	if pending.Content, err = s.screen(ctx, url, pending.Content); err != nil {
+		return false, err
+	}
+	if err := s.Repo.SavePendingArticle(ctx, pending); err != nil {
+		return false, fmt.Errorf("failed to store fetched article: %w", err)
+	}
+
+	// Listed as pending until enriched; searches skip it until then
+	if err := s.Repo.UpsertArticle(ctx, &domain.Article{
+		URL:             url,
+		Title:           pending.Title,
+		Content:         pending.Content,
+		URLHash:         calculateURLHash(url),
+		Author:          pending.Author,
+		Section:         pending.Section,
+		PublishedAt:     pending.PublishedAt,
+		SourceDomain:    SourceDomain(url),
+		ContentHash:     pending.ContentHash,
+		ETag:            pending.ETag,
+		LastRefreshedAt: &pending.FetchedAt,
+		Status:          domain.ArticlePending,
+	}); err != nil {
+		return false, fmt.Errorf("failed to store fetched article: %w", err)
+	}
+	return true, nil
+}
+
+// EnrichURL is the second half of IngestURL: it summarizes, embeds and analyzes the text
+// FetchURL stored for url and stores the article. It is a no-op when no text is pending, and
+// the text stays pending when it fails, so enrichment can be retried without fetching again.
+func (s *Service) EnrichURL(ctx context.Context, url string) error {
+	pending, err := s.Repo.GetPendingArticle(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to load fetched article: %w", err)
+	}
+	if pending == nil {
 		return nil
 	}
 
-	// Fetch content
-	contentInfo, err := fetchHTMLWithHeaders(url)
+	// Another ingest of the same URL may have finished first
+	existing, err := s.Repo.GetArticleByURL(ctx, url)
 	if err != nil {
-		return fmt.Errorf("failed to fetch content: %w", err)
+		return fmt.Errorf("failed to check existing article: %w", err)
 	}
+	if existing == nil || existing.Status != domain.ArticleEnriched {
+		if err := s.enrich(ctx, pending, false); err != nil {
+			return err
+		}
+	}
+	if err := s.Repo.DeletePendingArticle(ctx, url); err != nil {
+		logging.FromContext(ctx).Warn("failed to remove enriched article from the pending list", "url", url, "error", err)
+	}
+	return nil
+}
 
-	log.Printf("📄 Processing new article: %s", url)
+// ForceReingest fetches and re-analyzes an article even if it was already ingested,
+// overwriting the stored analysis
+func (s *Service) ForceReingest(ctx context.Context, url string) error {
+	contentInfo, err := s.fetchNew(ctx, url)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("re-ingesting article", "url", url)
+	return s.analyze(ctx, url, contentInfo, ExtractArticleText(contentInfo.HTML), true)
+}
 
-	// Process the content
-	text := StripHTMLBasic(contentInfo.HTML)
+// fetchNew fetches an article page for a first ingest or a forced re-ingest
+func (s *Service) fetchNew(ctx context.Context, url string) (*ContentInfo, error) {
+	if s.FailureHook != nil {
+		if err := s.FailureHook(ctx, url); err != nil {
+			return nil, fmt.Errorf("failed to fetch content: %w", err)
+		}
+	}
 
-	sum, err := s.LLM.Summarize(ctx, text)
+	contentInfo, err := s.fetch(ctx, url, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch content: %w", err)
+	}
+	return contentInfo, nil
+}
+
+// pending returns what enrichment needs from fetched content: its text and metadata
+func (s *Service) pending(url string, contentInfo *ContentInfo, text string) *domain.PendingArticle {
+	// Prefer structured metadata over the heuristic <title> parse
+	meta := ExtractMetadata(contentInfo.HTML, s.metadataExtractors())
+	title := meta.Title
+	if title == "" {
+		title = contentInfo.Title
+	}
+	return &domain.PendingArticle{
+		URL:         url,
+		Title:       title,
+		Author:      meta.Author,
+		Section:     meta.Section,
+		PublishedAt: meta.PublishedAt,
+		Content:     text,
+		ContentHash: ContentHash(text),
+		ETag:        contentInfo.ETag,
+		FetchedAt:   contentInfo.FetchedAt,
+	}
+}
+
+// linkDuplicate records dup as a duplicate in place of its pending article
+func (s *Service) linkDuplicate(ctx context.Context, dup *domain.Article, similarity float64) error {
+	if _, err := s.Repo.DeleteArticleByURL(ctx, dup.URL); err != nil {
+		return fmt.Errorf("failed to remove pending article: %w", err)
+	}
+	return s.Repo.AddDuplicate(ctx, dup, similarity)
+}
+
+// analyze enriches fetched content right away, as refreshes and re-ingests of stored articles do
+func (s *Service) analyze(ctx context.Context, url string, contentInfo *ContentInfo, text string, force bool) error {
+	p := s.pending(url, contentInfo, text)
+	var err error
+	if p.Content, err = s.screen(ctx, url, p.Content); err != nil {
+		return err
+	}
+	return s.enrich(ctx, p, force)
+}
+
+// screen removes instruction-like text from fetched or imported text and checks it with the
+// Moderator, if set. Flagged text is a FetchError of category FetchModerated, so it is not
+// retried; when the moderation call fails the text is kept. The content hash stays that of the
+// text as fetched, so refreshes compare like with like.
+func (s *Service) screen(ctx context.Context, url, text string) (string, error) {
+	logger := logging.FromContext(ctx).With("url", url)
+	clean, removed := moderation.Sanitize(text, s.Sanitize)
+	if removed > 0 {
+		logger.Warn("removed instruction-like text from article", "removed", removed)
+	}
+	if err := moderation.Check(ctx, s.Moderator, clean, s.Sanitize); err != nil {
+		if errors.Is(err, moderation.ErrFlagged) {
+			return "", &FetchError{URL: url, Category: FetchModerated, Err: err}
+		}
+		logger.Warn("article moderation failed, keeping it", "error", err)
+	}
+	return clean, nil
+}
+
+// enrich summarizes, embeds and extracts semantics from fetched text and stores the article.
+// force replaces an existing analysis instead of checking for near-duplicates.
+func (s *Service) enrich(ctx context.Context, p *domain.PendingArticle, force bool) error {
+	url, text, title, hash := p.URL, p.Content, p.Title, p.ContentHash
+	logger := logging.FromContext(ctx).With("url", url)
+
+	if !force && text != "" && s.duplicateThreshold() > 0 {
+		// Identical text at another URL is linked without any LLM calls
+		same, err := s.Repo.GetArticleByContentHash(ctx, hash, url)
+		if err != nil {
+			return fmt.Errorf("failed to check for identical content: %w", err)
+		}
+		if same != nil {
+			logger.Info("identical article content, linking to canonical", "canonical_id", same.ID, "canonical_url", same.URL)
+			return s.linkDuplicate(ctx, &domain.Article{URL: url, Title: title, CanonicalID: same.ID}, 1)
+		}
+	}
+
+	sum, err := s.LLM.Summarize(ctx, text, llm.SummaryOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to summarize: %w", err)
 	}
@@ -56,10 +369,25 @@ func (s *Service) IngestURL(ctx context.Context, url string) error {
 		return fmt.Errorf("failed to embed: %w", err)
 	}
 
+	// Near-identical content (syndication, reposts) is linked to the stored article, skipping analysis
+	if threshold := s.duplicateThreshold(); !force && threshold > 0 {
+		canonical, similarity, err := s.Repo.FindNearDuplicate(ctx, emb, url, threshold)
+		if err != nil {
+			return fmt.Errorf("failed to check for duplicates: %w", err)
+		}
+		if canonical != nil {
+			logger.Info("near-duplicate article, linking to canonical", "canonical_id", canonical.ID,
+				"canonical_url", canonical.URL, "similarity", similarity)
+			return s.linkDuplicate(ctx, &domain.Article{URL: url, Title: title, CanonicalID: canonical.ID}, similarity)
+		}
+	}
+
 	// Extract all semantic data in a single LLM call (faster and cheaper)
+	analysisVersion, analysisModel := llm.SemanticsVersion, s.AnalysisModel
 	semanticAnalysis, err := s.LLM.ExtractAllSemantics(ctx, sum)
 	if err != nil {
-		log.Printf("Failed to extract semantic data: %v", err)
+		logger.Warn("failed to extract semantic data", "error", err)
+		analysisVersion, analysisModel = "", "" // Picked up again by re-analysis
 		// Fallback to empty data
 		semanticAnalysis = &domain.SemanticAnalysis{
 			Entities:       []domain.SemanticEntity{},
@@ -79,6 +407,7 @@ func (s *Service) IngestURL(ctx context.Context, url string) error {
 			Confidence: entity.Confidence,
 		}
 	}
+	entities = s.entities().Normalize(ctx, entities)
 
 	keywords := make([]domain.SemanticKeyword, len(semanticAnalysis.Keywords))
 	for i, keyword := range semanticAnalysis.Keywords {
@@ -100,20 +429,52 @@ func (s *Service) IngestURL(ctx context.Context, url string) error {
 
 	// Create article with URL hash
 	a := &domain.Article{
-		ID:             uuid.New().String(),
-		URL:            url,
-		Title:          contentInfo.Title,
-		Summary:        sum,
-		Embedding:      emb,
-		Entities:       entities,
-		Keywords:       keywords,
-		Topics:         topics,
-		Sentiment:      semanticAnalysis.Sentiment,
-		SentimentScore: semanticAnalysis.SentimentScore,
-		URLHash:        urlHash,
-	}
-
-	return s.Repo.UpsertArticle(ctx, a)
+		ID:              uuid.New().String(),
+		URL:             url,
+		Title:           title,
+		Summary:         sum,
+		SummaryVersion:  llm.SummaryVersion,
+		SummaryModel:    s.SummaryModel,
+		Content:         text,
+		Embedding:       emb,
+		EmbeddingModel:  s.EmbeddingModel,
+		Entities:        entities,
+		Keywords:        keywords,
+		Topics:          topics,
+		TopicIDs:        s.mapTopics(ctx, sum, topics),
+		Sentiment:       semanticAnalysis.Sentiment,
+		SentimentScore:  semanticAnalysis.SentimentScore,
+		Tone:            semanticAnalysis.Tone,
+		AnalysisVersion: analysisVersion,
+		AnalysisModel:   analysisModel,
+		URLHash:         calculateURLHash(url),
+		Author:          p.Author,
+		Section:         p.Section,
+		PublishedAt:     p.PublishedAt,
+		SourceDomain:    SourceDomain(url),
+		Language:        language.Detect(text),
+		ContentHash:     hash,
+		ETag:            p.ETag,
+		LastRefreshedAt: &p.FetchedAt,
+		Status:          domain.ArticleEnriched,
+	}
+
+	if err := s.Repo.UpsertArticle(ctx, a); err != nil {
+		return err
+	}
+
+	// Passages for question answering; the article stays usable without them
+	if err := s.indexChunks(ctx, a); err != nil {
+		logger.Warn("failed to index article passages", "error", err)
+	}
+
+	if force {
+		// The summary changed, so cached reading-level rewrites are stale
+		if err := s.Repo.ClearSimplifiedSummaries(ctx, url); err != nil {
+			logger.Warn("failed to clear simplified summaries", "error", err)
+		}
+	}
+	return nil
 }
 
 func fetchHTML(url string) (body, title string, err error) {
@@ -0,0 +1,252 @@
+package ingest
+
+import (
+	"encoding/json"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Metadata holds structured article metadata extracted from HTML
+type Metadata struct {
+	Title       string
+	Description string
+	Author      string
+	Section     string
+	PublishedAt *time.Time
+}
+
+// MetadataExtractor extracts structured metadata from raw HTML.
+// Extractors return only the fields they can determine; empty fields are left for
+// lower-priority extractors to fill.
+type MetadataExtractor interface {
+	Extract(rawHTML string) Metadata
+}
+
+// DefaultMetadataExtractors returns the extractor chain in priority order:
+// JSON-LD, then OpenGraph/meta tags, then the <title> heuristic and <time> tags.
+func DefaultMetadataExtractors() []MetadataExtractor {
+	return []MetadataExtractor{
+		JSONLDExtractor{},
+		OpenGraphExtractor{},
+		TitleTagExtractor{},
+		TimeTagExtractor{},
+	}
+}
+
+// ExtractMetadata runs the extractors in order, keeping the first non-empty value for each field
+func ExtractMetadata(rawHTML string, extractors []MetadataExtractor) Metadata {
+	var out Metadata
+	for _, ex := range extractors {
+		m := ex.Extract(rawHTML)
+		if out.Title == "" {
+			out.Title = m.Title
+		}
+		if out.Description == "" {
+			out.Description = m.Description
+		}
+		if out.Author == "" {
+			out.Author = m.Author
+		}
+		if out.Section == "" {
+			out.Section = m.Section
+		}
+		if out.PublishedAt == nil {
+			out.PublishedAt = m.PublishedAt
+		}
+	}
+	return out
+}
+
+// SourceDomain returns the lowercased host of an article URL without a port or leading
+// "www.", or "" if the URL has no host
+func SourceDomain(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// ---------- <title> heuristic ----------
+
+// TitleTagExtractor reads the document <title>
+type TitleTagExtractor struct{}
+
+func (TitleTagExtractor) Extract(rawHTML string) Metadata {
+	title := ExtractBetween(rawHTML, "<title>", "</title>")
+	return Metadata{Title: strings.TrimSpace(html.UnescapeString(title))}
+}
+
+// ---------- OpenGraph / meta tags ----------
+
+var (
+	metaTagRe   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	metaAttrRe  = regexp.MustCompile(`(?is)([a-z][a-z0-9:_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	jsonLDRe    = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05", "2006-01-02"}
+)
+
+// OpenGraphExtractor reads og:*, article:* and standard name="author"/"description" meta tags
+type OpenGraphExtractor struct{}
+
+func (OpenGraphExtractor) Extract(rawHTML string) Metadata {
+	tags := make(map[string]string)
+	for _, tag := range metaTagRe.FindAllString(rawHTML, -1) {
+		attrs := make(map[string]string)
+		for _, m := range metaAttrRe.FindAllStringSubmatch(tag, -1) {
+			val := m[2]
+			if val == "" {
+				val = m[3]
+			}
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(strings.TrimSpace(val))
+		}
+		key := attrs["property"]
+		if key == "" {
+			key = attrs["name"]
+		}
+		key = strings.ToLower(key)
+		if key != "" && attrs["content"] != "" {
+			if _, exists := tags[key]; !exists {
+				tags[key] = attrs["content"]
+			}
+		}
+	}
+
+	m := Metadata{
+		Title:       firstNonEmpty(tags["og:title"], tags["twitter:title"]),
+		Description: firstNonEmpty(tags["og:description"], tags["description"], tags["twitter:description"]),
+		Author:      firstNonEmpty(tags["article:author"], tags["author"]),
+		Section:     tags["article:section"],
+	}
+	// article:author is frequently a profile URL rather than a name
+	if strings.HasPrefix(m.Author, "http://") || strings.HasPrefix(m.Author, "https://") {
+		m.Author = tags["author"]
+	}
+	m.PublishedAt = parseTime(firstNonEmpty(tags["article:published_time"], tags["og:published_time"], tags["pubdate"]))
+	return m
+}
+
+// ---------- JSON-LD ----------
+
+// JSONLDExtractor reads schema.org NewsArticle/Article objects from ld+json scripts
+type JSONLDExtractor struct{}
+
+var articleTypes = map[string]bool{
+	"newsarticle":          true,
+	"article":              true,
+	"reportagenewsarticle": true,
+	"blogposting":          true,
+	"analysisnewsarticle":  true,
+	"opinionnewsarticle":   true,
+}
+
+func (JSONLDExtractor) Extract(rawHTML string) Metadata {
+	for _, block := range jsonLDRe.FindAllStringSubmatch(rawHTML, -1) {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(block[1])), &doc); err != nil {
+			continue
+		}
+		if obj := findArticleObject(doc); obj != nil {
+			return Metadata{
+				Title:       stringField(obj["headline"]),
+				Description: stringField(obj["description"]),
+				Author:      personName(obj["author"]),
+				Section:     stringField(obj["articleSection"]),
+				PublishedAt: parseTime(stringField(obj["datePublished"])),
+			}
+		}
+	}
+	return Metadata{}
+}
+
+// findArticleObject walks arrays and @graph containers looking for an article-typed object
+func findArticleObject(v interface{}) map[string]interface{} {
+	switch node := v.(type) {
+	case []interface{}:
+		for _, item := range node {
+			if obj := findArticleObject(item); obj != nil {
+				return obj
+			}
+		}
+	case map[string]interface{}:
+		if isArticleType(node["@type"]) {
+			return node
+		}
+		if graph, ok := node["@graph"]; ok {
+			return findArticleObject(graph)
+		}
+	}
+	return nil
+}
+
+func isArticleType(v interface{}) bool {
+	switch t := v.(type) {
+	case string:
+		return articleTypes[strings.ToLower(t)]
+	case []interface{}:
+		for _, item := range t {
+			if isArticleType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringField returns a string value, or the first string of an array value
+func stringField(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(t))
+	case []interface{}:
+		for _, item := range t {
+			if s := stringField(item); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// personName resolves a schema.org author, which may be a string, a Person object, or a list of either
+func personName(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case map[string]interface{}:
+		return stringField(t["name"])
+	case []interface{}:
+		var names []string
+		for _, item := range t {
+			if n := personName(item); n != "" {
+				names = append(names, n)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func parseTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,320 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Politeness defaults
+const (
+	DefaultMaxPerHost = 2
+	DefaultCrawlDelay = time.Second
+	DefaultRobotsTTL  = time.Hour
+
+	// robotsAgent is the product token matched against robots.txt User-agent lines
+	robotsAgent = "ArticleAssistant"
+
+	// maxRobotsSize caps how much of a robots.txt is read, as crawlers commonly do
+	maxRobotsSize = 512 << 10
+)
+
+// ErrDisallowed is returned for URLs that the host's robots.txt disallows
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// PolitenessOptions configures Polite; zero values use the defaults
+type PolitenessOptions struct {
+	MaxPerHost int           // Concurrent requests per host
+	CrawlDelay time.Duration // Minimum gap between requests to a host; robots.txt Crawl-delay can raise it
+	RobotsTTL  time.Duration // How long a host's robots.txt is cached
+
+	// Overrides are hosts, including their subdomains, that are fetched without robots.txt
+	// checks, concurrency limits or delays, e.g. internal sources
+	Overrides []string
+}
+
+// Polite wraps next (http.DefaultTransport if nil) so that article fetches honor robots.txt
+// and do not hammer a host: requests are limited per host and spaced by the crawl delay.
+// Each redirect hop is checked separately, as the client sends it through the transport.
+func Polite(next http.RoundTripper, opts PolitenessOptions) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if opts.MaxPerHost <= 0 {
+		opts.MaxPerHost = DefaultMaxPerHost
+	}
+	if opts.CrawlDelay < 0 {
+		opts.CrawlDelay = 0
+	} else if opts.CrawlDelay == 0 {
+		opts.CrawlDelay = DefaultCrawlDelay
+	}
+	if opts.RobotsTTL <= 0 {
+		opts.RobotsTTL = DefaultRobotsTTL
+	}
+	overrides := make([]string, 0, len(opts.Overrides))
+	for _, h := range opts.Overrides {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			overrides = append(overrides, strings.TrimPrefix(h, "www."))
+		}
+	}
+	return &politeTransport{next: next, opts: opts, overrides: overrides, hosts: make(map[string]*hostState)}
+}
+
+type politeTransport struct {
+	next      http.RoundTripper
+	opts      PolitenessOptions
+	overrides []string
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// hostState is the per-host limiter and cached robots.txt
+type hostState struct {
+	slots chan struct{}
+
+	mu          sync.Mutex
+	nextAt      time.Time // Earliest start of the next request
+	rules       *robotsRules
+	rulesExpire time.Time
+}
+
+func (p *politeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Host)
+	if p.overridden(req.URL.Hostname()) {
+		return p.next.RoundTrip(req)
+	}
+	ctx := req.Context()
+	h := p.host(host)
+
+	rules := p.robots(ctx, h, req)
+	if !rules.allowed(req.URL.RequestURI()) {
+		return nil, fmt.Errorf("%w: %s", ErrDisallowed, req.URL)
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := sync.OnceFunc(func() { <-h.slots })
+
+	delay := max(p.opts.CrawlDelay, rules.crawlDelay)
+	h.mu.Lock()
+	now := time.Now()
+	start := now
+	if h.nextAt.After(now) {
+		start = h.nextAt
+	}
+	h.nextAt = start.Add(delay)
+	h.mu.Unlock()
+	if wait := start.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	resp, err := p.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// The slot is held until the body is read, which is most of a fetch
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// overridden reports whether hostname or a parent domain is on the override list
+func (p *politeTransport) overridden(hostname string) bool {
+	hostname = strings.TrimPrefix(strings.ToLower(hostname), "www.")
+	for _, o := range p.overrides {
+		if hostname == o || strings.HasSuffix(hostname, "."+o) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *politeTransport) host(host string) *hostState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, ok := p.hosts[host]
+	if !ok {
+		h = &hostState{slots: make(chan struct{}, p.opts.MaxPerHost)}
+		p.hosts[host] = h
+	}
+	return h
+}
+
+// robots returns the host's cached rules, fetching robots.txt when missing or expired.
+// The host lock is held during the fetch so concurrent requests wait for one download.
+func (p *politeTransport) robots(ctx context.Context, h *hostState, req *http.Request) *robotsRules {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rules != nil && time.Now().Before(h.rulesExpire) {
+		return h.rules
+	}
+	h.rules = p.fetchRobots(ctx, req)
+	h.rulesExpire = time.Now().Add(p.opts.RobotsTTL)
+	return h.rules
+}
+
+// fetchRobots downloads and parses robots.txt for the request's host. A missing file or a
+// failed download allows everything, so an unreachable robots.txt does not block ingestion.
+func (p *politeTransport) fetchRobots(ctx context.Context, req *http.Request) *robotsRules {
+	robotsURL := req.URL.Scheme + "://" + req.URL.Host + "/robots.txt"
+	robotsReq, err := http.NewRequestWithContext(ctx, "GET", robotsURL, nil)
+	if err != nil {
+		return &robotsRules{}
+	}
+	robotsReq.Header.Set("User-Agent", req.Header.Get("User-Agent"))
+	resp, err := p.next.RoundTrip(robotsReq)
+	if err != nil {
+		return &robotsRules{}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &robotsRules{}
+	}
+	return parseRobots(io.LimitReader(resp.Body, maxRobotsSize), robotsAgent)
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// robotsRules are the robots.txt rules that apply to one user agent
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// allowed applies the longest matching rule; Allow wins a tie, and no match allows
+func (r *robotsRules) allowed(path string) bool {
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if len(rule.pattern) < best || !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > best || rule.allow {
+			best, allow = len(rule.pattern), rule.allow
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a robots.txt path pattern, where * matches any characters and
+// a trailing $ anchors the end of the path
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for _, part := range parts[1:] {
+		i := strings.Index(path[pos:], part)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(part)
+	}
+	if anchored && len(parts) == 1 {
+		return pos == len(path)
+	}
+	if anchored {
+		return strings.HasSuffix(path, parts[len(parts)-1])
+	}
+	return true
+}
+
+// parseRobots reads the rules of the group naming agent, or of the * group if none does
+func parseRobots(r io.Reader, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+	var specific, wildcard *robotsRules
+	var current []*robotsRules // Groups the rules being read belong to
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			name := strings.ToLower(value)
+			switch {
+			case name == "*":
+				if wildcard == nil {
+					wildcard = &robotsRules{}
+				}
+				current = append(current, wildcard)
+			case strings.Contains(agent, name):
+				if specific == nil {
+					specific = &robotsRules{}
+				}
+				current = append(current, specific)
+			}
+		case "allow", "disallow":
+			inAgents = false
+			if value == "" {
+				continue // An empty Disallow allows everything
+			}
+			for _, g := range current {
+				g.rules = append(g.rules, robotsRule{pattern: value, allow: key == "allow"})
+			}
+		case "crawl-delay":
+			inAgents = false
+			if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+				for _, g := range current {
+					g.crawlDelay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	switch {
+	case specific != nil:
+		return specific
+	case wildcard != nil:
+		return wildcard
+	}
+	return &robotsRules{}
+}
@@ -0,0 +1,310 @@
+package ingest
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minArticleChars is the shortest extraction accepted before falling back to StripHTMLBasic
+const minArticleChars = 200
+
+var (
+	// Elements that never hold article body text
+	boilerplateTags = map[atom.Atom]bool{
+		atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true, atom.Header: true,
+		atom.Footer: true, atom.Aside: true, atom.Form: true, atom.Iframe: true, atom.Svg: true,
+		atom.Button: true, atom.Select: true, atom.Input: true, atom.Template: true, atom.Object: true,
+	}
+	unlikelyRe = regexp.MustCompile(`(?i)comment|sidebar|nav|menu|footer|header|masthead|share|social|sponsor|advert|\bads?\b|ad-|promo|related|recommend|subscribe|newsletter|cookie|consent|banner|popup|modal|breadcrumb|pagination|widget|outbrain|taboola`)
+	positiveRe = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text|blog`)
+	negativeRe = regexp.MustCompile(`(?i)hidden|meta|caption|byline|author|dateline|tags|combx|contact|foot|shopping|tool`)
+	blockTags  = map[atom.Atom]bool{
+		atom.P: true, atom.Pre: true, atom.Blockquote: true, atom.Li: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.Td: true,
+	}
+)
+
+// ExtractArticleText returns the main body text of an article page using a readability-style
+// algorithm: boilerplate elements are removed, paragraph containers are scored by text length,
+// commas, class/id hints and link density, and the best container (plus related siblings) is
+// rendered as paragraphs. It falls back to StripHTMLBasic when no convincing body is found.
+func ExtractArticleText(rawHTML string) string {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return StripHTMLBasic(rawHTML)
+	}
+
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	removeBoilerplate(body)
+
+	top := topCandidate(body)
+	if top == nil {
+		return StripHTMLBasic(rawHTML)
+	}
+
+	var paragraphs []string
+	for _, n := range withSiblings(top) {
+		paragraphs = appendParagraphs(paragraphs, n)
+	}
+	text := strings.Join(paragraphs, "\n\n")
+	if len(text) < minArticleChars {
+		return StripHTMLBasic(rawHTML)
+	}
+	return text
+}
+
+// removeBoilerplate drops comments, boilerplate elements and unlikely candidates in place
+func removeBoilerplate(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode || (c.Type == html.ElementNode && isBoilerplate(c)) {
+			n.RemoveChild(c)
+		} else {
+			removeBoilerplate(c)
+		}
+		c = next
+	}
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if boilerplateTags[n.DataAtom] {
+		return true
+	}
+	if hasAttr(n, "hidden") || strings.EqualFold(attr(n, "aria-hidden"), "true") || attr(n, "role") == "navigation" {
+		return true
+	}
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	hint := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyRe.MatchString(hint) && !positiveRe.MatchString(hint)
+}
+
+// topCandidate scores paragraph containers and returns the highest-scoring one
+func topCandidate(root *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node // Stable iteration for ties
+
+	addScore := func(n *html.Node, s float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = baseScore(n)
+			order = append(order, n)
+		}
+		scores[n] += s
+	}
+
+	walk(root, func(n *html.Node) {
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre && n.DataAtom != atom.Td {
+			return
+		}
+		text := nodeText(n)
+		if len(text) < 25 {
+			return
+		}
+		s := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		addScore(n.Parent, s)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, s/2)
+		}
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, n := range order {
+		s := scores[n] * (1 - linkDensity(n))
+		scores[n] = s
+		if s > bestScore {
+			best, bestScore = n, s
+		}
+	}
+	return best
+}
+
+// baseScore weights a candidate by tag and class/id hints
+func baseScore(n *html.Node) float64 {
+	var s float64
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		s = 10
+	case atom.Div:
+		s = 5
+	case atom.Section, atom.Blockquote, atom.Pre, atom.Td:
+		s = 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Form:
+		s = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		s = -5
+	}
+	hint := attr(n, "class") + " " + attr(n, "id")
+	if positiveRe.MatchString(hint) {
+		s += 25
+	}
+	if negativeRe.MatchString(hint) {
+		s -= 25
+	}
+	return s
+}
+
+// withSiblings returns the candidate plus siblings that look like part of the same article,
+// such as paragraphs split across adjacent containers
+func withSiblings(top *html.Node) []*html.Node {
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+	var out []*html.Node
+	for c := top.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c == top {
+			out = append(out, c)
+			continue
+		}
+		if c.Type != html.ElementNode || c.DataAtom != atom.P {
+			continue
+		}
+		text := nodeText(c)
+		ld := linkDensity(c)
+		if (len(text) > 80 && ld < 0.25) || (len(text) > 0 && ld == 0 && strings.HasSuffix(text, ".")) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// appendParagraphs renders block-level descendants as separate paragraphs
+func appendParagraphs(out []string, n *html.Node) []string {
+	if n.Type == html.ElementNode && blockTags[n.DataAtom] {
+		if text := nodeText(n); text != "" && linkDensity(n) < 0.5 {
+			out = append(out, text)
+		}
+		return out
+	}
+	if n.Type == html.TextNode {
+		if text := collapseSpace(n.Data); len(text) > 40 {
+			out = append(out, text)
+		}
+		return out
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		out = appendParagraphs(out, c)
+	}
+	return out
+}
+
+// linkDensity is the fraction of a node's text inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(nodeText(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walk(n, func(c *html.Node) {
+		if c.DataAtom == atom.A {
+			linked += len(nodeText(c))
+		}
+	})
+	return float64(linked) / float64(total)
+}
+
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return collapseSpace(b.String())
+}
+
+// collapseSpace joins whitespace runs and removes spaces before punctuation left by inline tags
+func collapseSpace(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, p := range []string{".", ",", "!", "?", ";", ":"} {
+		s = strings.ReplaceAll(s, " "+p, p)
+	}
+	return s
+}
+
+// walk calls fn for every element node under n, including n
+func walk(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(n, func(c *html.Node) {
+		if found == nil && c.DataAtom == a {
+			found = c
+		}
+	})
+	return found
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// ---------- <time> published date ----------
+
+// TimeTagExtractor reads the published date from <time datetime> or itemprop="datePublished"
+// elements, for pages without structured metadata
+type TimeTagExtractor struct{}
+
+func (TimeTagExtractor) Extract(rawHTML string) Metadata {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return Metadata{}
+	}
+
+	var candidates []string
+	walk(doc, func(n *html.Node) {
+		if attr(n, "itemprop") == "datePublished" {
+			candidates = append(candidates, firstNonEmpty(attr(n, "datetime"), attr(n, "content"), nodeText(n)))
+		}
+	})
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom == atom.Time {
+			candidates = append(candidates, attr(n, "datetime"))
+		}
+	})
+
+	for _, c := range candidates {
+		if t := parseTime(strings.TrimSpace(c)); t != nil {
+			return Metadata{PublishedAt: t}
+		}
+	}
+	return Metadata{}
+}
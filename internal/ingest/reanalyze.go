@@ -0,0 +1,205 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/tenant"
+)
+
+// Re-analysis defaults
+const (
+	DefaultReanalyzeBatch = 50
+	DefaultReanalyzeRate  = 1.0 // Articles per second
+)
+
+// Reanalyze extracts a stored article's entities, keywords, topics, sentiment and tone again
+// with the current extraction (llm.SemanticsVersion) and updates them in place. a needs ID
+// and Summary, as returned by ListArticlesToReanalyze. Unlike at ingestion, a failed
+// extraction is returned and the stored analysis kept.
+func (s *Service) Reanalyze(ctx context.Context, a *domain.Article) error {
+	analysis, err := s.LLM.ExtractAllSemantics(ctx, a.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to extract semantic data: %w", err)
+	}
+	a.Entities = s.entities().Normalize(ctx, analysis.Entities)
+	a.Keywords, a.Topics = nonNil(analysis.Keywords), nonNil(analysis.Topics)
+	a.TopicIDs = s.mapTopics(ctx, a.Summary, analysis.Topics)
+	a.Sentiment, a.SentimentScore, a.Tone = analysis.Sentiment, analysis.SentimentScore, analysis.Tone
+	a.AnalysisVersion, a.AnalysisModel = llm.SemanticsVersion, s.AnalysisModel
+	if err := s.Repo.SetArticleAnalysis(ctx, a); err != nil {
+		return fmt.Errorf("failed to store analysis: %w", err)
+	}
+	return nil
+}
+
+// ReanalyzeProgress reports a re-analysis run
+type ReanalyzeProgress struct {
+	Version    string     `json:"version"` // Analysis version articles are brought to
+	Total      int        `json:"total"`   // Articles to re-analyze when the run started
+	Done       int        `json:"done"`
+	Failed     int        `json:"failed"` // Left as they were; the next run retries them
+	Running    bool       `json:"running"`
+	Error      string     `json:"error,omitempty"` // Why the run stopped early
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Reanalyzer re-analyzes the stored articles not analyzed with the current version, in batches
+// and at a bounded rate. Articles are updated one by one, so an interrupted run continues where
+// it stopped when started again.
+type Reanalyzer struct {
+	Service *Service
+	Batch   int     // Articles listed per batch; DefaultReanalyzeBatch when 0
+	Rate    float64 // Articles per second at most; 0 for no limit
+}
+
+// Pending counts the enriched articles per analysis version and how many need re-analysis
+func (r *Reanalyzer) Pending(ctx context.Context) (int, []domain.AnalysisVersionCount, error) {
+	counts, err := r.Service.Repo.CountAnalysisVersions(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to count analysis versions: %w", err)
+	}
+	pending := 0
+	for _, c := range counts {
+		if c.Version != llm.SemanticsVersion && c.Version != domain.AnalysisSupplied {
+			pending += c.Articles
+		}
+	}
+	return pending, counts, nil
+}
+
+// Run re-analyzes every pending article, calling progress, if set, after each batch. It stops
+// early when ctx is done or listing articles fails; failed articles are counted and skipped.
+func (r *Reanalyzer) Run(ctx context.Context, progress func(ReanalyzeProgress)) (ReanalyzeProgress, error) {
+	p := ReanalyzeProgress{Version: llm.SemanticsVersion, Running: true, StartedAt: time.Now()}
+	finish := func(err error) (ReanalyzeProgress, error) {
+		now := time.Now()
+		p.Running, p.FinishedAt = false, &now
+		if err != nil {
+			p.Error = err.Error()
+		}
+		if progress != nil {
+			progress(p)
+		}
+		return p, err
+	}
+
+	total, _, err := r.Pending(ctx)
+	if err != nil {
+		return finish(err)
+	}
+	p.Total = total
+	if progress != nil {
+		progress(p)
+	}
+
+	batch := r.Batch
+	if batch <= 0 {
+		batch = DefaultReanalyzeBatch
+	}
+	var tick <-chan time.Time
+	if r.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / r.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	logger := logging.FromContext(ctx)
+	afterID := ""
+	for {
+		articles, err := r.Service.Repo.ListArticlesToReanalyze(ctx, llm.SemanticsVersion, afterID, batch)
+		if err != nil {
+			return finish(fmt.Errorf("failed to list articles: %w", err))
+		}
+		if len(articles) == 0 {
+			return finish(nil)
+		}
+		for i := range articles {
+			if tick != nil && p.Done+p.Failed > 0 {
+				select {
+				case <-ctx.Done():
+				case <-tick:
+				}
+			}
+			if ctx.Err() != nil {
+				return finish(ctx.Err())
+			}
+			if err := r.Service.Reanalyze(ctx, &articles[i]); err != nil {
+				if ctx.Err() != nil {
+					return finish(ctx.Err())
+				}
+				logger.Warn("failed to re-analyze article", "url", articles[i].URL, "error", err)
+				p.Failed++
+				continue
+			}
+			p.Done++
+		}
+		afterID = articles[len(articles)-1].ID
+		if progress != nil {
+			progress(p)
+		}
+	}
+}
+
+// ReanalyzeJobs runs at most one background re-analysis per tenant and keeps the progress of
+// the last one, for POST and GET /admin/reanalyze
+type ReanalyzeJobs struct {
+	Go func(fn func(ctx context.Context)) bool // Starts background work, e.g. worker.Manager.Go
+
+	// OnFinish, if set, runs after a run that updated at least one article, e.g. to
+	// invalidate cached chat responses
+	OnFinish func(ctx context.Context)
+
+	mu   sync.Mutex
+	jobs map[string]ReanalyzeProgress
+}
+
+// Start runs r in the background for ctx's tenant and returns its initial progress. It reports
+// false, with the running job's progress, when one is already running, and false with no
+// progress when background work is no longer accepted, e.g. at shutdown.
+func (j *ReanalyzeJobs) Start(ctx context.Context, r *Reanalyzer) (ReanalyzeProgress, bool) {
+	id := tenant.FromContext(ctx)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if p, ok := j.jobs[id]; ok && p.Running {
+		return p, false
+	}
+	if j.jobs == nil {
+		j.jobs = make(map[string]ReanalyzeProgress)
+	}
+	p := ReanalyzeProgress{Version: llm.SemanticsVersion, Running: true, StartedAt: time.Now()}
+	requestID := logging.RequestID(ctx)
+	started := j.Go(func(bg context.Context) {
+		bg = logging.WithRequestID(tenant.NewContext(bg, id), requestID)
+		final, err := r.Run(bg, func(p ReanalyzeProgress) {
+			j.mu.Lock()
+			j.jobs[id] = p
+			j.mu.Unlock()
+		})
+		if err != nil {
+			logging.FromContext(bg).Warn("re-analysis stopped", "done", final.Done, "failed", final.Failed, "error", err)
+		}
+		if final.Done > 0 && j.OnFinish != nil {
+			j.OnFinish(bg)
+		}
+	})
+	if !started {
+		return ReanalyzeProgress{}, false
+	}
+	j.jobs[id] = p
+	return p, true
+}
+
+// Progress returns the progress of the tenant's running or last re-analysis
+func (j *ReanalyzeJobs) Progress(tenantID string) (ReanalyzeProgress, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	p, ok := j.jobs[tenantID]
+	return p, ok
+}
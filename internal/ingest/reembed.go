@@ -0,0 +1,27 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	"article-assistant/internal/domain"
+)
+
+// Reembed embeds a stored article's summary and passages again with the current embedding
+// model, after switching models. a needs ID, Summary and Content, as returned by
+// ListArticlesToReembed.
+func (s *Service) Reembed(ctx context.Context, a *domain.Article) error {
+	emb, err := s.LLM.Embed(ctx, a.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to embed: %w", err)
+	}
+	if err := s.indexChunks(ctx, a); err != nil {
+		return fmt.Errorf("failed to index article passages: %w", err)
+	}
+	// The model is recorded last, so an interrupted run picks the article up again
+	if err := s.Repo.SetArticleEmbedding(ctx, a.ID, emb, s.EmbeddingModel); err != nil {
+		return fmt.Errorf("failed to store embedding: %w", err)
+	}
+	a.Embedding, a.EmbeddingModel = emb, s.EmbeddingModel
+	return nil
+}
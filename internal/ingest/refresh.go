@@ -0,0 +1,116 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+)
+
+// Refresh re-fetches a stored article and re-runs the analysis if its content changed.
+// a must carry the stored Content/ContentHash/ETag, as returned by Repo.GetStaleArticles.
+// It reports whether the article was re-analyzed.
+func (s *Service) Refresh(ctx context.Context, a *domain.Article) (bool, error) {
+	logger := logging.FromContext(ctx).With("url", a.URL)
+
+	contentInfo, err := s.fetch(ctx, a.URL, a.ETag)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch content: %w", err)
+	}
+	if contentInfo.NotModified {
+		logger.Debug("article not modified")
+		return false, s.Repo.MarkArticleRefreshed(ctx, a.URL, "", "", contentInfo.FetchedAt)
+	}
+
+	text := ExtractArticleText(contentInfo.HTML)
+	hash := ContentHash(text)
+	previous := a.ContentHash
+	if previous == "" && a.Content != "" {
+		// Articles ingested before content hashing; compare against the stored text
+		previous = ContentHash(a.Content)
+	}
+	if hash == previous {
+		logger.Debug("article content unchanged")
+		return false, s.Repo.MarkArticleRefreshed(ctx, a.URL, hash, contentInfo.ETag, contentInfo.FetchedAt)
+	}
+
+	logger.Info("article content changed, re-analyzing")
+	if err := s.analyze(ctx, a.URL, contentInfo, text, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Refresher periodically refreshes articles whose content has not been checked for MaxAge
+type Refresher struct {
+	Service   *Service
+	MaxAge    time.Duration
+	BatchSize int // Articles checked per run
+
+	// OnChange, if set, runs after a run that re-analyzed at least one article,
+	// e.g. to invalidate cached chat responses
+	OnChange func(ctx context.Context)
+}
+
+// NewRefresher creates a refresher for articles older than maxAge
+func NewRefresher(service *Service, maxAge time.Duration) *Refresher {
+	return &Refresher{Service: service, MaxAge: maxAge, BatchSize: 20}
+}
+
+// RunOnce refreshes one batch of stale articles and returns how many were checked and changed
+func (r *Refresher) RunOnce(ctx context.Context) (checked, changed int, err error) {
+	articles, err := r.Service.Repo.GetStaleArticles(ctx, time.Now().Add(-r.MaxAge), r.BatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load stale articles: %w", err)
+	}
+
+	logger := logging.FromContext(ctx)
+	for i := range articles {
+		if ctx.Err() != nil {
+			break
+		}
+		checked++
+		updated, err := r.Service.Refresh(ctx, &articles[i])
+		if err != nil {
+			logger.Warn("failed to refresh article", "url", articles[i].URL, "error", err)
+			// Retry after MaxAge so unreachable URLs don't hold up the rest of the corpus
+			if err := r.Service.Repo.MarkArticleRefreshed(ctx, articles[i].URL, "", "", time.Now()); err != nil {
+				logger.Warn("failed to record refresh attempt", "url", articles[i].URL, "error", err)
+			}
+			continue
+		}
+		if updated {
+			changed++
+		}
+	}
+
+	if changed > 0 && r.OnChange != nil {
+		r.OnChange(ctx)
+	}
+	return checked, changed, nil
+}
+
+// Run calls RunOnce every interval until ctx is cancelled
+func (r *Refresher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("🔄 Started article refresh every %v for articles older than %v", interval, r.MaxAge)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Article refresh stopped")
+			return
+		case <-ticker.C:
+			checked, changed, err := r.RunOnce(ctx)
+			if err != nil {
+				log.Printf("❌ Article refresh failed: %v", err)
+			} else if checked > 0 {
+				log.Printf("🔄 Refreshed %d stale articles, %d changed", checked, changed)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"syscall"
+)
+
+// ErrPrivateAddress is returned when an article host resolves to a loopback, private,
+// link-local or other non-public address, such as a cloud metadata endpoint
+var ErrPrivateAddress = errors.New("address is not public")
+
+// errRedirectScheme is returned by the redirect policy for redirects to non-HTTP(S) URLs
+var errRedirectScheme = errors.New("redirect to a non-http(s) URL")
+
+// errTooLarge is returned when a response body is longer than FetchOptions.MaxBytes
+var errTooLarge = errors.New("response too large")
+
+// nonPublicPrefixes are the special-purpose ranges not covered by netip.Addr's predicates
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+}
+
+// publicAddress reports whether ip is a globally routable unicast address
+func publicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialPublic is a net.Dialer Control function refusing connections to non-public addresses.
+// It sees the resolved address of every connection, so hosts that resolve, or re-resolve, to an
+// internal address are refused as well as literal IPs.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddress(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ap.Addr())
+	}
+	return nil
+}
+
+// limitedBody fails reads past its limit instead of truncating the page silently
+type limitedBody struct {
+	io.ReadCloser
+	left int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.left <= 0 {
+		// Only an extra byte tells a body of exactly the limit from a longer one
+		var one [1]byte
+		n, err := b.ReadCloser.Read(one[:])
+		if n > 0 {
+			return 0, errTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.left {
+		p = p[:b.left]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.left -= int64(n)
+	return n, err
+}
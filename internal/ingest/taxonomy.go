@@ -0,0 +1,83 @@
+package ingest
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MinTopicMatchScore is the MatchTopics score at or above which an article is mapped to a
+// taxonomy topic
+const MinTopicMatchScore = 0.5
+
+// mapTopics returns the IDs of the taxonomy topics matching an article's extracted topics.
+// It makes no LLM call when there is no taxonomy or nothing was extracted; failures are
+// logged and leave the article unmapped.
+func (s *Service) mapTopics(ctx context.Context, summary string, topics []domain.SemanticTopic) []string {
+	if s.TopicMatcher == nil || len(topics) == 0 {
+		return []string{}
+	}
+	logger := logging.FromContext(ctx)
+	taxonomy, err := s.Repo.ListTaxonomyTopics(ctx)
+	if err != nil {
+		logger.Warn("failed to load topic taxonomy", "error", err)
+		return []string{}
+	}
+	if len(taxonomy) == 0 {
+		return []string{}
+	}
+
+	names := make([]string, len(taxonomy))
+	for i, t := range taxonomy {
+		names[i] = t.Name
+	}
+	extracted := make([]string, len(topics))
+	for i, t := range topics {
+		extracted[i] = t.Name
+	}
+	content := fmt.Sprintf("Topics: %s\n\nSummary: %s", strings.Join(extracted, ", "), summary)
+
+	matches, err := s.TopicMatcher.MatchTopics(ctx, content, names)
+	if err != nil {
+		logger.Warn("failed to map topics to the taxonomy", "error", err)
+		return []string{}
+	}
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		if m.Score < MinTopicMatchScore {
+			continue
+		}
+		// Names are not unique across branches; a match maps to every topic with the name
+		for _, t := range taxonomy {
+			if strings.EqualFold(strings.TrimSpace(m.Topic), t.Name) && !seen[t.ID] {
+				seen[t.ID] = true
+				ids = append(ids, t.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// CheckTopicParent returns an error if parentID cannot be the parent of topic id: it must
+// exist and must not be the topic itself or one of its subtopics
+func CheckTopicParent(taxonomy []domain.TaxonomyTopic, id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	parents := make(map[string]string, len(taxonomy))
+	for _, t := range taxonomy {
+		parents[t.ID] = t.ParentID
+	}
+	if _, ok := parents[parentID]; !ok {
+		return fmt.Errorf("parent topic %s not found", parentID)
+	}
+	for p := parentID; p != ""; p = parents[p] {
+		if p == id {
+			return fmt.Errorf("topic %s cannot be its own ancestor", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrURLNotAllowed is returned for URLs a URLPolicy rejects
+var ErrURLNotAllowed = errors.New("URL not allowed")
+
+// URLPolicy decides which article URLs may be fetched. Patterns take three forms:
+//
+//   - a host, e.g. example.com, matching it and its subdomains, with or without "www."
+//   - a host with * wildcards, e.g. *.example.com or news.*.org, matching the whole host,
+//     so that a lone * matches every host
+//   - re: followed by a regular expression, e.g. re:^https://example\.com/2025/, matched
+//     against the full URL with its scheme and host lowercased and any user info removed
+//
+// A URL must be http(s) and match no deny pattern. With allow patterns it must also match
+// one of them; without any, every other URL is allowed.
+type URLPolicy struct {
+	allow []urlPattern
+	deny  []urlPattern
+}
+
+type urlPattern struct {
+	raw  string
+	host string         // Plain host, matched with its subdomains
+	glob *regexp.Regexp // Host with wildcards, or a re: expression
+	full bool           // glob is matched against the full URL
+}
+
+// ParseURLPolicy parses comma-separated allow and deny patterns, e.g. the URL_ALLOWLIST and
+// URL_DENYLIST settings. Empty lists allow every http(s) URL.
+func ParseURLPolicy(allow, deny string) (*URLPolicy, error) {
+	p := &URLPolicy{}
+	var err error
+	if p.allow, err = parseURLPatterns(allow); err != nil {
+		return nil, fmt.Errorf("allowlist: %w", err)
+	}
+	if p.deny, err = parseURLPatterns(deny); err != nil {
+		return nil, fmt.Errorf("denylist: %w", err)
+	}
+	return p, nil
+}
+
+func parseURLPatterns(list string) ([]urlPattern, error) {
+	var patterns []urlPattern
+	for _, raw := range strings.Split(list, ",") {
+		raw = strings.TrimSpace(raw)
+		switch {
+		case raw == "":
+			continue
+		case strings.HasPrefix(raw, "re:"):
+			re, err := regexp.Compile(strings.TrimPrefix(raw, "re:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", raw, err)
+			}
+			patterns = append(patterns, urlPattern{raw: raw, glob: re, full: true})
+		case strings.Contains(raw, "*"):
+			host := strings.TrimPrefix(strings.ToLower(raw), "www.")
+			expr := strings.ReplaceAll(regexp.QuoteMeta(host), `\*`, `.*`)
+			patterns = append(patterns, urlPattern{raw: raw, glob: regexp.MustCompile("^" + expr + "$")})
+		default:
+			if strings.ContainsAny(raw, "/: ") {
+				return nil, fmt.Errorf("invalid pattern %q: expected a host such as example.com", raw)
+			}
+			patterns = append(patterns, urlPattern{raw: raw, host: strings.TrimPrefix(strings.ToLower(raw), "www.")})
+		}
+	}
+	return patterns, nil
+}
+
+func (m urlPattern) match(rawURL, host string) bool {
+	switch {
+	case m.full:
+		return m.glob.MatchString(rawURL)
+	case m.glob != nil:
+		return m.glob.MatchString(host)
+	}
+	return host == m.host || strings.HasSuffix(host, "."+m.host)
+}
+
+// Check returns an error wrapping ErrURLNotAllowed when rawURL may not be fetched. A nil
+// policy allows every URL.
+func (p *URLPolicy) Check(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: not an http(s) URL", ErrURLNotAllowed)
+	}
+	// Expressions see one spelling of each URL, so case or user info cannot get around them
+	u.User = nil
+	u.Host = strings.ToLower(u.Host)
+	normalized := u.String()
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for _, m := range p.deny {
+		if m.match(normalized, host) {
+			return fmt.Errorf("%w: %s matches denied pattern %q", ErrURLNotAllowed, host, m.raw)
+		}
+	}
+	if len(p.allow) == 0 {
+		return nil
+	}
+	for _, m := range p.allow {
+		if m.match(normalized, host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s matches no allowed pattern", ErrURLNotAllowed, host)
+}
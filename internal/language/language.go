@@ -0,0 +1,163 @@
+// Package language detects the language of article text and maps language names to
+// ISO 639-1 codes.
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// names maps supported ISO 639-1 codes to English names
+var names = map[string]string{
+	"en": "English", "es": "Spanish", "fr": "French", "de": "German", "it": "Italian",
+	"pt": "Portuguese", "nl": "Dutch", "sv": "Swedish", "pl": "Polish", "ru": "Russian",
+	"uk": "Ukrainian", "el": "Greek", "tr": "Turkish", "ar": "Arabic", "he": "Hebrew",
+	"hi": "Hindi", "th": "Thai", "zh": "Chinese", "ja": "Japanese", "ko": "Korean",
+	"vi": "Vietnamese", "id": "Indonesian",
+}
+
+// nativeNames maps names users may write in the language itself
+var nativeNames = map[string]string{
+	"español": "es", "castellano": "es", "français": "fr", "francais": "fr", "deutsch": "de",
+	"italiano": "it", "português": "pt", "portugues": "pt", "nederlands": "nl", "svenska": "sv",
+	"polski": "pl", "русский": "ru", "українська": "uk", "ελληνικά": "el", "türkçe": "tr",
+	"中文": "zh", "日本語": "ja", "한국어": "ko", "mandarin": "zh",
+}
+
+// Name returns the English name of a language code, or the code itself if unknown
+func Name(code string) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return code
+}
+
+// Normalize maps a language code, English name or native name to its ISO 639-1 code.
+// It returns false for unsupported languages.
+func Normalize(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if _, ok := names[s]; ok {
+		return s, true
+	}
+	if code, ok := nativeNames[s]; ok {
+		return code, true
+	}
+	for code, name := range names {
+		if strings.ToLower(name) == s {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// stopwords holds frequent function words of languages written in Latin or Cyrillic script
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "that", "it", "for", "was", "with", "as", "on", "are", "this", "be", "by", "have", "from", "not", "but", "they", "has", "which", "were", "said"},
+	"es": {"el", "la", "de", "que", "y", "los", "del", "se", "las", "por", "un", "una", "para", "con", "es", "al", "lo", "como", "más", "pero", "sus", "fue", "este", "ha", "según"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "du", "que", "qui", "dans", "pour", "pas", "sur", "au", "avec", "ce", "il", "elle", "sont", "ont", "mais", "été"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "von", "mit", "sich", "des", "auf", "für", "im", "dem", "auch", "wird", "sind", "wurde", "bei", "nach", "oder"},
+	"it": {"il", "di", "che", "e", "la", "un", "una", "per", "non", "sono", "del", "della", "con", "gli", "nel", "alla", "da", "si", "ha", "anche", "come", "più", "ma", "questo", "è"},
+	"pt": {"o", "a", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "os", "as", "no", "na", "dos", "por", "mais", "se", "foi", "ao", "é", "são"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "op", "te", "zijn", "met", "voor", "niet", "er", "aan", "ook", "als", "bij", "door", "maar", "om", "dit", "wordt", "werd", "naar"},
+	"sv": {"och", "att", "det", "som", "en", "på", "är", "av", "för", "med", "till", "den", "har", "inte", "om", "ett", "var", "men", "från", "vid", "kan", "sig", "också", "när", "efter"},
+	"pl": {"i", "w", "na", "z", "się", "nie", "do", "że", "to", "jest", "o", "jak", "od", "po", "ale", "za", "co", "dla", "są", "przez", "tak", "było", "też", "jego", "już"},
+	"ru": {"и", "в", "не", "на", "что", "с", "по", "он", "как", "это", "к", "из", "а", "за", "для", "от", "его", "но", "также", "было", "так", "был", "при", "она", "они"},
+	"uk": {"і", "в", "не", "на", "що", "з", "по", "як", "це", "до", "та", "від", "за", "для", "його", "але", "також", "було", "так", "був", "при", "вона", "вони", "у", "є"},
+}
+
+var stopwordSets = func() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(stopwords))
+	for code, words := range stopwords {
+		set := make(map[string]bool, len(words))
+		for _, w := range words {
+			set[w] = true
+		}
+		sets[code] = set
+	}
+	return sets
+}()
+
+const (
+	maxDetectWords  = 2000 // Words examined; the start of an article is enough
+	minStopwordHits = 5    // Below this the text is too short to call
+)
+
+// Detect returns the ISO 639-1 code of the text's language, or "" if it cannot tell.
+// Non-Latin scripts are identified by their characters, Latin and Cyrillic languages by
+// the frequency of common function words.
+func Detect(text string) string {
+	if code := detectScript(text); code != "" {
+		return code
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) > maxDetectWords {
+		words = words[:maxDetectWords]
+	}
+
+	best, bestHits := "", 0
+	for code, set := range stopwordSets {
+		hits := 0
+		for _, w := range words {
+			if set[w] {
+				hits++
+			}
+		}
+		if hits > bestHits || (hits == bestHits && code < best) {
+			best, bestHits = code, hits
+		}
+	}
+	if bestHits < minStopwordHits {
+		return ""
+	}
+	return best
+}
+
+// detectScript identifies languages that have a script of their own
+func detectScript(text string) string {
+	counts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			counts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Hebrew, r):
+			counts["he"]++
+		case unicode.Is(unicode.Greek, r):
+			counts["el"]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts["hi"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Japanese mixes kana with kanji, so any substantial kana means Japanese
+	if counts["ja"] > 0 && counts["ja"]*10 >= letters {
+		return "ja"
+	}
+	best, bestCount := "", 0
+	for code, n := range counts {
+		if n > bestCount || (n == bestCount && code < best) {
+			best, bestCount = code, n
+		}
+	}
+	if bestCount*2 < letters {
+		return ""
+	}
+	return best
+}
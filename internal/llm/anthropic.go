@@ -0,0 +1,187 @@
+package llm
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/prompts"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	anthropicBaseURL      = "https://api.anthropic.com/v1/messages"
+	anthropicVersion      = "2023-06-01"
+	anthropicContextLimit = 200000
+	anthropicOutputLimit  = 4096
+
+	// DefaultAnthropicModel is used when no model is configured
+	DefaultAnthropicModel = "claude-3-5-sonnet-latest"
+)
+
+// Embedder produces vector embeddings. Anthropic has no embedding API, so the
+// Anthropic client delegates embeddings to another provider.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// AnthropicClient implements Client against the Anthropic Messages API
+type AnthropicClient struct {
+	apiKey   string
+	model    string
+	baseURL  string
+	http     *http.Client
+	embedder Embedder
+	prompts  *prompts.Factory
+}
+
+// NewAnthropic creates an Anthropic client. embedder may be nil, in which case Embed returns an error.
+func NewAnthropic(apiKey, model string, embedder Embedder) *AnthropicClient {
+	if model == "" {
+		model = DefaultAnthropicModel
+	}
+	return &AnthropicClient{
+		apiKey:   apiKey,
+		model:    model,
+		baseURL:  anthropicBaseURL,
+		http:     &http.Client{Timeout: 120 * time.Second},
+		embedder: embedder,
+		prompts:  prompts.Default(),
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature"`
+	Messages    []anthropicMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// complete sends a single-turn message and returns the concatenated text response
+func (a *AnthropicClient) complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	body, err := json.Marshal(anthropicRequest{
+		Model:       a.model,
+		MaxTokens:   maxTokens,
+		Temperature: 0,
+		Messages:    []anthropicMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal anthropic request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("anthropic request failed (model=%s): %w", a.model, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var parsed anthropicResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse anthropic response (status=%d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || parsed.Error != nil {
+		msg := resp.Status
+		if parsed.Error != nil {
+			msg = parsed.Error.Type + ": " + parsed.Error.Message
+		}
+		return "", fmt.Errorf("anthropic API error (model=%s, status=%d): %s", a.model, resp.StatusCode, msg)
+	}
+
+	var out strings.Builder
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			out.WriteString(block.Text)
+		}
+	}
+	if out.Len() == 0 {
+		return "", fmt.Errorf("no text content returned from Anthropic API")
+	}
+	return out.String(), nil
+}
+
+func (a *AnthropicClient) Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error) {
+	maxInput := anthropicContextLimit - anthropicOutputLimit - 200
+	truncated := truncateTextForModel(text, maxInput)
+	return a.complete(ctx, summarizePrompt(truncated, opts), anthropicOutputLimit)
+}
+
+func (a *AnthropicClient) SentimentScore(ctx context.Context, text string) (float64, error) {
+	resp, err := a.complete(ctx, fmt.Sprintf("Analyze the sentiment of this text and return only a number between -1 (very negative) and 1 (very positive):\n%s", text), 16)
+	if err != nil {
+		return 0, err
+	}
+	score, err := strconv.ParseFloat(strings.TrimSpace(resp), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse sentiment score: %w", err)
+	}
+	return score, nil
+}
+
+func (a *AnthropicClient) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	return a.complete(ctx, fmt.Sprintf("Compare tone across these summaries:\n%s\n---\n%s", text1, text2), anthropicOutputLimit)
+}
+
+func (a *AnthropicClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if a.embedder == nil {
+		return nil, fmt.Errorf("anthropic provider has no embedding API; configure an embedding client")
+	}
+	return a.embedder.Embed(ctx, text)
+}
+
+func (a *AnthropicClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return a.complete(ctx, prompt, anthropicOutputLimit)
+}
+
+func (a *AnthropicClient) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	prompt, err := a.prompts.Planner(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.complete(ctx, prompt, 500)
+	if err != nil {
+		return nil, err
+	}
+	return parsePlan(strings.TrimSpace(resp))
+}
+
+func (a *AnthropicClient) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	resp, err := a.complete(ctx, semanticsPrompt(text), anthropicOutputLimit)
+	if err != nil {
+		return nil, err
+	}
+	return parseSemanticAnalysis(strings.TrimSpace(resp)), nil
+}
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"fmt"
+
+	"article-assistant/internal/prompts"
+	"article-assistant/internal/usage"
+)
+
+// Supported LLM providers
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderGemini    = "gemini"
+	ProviderScenario  = "scenario" // Scripted responses from a fixture file, for end-to-end tests
+)
+
+// Config selects and configures an LLM provider
+type Config struct {
+	Provider        string            // openai (default), anthropic, gemini or scenario
+	APIKey          string            // API key for the selected provider
+	Model           string            // Provider-specific model name
+	TaskModels      map[string]string // Optional OpenAI model per Task*; tasks not listed use Model
+	AllowedModels   []string          // Further models a chat plan may ask for, besides Model and TaskModels
+	EmbeddingAPIKey string            // OpenAI key used for embeddings by providers without an embedding API
+	Ledger          *usage.Ledger     // Optional: daily token/cost totals for OpenAI calls
+	Retry           *RetryConfig      // Optional: OpenAI retry and circuit breaker settings; nil uses DefaultRetryConfig
+	Prompts         *prompts.Factory  // Optional: prompt versions to use; nil uses prompts.Default
+	Scenario        string            // YAML fixture read by the scenario provider
+}
+
+// newOpenAI creates an OpenAI client with the config's ledger, retry and prompt settings
+func newOpenAI(cfg Config, apiKey, model string) *OpenAIClient {
+	c := New(apiKey, model)
+	c.taskModels = cfg.TaskModels
+	c.ledger = cfg.Ledger
+	if cfg.Prompts != nil {
+		c.prompts = cfg.Prompts
+	}
+	if cfg.Retry != nil {
+		c.retry = NewRetrier(*cfg.Retry)
+	}
+	return c
+}
+
+// NewClient creates the LLM client for the configured provider
+func NewClient(cfg Config) (Client, error) {
+	switch cfg.Provider {
+	case "", ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("openai provider requires an API key")
+		}
+		return newOpenAI(cfg, cfg.APIKey, cfg.Model), nil
+
+	case ProviderAnthropic:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("anthropic provider requires an API key")
+		}
+		var embedder Embedder
+		if cfg.EmbeddingAPIKey != "" {
+			embedder = newOpenAI(cfg, cfg.EmbeddingAPIKey, "")
+		}
+		c := NewAnthropic(cfg.APIKey, cfg.Model, embedder)
+		if cfg.Prompts != nil {
+			c.prompts = cfg.Prompts
+		}
+		return c, nil
+
+	case ProviderGemini:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("gemini provider requires an API key")
+		}
+		c := NewGemini(cfg.APIKey, cfg.Model)
+		if cfg.Prompts != nil {
+			c.prompts = cfg.Prompts
+		}
+		return c, nil
+
+	case ProviderScenario:
+		if cfg.Scenario == "" {
+			return nil, fmt.Errorf("scenario provider requires a scenario file")
+		}
+		return LoadScenario(cfg.Scenario)
+
+	default:
+		return nil, fmt.Errorf("unknown LLM provider: %s", cfg.Provider)
+	}
+}
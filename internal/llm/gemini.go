@@ -0,0 +1,208 @@
+package llm
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/prompts"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	geminiBaseURL       = "https://generativelanguage.googleapis.com/v1beta/models"
+	geminiContextLimit  = 1000000
+	geminiOutputLimit   = 8192
+	geminiEmbeddingDims = 1536 // Matches the articles.embedding vector column
+
+	// ModelGemini15Flash is the default Gemini generation model
+	ModelGemini15Flash = "gemini-1.5-flash"
+	// ModelGeminiEmbedding is the Gemini embedding model; it supports reduced output dimensionality
+	ModelGeminiEmbedding = "gemini-embedding-001"
+)
+
+// GeminiClient implements Client against the Gemini generateContent/embedContent REST API
+type GeminiClient struct {
+	apiKey  string
+	model   string
+	baseURL string
+	http    *http.Client
+	prompts *prompts.Factory
+}
+
+// NewGemini creates a Gemini client
+func NewGemini(apiKey, model string) *GeminiClient {
+	if model == "" {
+		model = ModelGemini15Flash
+	}
+	return &GeminiClient{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: geminiBaseURL,
+		http:    &http.Client{Timeout: 120 * time.Second},
+		prompts: prompts.Default(),
+	}
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// post sends a JSON request to {baseURL}/{model}:{method} and decodes the response into out
+func (g *GeminiClient) post(ctx context.Context, model, method string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal gemini request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s:%s", g.baseURL, model, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// In a header rather than the ?key= parameter, so transport errors, which quote the URL,
+	// never carry the key into responses and logs
+	req.Header.Set("x-goog-api-key", g.apiKey)
+
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("gemini request failed (model=%s): %w", model, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error geminiError `json:"error"`
+		}
+		_ = json.Unmarshal(raw, &e)
+		return fmt.Errorf("gemini API error (model=%s, status=%d): %s %s", model, resp.StatusCode, e.Error.Status, e.Error.Message)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to parse gemini response: %w", err)
+	}
+	return nil
+}
+
+// complete runs a single-turn generateContent call and returns the text of the first candidate
+func (g *GeminiClient) complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	body := map[string]interface{}{
+		"contents": []geminiContent{{Role: "user", Parts: []geminiPart{{Text: prompt}}}},
+		"generationConfig": map[string]interface{}{
+			"temperature":     0,
+			"maxOutputTokens": maxTokens,
+		},
+	}
+
+	var resp struct {
+		Candidates []struct {
+			Content      geminiContent `json:"content"`
+			FinishReason string        `json:"finishReason"`
+		} `json:"candidates"`
+	}
+	if err := g.post(ctx, g.model, "generateContent", body, &resp); err != nil {
+		return "", err
+	}
+
+	if len(resp.Candidates) == 0 {
+		return "", fmt.Errorf("no candidates returned from Gemini API")
+	}
+
+	var out strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		out.WriteString(part.Text)
+	}
+	if out.Len() == 0 {
+		return "", fmt.Errorf("empty response from Gemini API (finish reason: %s)", resp.Candidates[0].FinishReason)
+	}
+	return out.String(), nil
+}
+
+func (g *GeminiClient) Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error) {
+	maxInput := geminiContextLimit - geminiOutputLimit - 200
+	truncated := truncateTextForModel(text, maxInput)
+	return g.complete(ctx, summarizePrompt(truncated, opts), geminiOutputLimit)
+}
+
+func (g *GeminiClient) SentimentScore(ctx context.Context, text string) (float64, error) {
+	resp, err := g.complete(ctx, fmt.Sprintf("Analyze the sentiment of this text and return only a number between -1 (very negative) and 1 (very positive):\n%s", text), 16)
+	if err != nil {
+		return 0, err
+	}
+	score, err := strconv.ParseFloat(strings.TrimSpace(resp), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse sentiment score: %w", err)
+	}
+	return score, nil
+}
+
+func (g *GeminiClient) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	return g.complete(ctx, fmt.Sprintf("Compare tone across these summaries:\n%s\n---\n%s", text1, text2), geminiOutputLimit)
+}
+
+func (g *GeminiClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	body := map[string]interface{}{
+		"model":                "models/" + ModelGeminiEmbedding,
+		"content":              geminiContent{Parts: []geminiPart{{Text: text}}},
+		"outputDimensionality": geminiEmbeddingDims,
+	}
+
+	var resp struct {
+		Embedding struct {
+			Values []float32 `json:"values"`
+		} `json:"embedding"`
+	}
+	if err := g.post(ctx, ModelGeminiEmbedding, "embedContent", body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("no embedding returned from Gemini API")
+	}
+	return resp.Embedding.Values, nil
+}
+
+func (g *GeminiClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return g.complete(ctx, prompt, geminiOutputLimit)
+}
+
+func (g *GeminiClient) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	prompt, err := g.prompts.Planner(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.complete(ctx, prompt, 500)
+	if err != nil {
+		return nil, err
+	}
+	return parsePlan(strings.TrimSpace(resp))
+}
+
+func (g *GeminiClient) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	resp, err := g.complete(ctx, semanticsPrompt(text), geminiOutputLimit)
+	if err != nil {
+		return nil, err
+	}
+	return parseSemanticAnalysis(strings.TrimSpace(resp)), nil
+}
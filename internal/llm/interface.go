@@ -6,7 +6,7 @@ import (
 )
 
 type Client interface {
-	Summarize(ctx context.Context, text string) (string, error)
+	Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error)
 	SentimentScore(ctx context.Context, text string) (float64, error)
 	ToneCompare(ctx context.Context, text1, text2 string) (string, error)
 	Embed(ctx context.Context, text string) ([]float32, error)
@@ -17,6 +17,8 @@ type Client interface {
 	ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error)
 }
 
-// Ensure both implementations satisfy the interface
+// Ensure all implementations satisfy the interface
 var _ Client = (*OpenAIClient)(nil)
+var _ Client = (*AnthropicClient)(nil)
+var _ Client = (*GeminiClient)(nil)
 var _ Client = (*MockClient)(nil)
@@ -0,0 +1,158 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"article-assistant/internal/domain"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// ErrBusy is returned when a call waited the limiter's MaxWait without a free slot
+var ErrBusy = errors.New("llm unavailable: too many concurrent calls")
+
+// Call weights: an embedding takes half a completion's slot, since embedding requests are short
+// and have their own, higher provider rate limit
+const (
+	completionWeight = 2
+	embedWeight      = 1
+)
+
+// Default limiter settings
+const (
+	DefaultMaxConcurrency = 8
+	DefaultMaxWait        = 10 * time.Second
+)
+
+// LimitConfig sizes the shared limit on concurrent LLM calls
+type LimitConfig struct {
+	MaxConcurrency int           // Completion calls in flight at most; 0 disables the limit
+	MaxWait        time.Duration // How long a call may queue for a slot; 0 waits as long as its context allows
+}
+
+// Limiter bounds the LLM calls in flight across all requests and background work, so a burst
+// of chats cannot exceed the provider's rate limits. Calls over the limit queue in arrival
+// order for up to MaxWait. It is safe for concurrent use.
+type Limiter struct {
+	cfg      LimitConfig
+	sem      *semaphore.Weighted
+	capacity int64
+
+	inFlight atomic.Int64 // Weight of running calls
+	waiting  atomic.Int64 // Calls queued for a slot
+	rejected atomic.Int64 // Calls that gave up after MaxWait
+}
+
+// NewLimiter creates a limiter; nil when cfg.MaxConcurrency is 0
+func NewLimiter(cfg LimitConfig) *Limiter {
+	if cfg.MaxConcurrency <= 0 {
+		return nil
+	}
+	capacity := int64(cfg.MaxConcurrency) * completionWeight
+	return &Limiter{cfg: cfg, sem: semaphore.NewWeighted(capacity), capacity: capacity}
+}
+
+// acquire waits for weight units and returns the function releasing them
+func (l *Limiter) acquire(ctx context.Context, weight int64) (func(), error) {
+	waitCtx := ctx
+	if l.cfg.MaxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.cfg.MaxWait)
+		defer cancel()
+	}
+
+	l.waiting.Add(1)
+	err := l.sem.Acquire(waitCtx, weight)
+	l.waiting.Add(-1)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		l.rejected.Add(1)
+		return nil, ErrBusy
+	}
+	l.inFlight.Add(weight)
+	return func() {
+		l.inFlight.Add(-weight)
+		l.sem.Release(weight)
+	}, nil
+}
+
+// WritePrometheus writes the limiter's gauges and rejection counter in the Prometheus text format
+func (l *Limiter) WritePrometheus(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `# HELP llm_limiter_in_flight Weight of LLM calls running; a completion weighs %[1]d, an embedding %[2]d.
+# TYPE llm_limiter_in_flight gauge
+llm_limiter_in_flight %[3]d
+# HELP llm_limiter_capacity Maximum weight of LLM calls running.
+# TYPE llm_limiter_capacity gauge
+llm_limiter_capacity %[4]d
+# HELP llm_limiter_waiting LLM calls queued for a slot.
+# TYPE llm_limiter_waiting gauge
+llm_limiter_waiting %[5]d
+# HELP llm_limiter_rejected_total LLM calls that gave up after waiting the maximum time.
+# TYPE llm_limiter_rejected_total counter
+llm_limiter_rejected_total %[6]d
+`, completionWeight, embedWeight, l.inFlight.Load(), l.capacity, l.waiting.Load(), l.rejected.Load())
+	return err
+}
+
+// LimitedClient runs every call of an llm.Client through a Limiter
+type LimitedClient struct {
+	Inner   Client
+	Limiter *Limiter
+}
+
+var _ Client = (*LimitedClient)(nil)
+
+// WithLimit wraps inner so its calls share limiter's slots; inner is returned as is when
+// limiter is nil
+func WithLimit(inner Client, limiter *Limiter) Client {
+	if limiter == nil {
+		return inner
+	}
+	return &LimitedClient{Inner: inner, Limiter: limiter}
+}
+
+// limited runs fn holding weight units of the limiter
+func limited[T any](ctx context.Context, l *Limiter, weight int64, fn func() (T, error)) (T, error) {
+	release, err := l.acquire(ctx, weight)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer release()
+	return fn()
+}
+
+func (c *LimitedClient) Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error) {
+	return limited(ctx, c.Limiter, completionWeight, func() (string, error) { return c.Inner.Summarize(ctx, text, opts) })
+}
+
+func (c *LimitedClient) SentimentScore(ctx context.Context, text string) (float64, error) {
+	return limited(ctx, c.Limiter, completionWeight, func() (float64, error) { return c.Inner.SentimentScore(ctx, text) })
+}
+
+func (c *LimitedClient) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	return limited(ctx, c.Limiter, completionWeight, func() (string, error) { return c.Inner.ToneCompare(ctx, text1, text2) })
+}
+
+func (c *LimitedClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	return limited(ctx, c.Limiter, embedWeight, func() ([]float32, error) { return c.Inner.Embed(ctx, text) })
+}
+
+func (c *LimitedClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return limited(ctx, c.Limiter, completionWeight, func() (string, error) { return c.Inner.GenerateText(ctx, prompt) })
+}
+
+func (c *LimitedClient) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	return limited(ctx, c.Limiter, completionWeight, func() (*domain.Plan, error) { return c.Inner.PlanQuery(ctx, query) })
+}
+
+func (c *LimitedClient) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	return limited(ctx, c.Limiter, completionWeight, func() (*domain.SemanticAnalysis, error) { return c.Inner.ExtractAllSemantics(ctx, text) })
+}
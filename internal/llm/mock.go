@@ -21,17 +21,16 @@ func NewMockClient() *MockClient {
 func (m *MockClient) Embed(ctx context.Context, text string) ([]float32, error) {
 	// Generate a deterministic embedding based on text length
 	embedding := make([]float32, 1536)
-	seed := int64(len(text))
-	rand.Seed(seed)
+	rng := rand.New(rand.NewSource(int64(len(text))))
 
 	for i := range embedding {
-		embedding[i] = rand.Float32()
+		embedding[i] = rng.Float32()
 	}
 	return embedding, nil
 }
 
 // Summarize returns a mock summary
-func (m *MockClient) Summarize(ctx context.Context, text string) (string, error) {
+func (m *MockClient) Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error) {
 	// Return a truncated version of the input as mock summary
 	if len(text) > 200 {
 		return text[:200] + "...", nil
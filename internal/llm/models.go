@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"article-assistant/internal/domain"
+	"context"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Tasks that can use their own OpenAI model; other calls use Config.Model
+const (
+	TaskPlanner   = "planner"   // PlanQuery
+	TaskSummarize = "summarize" // Summarize
+	TaskCompare   = "compare"   // Compare and ToneCompare
+	TaskEmbedding = "embedding" // Embed; changing it changes the embedding dimension
+
+	taskDefault = "" // Calls without a task setting
+)
+
+// TaskModelSettings are the settings that choose each task's OpenAI model
+var TaskModelSettings = map[string]string{
+	TaskPlanner:   "OPENAI_PLANNER_MODEL",
+	TaskSummarize: "OPENAI_SUMMARIZE_MODEL",
+	TaskCompare:   "OPENAI_COMPARE_MODEL",
+	TaskEmbedding: "OPENAI_EMBEDDING_MODEL",
+}
+
+// DefaultEmbeddingModel is used when no embedding model is configured
+const DefaultEmbeddingModel = string(openai.SmallEmbedding3)
+
+// EmbeddingModel returns the model that produces the config's embeddings
+func (c Config) EmbeddingModel() string {
+	if c.Provider == ProviderGemini {
+		return ModelGeminiEmbedding
+	}
+	if m := c.TaskModels[TaskEmbedding]; m != "" {
+		return m
+	}
+	return DefaultEmbeddingModel
+}
+
+// SummaryModel returns the model that summarizes articles at ingestion
+func (c Config) SummaryModel() string {
+	return c.taskModel(TaskSummarize)
+}
+
+// SemanticsModel returns the model that extracts articles' semantics at ingestion
+func (c Config) SemanticsModel() string {
+	return c.taskModel(taskDefault)
+}
+
+// taskModel returns the chat model the config's client uses for task, as modelFor does
+// without a requested model
+func (c Config) taskModel(task string) string {
+	switch c.Provider {
+	case ProviderAnthropic:
+		if c.Model == "" {
+			return DefaultAnthropicModel
+		}
+		return c.Model
+	case ProviderGemini:
+		if c.Model == "" {
+			return ModelGemini15Flash
+		}
+		return c.Model
+	}
+	if m := c.TaskModels[task]; m != "" {
+		return m
+	}
+	return c.Model
+}
+
+// AllowsModel reports whether a plan may ask for model: the default and task models are
+// allowed, plus AllowedModels
+func (c Config) AllowsModel(model string) bool {
+	if model == "" {
+		return false
+	}
+	if model == c.Model {
+		return true
+	}
+	for task, m := range c.TaskModels {
+		if m == model && task != TaskEmbedding {
+			return true
+		}
+	}
+	for _, m := range c.AllowedModels {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestedModel returns the model a plan asks for in its "model" arg, or ""
+func RequestedModel(plan *domain.Plan) string {
+	model, _ := plan.Args["model"].(string)
+	return strings.TrimSpace(model)
+}
+
+type modelCtxKey struct{}
+
+// WithModel returns a context whose chat calls use model instead of the configured one, for
+// requests that ask for a specific model. Embeddings are not affected.
+func WithModel(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, modelCtxKey{}, model)
+}
+
+// ModelFromContext returns the model set by WithModel, or ""
+func ModelFromContext(ctx context.Context) string {
+	model, _ := ctx.Value(modelCtxKey{}).(string)
+	return model
+}
+
+// modelFor returns the model for a call: the request's model, then the task's, then the default
+func (o *OpenAIClient) modelFor(ctx context.Context, task string) string {
+	if task == TaskEmbedding {
+		if m := o.taskModels[TaskEmbedding]; m != "" {
+			return m
+		}
+		return DefaultEmbeddingModel
+	}
+	if m := ModelFromContext(ctx); m != "" {
+		return m
+	}
+	if m := o.taskModels[task]; m != "" {
+		return m
+	}
+	return o.model
+}
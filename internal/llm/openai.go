@@ -3,26 +3,61 @@ package llm
 import (
 	"article-assistant/internal/domain"
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 
+	"article-assistant/internal/logging"
+	"article-assistant/internal/prompts"
+	"article-assistant/internal/usage"
+
 	"github.com/sashabaranov/go-openai"
 )
 
 type OpenAIClient struct {
-	c     *openai.Client
-	model string
+	c          *openai.Client
+	model      string
+	taskModels map[string]string // Optional model per Task*; others use model
+	ledger     *usage.Ledger     // Optional daily usage totals
+	retry      *Retrier
+	prompts    *prompts.Factory
 }
 
 func New(apiKey string, model string) *OpenAIClient {
 	return &OpenAIClient{
-		c:     openai.NewClient(apiKey),
-		model: model,
+		c:       openai.NewClient(apiKey),
+		model:   model,
+		retry:   NewRetrier(DefaultRetryConfig()),
+		prompts: prompts.Default(),
 	}
 }
 
+// chat creates a chat completion, retrying transient errors
+func (o *OpenAIClient) chat(ctx context.Context, req openai.ChatCompletionRequest) (resp openai.ChatCompletionResponse, err error) {
+	err = o.retry.Do(ctx, func() error {
+		resp, err = o.c.CreateChatCompletion(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// embed creates embeddings, retrying transient errors
+func (o *OpenAIClient) embed(ctx context.Context, req openai.EmbeddingRequestStrings) (resp openai.EmbeddingResponse, err error) {
+	err = o.retry.Do(ctx, func() error {
+		resp, err = o.c.CreateEmbeddings(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// record reports token usage of a call to the request tracker and daily ledger
+func (o *OpenAIClient) record(ctx context.Context, model string, u openai.Usage) {
+	logging.FromContext(ctx).Debug("llm call", "provider", "openai", "model", model,
+		"prompt_tokens", u.PromptTokens, "completion_tokens", u.CompletionTokens)
+	usage.Record(ctx, o.ledger, model, u.PromptTokens, u.CompletionTokens)
+}
+
 // getModelLimits returns context and output limits for different models
 func getModelLimits(model string) (int, int) {
 	switch model {
@@ -65,31 +100,31 @@ func calculateBudgets(inputText string, model string) (int, int) {
 		maxOutputTokens = 50
 	}
 
-	fmt.Printf("Token budget: input=%d (allowed=%d), output=%d\n",
-		inputTokens, maxInputTokens, maxOutputTokens)
+	slog.Debug("token budget", "model", model, "input_tokens", inputTokens, "allowed_input_tokens", maxInputTokens, "output_tokens", maxOutputTokens)
 
 	return maxInputTokens, maxOutputTokens
 }
 
-func (o *OpenAIClient) Summarize(ctx context.Context, text string) (string, error) {
-	totalInputTokens, maxOutputTokens := calculateBudgets(text, o.model)
-	fmt.Printf("Summarize: Original text length: %d chars, estimated tokens: %d\n", len(text), len(text)/4)
-	fmt.Printf("Summarize: Token budget: input=%d, output=%d\n", totalInputTokens, maxOutputTokens)
+func (o *OpenAIClient) Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error) {
+	model := o.modelFor(ctx, TaskSummarize)
+	totalInputTokens, maxOutputTokens := calculateBudgets(text, model)
 	truncatedText := truncateTextForModel(text, totalInputTokens)
-	fmt.Printf("Summarize: Truncated text length: %d chars\n", len(truncatedText))
+	logging.FromContext(ctx).Debug("summarize input", "model", model, "chars", len(text), "estimated_tokens", len(text)/4,
+		"truncated_chars", len(truncatedText), "max_output_tokens", maxOutputTokens)
 
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: o.model,
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
+		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
-			Content: "Summarize this text concisely while preserving key information:\n" + truncatedText,
+			Content: summarizePrompt(truncatedText, opts),
 		}},
 		MaxTokens:   maxOutputTokens,
 		Temperature: 0,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create chat completion for summarization (model=%s, tokens=%d): %w", o.model, maxOutputTokens, err)
+		return "", fmt.Errorf("failed to create chat completion for summarization (model=%s, tokens=%d): %w", model, maxOutputTokens, err)
 	}
+	o.record(ctx, model, resp.Usage)
 
 	if len(resp.Choices) == 0 {
 		return "", fmt.Errorf("no choices returned from OpenAI API for summarization")
@@ -100,14 +135,14 @@ func (o *OpenAIClient) Summarize(ctx context.Context, text string) (string, erro
 
 func (o *OpenAIClient) Compare(ctx context.Context, summaries []string) (string, error) {
 	joined := strings.Join(summaries, "\n---\n")
-	model := o.model
+	model := o.modelFor(ctx, TaskCompare)
 	_, maxOutputTokens := calculateBudgets(joined, model) // Comparison needs detailed output
 
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
-			Content: "Compare these summaries and highlight key differences:\n" + joined,
+			Content: ComparePrompt(summaries),
 		}},
 		MaxTokens:   maxOutputTokens,
 		Temperature: 0, // Consistent comparisons
@@ -115,6 +150,7 @@ func (o *OpenAIClient) Compare(ctx context.Context, summaries []string) (string,
 	if err != nil {
 		return "", fmt.Errorf("failed to create chat completion for comparison (model=%s, summaries=%d): %w", model, len(summaries), err)
 	}
+	o.record(ctx, model, resp.Usage)
 
 	if len(resp.Choices) == 0 {
 		return "", fmt.Errorf("no choices returned from OpenAI API for comparison")
@@ -124,10 +160,10 @@ func (o *OpenAIClient) Compare(ctx context.Context, summaries []string) (string,
 }
 
 func (o *OpenAIClient) GenerateText(ctx context.Context, prompt string) (string, error) {
-	model := o.model
+	model := o.modelFor(ctx, taskDefault)
 	_, maxTokens := calculateBudgets(prompt, model)
 
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
@@ -139,16 +175,17 @@ func (o *OpenAIClient) GenerateText(ctx context.Context, prompt string) (string,
 	if err != nil {
 		return "", err
 	}
+	o.record(ctx, model, resp.Usage)
 
 	return resp.Choices[0].Message.Content, nil
 }
 
 func (o *OpenAIClient) SentimentScore(ctx context.Context, text string) (float64, error) {
-	model := o.model
+	model := o.modelFor(ctx, taskDefault)
 
 	_, maxOutputTokens := calculateBudgets(text, model)
 
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
@@ -160,6 +197,7 @@ func (o *OpenAIClient) SentimentScore(ctx context.Context, text string) (float64
 	if err != nil {
 		return 0, err
 	}
+	o.record(ctx, model, resp.Usage)
 
 	// Parse the response as a float64
 	scoreStr := strings.TrimSpace(resp.Choices[0].Message.Content)
@@ -172,23 +210,25 @@ func (o *OpenAIClient) SentimentScore(ctx context.Context, text string) (float64
 }
 
 func (o *OpenAIClient) Embed(ctx context.Context, text string) ([]float32, error) {
-	resp, err := o.c.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+	model := o.modelFor(ctx, TaskEmbedding)
+	resp, err := o.embed(ctx, openai.EmbeddingRequestStrings{
 		Input: []string{text},
-		Model: openai.SmallEmbedding3,
+		Model: openai.EmbeddingModel(model),
 	})
 	if err != nil {
 		return nil, err
 	}
+	o.record(ctx, model, resp.Usage)
 
 	return resp.Data[0].Embedding, nil
 }
 
 func (o *OpenAIClient) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
 	joined := fmt.Sprintf("%s\n---\n%s", text1, text2)
-	model := o.model
+	model := o.modelFor(ctx, TaskCompare)
 	_, maxOutputTokens := calculateBudgets(joined, model) // Tone analysis is more concise
 
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
@@ -200,35 +240,19 @@ func (o *OpenAIClient) ToneCompare(ctx context.Context, text1, text2 string) (st
 	if err != nil {
 		return "", err
 	}
+	o.record(ctx, model, resp.Usage)
 
 	return resp.Choices[0].Message.Content, nil
 }
 
 func (o *OpenAIClient) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
-	model := o.model
+	model := o.modelFor(ctx, taskDefault)
 	_, maxOutputTokens := calculateBudgets(text, model) // Conservative ratio for semantic extraction to prevent response overflow
 	// Truncate for semantic extraction
 
-	prompt := fmt.Sprintf(`Extract entities, keywords, topics, sentiment, and tone from this text. Return JSON in this exact format:
-{
-  "entities": [{"name": "entity_name", "category": "person|organization|location|technology|other", "confidence": 0.85}],
-  "keywords": [{"term": "keyword", "relevance": 0.8, "context": "brief context"}],
-  "topics": [{"name": "topic_name", "score": 0.75, "description": "brief description"}],
-  "sentiment": "positive|negative|neutral",
-  "sentiment_score": 0.75,
-  "tone": "professional|casual|analytical|critical|optimistic|pessimistic"
-}
-
-Rules:
-- Extract 3-7 entities, 5-10 keywords, 2-5 topics
-- sentiment_score must be a number between 0.0 and 1.0
-- Only include items with confidence/relevance/score >= 0.6
-- Sort by score/confidence/relevance (highest first)
-- Return valid JSON only
-
-Text: %s`, text)
+	prompt := semanticsPrompt(text)
 
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
@@ -236,62 +260,41 @@ Text: %s`, text)
 		}},
 		MaxTokens:   maxOutputTokens,
 		Temperature: 0, // Deterministic results for structured data extraction
+		Tools:       []openai.Tool{semanticsTool},
+		ToolChoice:  forceTool(semanticsToolName),
 	})
 	if err != nil {
 		return nil, err
 	}
+	o.record(ctx, model, resp.Usage)
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned from OpenAI API for semantic extraction")
+	}
 
-	var analysis domain.SemanticAnalysis
-	jsonStr := strings.TrimSpace(resp.Choices[0].Message.Content)
-
-	// Debug: log the raw response
-	fmt.Printf("LLM Response: %s\n", jsonStr)
-
-	if err := json.Unmarshal([]byte(jsonStr), &analysis); err != nil {
-		// Try to clean up the JSON response and parse again
-		cleaned := cleanJSONResponse(jsonStr)
-		if err := json.Unmarshal([]byte(cleaned), &analysis); err != nil {
-			fmt.Printf("Failed to parse JSON response: %v\n", err)
-			return createEmptySemanticAnalysis(), nil
+	// The schema is enforced on the function arguments; plain content is only a fallback
+	msg := resp.Choices[0].Message
+	if args, ok := toolArguments(msg, semanticsToolName); ok {
+		analysis, err := ParseSemanticsCall(args)
+		if err != nil {
+			return nil, err
 		}
+		return analysis, nil
 	}
 
-	return &analysis, nil
+	jsonStr := strings.TrimSpace(msg.Content)
+	logging.FromContext(ctx).Debug("semantic extraction returned content instead of a function call", "model", model, "response", jsonStr)
+	return parseSemanticAnalysis(jsonStr), nil
 }
 
 func (o *OpenAIClient) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
-	model := o.model
-
-	prompt := fmt.Sprintf(`You are a query planner for an article assistant. Map user queries to commands with arguments.
-
-Supported commands:
-- summary: Get summary of specific articles (requires URLs)
-- keywords_or_topics: Extract keywords/topics from articles (requires URLs)  
-- get_sentiment: Get sentiment of articles (requires URLs)
-- compare_articles: Compare multiple articles (requires URLs)
-- ton_key_differences: Analyze tone differences between articles (requires URLs)
-- filter_by_specific_topic: Find articles by topic/filter (uses filter argument)
-- most_positive_article_for_filter: Find most positive article about a topic (uses filter argument)
-- get_top_entities: Get most common entities across all articles (no arguments)
-
-Rules:
-1. Extract URLs from query if provided - PRESERVE EXACT URL FORMAT including trailing slashes
-2. Extract filter/topic from query for search commands
-3. Return JSON in this exact format:
-{"command": "command_name", "args": {"urls": ["url1"], "filter": "topic"}}
-
-Examples:
-- "Summary of https://example.com/" → {"command": "summary", "args": {"urls": ["https://example.com/"]}}
-- "Compare https://site1.com/ and https://site2.com/" → {"command": "compare_articles", "args": {"urls": ["https://site1.com/", "https://site2.com/"]}}
-- "What articles discuss AI?" → {"command": "filter_by_specific_topic", "args": {"filter": "AI"}}
-- "Most positive about AI regulation" → {"command": "most_positive_article_for_filter", "args": {"filter": "AI regulation"}}
-- "Top entities" → {"command": "get_top_entities", "args": {}}
-
-IMPORTANT: Always preserve the exact URL format from the user query, including trailing slashes!
-
-Query: %s`, query)
-
-	resp, err := o.c.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	model := o.modelFor(ctx, TaskPlanner)
+
+	prompt, err := o.prompts.Planner(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.chat(ctx, openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{{
 			Role:    "user",
@@ -299,23 +302,22 @@ Query: %s`, query)
 		}},
 		MaxTokens:   500,
 		Temperature: 0, // Deterministic planning
+		Tools:       []openai.Tool{planTool},
+		ToolChoice:  forceTool(planToolName),
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	var plan domain.Plan
-	jsonStr := strings.TrimSpace(resp.Choices[0].Message.Content)
-
-	if err := json.Unmarshal([]byte(jsonStr), &plan); err != nil {
-		// Try to clean up the JSON response and parse again
-		cleaned := cleanJSONResponse(jsonStr)
-		if err := json.Unmarshal([]byte(cleaned), &plan); err != nil {
-			return nil, fmt.Errorf("failed to parse plan JSON: %v", err)
-		}
+	o.record(ctx, model, resp.Usage)
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned from OpenAI API for planning")
 	}
 
-	return &plan, nil
+	msg := resp.Choices[0].Message
+	if args, ok := toolArguments(msg, planToolName); ok {
+		return ParsePlanCall(args)
+	}
+	return parsePlan(strings.TrimSpace(msg.Content))
 }
 
 // truncateTextForModel truncates text to fit within model context limits
@@ -335,31 +337,6 @@ func truncateTextForModel(text string, maxInputTokens int) string {
 
 	// Truncate and add ellipsis
 	truncated := text[:maxChars-3] + "..."
-	fmt.Printf("Truncation: Truncated to %d chars (estimated %d tokens)\n", len(truncated), len(truncated)/4)
+	slog.Debug("truncated input", "chars", len(truncated), "estimated_tokens", len(truncated)/4)
 	return truncated
 }
-
-// cleanJSONResponse attempts to clean malformed JSON responses
-func cleanJSONResponse(jsonStr string) string {
-	// Remove markdown code blocks
-	cleaned := strings.TrimPrefix(jsonStr, "```json")
-	cleaned = strings.TrimPrefix(cleaned, "```")
-	cleaned = strings.TrimSuffix(cleaned, "```")
-
-	// Remove any leading/trailing whitespace
-	cleaned = strings.TrimSpace(cleaned)
-
-	return cleaned
-}
-
-// createEmptySemanticAnalysis creates an empty semantic analysis as fallback
-func createEmptySemanticAnalysis() *domain.SemanticAnalysis {
-	return &domain.SemanticAnalysis{
-		Entities:       []domain.SemanticEntity{},
-		Keywords:       []domain.SemanticKeyword{},
-		Topics:         []domain.SemanticTopic{},
-		Sentiment:      "neutral",
-		SentimentScore: 0.5,
-		Tone:           "neutral",
-	}
-}
@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"article-assistant/internal/domain"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Prompts and response parsing shared by all providers
+
+// SemanticsVersion identifies semanticsPrompt and the parsing of its response. Bump it when
+// either changes, so go run ./cmd/reanalyze re-runs the extraction over stored articles.
+const SemanticsVersion = "v1"
+
+// semanticsPrompt builds the combined entity/keyword/topic/sentiment extraction prompt
+func semanticsPrompt(text string) string {
+	return fmt.Sprintf(`Extract entities, keywords, topics, sentiment, and tone from this text. Return JSON in this exact format:
+{
+  "entities": [{"name": "entity_name", "category": "person|organization|location|technology|other", "confidence": 0.85}],
+  "keywords": [{"term": "keyword", "relevance": 0.8, "context": "brief context"}],
+  "topics": [{"name": "topic_name", "score": 0.75, "description": "brief description"}],
+  "sentiment": "positive|negative|neutral",
+  "sentiment_score": 0.75,
+  "tone": "professional|casual|analytical|critical|optimistic|pessimistic"
+}
+
+Rules:
+- Extract 3-7 entities, 5-10 keywords, 2-5 topics
+- sentiment_score must be a number between 0.0 and 1.0
+- Only include items with confidence/relevance/score >= 0.6
+- Sort by score/confidence/relevance (highest first)
+- Return valid JSON only
+
+Text: %s`, text)
+}
+
+// SummaryOptions shape a summary; the zero value asks for a concise prose summary
+type SummaryOptions struct {
+	Style    string // One of SummaryStyles; empty for prose
+	Bullets  int    // Number of points for the "bullets" style; 0 lets the model choose
+	MaxWords int    // Length limit in words; 0 for none
+	Language string // Language name to write in, e.g. "Spanish"; empty for the text's own language
+}
+
+// SummaryStyles are the supported SummaryOptions styles
+var SummaryStyles = []string{"bullets", "one_liner", "executive", "eli5"}
+
+// SummaryVersion identifies summarizePrompt's default style, which ingestion uses. Bump it when
+// the prompt changes, so articles summarized before can be told apart.
+const SummaryVersion = "v1"
+
+// summarizePrompt builds the summarization prompt for the given options
+func summarizePrompt(text string, opts SummaryOptions) string {
+	instruction := "Summarize this text concisely while preserving key information"
+	switch opts.Style {
+	case "bullets":
+		if opts.Bullets > 0 {
+			instruction = fmt.Sprintf("Summarize this text as exactly %d bullet points, one key point per line starting with \"- \"", opts.Bullets)
+		} else {
+			instruction = "Summarize this text as a short list of bullet points, one key point per line starting with \"- \""
+		}
+	case "one_liner":
+		instruction = "Summarize this text in a single sentence"
+	case "executive":
+		instruction = "Write an executive brief of this text: the bottom line first, then the key facts and their implications for a decision-maker"
+	case "eli5":
+		instruction = "Summarize this text so a ten-year-old could understand it, using simple words and short sentences"
+	}
+	if opts.MaxWords > 0 {
+		instruction += fmt.Sprintf(". Use at most %d words", opts.MaxWords)
+	}
+	if opts.Language != "" {
+		instruction += ". Write the summary in " + opts.Language
+	}
+	return instruction + ":\n" + text
+}
+
+// ComparePrompt builds the structured comparison prompt over any number of article texts,
+// which the model cites by their 1-based position
+func ComparePrompt(texts []string) string {
+	var b strings.Builder
+	b.WriteString(`Compare these articles. Identify the themes they share, points only one article makes, and where they disagree or contradict each other.
+Respond with only a JSON object, citing articles by number:
+{"summary": "one or two sentences on how the articles relate", "common_themes": ["..."], "unique_points": [{"citation": 1, "points": ["..."]}], "disagreements": [{"issue": "...", "positions": [{"citation": 1, "position": "..."}]}]}
+
+`)
+	for i, t := range texts {
+		b.WriteString(fmt.Sprintf("[%d] %s\n\n", i+1, t))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// DigestPrompt builds the multi-article digest prompt. Each entry describes one article (title and
+// summary), and the model cites entries by their 1-based position; scope describes what the
+// articles were selected by, e.g. a topic and date range.
+func DigestPrompt(scope string, entries []string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`Write a news digest of these articles about %s for a busy reader.
+Start with a short intro paragraph on the overall picture. Then group the most important developments into a few themed sections of bullet highlights, citing the articles each point comes from. Finally give every article a one-line takeaway.
+Respond with only a JSON object, citing articles by number:
+{"intro": "...", "sections": [{"heading": "...", "highlights": [{"text": "...", "citations": [1, 2]}]}], "articles": [{"citation": 1, "one_liner": "..."}]}
+
+`, scope))
+	for i, e := range entries {
+		b.WriteString(fmt.Sprintf("[%d] %s\n\n", i+1, e))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// EntityCanonicalizationPrompt asks for the canonical name of each entity name, e.g.
+// "Alphabet's Google" -> "Google"
+func EntityCanonicalizationPrompt(names []string) string {
+	var b strings.Builder
+	b.WriteString(`Give the canonical name of each named entity below: the name it is most widely known by, with its usual spelling and capitalization. Merge aliases, abbreviations and spelling variants of the same entity (e.g. "Open AI" -> "OpenAI", "U.S." -> "United States"). Keep names you do not recognize unchanged.
+Respond with only a JSON object mapping each name exactly as given to its canonical name.
+
+`)
+	for _, n := range names {
+		b.WriteString(fmt.Sprintf("- %s\n", n))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseSemanticAnalysis parses an extraction response, falling back to empty analysis on malformed JSON
+func parseSemanticAnalysis(jsonStr string) *domain.SemanticAnalysis {
+	var analysis domain.SemanticAnalysis
+	if err := json.Unmarshal([]byte(jsonStr), &analysis); err != nil {
+		// Try to clean up the JSON response and parse again
+		cleaned := CleanJSONResponse(jsonStr)
+		if err := json.Unmarshal([]byte(cleaned), &analysis); err != nil {
+			slog.Warn("failed to parse semantic analysis response", "error", err)
+			return createEmptySemanticAnalysis()
+		}
+	}
+	return &analysis
+}
+
+// parsePlan parses a planner response into a plan
+func parsePlan(jsonStr string) (*domain.Plan, error) {
+	var plan domain.Plan
+	if err := json.Unmarshal([]byte(jsonStr), &plan); err != nil {
+		// Try to clean up the JSON response and parse again
+		cleaned := CleanJSONResponse(jsonStr)
+		if err := json.Unmarshal([]byte(cleaned), &plan); err != nil {
+			return nil, fmt.Errorf("failed to parse plan JSON: %v", err)
+		}
+	}
+	return &plan, nil
+}
+
+// CleanJSONResponse attempts to clean malformed JSON responses
+func CleanJSONResponse(jsonStr string) string {
+	// Remove markdown code blocks
+	cleaned := strings.TrimPrefix(jsonStr, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	cleaned = strings.TrimSuffix(cleaned, "```")
+
+	// Remove any leading/trailing whitespace
+	cleaned = strings.TrimSpace(cleaned)
+
+	return cleaned
+}
+
+// createEmptySemanticAnalysis creates an empty semantic analysis as fallback
+func createEmptySemanticAnalysis() *domain.SemanticAnalysis {
+	return &domain.SemanticAnalysis{
+		Entities:       []domain.SemanticEntity{},
+		Keywords:       []domain.SemanticKeyword{},
+		Topics:         []domain.SemanticTopic{},
+		Sentiment:      "neutral",
+		SentimentScore: 0.5,
+		Tone:           "neutral",
+	}
+}
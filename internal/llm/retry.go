@@ -0,0 +1,166 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"article-assistant/internal/logging"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrCircuitOpen is returned without calling the provider while the circuit breaker is open
+var ErrCircuitOpen = errors.New("llm circuit breaker open: provider unavailable")
+
+// RetryConfig controls retries of transient provider errors and the circuit breaker
+type RetryConfig struct {
+	MaxAttempts      int           // Attempts per call, including the first
+	BaseBackoff      time.Duration // Backoff before the first retry; doubles per attempt
+	MaxBackoff       time.Duration
+	BreakerThreshold int           // Consecutive failed calls that open the circuit; 0 disables the breaker
+	BreakerCooldown  time.Duration // How long the circuit stays open before a trial call
+}
+
+// DefaultRetryConfig returns the retry settings used when none are configured
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:      4,
+		BaseBackoff:      500 * time.Millisecond,
+		MaxBackoff:       20 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Retrier retries transient errors with jittered exponential backoff and fails fast through
+// a circuit breaker once calls keep failing. It is safe for concurrent use.
+type Retrier struct {
+	cfg RetryConfig
+
+	mu        sync.Mutex
+	failures  int       // Consecutive failed calls
+	openUntil time.Time // Circuit open until this time
+	trial     bool      // A half-open trial call is in flight
+}
+
+// NewRetrier creates a retrier with the given configuration
+func NewRetrier(cfg RetryConfig) *Retrier {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Retrier{cfg: cfg}
+}
+
+// Do runs fn, retrying transient errors. Only calls that still fail with a transient error
+// after all attempts count towards opening the circuit.
+func (r *Retrier) Do(ctx context.Context, fn func() error) error {
+	if err := r.allow(); err != nil {
+		return err
+	}
+
+	var err error
+	backoff := r.cfg.BaseBackoff
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryable(err) || attempt >= r.cfg.MaxAttempts {
+			break
+		}
+
+		wait := jitter(backoff)
+		logging.FromContext(ctx).Warn("transient llm error, retrying", "attempt", attempt,
+			"max_attempts", r.cfg.MaxAttempts, "backoff", wait.String(), "error", err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			r.abandon()
+			return err
+		}
+		backoff *= 2
+		if backoff > r.cfg.MaxBackoff {
+			backoff = r.cfg.MaxBackoff
+		}
+	}
+
+	r.done(err != nil && IsRetryable(err))
+	return err
+}
+
+// allow rejects calls while the circuit is open; after the cooldown one trial call is let through
+func (r *Retrier) allow() error {
+	if r.cfg.BreakerThreshold <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures < r.cfg.BreakerThreshold {
+		return nil
+	}
+	if time.Now().Before(r.openUntil) || r.trial {
+		return ErrCircuitOpen
+	}
+	r.trial = true
+	return nil
+}
+
+// done records the outcome of a call
+func (r *Retrier) done(failed bool) {
+	if r.cfg.BreakerThreshold <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trial = false
+	if !failed {
+		r.failures = 0
+		return
+	}
+	r.failures++
+	if r.failures >= r.cfg.BreakerThreshold {
+		r.openUntil = time.Now().Add(r.cfg.BreakerCooldown)
+	}
+}
+
+// abandon ends a call given up by the caller without counting it either way
+func (r *Retrier) abandon() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trial = false
+}
+
+// jitter returns a random duration in [d/2, d)
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// IsRetryable reports whether a provider error is transient: rate limits, 5xx responses,
+// timeouts and connection failures
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.HTTPStatusCode)
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return retryableStatus(reqErr.HTTPStatusCode)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false // The caller's deadline has passed; retrying cannot succeed
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func retryableStatus(code int) bool {
+	return code == 429 || code >= 500
+}
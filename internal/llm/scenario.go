@@ -0,0 +1,289 @@
+package llm
+
+import (
+	"article-assistant/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario scripts the responses of a ScenarioClient. Rules are matched in order
+// against the call input; a match value prefixed with "re:" is a regular expression,
+// otherwise it is a case-insensitive substring. An empty match always matches.
+type Scenario struct {
+	Seed      int64          `yaml:"seed"`
+	LatencyMS int            `yaml:"latency_ms"`
+	Plans     []PlanRule     `yaml:"plans"`
+	Texts     []TextRule     `yaml:"texts"`     // GenerateText, Summarize, ToneCompare
+	Semantics []SemanticRule `yaml:"semantics"` // ExtractAllSemantics
+	Expect    map[string]int `yaml:"expect"`    // Method name -> expected call count
+}
+
+// PlanRule maps a query to a plan
+type PlanRule struct {
+	Match string                 `yaml:"match"`
+	Plan  domain.Plan            `yaml:"-"`
+	Raw   map[string]interface{} `yaml:"plan"`
+	Error string                 `yaml:"error"`
+}
+
+// TextRule maps a prompt or input text to a response
+type TextRule struct {
+	Method   string `yaml:"method"` // Optional: restrict to one method
+	Match    string `yaml:"match"`
+	Response string `yaml:"response"`
+	Error    string `yaml:"error"`
+}
+
+// SemanticRule maps input text to a semantic analysis
+type SemanticRule struct {
+	Match    string                  `yaml:"match"`
+	Analysis domain.SemanticAnalysis `yaml:"-"`
+	Raw      map[string]interface{}  `yaml:"analysis"`
+}
+
+// Call records a single invocation of a ScenarioClient method
+type Call struct {
+	Method string
+	Input  string
+}
+
+// ScenarioClient is a deterministic, scripted Client for tests
+type ScenarioClient struct {
+	scenario Scenario
+	matchers map[string]*regexp.Regexp
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ Client = (*ScenarioClient)(nil)
+
+// LoadScenario reads a YAML scenario fixture from disk
+func LoadScenario(path string) (*ScenarioClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	var sc Scenario
+	if err := yaml.Unmarshal(data, &sc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	return NewScenarioClient(sc)
+}
+
+// NewScenarioClient builds a client from an in-memory scenario
+func NewScenarioClient(sc Scenario) (*ScenarioClient, error) {
+	c := &ScenarioClient{scenario: sc, matchers: make(map[string]*regexp.Regexp)}
+
+	var patterns []string
+	for i := range c.scenario.Plans {
+		rule := &c.scenario.Plans[i]
+		if rule.Raw != nil {
+			if err := viaJSON(rule.Raw, &rule.Plan); err != nil {
+				return nil, fmt.Errorf("invalid scenario plan for %q: %w", rule.Match, err)
+			}
+		}
+		patterns = append(patterns, rule.Match)
+	}
+	for _, r := range sc.Texts {
+		patterns = append(patterns, r.Match)
+	}
+	for i := range c.scenario.Semantics {
+		rule := &c.scenario.Semantics[i]
+		if rule.Raw != nil {
+			if err := viaJSON(rule.Raw, &rule.Analysis); err != nil {
+				return nil, fmt.Errorf("invalid scenario analysis for %q: %w", rule.Match, err)
+			}
+		}
+		patterns = append(patterns, rule.Match)
+	}
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "re:") {
+			re, err := regexp.Compile(strings.TrimPrefix(p, "re:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid scenario pattern %q: %w", p, err)
+			}
+			c.matchers[p] = re
+		}
+	}
+	return c, nil
+}
+
+// viaJSON converts YAML-decoded data into a domain type through its JSON tags,
+// so fixtures use the same field names and shapes (e.g. []interface{} URL lists) as real LLM output
+func viaJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (c *ScenarioClient) matches(pattern, input string) bool {
+	if pattern == "" {
+		return true
+	}
+	if re, ok := c.matchers[pattern]; ok {
+		return re.MatchString(input)
+	}
+	return strings.Contains(strings.ToLower(input), strings.ToLower(pattern))
+}
+
+// record logs the call and applies simulated latency
+func (c *ScenarioClient) record(ctx context.Context, method, input string) error {
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{Method: method, Input: input})
+	c.mu.Unlock()
+
+	if c.scenario.LatencyMS > 0 {
+		select {
+		case <-time.After(time.Duration(c.scenario.LatencyMS) * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Calls returns recorded calls, optionally filtered by method
+func (c *ScenarioClient) Calls(method string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Call
+	for _, call := range c.calls {
+		if method == "" || call.Method == method {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+// CallCount returns how many times a method was called
+func (c *ScenarioClient) CallCount(method string) int {
+	return len(c.Calls(method))
+}
+
+// Verify checks the scenario's expected call counts against recorded calls
+func (c *ScenarioClient) Verify() error {
+	var problems []string
+	for method, want := range c.scenario.Expect {
+		if got := c.CallCount(method); got != want {
+			problems = append(problems, fmt.Sprintf("%s: expected %d calls, got %d", method, want, got))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("scenario expectations not met: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Reset clears recorded calls
+func (c *ScenarioClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+}
+
+func (c *ScenarioClient) text(ctx context.Context, method, input, fallback string) (string, error) {
+	if err := c.record(ctx, method, input); err != nil {
+		return "", err
+	}
+	for _, r := range c.scenario.Texts {
+		if r.Method != "" && r.Method != method {
+			continue
+		}
+		if c.matches(r.Match, input) {
+			if r.Error != "" {
+				return "", fmt.Errorf("%s", r.Error)
+			}
+			return r.Response, nil
+		}
+	}
+	return fallback, nil
+}
+
+func (c *ScenarioClient) Summarize(ctx context.Context, text string, opts SummaryOptions) (string, error) {
+	fallback := text
+	if len(fallback) > 200 {
+		fallback = fallback[:200] + "..."
+	}
+	return c.text(ctx, "Summarize", text, fallback)
+}
+
+func (c *ScenarioClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return c.text(ctx, "GenerateText", prompt, "")
+}
+
+func (c *ScenarioClient) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	return c.text(ctx, "ToneCompare", text1+"\n---\n"+text2, "")
+}
+
+func (c *ScenarioClient) SentimentScore(ctx context.Context, text string) (float64, error) {
+	if err := c.record(ctx, "SentimentScore", text); err != nil {
+		return 0, err
+	}
+	for _, r := range c.scenario.Semantics {
+		if c.matches(r.Match, text) {
+			return r.Analysis.SentimentScore, nil
+		}
+	}
+	return 0.5, nil
+}
+
+// Embed returns a deterministic embedding derived from the scenario seed and the text
+func (c *ScenarioClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := c.record(ctx, "Embed", text); err != nil {
+		return nil, err
+	}
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	rng := rand.New(rand.NewSource(c.scenario.Seed ^ int64(h.Sum64())))
+
+	embedding := make([]float32, 1536)
+	for i := range embedding {
+		embedding[i] = rng.Float32()
+	}
+	return embedding, nil
+}
+
+func (c *ScenarioClient) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	if err := c.record(ctx, "PlanQuery", query); err != nil {
+		return nil, err
+	}
+	for _, r := range c.scenario.Plans {
+		if c.matches(r.Match, query) {
+			if r.Error != "" {
+				return nil, fmt.Errorf("%s", r.Error)
+			}
+			plan := domain.Plan{Command: r.Plan.Command, Args: make(map[string]interface{}, len(r.Plan.Args))}
+			for k, v := range r.Plan.Args {
+				plan.Args[k] = v
+			}
+			return &plan, nil
+		}
+	}
+	return nil, fmt.Errorf("scenario has no plan for query: %s", query)
+}
+
+func (c *ScenarioClient) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	if err := c.record(ctx, "ExtractAllSemantics", text); err != nil {
+		return nil, err
+	}
+	for _, r := range c.scenario.Semantics {
+		if c.matches(r.Match, text) {
+			analysis := r.Analysis
+			return &analysis, nil
+		}
+	}
+	return createEmptySemanticAnalysis(), nil
+}
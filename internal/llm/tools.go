@@ -0,0 +1,245 @@
+package llm
+
+import (
+	"article-assistant/internal/commands"
+	"article-assistant/internal/domain"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// PlanCommands are the commands the planner may choose
+var PlanCommands = commands.Names()
+
+// ErrUnknownCommand is returned when the planner chooses a command outside PlanCommands
+var ErrUnknownCommand = errors.New("unknown command")
+
+// PlanArgs are the typed arguments of a plan; nil fields were not given
+type PlanArgs struct {
+	URLs            []string `json:"urls,omitempty"`
+	Filter          *string  `json:"filter,omitempty"`
+	Author          *string  `json:"author,omitempty"`
+	Section         *string  `json:"section,omitempty"`
+	Source          *string  `json:"source,omitempty"`
+	TopicID         *string  `json:"topic_id,omitempty"`
+	Tag             *string  `json:"tag,omitempty"`
+	Collection      *string  `json:"collection,omitempty"`
+	Level           *string  `json:"level,omitempty"`
+	Since           *string  `json:"since,omitempty"`
+	PublishedAfter  *string  `json:"published_after,omitempty"`
+	PublishedBefore *string  `json:"published_before,omitempty"`
+	K               *int     `json:"k,omitempty"`
+	Language        *string  `json:"language,omitempty"`
+	Interval        *string  `json:"interval,omitempty"`
+	Direction       *string  `json:"direction,omitempty"`
+	MinScore        *float64 `json:"min_score,omitempty"`
+	MaxScore        *float64 `json:"max_score,omitempty"`
+	Limit           *int     `json:"limit,omitempty"`
+	Offset          *int     `json:"offset,omitempty"`
+	Tone            *string  `json:"tone,omitempty"`
+	Category        *string  `json:"category,omitempty"`
+	Style           *string  `json:"style,omitempty"`
+	Bullets         *int     `json:"bullets,omitempty"`
+	MaxWords        *int     `json:"max_words,omitempty"`
+	Model           *string  `json:"model,omitempty"`
+}
+
+// PlanCall is the typed argument object of the create_plan function
+type PlanCall struct {
+	Command    string   `json:"command"`
+	Args       PlanArgs `json:"args"`
+	Confidence *float64 `json:"confidence,omitempty"`
+}
+
+const (
+	planToolName      = "create_plan"
+	semanticsToolName = "record_semantics"
+)
+
+// planTool is the create_plan function with a strict schema. Strict mode requires every
+// property to be listed as required, so optional arguments are nullable instead.
+var planTool = openai.Tool{
+	Type: openai.ToolTypeFunction,
+	Function: &openai.FunctionDefinition{
+		Name:        planToolName,
+		Description: "Record the execution plan for the user's query",
+		Strict:      true,
+		Parameters:  json.RawMessage(planSchema()),
+	},
+}
+
+func planSchema() string {
+	commands, _ := json.Marshal(PlanCommands)
+	return fmt.Sprintf(`{
+  "type": "object",
+  "properties": {
+    "command": {"type": "string", "enum": %s},
+    "args": {
+      "type": "object",
+      "properties": {
+        "urls": {"type": ["array", "null"], "items": {"type": "string"}, "description": "URLs exactly as written in the query"},
+        "filter": {"type": ["string", "null"], "description": "Topic or search filter"},
+        "author": {"type": ["string", "null"]},
+        "section": {"type": ["string", "null"]},
+        "source": {"type": ["string", "null"], "description": "Publisher domain to restrict to, e.g. techcrunch.com"},
+        "topic_id": {"type": ["string", "null"], "description": "Taxonomy topic ID to restrict to, only when the query gives one"},
+        "tag": {"type": ["string", "null"], "description": "User-defined article tag to restrict to, e.g. research"},
+        "collection": {"type": ["string", "null"], "description": "Name of the user's article collection to restrict to, e.g. AI policy"},
+        "level": {"type": ["string", "null"], "enum": ["eli5", "high_school", "expert", null]},
+        "since": {"type": ["string", "null"]},
+        "published_after": {"type": ["string", "null"], "description": "Earliest publication date: today, yesterday, last_week, last_month, 3d or YYYY-MM-DD"},
+        "published_before": {"type": ["string", "null"], "description": "Publication date to stop before, same formats as published_after"},
+        "k": {"type": ["integer", "null"], "description": "Number of topic groups"},
+        "language": {"type": ["string", "null"], "description": "Requested output language, e.g. Spanish"},
+        "interval": {"type": ["string", "null"], "enum": ["day", "week", null]},
+        "direction": {"type": ["string", "null"], "enum": ["positive", "negative", null]},
+        "min_score": {"type": ["number", "null"], "description": "Lowest sentiment score, 0.0 (very negative) to 1.0 (very positive)"},
+        "max_score": {"type": ["number", "null"], "description": "Highest sentiment score, 0.0 to 1.0"},
+        "limit": {"type": ["integer", "null"], "description": "Number of articles to return"},
+        "offset": {"type": ["integer", "null"], "description": "Number of ranked articles to skip, for the next page of a topic search"},
+        "tone": {"type": ["string", "null"], "description": "Article tone, e.g. critical, optimistic, analytical"},
+        "category": {"type": ["string", "null"], "enum": ["person", "organization", "location", "technology", "other", null], "description": "Entity category"},
+        "style": {"type": ["string", "null"], "enum": ["bullets", "one_liner", "executive", "eli5", null], "description": "Summary style"},
+        "bullets": {"type": ["integer", "null"], "description": "Number of bullet points in a bullets summary"},
+        "max_words": {"type": ["integer", "null"], "description": "Maximum summary length in words"},
+        "model": {"type": ["string", "null"], "description": "OpenAI model the query asks to use, e.g. gpt-4"}
+      },
+      "required": ["urls", "filter", "author", "section", "source", "topic_id", "tag", "collection", "level", "since", "published_after", "published_before", "k", "language", "interval", "direction", "min_score", "max_score", "limit", "offset", "tone", "category", "style", "bullets", "max_words", "model"],
+      "additionalProperties": false
+    },
+    "confidence": {"type": ["number", "null"], "description": "How sure you are that the command and args match the query, 0.0 to 1.0"}
+  },
+  "required": ["command", "args", "confidence"],
+  "additionalProperties": false
+}`, commands)
+}
+
+// semanticsTool is the record_semantics function; its arguments decode into domain.SemanticAnalysis
+var semanticsTool = openai.Tool{
+	Type: openai.ToolTypeFunction,
+	Function: &openai.FunctionDefinition{
+		Name:        semanticsToolName,
+		Description: "Record the entities, keywords, topics, sentiment and tone extracted from the text",
+		Strict:      true,
+		Parameters: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entities": {"type": "array", "items": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "category": {"type": "string", "enum": ["person", "organization", "location", "technology", "other"]},
+        "confidence": {"type": "number"}
+      },
+      "required": ["name", "category", "confidence"],
+      "additionalProperties": false
+    }},
+    "keywords": {"type": "array", "items": {
+      "type": "object",
+      "properties": {
+        "term": {"type": "string"},
+        "relevance": {"type": "number"},
+        "context": {"type": "string"}
+      },
+      "required": ["term", "relevance", "context"],
+      "additionalProperties": false
+    }},
+    "topics": {"type": "array", "items": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "score": {"type": "number"},
+        "description": {"type": "string"}
+      },
+      "required": ["name", "score", "description"],
+      "additionalProperties": false
+    }},
+    "sentiment": {"type": "string", "enum": ["positive", "negative", "neutral"]},
+    "sentiment_score": {"type": "number", "description": "0.0 (very negative) to 1.0 (very positive)"},
+    "tone": {"type": "string"}
+  },
+  "required": ["entities", "keywords", "topics", "sentiment", "sentiment_score", "tone"],
+  "additionalProperties": false
+}`),
+	},
+}
+
+// forceTool makes the model call the named function
+func forceTool(name string) openai.ToolChoice {
+	return openai.ToolChoice{Type: openai.ToolTypeFunction, Function: openai.ToolFunction{Name: name}}
+}
+
+// toolArguments returns the arguments of the first call to the named function
+func toolArguments(msg openai.ChatCompletionMessage, name string) (string, bool) {
+	for _, call := range msg.ToolCalls {
+		if call.Function.Name == name {
+			return call.Function.Arguments, true
+		}
+	}
+	return "", false
+}
+
+// decodeStrict decodes JSON into v, rejecting unknown fields and trailing data
+func decodeStrict(data string, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after JSON object")
+	}
+	return nil
+}
+
+// ParsePlanCall validates create_plan arguments and converts them to a plan
+func ParsePlanCall(arguments string) (*domain.Plan, error) {
+	var call PlanCall
+	if err := decodeStrict(arguments, &call); err != nil {
+		return nil, fmt.Errorf("failed to parse plan arguments: %w", err)
+	}
+	if !isPlanCommand(call.Command) {
+		return nil, fmt.Errorf("failed to parse plan arguments: %w %q", ErrUnknownCommand, call.Command)
+	}
+
+	// Round-trip through JSON so Args has the same shape as a decoded JSON plan
+	raw, err := json.Marshal(call.Args)
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.Plan{Command: call.Command, Args: map[string]interface{}{}, Confidence: call.Confidence}
+	if err := json.Unmarshal(raw, &plan.Args); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ParseSemanticsCall validates record_semantics arguments
+func ParseSemanticsCall(arguments string) (*domain.SemanticAnalysis, error) {
+	var analysis domain.SemanticAnalysis
+	if err := decodeStrict(arguments, &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse semantic analysis arguments: %w", err)
+	}
+	if analysis.Entities == nil {
+		analysis.Entities = []domain.SemanticEntity{}
+	}
+	if analysis.Keywords == nil {
+		analysis.Keywords = []domain.SemanticKeyword{}
+	}
+	if analysis.Topics == nil {
+		analysis.Topics = []domain.SemanticTopic{}
+	}
+	return &analysis, nil
+}
+
+func isPlanCommand(command string) bool {
+	for _, c := range PlanCommands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+// Package llmaudit records every LLM call (prompt hash, model, latency, token counts and a
+// truncated response) to a Postgres table or a JSON Lines file for debugging planner
+// misroutes and cost spikes.
+package llmaudit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/prompts"
+	"article-assistant/internal/tenant"
+	"article-assistant/internal/usage"
+)
+
+// DefaultResponseChars is how much of each response is kept when Client.ResponseChars is 0
+const DefaultResponseChars = 500
+
+// Entry is one audited LLM call
+type Entry struct {
+	Time             time.Time `json:"time"`
+	RequestID        string    `json:"request_id,omitempty"`
+	Tenant           string    `json:"tenant,omitempty"` // Whose request or background work made the call
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	Method           string    `json:"method"`                   // Client method, e.g. PlanQuery
+	PromptHash       string    `json:"prompt_hash"`              // SHA-256 of the method and its inputs
+	PromptVersion    string    `json:"prompt_version,omitempty"` // Planner prompt version of PlanQuery calls
+	PromptChars      int       `json:"prompt_chars"`
+	LatencyMS        int64     `json:"latency_ms"`
+	PromptTokens     int       `json:"prompt_tokens"` // Reported by OpenAI only; 0 for other providers
+	CompletionTokens int       `json:"completion_tokens"`
+	Cost             float64   `json:"cost"` // Estimated USD
+	Response         string    `json:"response,omitempty"`
+	Truncated        bool      `json:"truncated,omitempty"` // Response was cut to the configured length
+	Error            string    `json:"error,omitempty"`
+}
+
+// Filter selects entries; zero fields match everything
+type Filter struct {
+	Method        string
+	Model         string
+	PromptHash    string
+	PromptVersion string
+	RequestID     string
+	Tenant        string
+	Since         time.Time
+	Limit         int
+}
+
+// Matches reports whether e passes every set field except Limit
+func (f Filter) Matches(e Entry) bool {
+	return (f.Method == "" || e.Method == f.Method) &&
+		(f.Model == "" || e.Model == f.Model) &&
+		(f.PromptHash == "" || e.PromptHash == f.PromptHash) &&
+		(f.PromptVersion == "" || e.PromptVersion == f.PromptVersion) &&
+		(f.RequestID == "" || e.RequestID == f.RequestID) &&
+		(f.Tenant == "" || e.Tenant == f.Tenant) &&
+		(f.Since.IsZero() || !e.Time.Before(f.Since))
+}
+
+// Sink stores entries and answers queries, newest first
+type Sink interface {
+	Write(ctx context.Context, e Entry) error
+	List(ctx context.Context, f Filter) ([]Entry, error)
+}
+
+// Client wraps an llm.Client and writes an entry for every call
+type Client struct {
+	Inner         llm.Client
+	Sink          Sink
+	Provider      string
+	Model         string
+	ResponseChars int // Response prefix kept per entry; 0 uses DefaultResponseChars
+}
+
+var _ llm.Client = (*Client)(nil)
+
+// Wrap audits calls to inner for the given provider and model
+func Wrap(inner llm.Client, sink Sink, provider, model string) *Client {
+	return &Client{Inner: inner, Sink: sink, Provider: provider, Model: model}
+}
+
+// call is one call in progress
+type call struct {
+	ctx     context.Context // Carries a tracker for this call only
+	tracker *usage.Tracker
+	entry   Entry
+}
+
+// start hashes the call's inputs and gives it its own usage tracker, so token counts can be
+// attributed to this call and then added to the request's tracker
+func (c *Client) start(ctx context.Context, method string, inputs ...string) *call {
+	h := sha256.New()
+	h.Write([]byte(method))
+	chars := 0
+	for _, in := range inputs {
+		h.Write([]byte{0})
+		h.Write([]byte(in))
+		chars += len(in)
+	}
+	tracker := usage.NewTracker()
+	return &call{
+		ctx:     usage.NewContext(ctx, tracker),
+		tracker: tracker,
+		entry: Entry{
+			Time:        time.Now().UTC(),
+			RequestID:   logging.RequestID(ctx),
+			Tenant:      tenant.FromContext(ctx),
+			Provider:    c.Provider,
+			Model:       c.Model,
+			Method:      method,
+			PromptHash:  hex.EncodeToString(h.Sum(nil)),
+			PromptChars: chars,
+		},
+	}
+}
+
+// finish completes the entry and writes it; write failures are logged, never returned
+func (c *Client) finish(ctx context.Context, cl *call, response string, err error) {
+	u := cl.tracker.Usage()
+	if outer := usage.FromContext(ctx); outer != nil {
+		outer.AddUsage(u)
+	}
+
+	e := cl.entry
+	e.LatencyMS = time.Since(e.Time).Milliseconds()
+	e.PromptTokens, e.CompletionTokens, e.Cost = u.PromptTokens, u.CompletionTokens, u.Cost
+	if err != nil {
+		e.Error = err.Error()
+	} else {
+		e.Response, e.Truncated = truncate(response, c.responseChars())
+	}
+	// Entries are written even when the caller gave up on the call
+	if werr := c.Sink.Write(context.WithoutCancel(ctx), e); werr != nil {
+		logging.FromContext(ctx).Warn("failed to write LLM audit entry", "method", e.Method, "error", werr)
+	}
+}
+
+func (c *Client) responseChars() int {
+	if c.ResponseChars > 0 {
+		return c.ResponseChars
+	}
+	return DefaultResponseChars
+}
+
+// truncate keeps the first n runes of s
+func truncate(s string, n int) (string, bool) {
+	for i := range s {
+		if n == 0 {
+			return s[:i], true
+		}
+		n--
+	}
+	return s, false
+}
+
+// asJSON renders structured results for the response field
+func asJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func (c *Client) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
+	cl := c.start(ctx, "Summarize", text, asJSON(opts))
+	out, err := c.Inner.Summarize(cl.ctx, text, opts)
+	c.finish(ctx, cl, out, err)
+	return out, err
+}
+
+func (c *Client) SentimentScore(ctx context.Context, text string) (float64, error) {
+	cl := c.start(ctx, "SentimentScore", text)
+	out, err := c.Inner.SentimentScore(cl.ctx, text)
+	c.finish(ctx, cl, strconv.FormatFloat(out, 'f', -1, 64), err)
+	return out, err
+}
+
+func (c *Client) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	cl := c.start(ctx, "ToneCompare", text1, text2)
+	out, err := c.Inner.ToneCompare(cl.ctx, text1, text2)
+	c.finish(ctx, cl, out, err)
+	return out, err
+}
+
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	cl := c.start(ctx, "Embed", text)
+	out, err := c.Inner.Embed(cl.ctx, text)
+	c.finish(ctx, cl, fmt.Sprintf("[%d dimensions]", len(out)), err)
+	return out, err
+}
+
+func (c *Client) GenerateText(ctx context.Context, prompt string) (string, error) {
+	cl := c.start(ctx, "GenerateText", prompt)
+	out, err := c.Inner.GenerateText(cl.ctx, prompt)
+	c.finish(ctx, cl, out, err)
+	return out, err
+}
+
+func (c *Client) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	cl := c.start(ctx, "PlanQuery", query)
+	cl.entry.PromptVersion = prompts.VersionFromContext(ctx, prompts.Planner)
+	out, err := c.Inner.PlanQuery(cl.ctx, query)
+	c.finish(ctx, cl, asJSON(out), err)
+	return out, err
+}
+
+func (c *Client) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	cl := c.start(ctx, "ExtractAllSemantics", text)
+	out, err := c.Inner.ExtractAllSemantics(cl.ctx, text)
+	c.finish(ctx, cl, asJSON(out), err)
+	return out, err
+}
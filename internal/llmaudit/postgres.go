@@ -0,0 +1,65 @@
+package llmaudit
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PostgresSink stores entries in the llm_audit table. It is only used with a single tenant,
+// whose database holds the table, so entries and filters leave the tenant out.
+type PostgresSink struct {
+	DB *sql.DB
+}
+
+var _ Sink = (*PostgresSink)(nil)
+
+func (s *PostgresSink) Write(ctx context.Context, e Entry) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO llm_audit (created_at, request_id, provider, model, method, prompt_hash, prompt_version, prompt_chars,
+			latency_ms, prompt_tokens, completion_tokens, cost, response, truncated, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		e.Time, e.RequestID, e.Provider, e.Model, e.Method, e.PromptHash, e.PromptVersion, e.PromptChars,
+		e.LatencyMS, e.PromptTokens, e.CompletionTokens, e.Cost, e.Response, e.Truncated, e.Error)
+	return err
+}
+
+// List returns up to f.Limit matching entries, newest first
+func (s *PostgresSink) List(ctx context.Context, f Filter) ([]Entry, error) {
+	var since sql.NullTime
+	if !f.Since.IsZero() {
+		since = sql.NullTime{Time: f.Since.UTC(), Valid: true} // created_at is stored in UTC
+	}
+	var limit sql.NullInt64
+	if f.Limit > 0 {
+		limit = sql.NullInt64{Int64: int64(f.Limit), Valid: true}
+	}
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT created_at, request_id, provider, model, method, prompt_hash, prompt_version, prompt_chars,
+			latency_ms, prompt_tokens, completion_tokens, cost, response, truncated, error
+		FROM llm_audit
+		WHERE ($1 = '' OR method = $1)
+		  AND ($2 = '' OR model = $2)
+		  AND ($3 = '' OR prompt_hash = $3)
+		  AND ($4 = '' OR prompt_version = $4)
+		  AND ($5 = '' OR request_id = $5)
+		  AND ($6::timestamp IS NULL OR created_at >= $6)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $7`,
+		f.Method, f.Model, f.PromptHash, f.PromptVersion, f.RequestID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Time, &e.RequestID, &e.Provider, &e.Model, &e.Method, &e.PromptHash, &e.PromptVersion, &e.PromptChars,
+			&e.LatencyMS, &e.PromptTokens, &e.CompletionTokens, &e.Cost, &e.Response, &e.Truncated, &e.Error); err != nil {
+			return nil, err
+		}
+		e.Time = e.Time.UTC()
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,73 @@
+package llmhealth
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"context"
+	"errors"
+)
+
+// Client wraps an llm.Client and reports every call outcome to a Monitor
+type Client struct {
+	Inner    llm.Client
+	Monitor  *Monitor
+	Provider string
+	Model    string
+}
+
+var _ llm.Client = (*Client)(nil)
+
+// Wrap instruments an LLM client for the given provider and model
+func Wrap(inner llm.Client, monitor *Monitor, provider, model string) *Client {
+	return &Client{Inner: inner, Monitor: monitor, Provider: provider, Model: model}
+}
+
+func (c *Client) observe(method string, err error) {
+	// Calls abandoned by the caller say nothing about provider health
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	c.Monitor.Observe(c.Provider, c.Model, method, err)
+}
+
+func (c *Client) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
+	out, err := c.Inner.Summarize(ctx, text, opts)
+	c.observe("Summarize", err)
+	return out, err
+}
+
+func (c *Client) SentimentScore(ctx context.Context, text string) (float64, error) {
+	out, err := c.Inner.SentimentScore(ctx, text)
+	c.observe("SentimentScore", err)
+	return out, err
+}
+
+func (c *Client) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	out, err := c.Inner.ToneCompare(ctx, text1, text2)
+	c.observe("ToneCompare", err)
+	return out, err
+}
+
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	out, err := c.Inner.Embed(ctx, text)
+	c.observe("Embed", err)
+	return out, err
+}
+
+func (c *Client) GenerateText(ctx context.Context, prompt string) (string, error) {
+	out, err := c.Inner.GenerateText(ctx, prompt)
+	c.observe("GenerateText", err)
+	return out, err
+}
+
+func (c *Client) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	out, err := c.Inner.PlanQuery(ctx, query)
+	c.observe("PlanQuery", err)
+	return out, err
+}
+
+func (c *Client) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	out, err := c.Inner.ExtractAllSemantics(ctx, text)
+	c.observe("ExtractAllSemantics", err)
+	return out, err
+}
@@ -0,0 +1,56 @@
+package llmhealth
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// MetricsHandler serves the monitor's counters in the Prometheus text format
+func MetricsHandler(m *Monitor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := m.WritePrometheus(w); err != nil {
+			log.Printf("⚠️  Failed to write metrics: %v", err)
+		}
+	}
+}
+
+// HealthHandler serves per-provider/model error breakdowns and status as JSON
+func HealthHandler(m *Monitor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", 405)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"targets": m.Health(),
+		})
+	}
+}
+
+// WebhookHook returns an alert hook that POSTs the alert as JSON to url
+func WebhookHook(url string) AlertHook {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(a Alert) {
+		body, err := json.Marshal(a)
+		if err != nil {
+			log.Printf("⚠️  Failed to encode LLM alert: %v", err)
+			return
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("⚠️  Failed to send LLM alert webhook: %v", err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("⚠️  LLM alert webhook returned %s", resp.Status)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package llmhealth tracks LLM provider errors by class, exports them as Prometheus
+// metrics and raises webhook alerts when error rates cross a threshold.
+package llmhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error classes
+const (
+	ClassRateLimit = "rate_limit"
+	ClassAuth      = "auth"
+	ClassTimeout   = "timeout"
+	ClassMalformed = "malformed_output"
+	ClassServer    = "server"
+	ClassOther     = "other"
+)
+
+// Classify maps an LLM call error to an error class
+func Classify(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ClassTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ClassTimeout
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, "429", "rate limit", "rate_limit", "too many requests", "quota", "resource_exhausted"):
+		return ClassRateLimit
+	case containsAny(msg, "401", "403", "unauthorized", "forbidden", "invalid api key", "incorrect api key", "authentication", "permission_denied"):
+		return ClassAuth
+	case containsAny(msg, "timeout", "deadline exceeded", "timed out"):
+		return ClassTimeout
+	case containsAny(msg, "failed to parse", "invalid character", "unexpected end of json", "cannot unmarshal", "malformed"):
+		return ClassMalformed
+	case containsAny(msg, "status code: 5", "status=5", "overloaded", "unavailable", "internal server error", "bad gateway"):
+		return ClassServer
+	}
+	return ClassOther
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertConfig controls error-rate alerting. Alerts fire when, within Window, a provider/model
+// has at least MinCalls calls and an error rate at or above ErrorRate.
+type AlertConfig struct {
+	ErrorRate float64
+	Window    time.Duration
+	MinCalls  int
+	Cooldown  time.Duration // Minimum time between alerts for the same provider/model
+}
+
+// DefaultAlertConfig returns conservative alerting defaults
+func DefaultAlertConfig() AlertConfig {
+	return AlertConfig{ErrorRate: 0.5, Window: 5 * time.Minute, MinCalls: 10, Cooldown: 15 * time.Minute}
+}
+
+// Alert describes an error-rate threshold crossing
+type Alert struct {
+	Provider  string         `json:"provider"`
+	Model     string         `json:"model"`
+	ErrorRate float64        `json:"error_rate"`
+	Calls     int            `json:"calls"`
+	Errors    map[string]int `json:"errors"` // Class -> count within the window
+	Window    string         `json:"window"`
+	LastError string         `json:"last_error"`
+	Time      time.Time      `json:"time"`
+}
+
+// AlertHook receives alerts; hooks run in their own goroutine
+type AlertHook func(Alert)
+
+type event struct {
+	at    time.Time
+	class string // Empty for success
+}
+
+// target is one provider/model pair
+type target struct {
+	provider string
+	model    string
+}
+
+type targetStats struct {
+	calls     map[string]int // Method -> calls
+	errors    map[string]int // Class -> errors
+	recent    []event
+	lastError string
+	lastAt    time.Time
+	alertedAt time.Time
+}
+
+// Monitor aggregates LLM call outcomes
+type Monitor struct {
+	mu      sync.Mutex
+	cfg     AlertConfig
+	hooks   []AlertHook
+	targets map[target]*targetStats
+	now     func() time.Time
+}
+
+// NewMonitor creates a monitor with the given alert configuration
+func NewMonitor(cfg AlertConfig) *Monitor {
+	return &Monitor{cfg: cfg, targets: make(map[target]*targetStats), now: time.Now}
+}
+
+// OnAlert registers an alert hook
+func (m *Monitor) OnAlert(hook AlertHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// SetClock overrides the monitor clock; used in tests
+func (m *Monitor) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// Observe records the outcome of one call
+func (m *Monitor) Observe(provider, model, method string, err error) {
+	m.mu.Lock()
+	now := m.now()
+	key := target{provider, model}
+	st, ok := m.targets[key]
+	if !ok {
+		st = &targetStats{calls: make(map[string]int), errors: make(map[string]int)}
+		m.targets[key] = st
+	}
+
+	st.calls[method]++
+	ev := event{at: now}
+	if err != nil {
+		ev.class = Classify(err)
+		st.errors[ev.class]++
+		st.lastError = err.Error()
+		st.lastAt = now
+	}
+	st.recent = append(st.recent, ev)
+	cutoff := now.Add(-m.cfg.Window)
+	i := 0
+	for i < len(st.recent) && st.recent[i].at.Before(cutoff) {
+		i++
+	}
+	st.recent = st.recent[i:]
+
+	alert, fire := m.checkLocked(key, st, now)
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	if fire {
+		log.Printf("🚨 LLM error rate %.0f%% for %s/%s over %v", alert.ErrorRate*100, alert.Provider, alert.Model, m.cfg.Window)
+		for _, hook := range hooks {
+			go hook(alert)
+		}
+	}
+}
+
+// checkLocked evaluates the alert threshold; the caller must hold mu
+func (m *Monitor) checkLocked(key target, st *targetStats, now time.Time) (Alert, bool) {
+	if m.cfg.ErrorRate <= 0 || len(st.recent) < m.cfg.MinCalls {
+		return Alert{}, false
+	}
+	if !st.alertedAt.IsZero() && now.Sub(st.alertedAt) < m.cfg.Cooldown {
+		return Alert{}, false
+	}
+
+	classes := make(map[string]int)
+	failed := 0
+	for _, ev := range st.recent {
+		if ev.class != "" {
+			classes[ev.class]++
+			failed++
+		}
+	}
+	rate := float64(failed) / float64(len(st.recent))
+	if rate < m.cfg.ErrorRate {
+		return Alert{}, false
+	}
+
+	st.alertedAt = now
+	return Alert{
+		Provider:  key.provider,
+		Model:     key.model,
+		ErrorRate: rate,
+		Calls:     len(st.recent),
+		Errors:    classes,
+		Window:    m.cfg.Window.String(),
+		LastError: st.lastError,
+		Time:      now,
+	}, true
+}
+
+// TargetHealth is the health summary of one provider/model
+type TargetHealth struct {
+	Provider     string         `json:"provider"`
+	Model        string         `json:"model"`
+	Status       string         `json:"status"` // healthy, degraded or unknown
+	Calls        int            `json:"calls"`
+	Errors       map[string]int `json:"errors"`
+	WindowCalls  int            `json:"window_calls"`
+	WindowErrors int            `json:"window_errors"`
+	ErrorRate    float64        `json:"error_rate"` // Within the alert window
+	LastError    string         `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time     `json:"last_error_at,omitempty"`
+}
+
+// Health returns a summary per provider/model, sorted by provider and model
+func (m *Monitor) Health() []TargetHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := m.now().Add(-m.cfg.Window)
+	out := make([]TargetHealth, 0, len(m.targets))
+	for key, st := range m.targets {
+		h := TargetHealth{Provider: key.provider, Model: key.model, Errors: make(map[string]int), LastError: st.lastError}
+		for _, n := range st.calls {
+			h.Calls += n
+		}
+		for class, n := range st.errors {
+			h.Errors[class] = n
+		}
+		for _, ev := range st.recent {
+			if ev.at.Before(cutoff) {
+				continue
+			}
+			h.WindowCalls++
+			if ev.class != "" {
+				h.WindowErrors++
+			}
+		}
+		if !st.lastAt.IsZero() {
+			t := st.lastAt
+			h.LastErrorAt = &t
+		}
+
+		h.Status = "unknown"
+		if h.WindowCalls > 0 {
+			h.ErrorRate = float64(h.WindowErrors) / float64(h.WindowCalls)
+			h.Status = "healthy"
+			if m.cfg.ErrorRate > 0 && h.ErrorRate >= m.cfg.ErrorRate {
+				h.Status = "degraded"
+			}
+		}
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].Model < out[j].Model
+	})
+	return out
+}
+
+// WritePrometheus writes counters in the Prometheus text exposition format
+func (m *Monitor) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]target, 0, len(m.targets))
+	for k := range m.targets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].provider != keys[j].provider {
+			return keys[i].provider < keys[j].provider
+		}
+		return keys[i].model < keys[j].model
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP llm_requests_total LLM calls by provider, model and method.\n")
+	b.WriteString("# TYPE llm_requests_total counter\n")
+	for _, k := range keys {
+		for _, method := range sortedKeys(m.targets[k].calls) {
+			fmt.Fprintf(&b, "llm_requests_total{provider=%q,model=%q,method=%q} %d\n", k.provider, k.model, method, m.targets[k].calls[method])
+		}
+	}
+	b.WriteString("# HELP llm_errors_total LLM call errors by provider, model and error class.\n")
+	b.WriteString("# TYPE llm_errors_total counter\n")
+	for _, k := range keys {
+		for _, class := range sortedKeys(m.targets[k].errors) {
+			fmt.Fprintf(&b, "llm_errors_total{provider=%q,model=%q,class=%q} %d\n", k.provider, k.model, class, m.targets[k].errors[class])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+// Package logging configures structured (slog) logging and carries a request ID through
+// contexts so every log line of a request can be correlated.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is read from incoming requests and echoed on responses
+const RequestIDHeader = "X-Request-ID"
+
+// level is the minimum level of the default logger; SetLevel changes it at runtime
+var level slog.LevelVar
+
+// Setup installs the default slog logger. format is "json" (default) or "text"; lvl is
+// debug, info (default), warn or error. Output from the standard log package is routed
+// through the same handler.
+func Setup(format, lvl string) {
+	SetLevel(lvl)
+	opts := &slog.HandlerOptions{Level: &level}
+	var handler slog.Handler
+	if strings.EqualFold(format, "text") {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+// SetLevel changes the minimum level of the logger installed by Setup
+func SetLevel(lvl string) {
+	level.Set(parseLevel(lvl))
+}
+
+func parseLevel(lvl string) slog.Level {
+	switch strings.ToLower(lvl) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+type requestIDKey struct{}
+
+// NewRequestID generates a request ID
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger annotated with the request ID from ctx, if any
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+)
+
+// WriteError sends a JSON error envelope carrying the request's correlation ID
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	WriteErrorDetails(w, r, status, code, message, nil)
+}
+
+// WriteErrorDetails is WriteError with machine-readable details, e.g. a failure category
+func WriteErrorDetails(w http.ResponseWriter, r *http.Request, status int, code, message string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(domain.APIError{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: logging.RequestID(r.Context()),
+	})
+}
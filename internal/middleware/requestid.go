@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"article-assistant/internal/logging"
+)
+
+// RequestID assigns each request a correlation ID (reusing a valid X-Request-ID header),
+// echoes it on the response and stores it in the request context for logging
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+
+		ctx := logging.WithRequestID(r.Context(), id)
+		logging.FromContext(ctx).Info("request", "method", r.Method, "path", r.URL.Path)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/tenant"
+)
+
+// APIKeyHeader carries the API key when the Authorization header is not used
+const APIKeyHeader = "X-API-Key"
+
+// Tenant puts the tenant and role of the request's API key, sent as "Authorization: Bearer <key>"
+// or in X-API-Key, in the request context. Unknown or missing keys get 401, except on the open
+// paths and for CORS preflights. Without keys every request is the default tenant. When limiter
+// is set, requests over their tenant's rate limit get 429 with Retry-After.
+func Tenant(keys map[string]tenant.Key, limiter *tenant.RateLimiter, open []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range open {
+			if r.URL.Path == p {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		ctx := r.Context()
+		id := tenant.Default
+		if len(keys) > 0 && r.Method != http.MethodOptions {
+			key, ok := lookupKey(keys, requestKey(r))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="article-assistant"`)
+				WriteError(w, r, http.StatusUnauthorized, domain.ErrCodeUnauthorized, "A valid API key is required")
+				return
+			}
+			id = key.Tenant
+			ctx = tenant.WithRole(ctx, key.Role)
+		}
+		if limiter != nil {
+			if ok, wait := limiter.Allow(id); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				WriteError(w, r, http.StatusTooManyRequests, domain.ErrCodeRateLimited, "Rate limit reached; retry after Retry-After seconds")
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.NewContext(ctx, id)))
+	})
+}
+
+// Authorize answers 403 to requests whose API key's role is below the one policy requires for
+// them. It must run inside Tenant, which sets the role.
+func Authorize(policy func(*http.Request) tenant.Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if required, role := policy(r), tenant.RoleFromContext(r.Context()); !role.Allows(required) {
+			WriteError(w, r, http.StatusForbidden, domain.ErrCodeForbidden,
+				fmt.Sprintf("This API key's role (%s) cannot use %s %s; it needs %s", role, r.Method, r.URL.Path, required))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Operator guards routes that act on the whole deployment rather than one tenant, such as fault
+// injection. They need operatorKey; tenant keys get 403 whatever their role. Without an operator
+// key they are open only when no tenant keys are configured either. Operator routes must be among
+// Tenant's open paths, since the operator key belongs to no tenant.
+func Operator(operatorKey string, keys map[string]tenant.Key, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || IsOperator(operatorKey, keys, r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if _, ok := lookupKey(keys, requestKey(r)); ok {
+			WriteError(w, r, http.StatusForbidden, domain.ErrCodeForbidden,
+				fmt.Sprintf("%s %s affects every tenant; it needs the operator key", r.Method, r.URL.Path))
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="article-assistant"`)
+		WriteError(w, r, http.StatusUnauthorized, domain.ErrCodeUnauthorized, "The operator API key is required")
+	})
+}
+
+// IsOperator reports whether r carries operatorKey, or neither an operator key nor tenant keys
+// are configured, so that r may see data about every tenant
+func IsOperator(operatorKey string, keys map[string]tenant.Key, r *http.Request) bool {
+	if operatorKey == "" {
+		return len(keys) == 0
+	}
+	return subtle.ConstantTimeCompare([]byte(operatorKey), []byte(requestKey(r))) == 1
+}
+
+// requestKey returns the bearer token, or else the X-API-Key header
+func requestKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return strings.TrimSpace(r.Header.Get(APIKeyHeader))
+}
+
+// lookupKey looks key up comparing every configured key in constant time
+func lookupKey(keys map[string]tenant.Key, key string) (tenant.Key, bool) {
+	var found tenant.Key
+	if key == "" {
+		return found, false
+	}
+	for k, v := range keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			found = v
+		}
+	}
+	return found, found.Tenant != ""
+}
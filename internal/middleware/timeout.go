@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+)
+
+// Timeout bounds a handler's run time. The handler's request context is cancelled at the
+// deadline; if no response has been sent yet the client gets a 504 JSON error envelope.
+// Output is buffered until the handler returns or calls Flush, after which it is streamed
+// and a later timeout only cancels the context.
+func Timeout(d time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+
+		tw := &timeoutWriter{w: w, header: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.finish()
+		case <-ctx.Done():
+			tw.timeout(r, d)
+		}
+	}
+}
+
+// timeoutWriter buffers a handler's response so a timeout can still replace it
+type timeoutWriter struct {
+	w http.ResponseWriter
+
+	mu        sync.Mutex
+	header    http.Header
+	buf       bytes.Buffer
+	code      int
+	streaming bool // Response committed to the client by Flush
+	timedOut  bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.streaming {
+		return tw.w.Header()
+	}
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	if tw.streaming {
+		return tw.w.Write(p)
+	}
+	return tw.buf.Write(p)
+}
+
+// Flush commits the buffered response and switches to streaming
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	if !tw.streaming {
+		tw.commitLocked()
+		tw.streaming = true
+	}
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// commitLocked sends the buffered header and body; the caller must hold mu
+func (tw *timeoutWriter) commitLocked() {
+	dst := tw.w.Header()
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	tw.w.WriteHeader(tw.code)
+	tw.w.Write(tw.buf.Bytes())
+	tw.buf.Reset()
+}
+
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.streaming {
+		tw.commitLocked()
+	}
+}
+
+// timeout answers with the error envelope; "error" and "timeout" are kept for older clients
+func (tw *timeoutWriter) timeout(r *http.Request, d time.Duration) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.timedOut = true
+	if tw.streaming {
+		return
+	}
+	tw.w.Header().Set("Content-Type", "application/json")
+	tw.w.Header().Set("Access-Control-Allow-Origin", "*")
+	tw.w.WriteHeader(http.StatusGatewayTimeout)
+	json.NewEncoder(tw.w).Encode(map[string]string{
+		"code":       domain.ErrCodeTimeout,
+		"message":    "request timed out after " + d.String(),
+		"request_id": logging.RequestID(r.Context()),
+		"error":      "request timed out",
+		"timeout":    d.String(),
+	})
+}
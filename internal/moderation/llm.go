@@ -0,0 +1,56 @@
+package moderation
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"context"
+)
+
+// Client wraps an llm.Client and sanitizes the text of every prompt before it is sent, so
+// articles stored before sanitizing, or with it off at ingest, cannot steer the model. Only
+// the instruction-like sentences match, so the prompts' own instructions pass unchanged.
+// Queries given to PlanQuery come from the user and are passed as they are.
+type Client struct {
+	Inner llm.Client
+	Level string // Strictness; empty means Standard
+}
+
+var _ llm.Client = (*Client)(nil)
+
+// WrapLLM sanitizes the prompts of an LLM client at the given strictness
+func WrapLLM(inner llm.Client, level string) *Client {
+	return &Client{Inner: inner, Level: level}
+}
+
+func (c *Client) clean(text string) string {
+	out, _ := Sanitize(text, c.Level)
+	return out
+}
+
+func (c *Client) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
+	return c.Inner.Summarize(ctx, c.clean(text), opts)
+}
+
+func (c *Client) SentimentScore(ctx context.Context, text string) (float64, error) {
+	return c.Inner.SentimentScore(ctx, c.clean(text))
+}
+
+func (c *Client) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	return c.Inner.ToneCompare(ctx, c.clean(text1), c.clean(text2))
+}
+
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	return c.Inner.Embed(ctx, text)
+}
+
+func (c *Client) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return c.Inner.GenerateText(ctx, c.clean(prompt))
+}
+
+func (c *Client) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	return c.Inner.PlanQuery(ctx, query)
+}
+
+func (c *Client) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	return c.Inner.ExtractAllSemantics(ctx, c.clean(text))
+}
@@ -0,0 +1,111 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrFlagged is returned, wrapped with the flagged categories, for content a Moderator rejects
+var ErrFlagged = errors.New("content flagged by moderation")
+
+// Verdict is a moderation result: the harm categories the text was flagged for
+type Verdict struct {
+	Flagged    bool
+	Categories []string // e.g. "violence", "hate/threatening"
+}
+
+// Moderator classifies text for harmful content
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (*Verdict, error)
+}
+
+// severe are the categories rejected at Standard strictness; Strict rejects every flagged text.
+// News about wars or crime is often flagged for violence, so only content that is harmful in
+// itself is rejected by default.
+var severe = map[string]bool{
+	"sexual/minors":          true,
+	"hate/threatening":       true,
+	"harassment/threatening": true,
+	"self-harm/instructions": true,
+	"self-harm/intent":       true,
+}
+
+// Check moderates text and returns ErrFlagged, naming the categories, when the verdict is to be
+// rejected at the given strictness. Off never rejects.
+func Check(ctx context.Context, m Moderator, text, level string) error {
+	level = strings.ToLower(level)
+	if m == nil || level == Off {
+		return nil
+	}
+	v, err := m.Moderate(ctx, text)
+	if err != nil {
+		return fmt.Errorf("moderation failed: %w", err)
+	}
+	if !v.Flagged {
+		return nil
+	}
+	rejected := v.Categories
+	if level != Strict {
+		rejected = nil
+		for _, c := range v.Categories {
+			if severe[c] {
+				rejected = append(rejected, c)
+			}
+		}
+		if len(rejected) == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrFlagged, strings.Join(rejected, ", "))
+}
+
+// maxModerationChars bounds the text sent for moderation; the start of an article is enough
+// to classify it
+const maxModerationChars = 20000
+
+// OpenAIModerator classifies text with the OpenAI moderation endpoint
+type OpenAIModerator struct {
+	c     *openai.Client
+	Model string // Moderation model; empty uses the endpoint's default
+}
+
+// NewOpenAIModerator creates a moderator with an OpenAI API key
+func NewOpenAIModerator(apiKey string) *OpenAIModerator {
+	return &OpenAIModerator{c: openai.NewClient(apiKey)}
+}
+
+func (m *OpenAIModerator) Moderate(ctx context.Context, text string) (*Verdict, error) {
+	if len(text) > maxModerationChars {
+		text = strings.ToValidUTF8(text[:maxModerationChars], "")
+	}
+	resp, err := m.c.Moderations(ctx, openai.ModerationRequest{Input: text, Model: m.Model})
+	if err != nil {
+		return nil, err
+	}
+	v := &Verdict{}
+	for _, r := range resp.Results {
+		if !r.Flagged {
+			continue
+		}
+		v.Flagged = true
+		c := r.Categories
+		for name, on := range map[string]bool{
+			"hate": c.Hate, "hate/threatening": c.HateThreatening,
+			"harassment": c.Harassment, "harassment/threatening": c.HarassmentThreatening,
+			"self-harm": c.SelfHarm, "self-harm/intent": c.SelfHarmIntent, "self-harm/instructions": c.SelfHarmInstructions,
+			"sexual": c.Sexual, "sexual/minors": c.SexualMinors,
+			"violence": c.Violence, "violence/graphic": c.ViolenceGraphic,
+		} {
+			if on {
+				v.Categories = append(v.Categories, name)
+			}
+		}
+	}
+	sort.Strings(v.Categories)
+	return v, nil
+}
@@ -0,0 +1,94 @@
+// Package moderation keeps untrusted article text from steering the model it is given to.
+// Sanitize removes instruction-like sentences ("ignore previous instructions ...") and chat
+// markup from article text at ingest, and WrapLLM does the same for every prompt at the
+// moment it is sent. An optional Moderator rejects harmful articles at ingest.
+package moderation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Strictness levels
+const (
+	Off      = "off"
+	Standard = "standard" // Instruction overrides, prompt-leak requests and chat markup
+	Strict   = "strict"   // Also role-play and jailbreak phrasing and text addressed to an AI
+)
+
+var (
+	// chatMarkup are tokens chat models treat as turn boundaries; they are removed wherever they appear
+	chatMarkup = regexp.MustCompile(`(?i)<\|(?:im_start|im_end|endoftext|system|user|assistant)\|>|\[/?INST\]|<</?SYS>>`)
+
+	// rolePrefix marks a line posing as a system or assistant turn
+	rolePrefix = regexp.MustCompile(`(?i)^\s*(?:###\s*)?(?:system|assistant)\s*:`)
+
+	standardPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\b[^.!?\n]{0,40}\b(?:previous|prior|above|earlier|preceding|all|any|your|the|these)\b[^.!?\n]{0,30}\b(?:instructions?|prompts?|rules|directions|guidelines)\b`),
+		regexp.MustCompile(`(?i)\b(?:new|updated|real|actual|additional)\s+(?:instructions?|system\s+prompt)\s*:`),
+		regexp.MustCompile(`(?i)\b(?:reveal|print|show|repeat|output|leak)\b[^.!?\n]{0,30}\b(?:system\s+prompt|hidden\s+instructions|your\s+instructions|initial\s+prompt)\b`),
+	}
+
+	strictPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:you\s+are\s+now|from\s+now\s+on,?\s+you|act\s+as\s+an?|pretend\s+(?:to\s+be|you\s+are)|role-?play\s+as)\b`),
+		regexp.MustCompile(`(?i)\b(?:jailbreak|developer\s+mode|do\s+anything\s+now)\b`),
+		regexp.MustCompile(`(?i)\b(?:dear|attention|note\s+to(?:\s+the)?|hey)\s+(?:ai|llm|assistant|chatbot|language\s+model|gpt|chatgpt)\b`),
+		regexp.MustCompile(`(?i)\binstead,?\s+(?:say|respond|reply|output|write|answer)\b`),
+	}
+
+	sentence = regexp.MustCompile(`[^.!?]+[.!?]*\s*`)
+)
+
+// Sanitize removes the sentences of untrusted text that try to instruct the model reading it,
+// and chat markup, at the given strictness; empty means Standard. Lines posing as a system or
+// assistant turn are removed whole. It returns the cleaned text and how many sentences and
+// lines were removed.
+func Sanitize(text, level string) (string, int) {
+	level = strings.ToLower(level)
+	if level == Off {
+		return text, 0
+	}
+	patterns := standardPatterns
+	if level == Strict {
+		patterns = append(append([]*regexp.Regexp(nil), standardPatterns...), strictPatterns...)
+	}
+
+	removed := 0
+	text = chatMarkup.ReplaceAllStringFunc(text, func(string) string {
+		removed++
+		return ""
+	})
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if rolePrefix.MatchString(line) {
+			removed++
+			continue
+		}
+		if matchesAny(line, patterns) {
+			var b strings.Builder
+			for _, s := range sentence.FindAllString(line, -1) {
+				if matchesAny(s, patterns) {
+					removed++
+					continue
+				}
+				b.WriteString(s)
+			}
+			line = strings.TrimRight(b.String(), " \t")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n"), removed
+}
+
+func matchesAny(s string, patterns []*regexp.Regexp) bool {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAutoIngestWait is how long a chat request waits for missing articles to be ingested
+	DefaultAutoIngestWait = 45 * time.Second
+	defaultAutoIngestURLs = 5
+)
+
+// URLChecker reports which URLs are already stored
+type URLChecker interface {
+	GetExistingURLs(ctx context.Context, urls []string) (map[string]bool, error)
+}
+
+// AutoIngester ingests URLs a chat query refers to that are not stored yet, so the
+// query can be answered instead of failing with "Article not found"
+type AutoIngester struct {
+	Facade  *Facade
+	Checker URLChecker
+	Wait    time.Duration // Longer ingestions continue in the background
+	MaxURLs int           // Missing URLs ingested per query; the rest are left to the command
+}
+
+// NewAutoIngester creates an auto-ingester with default limits
+func NewAutoIngester(facade *Facade, checker URLChecker) *AutoIngester {
+	return &AutoIngester{
+		Facade:  facade,
+		Checker: checker,
+		Wait:    DefaultAutoIngestWait,
+		MaxURLs: defaultAutoIngestURLs,
+	}
+}
+
+// Ensure ingests the http(s) URLs that are not stored yet, concurrently, and waits up to Wait.
+// It returns the status of each URL it had to ingest; none means all were already stored.
+func (a *AutoIngester) Ensure(ctx context.Context, urls []string) ([]Status, error) {
+	existing, err := a.Checker.GetExistingURLs(ctx, urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stored URLs: %w", err)
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		if existing[u] || seen[u] || !(strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")) {
+			continue
+		}
+		seen[u] = true
+		missing = append(missing, u)
+	}
+	if len(missing) > a.MaxURLs {
+		missing = missing[:a.MaxURLs]
+	}
+
+	statuses := make([]Status, len(missing))
+	var wg sync.WaitGroup
+	for i, u := range missing {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i], _ = a.Facade.AddNewArticle(ctx, u, a.Wait)
+		}()
+	}
+	wg.Wait()
+	return statuses, nil
+}
+
+// Unfinished describes auto-ingested URLs that are still processing or failed, for the
+// chat answer; it is empty when all completed
+func Unfinished(statuses []Status) string {
+	var b strings.Builder
+	for _, st := range statuses {
+		switch st.State {
+		case StatusProcessing:
+			b.WriteString(fmt.Sprintf("%s is still being ingested; ask again shortly (status: /ingest/status?id=%s)\n", st.URL, st.ID))
+		case StatusFailed:
+			b.WriteString(fmt.Sprintf("%s could not be ingested: %s\n", st.URL, st.Error))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Pending reports whether any auto-ingested URL is still processing
+func Pending(statuses []Status) bool {
+	for _, st := range statuses {
+		if st.State == StatusProcessing {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed returns the error of each auto-ingested URL that failed, by URL
+func Failed(statuses []Status) map[string]string {
+	out := make(map[string]string)
+	for _, st := range statuses {
+		if st.State == StatusFailed {
+			out[st.URL] = st.Error
+		}
+	}
+	return out
+}
@@ -0,0 +1,461 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/tenant"
+	"article-assistant/internal/worker"
+
+	"github.com/google/uuid"
+)
+
+// Processing states
+const (
+	StatusProcessing = "processing"
+	StatusComplete   = "complete"
+	StatusFailed     = "failed"
+)
+
+// Phases of an ingest run by an Enricher
+const (
+	PhaseFetch  = "fetch"  // Fetching the page and storing its text
+	PhaseEnrich = "enrich" // Waiting for or running LLM enrichment
+)
+
+// Status describes the progress of a single AddNewArticle request
+type Status struct {
+	ID             string    `json:"id"`
+	URL            string    `json:"url"`
+	State          string    `json:"state"`
+	Error          string    `json:"error,omitempty"`
+	ErrorCategory  string    `json:"error_category,omitempty"` // Fetch failures: not_found, timeout, blocked, ... (see ingest.FetchError)
+	Phase          string    `json:"phase,omitempty"`          // fetch or enrich, for ingesters that enrich separately
+	Attempts       int       `json:"attempts"`
+	EnrichAttempts int       `json:"enrich_attempts,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Tenant         string    `json:"-"` // Tenant whose store the article is ingested into
+}
+
+// Ingester is the subset of ingest.Service used by the facade
+type Ingester interface {
+	IngestURL(ctx context.Context, url string) error
+}
+
+// Enricher is an Ingester that splits ingestion into a fast fetch, which stores the page text,
+// and LLM enrichment of the stored text. The facade then runs enrichment on its own queue with
+// its own retries, so a failed summary is retried without fetching the page again.
+type Enricher interface {
+	Ingester
+	// FetchURL fetches url and stores its text, reporting whether it awaits enrichment
+	FetchURL(ctx context.Context, url string) (bool, error)
+	// EnrichURL summarizes, embeds and analyzes the stored text of url and stores the article
+	EnrichURL(ctx context.Context, url string) error
+}
+
+var _ Enricher = (*ingest.Service)(nil)
+
+// FinishHook receives the final status of a request once it completes or fails;
+// hooks run in their own goroutine
+type FinishHook func(ctx context.Context, st Status)
+
+// Facade runs article ingestion in the background with retry and status tracking
+type Facade struct {
+	Ingester    Ingester
+	MaxAttempts int           // Total attempts including the first
+	BaseBackoff time.Duration // Delay before the first retry; doubles each retry
+	MaxBackoff  time.Duration
+	Retention   time.Duration // How long finished statuses are kept
+	Pool        *worker.Pool  // Shared ingestion workers; a default pool is started on first use when nil
+	EnrichPool  *worker.Pool  // Enrichment workers for an Enricher; a default pool is started on first use when nil
+
+	poolOnce   sync.Once
+	enrichOnce sync.Once
+	mu         sync.Mutex
+	statuses   map[string]*Status
+	hooks      []FinishHook
+}
+
+// Default ingestion pool limits; the concurrency avoids overwhelming the LLM API
+const (
+	DefaultIngestConcurrency = 5
+	DefaultIngestQueueSize   = 100
+	DefaultIngestURLTimeout  = 5 * time.Minute
+)
+
+// Default enrichment pool limits; fetched articles wait in the longer queue for LLM capacity
+const (
+	DefaultEnrichConcurrency = 5
+	DefaultEnrichQueueSize   = 1000
+	DefaultEnrichTimeout     = 5 * time.Minute
+)
+
+// NewFacade creates a facade with default retry settings
+func NewFacade(ingester Ingester) *Facade {
+	return &Facade{
+		Ingester:    ingester,
+		MaxAttempts: 3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Retention:   time.Hour,
+		statuses:    make(map[string]*Status),
+	}
+}
+
+// NewIngestPool creates an ingestion pool with the default limits
+func NewIngestPool(parent context.Context) *worker.Pool {
+	return worker.NewPool(parent, "ingest", DefaultIngestConcurrency, DefaultIngestQueueSize, DefaultIngestURLTimeout)
+}
+
+// NewEnrichPool creates an enrichment pool with the default limits
+func NewEnrichPool(parent context.Context) *worker.Pool {
+	return worker.NewPool(parent, "enrich", DefaultEnrichConcurrency, DefaultEnrichQueueSize, DefaultEnrichTimeout)
+}
+
+// AddNewArticle queues url for ingestion and waits up to wait for it to finish. It returns the
+// status snapshot and whether processing finished within the wait. When the queue is full the
+// status is failed and Rejected reports true.
+func (f *Facade) AddNewArticle(ctx context.Context, url string, wait time.Duration) (Status, bool) {
+	st, _, done := f.start(logging.RequestID(ctx), tenant.FromContext(ctx), url, func(job worker.Job) error {
+		return f.pool().TrySubmit(job)
+	})
+
+	select {
+	case <-done:
+		return f.snapshot(st.ID), true
+	case <-time.After(wait):
+		return f.snapshot(st.ID), false
+	case <-ctx.Done():
+		return f.snapshot(st.ID), false
+	}
+}
+
+// FetchArticle is AddNewArticle waiting only until url is fetched and its text stored; the
+// returned status is still processing, in the enrich phase, while enrichment runs in the
+// background. Ingesters that are not Enrichers are waited for until they finish.
+func (f *Facade) FetchArticle(ctx context.Context, url string, wait time.Duration) (Status, bool) {
+	st, fetched, _ := f.start(logging.RequestID(ctx), tenant.FromContext(ctx), url, func(job worker.Job) error {
+		return f.pool().TrySubmit(job)
+	})
+
+	select {
+	case <-fetched:
+		return f.snapshot(st.ID), true
+	case <-time.After(wait):
+		return f.snapshot(st.ID), false
+	case <-ctx.Done():
+		return f.snapshot(st.ID), false
+	}
+}
+
+// Enqueue queues url for background ingestion, waiting for queue room until ctx is done, and
+// returns its initial status
+func (f *Facade) Enqueue(ctx context.Context, url string) (Status, error) {
+	var submitErr error
+	st, _, _ := f.start("", tenant.FromContext(ctx), url, func(job worker.Job) error {
+		submitErr = f.pool().Submit(ctx, job)
+		return submitErr
+	})
+	return st, submitErr
+}
+
+// Submit queues url for background ingestion without waiting and returns its initial status.
+// When the queue is full the status is failed and Rejected reports true.
+func (f *Facade) Submit(ctx context.Context, url string) Status {
+	st, _, _ := f.start(logging.RequestID(ctx), tenant.FromContext(ctx), url, func(job worker.Job) error {
+		return f.pool().TrySubmit(job)
+	})
+	return st
+}
+
+// Rejected reports whether the request was never started because the queue was full or
+// the server was shutting down
+func (s Status) Rejected() bool {
+	return s.State == StatusFailed && s.Attempts == 0
+}
+
+// ResumeEnrichment queues enrichment of urls, whose text was fetched earlier, e.g. before a
+// restart, waiting for queue room until ctx is done. It needs an Enricher.
+func (f *Facade) ResumeEnrichment(ctx context.Context, urls []string) error {
+	enricher, ok := f.Ingester.(Enricher)
+	if !ok {
+		return errors.New("the ingester does not enrich separately")
+	}
+	for _, url := range urls {
+		st := f.register(url, tenant.FromContext(ctx))
+		f.update(st.ID, func(s *Status) { s.Phase = PhaseEnrich })
+		scope := jobScope("", st.Tenant)
+		if err := f.enrich(ctx, enricher, st, scope, make(chan struct{})); err != nil {
+			f.settle(st.ID, err)
+			f.finish(scope(ctx), st.ID)
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Facade) pool() *worker.Pool {
+	f.poolOnce.Do(func() {
+		if f.Pool == nil {
+			f.Pool = NewIngestPool(context.Background())
+		}
+	})
+	return f.Pool
+}
+
+func (f *Facade) enrichPool() *worker.Pool {
+	f.enrichOnce.Do(func() {
+		if f.EnrichPool == nil {
+			f.EnrichPool = NewEnrichPool(context.Background())
+		}
+	})
+	return f.EnrichPool
+}
+
+// start registers a status for url and hands its processing to submit; fetched is closed once
+// the page is fetched or processing ended, and done when processing ends. requestID, if set,
+// keeps background logs correlated with the originating request; processing runs as tenantID.
+func (f *Facade) start(requestID, tenantID, url string, submit func(worker.Job) error) (Status, <-chan struct{}, <-chan struct{}) {
+	st := f.register(url, tenantID)
+	initial := f.snapshot(st.ID)
+
+	// Processing outlives the HTTP request, so it runs under the pool's context, not the request's
+	fetched, done := make(chan struct{}), make(chan struct{})
+	scope := jobScope(requestID, tenantID)
+	err := submit(func(ctx context.Context) {
+		f.run(scope(ctx), st, scope, fetched, done)
+	})
+	if err != nil {
+		f.update(st.ID, func(s *Status) { s.State = StatusFailed; s.Error = err.Error() })
+		f.finish(scope(context.Background()), st.ID)
+		initial = f.snapshot(st.ID)
+		close(fetched)
+		close(done)
+	}
+	return initial, fetched, done
+}
+
+// register adds a processing status for url
+func (f *Facade) register(url, tenantID string) *Status {
+	now := time.Now()
+	st := &Status{
+		ID:        uuid.New().String(),
+		URL:       url,
+		State:     StatusProcessing,
+		StartedAt: now,
+		UpdatedAt: now,
+		Tenant:    tenantID,
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pruneLocked(now)
+	f.statuses[st.ID] = st
+	return st
+}
+
+// jobScope returns a function giving pool contexts the request ID and tenant of the request
+// that started the job
+func jobScope(requestID, tenantID string) func(context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		ctx = tenant.NewContext(ctx, tenantID)
+		if requestID != "" {
+			ctx = logging.WithRequestID(ctx, requestID)
+		}
+		return ctx
+	}
+}
+
+// OnFinish registers a hook called when a request completes or fails
+func (f *Facade) OnFinish(hook FinishHook) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hooks = append(f.hooks, hook)
+}
+
+// Status returns the status for an ID
+func (f *Facade) Status(id string) (Status, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, ok := f.statuses[id]
+	if !ok {
+		return Status{}, false
+	}
+	return *st, true
+}
+
+func (f *Facade) snapshot(id string) Status {
+	st, _ := f.Status(id)
+	return st
+}
+
+// run ingests with exponential backoff on transient errors. An Enricher's fetch is retried
+// here and its enrichment handed to the enrichment pool, which retries it separately.
+func (f *Facade) run(ctx context.Context, st *Status, scope func(context.Context) context.Context, fetched, done chan struct{}) {
+	enricher, ok := f.Ingester.(Enricher)
+	if !ok {
+		defer close(done)
+		defer close(fetched)
+		defer f.finish(ctx, st.ID)
+		f.settle(st.ID, f.retry(ctx, st, attempts, func(ctx context.Context) error { return f.Ingester.IngestURL(ctx, st.URL) }))
+		return
+	}
+
+	f.update(st.ID, func(s *Status) { s.Phase = PhaseFetch })
+	pending := false
+	err := f.retry(ctx, st, attempts, func(ctx context.Context) (err error) {
+		pending, err = enricher.FetchURL(ctx, st.URL)
+		return err
+	})
+	if err != nil || !pending {
+		f.settle(st.ID, err)
+		f.finish(ctx, st.ID)
+		close(fetched)
+		close(done)
+		return
+	}
+
+	f.update(st.ID, func(s *Status) { s.Phase = PhaseEnrich })
+	close(fetched)
+	if err := f.enrich(ctx, enricher, st, scope, done); err != nil {
+		f.settle(st.ID, err)
+		f.finish(ctx, st.ID)
+		close(done)
+	}
+}
+
+// enrich queues the enrichment of st, waiting for room until ctx is done; done is closed when
+// enrichment ends. It returns an error only when the job was not queued.
+func (f *Facade) enrich(ctx context.Context, enricher Enricher, st *Status, scope func(context.Context) context.Context, done chan struct{}) error {
+	return f.enrichPool().Submit(ctx, func(ctx context.Context) {
+		ctx = scope(ctx)
+		defer close(done)
+		defer f.finish(ctx, st.ID)
+		f.settle(st.ID, f.retry(ctx, st, enrichAttempts, func(ctx context.Context) error { return enricher.EnrichURL(ctx, st.URL) }))
+	})
+}
+
+// attempts and enrichAttempts select the status counter a retry loop advances
+func attempts(s *Status) *int       { return &s.Attempts }
+func enrichAttempts(s *Status) *int { return &s.EnrichAttempts }
+
+// retry calls do until it succeeds, fails permanently or runs out of attempts, backing off
+// exponentially between transient errors, and returns the last error
+func (f *Facade) retry(ctx context.Context, st *Status, counter func(*Status) *int, do func(context.Context) error) error {
+	logger := logging.FromContext(ctx).With("url", st.URL, "status_id", st.ID)
+	backoff := f.BaseBackoff
+	for attempt := 1; ; attempt++ {
+		f.update(st.ID, func(s *Status) { *counter(s) = attempt })
+
+		err := do(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= f.MaxAttempts || !IsTransient(err) {
+			logger.Error("processing failed", "attempts", attempt, "error", err)
+			return err
+		}
+
+		logger.Warn("transient error, retrying", "attempt", attempt, "max_attempts", f.MaxAttempts, "backoff", backoff.String(), "error", err)
+		f.update(st.ID, func(s *Status) { s.Error, s.ErrorCategory = err.Error(), ingest.FetchErrorCategory(err) })
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > f.MaxBackoff {
+			backoff = f.MaxBackoff
+		}
+	}
+}
+
+// settle marks a status complete, or failed with err
+func (f *Facade) settle(id string, err error) {
+	if err == nil {
+		f.update(id, func(s *Status) { s.State = StatusComplete; s.Error = ""; s.ErrorCategory = "" })
+		return
+	}
+	f.update(id, func(s *Status) {
+		s.State, s.Error, s.ErrorCategory = StatusFailed, err.Error(), ingest.FetchErrorCategory(err)
+	})
+}
+
+// finish passes the final status to the registered hooks
+func (f *Facade) finish(ctx context.Context, id string) {
+	f.mu.Lock()
+	st, ok := f.statuses[id]
+	if !ok {
+		f.mu.Unlock()
+		return
+	}
+	final := *st
+	hooks := f.hooks
+	f.mu.Unlock()
+
+	for _, hook := range hooks {
+		go hook(ctx, final)
+	}
+}
+
+func (f *Facade) update(id string, fn func(*Status)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if st, ok := f.statuses[id]; ok {
+		fn(st)
+		st.UpdatedAt = time.Now()
+	}
+}
+
+// pruneLocked drops finished statuses older than the retention window; the caller must hold mu
+func (f *Facade) pruneLocked(now time.Time) {
+	for id, st := range f.statuses {
+		if st.State != StatusProcessing && now.Sub(st.UpdatedAt) > f.Retention {
+			delete(f.statuses, id)
+		}
+	}
+}
+
+// IsTransient reports whether an ingest error is worth retrying: network timeouts,
+// connection failures, rate limits, a full LLM limiter and 5xx responses
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	// The fetcher already retried transient fetch failures with backoff
+	if ingest.FetchErrorCategory(err) != "" {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, llm.ErrBusy) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{
+		"timeout", "connection reset", "connection refused", "unexpected eof",
+		"status code: 429", "status=429", "rate limit", "too many requests",
+		"status code: 5", "status=5", "overloaded", "unavailable",
+	} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package processing
+
+import (
+	"context"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+)
+
+// FailureStore persists ingests that failed after all retries
+type FailureStore interface {
+	RecordIngestFailure(ctx context.Context, failure *domain.IngestFailure) error
+	ResolveIngestFailure(ctx context.Context, url string) (bool, error)
+	SetArticleStatus(ctx context.Context, url, status, reason string) error
+}
+
+// FailureHook returns a facade hook that records failed requests in store and clears a URL's
+// record once it ingests. Requests rejected before any attempt are not recorded. Articles
+// whose enrichment failed are marked failed.
+func FailureHook(store FailureStore) FinishHook {
+	return func(ctx context.Context, st Status) {
+		log := logging.FromContext(ctx)
+		switch {
+		case st.State == StatusComplete:
+			if _, err := store.ResolveIngestFailure(ctx, st.URL); err != nil {
+				log.Warn("failed to clear ingest failure", "url", st.URL, "error", err)
+			}
+		case st.State == StatusFailed && !st.Rejected():
+			failure := &domain.IngestFailure{URL: st.URL, Error: st.Error, ErrorCategory: st.ErrorCategory, Attempts: st.Attempts}
+			if err := store.RecordIngestFailure(ctx, failure); err != nil {
+				log.Warn("failed to record ingest failure", "url", st.URL, "error", err)
+			}
+			if st.Phase == PhaseEnrich {
+				if err := store.SetArticleStatus(ctx, st.URL, domain.ArticleFailed, st.Error); err != nil {
+					log.Warn("failed to mark article failed", "url", st.URL, "error", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Package prompts loads versioned prompt templates and renders them. Templates are YAML files
+// named <prompt>/<version>.yaml; the built-in set is embedded and a directory with the same
+// layout can replace it.
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"article-assistant/internal/commands"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Planner is the chat query planner prompt
+const Planner = "planner"
+
+// VersionHeader pins the planner prompt version of a chat request
+const VersionHeader = "X-Prompt-Version"
+
+//go:embed templates
+var embedded embed.FS
+
+// Template is one version of a prompt
+type Template struct {
+	Name        string    `yaml:"name"`
+	Version     string    `yaml:"version"`
+	Description string    `yaml:"description"`
+	Text        string    `yaml:"text"`     // text/template source
+	Commands    []Command `yaml:"commands"` // Few-shot examples per planner command
+
+	tmpl *template.Template
+}
+
+// Command holds a version's examples for a registered command. The planner prompt lists every
+// command in commands.Registry; Description replaces the registry's description in this
+// version when set.
+type Command struct {
+	Name        string    `yaml:"name"`
+	Description string    `yaml:"description"`
+	Examples    []Example `yaml:"examples"`
+}
+
+// Example is a query with the plan JSON the planner should return for it
+type Example struct {
+	Query string `yaml:"query"`
+	Plan  string `yaml:"plan"`
+}
+
+// Render executes the template with data
+func (t *Template) Render(data interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s prompt %s: %w", t.Name, t.Version, err)
+	}
+	return b.String(), nil
+}
+
+// Loader reads templates from a file system
+type Loader struct {
+	fsys fs.FS
+}
+
+// NewLoader loads templates from fsys
+func NewLoader(fsys fs.FS) *Loader {
+	return &Loader{fsys: fsys}
+}
+
+// EmbeddedLoader loads the built-in templates
+func EmbeddedLoader() *Loader {
+	sub, _ := fs.Sub(embedded, "templates")
+	return NewLoader(sub)
+}
+
+// DirLoader loads templates from dir, or the built-in ones when dir is empty
+func DirLoader(dir string) *Loader {
+	if dir == "" {
+		return EmbeddedLoader()
+	}
+	return NewLoader(os.DirFS(dir))
+}
+
+// Versions lists the versions of a prompt, oldest first
+func (l *Loader) Versions(name string) ([]string, error) {
+	entries, err := fs.ReadDir(l.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s prompt versions: %w", name, err)
+	}
+	var versions []string
+	for _, e := range entries {
+		if v, ok := strings.CutSuffix(e.Name(), ".yaml"); ok && !e.IsDir() {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions of the %s prompt", name)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versionLess(versions[i], versions[j]) })
+	return versions, nil
+}
+
+// versionLess orders v2 before v10; versions without a number sort by name
+func versionLess(a, b string) bool {
+	na, errA := strconv.Atoi(strings.TrimPrefix(a, "v"))
+	nb, errB := strconv.Atoi(strings.TrimPrefix(b, "v"))
+	if errA == nil && errB == nil && na != nb {
+		return na < nb
+	}
+	return a < b
+}
+
+// Load reads and parses one version of a prompt; an empty version loads the latest
+func (l *Loader) Load(name, version string) (*Template, error) {
+	if version == "" {
+		versions, err := l.Versions(name)
+		if err != nil {
+			return nil, err
+		}
+		version = versions[len(versions)-1]
+	}
+	data, err := fs.ReadFile(l.fsys, path.Join(name, version+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s prompt %s: %w", name, version, err)
+	}
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse %s prompt %s: %w", name, version, err)
+	}
+	t.Name, t.Version = name, version
+	for _, c := range t.Commands {
+		if _, ok := commands.Lookup(c.Name); !ok {
+			return nil, fmt.Errorf("%s prompt %s has examples for unknown command %q", name, version, c.Name)
+		}
+	}
+	if t.tmpl, err = template.New(name + "/" + version).Option("missingkey=error").Parse(t.Text); err != nil {
+		return nil, fmt.Errorf("failed to parse %s prompt %s template: %w", name, version, err)
+	}
+	return &t, nil
+}
+
+// Factory renders the prompts used by the LLM clients. Every version of the planner prompt is
+// loaded; requests use the one named in their context, or the default.
+type Factory struct {
+	planners       map[string]*Template
+	plannerDefault string
+	plannerSplit   []share // Optional traffic split between versions
+}
+
+type share struct {
+	version string
+	weight  int
+}
+
+// NewFactory loads every version of each prompt. defaults names the version used when a
+// request does not pick one, keyed by prompt name; the latest version is the default otherwise.
+func NewFactory(loader *Loader, defaults map[string]string) (*Factory, error) {
+	versions, err := loader.Versions(Planner)
+	if err != nil {
+		return nil, err
+	}
+	f := &Factory{planners: make(map[string]*Template, len(versions)), plannerDefault: versions[len(versions)-1]}
+	for _, v := range versions {
+		if f.planners[v], err = loader.Load(Planner, v); err != nil {
+			return nil, err
+		}
+	}
+	if v := defaults[Planner]; v != "" {
+		if f.planners[v] == nil {
+			return nil, fmt.Errorf("unknown %s prompt version %q (have %s)", Planner, v, strings.Join(versions, ", "))
+		}
+		f.plannerDefault = v
+	}
+	return f, nil
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultFactory *Factory
+)
+
+// Default renders the built-in prompts, using the latest version of each by default
+func Default() *Factory {
+	defaultOnce.Do(func() {
+		f, err := NewFactory(EmbeddedLoader(), nil)
+		if err != nil {
+			panic(fmt.Sprintf("built-in prompts: %v", err))
+		}
+		defaultFactory = f
+	})
+	return defaultFactory
+}
+
+// PlannerTemplate returns the default planner prompt version
+func (f *Factory) PlannerTemplate() *Template {
+	return f.planners[f.plannerDefault]
+}
+
+// PlannerVersions lists the loaded planner prompt versions, oldest first
+func (f *Factory) PlannerVersions() []string {
+	versions := make([]string, 0, len(f.planners))
+	for v := range f.planners {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versionLess(versions[i], versions[j]) })
+	return versions
+}
+
+// SplitPlanner sends requests that do not pin a version to the given versions in proportion
+// to their weights, for A/B tests of prompt changes
+func (f *Factory) SplitPlanner(weights map[string]int) error {
+	var split []share
+	for v, w := range weights {
+		if f.planners[v] == nil {
+			return fmt.Errorf("unknown %s prompt version %q", Planner, v)
+		}
+		if w < 0 {
+			return fmt.Errorf("negative weight %d for %s prompt %s", w, Planner, v)
+		}
+		if w > 0 {
+			split = append(split, share{v, w})
+		}
+	}
+	if len(split) == 0 {
+		return fmt.Errorf("%s prompt split needs a positive weight", Planner)
+	}
+	sort.Slice(split, func(i, j int) bool { return versionLess(split[i].version, split[j].version) })
+	f.plannerSplit = split
+	return nil
+}
+
+// ChoosePlanner returns the planner version for a request: pinned when set, otherwise one
+// picked by the split or the default. Requests with the same non-empty key, such as a session
+// ID, get the same version.
+func (f *Factory) ChoosePlanner(pinned, key string) (string, error) {
+	if pinned != "" {
+		if f.planners[pinned] == nil {
+			return "", fmt.Errorf("unknown %s prompt version %q (have %s)", Planner, pinned, strings.Join(f.PlannerVersions(), ", "))
+		}
+		return pinned, nil
+	}
+	if len(f.plannerSplit) == 0 {
+		return f.plannerDefault, nil
+	}
+
+	total := 0
+	for _, s := range f.plannerSplit {
+		total += s.weight
+	}
+	var n int
+	if key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		n = int(h.Sum32() % uint32(total))
+	} else {
+		n = rand.IntN(total)
+	}
+	for _, s := range f.plannerSplit {
+		if n < s.weight {
+			return s.version, nil
+		}
+		n -= s.weight
+	}
+	return f.plannerDefault, nil
+}
+
+// Planner builds the query planner prompt for query with the version named in ctx, or the
+// default version
+func (f *Factory) Planner(ctx context.Context, query string) (string, error) {
+	version := VersionFromContext(ctx, Planner)
+	if version == "" {
+		version = f.plannerDefault
+	}
+	t := f.planners[version]
+	if t == nil {
+		return "", fmt.Errorf("unknown %s prompt version %q", Planner, version)
+	}
+	return t.Render(struct {
+		Query    string
+		Commands []Command
+	}{query, t.PlannerCommands()})
+}
+
+// PlannerCommands lists every registered command, in registry order, with this version's
+// description override and examples
+func (t *Template) PlannerCommands() []Command {
+	own := make(map[string]Command, len(t.Commands))
+	for _, c := range t.Commands {
+		own[c.Name] = c
+	}
+	out := make([]Command, len(commands.Registry))
+	for i, reg := range commands.Registry {
+		c := own[reg.Name]
+		c.Name = reg.Name
+		if c.Description == "" {
+			c.Description = reg.Description
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// ParseWeights parses a split such as "v1=90,v2=10"
+func ParseWeights(s string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, part := range strings.Split(s, ",") {
+		version, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if !ok || err != nil || strings.TrimSpace(version) == "" {
+			return nil, fmt.Errorf("invalid weight %q, want version=weight", part)
+		}
+		weights[strings.TrimSpace(version)] = n
+	}
+	return weights, nil
+}
+
+type versionsCtxKey struct{}
+
+// WithVersion returns a context that renders prompt name at version
+func WithVersion(ctx context.Context, name, version string) context.Context {
+	versions := map[string]string{name: version}
+	for n, v := range versionsFromContext(ctx) {
+		if n != name {
+			versions[n] = v
+		}
+	}
+	return context.WithValue(ctx, versionsCtxKey{}, versions)
+}
+
+// VersionFromContext returns the version of prompt name chosen for ctx, or ""
+func VersionFromContext(ctx context.Context, name string) string {
+	return versionsFromContext(ctx)[name]
+}
+
+func versionsFromContext(ctx context.Context) map[string]string {
+	versions, _ := ctx.Value(versionsCtxKey{}).(map[string]string)
+	return versions
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"article-assistant/internal/domain"
+)
+
+// ArticleFields are the Article fields ListAllArticles can select, by JSON name. The embedding
+// is left out: listings never need it and it is most of an article row.
+var ArticleFields = []string{
+	"id", "url", "title", "summary", "content", "sentiment", "sentiment_score", "tone",
+	"entities", "keywords", "topics", "topic_ids", "url_hash", "author", "section", "published_at",
+	"source_domain", "language", "content_hash", "last_refreshed_at", "imported", "embedding_model",
+	"summary_version", "summary_model", "analysis_version", "analysis_model", "status", "status_error", "created_at", "updated_at",
+}
+
+// listItemFields are the fields of domain.ArticleListItem, selected when no fields are given
+var listItemFields = []string{"id", "url", "title", "sentiment", "sentiment_score", "author", "section", "published_at", "status", "status_error",
+	"summary_version", "summary_model", "analysis_version", "analysis_model", "created_at"}
+
+// selectFields validates fields and returns them with "id" first, or the list item fields
+// when fields is empty
+func selectFields(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return listItemFields, nil
+	}
+	known := make(map[string]bool, len(ArticleFields))
+	for _, f := range ArticleFields {
+		known[f] = true
+	}
+	out := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, f := range fields {
+		if !known[f] {
+			return nil, fmt.Errorf("unsupported field: %s", f)
+		}
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// checkSort validates a ListAllArticles sort key
+func checkSort(sort string) error {
+	if _, ok := articleSortColumns[sort]; !ok && sort != "" {
+		return fmt.Errorf("unsupported sort: %s", sort)
+	}
+	return nil
+}
+
+// articleLister is the part of ArticleStore that listArticlePage builds on
+type articleLister interface {
+	ListAllArticles(ctx context.Context, opts domain.ArticleListOptions) ([]domain.Article, error)
+	CountArticles(ctx context.Context, filter domain.ArticleFilter) (int, error)
+}
+
+// listArticlePage implements ListArticles with ListAllArticles and CountArticles
+func listArticlePage(ctx context.Context, store articleLister, filter domain.ArticleFilter, limit, offset int, sort string, desc bool) ([]domain.ArticleListItem, int, error) {
+	articles, err := store.ListAllArticles(ctx, domain.ArticleListOptions{Filter: filter, Sort: sort, Desc: desc, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := store.CountArticles(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	items := make([]domain.ArticleListItem, len(articles))
+	for i, a := range articles {
+		items[i] = domain.ArticleListItem{
+			ID: a.ID, URL: a.URL, Title: a.Title, Sentiment: a.Sentiment, SentimentScore: a.SentimentScore,
+			Author: a.Author, Section: a.Section, PublishedAt: a.PublishedAt, Status: a.Status, StatusError: a.StatusError,
+			SummaryVersion: a.SummaryVersion, SummaryModel: a.SummaryModel, AnalysisVersion: a.AnalysisVersion, AnalysisModel: a.AnalysisModel,
+			CreatedAt: a.CreatedAt,
+		}
+	}
+	return items, total, nil
+}
@@ -0,0 +1,1951 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore is an in-process ArticleStore for unit tests and running without a database.
+// Searches scan every article, so it suits corpora of a few thousand articles at most.
+// Results match Repo's, except that full-text matching is plain case-insensitive term search.
+type MemoryStore struct {
+	mu              sync.RWMutex
+	articles        map[string]*domain.Article // Keyed by URL
+	chunks          map[string][]memoryChunk   // Keyed by article ID
+	duplicates      map[string]*memoryDuplicate
+	simplifications map[string]map[string]string // Article ID -> level -> text
+	chatCache       map[string]*domain.ChatCache
+	topics          map[string]*domain.TaxonomyTopic
+	tags            map[string]map[string]bool      // Article ID -> tag set
+	notes           map[string][]domain.ArticleNote // Article ID -> notes, oldest first
+	collections     map[string]*domain.Collection
+	members         map[string]map[string]bool // Collection ID -> article ID set
+	searches        map[string]*domain.SavedSearch
+	alerts          []domain.Alert                   // In creation order
+	failures        map[string]*domain.IngestFailure // Keyed by URL
+	pending         map[string]domain.PendingArticle // Keyed by URL
+
+	// HybridMinSimilarity is the cosine similarity from which an article matches a hybrid
+	// search without matching its terms
+	HybridMinSimilarity float64
+}
+
+type memoryChunk struct {
+	text      string
+	embedding []float32
+}
+
+type memoryDuplicate struct {
+	article    domain.Article
+	similarity float64
+}
+
+var _ ArticleStore = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		articles:        make(map[string]*domain.Article),
+		chunks:          make(map[string][]memoryChunk),
+		duplicates:      make(map[string]*memoryDuplicate),
+		simplifications: make(map[string]map[string]string),
+		chatCache:       make(map[string]*domain.ChatCache),
+		topics:          make(map[string]*domain.TaxonomyTopic),
+		tags:            make(map[string]map[string]bool),
+		notes:           make(map[string][]domain.ArticleNote),
+		collections:     make(map[string]*domain.Collection),
+		members:         make(map[string]map[string]bool),
+		searches:        make(map[string]*domain.SavedSearch),
+		failures:        make(map[string]*domain.IngestFailure),
+		pending:         make(map[string]domain.PendingArticle),
+
+		HybridMinSimilarity: DefaultHybridMinSimilarity,
+	}
+}
+
+// ---------- Helpers ----------
+
+// cosineSimilarity returns 0 for empty or mismatched vectors
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// listed copies a without the fields Repo leaves out of articleColumns queries
+func listed(a *domain.Article) domain.Article {
+	out := *a
+	out.Content, out.Embedding, out.ContentHash, out.ETag, out.URLHash = "", nil, "", "", ""
+	return out
+}
+
+// hasTopic reports whether one of a's extracted topic names contains topic, ignoring case
+func hasTopic(a *domain.Article, topic string) bool {
+	for _, t := range a.Topics {
+		if containsFold(t.Name, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func urlSet(urls []string) map[string]bool {
+	if len(urls) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		set[u] = true
+	}
+	return set
+}
+
+// hasTopicID reports whether a is mapped to one of the taxonomy topics in ids
+func hasTopicID(a *domain.Article, ids map[string]bool) bool {
+	for _, id := range a.TopicIDs {
+		if ids[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// subtopics returns id and the IDs of all taxonomy topics below it; callers hold m.mu
+func (m *MemoryStore) subtopics(id string) map[string]bool {
+	ids := map[string]bool{id: true}
+	for grew := true; grew; {
+		grew = false
+		for _, t := range m.topics {
+			if ids[t.ParentID] && !ids[t.ID] {
+				ids[t.ID], grew = true, true
+			}
+		}
+	}
+	return ids
+}
+
+// selected returns the articles passing the URL and metadata filters, newest first
+func (m *MemoryStore) selected(urls []string, filter domain.ArticleFilter) []*domain.Article {
+	only := urlSet(urls)
+	var topicIDs map[string]bool
+	if filter.TopicID != "" {
+		topicIDs = m.subtopics(filter.TopicID)
+	}
+	var members map[string]bool
+	if filter.Collection != "" {
+		members = make(map[string]bool) // An unknown collection matches nothing
+		if c := m.collectionNamed(filter.Collection); c != nil {
+			members = m.members[c.ID]
+		}
+	}
+	var out []*domain.Article
+	for _, a := range m.articles {
+		if only != nil && !only[a.URL] {
+			continue
+		}
+		if filter.Author != "" && !containsFold(a.Author, filter.Author) {
+			continue
+		}
+		if filter.Section != "" && !containsFold(a.Section, filter.Section) {
+			continue
+		}
+		if !filter.IngestedAfter.IsZero() && !a.CreatedAt.After(filter.IngestedAfter) {
+			continue
+		}
+		if !filter.UpdatedAfter.IsZero() && !a.UpdatedAt.After(filter.UpdatedAfter) {
+			continue
+		}
+		published := a.CreatedAt
+		if a.PublishedAt != nil {
+			published = *a.PublishedAt
+		}
+		if !filter.PublishedAfter.IsZero() && published.Before(filter.PublishedAfter) {
+			continue
+		}
+		if !filter.PublishedBefore.IsZero() && !published.Before(filter.PublishedBefore) {
+			continue
+		}
+		if filter.SourceDomain != "" && !containsFold(a.SourceDomain, filter.SourceDomain) {
+			continue
+		}
+		if filter.Topic != "" && !hasTopic(a, filter.Topic) {
+			continue
+		}
+		if topicIDs != nil && !hasTopicID(a, topicIDs) {
+			continue
+		}
+		if filter.Tag != "" && !m.tags[a.ID][filter.Tag] {
+			continue
+		}
+		if members != nil && !members[a.ID] {
+			continue
+		}
+		if filter.Status != "" && a.Status != filter.Status {
+			continue
+		}
+		if filter.AnalysisVersion != "" && a.AnalysisVersion != filter.AnalysisVersion {
+			continue
+		}
+		if filter.SummaryVersion != "" && a.SummaryVersion != filter.SummaryVersion {
+			continue
+		}
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// textRank scores how well an article matches every term of query, weighting title over
+// summary over body like the search_tsv column. It is 0 unless all terms match and, like
+// ts_rank_cd normalization 32, stays in [0, 1).
+func textRank(a *domain.Article, query string) float64 {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return 0
+	}
+	title, summary, content := strings.ToLower(a.Title), strings.ToLower(a.Summary), strings.ToLower(a.Content)
+	var rank float64
+	for _, t := range terms {
+		score := 1.0*float64(strings.Count(title, t)) + 0.4*float64(strings.Count(summary, t)) + 0.1*float64(strings.Count(content, t))
+		if score == 0 {
+			return 0
+		}
+		rank += score
+	}
+	rank /= 10
+	return rank / (rank + 1)
+}
+
+// ---------- Articles ----------
+
+func (m *MemoryStore) GetArticleByURL(ctx context.Context, url string) (*domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.articles[url]
+	if !ok {
+		return nil, nil
+	}
+	out := *a
+	return &out, nil
+}
+
+func (m *MemoryStore) GetArticleByID(ctx context.Context, id string) (*domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, a := range m.articles {
+		if a.ID == id {
+			out := listed(a)
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MemoryStore) GetArticlesByURLs(ctx context.Context, urls []string) ([]domain.Article, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs provided")
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.Article
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		if a, ok := m.articles[u]; ok && !seen[u] {
+			seen[u] = true
+			out = append(out, listed(a))
+		}
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) GetExistingURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	existing := make(map[string]bool)
+	for _, u := range urls {
+		if a, ok := m.articles[u]; ok && a.Status == domain.ArticleEnriched {
+			existing[u] = true
+		} else if _, ok := m.duplicates[u]; ok {
+			existing[u] = true
+		}
+	}
+	return existing, nil
+}
+
+func (m *MemoryStore) GetArticleURLs(ctx context.Context, urls []string, filter domain.ArticleFilter) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []string
+	for _, a := range m.selected(urls, filter) {
+		out = append(out, a.URL)
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) GetArticleContentsByURLs(ctx context.Context, urls []string) (map[string]string, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs provided")
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	contents := make(map[string]string)
+	for _, u := range urls {
+		if a, ok := m.articles[u]; ok && a.Content != "" {
+			contents[u] = a.Content
+		}
+	}
+	return contents, nil
+}
+
+func (m *MemoryStore) ListArticles(ctx context.Context, filter domain.ArticleFilter, limit, offset int, sortBy string, desc bool) ([]domain.ArticleListItem, int, error) {
+	return listArticlePage(ctx, m, filter, limit, offset, sortBy, desc)
+}
+
+// articleFieldCopies copies each of the ArticleFields from src to dst
+var articleFieldCopies = map[string]func(dst, src *domain.Article){
+	"id":                func(dst, src *domain.Article) { dst.ID = src.ID },
+	"url":               func(dst, src *domain.Article) { dst.URL = src.URL },
+	"title":             func(dst, src *domain.Article) { dst.Title = src.Title },
+	"summary":           func(dst, src *domain.Article) { dst.Summary = src.Summary },
+	"content":           func(dst, src *domain.Article) { dst.Content = src.Content },
+	"sentiment":         func(dst, src *domain.Article) { dst.Sentiment = src.Sentiment },
+	"sentiment_score":   func(dst, src *domain.Article) { dst.SentimentScore = src.SentimentScore },
+	"tone":              func(dst, src *domain.Article) { dst.Tone = src.Tone },
+	"entities":          func(dst, src *domain.Article) { dst.Entities = src.Entities },
+	"keywords":          func(dst, src *domain.Article) { dst.Keywords = src.Keywords },
+	"topics":            func(dst, src *domain.Article) { dst.Topics = src.Topics },
+	"topic_ids":         func(dst, src *domain.Article) { dst.TopicIDs = src.TopicIDs },
+	"url_hash":          func(dst, src *domain.Article) { dst.URLHash = src.URLHash },
+	"author":            func(dst, src *domain.Article) { dst.Author = src.Author },
+	"section":           func(dst, src *domain.Article) { dst.Section = src.Section },
+	"published_at":      func(dst, src *domain.Article) { dst.PublishedAt = src.PublishedAt },
+	"source_domain":     func(dst, src *domain.Article) { dst.SourceDomain = src.SourceDomain },
+	"language":          func(dst, src *domain.Article) { dst.Language = src.Language },
+	"content_hash":      func(dst, src *domain.Article) { dst.ContentHash = src.ContentHash },
+	"last_refreshed_at": func(dst, src *domain.Article) { dst.LastRefreshedAt = src.LastRefreshedAt },
+	"imported":          func(dst, src *domain.Article) { dst.Imported = src.Imported },
+	"embedding_model":   func(dst, src *domain.Article) { dst.EmbeddingModel = src.EmbeddingModel },
+	"summary_version":   func(dst, src *domain.Article) { dst.SummaryVersion = src.SummaryVersion },
+	"summary_model":     func(dst, src *domain.Article) { dst.SummaryModel = src.SummaryModel },
+	"analysis_version":  func(dst, src *domain.Article) { dst.AnalysisVersion = src.AnalysisVersion },
+	"analysis_model":    func(dst, src *domain.Article) { dst.AnalysisModel = src.AnalysisModel },
+	"status":            func(dst, src *domain.Article) { dst.Status = src.Status },
+	"status_error":      func(dst, src *domain.Article) { dst.StatusError = src.StatusError },
+	"created_at":        func(dst, src *domain.Article) { dst.CreatedAt = src.CreatedAt },
+	"updated_at":        func(dst, src *domain.Article) { dst.UpdatedAt = src.UpdatedAt },
+}
+
+func (m *MemoryStore) ListAllArticles(ctx context.Context, opts domain.ArticleListOptions) ([]domain.Article, error) {
+	fields, err := selectFields(opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSort(opts.Sort); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	all := m.selected(nil, opts.Filter)
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if opts.Sort == "sentiment_score" && a.SentimentScore != b.SentimentScore {
+			return (a.SentimentScore > b.SentimentScore) == opts.Desc
+		}
+		if opts.Sort != "sentiment_score" && !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt) == opts.Desc
+		}
+		return a.ID < b.ID
+	})
+
+	out := []domain.Article{}
+	for i := opts.Offset; i < len(all) && (opts.Limit <= 0 || len(out) < opts.Limit); i++ {
+		var a domain.Article
+		for _, f := range fields {
+			articleFieldCopies[f](&a, all[i])
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) CountArticles(ctx context.Context, filter domain.ArticleFilter) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.selected(nil, filter)), nil
+}
+
+// UpsertArticle stores article by URL; an existing article keeps its ID, which is reported back
+func (m *MemoryStore) UpsertArticle(ctx context.Context, article *domain.Article) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	article.CreatedAt, article.UpdatedAt = now, now
+	if article.Status == "" {
+		article.Status = domain.ArticleEnriched
+	}
+	stored := *article
+	if existing, ok := m.articles[article.URL]; ok {
+		stored.ID, stored.CreatedAt = existing.ID, existing.CreatedAt
+		article.ID = existing.ID
+	}
+	if stored.ID == "" {
+		stored.ID = uuid.New().String()
+		article.ID = stored.ID
+	}
+	m.articles[article.URL] = &stored
+	return nil
+}
+
+// StreamArticles calls fn for every article matching filter, oldest first. Content is not set,
+// and Embedding only when withEmbeddings is true. An error from fn stops the scan and is returned.
+func (m *MemoryStore) StreamArticles(ctx context.Context, filter domain.ArticleFilter, withEmbeddings bool, fn func(domain.Article) error) error {
+	m.mu.RLock()
+	matching := m.selected(nil, filter)
+	out := make([]domain.Article, len(matching))
+	for i, a := range matching {
+		out[len(matching)-1-i] = listed(a)
+		if withEmbeddings {
+			out[len(matching)-1-i].Embedding = a.Embedding
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, a := range out {
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put stores article as-is, keeping its ID and timestamps; articles stored before statuses
+// were recorded are enriched
+func (m *MemoryStore) put(article domain.Article) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if article.Status == "" {
+		article.Status = domain.ArticleEnriched
+	}
+	m.articles[article.URL] = &article
+}
+
+// DeleteArticleByURL removes an article with its passages, rewrites and duplicates, or a
+// recorded duplicate URL; it reports whether anything was deleted
+func (m *MemoryStore) DeleteArticleByURL(ctx context.Context, url string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.articles[url]
+	if !ok {
+		if _, ok := m.duplicates[url]; ok {
+			delete(m.duplicates, url)
+			return true, nil
+		}
+		return false, nil
+	}
+	delete(m.articles, url)
+	delete(m.chunks, a.ID)
+	delete(m.simplifications, a.ID)
+	delete(m.tags, a.ID)
+	delete(m.notes, a.ID)
+	for _, set := range m.members {
+		delete(set, a.ID)
+	}
+	for u, d := range m.duplicates {
+		if d.article.CanonicalID == a.ID {
+			delete(m.duplicates, u)
+		}
+	}
+	return true, nil
+}
+
+// ---------- Search ----------
+
+func (m *MemoryStore) GetArticlesByVectorSearchWithFilter(ctx context.Context, queryEmbedding []float32, limit int, urls []string, filter domain.ArticleFilter) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.nearest(queryEmbedding, limit, m.selected(urls, filter)), nil
+}
+
+// nearest returns up to limit enriched candidates with embeddings, most similar to embedding first
+func (m *MemoryStore) nearest(embedding []float32, limit int, candidates []*domain.Article) []domain.Article {
+	type scored struct {
+		a   *domain.Article
+		sim float64
+	}
+	var ranked []scored
+	for _, a := range candidates {
+		if len(a.Embedding) > 0 && a.Status == domain.ArticleEnriched {
+			ranked = append(ranked, scored{a, cosineSimilarity(embedding, a.Embedding)})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].sim > ranked[j].sim })
+
+	var out []domain.Article
+	for i := 0; i < len(ranked) && i < limit; i++ {
+		out = append(out, listed(ranked[i].a))
+	}
+	return out
+}
+
+func (m *MemoryStore) GetArticlesByHybridSearch(ctx context.Context, queryEmbedding []float32, queryText string, limit, offset int, urls []string, filter domain.ArticleFilter, vectorWeight float64) ([]domain.Article, int, error) {
+	if vectorWeight < 0 || vectorWeight > 1 {
+		return nil, 0, fmt.Errorf("vector weight must be between 0 and 1, got %v", vectorWeight)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Embedding-only matches come from the nearest candidates, as in Postgres
+	selected := m.selected(urls, filter)
+	nearest := make(map[string]bool)
+	for _, a := range m.nearest(queryEmbedding, max(hybridCandidates, offset+limit), selected) {
+		nearest[a.URL] = true
+	}
+
+	var ranked []domain.Article
+	for _, a := range selected {
+		if a.Status != domain.ArticleEnriched {
+			continue
+		}
+		rank := textRank(a, queryText)
+		sim := cosineSimilarity(queryEmbedding, a.Embedding)
+		if rank == 0 && (!nearest[a.URL] || sim < m.HybridMinSimilarity) {
+			continue
+		}
+		out := listed(a)
+		out.Score = vectorWeight*sim + (1-vectorWeight)*rank
+		ranked = append(ranked, out)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	if offset >= len(ranked) {
+		return nil, len(ranked), nil
+	}
+	return ranked[offset:min(offset+limit, len(ranked))], len(ranked), nil
+}
+
+func (m *MemoryStore) GetArticlesBySentiment(ctx context.Context, topicEmbedding []float32, candidates int, urls []string, filter domain.ArticleFilter, sq domain.SentimentQuery) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var pool []*domain.Article
+	for _, a := range m.selected(urls, filter) {
+		if a.Status != domain.ArticleEnriched {
+			continue
+		}
+		if sq.MinScore != nil && a.SentimentScore < *sq.MinScore {
+			continue
+		}
+		if sq.MaxScore != nil && a.SentimentScore > *sq.MaxScore {
+			continue
+		}
+		pool = append(pool, a)
+	}
+
+	var ranked []domain.Article
+	if topicEmbedding != nil {
+		ranked = m.nearest(topicEmbedding, candidates, pool)
+	} else {
+		for _, a := range pool {
+			ranked = append(ranked, listed(a))
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].SentimentScore != ranked[j].SentimentScore {
+			return (ranked[i].SentimentScore < ranked[j].SentimentScore) == sq.Negative
+		}
+		return ranked[i].CreatedAt.After(ranked[j].CreatedAt)
+	})
+	if len(ranked) > sq.Limit {
+		ranked = ranked[:sq.Limit]
+	}
+	return ranked, nil
+}
+
+func (m *MemoryStore) GetArticlesByTone(ctx context.Context, tone string, urls []string, limit int) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.Article
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		if a.Tone != "" && containsFold(a.Tone, strings.TrimSpace(tone)) && len(out) < limit {
+			out = append(out, listed(a))
+		}
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) ReplaceArticleChunks(ctx context.Context, articleID string, chunks []string, embeddings [][]float32) error {
+	if len(chunks) != len(embeddings) {
+		return fmt.Errorf("got %d chunks but %d embeddings", len(chunks), len(embeddings))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := make([]memoryChunk, len(chunks))
+	for i := range chunks {
+		stored[i] = memoryChunk{text: chunks[i], embedding: embeddings[i]}
+	}
+	m.chunks[articleID] = stored
+	return nil
+}
+
+func (m *MemoryStore) SearchArticleChunks(ctx context.Context, queryEmbedding []float32, limit int, urls []string) ([]domain.ArticleChunk, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.ArticleChunk
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		for i, c := range m.chunks[a.ID] {
+			out = append(out, domain.ArticleChunk{
+				ArticleID: a.ID, URL: a.URL, Title: a.Title, Index: i, Text: c.text,
+				Similarity: cosineSimilarity(queryEmbedding, c.embedding),
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Similarity > out[j].Similarity })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// ---------- Corpus Analysis ----------
+
+func (m *MemoryStore) GetTopEntities(ctx context.Context, limit int, urls []string, category string) ([]domain.TopEntity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Spelling variants of a canonical name are counted together under the most common spelling
+	type ranked struct {
+		articles   map[string]bool
+		names      map[string]int
+		categories map[string]int
+		confidence float64
+		mentions   int
+	}
+	entities := make(map[string]*ranked)
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		for _, e := range a.Entities {
+			name := entityName(e)
+			key := entity.Key(name)
+			if key == "" {
+				continue
+			}
+			c := entityCategory(e)
+			if category != "" && c != category {
+				continue
+			}
+			ent, ok := entities[key]
+			if !ok {
+				ent = &ranked{articles: map[string]bool{}, names: map[string]int{}, categories: map[string]int{}}
+				entities[key] = ent
+			}
+			ent.articles[a.ID] = true
+			ent.names[name]++
+			ent.categories[c]++
+			ent.confidence += e.Confidence
+			ent.mentions++
+		}
+	}
+	result := make([]domain.TopEntity, 0, len(entities))
+	for _, ent := range entities {
+		result = append(result, newTopEntity(mostCommon(ent.names), mostCommon(ent.categories), len(ent.articles), ent.confidence/float64(ent.mentions)))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MemoryStore) GetKeywordsAndTopics(ctx context.Context, urls []string, limit int) ([]domain.TermAggregate, []domain.TermAggregate, error) {
+	if len(urls) == 0 {
+		return nil, nil, fmt.Errorf("no URLs provided")
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	kwAgg, tpAgg := newTermAggregator(), newTermAggregator()
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		for _, k := range a.Keywords {
+			kwAgg.add(k.Term, k.Relevance)
+		}
+		for _, t := range a.Topics {
+			tpAgg.add(t.Name, t.Score)
+		}
+		kwAgg.next()
+		tpAgg.next()
+	}
+	return kwAgg.top(limit), tpAgg.top(limit), nil
+}
+
+func (m *MemoryStore) GetArticleEmbeddings(ctx context.Context, limit int) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.Article
+	for _, a := range m.selected(nil, domain.ArticleFilter{}) {
+		if len(a.Embedding) > 0 && len(out) < limit {
+			out = append(out, domain.Article{ID: a.ID, URL: a.URL, Title: a.Title, Summary: a.Summary,
+				Embedding: a.Embedding, CreatedAt: a.CreatedAt})
+		}
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) GetEntityGraph(ctx context.Context, maxNodes, minShared int, urls []string) (*domain.EntityGraph, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	type node struct {
+		articles   map[string]bool
+		names      map[string]int
+		categories map[string]int
+	}
+	entities := make(map[string]*node)
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		for _, e := range a.Entities {
+			name := entityName(e)
+			key := entity.Key(name)
+			if key == "" {
+				continue
+			}
+			category := e.Category
+			if category == "" {
+				category = "other"
+			}
+			ent, ok := entities[key]
+			if !ok {
+				ent = &node{articles: map[string]bool{}, names: map[string]int{}, categories: map[string]int{}}
+				entities[key] = ent
+			}
+			ent.articles[a.ID] = true
+			ent.names[name]++
+			ent.categories[category]++
+		}
+	}
+
+	graph := &domain.EntityGraph{Nodes: []domain.EntityNode{}, Edges: []domain.EntityEdge{}}
+	for key, ent := range entities {
+		graph.Nodes = append(graph.Nodes, domain.EntityNode{
+			ID: key, Name: mostCommon(ent.names), Category: mostCommon(ent.categories), Articles: len(ent.articles),
+		})
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Articles != graph.Nodes[j].Articles {
+			return graph.Nodes[i].Articles > graph.Nodes[j].Articles
+		}
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	if len(graph.Nodes) > maxNodes {
+		graph.Nodes = graph.Nodes[:maxNodes]
+	}
+
+	for i, a := range graph.Nodes {
+		for _, b := range graph.Nodes[i+1:] {
+			shared := 0
+			for id := range entities[a.ID].articles {
+				if entities[b.ID].articles[id] {
+					shared++
+				}
+			}
+			if shared >= minShared {
+				source, target := a.ID, b.ID
+				if target < source {
+					source, target = target, source
+				}
+				graph.Edges = append(graph.Edges, domain.EntityEdge{Source: source, Target: target, Articles: shared})
+			}
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.Articles != b.Articles {
+			return a.Articles > b.Articles
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Target < b.Target
+	})
+	return graph, nil
+}
+
+// mentionsEntity reports whether a mentions the entity with folded name key
+func mentionsEntity(a *domain.Article, key string) bool {
+	for _, e := range a.Entities {
+		if entity.Key(entityName(e)) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryStore) GetEntityDetail(ctx context.Context, key, interval string, related int) (*domain.EntityDetail, error) {
+	if !trendIntervals[interval] {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	detail := &domain.EntityDetail{ID: key, CoOccurring: []domain.EntityNode{}, Timeline: []domain.SentimentBucket{}}
+	names, categories := map[string]int{}, map[string]int{}
+	type node struct {
+		articles   map[string]bool
+		names      map[string]int
+		categories map[string]int
+	}
+	others := make(map[string]*node)
+	buckets := make(map[time.Time]*domain.SentimentBucket)
+	for _, a := range m.selected(nil, domain.ArticleFilter{}) {
+		if !mentionsEntity(a, key) {
+			continue
+		}
+		for _, e := range a.Entities {
+			name := entityName(e)
+			k := entity.Key(name)
+			category := e.Category
+			if category == "" {
+				category = "other"
+			}
+			switch {
+			case k == key:
+				names[name]++
+				categories[category]++
+			case k != "":
+				o, ok := others[k]
+				if !ok {
+					o = &node{articles: map[string]bool{}, names: map[string]int{}, categories: map[string]int{}}
+					others[k] = o
+				}
+				o.articles[a.ID] = true
+				o.names[name]++
+				o.categories[category]++
+			}
+		}
+
+		at := a.CreatedAt
+		if a.PublishedAt != nil {
+			at = *a.PublishedAt
+		}
+		start := truncateTo(at, interval)
+		b, ok := buckets[start]
+		if !ok {
+			b = &domain.SentimentBucket{Start: start}
+			buckets[start] = b
+		}
+		b.Articles++
+		b.AverageScore += a.SentimentScore
+		detail.Articles++
+		detail.AverageScore += a.SentimentScore
+		switch a.Sentiment {
+		case "positive":
+			b.Positive++
+			detail.Positive++
+		case "negative":
+			b.Negative++
+			detail.Negative++
+		default:
+			b.Neutral++
+			detail.Neutral++
+		}
+	}
+	if detail.Articles == 0 {
+		return nil, nil
+	}
+	detail.AverageScore /= float64(detail.Articles)
+	detail.Name, detail.Category = mostCommon(names), mostCommon(categories)
+
+	for k, o := range others {
+		detail.CoOccurring = append(detail.CoOccurring, domain.EntityNode{
+			ID: k, Name: mostCommon(o.names), Category: mostCommon(o.categories), Articles: len(o.articles),
+		})
+	}
+	sort.Slice(detail.CoOccurring, func(i, j int) bool {
+		a, b := detail.CoOccurring[i], detail.CoOccurring[j]
+		if a.Articles != b.Articles {
+			return a.Articles > b.Articles
+		}
+		return a.ID < b.ID
+	})
+	if len(detail.CoOccurring) > related {
+		detail.CoOccurring = detail.CoOccurring[:related]
+	}
+
+	for _, b := range buckets {
+		b.AverageScore /= float64(b.Articles)
+		detail.Timeline = append(detail.Timeline, *b)
+	}
+	sort.Slice(detail.Timeline, func(i, j int) bool { return detail.Timeline[i].Start.Before(detail.Timeline[j].Start) })
+	return detail, nil
+}
+
+func (m *MemoryStore) ListEntityArticles(ctx context.Context, key string, limit, offset int) ([]domain.ArticleListItem, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matching []*domain.Article
+	for _, a := range m.selected(nil, domain.ArticleFilter{}) {
+		if mentionsEntity(a, key) {
+			matching = append(matching, a)
+		}
+	}
+	publishedAt := func(a *domain.Article) time.Time {
+		if a.PublishedAt != nil {
+			return *a.PublishedAt
+		}
+		return a.CreatedAt
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if ti, tj := publishedAt(matching[i]), publishedAt(matching[j]); !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return matching[i].ID < matching[j].ID
+	})
+
+	items := []domain.ArticleListItem{}
+	for i := offset; i < len(matching) && len(items) < limit; i++ {
+		a := matching[i]
+		items = append(items, domain.ArticleListItem{
+			ID: a.ID, URL: a.URL, Title: a.Title, Sentiment: a.Sentiment, SentimentScore: a.SentimentScore,
+			Author: a.Author, Section: a.Section, PublishedAt: a.PublishedAt, CreatedAt: a.CreatedAt,
+		})
+	}
+	return items, len(matching), nil
+}
+
+// entityName is an entity's canonical name, or its raw name from before normalization
+func entityName(e domain.SemanticEntity) string {
+	if name := strings.TrimSpace(e.CanonicalName); name != "" {
+		return name
+	}
+	return strings.TrimSpace(e.Name)
+}
+
+// entityCategory is an entity's lowercase category, "other" when none was extracted, like
+// entity_mentions in init.sql
+func entityCategory(e domain.SemanticEntity) string {
+	if c := strings.ToLower(strings.TrimSpace(e.Category)); c != "" {
+		return c
+	}
+	return "other"
+}
+
+// newTopEntity ranks an entity by its article count weighted by its mean confidence
+func newTopEntity(name, category string, articles int, confidence float64) domain.TopEntity {
+	return domain.TopEntity{Name: name, Category: category, Count: articles, AvgConfidence: confidence, Score: float64(articles) * confidence}
+}
+
+// mostCommon returns the most frequent key, the smallest on ties, like Postgres mode()
+func mostCommon(counts map[string]int) string {
+	best, bestCount := "", 0
+	for k, n := range counts {
+		if n > bestCount || (n == bestCount && k < best) {
+			best, bestCount = k, n
+		}
+	}
+	return best
+}
+
+func (m *MemoryStore) GetSentimentTrend(ctx context.Context, topic string, urls []string, since time.Time, interval string) ([]domain.SentimentBucket, error) {
+	if !trendIntervals[interval] {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	buckets := make(map[time.Time]*domain.SentimentBucket)
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		at := a.CreatedAt
+		if a.PublishedAt != nil {
+			at = *a.PublishedAt
+		}
+		if at.Before(since) || (topic != "" && textRank(a, topic) == 0) {
+			continue
+		}
+		start := truncateTo(at, interval)
+		b, ok := buckets[start]
+		if !ok {
+			b = &domain.SentimentBucket{Start: start}
+			buckets[start] = b
+		}
+		b.Articles++
+		b.AverageScore += a.SentimentScore
+		switch a.Sentiment {
+		case "positive":
+			b.Positive++
+		case "negative":
+			b.Negative++
+		default:
+			b.Neutral++
+		}
+	}
+
+	var out []domain.SentimentBucket
+	for _, b := range buckets {
+		b.AverageScore /= float64(b.Articles)
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out, nil
+}
+
+// truncateTo mirrors date_trunc for "day" and "week" (weeks start on Monday)
+func truncateTo(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if interval == "week" {
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+func (m *MemoryStore) GetToneCounts(ctx context.Context, urls []string) ([]domain.ToneCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, a := range m.selected(urls, domain.ArticleFilter{}) {
+		if t := strings.ToLower(strings.TrimSpace(a.Tone)); t != "" {
+			counts[t]++
+		}
+	}
+	var out []domain.ToneCount
+	for t, n := range counts {
+		out = append(out, domain.ToneCount{Tone: t, Articles: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Articles != out[j].Articles {
+			return out[i].Articles > out[j].Articles
+		}
+		return out[i].Tone < out[j].Tone
+	})
+	return out, nil
+}
+
+// ---------- Refresh and Duplicates ----------
+
+func (m *MemoryStore) GetStaleArticles(ctx context.Context, cutoff time.Time, limit int) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	checkedAt := func(a *domain.Article) time.Time {
+		if a.LastRefreshedAt != nil {
+			return *a.LastRefreshedAt
+		}
+		return a.CreatedAt
+	}
+	var stale []*domain.Article
+	for _, a := range m.articles {
+		if !a.Imported && a.Status == domain.ArticleEnriched && checkedAt(a).Before(cutoff) {
+			stale = append(stale, a)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return checkedAt(stale[i]).Before(checkedAt(stale[j])) })
+
+	var out []domain.Article
+	for i := 0; i < len(stale) && i < limit; i++ {
+		a := listed(stale[i])
+		a.Content, a.ContentHash, a.ETag = stale[i].Content, stale[i].ContentHash, stale[i].ETag
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) MarkArticleRefreshed(ctx context.Context, url, contentHash, etag string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.articles[url]
+	if !ok {
+		return nil
+	}
+	a.LastRefreshedAt = &at
+	if contentHash != "" {
+		a.ContentHash = contentHash
+	}
+	if etag != "" {
+		a.ETag = etag
+	}
+	return nil
+}
+
+func (m *MemoryStore) SetArticleStatus(ctx context.Context, url, status, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a, ok := m.articles[url]; ok {
+		a.Status, a.StatusError, a.UpdatedAt = status, reason, time.Now()
+	}
+	return nil
+}
+
+func (m *MemoryStore) FindNearDuplicate(ctx context.Context, embedding []float32, excludeURL string, minSimilarity float64) (*domain.Article, float64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var best *domain.Article
+	bestSim := math.Inf(-1)
+	for _, a := range m.articles {
+		if a.URL == excludeURL || len(a.Embedding) == 0 || a.Status != domain.ArticleEnriched {
+			continue
+		}
+		if sim := cosineSimilarity(embedding, a.Embedding); sim > bestSim {
+			best, bestSim = a, sim
+		}
+	}
+	if best == nil {
+		return nil, 0, nil
+	}
+	if bestSim < minSimilarity {
+		return nil, bestSim, nil
+	}
+	out := listed(best)
+	return &out, bestSim, nil
+}
+
+func (m *MemoryStore) GetArticleByContentHash(ctx context.Context, contentHash, excludeURL string) (*domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var first *domain.Article
+	for _, a := range m.articles {
+		if a.ContentHash == contentHash && a.URL != excludeURL && a.Status == domain.ArticleEnriched && (first == nil || a.CreatedAt.Before(first.CreatedAt)) {
+			first = a
+		}
+	}
+	if first == nil {
+		return nil, nil
+	}
+	out := listed(first)
+	return &out, nil
+}
+
+func (m *MemoryStore) AddDuplicate(ctx context.Context, dup *domain.Article, similarity float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.New().String()
+	if existing, ok := m.duplicates[dup.URL]; ok {
+		id = existing.article.ID
+	}
+	m.duplicates[dup.URL] = &memoryDuplicate{
+		article:    domain.Article{ID: id, URL: dup.URL, Title: dup.Title, CanonicalID: dup.CanonicalID, CreatedAt: time.Now()},
+		similarity: similarity,
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetDuplicateByURL(ctx context.Context, url string) (*domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.duplicates[url]
+	if !ok {
+		return nil, nil
+	}
+	out := d.article
+	return &out, nil
+}
+
+// ---------- Embedding Migration ----------
+
+func (m *MemoryStore) CountEmbeddingModels(ctx context.Context) ([]domain.EmbeddingModelCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, a := range m.articles {
+		counts[a.EmbeddingModel]++
+	}
+	out := make([]domain.EmbeddingModelCount, 0, len(counts))
+	for model, n := range counts {
+		out = append(out, domain.EmbeddingModelCount{Model: model, Articles: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Articles != out[j].Articles {
+			return out[i].Articles > out[j].Articles
+		}
+		return out[i].Model < out[j].Model
+	})
+	return out, nil
+}
+
+func (m *MemoryStore) ListArticlesToReembed(ctx context.Context, model, afterID string, limit int) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.Article
+	for _, a := range m.articles {
+		if a.EmbeddingModel != model && a.ID > afterID {
+			out = append(out, domain.Article{ID: a.ID, URL: a.URL, Title: a.Title, Summary: a.Summary, Content: a.Content, EmbeddingModel: a.EmbeddingModel})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) SetArticleEmbedding(ctx context.Context, id string, embedding []float32, model string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a := m.articleByID(id); a != nil {
+		a.Embedding, a.EmbeddingModel = embedding, model
+	}
+	return nil
+}
+
+// ---------- Re-analysis ----------
+
+func (m *MemoryStore) CountAnalysisVersions(ctx context.Context) ([]domain.AnalysisVersionCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, a := range m.articles {
+		if a.Status == domain.ArticleEnriched {
+			counts[a.AnalysisVersion]++
+		}
+	}
+	out := make([]domain.AnalysisVersionCount, 0, len(counts))
+	for version, n := range counts {
+		out = append(out, domain.AnalysisVersionCount{Version: version, Articles: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Articles != out[j].Articles {
+			return out[i].Articles > out[j].Articles
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out, nil
+}
+
+func (m *MemoryStore) ListArticlesToReanalyze(ctx context.Context, version, afterID string, limit int) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.Article
+	for _, a := range m.articles {
+		if a.Status == domain.ArticleEnriched && a.AnalysisVersion != version && a.AnalysisVersion != domain.AnalysisSupplied && a.ID > afterID {
+			out = append(out, domain.Article{ID: a.ID, URL: a.URL, Title: a.Title, Summary: a.Summary, AnalysisVersion: a.AnalysisVersion})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) SetArticleAnalysis(ctx context.Context, a *domain.Article) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored := m.articleByID(a.ID); stored != nil {
+		setAnalysis(stored, a)
+	}
+	return nil
+}
+
+// setAnalysis copies the semantic fields of src to dst
+func setAnalysis(dst, src *domain.Article) {
+	dst.Entities, dst.Keywords, dst.Topics, dst.TopicIDs = src.Entities, src.Keywords, src.Topics, src.TopicIDs
+	dst.Sentiment, dst.SentimentScore, dst.Tone = src.Sentiment, src.SentimentScore, src.Tone
+	dst.AnalysisVersion, dst.AnalysisModel, dst.UpdatedAt = src.AnalysisVersion, src.AnalysisModel, time.Now()
+}
+
+// articleByID returns the stored article with id, or nil; the caller must hold mu
+func (m *MemoryStore) articleByID(id string) *domain.Article {
+	for _, a := range m.articles {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// ---------- Reading-Level Rewrites ----------
+
+func (m *MemoryStore) GetSimplifiedSummary(ctx context.Context, articleID, level string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.simplifications[articleID][level], nil
+}
+
+func (m *MemoryStore) SetSimplifiedSummary(ctx context.Context, articleID, level, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.simplifications[articleID] == nil {
+		m.simplifications[articleID] = make(map[string]string)
+	}
+	m.simplifications[articleID][level] = text
+	return nil
+}
+
+func (m *MemoryStore) ClearSimplifiedSummaries(ctx context.Context, url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a, ok := m.articles[url]; ok {
+		delete(m.simplifications, a.ID)
+	}
+	return nil
+}
+
+// ---------- Topic taxonomy ----------
+
+// putTaxonomyTopic stores a copy of topic as is
+func (m *MemoryStore) putTaxonomyTopic(topic domain.TaxonomyTopic) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topics[topic.ID] = &topic
+}
+
+func (m *MemoryStore) CreateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if topic.ID == "" {
+		topic.ID = uuid.New().String()
+	}
+	if _, ok := m.topics[topic.ID]; ok {
+		return ErrTopicExists
+	}
+	now := time.Now()
+	topic.CreatedAt, topic.UpdatedAt = now, now
+	stored := *topic
+	m.topics[topic.ID] = &stored
+	return nil
+}
+
+// ListTaxonomyTopics returns every taxonomy topic, by name
+func (m *MemoryStore) ListTaxonomyTopics(ctx context.Context) ([]domain.TaxonomyTopic, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]domain.TaxonomyTopic, 0, len(m.topics))
+	for _, t := range m.topics {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (m *MemoryStore) GetTaxonomyTopic(ctx context.Context, id string) (*domain.TaxonomyTopic, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.topics[id]
+	if !ok {
+		return nil, nil
+	}
+	out := *t
+	return &out, nil
+}
+
+func (m *MemoryStore) UpdateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.topics[topic.ID]
+	if !ok {
+		return false, nil
+	}
+	topic.CreatedAt, topic.UpdatedAt = existing.CreatedAt, time.Now()
+	stored := *topic
+	m.topics[topic.ID] = &stored
+	return true, nil
+}
+
+// DeleteTaxonomyTopic removes a taxonomy topic and its ID from articles, and reports whether it
+// existed. Its subtopics become top-level topics.
+func (m *MemoryStore) DeleteTaxonomyTopic(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.topics[id]; !ok {
+		return false, nil
+	}
+	delete(m.topics, id)
+	for _, t := range m.topics {
+		if t.ParentID == id {
+			t.ParentID = ""
+		}
+	}
+	for _, a := range m.articles {
+		if !hasTopicID(a, map[string]bool{id: true}) {
+			continue
+		}
+		kept := make([]string, 0, len(a.TopicIDs)-1)
+		for _, t := range a.TopicIDs {
+			if t != id {
+				kept = append(kept, t)
+			}
+		}
+		a.TopicIDs = kept
+	}
+	return true, nil
+}
+
+// ---------- Tags and notes ----------
+
+// AddArticleTags adds tags, as returned by domain.NormalizeTag, to an article; tags it already
+// has are kept
+func (m *MemoryStore) AddArticleTags(ctx context.Context, articleID string, tags []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	set := m.tags[articleID]
+	if set == nil {
+		set = make(map[string]bool, len(tags))
+		m.tags[articleID] = set
+	}
+	for _, t := range tags {
+		set[t] = true
+	}
+	return nil
+}
+
+// RemoveArticleTag removes a tag from an article and reports whether the article had it
+func (m *MemoryStore) RemoveArticleTag(ctx context.Context, articleID, tag string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.tags[articleID][tag] {
+		return false, nil
+	}
+	delete(m.tags[articleID], tag)
+	if len(m.tags[articleID]) == 0 {
+		delete(m.tags, articleID)
+	}
+	return true, nil
+}
+
+// GetArticleTags returns an article's tags in alphabetical order
+func (m *MemoryStore) GetArticleTags(ctx context.Context, articleID string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []string
+	for t := range m.tags[articleID] {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ListTags returns every tag in use with its article count, most used first
+func (m *MemoryStore) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, set := range m.tags {
+		for t := range set {
+			counts[t]++
+		}
+	}
+	out := make([]domain.TagCount, 0, len(counts))
+	for t, n := range counts {
+		out = append(out, domain.TagCount{Tag: t, Articles: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Articles != out[j].Articles {
+			return out[i].Articles > out[j].Articles
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out, nil
+}
+
+// putArticleNote appends a copy of note as is
+func (m *MemoryStore) putArticleNote(note domain.ArticleNote) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notes[note.ArticleID] = append(m.notes[note.ArticleID], note)
+}
+
+// AddArticleNote stores note and sets its ID and creation time
+func (m *MemoryStore) AddArticleNote(ctx context.Context, note *domain.ArticleNote) error {
+	note.ID, note.CreatedAt = uuid.New().String(), time.Now()
+	m.putArticleNote(*note)
+	return nil
+}
+
+// ListArticleNotes returns an article's notes, oldest first
+func (m *MemoryStore) ListArticleNotes(ctx context.Context, articleID string) ([]domain.ArticleNote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]domain.ArticleNote(nil), m.notes[articleID]...), nil
+}
+
+// DeleteArticleNote removes one of an article's notes and reports whether it existed
+func (m *MemoryStore) DeleteArticleNote(ctx context.Context, articleID, noteID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	notes := m.notes[articleID]
+	for i, n := range notes {
+		if n.ID == noteID {
+			m.notes[articleID] = append(notes[:i:i], notes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ---------- Collections ----------
+
+// collectionNamed returns the collection with ID key, or else named key in any case; callers
+// hold m.mu
+func (m *MemoryStore) collectionNamed(key string) *domain.Collection {
+	if c, ok := m.collections[key]; ok {
+		return c
+	}
+	for _, c := range m.collections {
+		if strings.EqualFold(c.Name, key) {
+			return c
+		}
+	}
+	return nil
+}
+
+// nameTaken reports whether a collection other than id is named name in any case; callers hold
+// m.mu
+func (m *MemoryStore) nameTaken(name, id string) bool {
+	for _, c := range m.collections {
+		if c.ID != id && strings.EqualFold(c.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectionCopy returns a copy of c with its article count; callers hold m.mu
+func (m *MemoryStore) collectionCopy(c *domain.Collection) domain.Collection {
+	out := *c
+	out.Articles = len(m.members[c.ID])
+	return out
+}
+
+// putCollection stores a copy of collection as is
+func (m *MemoryStore) putCollection(collection domain.Collection) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collections[collection.ID] = &collection
+}
+
+func (m *MemoryStore) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.nameTaken(collection.Name, "") {
+		return ErrCollectionExists
+	}
+	now := time.Now()
+	collection.ID, collection.Articles = uuid.New().String(), 0
+	collection.CreatedAt, collection.UpdatedAt = now, now
+	stored := *collection
+	m.collections[collection.ID] = &stored
+	return nil
+}
+
+// ListCollections returns every collection with its article count, by name
+func (m *MemoryStore) ListCollections(ctx context.Context) ([]domain.Collection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]domain.Collection, 0, len(m.collections))
+	for _, c := range m.collections {
+		out = append(out, m.collectionCopy(c))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out, nil
+}
+
+func (m *MemoryStore) GetCollection(ctx context.Context, id string) (*domain.Collection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.collections[id]
+	if !ok {
+		return nil, nil
+	}
+	out := m.collectionCopy(c)
+	return &out, nil
+}
+
+func (m *MemoryStore) UpdateCollection(ctx context.Context, collection *domain.Collection) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.collections[collection.ID]
+	if !ok {
+		return false, nil
+	}
+	if m.nameTaken(collection.Name, collection.ID) {
+		return false, ErrCollectionExists
+	}
+	existing.Name, existing.Description, existing.UpdatedAt = collection.Name, collection.Description, time.Now()
+	*collection = m.collectionCopy(existing)
+	return true, nil
+}
+
+// DeleteCollection removes a collection, but not its articles, and reports whether it existed
+func (m *MemoryStore) DeleteCollection(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.collections[id]; !ok {
+		return false, nil
+	}
+	delete(m.collections, id)
+	delete(m.members, id)
+	return true, nil
+}
+
+// AddCollectionArticles adds articles to a collection; articles already in it are kept
+func (m *MemoryStore) AddCollectionArticles(ctx context.Context, collectionID string, articleIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	set := m.members[collectionID]
+	if set == nil {
+		set = make(map[string]bool, len(articleIDs))
+		m.members[collectionID] = set
+	}
+	for _, id := range articleIDs {
+		set[id] = true
+	}
+	return nil
+}
+
+// RemoveCollectionArticle removes an article from a collection and reports whether it was in it
+func (m *MemoryStore) RemoveCollectionArticle(ctx context.Context, collectionID, articleID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.members[collectionID][articleID] {
+		return false, nil
+	}
+	delete(m.members[collectionID], articleID)
+	return true, nil
+}
+
+// ---------- Saved searches and alerts ----------
+
+// putSavedSearch stores a copy of search as is
+func (m *MemoryStore) putSavedSearch(search domain.SavedSearch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.searches[search.ID] = &search
+}
+
+// hasAlert reports whether articleID already matched searchID; callers hold m.mu
+func (m *MemoryStore) hasAlert(searchID, articleID string) bool {
+	for _, a := range m.alerts {
+		if a.SearchID == searchID && a.ArticleID == articleID {
+			return true
+		}
+	}
+	return false
+}
+
+// putAlert stores alert as is unless its article already matched the search
+func (m *MemoryStore) putAlert(alert domain.Alert) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hasAlert(alert.SearchID, alert.ArticleID) {
+		return false
+	}
+	m.alerts = append(m.alerts, alert)
+	return true
+}
+
+func (m *MemoryStore) CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) error {
+	now := time.Now()
+	search.ID, search.CreatedAt, search.CheckedAt = uuid.New().String(), now, now
+	m.putSavedSearch(*search)
+	return nil
+}
+
+// ListSavedSearches returns every saved search, oldest first
+func (m *MemoryStore) ListSavedSearches(ctx context.Context) ([]domain.SavedSearch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]domain.SavedSearch, 0, len(m.searches))
+	for _, s := range m.searches {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// DeleteSavedSearch removes a saved search with its alerts and reports whether it existed
+func (m *MemoryStore) DeleteSavedSearch(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.searches[id]; !ok {
+		return false, nil
+	}
+	delete(m.searches, id)
+	kept := m.alerts[:0]
+	for _, a := range m.alerts {
+		if a.SearchID != id {
+			kept = append(kept, a)
+		}
+	}
+	m.alerts = kept
+	return true, nil
+}
+
+func (m *MemoryStore) MarkSavedSearchChecked(ctx context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.searches[id]; ok {
+		s.CheckedAt = at
+	}
+	return nil
+}
+
+// AddAlert records a match and sets its ID and creation time; it reports false when the
+// article already matched the search
+func (m *MemoryStore) AddAlert(ctx context.Context, alert *domain.Alert) (bool, error) {
+	stored := *alert
+	stored.ID, stored.CreatedAt = uuid.New().String(), time.Now()
+	if !m.putAlert(stored) {
+		return false, nil
+	}
+	alert.ID, alert.CreatedAt = stored.ID, stored.CreatedAt
+	return true, nil
+}
+
+// ListAlerts returns up to limit alerts, newest first, for one saved search or all when
+// searchID is empty
+func (m *MemoryStore) ListAlerts(ctx context.Context, searchID string, limit int) ([]domain.Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.Alert
+	for i := len(m.alerts) - 1; i >= 0 && len(out) < limit; i-- {
+		if searchID == "" || m.alerts[i].SearchID == searchID {
+			out = append(out, m.alerts[i])
+		}
+	}
+	return out, nil
+}
+
+// ---------- Ingest failures ----------
+
+// putIngestFailure stores a copy of failure as is
+func (m *MemoryStore) putIngestFailure(failure domain.IngestFailure) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[failure.URL] = &failure
+}
+
+// mergeIngestFailure returns failure added to the URL's existing record, or as a new record;
+// callers hold m.mu
+func (m *MemoryStore) mergeIngestFailure(failure domain.IngestFailure) domain.IngestFailure {
+	now := time.Now()
+	failure.Failures, failure.LastFailedAt = 1, now
+	if existing, ok := m.failures[failure.URL]; ok {
+		failure.ID, failure.FirstFailedAt = existing.ID, existing.FirstFailedAt
+		failure.Attempts += existing.Attempts
+		failure.Failures += existing.Failures
+	} else {
+		failure.ID, failure.FirstFailedAt = uuid.New().String(), now
+	}
+	return failure
+}
+
+// RecordIngestFailure stores a failed ingest, or adds it to the URL's existing record, and
+// sets failure to the stored record
+func (m *MemoryStore) RecordIngestFailure(ctx context.Context, failure *domain.IngestFailure) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := m.mergeIngestFailure(*failure)
+	m.failures[stored.URL] = &stored
+	*failure = stored
+	return nil
+}
+
+// ListIngestFailures returns up to limit failures, most recently failed first, with the given
+// error category or all when category is empty
+func (m *MemoryStore) ListIngestFailures(ctx context.Context, category string, limit int) ([]domain.IngestFailure, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []domain.IngestFailure
+	for _, f := range m.failures {
+		if category == "" || f.ErrorCategory == category {
+			out = append(out, *f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].LastFailedAt.Equal(out[j].LastFailedAt) {
+			return out[i].LastFailedAt.After(out[j].LastFailedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// GetIngestFailure returns nil without error when no failure has the ID
+func (m *MemoryStore) GetIngestFailure(ctx context.Context, id string) (*domain.IngestFailure, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, f := range m.failures {
+		if f.ID == id {
+			out := *f
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+// ResolveIngestFailure removes the failure recorded for url and reports whether there was one
+func (m *MemoryStore) ResolveIngestFailure(ctx context.Context, url string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.failures[url]; !ok {
+		return false, nil
+	}
+	delete(m.failures, url)
+	return true, nil
+}
+
+// ---------- Pending Enrichment ----------
+
+func (m *MemoryStore) SavePendingArticle(ctx context.Context, pending *domain.PendingArticle) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending[pending.URL] = *pending
+	return nil
+}
+
+func (m *MemoryStore) GetPendingArticle(ctx context.Context, url string) (*domain.PendingArticle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.pending[url]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (m *MemoryStore) DeletePendingArticle(ctx context.Context, url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, url)
+	return nil
+}
+
+// ListPendingArticleURLs returns the pending URLs, oldest fetch first
+func (m *MemoryStore) ListPendingArticleURLs(ctx context.Context) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pending := make([]domain.PendingArticle, 0, len(m.pending))
+	for _, p := range m.pending {
+		pending = append(pending, p)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].FetchedAt.Equal(pending[j].FetchedAt) {
+			return pending[i].FetchedAt.Before(pending[j].FetchedAt)
+		}
+		return pending[i].URL < pending[j].URL
+	})
+	urls := make([]string, len(pending))
+	for i, p := range pending {
+		urls[i] = p.URL
+	}
+	return urls, nil
+}
+
+// ---------- Chat Cache ----------
+
+// GetChatCache returns an unexpired entry; like Repo, request and response are decoded from JSON
+func (m *MemoryStore) GetChatCache(ctx context.Context, requestHash string) (*domain.ChatCache, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.chatCache[requestHash]
+	if !ok || !entry.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	out := *entry
+	return &out, nil
+}
+
+func (m *MemoryStore) SetChatCache(ctx context.Context, requestHash string, request, response interface{}, ttl time.Duration) error {
+	var entry domain.ChatCache
+	for _, field := range []struct {
+		value interface{}
+		dest  *interface{}
+	}{{request, &entry.RequestJSON}, {response, &entry.ResponseJSON}} {
+		data, err := json.Marshal(field.value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cache entry: %w", err)
+		}
+		if err := json.Unmarshal(data, field.dest); err != nil {
+			return fmt.Errorf("failed to decode cache entry: %w", err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	entry.ID, entry.RequestHash, entry.CreatedAt, entry.ExpiresAt = uuid.New().String(), requestHash, now, now.Add(ttl)
+	if existing, ok := m.chatCache[requestHash]; ok {
+		entry.ID, entry.CreatedAt = existing.ID, existing.CreatedAt
+	}
+	m.chatCache[requestHash] = &entry
+	return nil
+}
+
+func (m *MemoryStore) CleanExpiredChatCache(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, entry := range m.chatCache {
+		if entry.ExpiresAt.Before(now) {
+			delete(m.chatCache, k)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) ClearChatCache(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k := range m.chatCache {
+		if strings.HasPrefix(k, prefix) {
+			delete(m.chatCache, k)
+		}
+	}
+	return nil
+}
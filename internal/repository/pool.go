@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PoolConfig sizes the Postgres connection pool
+type PoolConfig struct {
+	MaxOpenConns    int           // Open connections at most; 0 is unlimited
+	MaxIdleConns    int           // Idle connections kept for reuse; 0 keeps none
+	ConnMaxLifetime time.Duration // Connections are replaced after this long; 0 keeps them
+	ConnMaxIdleTime time.Duration // Idle connections are closed after this long; 0 keeps them
+}
+
+// DefaultPoolConfig returns the pool settings used when none are configured. database/sql's
+// own defaults keep only 2 idle connections, so a burst of requests keeps reconnecting and
+// re-preparing statements.
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+}
+
+// Apply sets the pool limits on db
+func (p PoolConfig) Apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+}
@@ -4,37 +4,147 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-type Repo struct{ DB *sql.DB }
+type Repo struct {
+	DB *sql.DB
+
+	// DisablePrepare runs hot-path queries unprepared, for poolers such as PgBouncer in
+	// transaction mode that do not keep prepared statements
+	DisablePrepare bool
+
+	// VectorIndex is how ResizeEmbeddings and Reindex build the embedding indexes
+	VectorIndex VectorIndexConfig
+
+	// HybridMinSimilarity is the cosine similarity from which an article matches a hybrid
+	// search without matching its terms
+	HybridMinSimilarity float64
+
+	stmts sync.Map // Query text -> *sql.Stmt, see query
+}
+
+func NewRepo(db *sql.DB) *Repo {
+	return &Repo{DB: db, VectorIndex: DefaultVectorIndexConfig(), HybridMinSimilarity: DefaultHybridMinSimilarity}
+}
+
+// query runs a hot-path query through a cached prepared statement, so Postgres parses it once
+// per connection rather than on every request. Query texts only vary with the filters used,
+// which keeps the cache small.
+func (r *Repo) query(ctx context.Context, q string, args ...interface{}) (*sql.Rows, error) {
+	if r.DisablePrepare {
+		return r.DB.QueryContext(ctx, q, args...)
+	}
+	stmt, err := r.prepared(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// queryRow is query for a single row
+func (r *Repo) queryRow(ctx context.Context, q string, args ...interface{}) *sql.Row {
+	if r.DisablePrepare {
+		return r.DB.QueryRowContext(ctx, q, args...)
+	}
+	stmt, err := r.prepared(ctx, q)
+	if err != nil {
+		// Preparing failed, e.g. on a dropped connection; the unprepared query reports the error
+		return r.DB.QueryRowContext(ctx, q, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// prepared returns the cached statement for q, preparing it on first use
+func (r *Repo) prepared(ctx context.Context, q string) (*sql.Stmt, error) {
+	if stmt, ok := r.stmts.Load(q); ok {
+		return stmt.(*sql.Stmt), nil
+	}
+	stmt, err := r.DB.PrepareContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if existing, loaded := r.stmts.LoadOrStore(q, stmt); loaded {
+		stmt.Close()
+		return existing.(*sql.Stmt), nil
+	}
+	return stmt, nil
+}
 
-func NewRepo(db *sql.DB) *Repo { return &Repo{DB: db} }
+// Close releases the prepared statements; the database itself is closed by its owner
+func (r *Repo) Close() error {
+	r.stmts.Range(func(q, stmt interface{}) bool {
+		stmt.(*sql.Stmt).Close()
+		r.stmts.Delete(q)
+		return true
+	})
+	return nil
+}
 
 // ---------- Helpers ----------
 
-// applyURLFilter adds url filtering if urls provided
+// parseEmbedding parses a pgvector string like "[0.1,0.2,0.3]"
+func parseEmbedding(s string) []float32 {
+	s = strings.Trim(s, "[]")
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]float32, len(parts))
+	for i, part := range parts {
+		if val, err := strconv.ParseFloat(strings.TrimSpace(part), 32); err == nil {
+			out[i] = float32(val)
+		}
+	}
+	return out
+}
+
+// traceQuery starts a span for a repository query. The returned finish func ends it and logs the
+// row count and duration at debug level, tagged with the request ID.
+func traceQuery(ctx context.Context, name string) (context.Context, func(rows int, err error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "db."+name,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation.name", name),
+	)
+	return ctx, func(rows int, err error) {
+		span.SetAttributes(attribute.Int("db.rows", rows))
+		tracing.End(span, err)
+		logging.FromContext(ctx).Debug("db query", "query", name, "rows", rows, "duration_ms", time.Since(start).Milliseconds(), "error", err)
+	}
+}
+
+// applyURLFilter adds url filtering if urls provided. The URLs are one array parameter, so the
+// query text does not depend on how many there are.
 func applyURLFilter(query string, urls []string, args []interface{}) (string, []interface{}) {
 	if len(urls) == 0 {
 		return query, args
 	}
-	placeholders := make([]string, len(urls))
-	startIndex := len(args) + 1
-	for i, u := range urls {
-		placeholders[i] = fmt.Sprintf("$%d", startIndex+i)
-		args = append(args, u)
-	}
-	query += fmt.Sprintf(" AND url IN (%s)", strings.Join(placeholders, ","))
+	args = append(args, pq.Array(urls))
+	query += fmt.Sprintf(" AND url = ANY($%d)", len(args))
 	return query, args
 }
 
-// parseJSONFields parses entities/keywords/topics JSON
-func parseJSONFields(a *domain.Article, entitiesJSON, keywordsJSON, topicsJSON []byte) {
+// nullString maps empty strings to SQL NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// parseJSONFields parses entities/keywords/topics/topic_ids JSON
+func parseJSONFields(a *domain.Article, entitiesJSON, keywordsJSON, topicsJSON, topicIDsJSON []byte) {
 	if len(entitiesJSON) > 0 {
 		_ = json.Unmarshal(entitiesJSON, &a.Entities)
 	}
@@ -44,49 +154,132 @@ func parseJSONFields(a *domain.Article, entitiesJSON, keywordsJSON, topicsJSON [
 	if len(topicsJSON) > 0 {
 		_ = json.Unmarshal(topicsJSON, &a.Topics)
 	}
+	if len(topicIDsJSON) > 0 {
+		_ = json.Unmarshal(topicIDsJSON, &a.TopicIDs)
+	}
 }
 
-// GetArticleByURL retrieves an article by URL, including URL hash
-func (r *Repo) GetArticleByURL(ctx context.Context, url string) (*domain.Article, error) {
-	query := `SELECT id, url, title, summary, embedding, sentiment, sentiment_score, tone, 
-	          entities, keywords, topics, url_hash, created_at, updated_at
-	          FROM articles WHERE url = $1`
+// articleColumns is the column list read by scanArticle
+const articleColumns = `id, url, title, summary, sentiment, sentiment_score, tone, entities, keywords, topics, topic_ids,
+	COALESCE(author, ''), COALESCE(section, ''), published_at, COALESCE(source_domain, ''), COALESCE(language, ''), last_refreshed_at, COALESCE(embedding_model, ''),
+	COALESCE(summary_version, ''), COALESCE(summary_model, ''), COALESCE(analysis_version, ''), COALESCE(analysis_model, ''),
+	status, COALESCE(status_error, ''), created_at, updated_at`
 
-	row := r.DB.QueryRowContext(ctx, query, url)
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanArticle scans a row selected with articleColumns, followed by any extra columns
+func scanArticle(row rowScanner, extra ...interface{}) (domain.Article, error) {
 	var a domain.Article
-	var entitiesJSON, keywordsJSON, topicsJSON []byte
-	var embeddingStr string
+	var entitiesJSON, keywordsJSON, topicsJSON, topicIDsJSON []byte
+	var publishedAt, lastRefreshedAt sql.NullTime
 
-	err := row.Scan(&a.ID, &a.URL, &a.Title, &a.Summary, &embeddingStr,
+	dest := []interface{}{&a.ID, &a.URL, &a.Title, &a.Summary,
 		&a.Sentiment, &a.SentimentScore, &a.Tone,
-		&entitiesJSON, &keywordsJSON, &topicsJSON,
-		&a.URLHash, &a.CreatedAt, &a.UpdatedAt)
+		&entitiesJSON, &keywordsJSON, &topicsJSON, &topicIDsJSON,
+		&a.Author, &a.Section, &publishedAt, &a.SourceDomain, &a.Language, &lastRefreshedAt,
+		&a.EmbeddingModel, &a.SummaryVersion, &a.SummaryModel, &a.AnalysisVersion, &a.AnalysisModel, &a.Status, &a.StatusError, &a.CreatedAt, &a.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return a, err
+	}
+	if publishedAt.Valid {
+		a.PublishedAt = &publishedAt.Time
+	}
+	if lastRefreshedAt.Valid {
+		a.LastRefreshedAt = &lastRefreshedAt.Time
+	}
+	parseJSONFields(&a, entitiesJSON, keywordsJSON, topicsJSON, topicIDsJSON)
+	return a, nil
+}
+
+// applyArticleFilter adds author, section, source domain, topic, taxonomy topic, tag, collection, status, analysis and summary version, ingestion- and update-time and publication-date filtering if set
+func applyArticleFilter(query string, filter domain.ArticleFilter, args []interface{}) (string, []interface{}) {
+	if filter.Author != "" {
+		args = append(args, "%"+filter.Author+"%")
+		query += fmt.Sprintf(" AND author ILIKE $%d", len(args))
+	}
+	if filter.Section != "" {
+		args = append(args, "%"+filter.Section+"%")
+		query += fmt.Sprintf(" AND section ILIKE $%d", len(args))
+	}
+	if !filter.IngestedAfter.IsZero() {
+		args = append(args, filter.IngestedAfter)
+		query += fmt.Sprintf(" AND created_at > $%d", len(args))
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		args = append(args, filter.UpdatedAfter)
+		query += fmt.Sprintf(" AND updated_at > $%d", len(args))
+	}
+	// Articles without a publication date are placed at their ingestion time
+	if !filter.PublishedAfter.IsZero() {
+		args = append(args, filter.PublishedAfter)
+		query += fmt.Sprintf(" AND COALESCE(published_at, created_at) >= $%d", len(args))
+	}
+	if !filter.PublishedBefore.IsZero() {
+		args = append(args, filter.PublishedBefore)
+		query += fmt.Sprintf(" AND COALESCE(published_at, created_at) < $%d", len(args))
+	}
+	if filter.SourceDomain != "" {
+		args = append(args, "%"+filter.SourceDomain+"%")
+		query += fmt.Sprintf(" AND source_domain ILIKE $%d", len(args))
+	}
+	if filter.Topic != "" {
+		args = append(args, "%"+filter.Topic+"%")
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM jsonb_array_elements(topics) t WHERE t->>'name' ILIKE $%d)", len(args))
+	}
+	if filter.TopicID != "" {
+		args = append(args, filter.TopicID)
+		query += fmt.Sprintf(` AND topic_ids ?| ARRAY(
+		    WITH RECURSIVE subtopics AS (
+		      SELECT id FROM taxonomy_topics WHERE id = $%d
+		      UNION SELECT t.id FROM taxonomy_topics t JOIN subtopics s ON t.parent_id = s.id
+		    ) SELECT id FROM subtopics)`, len(args))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM article_tags t WHERE t.article_id = articles.id AND t.tag = $%d)", len(args))
+	}
+	if filter.Collection != "" {
+		args = append(args, filter.Collection)
+		query += fmt.Sprintf(` AND EXISTS (
+		    SELECT 1 FROM collection_articles ca JOIN collections c ON c.id = ca.collection_id
+		    WHERE ca.article_id = articles.id AND (c.id::text = $%[1]d OR lower(c.name) = lower($%[1]d)))`, len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.AnalysisVersion != "" {
+		args = append(args, filter.AnalysisVersion)
+		query += fmt.Sprintf(" AND analysis_version = $%d", len(args))
+	}
+	if filter.SummaryVersion != "" {
+		args = append(args, filter.SummaryVersion)
+		query += fmt.Sprintf(" AND summary_version = $%d", len(args))
+	}
+	return query, args
+}
+
+// GetArticleByURL retrieves an article by URL, including its content, embedding, hashes and ETag
+func (r *Repo) GetArticleByURL(ctx context.Context, url string) (*domain.Article, error) {
+	query := `SELECT ` + articleColumns + `, COALESCE(content, ''), embedding, url_hash,
+	          COALESCE(content_hash, ''), COALESCE(etag, ''), imported
+	          FROM articles WHERE url = $1`
 
+	var embeddingStr sql.NullString
+	var content, urlHash, contentHash, etag string
+	var imported bool
+	a, err := scanArticle(r.DB.QueryRowContext(ctx, query, url), &content, &embeddingStr, &urlHash, &contentHash, &etag, &imported)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Article not found
 		}
 		return nil, err
 	}
-
-	// Parse embedding
-	if embeddingStr != "[]" && embeddingStr != "" {
-		// Parse vector string like "[0.1,0.2,0.3]"
-		embeddingStr = strings.Trim(embeddingStr, "[]")
-		if embeddingStr != "" {
-			parts := strings.Split(embeddingStr, ",")
-			a.Embedding = make([]float32, len(parts))
-			for i, part := range parts {
-				var val float64
-				if _, err := fmt.Sscanf(strings.TrimSpace(part), "%f", &val); err == nil {
-					a.Embedding[i] = float32(val)
-				}
-			}
-		}
-	}
-
-	parseJSONFields(&a, entitiesJSON, keywordsJSON, topicsJSON)
+	a.Content, a.URLHash, a.ContentHash, a.ETag, a.Imported = content, urlHash, contentHash, etag, imported
+	a.Embedding = parseEmbedding(embeddingStr.String)
 	return &a, nil
 }
 
@@ -102,10 +295,62 @@ func (r *Repo) GetSummaryByID(ctx context.Context, id int, urls []string) (strin
 	return s, err
 }
 
+// GetArticlesBySentiment ranks articles by sentiment score. With a topic embedding, only
+// the candidates articles nearest to it are ranked; without one, all articles are.
+func (r *Repo) GetArticlesBySentiment(ctx context.Context, topicEmbedding []float32, candidates int, urls []string, filter domain.ArticleFilter, sq domain.SentimentQuery) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "sentiment_filter")
+	defer func() { finish(len(out), err) }()
+
+	where := " WHERE sentiment_score IS NOT NULL AND status = 'enriched'"
+	var args []interface{}
+	if topicEmbedding != nil {
+		args = append(args, "["+strings.Trim(strings.Join(strings.Fields(fmt.Sprint(topicEmbedding)), ","), "[]")+"]")
+		where += " AND embedding IS NOT NULL"
+	}
+	if sq.MinScore != nil {
+		args = append(args, *sq.MinScore)
+		where += fmt.Sprintf(" AND sentiment_score >= $%d", len(args))
+	}
+	if sq.MaxScore != nil {
+		args = append(args, *sq.MaxScore)
+		where += fmt.Sprintf(" AND sentiment_score <= $%d", len(args))
+	}
+	where, args = applyURLFilter(where, urls, args)
+	where, args = applyArticleFilter(where, filter, args)
+
+	q := `SELECT ` + articleColumns + ` FROM articles` + where
+	if topicEmbedding != nil {
+		args = append(args, candidates)
+		q = `SELECT ` + articleColumns + ` FROM articles WHERE id IN (
+		  SELECT id FROM articles` + where + fmt.Sprintf(` ORDER BY embedding <=> $1::vector LIMIT $%d)`, len(args))
+	}
+	order := "DESC"
+	if sq.Negative {
+		order = "ASC"
+	}
+	args = append(args, sq.Limit)
+	q += fmt.Sprintf(" ORDER BY sentiment_score %s, created_at DESC LIMIT $%d", order, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
 // GetMostPositiveByTopic returns the most positive article on a given topic
 func (r *Repo) GetMostPositiveByTopic(ctx context.Context, topic string, urls []string) (*domain.Article, error) {
 	q := `
-	  SELECT id, url, title, summary, sentiment, sentiment_score, tone, entities, keywords, topics, created_at, updated_at
+	  SELECT ` + articleColumns + `
 	  FROM articles
 	  WHERE (
 	    EXISTS (SELECT 1 FROM jsonb_array_elements(keywords) kw WHERE LOWER(kw->>'term') LIKE LOWER($1))
@@ -118,34 +363,66 @@ func (r *Repo) GetMostPositiveByTopic(ctx context.Context, topic string, urls []
 
 	row := r.DB.QueryRowContext(ctx, q, args...)
 
-	var a domain.Article
-	var entitiesJSON, keywordsJSON, topicsJSON []byte
-	if err := row.Scan(&a.ID, &a.URL, &a.Title, &a.Summary,
-		&a.Sentiment, &a.SentimentScore, &a.Tone,
-		&entitiesJSON, &keywordsJSON, &topicsJSON,
-		&a.CreatedAt, &a.UpdatedAt,
-	); err != nil {
+	a, err := scanArticle(row)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	parseJSONFields(&a, entitiesJSON, keywordsJSON, topicsJSON)
 	return &a, nil
 }
 
-// GetTopEntities returns most commonly discussed entities across all articles
-func (r *Repo) GetTopEntities(ctx context.Context, limit int, urls []string) ([]domain.SemanticEntity, error) {
-	q := `
-	  SELECT elem->>'name' AS entity_name,
-	         COUNT(*) AS count,
-	         AVG((elem->>'confidence')::float) AS avg_confidence
-	  FROM articles, jsonb_array_elements(entities) elem
-	  WHERE entities IS NOT NULL`
-	args := []interface{}{}
-	q, args = applyURLFilter(q, urls, args)
-	q += fmt.Sprintf(" GROUP BY elem->>'name' ORDER BY count DESC, avg_confidence DESC LIMIT $%d", len(args)+1)
+// entityNameSQL is an entities element's canonical name, or its raw name from before normalization
+const entityNameSQL = `COALESCE(NULLIF(trim(elem->>'canonical_name'), ''), trim(elem->>'name'))`
+
+// entityKeySQL folds entityNameSQL like entity.Key: lowercased, without spaces or punctuation.
+// The entity_mentions function in init.sql computes the same key.
+const entityKeySQL = `lower(regexp_replace(` + entityNameSQL + `, '[^[:alnum:]]+', '', 'g'))`
+
+// GetTopEntities returns the most prominent entities across all articles, ranked by the
+// number of articles mentioning them times their mean confidence. Spelling variants of a
+// canonical name are counted together and shown with the most common spelling. category, if
+// set, counts only mentions with that category. The whole corpus is ranked from the
+// trigger-maintained entity_counts and entity_articles tables; a URL scope aggregates just
+// those articles. Either way an article counts once per entity, however it spells it.
+func (r *Repo) GetTopEntities(ctx context.Context, limit int, urls []string, category string) (out []domain.TopEntity, err error) {
+	ctx, finish := traceQuery(ctx, "top_entities")
+	defer func() { finish(len(out), err) }()
+
+	var q string
+	var args []interface{}
+	if len(urls) == 0 {
+		q = `
+	  SELECT (array_agg(c.name ORDER BY c.articles DESC, c.name))[1] AS name,
+	         (array_agg(c.category ORDER BY c.articles DESC, c.category))[1] AS category,
+	         a.articles AS count,
+	         COALESCE(SUM(c.confidence_sum) / NULLIF(SUM(c.confidences), 0), 0) AS avg_confidence
+	  FROM entity_counts c JOIN entity_articles a ON a.entity_key = c.entity_key`
+		if category != "" {
+			args = append(args, category)
+			q += fmt.Sprintf(" WHERE a.category = $%[1]d AND c.category = $%[1]d", len(args))
+		} else {
+			q += " WHERE a.category = ''"
+		}
+		q += " GROUP BY c.entity_key, a.articles"
+	} else {
+		q, args = applyURLFilter(`
+	  SELECT mode() WITHIN GROUP (ORDER BY m.name) AS name,
+	         mode() WITHIN GROUP (ORDER BY m.category) AS category,
+	         COUNT(DISTINCT articles.id) AS count,
+	         COALESCE(AVG(m.confidence), 0) AS avg_confidence
+	  FROM articles, entity_mentions(entities) m
+	  WHERE TRUE`, urls, nil)
+		if category != "" {
+			args = append(args, category)
+			q += fmt.Sprintf(" AND m.category = $%d", len(args))
+		}
+		q += " GROUP BY m.entity_key"
+	}
 	args = append(args, limit)
+	q = fmt.Sprintf(`SELECT name, category, count, avg_confidence FROM (%s) e
+	  ORDER BY count * avg_confidence DESC, count DESC, name LIMIT $%d`, q, len(args))
 
 	rows, err := r.DB.QueryContext(ctx, q, args...)
 	if err != nil {
@@ -153,61 +430,205 @@ func (r *Repo) GetTopEntities(ctx context.Context, limit int, urls []string) ([]
 	}
 	defer rows.Close()
 
-	var result []domain.SemanticEntity
 	for rows.Next() {
-		var e domain.SemanticEntity
+		var name, cat string
 		var count int
 		var avg float64
-		if err := rows.Scan(&e.Name, &count, &avg); err != nil {
+		if err := rows.Scan(&name, &cat, &count, &avg); err != nil {
 			return nil, err
 		}
-		e.Confidence = avg
-		result = append(result, e)
+		out = append(out, newTopEntity(name, cat, count, avg))
 	}
-	return result, nil
+	return out, rows.Err()
 }
 
 // GetArticlesByVectorSearch performs semantic search using embeddings
 func (r *Repo) GetArticlesByVectorSearch(ctx context.Context, queryEmbedding []float32, limit int, urls []string) ([]domain.Article, error) {
+	return r.GetArticlesByVectorSearchWithFilter(ctx, queryEmbedding, limit, urls, domain.ArticleFilter{})
+}
+
+// GetArticlesByVectorSearchWithFilter performs semantic search restricted by article metadata
+func (r *Repo) GetArticlesByVectorSearchWithFilter(ctx context.Context, queryEmbedding []float32, limit int, urls []string, filter domain.ArticleFilter) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "vector_search")
+	defer func() { finish(len(out), err) }()
+
 	embeddingStr := "[" + strings.Trim(strings.Join(strings.Fields(fmt.Sprint(queryEmbedding)), ","), "[]") + "]"
 
 	q := `
-	  SELECT id, url, title, summary, sentiment, sentiment_score, tone, entities, keywords, topics, created_at, updated_at,
+	  SELECT ` + articleColumns + `,
 	         1 - (embedding <=> $1::vector) AS similarity
 	  FROM articles
-	  WHERE embedding IS NOT NULL`
+	  WHERE embedding IS NOT NULL AND status = 'enriched'`
 	args := []interface{}{embeddingStr}
 	q, args = applyURLFilter(q, urls, args)
+	q, args = applyArticleFilter(q, filter, args)
 	q += fmt.Sprintf(" ORDER BY embedding <=> $1::vector LIMIT $%d", len(args)+1)
 	args = append(args, limit)
 
-	rows, err := r.DB.QueryContext(ctx, q, args...)
+	rows, err := r.query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var out []domain.Article
 	for rows.Next() {
-		var a domain.Article
-		var entitiesJSON, keywordsJSON, topicsJSON []byte
 		var sim float64
-		if err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Summary,
-			&a.Sentiment, &a.SentimentScore, &a.Tone,
-			&entitiesJSON, &keywordsJSON, &topicsJSON,
-			&a.CreatedAt, &a.UpdatedAt, &sim); err != nil {
+		a, err := scanArticle(rows, &sim)
+		if err != nil {
 			return nil, err
 		}
-		parseJSONFields(&a, entitiesJSON, keywordsJSON, topicsJSON)
 		out = append(out, a)
 	}
 	return out, nil
 }
 
+// DefaultHybridVectorWeight is the share of the hybrid score given to vector similarity;
+// the rest goes to full-text rank
+const DefaultHybridVectorWeight = 0.7
+
+// DefaultHybridMinSimilarity is the cosine similarity from which an article matches a hybrid
+// search on its embedding alone; less similar articles must match the query's terms
+const DefaultHybridMinSimilarity = 0.3
+
+// hybridCandidates is how many nearest articles by embedding a hybrid search considers, besides
+// its full-text matches; deeper pages raise it to offset+limit
+const hybridCandidates = 200
+
+// GetArticlesByHybridSearch ranks articles by a weighted fusion of pgvector cosine similarity
+// and Postgres full-text rank for queryText, so articles with weak embeddings but matching
+// terms are still found. Candidates are the nearest articles by embedding, found through the
+// vector index, and the full-text matches, found through the text index; only they are scored.
+// Articles match on their terms or with a similarity of at least HybridMinSimilarity. It
+// returns the page at offset, each with its Score, and the number of matches. vectorWeight is
+// in [0, 1].
+func (r *Repo) GetArticlesByHybridSearch(ctx context.Context, queryEmbedding []float32, queryText string, limit, offset int, urls []string, filter domain.ArticleFilter, vectorWeight float64) (out []domain.Article, total int, err error) {
+	if vectorWeight < 0 || vectorWeight > 1 {
+		return nil, 0, fmt.Errorf("vector weight must be between 0 and 1, got %v", vectorWeight)
+	}
+	ctx, finish := traceQuery(ctx, "hybrid_search")
+	defer func() { finish(len(out), err) }()
+	embeddingStr := "[" + strings.Trim(strings.Join(strings.Fields(fmt.Sprint(queryEmbedding)), ","), "[]") + "]"
+
+	args := []interface{}{embeddingStr, queryText, r.HybridMinSimilarity}
+	nearest := `SELECT id FROM articles WHERE embedding IS NOT NULL AND status = 'enriched'`
+	nearest, args = applyURLFilter(nearest, urls, args)
+	nearest, args = applyArticleFilter(nearest, filter, args)
+	args = append(args, max(hybridCandidates, offset+limit))
+	nearest += fmt.Sprintf(" ORDER BY embedding <=> $1::vector LIMIT $%d", len(args))
+	matched := `SELECT id FROM articles WHERE status = 'enriched' AND search_tsv @@ websearch_to_tsquery('english', $2)`
+	matched, args = applyURLFilter(matched, urls, args)
+	matched, args = applyArticleFilter(matched, filter, args)
+
+	where := ` FROM articles
+	  WHERE id IN ((` + nearest + `) UNION (` + matched + `))
+	    AND (search_tsv @@ websearch_to_tsquery('english', $2) OR 1 - (embedding <=> $1::vector) >= $3)`
+	if err := r.queryRow(ctx, `SELECT COUNT(*)`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// ts_rank_cd normalization 32 maps rank into [0, 1) so it is comparable to cosine similarity
+	n := len(args)
+	q := `
+	  SELECT ` + articleColumns + fmt.Sprintf(`,
+	         $%d * COALESCE(1 - (embedding <=> $1::vector), 0)
+	           + (1 - $%d) * ts_rank_cd(search_tsv, websearch_to_tsquery('english', $2), 32) AS score`, n+1, n+1) +
+		where + fmt.Sprintf(" ORDER BY score DESC, id LIMIT $%d OFFSET $%d", n+2, n+3)
+	args = append(args, vectorWeight, limit, offset)
+
+	rows, err := r.query(ctx, q, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var score float64
+		a, err := scanArticle(rows, &score)
+		if err != nil {
+			return nil, 0, err
+		}
+		a.Score = score
+		out = append(out, a)
+	}
+	return out, total, rows.Err()
+}
+
+// StreamArticles calls fn for every article matching filter, oldest first, without loading the
+// corpus into memory. Content is not set, and Embedding only when withEmbeddings is true.
+// An error from fn stops the scan and is returned.
+func (r *Repo) StreamArticles(ctx context.Context, filter domain.ArticleFilter, withEmbeddings bool, fn func(domain.Article) error) (err error) {
+	ctx, finish := traceQuery(ctx, "stream_articles")
+	n := 0
+	defer func() { finish(n, err) }()
+
+	columns := articleColumns
+	if withEmbeddings {
+		columns += `, COALESCE(embedding::text, '')`
+	}
+	q, args := applyArticleFilter(`SELECT `+columns+` FROM articles WHERE TRUE`, filter, nil)
+	rows, err := r.DB.QueryContext(ctx, q+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var embeddingStr string
+		var extra []interface{}
+		if withEmbeddings {
+			extra = append(extra, &embeddingStr)
+		}
+		a, err := scanArticle(rows, extra...)
+		if err != nil {
+			return err
+		}
+		if embeddingStr != "" {
+			a.Embedding = parseEmbedding(embeddingStr)
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+		n++
+	}
+	return rows.Err()
+}
+
+// GetArticleEmbeddings returns the most recent articles that have embeddings, for corpus-wide
+// analysis such as clustering. Only ID, URL, Title, Summary, Embedding and CreatedAt are set.
+func (r *Repo) GetArticleEmbeddings(ctx context.Context, limit int) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "article_embeddings")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, url, title, COALESCE(summary, ''), embedding::text, created_at
+		FROM articles
+		WHERE embedding IS NOT NULL
+		ORDER BY created_at DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.Article
+		var embeddingStr string
+		if err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Summary, &embeddingStr, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.Embedding = parseEmbedding(embeddingStr)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
 // GetArticlesByKeywordsOrEntities queries articles by keywords or entities
-func (r *Repo) GetArticlesByKeywordsOrEntities(ctx context.Context, filter string, limit int) ([]domain.Article, error) {
+func (r *Repo) GetArticlesByKeywordsOrEntities(ctx context.Context, filter string, limit int) (articles []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "keyword_search")
+	defer func() { finish(len(articles), err) }()
+
 	q := `
-	  SELECT id, url, title, summary, sentiment, sentiment_score, tone, entities, keywords, topics, created_at, updated_at
+	  SELECT ` + articleColumns + `
 	  FROM articles
 	  WHERE 
 	    EXISTS (SELECT 1 FROM jsonb_array_elements(keywords) kw WHERE LOWER(kw->>'term') LIKE LOWER($1))
@@ -222,51 +643,34 @@ func (r *Repo) GetArticlesByKeywordsOrEntities(ctx context.Context, filter strin
 	}
 	defer rows.Close()
 
-	var articles []domain.Article
 	for rows.Next() {
-		var a domain.Article
-		var entitiesJSON, keywordsJSON, topicsJSON []byte
-		err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Summary,
-			&a.Sentiment, &a.SentimentScore, &a.Tone,
-			&entitiesJSON, &keywordsJSON, &topicsJSON,
-			&a.CreatedAt, &a.UpdatedAt)
+		a, err := scanArticle(rows)
 		if err != nil {
 			return nil, err
 		}
-		parseJSONFields(&a, entitiesJSON, keywordsJSON, topicsJSON)
 		articles = append(articles, a)
 	}
 
 	return articles, nil
 }
 
-// GetKeywordsAndTopics retrieves and aggregates keywords and topics from articles
-func (r *Repo) GetKeywordsAndTopics(ctx context.Context, urls []string, limit int) ([]domain.SemanticKeyword, []domain.SemanticTopic, error) {
+// GetKeywordsAndTopics merges the keywords and topics of the given articles, ranked by summed
+// relevance and then by the number of articles they were extracted from
+func (r *Repo) GetKeywordsAndTopics(ctx context.Context, urls []string, limit int) (keywords, topics []domain.TermAggregate, err error) {
 	if len(urls) == 0 {
 		return nil, nil, fmt.Errorf("no URLs provided")
 	}
+	ctx, finish := traceQuery(ctx, "keywords_and_topics")
+	defer func() { finish(len(keywords)+len(topics), err) }()
 
-	placeholders := make([]string, len(urls))
-	args := make([]interface{}, len(urls))
-	for i, u := range urls {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = u
-	}
-
-	query := fmt.Sprintf(`
-		SELECT keywords, topics
-		FROM articles
-		WHERE url IN (%s)`, strings.Join(placeholders, ","))
-
+	query, args := applyURLFilter(`SELECT keywords, topics FROM articles WHERE TRUE`, urls, nil)
 	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer rows.Close()
 
-	kwCount := make(map[string]int)
-	tpCount := make(map[string]int)
-
+	kwAgg, tpAgg := newTermAggregator(), newTermAggregator()
 	for rows.Next() {
 		var kwJSON, tpJSON []byte
 		if err := rows.Scan(&kwJSON, &tpJSON); err != nil {
@@ -279,37 +683,73 @@ func (r *Repo) GetKeywordsAndTopics(ctx context.Context, urls []string, limit in
 		json.Unmarshal(tpJSON, &tps)
 
 		for _, k := range kws {
-			kwCount[k.Term]++
+			kwAgg.add(k.Term, k.Relevance)
 		}
 		for _, t := range tps {
-			tpCount[t.Name]++
+			tpAgg.add(t.Name, t.Score)
 		}
+		kwAgg.next()
+		tpAgg.next()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return kwAgg.top(limit), tpAgg.top(limit), nil
+}
+
+// GetArticlesByURLs retrieves articles by their URLs
+func (r *Repo) GetArticlesByURLs(ctx context.Context, urls []string) (articles []domain.Article, err error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs provided")
 	}
+	ctx, finish := traceQuery(ctx, "articles_by_urls")
+	defer func() { finish(len(articles), err) }()
 
-	// Convert maps to slices and sort by frequency
-	var kwList []domain.SemanticKeyword
-	for term, count := range kwCount {
-		kwList = append(kwList, domain.SemanticKeyword{Term: term, Relevance: float64(count)})
+	rows, err := r.query(ctx, `SELECT `+articleColumns+` FROM articles WHERE url = ANY($1)`, pq.Array(urls))
+	if err != nil {
+		return nil, err
 	}
-	sort.Slice(kwList, func(i, j int) bool { return kwList[i].Relevance > kwList[j].Relevance })
-	if len(kwList) > limit {
-		kwList = kwList[:limit]
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, a)
 	}
 
-	var tpList []domain.SemanticTopic
-	for name, count := range tpCount {
-		tpList = append(tpList, domain.SemanticTopic{Name: name, Score: float64(count)})
+	return articles, nil
+}
+
+// GetExistingURLs returns which of urls are already stored and enriched, using a single query
+func (r *Repo) GetExistingURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(urls) == 0 {
+		return existing, nil
 	}
-	sort.Slice(tpList, func(i, j int) bool { return tpList[i].Score > tpList[j].Score })
-	if len(tpList) > limit {
-		tpList = tpList[:limit]
+
+	// URLs recorded as near-duplicates count as ingested too
+	rows, err := r.query(ctx, `SELECT url FROM articles WHERE url = ANY($1) AND status = 'enriched'
+		UNION SELECT url FROM article_duplicates WHERE url = ANY($1)`, pq.Array(urls))
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return kwList, tpList, nil
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		existing[url] = true
+	}
+	return existing, rows.Err()
 }
 
-// GetArticlesByURLs retrieves articles by their URLs
-func (r *Repo) GetArticlesByURLs(ctx context.Context, urls []string) ([]domain.Article, error) {
+// GetArticleContentsByURLs returns the full stored text of articles keyed by URL.
+// Articles without stored content are omitted from the result.
+func (r *Repo) GetArticleContentsByURLs(ctx context.Context, urls []string) (map[string]string, error) {
 	if len(urls) == 0 {
 		return nil, fmt.Errorf("no URLs provided")
 	}
@@ -322,9 +762,9 @@ func (r *Repo) GetArticlesByURLs(ctx context.Context, urls []string) ([]domain.A
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, url, title, summary, sentiment, sentiment_score, tone, entities, keywords, topics, created_at, updated_at
+		SELECT url, content
 		FROM articles
-		WHERE url IN (%s)`, strings.Join(placeholders, ","))
+		WHERE url IN (%s) AND content IS NOT NULL AND content <> ''`, strings.Join(placeholders, ","))
 
 	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -332,47 +772,47 @@ func (r *Repo) GetArticlesByURLs(ctx context.Context, urls []string) ([]domain.A
 	}
 	defer rows.Close()
 
-	var articles []domain.Article
+	contents := make(map[string]string)
 	for rows.Next() {
-		var a domain.Article
-		var entitiesJSON, keywordsJSON, topicsJSON []byte
-		err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Summary,
-			&a.Sentiment, &a.SentimentScore, &a.Tone,
-			&entitiesJSON, &keywordsJSON, &topicsJSON,
-			&a.CreatedAt, &a.UpdatedAt)
-		if err != nil {
+		var url, content string
+		if err := rows.Scan(&url, &content); err != nil {
 			return nil, err
 		}
-		parseJSONFields(&a, entitiesJSON, keywordsJSON, topicsJSON)
-		articles = append(articles, a)
+		contents[url] = content
 	}
-
-	return articles, nil
+	return contents, nil
 }
 
 // ---------- Upsert ----------
 func (r *Repo) UpsertArticle(ctx context.Context, article *domain.Article) error {
-	query := `INSERT INTO articles (id, url, title, summary, embedding, sentiment, sentiment_score, tone, entities, keywords, topics, url_hash, created_at, updated_at)
-		  VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
+	query := `INSERT INTO articles (id, url, title, summary, content, embedding, sentiment, sentiment_score, tone, entities, keywords, topics, url_hash, author, section, published_at,
+		    language, content_hash, etag, last_refreshed_at, created_at, updated_at, source_domain, imported, topic_ids, embedding_model, status, status_error, analysis_version,
+		    analysis_model, summary_version, summary_model)
+		  VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32)
 		  ON CONFLICT (url) DO UPDATE SET 
-		    title=EXCLUDED.title, summary=EXCLUDED.summary, embedding=EXCLUDED.embedding,
+		    title=EXCLUDED.title, summary=EXCLUDED.summary, summary_version=EXCLUDED.summary_version, summary_model=EXCLUDED.summary_model, content=EXCLUDED.content, embedding=EXCLUDED.embedding, embedding_model=EXCLUDED.embedding_model,
 		    sentiment=EXCLUDED.sentiment, sentiment_score=EXCLUDED.sentiment_score,
 		    tone=EXCLUDED.tone, entities=EXCLUDED.entities, keywords=EXCLUDED.keywords,
-		    topics=EXCLUDED.topics, url_hash=EXCLUDED.url_hash,
-		    updated_at=EXCLUDED.updated_at`
+		    topics=EXCLUDED.topics, topic_ids=EXCLUDED.topic_ids, analysis_version=EXCLUDED.analysis_version, analysis_model=EXCLUDED.analysis_model, url_hash=EXCLUDED.url_hash,
+		    author=EXCLUDED.author, section=EXCLUDED.section, published_at=EXCLUDED.published_at, source_domain=EXCLUDED.source_domain,
+		    language=EXCLUDED.language, content_hash=EXCLUDED.content_hash, etag=EXCLUDED.etag, last_refreshed_at=EXCLUDED.last_refreshed_at,
+		    imported=EXCLUDED.imported, status=EXCLUDED.status, status_error=EXCLUDED.status_error, updated_at=EXCLUDED.updated_at
+		  RETURNING id`
 
 	now := time.Now()
 	article.CreatedAt, article.UpdatedAt = now, now
+	if article.Status == "" {
+		article.Status = domain.ArticleEnriched
+	}
 
-	var embeddingStr string
+	// Pending articles have no embedding yet
+	var embedding sql.NullString
 	if len(article.Embedding) > 0 {
 		parts := make([]string, len(article.Embedding))
 		for i, v := range article.Embedding {
 			parts[i] = fmt.Sprintf("%f", v)
 		}
-		embeddingStr = "[" + strings.Join(parts, ",") + "]"
-	} else {
-		embeddingStr = "[]"
+		embedding = sql.NullString{String: "[" + strings.Join(parts, ",") + "]", Valid: true}
 	}
 
 	entitiesJSON, err := json.Marshal(article.Entities)
@@ -387,69 +827,1562 @@ func (r *Repo) UpsertArticle(ctx context.Context, article *domain.Article) error
 	if err != nil {
 		return fmt.Errorf("failed to marshal topics: %w", err)
 	}
+	topicIDs := article.TopicIDs
+	if topicIDs == nil {
+		topicIDs = []string{}
+	}
+	topicIDsJSON, err := json.Marshal(topicIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal topic IDs: %w", err)
+	}
 
-	_, err = r.DB.ExecContext(ctx, query,
-		article.ID, article.URL, article.Title, article.Summary,
-		embeddingStr, article.Sentiment, article.SentimentScore, article.Tone,
+	// On conflict the existing row keeps its ID; report it back to the caller
+	err = r.DB.QueryRowContext(ctx, query,
+		article.ID, article.URL, article.Title, article.Summary, article.Content,
+		embedding, article.Sentiment, article.SentimentScore, article.Tone,
 		entitiesJSON, keywordsJSON, topicsJSON,
-		article.URLHash, article.CreatedAt, article.UpdatedAt,
-	)
+		article.URLHash, nullString(article.Author), nullString(article.Section), article.PublishedAt,
+		nullString(article.Language), nullString(article.ContentHash), nullString(article.ETag), article.LastRefreshedAt,
+		article.CreatedAt, article.UpdatedAt, nullString(article.SourceDomain), article.Imported, topicIDsJSON,
+		nullString(article.EmbeddingModel), article.Status, nullString(article.StatusError), nullString(article.AnalysisVersion),
+		nullString(article.AnalysisModel), nullString(article.SummaryVersion), nullString(article.SummaryModel),
+	).Scan(&article.ID)
 	return err
 }
 
-// ---------- Chat Cache ----------
-
-// GetChatCache retrieves a cached chat response by request hash
-func (r *Repo) GetChatCache(ctx context.Context, requestHash string) (*domain.ChatCache, error) {
-	query := `SELECT id, request_hash, request_json, response_json, created_at, expires_at
-	          FROM chat_cache WHERE request_hash = $1 AND expires_at > NOW()`
-
-	row := r.DB.QueryRowContext(ctx, query, requestHash)
-
-	var cache domain.ChatCache
-	var requestJSON, responseJSON []byte
-
-	err := row.Scan(&cache.ID, &cache.RequestHash, &requestJSON, &responseJSON,
-		&cache.CreatedAt, &cache.ExpiresAt)
-
+// ReplaceArticleChunks stores the embedded passages of an article, replacing any previous ones
+func (r *Repo) ReplaceArticleChunks(ctx context.Context, articleID string, chunks []string, embeddings [][]float32) error {
+	if len(chunks) != len(embeddings) {
+		return fmt.Errorf("got %d chunks but %d embeddings", len(chunks), len(embeddings))
+	}
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Cache not found or expired
-		}
-		return nil, err
+		return err
 	}
+	defer tx.Rollback()
 
-	// Parse JSON fields
-	if len(requestJSON) > 0 {
-		_ = json.Unmarshal(requestJSON, &cache.RequestJSON)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_chunks WHERE article_id = $1`, articleID); err != nil {
+		return err
 	}
-	if len(responseJSON) > 0 {
-		_ = json.Unmarshal(responseJSON, &cache.ResponseJSON)
+	for i, text := range chunks {
+		embeddingStr := "[" + strings.Trim(strings.Join(strings.Fields(fmt.Sprint(embeddings[i])), ","), "[]") + "]"
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO article_chunks (article_id, chunk_index, text, embedding) VALUES ($1, $2, $3, $4::vector)`,
+			articleID, i, text, embeddingStr); err != nil {
+			return fmt.Errorf("failed to insert chunk %d: %w", i, err)
+		}
 	}
-
-	return &cache, nil
+	return tx.Commit()
 }
 
-// SetChatCache stores a chat request/response in cache
-func (r *Repo) SetChatCache(ctx context.Context, requestHash string, request, response interface{}) error {
-	requestJSON, err := json.Marshal(request)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+// SearchArticleChunks returns the passages most similar to the query embedding
+func (r *Repo) SearchArticleChunks(ctx context.Context, queryEmbedding []float32, limit int, urls []string) (out []domain.ArticleChunk, err error) {
+	ctx, finish := traceQuery(ctx, "chunk_search")
+	defer func() { finish(len(out), err) }()
+	embeddingStr := "[" + strings.Trim(strings.Join(strings.Fields(fmt.Sprint(queryEmbedding)), ","), "[]") + "]"
+
+	q := `
+	  SELECT c.article_id, a.url, a.title, c.chunk_index, c.text, 1 - (c.embedding <=> $1::vector) AS similarity
+	  FROM article_chunks c JOIN articles a ON a.id = c.article_id
+	  WHERE true`
+	args := []interface{}{embeddingStr}
+	if len(urls) > 0 {
+		args = append(args, pq.Array(urls))
+		q += fmt.Sprintf(" AND a.url = ANY($%d)", len(args))
 	}
+	q += fmt.Sprintf(" ORDER BY c.embedding <=> $1::vector LIMIT $%d", len(args)+1)
+	args = append(args, limit)
 
-	responseJSON, err := json.Marshal(response)
+	rows, err := r.query(ctx, q, args...)
 	if err != nil {
-		return fmt.Errorf("failed to marshal response: %w", err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	query := `INSERT INTO chat_cache (request_hash, request_json, response_json, expires_at)
-	          VALUES ($1, $2, $3, NOW() + INTERVAL '24 hours')
+	for rows.Next() {
+		var c domain.ArticleChunk
+		if err := rows.Scan(&c.ArticleID, &c.URL, &c.Title, &c.Index, &c.Text, &c.Similarity); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// GetStaleArticles returns up to limit articles whose content was last checked before cutoff,
+// oldest first, with the stored content, hash and ETag needed to detect changes
+func (r *Repo) GetStaleArticles(ctx context.Context, cutoff time.Time, limit int) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "stale_articles")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+	  SELECT `+articleColumns+`, COALESCE(content, ''), COALESCE(content_hash, ''), COALESCE(etag, '')
+	  FROM articles
+	  WHERE NOT imported AND status = 'enriched' AND COALESCE(last_refreshed_at, created_at) < $1
+	  ORDER BY COALESCE(last_refreshed_at, created_at)
+	  LIMIT $2`, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var content, contentHash, etag string
+		a, err := scanArticle(rows, &content, &contentHash, &etag)
+		if err != nil {
+			return nil, err
+		}
+		a.Content, a.ContentHash, a.ETag = content, contentHash, etag
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// MarkArticleRefreshed records a refresh that found the content unchanged
+func (r *Repo) MarkArticleRefreshed(ctx context.Context, url, contentHash, etag string, at time.Time) error {
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE articles SET last_refreshed_at = $2,
+		   content_hash = COALESCE($3, content_hash), etag = COALESCE($4, etag)
+		 WHERE url = $1`,
+		url, at, nullString(contentHash), nullString(etag))
+	return err
+}
+
+// SetArticleStatus sets the ingest status of the article at url, with the reason when it failed
+func (r *Repo) SetArticleStatus(ctx context.Context, url, status, reason string) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE articles SET status = $2, status_error = $3, updated_at = NOW() WHERE url = $1`,
+		url, status, nullString(reason))
+	return err
+}
+
+// GetArticleURLs returns the URLs of articles matching filter, newest first; a non-empty
+// urls restricts the candidates
+func (r *Repo) GetArticleURLs(ctx context.Context, urls []string, filter domain.ArticleFilter) (out []string, err error) {
+	ctx, finish := traceQuery(ctx, "article_urls")
+	defer func() { finish(len(out), err) }()
+
+	q := `SELECT url FROM articles WHERE TRUE`
+	var args []interface{}
+	q, args = applyURLFilter(q, urls, args)
+	q, args = applyArticleFilter(q, filter, args)
+	q += " ORDER BY created_at DESC"
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// GetArticleByID retrieves an article by ID
+func (r *Repo) GetArticleByID(ctx context.Context, id string) (*domain.Article, error) {
+	row := r.DB.QueryRowContext(ctx, `SELECT `+articleColumns+` FROM articles WHERE id = $1`, id)
+	a, err := scanArticle(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetSimplifiedSummary returns a cached reading-level rewrite, or "" if none exists
+func (r *Repo) GetSimplifiedSummary(ctx context.Context, articleID, level string) (string, error) {
+	var text string
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT text FROM article_simplifications WHERE article_id = $1 AND level = $2`,
+		articleID, level).Scan(&text)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return text, err
+}
+
+// SetSimplifiedSummary caches a reading-level rewrite of an article summary
+func (r *Repo) SetSimplifiedSummary(ctx context.Context, articleID, level, text string) error {
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO article_simplifications (article_id, level, text) VALUES ($1, $2, $3)
+		 ON CONFLICT (article_id, level) DO UPDATE SET text = EXCLUDED.text, created_at = NOW()`,
+		articleID, level, text)
+	return err
+}
+
+// ClearSimplifiedSummaries drops cached rewrites for an article, e.g. after it is re-ingested
+func (r *Repo) ClearSimplifiedSummaries(ctx context.Context, url string) error {
+	_, err := r.DB.ExecContext(ctx,
+		`DELETE FROM article_simplifications WHERE article_id IN (SELECT id FROM articles WHERE url = $1)`, url)
+	return err
+}
+
+// articleSortColumns maps accepted ListArticles sort keys to ORDER BY columns
+var articleSortColumns = map[string]string{
+	"created_at":      "created_at",
+	"sentiment_score": "sentiment_score",
+}
+
+// ListArticles returns a page of metadata of the articles matching filter and their total count.
+// sort is "created_at" (default) or "sentiment_score"; desc selects descending order.
+func (r *Repo) ListArticles(ctx context.Context, filter domain.ArticleFilter, limit, offset int, sort string, desc bool) ([]domain.ArticleListItem, int, error) {
+	return listArticlePage(ctx, r, filter, limit, offset, sort, desc)
+}
+
+// articleField is a column ListAllArticles can select; bind returns the scan destination and,
+// for values that need converting, a func that sets the article field after the scan
+type articleField struct {
+	column string
+	bind   func(a *domain.Article) (dest interface{}, apply func())
+}
+
+func textField(column string, field func(a *domain.Article) *string) articleField {
+	return articleField{"COALESCE(" + column + "::text, '')", func(a *domain.Article) (interface{}, func()) { return field(a), nil }}
+}
+
+func jsonField(column string, decode func(a *domain.Article, data []byte)) articleField {
+	return articleField{column, func(a *domain.Article) (interface{}, func()) {
+		var data []byte
+		return &data, func() { decode(a, data) }
+	}}
+}
+
+func timeField(column string, field func(a *domain.Article) **time.Time) articleField {
+	return articleField{column, func(a *domain.Article) (interface{}, func()) {
+		var t sql.NullTime
+		return &t, func() {
+			if t.Valid {
+				*field(a) = &t.Time
+			}
+		}
+	}}
+}
+
+// articleFieldColumns maps the ArticleFields names to their columns
+var articleFieldColumns = map[string]articleField{
+	"id":                textField("id", func(a *domain.Article) *string { return &a.ID }),
+	"url":               textField("url", func(a *domain.Article) *string { return &a.URL }),
+	"title":             textField("title", func(a *domain.Article) *string { return &a.Title }),
+	"summary":           textField("summary", func(a *domain.Article) *string { return &a.Summary }),
+	"content":           textField("content", func(a *domain.Article) *string { return &a.Content }),
+	"sentiment":         textField("sentiment", func(a *domain.Article) *string { return &a.Sentiment }),
+	"sentiment_score":   {"COALESCE(sentiment_score, 0.5)", func(a *domain.Article) (interface{}, func()) { return &a.SentimentScore, nil }},
+	"tone":              textField("tone", func(a *domain.Article) *string { return &a.Tone }),
+	"entities":          jsonField("entities", func(a *domain.Article, data []byte) { parseJSONFields(a, data, nil, nil, nil) }),
+	"keywords":          jsonField("keywords", func(a *domain.Article, data []byte) { parseJSONFields(a, nil, data, nil, nil) }),
+	"topics":            jsonField("topics", func(a *domain.Article, data []byte) { parseJSONFields(a, nil, nil, data, nil) }),
+	"topic_ids":         jsonField("topic_ids", func(a *domain.Article, data []byte) { parseJSONFields(a, nil, nil, nil, data) }),
+	"url_hash":          textField("url_hash", func(a *domain.Article) *string { return &a.URLHash }),
+	"author":            textField("author", func(a *domain.Article) *string { return &a.Author }),
+	"section":           textField("section", func(a *domain.Article) *string { return &a.Section }),
+	"published_at":      timeField("published_at", func(a *domain.Article) **time.Time { return &a.PublishedAt }),
+	"source_domain":     textField("source_domain", func(a *domain.Article) *string { return &a.SourceDomain }),
+	"language":          textField("language", func(a *domain.Article) *string { return &a.Language }),
+	"content_hash":      textField("content_hash", func(a *domain.Article) *string { return &a.ContentHash }),
+	"last_refreshed_at": timeField("last_refreshed_at", func(a *domain.Article) **time.Time { return &a.LastRefreshedAt }),
+	"imported":          {"imported", func(a *domain.Article) (interface{}, func()) { return &a.Imported, nil }},
+	"embedding_model":   textField("embedding_model", func(a *domain.Article) *string { return &a.EmbeddingModel }),
+	"summary_version":   textField("summary_version", func(a *domain.Article) *string { return &a.SummaryVersion }),
+	"summary_model":     textField("summary_model", func(a *domain.Article) *string { return &a.SummaryModel }),
+	"analysis_version":  textField("analysis_version", func(a *domain.Article) *string { return &a.AnalysisVersion }),
+	"analysis_model":    textField("analysis_model", func(a *domain.Article) *string { return &a.AnalysisModel }),
+	"status":            textField("status", func(a *domain.Article) *string { return &a.Status }),
+	"status_error":      textField("status_error", func(a *domain.Article) *string { return &a.StatusError }),
+	"created_at":        {"created_at", func(a *domain.Article) (interface{}, func()) { return &a.CreatedAt, nil }},
+	"updated_at":        {"updated_at", func(a *domain.Article) (interface{}, func()) { return &a.UpdatedAt, nil }},
+}
+
+// ListAllArticles returns the articles matching opts.Filter with only opts.Fields set, for
+// listings and admin tooling that need no vector search. Articles are ordered by opts.Sort,
+// then ID; opts.Limit 0 returns every article.
+func (r *Repo) ListAllArticles(ctx context.Context, opts domain.ArticleListOptions) (out []domain.Article, err error) {
+	fields, err := selectFields(opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSort(opts.Sort); err != nil {
+		return nil, err
+	}
+	ctx, finish := traceQuery(ctx, "list_all_articles")
+	defer func() { finish(len(out), err) }()
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = articleFieldColumns[f].column
+	}
+	sortColumn, direction := "created_at", "ASC"
+	if opts.Sort != "" {
+		sortColumn = articleSortColumns[opts.Sort]
+	}
+	if opts.Desc {
+		direction = "DESC"
+	}
+	q, args := applyArticleFilter(`SELECT `+strings.Join(columns, ", ")+` FROM articles WHERE TRUE`, opts.Filter, nil)
+	q += fmt.Sprintf(` ORDER BY %s %s, id`, sortColumn, direction)
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit)
+		q += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+	if opts.Offset > 0 {
+		args = append(args, opts.Offset)
+		q += fmt.Sprintf(` OFFSET $%d`, len(args))
+	}
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out = []domain.Article{}
+	for rows.Next() {
+		var a domain.Article
+		dests := make([]interface{}, len(fields))
+		var applies []func()
+		for i, f := range fields {
+			dest, apply := articleFieldColumns[f].bind(&a)
+			dests[i] = dest
+			if apply != nil {
+				applies = append(applies, apply)
+			}
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+		for _, apply := range applies {
+			apply()
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// CountArticles returns the number of articles matching filter
+func (r *Repo) CountArticles(ctx context.Context, filter domain.ArticleFilter) (int, error) {
+	q, args := applyArticleFilter(`SELECT COUNT(*) FROM articles WHERE TRUE`, filter, nil)
+	var total int
+	err := r.DB.QueryRowContext(ctx, q, args...).Scan(&total)
+	return total, err
+}
+
+// GetEntityGraph builds a co-occurrence graph of the maxNodes entities mentioned in the most
+// articles. Entities are grouped by folded canonical name and shown with their most common spelling;
+// an edge links two entities appearing together in at least minShared articles.
+func (r *Repo) GetEntityGraph(ctx context.Context, maxNodes, minShared int, urls []string) (graph *domain.EntityGraph, err error) {
+	ctx, finish := traceQuery(ctx, "entity_graph")
+	defer func() {
+		rows := 0
+		if graph != nil {
+			rows = len(graph.Nodes) + len(graph.Edges)
+		}
+		finish(rows, err)
+	}()
+
+	cte := `
+	  WITH mentions AS (
+	    SELECT id AS article_id, ` + entityKeySQL + ` AS entity_key,
+	           ` + entityNameSQL + ` AS name, COALESCE(NULLIF(elem->>'category', ''), 'other') AS category
+	    FROM articles, jsonb_array_elements(entities) elem
+	    WHERE ` + entityKeySQL + ` <> ''`
+	args := []interface{}{}
+	cte, args = applyURLFilter(cte, urls, args)
+	args = append(args, maxNodes)
+	cte += fmt.Sprintf(`
+	  ), top AS (
+	    SELECT entity_key, COUNT(DISTINCT article_id) AS articles,
+	           mode() WITHIN GROUP (ORDER BY name) AS name,
+	           mode() WITHIN GROUP (ORDER BY category) AS category
+	    FROM mentions
+	    GROUP BY entity_key
+	    ORDER BY articles DESC, entity_key
+	    LIMIT $%d
+	  )`, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, cte+` SELECT entity_key, name, category, articles FROM top ORDER BY articles DESC, entity_key`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	graph = &domain.EntityGraph{Nodes: []domain.EntityNode{}, Edges: []domain.EntityEdge{}}
+	for rows.Next() {
+		var n domain.EntityNode
+		if err := rows.Scan(&n.ID, &n.Name, &n.Category, &n.Articles); err != nil {
+			return nil, err
+		}
+		graph.Nodes = append(graph.Nodes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	args = append(args, minShared)
+	edgeRows, err := r.DB.QueryContext(ctx, cte+fmt.Sprintf(`, pairs AS (
+	    SELECT DISTINCT article_id, entity_key FROM mentions WHERE entity_key IN (SELECT entity_key FROM top)
+	  )
+	  SELECT a.entity_key, b.entity_key, COUNT(*) AS shared
+	  FROM pairs a JOIN pairs b ON a.article_id = b.article_id AND a.entity_key < b.entity_key
+	  GROUP BY a.entity_key, b.entity_key
+	  HAVING COUNT(*) >= $%d
+	  ORDER BY shared DESC, a.entity_key, b.entity_key`, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer edgeRows.Close()
+
+	for edgeRows.Next() {
+		var e domain.EntityEdge
+		if err := edgeRows.Scan(&e.Source, &e.Target, &e.Articles); err != nil {
+			return nil, err
+		}
+		graph.Edges = append(graph.Edges, e)
+	}
+	return graph, edgeRows.Err()
+}
+
+// mentionsEntitySQL matches articles with an entity whose folded canonical name is the
+// placeholder, through the GIN index on the generated entity_keys column
+const mentionsEntitySQL = `articles.entity_keys ? $%d`
+
+// GetEntityDetail aggregates the articles mentioning the entity with folded name key (see
+// entity.Key): sentiment, the related entities mentioned with it most often, and a timeline
+// bucketed by interval ("day" or "week"). It returns nil if no article mentions the entity.
+func (r *Repo) GetEntityDetail(ctx context.Context, key, interval string, related int) (detail *domain.EntityDetail, err error) {
+	if !trendIntervals[interval] {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	ctx, finish := traceQuery(ctx, "entity_detail")
+	defer func() {
+		rows := 0
+		if detail != nil {
+			rows = 1 + len(detail.CoOccurring) + len(detail.Timeline)
+		}
+		finish(rows, err)
+	}()
+
+	detail = &domain.EntityDetail{ID: key, CoOccurring: []domain.EntityNode{}, Timeline: []domain.SentimentBucket{}}
+	err = r.DB.QueryRowContext(ctx, `
+	  SELECT COUNT(*), COALESCE(AVG(sentiment_score), 0),
+	         COUNT(*) FILTER (WHERE sentiment = 'positive'),
+	         COUNT(*) FILTER (WHERE sentiment = 'negative'),
+	         COUNT(*) FILTER (WHERE sentiment NOT IN ('positive', 'negative') OR sentiment IS NULL)
+	  FROM articles WHERE `+fmt.Sprintf(mentionsEntitySQL, 1), key).
+		Scan(&detail.Articles, &detail.AverageScore, &detail.Positive, &detail.Negative, &detail.Neutral)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Articles == 0 {
+		return nil, nil
+	}
+
+	err = r.DB.QueryRowContext(ctx, `
+	  SELECT mode() WITHIN GROUP (ORDER BY `+entityNameSQL+`),
+	         mode() WITHIN GROUP (ORDER BY COALESCE(NULLIF(elem->>'category', ''), 'other'))
+	  FROM articles, jsonb_array_elements(entities) elem
+	  WHERE `+entityKeySQL+` = $1`, key).Scan(&detail.Name, &detail.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `
+	  SELECT `+entityKeySQL+` AS entity_key,
+	         mode() WITHIN GROUP (ORDER BY `+entityNameSQL+`),
+	         mode() WITHIN GROUP (ORDER BY COALESCE(NULLIF(elem->>'category', ''), 'other')),
+	         COUNT(DISTINCT id) AS shared
+	  FROM articles, jsonb_array_elements(entities) elem
+	  WHERE `+fmt.Sprintf(mentionsEntitySQL, 1)+` AND `+entityKeySQL+` NOT IN ($1, '')
+	  GROUP BY entity_key
+	  ORDER BY shared DESC, entity_key
+	  LIMIT $2`, key, related)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n domain.EntityNode
+		if err := rows.Scan(&n.ID, &n.Name, &n.Category, &n.Articles); err != nil {
+			return nil, err
+		}
+		detail.CoOccurring = append(detail.CoOccurring, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	trend, err := r.DB.QueryContext(ctx, `
+	  SELECT date_trunc($1, COALESCE(published_at, created_at)) AS bucket,
+	         COUNT(*), AVG(sentiment_score),
+	         COUNT(*) FILTER (WHERE sentiment = 'positive'),
+	         COUNT(*) FILTER (WHERE sentiment = 'negative'),
+	         COUNT(*) FILTER (WHERE sentiment NOT IN ('positive', 'negative') OR sentiment IS NULL)
+	  FROM articles
+	  WHERE `+fmt.Sprintf(mentionsEntitySQL, 2)+`
+	  GROUP BY bucket ORDER BY bucket`, interval, key)
+	if err != nil {
+		return nil, err
+	}
+	defer trend.Close()
+	for trend.Next() {
+		var b domain.SentimentBucket
+		if err := trend.Scan(&b.Start, &b.Articles, &b.AverageScore, &b.Positive, &b.Negative, &b.Neutral); err != nil {
+			return nil, err
+		}
+		detail.Timeline = append(detail.Timeline, b)
+	}
+	return detail, trend.Err()
+}
+
+// ListEntityArticles pages through the articles mentioning the entity with folded name key,
+// most recently published first, with the total for pagination
+func (r *Repo) ListEntityArticles(ctx context.Context, key string, limit, offset int) (items []domain.ArticleListItem, total int, err error) {
+	ctx, finish := traceQuery(ctx, "entity_articles")
+	defer func() { finish(len(items), err) }()
+
+	where := ` FROM articles WHERE ` + fmt.Sprintf(mentionsEntitySQL, 1)
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*)`+where, key).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT id, url, title, sentiment, sentiment_score, COALESCE(author, ''), COALESCE(section, ''), published_at, created_at`+
+		where+` ORDER BY COALESCE(published_at, created_at) DESC, id LIMIT $2 OFFSET $3`, key, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items = []domain.ArticleListItem{}
+	for rows.Next() {
+		var it domain.ArticleListItem
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&it.ID, &it.URL, &it.Title, &it.Sentiment, &it.SentimentScore,
+			&it.Author, &it.Section, &publishedAt, &it.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if publishedAt.Valid {
+			it.PublishedAt = &publishedAt.Time
+		}
+		items = append(items, it)
+	}
+	return items, total, rows.Err()
+}
+
+// trendIntervals are the accepted GetSentimentTrend bucket sizes (date_trunc fields)
+var trendIntervals = map[string]bool{"day": true, "week": true}
+
+// GetSentimentTrend buckets article sentiment by publication day or week (ingestion time when
+// the publication date is unknown), oldest first. topic, if set, is matched with full-text search.
+func (r *Repo) GetSentimentTrend(ctx context.Context, topic string, urls []string, since time.Time, interval string) (out []domain.SentimentBucket, err error) {
+	if !trendIntervals[interval] {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	ctx, finish := traceQuery(ctx, "sentiment_trend")
+	defer func() { finish(len(out), err) }()
+
+	q := `
+	  SELECT date_trunc($1, COALESCE(published_at, created_at)) AS bucket,
+	         COUNT(*), AVG(sentiment_score),
+	         COUNT(*) FILTER (WHERE sentiment = 'positive'),
+	         COUNT(*) FILTER (WHERE sentiment = 'negative'),
+	         COUNT(*) FILTER (WHERE sentiment NOT IN ('positive', 'negative') OR sentiment IS NULL)
+	  FROM articles
+	  WHERE COALESCE(published_at, created_at) >= $2`
+	args := []interface{}{interval, since}
+	if topic != "" {
+		args = append(args, topic)
+		q += fmt.Sprintf(" AND search_tsv @@ websearch_to_tsquery('english', $%d)", len(args))
+	}
+	q, args = applyURLFilter(q, urls, args)
+	q += " GROUP BY bucket ORDER BY bucket"
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b domain.SentimentBucket
+		if err := rows.Scan(&b.Start, &b.Articles, &b.AverageScore, &b.Positive, &b.Negative, &b.Neutral); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// GetArticlesByTone returns articles whose stored tone contains tone (case-insensitive),
+// newest first
+func (r *Repo) GetArticlesByTone(ctx context.Context, tone string, urls []string, limit int) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "tone_filter")
+	defer func() { finish(len(out), err) }()
+
+	q := `SELECT ` + articleColumns + ` FROM articles WHERE tone ILIKE $1`
+	args := []interface{}{"%" + strings.TrimSpace(tone) + "%"}
+	q, args = applyURLFilter(q, urls, args)
+	args = append(args, limit)
+	q += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// GetToneCounts groups articles by stored tone (case-insensitive), most common first
+func (r *Repo) GetToneCounts(ctx context.Context, urls []string) (out []domain.ToneCount, err error) {
+	ctx, finish := traceQuery(ctx, "tone_counts")
+	defer func() { finish(len(out), err) }()
+
+	q := `SELECT lower(trim(tone)) AS t, COUNT(*) FROM articles WHERE COALESCE(trim(tone), '') <> ''`
+	q, args := applyURLFilter(q, urls, nil)
+	q += " GROUP BY t ORDER BY COUNT(*) DESC, t"
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tc domain.ToneCount
+		if err := rows.Scan(&tc.Tone, &tc.Articles); err != nil {
+			return nil, err
+		}
+		out = append(out, tc)
+	}
+	return out, rows.Err()
+}
+
+// DeleteArticleByURL removes an article; it reports whether a row was deleted
+func (r *Repo) DeleteArticleByURL(ctx context.Context, url string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM articles WHERE url = $1`, url)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		// The URL may have been recorded as a duplicate rather than stored
+		res, err = r.DB.ExecContext(ctx, `DELETE FROM article_duplicates WHERE url = $1`, url)
+		if err != nil {
+			return false, err
+		}
+		if n, err = res.RowsAffected(); err != nil {
+			return false, err
+		}
+	}
+	return n > 0, nil
+}
+
+// ---------- Duplicates ----------
+
+// FindNearDuplicate returns the stored article most similar to embedding if its cosine similarity
+// is at least minSimilarity, or nil. excludeURL skips the article being ingested.
+func (r *Repo) FindNearDuplicate(ctx context.Context, embedding []float32, excludeURL string, minSimilarity float64) (*domain.Article, float64, error) {
+	embeddingStr := "[" + strings.Trim(strings.Join(strings.Fields(fmt.Sprint(embedding)), ","), "[]") + "]"
+
+	q := `
+	  SELECT ` + articleColumns + `,
+	         1 - (embedding <=> $1::vector) AS similarity
+	  FROM articles
+	  WHERE embedding IS NOT NULL AND status = 'enriched' AND url <> $2
+	  ORDER BY embedding <=> $1::vector
+	  LIMIT 1`
+
+	var sim float64
+	a, err := scanArticle(r.DB.QueryRowContext(ctx, q, embeddingStr, excludeURL), &sim)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	if sim < minSimilarity {
+		return nil, sim, nil
+	}
+	return &a, sim, nil
+}
+
+// ---------- Embedding migration ----------
+
+// CountEmbeddingModels returns the number of articles per embedding model, most used first
+func (r *Repo) CountEmbeddingModels(ctx context.Context) (out []domain.EmbeddingModelCount, err error) {
+	ctx, finish := traceQuery(ctx, "count_embedding_models")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT COALESCE(embedding_model, ''), COUNT(*)
+		FROM articles
+		GROUP BY 1
+		ORDER BY 2 DESC, 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.EmbeddingModelCount
+		if err := rows.Scan(&c.Model, &c.Articles); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// ListArticlesToReembed returns up to limit articles not embedded with model, in ID order after
+// afterID ("" to start). Only ID, URL, Title, Summary, Content and EmbeddingModel are set.
+func (r *Repo) ListArticlesToReembed(ctx context.Context, model, afterID string, limit int) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "list_articles_to_reembed")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, url, title, COALESCE(summary, ''), COALESCE(content, ''), COALESCE(embedding_model, '')
+		FROM articles
+		WHERE embedding_model IS DISTINCT FROM $1 AND id::text > $2
+		ORDER BY id::text
+		LIMIT $3`, model, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.Article
+		if err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Summary, &a.Content, &a.EmbeddingModel); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetArticleEmbedding replaces an article's embedding and the model that produced it
+func (r *Repo) SetArticleEmbedding(ctx context.Context, id string, embedding []float32, model string) error {
+	embeddingStr := "[" + strings.Trim(strings.Join(strings.Fields(fmt.Sprint(embedding)), ","), "[]") + "]"
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE articles SET embedding = $2::vector, embedding_model = $3 WHERE id = $1`,
+		id, embeddingStr, nullString(model))
+	return err
+}
+
+// ---------- Re-analysis ----------
+
+// CountAnalysisVersions returns the number of enriched articles per analysis version, most used first
+func (r *Repo) CountAnalysisVersions(ctx context.Context) (out []domain.AnalysisVersionCount, err error) {
+	ctx, finish := traceQuery(ctx, "count_analysis_versions")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT COALESCE(analysis_version, ''), COUNT(*)
+		FROM articles
+		WHERE status = 'enriched'
+		GROUP BY 1
+		ORDER BY 2 DESC, 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.AnalysisVersionCount
+		if err := rows.Scan(&c.Version, &c.Articles); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// ListArticlesToReanalyze returns up to limit enriched articles not analyzed with version, in ID
+// order after afterID ("" to start). Articles with a supplied analysis are left out. Only ID,
+// URL, Title, Summary and AnalysisVersion are set.
+func (r *Repo) ListArticlesToReanalyze(ctx context.Context, version, afterID string, limit int) (out []domain.Article, err error) {
+	ctx, finish := traceQuery(ctx, "list_articles_to_reanalyze")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, url, title, COALESCE(summary, ''), COALESCE(analysis_version, '')
+		FROM articles
+		WHERE status = 'enriched' AND analysis_version IS DISTINCT FROM $1 AND analysis_version IS DISTINCT FROM $2
+		  AND id::text > $3
+		ORDER BY id::text
+		LIMIT $4`, version, domain.AnalysisSupplied, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.Article
+		if err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Summary, &a.AnalysisVersion); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetArticleAnalysis replaces the entities, keywords, topics, taxonomy topics, sentiment and tone
+// of the article with a.ID, and the analysis version that produced them
+func (r *Repo) SetArticleAnalysis(ctx context.Context, a *domain.Article) error {
+	topicIDs := a.TopicIDs
+	if topicIDs == nil {
+		topicIDs = []string{}
+	}
+	var fields [4][]byte
+	for i, v := range []interface{}{a.Entities, a.Keywords, a.Topics, topicIDs} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal analysis: %w", err)
+		}
+		fields[i] = data
+	}
+	_, err := r.DB.ExecContext(ctx, `
+		UPDATE articles SET entities = $2, keywords = $3, topics = $4, topic_ids = $5,
+		  sentiment = $6, sentiment_score = $7, tone = $8, analysis_version = $9, analysis_model = $10, updated_at = $11
+		WHERE id = $1`,
+		a.ID, fields[0], fields[1], fields[2], fields[3], a.Sentiment, a.SentimentScore, a.Tone,
+		nullString(a.AnalysisVersion), nullString(a.AnalysisModel), time.Now())
+	return err
+}
+
+// EmbeddingDimensions returns the dimension of the articles.embedding vector column
+func (r *Repo) EmbeddingDimensions(ctx context.Context) (int, error) {
+	var dims int
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = 'articles'::regclass AND attname = 'embedding'`).Scan(&dims)
+	return dims, err
+}
+
+// maxIndexedDimensions is the largest vector pgvector's ivfflat and hnsw indexes accept
+const maxIndexedDimensions = 2000
+
+// ResizeEmbeddings changes the article and passage vector columns to dims dimensions for a new
+// embedding model. Every stored embedding and passage is dropped, so articles must be
+// re-embedded afterwards. The vector indexes are rebuilt when dims allows it.
+func (r *Repo) ResizeEmbeddings(ctx context.Context, dims int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`DROP INDEX IF EXISTS articles_embedding_idx`,
+		`DROP INDEX IF EXISTS article_chunks_embedding_idx`,
+		`DELETE FROM article_chunks`,
+		fmt.Sprintf(`ALTER TABLE articles ALTER COLUMN embedding TYPE vector(%d) USING NULL`, dims),
+		`UPDATE articles SET embedding_model = NULL`,
+		fmt.Sprintf(`ALTER TABLE article_chunks ALTER COLUMN embedding TYPE vector(%d)`, dims),
+	}
+	if dims <= maxIndexedDimensions {
+		// Both tables are empty now; Reindex sizes ivfflat lists once articles are re-embedded
+		for _, idx := range vectorIndexes {
+			create, err := r.VectorIndex.createStatement(idx.table, idx.name, 0)
+			if err != nil {
+				return err
+			}
+			statements = append(statements, create)
+		}
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to resize embeddings: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetArticleByContentHash returns a stored article, other than excludeURL, whose extracted text
+// has the given hash, or nil
+func (r *Repo) GetArticleByContentHash(ctx context.Context, contentHash, excludeURL string) (*domain.Article, error) {
+	a, err := scanArticle(r.DB.QueryRowContext(ctx, `
+	  SELECT `+articleColumns+`
+	  FROM articles
+	  WHERE content_hash = $1 AND status = 'enriched' AND url <> $2
+	  ORDER BY created_at
+	  LIMIT 1`, contentHash, excludeURL))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// AddDuplicate records dup.URL as a near-duplicate of the article dup.CanonicalID instead of
+// storing it as a separate article
+func (r *Repo) AddDuplicate(ctx context.Context, dup *domain.Article, similarity float64) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO article_duplicates (url, title, canonical_id, similarity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (url) DO UPDATE SET
+		  title = EXCLUDED.title, canonical_id = EXCLUDED.canonical_id,
+		  similarity = EXCLUDED.similarity, created_at = CURRENT_TIMESTAMP`,
+		dup.URL, dup.Title, dup.CanonicalID, similarity)
+	return err
+}
+
+// GetDuplicates returns the near-duplicates linked to a canonical article, most recent first.
+// Only ID, URL, Title, CanonicalID and CreatedAt are set.
+func (r *Repo) GetDuplicates(ctx context.Context, canonicalID string) ([]domain.Article, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, url, title, canonical_id, created_at
+		FROM article_duplicates
+		WHERE canonical_id = $1
+		ORDER BY created_at DESC`, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Article
+	for rows.Next() {
+		var a domain.Article
+		if err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.CanonicalID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// GetDuplicateByURL returns the duplicate record for url, or nil if url is not a known duplicate
+func (r *Repo) GetDuplicateByURL(ctx context.Context, url string) (*domain.Article, error) {
+	var a domain.Article
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT id, url, title, canonical_id, created_at
+		FROM article_duplicates WHERE url = $1`, url).
+		Scan(&a.ID, &a.URL, &a.Title, &a.CanonicalID, &a.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ---------- Saved searches and alerts ----------
+
+// CreateTaxonomyTopic stores topic and sets its timestamps, and its ID when empty
+func (r *Repo) CreateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) error {
+	if topic.ID == "" {
+		topic.ID = uuid.New().String()
+	}
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO taxonomy_topics (id, name, description, parent_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING created_at, updated_at`,
+		topic.ID, topic.Name, nullString(topic.Description), nullString(topic.ParentID)).
+		Scan(&topic.CreatedAt, &topic.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrTopicExists
+	}
+	return err
+}
+
+// ListTaxonomyTopics returns every taxonomy topic, by name
+func (r *Repo) ListTaxonomyTopics(ctx context.Context) (out []domain.TaxonomyTopic, err error) {
+	ctx, finish := traceQuery(ctx, "taxonomy_topics")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), COALESCE(parent_id, ''), created_at, updated_at
+		FROM taxonomy_topics ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out = []domain.TaxonomyTopic{}
+	for rows.Next() {
+		var t domain.TaxonomyTopic
+		if err := rows.Scan(&t.ID, &t.Name, &t.Description, &t.ParentID, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// GetTaxonomyTopic returns a taxonomy topic by ID, or nil
+func (r *Repo) GetTaxonomyTopic(ctx context.Context, id string) (*domain.TaxonomyTopic, error) {
+	var t domain.TaxonomyTopic
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), COALESCE(parent_id, ''), created_at, updated_at
+		FROM taxonomy_topics WHERE id = $1`, id).
+		Scan(&t.ID, &t.Name, &t.Description, &t.ParentID, &t.CreatedAt, &t.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdateTaxonomyTopic replaces the name, description and parent of topic by its ID and reports
+// whether it exists. Articles keep their topic IDs.
+func (r *Repo) UpdateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) (bool, error) {
+	err := r.DB.QueryRowContext(ctx, `
+		UPDATE taxonomy_topics SET name = $2, description = $3, parent_id = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING created_at, updated_at`,
+		topic.ID, topic.Name, nullString(topic.Description), nullString(topic.ParentID)).
+		Scan(&topic.CreatedAt, &topic.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteTaxonomyTopic removes a taxonomy topic and its ID from articles, and reports whether it
+// existed. Its subtopics become top-level topics.
+func (r *Repo) DeleteTaxonomyTopic(ctx context.Context, id string) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM taxonomy_topics WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE articles SET topic_ids = topic_ids - $1 WHERE topic_ids ? $1`, id); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// AddArticleTags adds tags, as returned by domain.NormalizeTag, to an article; tags it already
+// has are kept
+func (r *Repo) AddArticleTags(ctx context.Context, articleID string, tags []string) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO article_tags (article_id, tag)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING`, articleID, pq.Array(tags))
+	return err
+}
+
+// RemoveArticleTag removes a tag from an article and reports whether the article had it
+func (r *Repo) RemoveArticleTag(ctx context.Context, articleID, tag string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM article_tags WHERE article_id::text = $1 AND tag = $2`, articleID, tag)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetArticleTags returns an article's tags in alphabetical order
+func (r *Repo) GetArticleTags(ctx context.Context, articleID string) (out []string, err error) {
+	rows, err := r.DB.QueryContext(ctx, `SELECT tag FROM article_tags WHERE article_id::text = $1 ORDER BY tag`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		out = append(out, tag)
+	}
+	return out, rows.Err()
+}
+
+// ListTags returns every tag in use with its article count, most used first
+func (r *Repo) ListTags(ctx context.Context) (out []domain.TagCount, err error) {
+	ctx, finish := traceQuery(ctx, "tags")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT tag, COUNT(*) FROM article_tags GROUP BY tag ORDER BY COUNT(*) DESC, tag`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t domain.TagCount
+		if err := rows.Scan(&t.Tag, &t.Articles); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// AddArticleNote stores note and sets its ID and creation time
+func (r *Repo) AddArticleNote(ctx context.Context, note *domain.ArticleNote) error {
+	return r.DB.QueryRowContext(ctx, `
+		INSERT INTO article_notes (article_id, text) VALUES ($1, $2)
+		RETURNING id, created_at`, note.ArticleID, note.Text).Scan(&note.ID, &note.CreatedAt)
+}
+
+// ListArticleNotes returns an article's notes, oldest first
+func (r *Repo) ListArticleNotes(ctx context.Context, articleID string) (out []domain.ArticleNote, err error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, article_id, text, created_at FROM article_notes
+		WHERE article_id::text = $1 ORDER BY created_at, id`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n domain.ArticleNote
+		if err := rows.Scan(&n.ID, &n.ArticleID, &n.Text, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// DeleteArticleNote removes one of an article's notes and reports whether it existed
+func (r *Repo) DeleteArticleNote(ctx context.Context, articleID, noteID string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM article_notes WHERE id::text = $1 AND article_id::text = $2`, noteID, articleID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// collectionColumns reads a collection with its article count
+const collectionColumns = `c.id, c.name, COALESCE(c.description, ''),
+	(SELECT COUNT(*) FROM collection_articles ca WHERE ca.collection_id = c.id), c.created_at, c.updated_at`
+
+func scanCollection(row interface{ Scan(...any) error }) (domain.Collection, error) {
+	var c domain.Collection
+	err := row.Scan(&c.ID, &c.Name, &c.Description, &c.Articles, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// CreateCollection stores collection and sets its ID and timestamps
+func (r *Repo) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO collections (name, description) VALUES ($1, $2)
+		RETURNING id, created_at, updated_at`,
+		collection.Name, nullString(collection.Description)).
+		Scan(&collection.ID, &collection.CreatedAt, &collection.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrCollectionExists
+	}
+	return err
+}
+
+// ListCollections returns every collection with its article count, by name
+func (r *Repo) ListCollections(ctx context.Context) (out []domain.Collection, err error) {
+	ctx, finish := traceQuery(ctx, "collections")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+collectionColumns+` FROM collections c ORDER BY lower(c.name)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out = []domain.Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// GetCollection returns a collection by ID, or nil
+func (r *Repo) GetCollection(ctx context.Context, id string) (*domain.Collection, error) {
+	c, err := scanCollection(r.DB.QueryRowContext(ctx, `SELECT `+collectionColumns+` FROM collections c WHERE c.id::text = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UpdateCollection replaces the name and description of collection by its ID and reports
+// whether it exists
+func (r *Repo) UpdateCollection(ctx context.Context, collection *domain.Collection) (bool, error) {
+	c, err := scanCollection(r.DB.QueryRowContext(ctx, `
+		UPDATE collections c SET name = $2, description = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE c.id::text = $1
+		RETURNING `+collectionColumns,
+		collection.ID, collection.Name, nullString(collection.Description)))
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case isUniqueViolation(err):
+		return false, ErrCollectionExists
+	case err != nil:
+		return false, err
+	}
+	*collection = c
+	return true, nil
+}
+
+// DeleteCollection removes a collection, but not its articles, and reports whether it existed
+func (r *Repo) DeleteCollection(ctx context.Context, id string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM collections WHERE id::text = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// AddCollectionArticles adds articles to a collection; articles already in it are kept
+func (r *Repo) AddCollectionArticles(ctx context.Context, collectionID string, articleIDs []string) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO collection_articles (collection_id, article_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING`, collectionID, pq.Array(articleIDs))
+	return err
+}
+
+// RemoveCollectionArticle removes an article from a collection and reports whether it was in it
+func (r *Repo) RemoveCollectionArticle(ctx context.Context, collectionID, articleID string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `
+		DELETE FROM collection_articles WHERE collection_id::text = $1 AND article_id::text = $2`,
+		collectionID, articleID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CreateSavedSearch stores search and sets its ID and timestamps; only articles ingested
+// afterwards are evaluated against it
+func (r *Repo) CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) error {
+	return r.DB.QueryRowContext(ctx, `
+		INSERT INTO saved_searches (topic, min_sentiment, max_sentiment)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, checked_at`,
+		search.Topic, search.MinSentiment, search.MaxSentiment).
+		Scan(&search.ID, &search.CreatedAt, &search.CheckedAt)
+}
+
+// ListSavedSearches returns every saved search, oldest first
+func (r *Repo) ListSavedSearches(ctx context.Context) (out []domain.SavedSearch, err error) {
+	ctx, finish := traceQuery(ctx, "saved_searches")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, topic, min_sentiment, max_sentiment, created_at, checked_at
+		FROM saved_searches ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.SavedSearch
+		var minSentiment, maxSentiment sql.NullFloat64
+		if err := rows.Scan(&s.ID, &s.Topic, &minSentiment, &maxSentiment, &s.CreatedAt, &s.CheckedAt); err != nil {
+			return nil, err
+		}
+		if minSentiment.Valid {
+			s.MinSentiment = &minSentiment.Float64
+		}
+		if maxSentiment.Valid {
+			s.MaxSentiment = &maxSentiment.Float64
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// DeleteSavedSearch removes a saved search with its alerts and reports whether it existed
+func (r *Repo) DeleteSavedSearch(ctx context.Context, id string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM saved_searches WHERE id::text = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *Repo) MarkSavedSearchChecked(ctx context.Context, id string, at time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE saved_searches SET checked_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+// AddAlert records a match and sets its ID and creation time; it reports false without error
+// when the article already matched the search
+func (r *Repo) AddAlert(ctx context.Context, alert *domain.Alert) (bool, error) {
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO alerts (search_id, article_id, url, title, sentiment, sentiment_score)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (search_id, article_id) DO NOTHING
+		RETURNING id, created_at`,
+		alert.SearchID, alert.ArticleID, alert.URL, alert.Title, nullString(alert.Sentiment), alert.SentimentScore).
+		Scan(&alert.ID, &alert.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListAlerts returns up to limit alerts, newest first, for one saved search or all when
+// searchID is empty
+func (r *Repo) ListAlerts(ctx context.Context, searchID string, limit int) (out []domain.Alert, err error) {
+	ctx, finish := traceQuery(ctx, "alerts")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, search_id, article_id, url, title, COALESCE(sentiment, ''), COALESCE(sentiment_score, 0), created_at
+		FROM alerts
+		WHERE $1 = '' OR search_id::text = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2`, searchID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.Alert
+		if err := rows.Scan(&a.ID, &a.SearchID, &a.ArticleID, &a.URL, &a.Title, &a.Sentiment, &a.SentimentScore, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ---------- Ingest failures ----------
+
+// RecordIngestFailure stores a failed ingest, or adds it to the URL's existing record, and
+// sets failure to the stored record
+func (r *Repo) RecordIngestFailure(ctx context.Context, failure *domain.IngestFailure) error {
+	var category sql.NullString
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO ingest_failures (url, error, error_category, attempts)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (url) DO UPDATE SET
+			error = EXCLUDED.error,
+			error_category = EXCLUDED.error_category,
+			attempts = ingest_failures.attempts + EXCLUDED.attempts,
+			failures = ingest_failures.failures + 1,
+			last_failed_at = CURRENT_TIMESTAMP
+		RETURNING id, error_category, attempts, failures, first_failed_at, last_failed_at`,
+		failure.URL, failure.Error, nullString(failure.ErrorCategory), failure.Attempts).
+		Scan(&failure.ID, &category, &failure.Attempts, &failure.Failures, &failure.FirstFailedAt, &failure.LastFailedAt)
+	failure.ErrorCategory = category.String
+	return err
+}
+
+// ListIngestFailures returns up to limit failures, most recently failed first, with the given
+// error category or all when category is empty
+func (r *Repo) ListIngestFailures(ctx context.Context, category string, limit int) (out []domain.IngestFailure, err error) {
+	ctx, finish := traceQuery(ctx, "ingest_failures")
+	defer func() { finish(len(out), err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, url, error, COALESCE(error_category, ''), attempts, failures, first_failed_at, last_failed_at
+		FROM ingest_failures
+		WHERE $1 = '' OR error_category = $1
+		ORDER BY last_failed_at DESC, id
+		LIMIT $2`, category, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f domain.IngestFailure
+		if err := rows.Scan(&f.ID, &f.URL, &f.Error, &f.ErrorCategory, &f.Attempts, &f.Failures, &f.FirstFailedAt, &f.LastFailedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// GetIngestFailure returns nil without error when no failure has the ID
+func (r *Repo) GetIngestFailure(ctx context.Context, id string) (*domain.IngestFailure, error) {
+	var f domain.IngestFailure
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT id, url, error, COALESCE(error_category, ''), attempts, failures, first_failed_at, last_failed_at
+		FROM ingest_failures WHERE id::text = $1`, id).
+		Scan(&f.ID, &f.URL, &f.Error, &f.ErrorCategory, &f.Attempts, &f.Failures, &f.FirstFailedAt, &f.LastFailedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// ResolveIngestFailure removes the failure recorded for url and reports whether there was one
+func (r *Repo) ResolveIngestFailure(ctx context.Context, url string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM ingest_failures WHERE url = $1`, url)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ---------- Pending Enrichment ----------
+
+// SavePendingArticle stores or replaces the fetched text of a new article awaiting enrichment
+func (r *Repo) SavePendingArticle(ctx context.Context, pending *domain.PendingArticle) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO pending_articles (url, title, author, section, published_at, content, content_hash, etag, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (url) DO UPDATE SET
+			title = EXCLUDED.title,
+			author = EXCLUDED.author,
+			section = EXCLUDED.section,
+			published_at = EXCLUDED.published_at,
+			content = EXCLUDED.content,
+			content_hash = EXCLUDED.content_hash,
+			etag = EXCLUDED.etag,
+			fetched_at = EXCLUDED.fetched_at`,
+		pending.URL, pending.Title, nullString(pending.Author), nullString(pending.Section), pending.PublishedAt,
+		pending.Content, pending.ContentHash, nullString(pending.ETag), pending.FetchedAt)
+	return err
+}
+
+// GetPendingArticle returns the fetched text awaiting enrichment for url, or nil
+func (r *Repo) GetPendingArticle(ctx context.Context, url string) (*domain.PendingArticle, error) {
+	var p domain.PendingArticle
+	var author, section, etag sql.NullString
+	var publishedAt sql.NullTime
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT url, title, author, section, published_at, content, content_hash, etag, fetched_at
+		FROM pending_articles WHERE url = $1`, url).
+		Scan(&p.URL, &p.Title, &author, &section, &publishedAt, &p.Content, &p.ContentHash, &etag, &p.FetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.Author, p.Section, p.ETag = author.String, section.String, etag.String
+	if publishedAt.Valid {
+		p.PublishedAt = &publishedAt.Time
+	}
+	return &p, nil
+}
+
+// DeletePendingArticle removes the pending text of url, if any
+func (r *Repo) DeletePendingArticle(ctx context.Context, url string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM pending_articles WHERE url = $1`, url)
+	return err
+}
+
+// ListPendingArticleURLs returns the URLs awaiting enrichment, oldest fetch first
+func (r *Repo) ListPendingArticleURLs(ctx context.Context) ([]string, error) {
+	rows, err := r.DB.QueryContext(ctx, `SELECT url FROM pending_articles ORDER BY fetched_at, url`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+// ---------- Chat Cache ----------
+
+// GetChatCache retrieves a cached chat response by request hash
+func (r *Repo) GetChatCache(ctx context.Context, requestHash string) (*domain.ChatCache, error) {
+	query := `SELECT id, request_hash, request_json, response_json, created_at, expires_at
+	          FROM chat_cache WHERE request_hash = $1 AND expires_at > NOW()`
+
+	row := r.queryRow(ctx, query, requestHash)
+
+	var cache domain.ChatCache
+	var requestJSON, responseJSON []byte
+
+	err := row.Scan(&cache.ID, &cache.RequestHash, &requestJSON, &responseJSON,
+		&cache.CreatedAt, &cache.ExpiresAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Cache not found or expired
+		}
+		return nil, err
+	}
+
+	// Parse JSON fields
+	if len(requestJSON) > 0 {
+		_ = json.Unmarshal(requestJSON, &cache.RequestJSON)
+	}
+	if len(responseJSON) > 0 {
+		_ = json.Unmarshal(responseJSON, &cache.ResponseJSON)
+	}
+
+	return &cache, nil
+}
+
+// SetChatCache stores a chat request/response in cache for ttl
+func (r *Repo) SetChatCache(ctx context.Context, requestHash string, request, response interface{}, ttl time.Duration) error {
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	query := `INSERT INTO chat_cache (request_hash, request_json, response_json, expires_at)
+	          VALUES ($1, $2, $3, NOW() + $4 * INTERVAL '1 second')
 	          ON CONFLICT (request_hash) DO UPDATE SET
 	            request_json = EXCLUDED.request_json,
 	            response_json = EXCLUDED.response_json,
 	            expires_at = EXCLUDED.expires_at`
 
-	_, err = r.DB.ExecContext(ctx, query, requestHash, requestJSON, responseJSON)
+	_, err = r.DB.ExecContext(ctx, query, requestHash, requestJSON, responseJSON, ttl.Seconds())
 	return err
 }
 
@@ -459,3 +2392,10 @@ func (r *Repo) CleanExpiredChatCache(ctx context.Context) error {
 	_, err := r.DB.ExecContext(ctx, query)
 	return err
 }
+
+// ClearChatCache removes cache entries whose hash starts with prefix ("" clears all),
+// e.g. after the article corpus changes
+func (r *Repo) ClearChatCache(ctx context.Context, prefix string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM chat_cache WHERE left(request_hash, length($1)) = $1`, prefix)
+	return err
+}
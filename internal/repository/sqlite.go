@@ -0,0 +1,1014 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"article-assistant/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// sqliteSchema is created on open; articles are stored as JSON since every query runs in memory
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS articles (
+  url  TEXT PRIMARY KEY,
+  data TEXT NOT NULL,
+  etag TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS article_chunks (
+  article_id  TEXT NOT NULL,
+  chunk_index INTEGER NOT NULL,
+  text        TEXT NOT NULL,
+  embedding   TEXT NOT NULL,
+  PRIMARY KEY (article_id, chunk_index)
+);
+CREATE TABLE IF NOT EXISTS article_duplicates (
+  url          TEXT PRIMARY KEY,
+  title        TEXT NOT NULL DEFAULT '',
+  canonical_id TEXT NOT NULL,
+  similarity   REAL NOT NULL
+);
+CREATE TABLE IF NOT EXISTS article_simplifications (
+  article_id TEXT NOT NULL,
+  level      TEXT NOT NULL,
+  text       TEXT NOT NULL,
+  PRIMARY KEY (article_id, level)
+);
+CREATE TABLE IF NOT EXISTS taxonomy_topics (
+  id   TEXT PRIMARY KEY,
+  data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS article_tags (
+  article_id TEXT NOT NULL,
+  tag        TEXT NOT NULL,
+  PRIMARY KEY (article_id, tag)
+);
+CREATE TABLE IF NOT EXISTS article_notes (
+  id         TEXT PRIMARY KEY,
+  article_id TEXT NOT NULL,
+  data       TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS collections (
+  id   TEXT PRIMARY KEY,
+  data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS collection_articles (
+  collection_id TEXT NOT NULL,
+  article_id    TEXT NOT NULL,
+  PRIMARY KEY (collection_id, article_id)
+);
+CREATE TABLE IF NOT EXISTS saved_searches (
+  id   TEXT PRIMARY KEY,
+  data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS alerts (
+  id         TEXT PRIMARY KEY,
+  search_id  TEXT NOT NULL,
+  article_id TEXT NOT NULL,
+  data       TEXT NOT NULL,
+  UNIQUE (search_id, article_id)
+);
+CREATE TABLE IF NOT EXISTS ingest_failures (
+  url  TEXT PRIMARY KEY,
+  data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS pending_articles (
+  url  TEXT PRIMARY KEY,
+  data TEXT NOT NULL
+);`
+
+// SQLiteStore is an ArticleStore for running locally without Postgres. Writes go to a SQLite
+// database and the corpus is loaded into a MemoryStore on open, which answers every read with
+// brute-force similarity; cached chat responses are kept in memory only.
+type SQLiteStore struct {
+	*MemoryStore
+	DB *sql.DB
+
+	mu sync.Mutex // Serializes writes so the database and memory apply them in the same order
+}
+
+var _ ArticleStore = (*SQLiteStore)(nil)
+
+// NewSQLiteStore creates the schema if needed and loads the stored corpus
+func NewSQLiteStore(ctx context.Context, db *sql.DB) (*SQLiteStore, error) {
+	// SQLite allows one writer; a single connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+	s := &SQLiteStore{MemoryStore: NewMemoryStore(), DB: db}
+	if err := s.load(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load SQLite store: %w", err)
+	}
+	return s, nil
+}
+
+// load copies every stored row into memory
+func (s *SQLiteStore) load(ctx context.Context) error {
+	rows, err := s.DB.QueryContext(ctx, `SELECT data, etag FROM articles`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data, etag string
+		if err := rows.Scan(&data, &etag); err != nil {
+			return err
+		}
+		var a domain.Article
+		if err := json.Unmarshal([]byte(data), &a); err != nil {
+			return fmt.Errorf("failed to decode article: %w", err)
+		}
+		a.ETag = etag
+		s.put(a)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	chunks := make(map[string][]memoryChunk)
+	rows, err = s.DB.QueryContext(ctx, `SELECT article_id, text, embedding FROM article_chunks ORDER BY article_id, chunk_index`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, text, embedding string
+		if err := rows.Scan(&id, &text, &embedding); err != nil {
+			return err
+		}
+		c := memoryChunk{text: text}
+		if err := json.Unmarshal([]byte(embedding), &c.embedding); err != nil {
+			return fmt.Errorf("failed to decode passage embedding: %w", err)
+		}
+		chunks[id] = append(chunks[id], c)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT url, title, canonical_id, similarity FROM article_duplicates`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var dup domain.Article
+		var similarity float64
+		if err := rows.Scan(&dup.URL, &dup.Title, &dup.CanonicalID, &similarity); err != nil {
+			return err
+		}
+		s.MemoryStore.AddDuplicate(ctx, &dup, similarity)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT article_id, level, text FROM article_simplifications`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, level, text string
+		if err := rows.Scan(&id, &level, &text); err != nil {
+			return err
+		}
+		s.MemoryStore.SetSimplifiedSummary(ctx, id, level, text)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM taxonomy_topics`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var topic domain.TaxonomyTopic
+		if err := json.Unmarshal([]byte(data), &topic); err != nil {
+			return fmt.Errorf("failed to decode taxonomy topic: %w", err)
+		}
+		s.putTaxonomyTopic(topic)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT article_id, tag FROM article_tags`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return err
+		}
+		s.MemoryStore.AddArticleTags(ctx, id, []string{tag})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM article_notes ORDER BY rowid`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var note domain.ArticleNote
+		if err := json.Unmarshal([]byte(data), &note); err != nil {
+			return fmt.Errorf("failed to decode article note: %w", err)
+		}
+		s.putArticleNote(note)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM collections`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var collection domain.Collection
+		if err := json.Unmarshal([]byte(data), &collection); err != nil {
+			return fmt.Errorf("failed to decode collection: %w", err)
+		}
+		s.putCollection(collection)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT collection_id, article_id FROM collection_articles`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var collectionID, articleID string
+		if err := rows.Scan(&collectionID, &articleID); err != nil {
+			return err
+		}
+		s.MemoryStore.AddCollectionArticles(ctx, collectionID, []string{articleID})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM saved_searches`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var search domain.SavedSearch
+		if err := json.Unmarshal([]byte(data), &search); err != nil {
+			return fmt.Errorf("failed to decode saved search: %w", err)
+		}
+		s.putSavedSearch(search)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM alerts ORDER BY rowid`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var alert domain.Alert
+		if err := json.Unmarshal([]byte(data), &alert); err != nil {
+			return fmt.Errorf("failed to decode alert: %w", err)
+		}
+		s.putAlert(alert)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM ingest_failures`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var failure domain.IngestFailure
+		if err := json.Unmarshal([]byte(data), &failure); err != nil {
+			return fmt.Errorf("failed to decode ingest failure: %w", err)
+		}
+		s.putIngestFailure(failure)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, `SELECT data FROM pending_articles`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var pending domain.PendingArticle
+		if err := json.Unmarshal([]byte(data), &pending); err != nil {
+			return fmt.Errorf("failed to decode pending article: %w", err)
+		}
+		s.MemoryStore.SavePendingArticle(ctx, &pending)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	s.MemoryStore.mu.Lock()
+	s.MemoryStore.chunks = chunks
+	s.MemoryStore.mu.Unlock()
+	return nil
+}
+
+// saveArticle writes article and then makes it visible to reads
+func (s *SQLiteStore) saveArticle(ctx context.Context, article domain.Article) error {
+	data, err := json.Marshal(article)
+	if err != nil {
+		return fmt.Errorf("failed to marshal article: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx,
+		`INSERT INTO articles (url, data, etag) VALUES (?, ?, ?)
+		 ON CONFLICT(url) DO UPDATE SET data = excluded.data, etag = excluded.etag`,
+		article.URL, string(data), article.ETag)
+	if err != nil {
+		return err
+	}
+	s.put(article)
+	return nil
+}
+
+// UpsertArticle stores article by URL; an existing article keeps its ID, which is reported back
+func (s *SQLiteStore) UpsertArticle(ctx context.Context, article *domain.Article) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	article.CreatedAt, article.UpdatedAt = now, now
+	if article.Status == "" {
+		article.Status = domain.ArticleEnriched
+	}
+	stored := *article
+	existing, _ := s.MemoryStore.GetArticleByURL(ctx, article.URL)
+	if existing != nil {
+		stored.ID, stored.CreatedAt = existing.ID, existing.CreatedAt
+	}
+	if stored.ID == "" {
+		stored.ID = uuid.New().String()
+	}
+	if err := s.saveArticle(ctx, stored); err != nil {
+		return err
+	}
+	article.ID = stored.ID
+	return nil
+}
+
+func (s *SQLiteStore) MarkArticleRefreshed(ctx context.Context, url, contentHash, etag string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, _ := s.MemoryStore.GetArticleByURL(ctx, url)
+	if a == nil {
+		return nil
+	}
+	a.LastRefreshedAt = &at
+	if contentHash != "" {
+		a.ContentHash = contentHash
+	}
+	if etag != "" {
+		a.ETag = etag
+	}
+	return s.saveArticle(ctx, *a)
+}
+
+func (s *SQLiteStore) SetArticleStatus(ctx context.Context, url, status, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, _ := s.MemoryStore.GetArticleByURL(ctx, url)
+	if a == nil {
+		return nil
+	}
+	a.Status, a.StatusError, a.UpdatedAt = status, reason, time.Now()
+	return s.saveArticle(ctx, *a)
+}
+
+// DeleteArticleByURL removes an article with its passages, rewrites and duplicates, or a
+// recorded duplicate URL; it reports whether anything was deleted
+func (s *SQLiteStore) DeleteArticleByURL(ctx context.Context, url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if a, _ := s.MemoryStore.GetArticleByURL(ctx, url); a != nil {
+		for _, q := range []string{
+			`DELETE FROM article_chunks WHERE article_id = ?`,
+			`DELETE FROM article_simplifications WHERE article_id = ?`,
+			`DELETE FROM article_tags WHERE article_id = ?`,
+			`DELETE FROM article_notes WHERE article_id = ?`,
+			`DELETE FROM collection_articles WHERE article_id = ?`,
+			`DELETE FROM article_duplicates WHERE canonical_id = ?`,
+		} {
+			if _, err := tx.ExecContext(ctx, q, a.ID); err != nil {
+				return false, err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM articles WHERE url = ?`, url); err != nil {
+			return false, err
+		}
+	} else if _, err := tx.ExecContext(ctx, `DELETE FROM article_duplicates WHERE url = ?`, url); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.DeleteArticleByURL(ctx, url)
+}
+
+func (s *SQLiteStore) ReplaceArticleChunks(ctx context.Context, articleID string, chunks []string, embeddings [][]float32) error {
+	if len(chunks) != len(embeddings) {
+		return fmt.Errorf("got %d chunks but %d embeddings", len(chunks), len(embeddings))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_chunks WHERE article_id = ?`, articleID); err != nil {
+		return err
+	}
+	for i, text := range chunks {
+		embedding, err := json.Marshal(embeddings[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal passage embedding: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO article_chunks (article_id, chunk_index, text, embedding) VALUES (?, ?, ?, ?)`,
+			articleID, i, text, string(embedding)); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.MemoryStore.ReplaceArticleChunks(ctx, articleID, chunks, embeddings)
+}
+
+func (s *SQLiteStore) AddDuplicate(ctx context.Context, dup *domain.Article, similarity float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO article_duplicates (url, title, canonical_id, similarity) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(url) DO UPDATE SET title = excluded.title, canonical_id = excluded.canonical_id, similarity = excluded.similarity`,
+		dup.URL, dup.Title, dup.CanonicalID, similarity)
+	if err != nil {
+		return err
+	}
+	return s.MemoryStore.AddDuplicate(ctx, dup, similarity)
+}
+
+func (s *SQLiteStore) SetArticleEmbedding(ctx context.Context, id string, embedding []float32, model string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.mu.RLock()
+	var a domain.Article
+	stored := s.MemoryStore.articleByID(id)
+	if stored != nil {
+		a = *stored
+	}
+	s.MemoryStore.mu.RUnlock()
+	if stored == nil {
+		return nil
+	}
+	a.Embedding, a.EmbeddingModel = embedding, model
+	return s.saveArticle(ctx, a)
+}
+
+func (s *SQLiteStore) SetArticleAnalysis(ctx context.Context, a *domain.Article) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.mu.RLock()
+	var stored domain.Article
+	found := s.MemoryStore.articleByID(a.ID)
+	if found != nil {
+		stored = *found
+	}
+	s.MemoryStore.mu.RUnlock()
+	if found == nil {
+		return nil
+	}
+	setAnalysis(&stored, a)
+	return s.saveArticle(ctx, stored)
+}
+
+func (s *SQLiteStore) SetSimplifiedSummary(ctx context.Context, articleID, level, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO article_simplifications (article_id, level, text) VALUES (?, ?, ?)
+		 ON CONFLICT(article_id, level) DO UPDATE SET text = excluded.text`,
+		articleID, level, text)
+	if err != nil {
+		return err
+	}
+	return s.MemoryStore.SetSimplifiedSummary(ctx, articleID, level, text)
+}
+
+func (s *SQLiteStore) ClearSimplifiedSummaries(ctx context.Context, url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, _ := s.MemoryStore.GetArticleByURL(ctx, url)
+	if a == nil {
+		return nil
+	}
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM article_simplifications WHERE article_id = ?`, a.ID); err != nil {
+		return err
+	}
+	return s.MemoryStore.ClearSimplifiedSummaries(ctx, url)
+}
+
+// saveTaxonomyTopic writes topic and then makes it visible to reads
+func (s *SQLiteStore) saveTaxonomyTopic(ctx context.Context, topic domain.TaxonomyTopic) error {
+	data, err := json.Marshal(topic)
+	if err != nil {
+		return fmt.Errorf("failed to marshal taxonomy topic: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx,
+		`INSERT INTO taxonomy_topics (id, data) VALUES (?, ?)
+		 ON CONFLICT(id) DO UPDATE SET data = excluded.data`,
+		topic.ID, string(data))
+	if err != nil {
+		return err
+	}
+	s.putTaxonomyTopic(topic)
+	return nil
+}
+
+func (s *SQLiteStore) CreateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *topic
+	if stored.ID == "" {
+		stored.ID = uuid.New().String()
+	}
+	if existing, _ := s.MemoryStore.GetTaxonomyTopic(ctx, stored.ID); existing != nil {
+		return ErrTopicExists
+	}
+	now := time.Now()
+	stored.CreatedAt, stored.UpdatedAt = now, now
+	if err := s.saveTaxonomyTopic(ctx, stored); err != nil {
+		return err
+	}
+	*topic = stored
+	return nil
+}
+
+func (s *SQLiteStore) UpdateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, _ := s.MemoryStore.GetTaxonomyTopic(ctx, topic.ID)
+	if existing == nil {
+		return false, nil
+	}
+	stored := *topic
+	stored.CreatedAt, stored.UpdatedAt = existing.CreatedAt, time.Now()
+	if err := s.saveTaxonomyTopic(ctx, stored); err != nil {
+		return false, err
+	}
+	*topic = stored
+	return true, nil
+}
+
+// DeleteTaxonomyTopic removes a taxonomy topic, then rewrites its subtopics and the articles
+// mapped to it as the MemoryStore left them
+func (s *SQLiteStore) DeleteTaxonomyTopic(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM taxonomy_topics WHERE id = ?`, id); err != nil {
+		return false, err
+	}
+	s.MemoryStore.mu.RLock()
+	var children []string
+	for _, t := range s.MemoryStore.topics {
+		if t.ParentID == id {
+			children = append(children, t.ID)
+		}
+	}
+	var urls []string
+	for _, a := range s.MemoryStore.articles {
+		if hasTopicID(a, map[string]bool{id: true}) {
+			urls = append(urls, a.URL)
+		}
+	}
+	s.MemoryStore.mu.RUnlock()
+
+	deleted, err := s.MemoryStore.DeleteTaxonomyTopic(ctx, id)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+	for _, child := range children {
+		if t, _ := s.MemoryStore.GetTaxonomyTopic(ctx, child); t != nil {
+			if err := s.saveTaxonomyTopic(ctx, *t); err != nil {
+				return true, err
+			}
+		}
+	}
+	for _, url := range urls {
+		if a, _ := s.MemoryStore.GetArticleByURL(ctx, url); a != nil {
+			if err := s.saveArticle(ctx, *a); err != nil {
+				return true, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func (s *SQLiteStore) AddArticleTags(ctx context.Context, articleID string, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO article_tags (article_id, tag) VALUES (?, ?)`, articleID, tag); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.MemoryStore.AddArticleTags(ctx, articleID, tags)
+}
+
+func (s *SQLiteStore) RemoveArticleTag(ctx context.Context, articleID, tag string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM article_tags WHERE article_id = ? AND tag = ?`, articleID, tag); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.RemoveArticleTag(ctx, articleID, tag)
+}
+
+func (s *SQLiteStore) AddArticleNote(ctx context.Context, note *domain.ArticleNote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *note
+	stored.ID, stored.CreatedAt = uuid.New().String(), time.Now()
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal article note: %w", err)
+	}
+	if _, err := s.DB.ExecContext(ctx, `INSERT INTO article_notes (id, article_id, data) VALUES (?, ?, ?)`,
+		stored.ID, stored.ArticleID, string(data)); err != nil {
+		return err
+	}
+	s.putArticleNote(stored)
+	*note = stored
+	return nil
+}
+
+func (s *SQLiteStore) DeleteArticleNote(ctx context.Context, articleID, noteID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM article_notes WHERE id = ? AND article_id = ?`, noteID, articleID); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.DeleteArticleNote(ctx, articleID, noteID)
+}
+
+// saveCollection writes collection and then makes it visible to reads
+func (s *SQLiteStore) saveCollection(ctx context.Context, collection domain.Collection) error {
+	collection.Articles = 0 // Counted from the members on read
+	data, err := json.Marshal(collection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal collection: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx,
+		`INSERT INTO collections (id, data) VALUES (?, ?)
+		 ON CONFLICT(id) DO UPDATE SET data = excluded.data`,
+		collection.ID, string(data))
+	if err != nil {
+		return err
+	}
+	s.putCollection(collection)
+	return nil
+}
+
+func (s *SQLiteStore) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.mu.RLock()
+	taken := s.MemoryStore.nameTaken(collection.Name, "")
+	s.MemoryStore.mu.RUnlock()
+	if taken {
+		return ErrCollectionExists
+	}
+	stored := *collection
+	now := time.Now()
+	stored.ID, stored.Articles = uuid.New().String(), 0
+	stored.CreatedAt, stored.UpdatedAt = now, now
+	if err := s.saveCollection(ctx, stored); err != nil {
+		return err
+	}
+	*collection = stored
+	return nil
+}
+
+func (s *SQLiteStore) UpdateCollection(ctx context.Context, collection *domain.Collection) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, _ := s.MemoryStore.GetCollection(ctx, collection.ID)
+	if existing == nil {
+		return false, nil
+	}
+	s.MemoryStore.mu.RLock()
+	taken := s.MemoryStore.nameTaken(collection.Name, collection.ID)
+	s.MemoryStore.mu.RUnlock()
+	if taken {
+		return false, ErrCollectionExists
+	}
+	stored := *existing
+	stored.Name, stored.Description, stored.UpdatedAt = collection.Name, collection.Description, time.Now()
+	if err := s.saveCollection(ctx, stored); err != nil {
+		return false, err
+	}
+	*collection = stored
+	return true, nil
+}
+
+func (s *SQLiteStore) DeleteCollection(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	for _, q := range []string{
+		`DELETE FROM collection_articles WHERE collection_id = ?`,
+		`DELETE FROM collections WHERE id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.DeleteCollection(ctx, id)
+}
+
+func (s *SQLiteStore) AddCollectionArticles(ctx context.Context, collectionID string, articleIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, id := range articleIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO collection_articles (collection_id, article_id) VALUES (?, ?)`, collectionID, id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.MemoryStore.AddCollectionArticles(ctx, collectionID, articleIDs)
+}
+
+func (s *SQLiteStore) RemoveCollectionArticle(ctx context.Context, collectionID, articleID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM collection_articles WHERE collection_id = ? AND article_id = ?`, collectionID, articleID); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.RemoveCollectionArticle(ctx, collectionID, articleID)
+}
+
+// saveSavedSearch writes search and then makes it visible to reads
+func (s *SQLiteStore) saveSavedSearch(ctx context.Context, search domain.SavedSearch) error {
+	data, err := json.Marshal(search)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved search: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx,
+		`INSERT INTO saved_searches (id, data) VALUES (?, ?)
+		 ON CONFLICT(id) DO UPDATE SET data = excluded.data`,
+		search.ID, string(data))
+	if err != nil {
+		return err
+	}
+	s.putSavedSearch(search)
+	return nil
+}
+
+func (s *SQLiteStore) CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *search
+	now := time.Now()
+	stored.ID, stored.CreatedAt, stored.CheckedAt = uuid.New().String(), now, now
+	if err := s.saveSavedSearch(ctx, stored); err != nil {
+		return err
+	}
+	*search = stored
+	return nil
+}
+
+func (s *SQLiteStore) DeleteSavedSearch(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM alerts WHERE search_id = ?`, id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ?`, id); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.DeleteSavedSearch(ctx, id)
+}
+
+func (s *SQLiteStore) MarkSavedSearchChecked(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.mu.RLock()
+	existing, ok := s.MemoryStore.searches[id]
+	var search domain.SavedSearch
+	if ok {
+		search = *existing
+	}
+	s.MemoryStore.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	search.CheckedAt = at
+	return s.saveSavedSearch(ctx, search)
+}
+
+// AddAlert records a match unless the article already matched the search
+func (s *SQLiteStore) AddAlert(ctx context.Context, alert *domain.Alert) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *alert
+	stored.ID, stored.CreatedAt = uuid.New().String(), time.Now()
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	res, err := s.DB.ExecContext(ctx,
+		`INSERT INTO alerts (id, search_id, article_id, data) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(search_id, article_id) DO NOTHING`,
+		stored.ID, stored.SearchID, stored.ArticleID, string(data))
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	s.putAlert(stored)
+	alert.ID, alert.CreatedAt = stored.ID, stored.CreatedAt
+	return true, nil
+}
+
+func (s *SQLiteStore) RecordIngestFailure(ctx context.Context, failure *domain.IngestFailure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.mu.RLock()
+	stored := s.mergeIngestFailure(*failure)
+	s.MemoryStore.mu.RUnlock()
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingest failure: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx,
+		`INSERT INTO ingest_failures (url, data) VALUES (?, ?)
+		 ON CONFLICT(url) DO UPDATE SET data = excluded.data`,
+		stored.URL, string(data))
+	if err != nil {
+		return err
+	}
+	s.putIngestFailure(stored)
+	*failure = stored
+	return nil
+}
+
+func (s *SQLiteStore) ResolveIngestFailure(ctx context.Context, url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM ingest_failures WHERE url = ?`, url); err != nil {
+		return false, err
+	}
+	return s.MemoryStore.ResolveIngestFailure(ctx, url)
+}
+
+func (s *SQLiteStore) SavePendingArticle(ctx context.Context, pending *domain.PendingArticle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending article: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx,
+		`INSERT INTO pending_articles (url, data) VALUES (?, ?)
+		 ON CONFLICT(url) DO UPDATE SET data = excluded.data`,
+		pending.URL, string(data))
+	if err != nil {
+		return err
+	}
+	return s.MemoryStore.SavePendingArticle(ctx, pending)
+}
+
+func (s *SQLiteStore) DeletePendingArticle(ctx context.Context, url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM pending_articles WHERE url = ?`, url); err != nil {
+		return err
+	}
+	return s.MemoryStore.DeletePendingArticle(ctx, url)
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"article-assistant/internal/domain"
+)
+
+// ErrTopicExists is returned when creating a taxonomy topic with an ID already in use
+var ErrTopicExists = errors.New("taxonomy topic already exists")
+
+// ErrCollectionExists is returned when creating or renaming a collection to a name already in
+// use, compared case-insensitively
+var ErrCollectionExists = errors.New("collection already exists")
+
+// ArticleStore is the storage used by the executor, ingestion, the chat cache and the HTTP
+// handlers. Repo implements it on Postgres with pgvector; SQLiteStore persists to a local file
+// for running without Docker, and MemoryStore keeps everything in process for tests and the
+// no-database "lite" mode. WeaviateStore wraps any of them to serve vector search from Weaviate.
+type ArticleStore interface {
+	// Articles
+	GetArticleByURL(ctx context.Context, url string) (*domain.Article, error)
+	GetArticleByID(ctx context.Context, id string) (*domain.Article, error)
+	GetArticlesByURLs(ctx context.Context, urls []string) ([]domain.Article, error)
+	GetExistingURLs(ctx context.Context, urls []string) (map[string]bool, error)
+	GetArticleURLs(ctx context.Context, urls []string, filter domain.ArticleFilter) ([]string, error)
+	GetArticleContentsByURLs(ctx context.Context, urls []string) (map[string]string, error)
+	ListArticles(ctx context.Context, filter domain.ArticleFilter, limit, offset int, sort string, desc bool) ([]domain.ArticleListItem, int, error)
+	ListAllArticles(ctx context.Context, opts domain.ArticleListOptions) ([]domain.Article, error)
+	CountArticles(ctx context.Context, filter domain.ArticleFilter) (int, error)
+	StreamArticles(ctx context.Context, filter domain.ArticleFilter, withEmbeddings bool, fn func(domain.Article) error) error
+	UpsertArticle(ctx context.Context, article *domain.Article) error
+	DeleteArticleByURL(ctx context.Context, url string) (bool, error)
+
+	// Search
+	GetArticlesByVectorSearchWithFilter(ctx context.Context, queryEmbedding []float32, limit int, urls []string, filter domain.ArticleFilter) ([]domain.Article, error)
+	GetArticlesByHybridSearch(ctx context.Context, queryEmbedding []float32, queryText string, limit, offset int, urls []string, filter domain.ArticleFilter, vectorWeight float64) ([]domain.Article, int, error)
+	GetArticlesBySentiment(ctx context.Context, topicEmbedding []float32, candidates int, urls []string, filter domain.ArticleFilter, sq domain.SentimentQuery) ([]domain.Article, error)
+	GetArticlesByTone(ctx context.Context, tone string, urls []string, limit int) ([]domain.Article, error)
+	ReplaceArticleChunks(ctx context.Context, articleID string, chunks []string, embeddings [][]float32) error
+	SearchArticleChunks(ctx context.Context, queryEmbedding []float32, limit int, urls []string) ([]domain.ArticleChunk, error)
+
+	// Corpus analysis
+	GetTopEntities(ctx context.Context, limit int, urls []string, category string) ([]domain.TopEntity, error)
+	GetKeywordsAndTopics(ctx context.Context, urls []string, limit int) ([]domain.TermAggregate, []domain.TermAggregate, error)
+	GetArticleEmbeddings(ctx context.Context, limit int) ([]domain.Article, error)
+	GetEntityGraph(ctx context.Context, maxNodes, minShared int, urls []string) (*domain.EntityGraph, error)
+	GetEntityDetail(ctx context.Context, key, interval string, related int) (*domain.EntityDetail, error)
+	ListEntityArticles(ctx context.Context, key string, limit, offset int) ([]domain.ArticleListItem, int, error)
+	GetSentimentTrend(ctx context.Context, topic string, urls []string, since time.Time, interval string) ([]domain.SentimentBucket, error)
+	GetToneCounts(ctx context.Context, urls []string) ([]domain.ToneCount, error)
+
+	// Refresh and deduplication
+	GetStaleArticles(ctx context.Context, cutoff time.Time, limit int) ([]domain.Article, error)
+	MarkArticleRefreshed(ctx context.Context, url, contentHash, etag string, at time.Time) error
+	SetArticleStatus(ctx context.Context, url, status, reason string) error
+	FindNearDuplicate(ctx context.Context, embedding []float32, excludeURL string, minSimilarity float64) (*domain.Article, float64, error)
+	GetArticleByContentHash(ctx context.Context, contentHash, excludeURL string) (*domain.Article, error)
+	AddDuplicate(ctx context.Context, dup *domain.Article, similarity float64) error
+	GetDuplicateByURL(ctx context.Context, url string) (*domain.Article, error)
+
+	// Embedding migration
+	CountEmbeddingModels(ctx context.Context) ([]domain.EmbeddingModelCount, error)
+	ListArticlesToReembed(ctx context.Context, model, afterID string, limit int) ([]domain.Article, error)
+	SetArticleEmbedding(ctx context.Context, id string, embedding []float32, model string) error
+
+	// Re-analysis
+	CountAnalysisVersions(ctx context.Context) ([]domain.AnalysisVersionCount, error)
+	ListArticlesToReanalyze(ctx context.Context, version, afterID string, limit int) ([]domain.Article, error)
+	SetArticleAnalysis(ctx context.Context, a *domain.Article) error
+
+	// Reading-level rewrites
+	GetSimplifiedSummary(ctx context.Context, articleID, level string) (string, error)
+	SetSimplifiedSummary(ctx context.Context, articleID, level, text string) error
+	ClearSimplifiedSummaries(ctx context.Context, url string) error
+
+	// Topic taxonomy
+	CreateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) error
+	ListTaxonomyTopics(ctx context.Context) ([]domain.TaxonomyTopic, error)
+	GetTaxonomyTopic(ctx context.Context, id string) (*domain.TaxonomyTopic, error)
+	UpdateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) (bool, error)
+	DeleteTaxonomyTopic(ctx context.Context, id string) (bool, error)
+
+	// User tags and notes
+	AddArticleTags(ctx context.Context, articleID string, tags []string) error
+	RemoveArticleTag(ctx context.Context, articleID, tag string) (bool, error)
+	GetArticleTags(ctx context.Context, articleID string) ([]string, error)
+	ListTags(ctx context.Context) ([]domain.TagCount, error)
+	AddArticleNote(ctx context.Context, note *domain.ArticleNote) error
+	ListArticleNotes(ctx context.Context, articleID string) ([]domain.ArticleNote, error)
+	DeleteArticleNote(ctx context.Context, articleID, noteID string) (bool, error)
+
+	// Collections
+	CreateCollection(ctx context.Context, collection *domain.Collection) error
+	ListCollections(ctx context.Context) ([]domain.Collection, error)
+	GetCollection(ctx context.Context, id string) (*domain.Collection, error)
+	UpdateCollection(ctx context.Context, collection *domain.Collection) (bool, error)
+	DeleteCollection(ctx context.Context, id string) (bool, error)
+	AddCollectionArticles(ctx context.Context, collectionID string, articleIDs []string) error
+	RemoveCollectionArticle(ctx context.Context, collectionID, articleID string) (bool, error)
+
+	// Saved searches and alerts
+	CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) error
+	ListSavedSearches(ctx context.Context) ([]domain.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string) (bool, error)
+	MarkSavedSearchChecked(ctx context.Context, id string, at time.Time) error
+	AddAlert(ctx context.Context, alert *domain.Alert) (bool, error)
+	ListAlerts(ctx context.Context, searchID string, limit int) ([]domain.Alert, error)
+
+	// Ingest failures
+	RecordIngestFailure(ctx context.Context, failure *domain.IngestFailure) error
+	ListIngestFailures(ctx context.Context, category string, limit int) ([]domain.IngestFailure, error)
+	GetIngestFailure(ctx context.Context, id string) (*domain.IngestFailure, error)
+	ResolveIngestFailure(ctx context.Context, url string) (bool, error)
+
+	// Fetched articles waiting for enrichment
+	SavePendingArticle(ctx context.Context, pending *domain.PendingArticle) error
+	GetPendingArticle(ctx context.Context, url string) (*domain.PendingArticle, error)
+	DeletePendingArticle(ctx context.Context, url string) error
+	ListPendingArticleURLs(ctx context.Context) ([]string, error)
+
+	// Chat cache
+	GetChatCache(ctx context.Context, requestHash string) (*domain.ChatCache, error)
+	SetChatCache(ctx context.Context, requestHash string, request, response interface{}, ttl time.Duration) error
+	CleanExpiredChatCache(ctx context.Context) error
+	ClearChatCache(ctx context.Context, prefix string) error
+}
+
+var _ ArticleStore = (*Repo)(nil)
@@ -0,0 +1,672 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/tenant"
+)
+
+// TenantStore routes every call to the store of the tenant in the call's context, so tenants
+// sharing a deployment each read and write only their own articles, cache, tags, collections,
+// saved searches and failures. Contexts naming no configured tenant get an error.
+type TenantStore struct {
+	stores map[string]ArticleStore
+}
+
+var _ ArticleStore = (*TenantStore)(nil)
+
+// ForTenants returns a store routing calls by tenant across stores, keyed by tenant ID; the
+// only store is returned as is when there is one
+func ForTenants(stores map[string]ArticleStore) ArticleStore {
+	if len(stores) == 1 {
+		for _, s := range stores {
+			return s
+		}
+	}
+	return &TenantStore{stores: stores}
+}
+
+// store returns the store of ctx's tenant
+func (t *TenantStore) store(ctx context.Context) (ArticleStore, error) {
+	id := tenant.FromContext(ctx)
+	s, ok := t.stores[id]
+	if !ok {
+		return nil, fmt.Errorf("no store for tenant %q", id)
+	}
+	return s, nil
+}
+
+func (t *TenantStore) GetArticleByURL(ctx context.Context, url string) (*domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleByURL(ctx, url)
+}
+
+func (t *TenantStore) GetArticleByID(ctx context.Context, id string) (*domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleByID(ctx, id)
+}
+
+func (t *TenantStore) GetArticlesByURLs(ctx context.Context, urls []string) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticlesByURLs(ctx, urls)
+}
+
+func (t *TenantStore) GetExistingURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExistingURLs(ctx, urls)
+}
+
+func (t *TenantStore) GetArticleURLs(ctx context.Context, urls []string, filter domain.ArticleFilter) ([]string, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleURLs(ctx, urls, filter)
+}
+
+func (t *TenantStore) GetArticleContentsByURLs(ctx context.Context, urls []string) (map[string]string, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleContentsByURLs(ctx, urls)
+}
+
+func (t *TenantStore) ListArticles(ctx context.Context, filter domain.ArticleFilter, limit, offset int, sort string, desc bool) ([]domain.ArticleListItem, int, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.ListArticles(ctx, filter, limit, offset, sort, desc)
+}
+
+func (t *TenantStore) ListAllArticles(ctx context.Context, opts domain.ArticleListOptions) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListAllArticles(ctx, opts)
+}
+
+func (t *TenantStore) CountArticles(ctx context.Context, filter domain.ArticleFilter) (int, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.CountArticles(ctx, filter)
+}
+
+func (t *TenantStore) StreamArticles(ctx context.Context, filter domain.ArticleFilter, withEmbeddings bool, fn func(domain.Article) error) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.StreamArticles(ctx, filter, withEmbeddings, fn)
+}
+
+func (t *TenantStore) UpsertArticle(ctx context.Context, article *domain.Article) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.UpsertArticle(ctx, article)
+}
+
+func (t *TenantStore) DeleteArticleByURL(ctx context.Context, url string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.DeleteArticleByURL(ctx, url)
+}
+
+func (t *TenantStore) GetArticlesByVectorSearchWithFilter(ctx context.Context, queryEmbedding []float32, limit int, urls []string, filter domain.ArticleFilter) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticlesByVectorSearchWithFilter(ctx, queryEmbedding, limit, urls, filter)
+}
+
+func (t *TenantStore) GetArticlesByHybridSearch(ctx context.Context, queryEmbedding []float32, queryText string, limit, offset int, urls []string, filter domain.ArticleFilter, vectorWeight float64) ([]domain.Article, int, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.GetArticlesByHybridSearch(ctx, queryEmbedding, queryText, limit, offset, urls, filter, vectorWeight)
+}
+
+func (t *TenantStore) GetArticlesBySentiment(ctx context.Context, topicEmbedding []float32, candidates int, urls []string, filter domain.ArticleFilter, sq domain.SentimentQuery) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticlesBySentiment(ctx, topicEmbedding, candidates, urls, filter, sq)
+}
+
+func (t *TenantStore) GetArticlesByTone(ctx context.Context, tone string, urls []string, limit int) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticlesByTone(ctx, tone, urls, limit)
+}
+
+func (t *TenantStore) ReplaceArticleChunks(ctx context.Context, articleID string, chunks []string, embeddings [][]float32) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.ReplaceArticleChunks(ctx, articleID, chunks, embeddings)
+}
+
+func (t *TenantStore) SearchArticleChunks(ctx context.Context, queryEmbedding []float32, limit int, urls []string) ([]domain.ArticleChunk, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.SearchArticleChunks(ctx, queryEmbedding, limit, urls)
+}
+
+func (t *TenantStore) GetTopEntities(ctx context.Context, limit int, urls []string, category string) ([]domain.TopEntity, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTopEntities(ctx, limit, urls, category)
+}
+
+func (t *TenantStore) GetKeywordsAndTopics(ctx context.Context, urls []string, limit int) ([]domain.TermAggregate, []domain.TermAggregate, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.GetKeywordsAndTopics(ctx, urls, limit)
+}
+
+func (t *TenantStore) GetArticleEmbeddings(ctx context.Context, limit int) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleEmbeddings(ctx, limit)
+}
+
+func (t *TenantStore) GetEntityGraph(ctx context.Context, maxNodes, minShared int, urls []string) (*domain.EntityGraph, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEntityGraph(ctx, maxNodes, minShared, urls)
+}
+
+func (t *TenantStore) GetEntityDetail(ctx context.Context, key, interval string, related int) (*domain.EntityDetail, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEntityDetail(ctx, key, interval, related)
+}
+
+func (t *TenantStore) ListEntityArticles(ctx context.Context, key string, limit, offset int) ([]domain.ArticleListItem, int, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.ListEntityArticles(ctx, key, limit, offset)
+}
+
+func (t *TenantStore) GetSentimentTrend(ctx context.Context, topic string, urls []string, since time.Time, interval string) ([]domain.SentimentBucket, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSentimentTrend(ctx, topic, urls, since, interval)
+}
+
+func (t *TenantStore) GetToneCounts(ctx context.Context, urls []string) ([]domain.ToneCount, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetToneCounts(ctx, urls)
+}
+
+func (t *TenantStore) GetStaleArticles(ctx context.Context, cutoff time.Time, limit int) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStaleArticles(ctx, cutoff, limit)
+}
+
+func (t *TenantStore) MarkArticleRefreshed(ctx context.Context, url, contentHash, etag string, at time.Time) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.MarkArticleRefreshed(ctx, url, contentHash, etag, at)
+}
+
+func (t *TenantStore) SetArticleStatus(ctx context.Context, url, status, reason string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetArticleStatus(ctx, url, status, reason)
+}
+
+func (t *TenantStore) FindNearDuplicate(ctx context.Context, embedding []float32, excludeURL string, minSimilarity float64) (*domain.Article, float64, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.FindNearDuplicate(ctx, embedding, excludeURL, minSimilarity)
+}
+
+func (t *TenantStore) GetArticleByContentHash(ctx context.Context, contentHash, excludeURL string) (*domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleByContentHash(ctx, contentHash, excludeURL)
+}
+
+func (t *TenantStore) AddDuplicate(ctx context.Context, dup *domain.Article, similarity float64) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.AddDuplicate(ctx, dup, similarity)
+}
+
+func (t *TenantStore) GetDuplicateByURL(ctx context.Context, url string) (*domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDuplicateByURL(ctx, url)
+}
+
+func (t *TenantStore) CountEmbeddingModels(ctx context.Context) ([]domain.EmbeddingModelCount, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.CountEmbeddingModels(ctx)
+}
+
+func (t *TenantStore) ListArticlesToReembed(ctx context.Context, model, afterID string, limit int) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListArticlesToReembed(ctx, model, afterID, limit)
+}
+
+func (t *TenantStore) SetArticleEmbedding(ctx context.Context, id string, embedding []float32, model string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetArticleEmbedding(ctx, id, embedding, model)
+}
+
+func (t *TenantStore) CountAnalysisVersions(ctx context.Context) ([]domain.AnalysisVersionCount, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.CountAnalysisVersions(ctx)
+}
+
+func (t *TenantStore) ListArticlesToReanalyze(ctx context.Context, version, afterID string, limit int) ([]domain.Article, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListArticlesToReanalyze(ctx, version, afterID, limit)
+}
+
+func (t *TenantStore) SetArticleAnalysis(ctx context.Context, a *domain.Article) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetArticleAnalysis(ctx, a)
+}
+
+func (t *TenantStore) GetSimplifiedSummary(ctx context.Context, articleID, level string) (string, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.GetSimplifiedSummary(ctx, articleID, level)
+}
+
+func (t *TenantStore) SetSimplifiedSummary(ctx context.Context, articleID, level, text string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetSimplifiedSummary(ctx, articleID, level, text)
+}
+
+func (t *TenantStore) ClearSimplifiedSummaries(ctx context.Context, url string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.ClearSimplifiedSummaries(ctx, url)
+}
+
+func (t *TenantStore) CreateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.CreateTaxonomyTopic(ctx, topic)
+}
+
+func (t *TenantStore) ListTaxonomyTopics(ctx context.Context) ([]domain.TaxonomyTopic, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListTaxonomyTopics(ctx)
+}
+
+func (t *TenantStore) GetTaxonomyTopic(ctx context.Context, id string) (*domain.TaxonomyTopic, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTaxonomyTopic(ctx, id)
+}
+
+func (t *TenantStore) UpdateTaxonomyTopic(ctx context.Context, topic *domain.TaxonomyTopic) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.UpdateTaxonomyTopic(ctx, topic)
+}
+
+func (t *TenantStore) DeleteTaxonomyTopic(ctx context.Context, id string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.DeleteTaxonomyTopic(ctx, id)
+}
+
+func (t *TenantStore) AddArticleTags(ctx context.Context, articleID string, tags []string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.AddArticleTags(ctx, articleID, tags)
+}
+
+func (t *TenantStore) RemoveArticleTag(ctx context.Context, articleID, tag string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.RemoveArticleTag(ctx, articleID, tag)
+}
+
+func (t *TenantStore) GetArticleTags(ctx context.Context, articleID string) ([]string, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetArticleTags(ctx, articleID)
+}
+
+func (t *TenantStore) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListTags(ctx)
+}
+
+func (t *TenantStore) AddArticleNote(ctx context.Context, note *domain.ArticleNote) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.AddArticleNote(ctx, note)
+}
+
+func (t *TenantStore) ListArticleNotes(ctx context.Context, articleID string) ([]domain.ArticleNote, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListArticleNotes(ctx, articleID)
+}
+
+func (t *TenantStore) DeleteArticleNote(ctx context.Context, articleID, noteID string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.DeleteArticleNote(ctx, articleID, noteID)
+}
+
+func (t *TenantStore) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.CreateCollection(ctx, collection)
+}
+
+func (t *TenantStore) ListCollections(ctx context.Context) ([]domain.Collection, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListCollections(ctx)
+}
+
+func (t *TenantStore) GetCollection(ctx context.Context, id string) (*domain.Collection, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCollection(ctx, id)
+}
+
+func (t *TenantStore) UpdateCollection(ctx context.Context, collection *domain.Collection) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.UpdateCollection(ctx, collection)
+}
+
+func (t *TenantStore) DeleteCollection(ctx context.Context, id string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.DeleteCollection(ctx, id)
+}
+
+func (t *TenantStore) AddCollectionArticles(ctx context.Context, collectionID string, articleIDs []string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.AddCollectionArticles(ctx, collectionID, articleIDs)
+}
+
+func (t *TenantStore) RemoveCollectionArticle(ctx context.Context, collectionID, articleID string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.RemoveCollectionArticle(ctx, collectionID, articleID)
+}
+
+func (t *TenantStore) CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.CreateSavedSearch(ctx, search)
+}
+
+func (t *TenantStore) ListSavedSearches(ctx context.Context) ([]domain.SavedSearch, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListSavedSearches(ctx)
+}
+
+func (t *TenantStore) DeleteSavedSearch(ctx context.Context, id string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.DeleteSavedSearch(ctx, id)
+}
+
+func (t *TenantStore) MarkSavedSearchChecked(ctx context.Context, id string, at time.Time) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.MarkSavedSearchChecked(ctx, id, at)
+}
+
+func (t *TenantStore) AddAlert(ctx context.Context, alert *domain.Alert) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.AddAlert(ctx, alert)
+}
+
+func (t *TenantStore) ListAlerts(ctx context.Context, searchID string, limit int) ([]domain.Alert, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListAlerts(ctx, searchID, limit)
+}
+
+func (t *TenantStore) RecordIngestFailure(ctx context.Context, failure *domain.IngestFailure) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.RecordIngestFailure(ctx, failure)
+}
+
+func (t *TenantStore) ListIngestFailures(ctx context.Context, category string, limit int) ([]domain.IngestFailure, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListIngestFailures(ctx, category, limit)
+}
+
+func (t *TenantStore) GetIngestFailure(ctx context.Context, id string) (*domain.IngestFailure, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIngestFailure(ctx, id)
+}
+
+func (t *TenantStore) ResolveIngestFailure(ctx context.Context, url string) (bool, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.ResolveIngestFailure(ctx, url)
+}
+
+func (t *TenantStore) GetChatCache(ctx context.Context, requestHash string) (*domain.ChatCache, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetChatCache(ctx, requestHash)
+}
+
+func (t *TenantStore) SetChatCache(ctx context.Context, requestHash string, request, response interface{}, ttl time.Duration) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetChatCache(ctx, requestHash, request, response, ttl)
+}
+
+func (t *TenantStore) CleanExpiredChatCache(ctx context.Context) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.CleanExpiredChatCache(ctx)
+}
+
+func (t *TenantStore) ClearChatCache(ctx context.Context, prefix string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.ClearChatCache(ctx, prefix)
+}
+
+func (t *TenantStore) SavePendingArticle(ctx context.Context, pending *domain.PendingArticle) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SavePendingArticle(ctx, pending)
+}
+
+func (t *TenantStore) GetPendingArticle(ctx context.Context, url string) (*domain.PendingArticle, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPendingArticle(ctx, url)
+}
+
+func (t *TenantStore) DeletePendingArticle(ctx context.Context, url string) error {
+	s, err := t.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.DeletePendingArticle(ctx, url)
+}
+
+func (t *TenantStore) ListPendingArticleURLs(ctx context.Context) ([]string, error) {
+	s, err := t.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListPendingArticleURLs(ctx)
+}
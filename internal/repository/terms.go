@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	"article-assistant/internal/domain"
+)
+
+// termAggregator merges keywords or topics across articles. Spellings differing only in case
+// or surrounding space are merged and shown with the most common one. Relevance is summed
+// over articles, taking the highest value when an article repeats a term.
+type termAggregator struct {
+	terms   map[string]*aggregatedTerm
+	article map[string]float64 // Key -> relevance within the current article
+}
+
+type aggregatedTerm struct {
+	spellings map[string]int
+	relevance float64
+	articles  int
+}
+
+func newTermAggregator() *termAggregator {
+	return &termAggregator{terms: make(map[string]*aggregatedTerm), article: make(map[string]float64)}
+}
+
+// add records a term of the current article
+func (t *termAggregator) add(term string, relevance float64) {
+	term = strings.TrimSpace(term)
+	key := strings.ToLower(term)
+	if key == "" {
+		return
+	}
+	agg, ok := t.terms[key]
+	if !ok {
+		agg = &aggregatedTerm{spellings: make(map[string]int)}
+		t.terms[key] = agg
+	}
+	agg.spellings[term]++
+	if best, seen := t.article[key]; !seen || relevance > best {
+		t.article[key] = relevance
+	}
+}
+
+// next ends the current article
+func (t *termAggregator) next() {
+	for key, relevance := range t.article {
+		agg := t.terms[key]
+		agg.relevance += relevance
+		agg.articles++
+	}
+	clear(t.article)
+}
+
+// top returns up to limit terms by summed relevance, then article count
+func (t *termAggregator) top(limit int) []domain.TermAggregate {
+	t.next()
+	out := make([]domain.TermAggregate, 0, len(t.terms))
+	for _, agg := range t.terms {
+		out = append(out, domain.TermAggregate{Term: mostCommon(agg.spellings), Relevance: agg.relevance, Articles: agg.articles})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Relevance != out[j].Relevance {
+			return out[i].Relevance > out[j].Relevance
+		}
+		if out[i].Articles != out[j].Articles {
+			return out[i].Articles > out[j].Articles
+		}
+		return out[i].Term < out[j].Term
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"article-assistant/internal/domain"
+)
+
+// Vector index methods supported by pgvector
+const (
+	IndexIVFFlat = "ivfflat"
+	IndexHNSW    = "hnsw"
+)
+
+// vectorIndexes are the pgvector indexes by table
+var vectorIndexes = []struct{ table, name string }{
+	{"articles", "articles_embedding_idx"},
+	{"article_chunks", "article_chunks_embedding_idx"},
+}
+
+// VectorIndexConfig selects how the article and passage embedding indexes are built
+type VectorIndexConfig struct {
+	Method         string // IndexIVFFlat or IndexHNSW
+	Lists          int    // ivfflat clusters; 0 sizes them from the table's row count
+	M              int    // hnsw links per node
+	EfConstruction int    // hnsw candidate list size while building
+}
+
+// DefaultVectorIndexConfig returns the index settings used when none are configured: ivfflat
+// with lists sized to the corpus, and pgvector's own hnsw defaults
+func DefaultVectorIndexConfig() VectorIndexConfig {
+	return VectorIndexConfig{Method: IndexIVFFlat, M: 16, EfConstruction: 64}
+}
+
+// IVFFlatLists returns the ivfflat cluster count pgvector recommends for rows vectors: one
+// per thousand rows up to a million, then the square root. A fixed count built over an
+// almost empty table leaves most clusters empty and searches miss close matches.
+func IVFFlatLists(rows int) int {
+	lists := rows / 1000
+	if rows > 1_000_000 {
+		lists = int(math.Sqrt(float64(rows)))
+	}
+	return max(lists, 1)
+}
+
+// createStatement returns the CREATE INDEX statement for one table holding rows vectors
+func (c VectorIndexConfig) createStatement(table, name string, rows int) (string, error) {
+	switch c.Method {
+	case IndexIVFFlat:
+		lists := c.Lists
+		if lists <= 0 {
+			lists = IVFFlatLists(rows)
+		}
+		return fmt.Sprintf(`CREATE INDEX %s ON %s USING ivfflat (embedding vector_cosine_ops) WITH (lists = %d)`,
+			name, table, lists), nil
+	case IndexHNSW:
+		return fmt.Sprintf(`CREATE INDEX %s ON %s USING hnsw (embedding vector_cosine_ops) WITH (m = %d, ef_construction = %d)`,
+			name, table, c.M, c.EfConstruction), nil
+	default:
+		return "", fmt.Errorf("unknown vector index method %q (use %s or %s)", c.Method, IndexIVFFlat, IndexHNSW)
+	}
+}
+
+// VectorIndexes describes the article and passage embedding indexes; a missing index is
+// reported with an empty Method
+func (r *Repo) VectorIndexes(ctx context.Context) (out []domain.VectorIndex, err error) {
+	ctx, finish := traceQuery(ctx, "vector_indexes")
+	defer func() { finish(len(out), err) }()
+
+	for _, idx := range vectorIndexes {
+		vi := domain.VectorIndex{Name: idx.name, Table: idx.table}
+		if err := r.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, idx.table)).Scan(&vi.Rows); err != nil {
+			return nil, err
+		}
+		err := r.DB.QueryRowContext(ctx, `
+			SELECT am.amname, pg_get_indexdef(c.oid), pg_relation_size(c.oid)
+			FROM pg_class c
+			JOIN pg_am am ON am.oid = c.relam
+			WHERE c.relname = $1 AND c.relkind = 'i'`, idx.name).Scan(&vi.Method, &vi.Definition, &vi.SizeBytes)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		out = append(out, vi)
+	}
+	return out, nil
+}
+
+// Reindex rebuilds the article and passage embedding indexes with r.VectorIndex, so ivfflat
+// clusters match the current corpus or the index method can be switched. Each table is locked
+// against writes while its index builds. No index is built over vectors wider than pgvector
+// can index.
+func (r *Repo) Reindex(ctx context.Context) error {
+	dims, err := r.EmbeddingDimensions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the embedding column dimension: %w", err)
+	}
+	for _, idx := range vectorIndexes {
+		var rows int
+		if err := r.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, idx.table)).Scan(&rows); err != nil {
+			return fmt.Errorf("failed to count %s: %w", idx.table, err)
+		}
+		create, err := r.VectorIndex.createStatement(idx.table, idx.name, rows)
+		if err != nil {
+			return err
+		}
+		if err := r.rebuildIndex(ctx, idx.name, create, dims <= maxIndexedDimensions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildIndex replaces one index in a transaction, so a failed build keeps the old index
+func (r *Repo) rebuildIndex(ctx context.Context, name, create string, build bool) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DROP INDEX IF EXISTS `+name); err != nil {
+		return fmt.Errorf("failed to drop %s: %w", name, err)
+	}
+	if build {
+		if _, err := tx.ExecContext(ctx, create); err != nil {
+			return fmt.Errorf("failed to build %s: %w", name, err)
+		}
+	}
+	return tx.Commit()
+}
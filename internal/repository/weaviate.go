@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"article-assistant/internal/domain"
+)
+
+// DefaultWeaviateClass is the Weaviate collection holding article embeddings
+const DefaultWeaviateClass = "Article"
+
+// weaviateBatchSize bounds the objects sent per batch request when backfilling
+const weaviateBatchSize = 100
+
+// WeaviateStore keeps article embeddings in Weaviate and answers vector searches from it;
+// everything else, including searches narrowed by metadata filters, goes to the wrapped store.
+// Objects use the article ID as their Weaviate ID and store only the URL.
+type WeaviateStore struct {
+	ArticleStore
+	BaseURL string // e.g. http://localhost:8080
+	APIKey  string // Sent as a bearer token when set
+	Class   string
+	Client  *http.Client
+}
+
+var _ ArticleStore = (*WeaviateStore)(nil)
+
+// NewWeaviateStore wraps store and creates the article class in Weaviate if it is missing
+func NewWeaviateStore(ctx context.Context, store ArticleStore, baseURL, apiKey string) (*WeaviateStore, error) {
+	return NewWeaviateStoreWithClass(ctx, store, baseURL, apiKey, DefaultWeaviateClass)
+}
+
+// NewWeaviateStoreWithClass is NewWeaviateStore keeping embeddings in class, so several stores
+// can share one Weaviate instance
+func NewWeaviateStoreWithClass(ctx context.Context, store ArticleStore, baseURL, apiKey, class string) (*WeaviateStore, error) {
+	w := &WeaviateStore{
+		ArticleStore: store,
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		APIKey:       apiKey,
+		Class:        class,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := w.ensureClass(ctx); err != nil {
+		return nil, fmt.Errorf("failed to set up Weaviate class: %w", err)
+	}
+	return w, nil
+}
+
+type weaviateObject struct {
+	Class      string            `json:"class"`
+	ID         string            `json:"id"`
+	Properties map[string]string `json:"properties"`
+	Vector     []float32         `json:"vector"`
+}
+
+// do sends a JSON request and decodes a JSON response into out when non-nil. A 404 is reported
+// as found == false rather than an error.
+func (w *WeaviateStore) do(ctx context.Context, method, path string, body, out interface{}) (found bool, err error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal Weaviate request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.BaseURL+path, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.APIKey)
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, fmt.Errorf("weaviate %s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return false, fmt.Errorf("failed to decode Weaviate response: %w", err)
+		}
+	}
+	return true, nil
+}
+
+func (w *WeaviateStore) ensureClass(ctx context.Context) error {
+	found, err := w.do(ctx, http.MethodGet, "/v1/schema/"+w.Class, nil, nil)
+	if err != nil || found {
+		return err
+	}
+	// Vectors come from our embedding model, and URLs are matched whole
+	class := map[string]interface{}{
+		"class":      w.Class,
+		"vectorizer": "none",
+		"properties": []map[string]interface{}{
+			{"name": "url", "dataType": []string{"text"}, "tokenization": "field"},
+		},
+	}
+	_, err = w.do(ctx, http.MethodPost, "/v1/schema", class, nil)
+	return err
+}
+
+// index upserts the embeddings of articles; articles without one are skipped
+func (w *WeaviateStore) index(ctx context.Context, articles []domain.Article) error {
+	var objects []weaviateObject
+	for _, a := range articles {
+		if len(a.Embedding) > 0 && a.ID != "" {
+			objects = append(objects, weaviateObject{Class: w.Class, ID: a.ID, Properties: map[string]string{"url": a.URL}, Vector: a.Embedding})
+		}
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+
+	// Batch writes replace existing objects, which makes them upserts; per-object failures
+	// are reported in the response body
+	var results []struct {
+		Result struct {
+			Errors *struct {
+				Error []struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+	if _, err := w.do(ctx, http.MethodPost, "/v1/batch/objects", map[string]interface{}{"objects": objects}, &results); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Result.Errors != nil && len(r.Result.Errors.Error) > 0 {
+			return fmt.Errorf("weaviate rejected object: %s", r.Result.Errors.Error[0].Message)
+		}
+	}
+	return nil
+}
+
+// UpsertArticle stores the article and then indexes its embedding
+func (w *WeaviateStore) UpsertArticle(ctx context.Context, article *domain.Article) error {
+	if err := w.ArticleStore.UpsertArticle(ctx, article); err != nil {
+		return err
+	}
+	if err := w.index(ctx, []domain.Article{*article}); err != nil {
+		return fmt.Errorf("failed to index article in Weaviate: %w", err)
+	}
+	return nil
+}
+
+// SetArticleEmbedding stores the new embedding and then re-indexes it
+func (w *WeaviateStore) SetArticleEmbedding(ctx context.Context, id string, embedding []float32, model string) error {
+	if err := w.ArticleStore.SetArticleEmbedding(ctx, id, embedding, model); err != nil {
+		return err
+	}
+	a, err := w.ArticleStore.GetArticleByID(ctx, id)
+	if err != nil || a == nil {
+		return err
+	}
+	a.Embedding = embedding
+	if err := w.index(ctx, []domain.Article{*a}); err != nil {
+		return fmt.Errorf("failed to index article in Weaviate: %w", err)
+	}
+	return nil
+}
+
+func (w *WeaviateStore) DeleteArticleByURL(ctx context.Context, url string) (bool, error) {
+	existing, err := w.ArticleStore.GetArticleByURL(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := w.ArticleStore.DeleteArticleByURL(ctx, url)
+	if err != nil || existing == nil {
+		return deleted, err
+	}
+	if _, err := w.do(ctx, http.MethodDelete, "/v1/objects/"+w.Class+"/"+existing.ID, nil, nil); err != nil {
+		return deleted, fmt.Errorf("failed to remove article from Weaviate: %w", err)
+	}
+	return deleted, nil
+}
+
+// Backfill indexes every stored article with an embedding, e.g. after switching to Weaviate;
+// it returns the number of articles indexed
+func (w *WeaviateStore) Backfill(ctx context.Context) (int, error) {
+	articles, err := w.ArticleStore.GetArticleEmbeddings(ctx, math.MaxInt32)
+	if err != nil {
+		return 0, err
+	}
+	for start := 0; start < len(articles); start += weaviateBatchSize {
+		end := min(start+weaviateBatchSize, len(articles))
+		if err := w.index(ctx, articles[start:end]); err != nil {
+			return start, err
+		}
+	}
+	return len(articles), nil
+}
+
+// GetArticlesByVectorSearchWithFilter finds the nearest articles in Weaviate and loads them
+// from the wrapped store in rank order. Weaviate only knows URLs, so metadata-filtered
+// searches run on the wrapped store.
+func (w *WeaviateStore) GetArticlesByVectorSearchWithFilter(ctx context.Context, queryEmbedding []float32, limit int, urls []string, filter domain.ArticleFilter) ([]domain.Article, error) {
+	if !filter.IsEmpty() {
+		return w.ArticleStore.GetArticlesByVectorSearchWithFilter(ctx, queryEmbedding, limit, urls, filter)
+	}
+
+	vector, err := json.Marshal(queryEmbedding)
+	if err != nil {
+		return nil, err
+	}
+	args := fmt.Sprintf("nearVector: {vector: %s}, limit: %d", vector, limit)
+	if len(urls) > 0 {
+		quoted, err := json.Marshal(urls)
+		if err != nil {
+			return nil, err
+		}
+		args += fmt.Sprintf(`, where: {path: ["url"], operator: ContainsAny, valueText: %s}`, quoted)
+	}
+	query := fmt.Sprintf("{ Get { %s(%s) { url } } }", w.Class, args)
+
+	var resp struct {
+		Data struct {
+			Get map[string][]struct {
+				URL string `json:"url"`
+			} `json:"Get"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := w.do(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": query}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("weaviate query failed: %s", resp.Errors[0].Message)
+	}
+
+	hits := resp.Data.Get[w.Class]
+	if len(hits) == 0 {
+		return nil, nil
+	}
+	ranked := make([]string, len(hits))
+	for i, h := range hits {
+		ranked[i] = h.URL
+	}
+	articles, err := w.ArticleStore.GetArticlesByURLs(ctx, ranked)
+	if err != nil {
+		return nil, err
+	}
+	byURL := make(map[string]domain.Article, len(articles))
+	for _, a := range articles {
+		byURL[a.URL] = a
+	}
+	// Objects whose article was deleted outside this store, or awaits enrichment again, are dropped
+	var out []domain.Article
+	for _, u := range ranked {
+		if a, ok := byURL[u]; ok && a.Status == domain.ArticleEnriched {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,247 @@
+package session
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memo kinds for intermediate step results
+const (
+	KindEmbedding = "embedding"
+	KindRetrieval = "retrieval"
+	KindText      = "text"
+)
+
+// Bounds on a memo's entries. Retrieval sets hold whole articles, and a chat bot's session
+// stays active as long as its conversation does.
+const (
+	DefaultMaxEntries = 200
+	DefaultEntryTTL   = 10 * time.Minute
+)
+
+// Memo stores intermediate step results for a single chat session
+type Memo struct {
+	mu       sync.RWMutex
+	entries  map[string]memoEntry
+	asked    map[string]time.Time // topic -> time the session last asked about it
+	lastUsed time.Time
+	hits     int
+	misses   int
+
+	MaxEntries int           // Oldest entries are dropped beyond this; DefaultMaxEntries when 0
+	EntryTTL   time.Duration // Entries older than this are misses; DefaultEntryTTL when 0
+}
+
+type memoEntry struct {
+	value  interface{}
+	stored time.Time
+}
+
+// NewMemo creates an empty memo
+func NewMemo() *Memo {
+	return &Memo{
+		entries:  make(map[string]memoEntry),
+		asked:    make(map[string]time.Time),
+		lastUsed: time.Now(),
+	}
+}
+
+func (m *Memo) entryTTL() time.Duration {
+	if m.EntryTTL > 0 {
+		return m.EntryTTL
+	}
+	return DefaultEntryTTL
+}
+
+func memoKey(kind, key string) string {
+	return kind + "|" + key
+}
+
+// Get returns a memoized value for the given kind and key
+func (m *Memo) Get(kind, key string) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastUsed = time.Now()
+	k := memoKey(kind, key)
+	e, ok := m.entries[k]
+	if ok && m.lastUsed.Sub(e.stored) > m.entryTTL() {
+		delete(m.entries, k)
+		ok = false
+	}
+	if ok {
+		m.hits++
+	} else {
+		m.misses++
+	}
+	return e.value, ok
+}
+
+// Set stores a value for the given kind and key
+func (m *Memo) Set(kind, key string, value interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastUsed = time.Now()
+	m.entries[memoKey(kind, key)] = memoEntry{value: value, stored: m.lastUsed}
+
+	limit := m.MaxEntries
+	if limit <= 0 {
+		limit = DefaultMaxEntries
+	}
+	for len(m.entries) > limit {
+		m.dropOldestLocked()
+	}
+}
+
+func (m *Memo) dropOldestLocked() {
+	var oldest string
+	var at time.Time
+	for k, e := range m.entries {
+		if oldest == "" || e.stored.Before(at) {
+			oldest, at = k, e.stored
+		}
+	}
+	delete(m.entries, oldest)
+}
+
+// Clear drops every memoized result, e.g. after the articles they were computed from changed.
+// When the session last asked about each topic is kept.
+func (m *Memo) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.entries)
+}
+
+// Len returns the number of memoized results
+func (m *Memo) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries)
+}
+
+// pruneLocked drops expired entries
+func (m *Memo) pruneLocked(now time.Time) {
+	for k, e := range m.entries {
+		if now.Sub(e.stored) > m.entryTTL() {
+			delete(m.entries, k)
+		}
+	}
+}
+
+// LastAsked returns when the session last asked about a topic
+func (m *Memo) LastAsked(topic string) (time.Time, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.asked[strings.ToLower(strings.TrimSpace(topic))]
+	return t, ok
+}
+
+// MarkAsked records that the session asked about a topic at the given time
+func (m *Memo) MarkAsked(topic string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.asked[strings.ToLower(strings.TrimSpace(topic))] = at
+}
+
+// Stats returns the number of hits and misses recorded by the memo
+func (m *Memo) Stats() (hits, misses int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hits, m.misses
+}
+
+// Store keeps one memo per session ID and evicts idle sessions
+type Store struct {
+	mu       sync.Mutex
+	sessions map[string]*Memo
+	ttl      time.Duration
+}
+
+// NewStore creates a session store whose memos expire after ttl of inactivity
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		sessions: make(map[string]*Memo),
+		ttl:      ttl,
+	}
+}
+
+// Memo returns the memo for a session, creating it if needed
+func (s *Store) Memo(sessionID string) *Memo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.sessions[sessionID]
+	if !ok {
+		m = NewMemo()
+		s.sessions[sessionID] = m
+	}
+	return m
+}
+
+// Evict removes memos that have been idle for longer than the TTL, and expired entries from
+// the others
+func (s *Store) Evict() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-s.ttl)
+	removed := 0
+	for id, m := range s.sessions {
+		m.mu.Lock()
+		idle := m.lastUsed.Before(cutoff)
+		if !idle {
+			m.pruneLocked(now)
+		}
+		m.mu.Unlock()
+		if idle {
+			delete(s.sessions, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Invalidate clears the memoized results of every session, e.g. when articles were ingested,
+// changed or deleted
+func (s *Store) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.sessions {
+		m.Clear()
+	}
+}
+
+// RunEviction evicts idle session memos every interval until ctx is cancelled
+func (s *Store) RunEviction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := s.Evict(); n > 0 {
+				log.Printf("🧹 Evicted %d idle session memos", n)
+			}
+		}
+	}
+}
+
+type memoCtxKey struct{}
+
+// NewContext returns a context carrying the given memo
+func NewContext(ctx context.Context, m *Memo) context.Context {
+	return context.WithValue(ctx, memoCtxKey{}, m)
+}
+
+// FromContext returns the memo carried by ctx, or nil
+func FromContext(ctx context.Context) *Memo {
+	m, _ := ctx.Value(memoCtxKey{}).(*Memo)
+	return m
+}
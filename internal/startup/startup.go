@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"article-assistant/internal/ingest"
+	"article-assistant/internal/processing"
 )
 
 // LoadOnStartupData defines the interface for loading data on startup
@@ -17,18 +18,77 @@ type LoadOnStartupData interface {
 	LoadData(ctx context.Context, dataSource string) error
 }
 
+// Queue accepts URLs for background ingestion, waiting for room when it is full
+type Queue interface {
+	Enqueue(ctx context.Context, url string) (processing.Status, error)
+}
+
+var _ Queue = (*processing.Facade)(nil)
+
 // ArticleLoader implements LoadOnStartupData for article ingestion
 type ArticleLoader struct {
 	ingestService *ingest.Service
+	queue         Queue
 }
 
-// NewArticleLoader creates a new ArticleLoader
+// NewArticleLoader creates a new ArticleLoader that ingests articles before LoadData returns
 func NewArticleLoader(ingestService *ingest.Service) *ArticleLoader {
 	return &ArticleLoader{
 		ingestService: ingestService,
 	}
 }
 
+// NewQueuedArticleLoader creates an ArticleLoader that hands new URLs to the ingestion
+// queue; LoadData returns once every URL is queued
+func NewQueuedArticleLoader(ingestService *ingest.Service, queue Queue) *ArticleLoader {
+	return &ArticleLoader{
+		ingestService: ingestService,
+		queue:         queue,
+	}
+}
+
+// filterExisting drops URLs that are already ingested using one batch lookup, so
+// restarts on a warm corpus do no per-URL fetches or checks
+func (al *ArticleLoader) filterExisting(ctx context.Context, urls []string) []string {
+	if al.ingestService == nil || al.ingestService.Repo == nil {
+		return urls
+	}
+
+	existing, err := al.ingestService.Repo.GetExistingURLs(ctx, urls)
+	if err != nil {
+		log.Printf("⚠️  Batch existence check failed, ingesting all startup URLs: %v", err)
+		return urls
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if existing[url] || seen[url] {
+			continue
+		}
+		seen[url] = true
+		missing = append(missing, url)
+	}
+	log.Printf("📄 Startup articles: %d already ingested, %d new", len(urls)-len(missing), len(missing))
+	return missing
+}
+
+// filterAllowed drops URLs the ingest service's URL policy rejects, logging each
+func (al *ArticleLoader) filterAllowed(urls []string) []string {
+	if al.ingestService == nil || al.ingestService.URLPolicy == nil {
+		return urls
+	}
+	allowed := urls[:0]
+	for _, url := range urls {
+		if err := al.ingestService.URLPolicy.Check(url); err != nil {
+			log.Printf("⚠️  Skipping startup article %s: %v", url, err)
+			continue
+		}
+		allowed = append(allowed, url)
+	}
+	return allowed
+}
+
 // LoadData loads articles from a file in parallel
 func (al *ArticleLoader) LoadData(ctx context.Context, articlesFile string) error {
 	// Check if file exists
@@ -64,26 +124,31 @@ func (al *ArticleLoader) LoadData(ctx context.Context, articlesFile string) erro
 		return nil
 	}
 
+	urls = al.filterExisting(ctx, al.filterAllowed(urls))
+	if len(urls) == 0 {
+		return nil
+	}
+
+	if al.queue != nil {
+		for i, url := range urls {
+			if _, err := al.queue.Enqueue(ctx, url); err != nil {
+				return fmt.Errorf("queued %d of %d startup articles: %w", i, len(urls), err)
+			}
+		}
+		log.Printf("📄 Queued %d startup articles for background ingestion", len(urls))
+		return nil
+	}
+
 	log.Printf("📄 Starting parallel article ingestion on startup (%d articles)...", len(urls))
 
-	// Use WaitGroup to wait for all goroutines to complete
-	var wg sync.WaitGroup
 	var mu sync.Mutex
 	successCount := 0
 	errorCount := 0
 
-	// Process URLs in parallel (limit to 5 concurrent to avoid overwhelming the API)
-	semaphore := make(chan struct{}, 5)
-
+	// Process URLs in parallel on a pool sized like the server's ingestion pool
+	pool := processing.NewIngestPool(ctx)
 	for _, url := range urls {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
+		err := pool.Submit(ctx, func(ctx context.Context) {
 			log.Printf("📄 Ingesting: %s", url)
 
 			err := al.ingestService.IngestURL(ctx, url)
@@ -97,11 +162,19 @@ func (al *ArticleLoader) LoadData(ctx context.Context, articlesFile string) erro
 				successCount++
 			}
 			mu.Unlock()
-		}(url)
+		})
+		if err != nil {
+			mu.Lock()
+			log.Printf("❌ Failed to queue %s: %v", url, err)
+			errorCount++
+			mu.Unlock()
+		}
 	}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
+	// Wait for all queued articles to complete
+	pool.Drain(ctx)
+	mu.Lock()
+	defer mu.Unlock()
 
 	log.Printf("📊 Startup ingestion complete: ✅ %d success, ❌ %d errors", successCount, errorCount)
 
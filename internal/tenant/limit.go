@@ -0,0 +1,93 @@
+package tenant
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLimiter allows each tenant a number of requests per minute, refilled continuously, with
+// bursts up to a minute's worth. It is safe for concurrent use.
+type RateLimiter struct {
+	perMinute float64
+	now       func() time.Time // Clock, overridable in tests
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	allowed  int64
+	rejected int64
+}
+
+// NewRateLimiter creates a limiter allowing perMinute requests per tenant; nil when perMinute is 0
+func NewRateLimiter(perMinute int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{perMinute: float64(perMinute), now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// Allow takes one request from id's allowance. When none is left it returns false and how long
+// until one is.
+func (l *RateLimiter) Allow(id string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{tokens: l.perMinute, updated: now}
+		l.buckets[id] = b
+	}
+	b.tokens = min(l.perMinute, b.tokens+now.Sub(b.updated).Minutes()*l.perMinute)
+	b.updated = now
+	if b.tokens < 1 {
+		b.rejected++
+		return false, time.Duration((1 - b.tokens) / l.perMinute * float64(time.Minute))
+	}
+	b.tokens--
+	b.allowed++
+	return true, 0
+}
+
+// SetClock overrides the limiter clock; used in tests
+func (l *RateLimiter) SetClock(now func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.now = now
+}
+
+// WritePrometheus writes each tenant's allowed and rejected request counters in the Prometheus
+// text format
+func (l *RateLimiter) WritePrometheus(w io.Writer) error {
+	l.mu.Lock()
+	ids := make([]string, 0, len(l.buckets))
+	for id := range l.buckets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	counts := make([][2]int64, len(ids))
+	for i, id := range ids {
+		counts[i] = [2]int64{l.buckets[id].allowed, l.buckets[id].rejected}
+	}
+	l.mu.Unlock()
+
+	if _, err := io.WriteString(w, `# HELP tenant_requests_total Requests per tenant, by whether the rate limit allowed them.
+# TYPE tenant_requests_total counter
+`); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if _, err := fmt.Fprintf(w, "tenant_requests_total{tenant=%q,result=\"allowed\"} %d\ntenant_requests_total{tenant=%q,result=\"rejected\"} %d\n",
+			id, counts[i][0], id, counts[i][1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package tenant
+
+import "context"
+
+// Role is what an API key may do; each role may also do everything the lower ones may
+type Role int
+
+// Roles, lowest first
+const (
+	RoleReader Role = iota + 1 // Chat, list, search and export
+	RoleEditor                 // Also ingest, import, delete and annotate articles, and manage topics, collections and searches
+	RoleAdmin                  // Also the /admin routes
+)
+
+var roleNames = map[Role]string{RoleReader: "reader", RoleEditor: "editor", RoleAdmin: "admin"}
+
+// String returns the role's name as written in API_KEYS
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "none"
+}
+
+// Allows reports whether r may use routes requiring required
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole returns the role named name: reader, editor or admin
+func ParseRole(name string) (Role, bool) {
+	for r, n := range roleNames {
+		if n == name {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+type roleKey struct{}
+
+// WithRole returns a context carrying the role of the request's API key
+func WithRole(ctx context.Context, r Role) context.Context {
+	return context.WithValue(ctx, roleKey{}, r)
+}
+
+// RoleFromContext returns the role carried by ctx, or RoleAdmin: without API keys every request
+// may do everything
+func RoleFromContext(ctx context.Context) Role {
+	if r, ok := ctx.Value(roleKey{}).(Role); ok {
+		return r
+	}
+	return RoleAdmin
+}
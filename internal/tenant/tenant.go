@@ -0,0 +1,98 @@
+// Package tenant identifies the team a request belongs to, so several teams can share one
+// deployment without seeing each other's articles. The tenant is derived from the request's API
+// key and carried in its context; storage, the chat cache, usage totals and rate limits are
+// kept per tenant. Each key also has a role limiting the routes it may use. Without API keys
+// every request belongs to the default tenant and may use every route.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Default is the tenant of every request when no API keys are configured, and of contexts
+// that carry no tenant
+const Default = "default"
+
+// Placeholder in DATABASE_URL is replaced with the tenant ID, giving each tenant its own database
+const Placeholder = "{tenant}"
+
+// idPattern restricts tenant IDs to names safe in database names, file paths and cache keys
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type contextKey struct{}
+
+// NewContext returns a context carrying tenant id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant carried by ctx, or Default
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Key is who an API key acts for and what it may do
+type Key struct {
+	Tenant string
+	Role   Role
+}
+
+// ParseKeys reads API_KEYS: comma-separated tenant:key or tenant:key:role entries, e.g.
+// "news:k1:reader,news:k2:editor,research:k3". A tenant may have several keys; keys without a
+// role are admin keys. It returns each key's tenant and role; empty when s is empty.
+func ParseKeys(s string) (map[string]Key, error) {
+	keys := make(map[string]Key)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(entry, ":")
+		id, key = strings.TrimSpace(id), strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q is not tenant:key", id)
+		}
+		if !idPattern.MatchString(id) {
+			return nil, fmt.Errorf("tenant %q must be lowercase letters, digits, '-' or '_'", id)
+		}
+		role := RoleAdmin
+		if i := strings.LastIndex(key, ":"); i >= 0 {
+			r, ok := ParseRole(strings.TrimSpace(key[i+1:]))
+			if !ok {
+				return nil, fmt.Errorf("tenant %q: unknown role %q (use reader, editor or admin)", id, key[i+1:])
+			}
+			key, role = strings.TrimSpace(key[:i]), r
+			if key == "" {
+				return nil, fmt.Errorf("%q is not tenant:key:role", id)
+			}
+		}
+		if other, dup := keys[key]; dup && other.Tenant != id {
+			return nil, fmt.Errorf("a key is given to both %q and %q", other.Tenant, id)
+		}
+		keys[key] = Key{Tenant: id, Role: role}
+	}
+	return keys, nil
+}
+
+// IDs returns the tenants of keys in alphabetical order, or only Default when keys is empty
+func IDs(keys map[string]Key) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, k := range keys {
+		if !seen[k.Tenant] {
+			seen[k.Tenant] = true
+			out = append(out, k.Tenant)
+		}
+	}
+	if len(out) == 0 {
+		return []string{Default}
+	}
+	sort.Strings(out)
+	return out
+}
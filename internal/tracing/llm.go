@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client wraps an llm.Client and records a span for every call
+type Client struct {
+	Inner    llm.Client
+	Provider string
+	Model    string
+}
+
+var _ llm.Client = (*Client)(nil)
+
+// WrapLLM instruments an LLM client for the given provider and model
+func WrapLLM(inner llm.Client, provider, model string) *Client {
+	return &Client{Inner: inner, Provider: provider, Model: model}
+}
+
+func (c *Client) start(ctx context.Context, method string, inputChars int) (context.Context, trace.Span) {
+	return Start(ctx, "llm."+method,
+		attribute.String("llm.provider", c.Provider),
+		attribute.String("llm.model", c.Model),
+		attribute.Int("llm.input_chars", inputChars),
+	)
+}
+
+func (c *Client) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
+	ctx, span := c.start(ctx, "Summarize", len(text))
+	out, err := c.Inner.Summarize(ctx, text, opts)
+	End(span, err)
+	return out, err
+}
+
+func (c *Client) SentimentScore(ctx context.Context, text string) (float64, error) {
+	ctx, span := c.start(ctx, "SentimentScore", len(text))
+	out, err := c.Inner.SentimentScore(ctx, text)
+	End(span, err)
+	return out, err
+}
+
+func (c *Client) ToneCompare(ctx context.Context, text1, text2 string) (string, error) {
+	ctx, span := c.start(ctx, "ToneCompare", len(text1)+len(text2))
+	out, err := c.Inner.ToneCompare(ctx, text1, text2)
+	End(span, err)
+	return out, err
+}
+
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	ctx, span := c.start(ctx, "Embed", len(text))
+	out, err := c.Inner.Embed(ctx, text)
+	End(span, err)
+	return out, err
+}
+
+func (c *Client) GenerateText(ctx context.Context, prompt string) (string, error) {
+	ctx, span := c.start(ctx, "GenerateText", len(prompt))
+	out, err := c.Inner.GenerateText(ctx, prompt)
+	End(span, err)
+	return out, err
+}
+
+func (c *Client) PlanQuery(ctx context.Context, query string) (*domain.Plan, error) {
+	ctx, span := c.start(ctx, "PlanQuery", len(query))
+	out, err := c.Inner.PlanQuery(ctx, query)
+	if out != nil {
+		span.SetAttributes(attribute.String("plan.command", out.Command))
+	}
+	End(span, err)
+	return out, err
+}
+
+func (c *Client) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	ctx, span := c.start(ctx, "ExtractAllSemantics", len(text))
+	out, err := c.Inner.ExtractAllSemantics(ctx, text)
+	End(span, err)
+	return out, err
+}
@@ -0,0 +1,256 @@
+package usage
+
+import (
+	"article-assistant/internal/domain"
+	"article-assistant/internal/tenant"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// price is the USD cost per million tokens
+type price struct {
+	prompt     float64
+	completion float64
+}
+
+// prices lists known models; longer prefixes are matched first so "gpt-4o-mini" does not match "gpt-4o"
+var prices = map[string]price{
+	"gpt-4o-mini":            {0.15, 0.60},
+	"gpt-4o":                 {2.50, 10.00},
+	"gpt-4-turbo":            {10.00, 30.00},
+	"gpt-4":                  {30.00, 60.00},
+	"gpt-3.5-turbo":          {0.50, 1.50},
+	"text-embedding-3-small": {0.02, 0},
+	"text-embedding-3-large": {0.13, 0},
+	"text-embedding-ada-002": {0.10, 0},
+}
+
+// EstimateCost returns the estimated USD cost of a call; unknown models cost 0
+func EstimateCost(model string, promptTokens, completionTokens int) float64 {
+	best := ""
+	for prefix := range prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0
+	}
+	p := prices[best]
+	return (float64(promptTokens)*p.prompt + float64(completionTokens)*p.completion) / 1_000_000
+}
+
+// Tracker accumulates token usage for a single request
+type Tracker struct {
+	mu    sync.Mutex
+	usage domain.Usage
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Add records one LLM call
+func (t *Tracker) Add(model string, promptTokens, completionTokens int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.PromptTokens += promptTokens
+	t.usage.CompletionTokens += completionTokens
+	t.usage.Tokens += promptTokens + completionTokens
+	t.usage.Cost += EstimateCost(model, promptTokens, completionTokens)
+	t.addModelLocked(model)
+}
+
+// AddUsage adds totals recorded elsewhere, such as by a per-call tracker
+func (t *Tracker) AddUsage(u domain.Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.PromptTokens += u.PromptTokens
+	t.usage.CompletionTokens += u.CompletionTokens
+	t.usage.Tokens += u.Tokens
+	t.usage.Cost += u.Cost
+	for _, m := range u.Models {
+		t.addModelLocked(m)
+	}
+}
+
+// addModelLocked adds model to the sorted model list; the caller must hold mu
+func (t *Tracker) addModelLocked(model string) {
+	if model == "" {
+		return
+	}
+	i := sort.SearchStrings(t.usage.Models, model)
+	if i < len(t.usage.Models) && t.usage.Models[i] == model {
+		return
+	}
+	t.usage.Models = slices.Insert(t.usage.Models, i, model)
+}
+
+// Usage returns the accumulated totals
+func (t *Tracker) Usage() domain.Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u := t.usage
+	u.Models = slices.Clone(u.Models)
+	return u
+}
+
+// DayTotal is the usage recorded on one UTC day
+type DayTotal struct {
+	Date             string  `json:"date"` // YYYY-MM-DD
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Tokens           int     `json:"tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// Ledger keeps daily usage totals per tenant across all requests and background work
+type Ledger struct {
+	mu        sync.Mutex
+	days      map[string]map[string]*DayTotal // By tenant, then date
+	totals    map[string]*DayTotal            // Running totals by tenant, for metrics; never pruned
+	retention int                             // Days kept
+	now       func() time.Time                // Clock, overridable in tests
+}
+
+// NewLedger creates a ledger keeping the given number of days
+func NewLedger(retentionDays int) *Ledger {
+	return &Ledger{
+		days:      make(map[string]map[string]*DayTotal),
+		totals:    make(map[string]*DayTotal),
+		retention: retentionDays,
+		now:       time.Now,
+	}
+}
+
+// Add records one LLM call made for tenantID on today's total
+func (l *Ledger) Add(tenantID, model string, promptTokens, completionTokens int) {
+	now := l.now().UTC()
+	date := now.Format("2006-01-02")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	days, ok := l.days[tenantID]
+	if !ok {
+		days = make(map[string]*DayTotal)
+		l.days[tenantID] = days
+		l.totals[tenantID] = &DayTotal{}
+	}
+	day, ok := days[date]
+	if !ok {
+		day = &DayTotal{Date: date}
+		days[date] = day
+		l.pruneLocked(now)
+	}
+	cost := EstimateCost(model, promptTokens, completionTokens)
+	for _, t := range []*DayTotal{day, l.totals[tenantID]} {
+		t.Calls++
+		t.PromptTokens += promptTokens
+		t.CompletionTokens += completionTokens
+		t.Tokens += promptTokens + completionTokens
+		t.Cost += cost
+	}
+}
+
+// Daily returns the days recorded for tenantID, most recent first
+func (l *Ledger) Daily(tenantID string) []DayTotal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]DayTotal, 0, len(l.days[tenantID]))
+	for _, d := range l.days[tenantID] {
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date > out[j].Date })
+	return out
+}
+
+// WritePrometheus writes each tenant's LLM calls, tokens and estimated cost since startup in the
+// Prometheus text format
+func (l *Ledger) WritePrometheus(w io.Writer) error {
+	l.mu.Lock()
+	ids := make([]string, 0, len(l.totals))
+	for id := range l.totals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	totals := make([]DayTotal, len(ids))
+	for i, id := range ids {
+		totals[i] = *l.totals[id]
+	}
+	l.mu.Unlock()
+
+	metrics := []struct {
+		name, help string
+		value      func(DayTotal) string
+	}{
+		{"tenant_llm_calls_total", "LLM calls per tenant.", func(t DayTotal) string { return strconv.Itoa(t.Calls) }},
+		{"tenant_llm_tokens_total", "LLM tokens per tenant.", func(t DayTotal) string { return strconv.Itoa(t.Tokens) }},
+		{"tenant_llm_cost_usd_total", "Estimated LLM cost in USD per tenant.", func(t DayTotal) string { return strconv.FormatFloat(t.Cost, 'f', -1, 64) }},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if _, err := fmt.Fprintf(w, "%s{tenant=%q} %s\n", m.name, id, m.value(totals[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetClock overrides the ledger clock; used in tests
+func (l *Ledger) SetClock(now func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.now = now
+}
+
+// pruneLocked drops days outside the retention window; the caller must hold mu
+func (l *Ledger) pruneLocked(now time.Time) {
+	if l.retention <= 0 {
+		return
+	}
+	cutoff := now.AddDate(0, 0, -l.retention).Format("2006-01-02")
+	for _, days := range l.days {
+		for date := range days {
+			if date <= cutoff {
+				delete(days, date)
+			}
+		}
+	}
+}
+
+type trackerCtxKey struct{}
+
+// NewContext returns a context carrying the given request tracker
+func NewContext(ctx context.Context, t *Tracker) context.Context {
+	return context.WithValue(ctx, trackerCtxKey{}, t)
+}
+
+// FromContext returns the tracker carried by ctx, or nil
+func FromContext(ctx context.Context) *Tracker {
+	t, _ := ctx.Value(trackerCtxKey{}).(*Tracker)
+	return t
+}
+
+// Record adds a call to the request tracker in ctx (if any) and to the ledger (if non-nil) under
+// ctx's tenant
+func Record(ctx context.Context, ledger *Ledger, model string, promptTokens, completionTokens int) {
+	if t := FromContext(ctx); t != nil {
+		t.Add(model, promptTokens, completionTokens)
+	}
+	if ledger != nil {
+		ledger.Add(tenant.FromContext(ctx), model, promptTokens, completionTokens)
+	}
+}
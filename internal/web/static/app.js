@@ -0,0 +1,270 @@
+// Dashboard for the Article Assistant API: chat, ingest and the article list. Everything the
+// API returns is rendered as text, never as HTML.
+"use strict";
+
+const keyStorage = "article-assistant-api-key";
+const pageSize = 20;
+const pollInterval = 2000;
+
+let articleOffset = 0;
+
+function $(id) {
+  return document.getElementById(id);
+}
+
+function el(tag, props, ...children) {
+  const node = document.createElement(tag);
+  Object.assign(node, props || {});
+  for (const child of children) {
+    if (child !== null && child !== undefined) {
+      node.append(child);
+    }
+  }
+  return node;
+}
+
+// api calls an endpoint with the saved API key and returns the decoded body. Error responses
+// throw with the API's message.
+async function api(path, options) {
+  const opts = Object.assign({ headers: {} }, options);
+  const key = localStorage.getItem(keyStorage);
+  if (key) {
+    opts.headers["X-API-Key"] = key;
+  }
+  if (opts.body !== undefined) {
+    opts.headers["Content-Type"] = "application/json";
+    opts.body = JSON.stringify(opts.body);
+  }
+  const resp = await fetch(path, opts);
+  let body = null;
+  try {
+    body = await resp.json();
+  } catch (e) {
+    // Not JSON; reported by status below
+  }
+  if (!resp.ok && resp.status !== 202) {
+    const message = body && body.message ? body.message : resp.statusText;
+    const code = body && body.code ? body.code + ": " : "";
+    throw new Error(code + message + " (" + resp.status + ")");
+  }
+  return { status: resp.status, body };
+}
+
+// ---------- API key ----------
+
+function setupKey() {
+  const input = $("api-key");
+  input.value = localStorage.getItem(keyStorage) || "";
+  $("key-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    const key = input.value.trim();
+    if (key) {
+      localStorage.setItem(keyStorage, key);
+    } else {
+      localStorage.removeItem(keyStorage);
+    }
+    loadArticles();
+  });
+}
+
+// articleLink links to an article's http(s) URL. Stored URLs come from clients, so any other
+// scheme, such as javascript:, is shown as plain text instead.
+function articleLink(url, title) {
+  let parsed = null;
+  try {
+    parsed = new URL(url);
+  } catch (e) {
+    // Not an absolute URL
+  }
+  if (!parsed || (parsed.protocol !== "http:" && parsed.protocol !== "https:")) {
+    return el("span", { textContent: title || url });
+  }
+  return el("a", { href: parsed.href, target: "_blank", rel: "noopener noreferrer", textContent: title || url });
+}
+
+// ---------- Chat ----------
+
+function renderSources(sources) {
+  if (!sources || sources.length === 0) {
+    return null;
+  }
+  const list = el("ol");
+  for (const s of sources) {
+    const item = el("li", null, articleLink(s.url, s.title));
+    if (s.reason) {
+      item.append(" ", el("span", { className: "badge", textContent: s.reason }));
+    }
+    list.append(item);
+  }
+  return list;
+}
+
+function renderMeta(resp) {
+  const parts = [];
+  if (resp.task) {
+    parts.push(resp.task);
+  }
+  if (resp.cached) {
+    parts.push("cached");
+  }
+  if (resp.grounding) {
+    const score = Math.round(resp.grounding.score * 100) + "% grounded";
+    parts.push(resp.grounding.low ? score + " (low)" : score);
+  }
+  if (resp.degraded && resp.degraded.length > 0) {
+    parts.push("skipped: " + resp.degraded.join(", "));
+  }
+  if (resp.usage && resp.usage.tokens) {
+    parts.push(resp.usage.tokens + " tokens");
+  }
+  return parts.length > 0 ? el("p", { className: "meta", textContent: parts.join(" · ") }) : null;
+}
+
+function setupChat() {
+  const form = $("chat-form");
+  const query = $("chat-query");
+  const log = $("chat-log");
+
+  form.addEventListener("submit", async (event) => {
+    event.preventDefault();
+    const text = query.value.trim();
+    if (!text) {
+      return;
+    }
+    const answer = el("p", { className: "answer muted", textContent: "Thinking…" });
+    const exchange = el("div", { className: "exchange" }, el("p", { className: "query", textContent: text }), answer);
+    log.prepend(exchange);
+    form.querySelector("button").disabled = true;
+
+    try {
+      const { body } = await api("/chat", { method: "POST", body: { query: text } });
+      answer.className = "answer";
+      answer.textContent = body.answer || "(no answer)";
+      if (body.error) {
+        answer.append(el("span", { className: "error", textContent: " " + body.error.message }));
+      }
+      exchange.append(renderSources(body.sources) || "", renderMeta(body) || "");
+      query.value = "";
+    } catch (err) {
+      answer.className = "answer error";
+      answer.textContent = err.message;
+    } finally {
+      form.querySelector("button").disabled = false;
+    }
+  });
+
+  // Ctrl/Cmd+Enter sends, plain Enter adds a line
+  query.addEventListener("keydown", (event) => {
+    if (event.key === "Enter" && (event.ctrlKey || event.metaKey)) {
+      form.requestSubmit();
+    }
+  });
+}
+
+// ---------- Ingest ----------
+
+function showIngestStatus(text, isError) {
+  const status = $("ingest-status");
+  status.className = isError ? "status error" : "status";
+  status.textContent = text;
+}
+
+async function pollIngest(id, url) {
+  for (;;) {
+    await new Promise((resolve) => setTimeout(resolve, pollInterval));
+    const { body } = await api("/ingest/status?id=" + encodeURIComponent(id));
+    if (body.state === "complete") {
+      showIngestStatus("Ingested " + url, false);
+      loadArticles();
+      return;
+    }
+    if (body.state === "failed") {
+      const category = body.error_category ? " [" + body.error_category + "]" : "";
+      showIngestStatus("Failed: " + (body.error || "unknown error") + category, true);
+      return;
+    }
+    showIngestStatus("Processing (" + (body.phase || "fetch") + ")…", false);
+  }
+}
+
+function setupIngest() {
+  const form = $("ingest-form");
+  const input = $("ingest-url");
+
+  form.addEventListener("submit", async (event) => {
+    event.preventDefault();
+    const url = input.value.trim();
+    form.querySelector("button").disabled = true;
+    showIngestStatus("Fetching…", false);
+    try {
+      const { status, body } = await api("/ingest", { method: "POST", body: { url } });
+      input.value = "";
+      if (status === 202) {
+        showIngestStatus("Processing (" + (body.phase || "fetch") + ")…", false);
+        loadArticles();
+        await pollIngest(body.id, url);
+      } else {
+        showIngestStatus(body.message || "Ingested " + url, false);
+        loadArticles();
+      }
+    } catch (err) {
+      showIngestStatus(err.message, true);
+    } finally {
+      form.querySelector("button").disabled = false;
+    }
+  });
+}
+
+// ---------- Articles ----------
+
+function renderArticle(a) {
+  const link = articleLink(a.url, a.title);
+  const meta = [];
+  if (a.status && a.status !== "enriched") {
+    meta.push(a.status);
+  }
+  if (a.sentiment) {
+    meta.push(a.sentiment);
+  }
+  const date = a.published_at || a.created_at;
+  if (date) {
+    meta.push(new Date(date).toLocaleDateString());
+  }
+  return el("li", null, link, el("span", { className: "meta", textContent: meta.join(" · ") }));
+}
+
+async function loadArticles() {
+  const list = $("article-list");
+  try {
+    const { body } = await api("/articles?limit=" + pageSize + "&offset=" + articleOffset);
+    const articles = body.articles || [];
+    list.replaceChildren(...articles.map(renderArticle));
+    if (articles.length === 0) {
+      list.append(el("li", { className: "muted", textContent: "No articles yet" }));
+    }
+    $("article-total").textContent = "(" + body.total + ")";
+    $("article-prev").disabled = articleOffset === 0;
+    $("article-next").disabled = articleOffset + pageSize >= body.total;
+  } catch (err) {
+    list.replaceChildren(el("li", { className: "error", textContent: err.message }));
+    $("article-total").textContent = "";
+  }
+}
+
+function setupArticles() {
+  $("article-prev").addEventListener("click", () => {
+    articleOffset = Math.max(0, articleOffset - pageSize);
+    loadArticles();
+  });
+  $("article-next").addEventListener("click", () => {
+    articleOffset += pageSize;
+    loadArticles();
+  });
+  $("article-refresh").addEventListener("click", loadArticles);
+  loadArticles();
+}
+
+setupKey();
+setupChat();
+setupIngest();
+setupArticles();
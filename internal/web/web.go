@@ -0,0 +1,82 @@
+// Package web serves the dashboard: a static page at / with a chat box, an ingest form and the
+// article list, built on the public endpoints. The assets are embedded, so the server binary
+// needs no files beside it.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/middleware"
+)
+
+// AssetPrefix is the path the dashboard's scripts and styles are served under
+const AssetPrefix = "/ui/"
+
+//go:embed static
+var embedded embed.FS
+
+// contentSecurityPolicy keeps the page to its own assets and API
+const contentSecurityPolicy = "default-src 'self'; img-src 'self' data:; frame-ancestors 'none'"
+
+func static() fs.FS {
+	sub, err := fs.Sub(embedded, "static")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Paths lists / and every asset path. They are served without an API key; the page asks for
+// one and sends it with each API request.
+func Paths() []string {
+	paths := []string{"/"}
+	fs.WalkDir(static(), ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && p != "index.html" {
+			paths = append(paths, AssetPrefix+p)
+		}
+		return nil
+	})
+	return paths
+}
+
+// Handler serves the dashboard page at / and its assets under AssetPrefix. Any other path is
+// answered with a NOT_FOUND error, as it is registered for the catch-all / route.
+func Handler() http.HandlerFunc {
+	assets := static()
+	files := http.StripPrefix(AssetPrefix, http.FileServer(http.FS(assets)))
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := ""
+		switch {
+		case r.URL.Path == "/":
+			name = "index.html"
+		case strings.HasPrefix(r.URL.Path, AssetPrefix):
+			name = path.Clean(strings.TrimPrefix(r.URL.Path, AssetPrefix))
+		}
+		if name == "" || name == "." || name == "index.html" && r.URL.Path != "/" {
+			middleware.WriteError(w, r, 404, domain.ErrCodeNotFound, "Not found")
+			return
+		}
+		if _, err := fs.Stat(assets, name); err != nil {
+			middleware.WriteError(w, r, 404, domain.ErrCodeNotFound, "Not found")
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			middleware.WriteError(w, r, 405, domain.ErrCodeMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if name == "index.html" {
+			w.Header().Set("Cache-Control", "no-cache")
+			http.ServeFileFS(w, r, assets, name)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,224 @@
+// Package webhook notifies downstream systems about ingested articles and saved search
+// matches by POSTing HMAC-signed JSON payloads to configured callback URLs.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/logging"
+	"article-assistant/internal/processing"
+	"article-assistant/internal/tenant"
+)
+
+// Event types
+const (
+	EventIngestCompleted = "ingest.completed"
+	EventIngestFailed    = "ingest.failed"
+	EventAlertMatched    = "alert.matched"
+)
+
+// Request headers sent with every delivery
+const (
+	EventHeader     = "X-Webhook-Event"
+	SignatureHeader = "X-Webhook-Signature" // "sha256=" + hex HMAC-SHA256 of the body
+)
+
+// Payload is the JSON body of an ingest or alert notification
+type Payload struct {
+	Event          string    `json:"event"`
+	Tenant         string    `json:"tenant"`              // Set by Send from its context
+	StatusID       string    `json:"status_id,omitempty"` // Ingest events only
+	ArticleID      string    `json:"article_id,omitempty"`
+	URL            string    `json:"url"`
+	Title          string    `json:"title,omitempty"`
+	Sentiment      string    `json:"sentiment,omitempty"`
+	SentimentScore float64   `json:"sentiment_score,omitempty"`
+	Topics         []string  `json:"topics,omitempty"`
+	CanonicalID    string    `json:"canonical_id,omitempty"` // Set when the URL was linked as a near-duplicate
+	SearchID       string    `json:"search_id,omitempty"`    // Alert events only: the saved search that matched
+	SearchTopic    string    `json:"search_topic,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	ErrorCategory  string    `json:"error_category,omitempty"` // Failed ingests: the fetch failure category
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// ArticleLookup is the subset of repository.Repo used to fill payloads
+type ArticleLookup interface {
+	GetArticleByURL(ctx context.Context, url string) (*domain.Article, error)
+	GetDuplicateByURL(ctx context.Context, url string) (*domain.Article, error)
+}
+
+// Notifier delivers payloads to every configured URL
+type Notifier struct {
+	URLs        []string
+	Secret      string // HMAC key; payloads are unsigned when empty
+	Client      *http.Client
+	MaxAttempts int           // Attempts per URL including the first
+	BaseBackoff time.Duration // Delay before the first retry; doubles each retry
+}
+
+// NewNotifier creates a notifier with default delivery settings
+func NewNotifier(urls []string, secret string) *Notifier {
+	return &Notifier{
+		URLs:        urls,
+		Secret:      secret,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: 3,
+		BaseBackoff: time.Second,
+	}
+}
+
+// Sign returns the signature header value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches body; for use by receivers
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Send POSTs p to every URL, retrying failed deliveries, and returns the errors of URLs
+// that never accepted it. The payload's tenant is the one ctx carries.
+func (n *Notifier) Send(ctx context.Context, p Payload) error {
+	p.Tenant = tenant.FromContext(ctx)
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var errs []error
+	for _, url := range n.URLs {
+		if err := n.deliver(ctx, url, p.Event, body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver posts body to url with exponential backoff on network errors and 5xx/429 responses
+func (n *Notifier) deliver(ctx context.Context, url, event string, body []byte) error {
+	backoff := n.BaseBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := n.post(ctx, url, event, body)
+		if err == nil || !retryable || attempt >= n.MaxAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, url, event string, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ArticleAssistant/1.0")
+	req.Header.Set(EventHeader, event)
+	if n.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.Secret, body))
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return false, nil
+}
+
+// IngestPayload builds the notification for a finished ingest request
+func IngestPayload(ctx context.Context, lookup ArticleLookup, st processing.Status) Payload {
+	p := Payload{
+		Event:     EventIngestCompleted,
+		StatusID:  st.ID,
+		URL:       st.URL,
+		Timestamp: st.UpdatedAt,
+	}
+	if st.State == processing.StatusFailed {
+		p.Event = EventIngestFailed
+		p.Error = st.Error
+		p.ErrorCategory = st.ErrorCategory
+		return p
+	}
+
+	logger := logging.FromContext(ctx)
+	article, err := lookup.GetArticleByURL(ctx, st.URL)
+	if err != nil {
+		logger.Warn("failed to load article for webhook", "url", st.URL, "error", err)
+	}
+	if article == nil {
+		// Near-duplicates are not stored as articles; report the canonical they were linked to
+		if dup, err := lookup.GetDuplicateByURL(ctx, st.URL); err == nil && dup != nil {
+			p.Title = dup.Title
+			p.CanonicalID = dup.CanonicalID
+		}
+		return p
+	}
+
+	p.ArticleID = article.ID
+	p.Title = article.Title
+	p.Sentiment = article.Sentiment
+	p.SentimentScore = article.SentimentScore
+	for _, topic := range article.Topics {
+		p.Topics = append(p.Topics, topic.Name)
+	}
+	return p
+}
+
+// IngestHook returns a facade hook that sends a notification for every finished request
+func IngestHook(n *Notifier, lookup ArticleLookup) processing.FinishHook {
+	return func(ctx context.Context, st processing.Status) {
+		p := IngestPayload(ctx, lookup, st)
+		if err := n.Send(ctx, p); err != nil {
+			logging.FromContext(ctx).Warn("failed to deliver ingest webhook", "event", p.Event, "url", st.URL, "error", err)
+		}
+	}
+}
+
+// AlertPayload builds the notification for an article matching a saved search
+func AlertPayload(search domain.SavedSearch, alert domain.Alert) Payload {
+	return Payload{
+		Event:          EventAlertMatched,
+		ArticleID:      alert.ArticleID,
+		URL:            alert.URL,
+		Title:          alert.Title,
+		Sentiment:      alert.Sentiment,
+		SentimentScore: alert.SentimentScore,
+		SearchID:       search.ID,
+		SearchTopic:    search.Topic,
+		Timestamp:      alert.CreatedAt,
+	}
+}
+
+// AlertHook returns an alerts.Evaluator hook that sends a notification for every new alert
+func AlertHook(n *Notifier) func(ctx context.Context, search domain.SavedSearch, alert domain.Alert) {
+	return func(ctx context.Context, search domain.SavedSearch, alert domain.Alert) {
+		if err := n.Send(ctx, AlertPayload(search, alert)); err != nil {
+			logging.FromContext(ctx).Warn("failed to deliver alert webhook", "search_id", search.ID, "url", alert.URL, "error", err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pool errors returned when a job is not queued
+var (
+	ErrQueueFull = errors.New("worker queue is full")
+	ErrClosed    = errors.New("worker pool is shutting down")
+)
+
+// Job is a unit of pool work; ctx carries the pool's per-job timeout
+type Job func(ctx context.Context)
+
+// Pool runs jobs on a fixed number of workers fed by a bounded queue
+type Pool struct {
+	name    string
+	timeout time.Duration // Per-job limit; zero means none
+	workers *Manager
+	size    int
+
+	mu        sync.RWMutex // Guards closing jobs against concurrent sends
+	closed    bool
+	jobs      chan Job
+	closing   chan struct{} // Closed when Drain starts, releasing blocked Submit calls
+	closeOnce sync.Once
+	active    atomic.Int64
+}
+
+// NewPool starts concurrency workers under parent. name labels the pool's metrics.
+func NewPool(parent context.Context, name string, concurrency, queueSize int, timeout time.Duration) *Pool {
+	concurrency = max(concurrency, 1)
+	p := &Pool{
+		name:    name,
+		timeout: timeout,
+		workers: NewManager(parent),
+		size:    concurrency,
+		jobs:    make(chan Job, max(queueSize, 0)),
+		closing: make(chan struct{}),
+	}
+	for i := 0; i < concurrency; i++ {
+		p.workers.Go(p.work)
+	}
+	return p
+}
+
+// work runs queued jobs until the queue is closed and empty
+func (p *Pool) work(ctx context.Context) {
+	for job := range p.jobs {
+		p.active.Add(1)
+		jobCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p.timeout > 0 {
+			jobCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		}
+		job(jobCtx)
+		cancel()
+		p.active.Add(-1)
+	}
+}
+
+// TrySubmit queues job without waiting, failing with ErrQueueFull when the queue has no room
+func (p *Pool) TrySubmit(job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Submit queues job, waiting for room until ctx is done
+func (p *Pool) Submit(ctx context.Context, job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-p.closing:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// QueueDepth is the number of jobs waiting for a worker
+func (p *Pool) QueueDepth() int {
+	return len(p.jobs)
+}
+
+// Drain stops accepting jobs and waits for queued and running ones to finish. Jobs still
+// running when ctx ends are cancelled and ctx's error is returned.
+func (p *Pool) Drain(ctx context.Context) error {
+	p.closeOnce.Do(func() { close(p.closing) })
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+	return p.workers.Drain(ctx)
+}
+
+// WritePrometheus writes queue depth, capacity and busy workers in Prometheus text format
+func (p *Pool) WritePrometheus(w io.Writer) error {
+	return WritePrometheus(w, p)
+}
+
+// WritePrometheus writes the gauges of several pools, labelled by pool name, under one set of
+// HELP and TYPE lines
+func WritePrometheus(w io.Writer, pools ...*Pool) error {
+	gauges := []struct {
+		name, help string
+		value      func(*Pool) int64
+	}{
+		{"worker_queue_depth", "Jobs waiting for a worker.", func(p *Pool) int64 { return int64(p.QueueDepth()) }},
+		{"worker_queue_capacity", "Maximum queued jobs.", func(p *Pool) int64 { return int64(cap(p.jobs)) }},
+		{"worker_active", "Workers running a job.", func(p *Pool) int64 { return p.active.Load() }},
+		{"worker_concurrency", "Configured workers.", func(p *Pool) int64 { return int64(p.size) }},
+	}
+	for _, g := range gauges {
+		if _, err := fmt.Fprintf(w, "# HELP %[1]s %[2]s\n# TYPE %[1]s gauge\n", g.name, g.help); err != nil {
+			return err
+		}
+		for _, p := range pools {
+			if _, err := fmt.Fprintf(w, "%s{pool=%q} %d\n", g.name, p.name, g.value(p)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package worker tracks background goroutines so the server can stop them on shutdown
+// instead of killing them mid-write.
+package worker
+
+import (
+	"context"
+	"sync"
+)
+
+// Manager runs goroutines under a shared context and waits for them on shutdown
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewManager creates a manager whose goroutines' context derives from parent
+func NewManager(parent context.Context) *Manager {
+	ctx, cancel := context.WithCancel(parent)
+	return &Manager{ctx: ctx, cancel: cancel}
+}
+
+// Context is cancelled when the manager stops, or when a drain passes its deadline
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Go runs fn in a tracked goroutine. fn must return once its context is done. It reports
+// false, without running fn, once shutdown has begun.
+func (m *Manager) Go(fn func(ctx context.Context)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		fn(m.ctx)
+	}()
+	return true
+}
+
+// Drain stops accepting work and waits for running goroutines to finish. If ctx ends
+// first, their context is cancelled and ctx's error is returned.
+func (m *Manager) Drain(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		m.cancel()
+		return nil
+	case <-ctx.Done():
+		m.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stop cancels running goroutines and waits for them to return, up to ctx's deadline
+func (m *Manager) Stop(ctx context.Context) error {
+	m.cancel()
+	return m.Drain(ctx)
+}
@@ -4,28 +4,325 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 -- Drop existing table to ensure clean migration
 DROP TABLE IF EXISTS articles CASCADE;
 
+-- An article's entity mentions keyed like entity.Key: the canonical name (or the name as
+-- written) lowercased without spaces or punctuation. Mirrors entityKeySQL in the repository.
+-- The category is lowercase, 'other' when none was extracted.
+CREATE OR REPLACE FUNCTION entity_mentions(entities JSONB)
+RETURNS TABLE (entity_key TEXT, name TEXT, category TEXT, confidence DOUBLE PRECISION)
+LANGUAGE SQL IMMUTABLE AS $$
+  SELECT lower(regexp_replace(n.name, '[^[:alnum:]]+', '', 'g')), n.name,
+         COALESCE(NULLIF(lower(trim(elem->>'category')), ''), 'other'), (elem->>'confidence')::float
+  FROM jsonb_array_elements(CASE WHEN jsonb_typeof(entities) = 'array' THEN entities ELSE '[]'::jsonb END) elem,
+       LATERAL (SELECT COALESCE(NULLIF(trim(elem->>'canonical_name'), ''), trim(elem->>'name')) AS name) n
+  WHERE lower(regexp_replace(n.name, '[^[:alnum:]]+', '', 'g')) <> ''
+$$;
+
+-- The distinct entity keys of an article as a JSON array, for GIN lookups by entity
+CREATE OR REPLACE FUNCTION entity_keys(entities JSONB) RETURNS JSONB
+LANGUAGE SQL IMMUTABLE AS $$
+  SELECT COALESCE(jsonb_agg(DISTINCT entity_key), '[]'::jsonb) FROM entity_mentions(entities)
+$$;
+
 CREATE TABLE articles (
   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
   url TEXT UNIQUE NOT NULL,
   title TEXT NOT NULL,
   summary TEXT,
+  summary_version TEXT, -- Summarization prompt version; 'supplied' for imported summaries
+  summary_model TEXT, -- Model that wrote the summary
+  content TEXT, -- Full extracted article text
   embedding vector(1536),
+  embedding_model TEXT, -- Model that produced embedding; NULL for articles stored before it was recorded
   sentiment VARCHAR(50),
   sentiment_score DECIMAL(3,2) DEFAULT 0.5,
   tone TEXT,
   entities JSONB DEFAULT '[]'::jsonb,
   keywords JSONB DEFAULT '[]'::jsonb,
   topics JSONB DEFAULT '[]'::jsonb,
+  topic_ids JSONB DEFAULT '[]'::jsonb, -- IDs of the taxonomy topics the extracted topics map to
+  analysis_version TEXT, -- Semantic extraction version that produced the fields above; 'supplied' for imported analyses
+  analysis_model TEXT, -- Model that ran the semantic extraction
+  entity_keys JSONB GENERATED ALWAYS AS (entity_keys(entities)) STORED, -- Keys of the mentioned entities
   url_hash TEXT UNIQUE NOT NULL, -- SHA-256 hash of the URL for caching
+  author TEXT,
+  section TEXT,
+  published_at TIMESTAMP,
+  source_domain TEXT, -- Publisher host without "www.", e.g. techcrunch.com
+  language VARCHAR(8), -- Detected ISO 639-1 code of the article text
+  content_hash TEXT, -- SHA-256 of the extracted text; detects changed content and identical copies
+  etag TEXT, -- ETag of the last fetch, sent as If-None-Match on refresh
+  last_refreshed_at TIMESTAMP, -- When the content was last fetched and checked
+  imported BOOLEAN NOT NULL DEFAULT FALSE, -- Loaded through /import; never re-fetched by refresh
+  status VARCHAR(16) NOT NULL DEFAULT 'enriched', -- pending until summarized, embedded and analyzed; failed when that failed
+  status_error TEXT, -- Why enrichment failed
+  -- Full-text search document; title weighs most, then summary, then body
+  search_tsv tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(summary, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(content, '')), 'C')
+  ) STORED,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Rebuilt with the VECTOR_INDEX settings (ivfflat or hnsw) by cmd/reindex or POST /admin/vector-index
 CREATE INDEX articles_embedding_idx
   ON articles USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100);
 
 CREATE INDEX articles_url_idx ON articles(url);
+CREATE INDEX articles_last_refreshed_at_idx ON articles(last_refreshed_at);
+CREATE INDEX articles_updated_at_idx ON articles(updated_at);
+CREATE INDEX articles_content_hash_idx ON articles(content_hash);
 CREATE INDEX articles_url_hash_idx ON articles(url_hash);
+CREATE INDEX articles_analysis_version_idx ON articles(analysis_version);
+CREATE INDEX articles_status_idx ON articles(status) WHERE status <> 'enriched';
+CREATE INDEX articles_author_idx ON articles(LOWER(author));
+CREATE INDEX articles_section_idx ON articles(LOWER(section));
+CREATE INDEX articles_source_domain_idx ON articles(source_domain);
+CREATE INDEX articles_published_idx ON articles(COALESCE(published_at, created_at));
+CREATE INDEX articles_search_tsv_idx ON articles USING GIN(search_tsv);
+CREATE INDEX articles_topic_ids_idx ON articles USING GIN(topic_ids);
+CREATE INDEX articles_entities_idx ON articles USING GIN(entities jsonb_path_ops);
+CREATE INDEX articles_keywords_idx ON articles USING GIN(keywords jsonb_path_ops);
+CREATE INDEX articles_topics_idx ON articles USING GIN(topics jsonb_path_ops);
+CREATE INDEX articles_entity_keys_idx ON articles USING GIN(entity_keys);
+
+-- Mentions of each entity per key, spelling and category, kept current by triggers on articles so top-entity
+-- rankings read this table instead of unnesting every article's entities
+CREATE TABLE entity_counts (
+  entity_key TEXT NOT NULL,
+  name TEXT NOT NULL,
+  category TEXT NOT NULL,
+  articles INTEGER NOT NULL, -- Articles mentioning the entity with this spelling and category
+  confidence_sum DOUBLE PRECISION NOT NULL DEFAULT 0, -- Over the mentions that have a confidence
+  confidences INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (entity_key, name, category)
+);
+
+-- Distinct articles mentioning each entity key, per category and under '' for any category, so an
+-- article that spells an entity two ways is counted once
+CREATE TABLE entity_articles (
+  entity_key TEXT NOT NULL,
+  category TEXT NOT NULL,
+  articles INTEGER NOT NULL,
+  PRIMARY KEY (entity_key, category)
+);
+
+-- The entity keys an article mentions, once per category and once under ''
+CREATE OR REPLACE FUNCTION entity_article_keys(entities JSONB) RETURNS TABLE (entity_key TEXT, category TEXT)
+LANGUAGE SQL IMMUTABLE AS $$
+  SELECT entity_key, category FROM entity_mentions(entities)
+  UNION
+  SELECT entity_key, '' FROM entity_mentions(entities)
+$$;
+
+CREATE OR REPLACE FUNCTION articles_count_entities() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    UPDATE entity_counts c
+    SET articles = c.articles - 1,
+        confidence_sum = c.confidence_sum - o.confidence_sum,
+        confidences = c.confidences - o.confidences
+    FROM (SELECT entity_key, name, category, COALESCE(SUM(confidence), 0) AS confidence_sum,
+                 COUNT(confidence) AS confidences
+          FROM entity_mentions(OLD.entities) GROUP BY entity_key, name, category) o
+    WHERE c.entity_key = o.entity_key AND c.name = o.name AND c.category = o.category;
+    DELETE FROM entity_counts c USING entity_mentions(OLD.entities) o
+    WHERE c.entity_key = o.entity_key AND c.name = o.name AND c.category = o.category AND c.articles <= 0;
+    UPDATE entity_articles c SET articles = c.articles - 1
+    FROM entity_article_keys(OLD.entities) o
+    WHERE c.entity_key = o.entity_key AND c.category = o.category;
+    DELETE FROM entity_articles c USING entity_article_keys(OLD.entities) o
+    WHERE c.entity_key = o.entity_key AND c.category = o.category AND c.articles <= 0;
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    INSERT INTO entity_counts (entity_key, name, category, articles, confidence_sum, confidences)
+    SELECT entity_key, name, category, 1, COALESCE(SUM(confidence), 0), COUNT(confidence)
+    FROM entity_mentions(NEW.entities)
+    GROUP BY entity_key, name, category
+    ORDER BY entity_key, name, category
+    ON CONFLICT (entity_key, name, category) DO UPDATE SET
+      articles = entity_counts.articles + 1,
+      confidence_sum = entity_counts.confidence_sum + EXCLUDED.confidence_sum,
+      confidences = entity_counts.confidences + EXCLUDED.confidences;
+    INSERT INTO entity_articles (entity_key, category, articles)
+    SELECT entity_key, category, 1 FROM entity_article_keys(NEW.entities)
+    ORDER BY entity_key, category
+    ON CONFLICT (entity_key, category) DO UPDATE SET articles = entity_articles.articles + 1;
+  END IF;
+  RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER articles_entity_counts
+  AFTER INSERT OR DELETE ON articles
+  FOR EACH ROW EXECUTE FUNCTION articles_count_entities();
+
+CREATE TRIGGER articles_entity_counts_update
+  AFTER UPDATE OF entities ON articles
+  FOR EACH ROW WHEN (OLD.entities IS DISTINCT FROM NEW.entities)
+  EXECUTE FUNCTION articles_count_entities();
+
+-- Reading-level rewrites of article summaries, cached per level
+CREATE TABLE article_simplifications (
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  level VARCHAR(32) NOT NULL, -- eli5, high_school, expert
+  text TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (article_id, level)
+);
+
+-- URLs whose content is a near-duplicate of a stored article (embedding cosine similarity
+-- above the ingest threshold); they are linked to the canonical article instead of stored
+CREATE TABLE article_duplicates (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  url TEXT UNIQUE NOT NULL,
+  title TEXT NOT NULL,
+  canonical_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  similarity DOUBLE PRECISION NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX article_duplicates_canonical_id_idx ON article_duplicates(canonical_id);
+
+-- Passages of article text embedded for question answering
+CREATE TABLE article_chunks (
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  chunk_index INT NOT NULL,
+  text TEXT NOT NULL,
+  embedding vector(1536) NOT NULL,
+  PRIMARY KEY (article_id, chunk_index)
+);
+
+CREATE INDEX article_chunks_embedding_idx
+  ON article_chunks USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100);
+
+-- Managed topic taxonomy; extracted topics are mapped to these nodes at ingest
+CREATE TABLE taxonomy_topics (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT,
+  parent_id TEXT REFERENCES taxonomy_topics(id) ON DELETE SET NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- User-defined tags on articles, stored lowercase; they filter articles like the metadata filters
+CREATE TABLE article_tags (
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  tag TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (article_id, tag)
+);
+
+CREATE INDEX article_tags_tag_idx ON article_tags(tag);
+
+-- User notes on articles
+CREATE TABLE article_notes (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  text TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX article_notes_article_id_idx ON article_notes(article_id, created_at);
+
+-- Named sets of articles that queries can be scoped to; names are unique in any case
+CREATE TABLE collections (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name TEXT NOT NULL,
+  description TEXT,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX collections_name_idx ON collections(lower(name));
+
+CREATE TABLE collection_articles (
+  collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (collection_id, article_id)
+);
+
+CREATE INDEX collection_articles_article_id_idx ON collection_articles(article_id);
+
+-- Queries evaluated against newly ingested articles; checked_at is the ingest time up to
+-- which articles have been evaluated
+CREATE TABLE saved_searches (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  topic TEXT NOT NULL,
+  min_sentiment DOUBLE PRECISION,
+  max_sentiment DOUBLE PRECISION,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Articles that matched a saved search; article fields are copied so alerts outlive the article
+CREATE TABLE alerts (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+  article_id UUID NOT NULL,
+  url TEXT NOT NULL,
+  title TEXT NOT NULL,
+  sentiment VARCHAR(50),
+  sentiment_score DOUBLE PRECISION,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (search_id, article_id)
+);
+
+CREATE INDEX alerts_created_at_idx ON alerts(created_at);
+
+-- Ingests that failed after all retries, one row per URL; a successful ingest removes the row
+CREATE TABLE ingest_failures (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  url TEXT UNIQUE NOT NULL,
+  error TEXT NOT NULL,
+  error_category VARCHAR(50),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  failures INTEGER NOT NULL DEFAULT 1,
+  first_failed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  last_failed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ingest_failures_last_failed_at_idx ON ingest_failures(last_failed_at);
+
+-- Fetched text of new articles waiting for LLM enrichment; removed once the article is stored
+CREATE TABLE pending_articles (
+  url TEXT PRIMARY KEY,
+  title TEXT NOT NULL DEFAULT '',
+  author TEXT,
+  section TEXT,
+  published_at TIMESTAMP,
+  content TEXT NOT NULL,
+  content_hash CHAR(64) NOT NULL,
+  etag TEXT,
+  fetched_at TIMESTAMP NOT NULL
+);
+
+-- LLM call audit log, written only when LLM_AUDIT=postgres
+CREATE TABLE llm_audit (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  request_id TEXT NOT NULL DEFAULT '',
+  provider VARCHAR(50) NOT NULL,
+  model TEXT NOT NULL,
+  method VARCHAR(50) NOT NULL,
+  prompt_hash CHAR(64) NOT NULL,
+  prompt_version TEXT NOT NULL DEFAULT '',
+  prompt_chars INTEGER NOT NULL,
+  latency_ms BIGINT NOT NULL,
+  prompt_tokens INTEGER NOT NULL DEFAULT 0,
+  completion_tokens INTEGER NOT NULL DEFAULT 0,
+  cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+  response TEXT NOT NULL DEFAULT '',
+  truncated BOOLEAN NOT NULL DEFAULT FALSE,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX llm_audit_created_at_idx ON llm_audit(created_at);
+CREATE INDEX llm_audit_prompt_hash_idx ON llm_audit(prompt_hash);
 
 -- Chat request/response cache table
 CREATE TABLE chat_cache (
@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/processing"
 )
 
 type ChatRequest struct {
@@ -31,14 +38,9 @@ const (
 	timeout = 60 * time.Second
 )
 
-// TestE2EAllQueries tests all 8 supported queries end-to-end
+// TestE2EAllQueries tests all 8 supported queries end-to-end against the seed articles.
+// Expected content comes from the articles and the scenario's scripted responses.
 func TestE2EAllQueries(t *testing.T) {
-	// Check if server is running
-	if !isServerRunning() {
-		t.Skip("Server not running, skipping e2e tests")
-	}
-
-	// Wait for server to be ready
 	waitForServer(t)
 
 	testCases := []struct {
@@ -50,39 +52,39 @@ func TestE2EAllQueries(t *testing.T) {
 	}{
 		{
 			name:          "Summary of specific article",
-			query:         "Give me a summary of https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/",
+			query:         "Give me a summary of https://example.com/article1",
 			expectedTask:  "summary",
 			shouldContain: []string{"Gwyneth Paltrow", "astronomer"},
 		},
 		{
 			name:          "Extract keywords from article",
-			query:         "Extract keywords from https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/",
+			query:         "Extract keywords from https://example.com/article1",
 			expectedTask:  "keywords_or_topics",
-			shouldContain: []string{"keywords", "topics"},
+			shouldContain: []string{"keywords", "topics", "artificial intelligence"},
 		},
 		{
 			name:          "Get sentiment of article",
-			query:         "What is the sentiment of https://techcrunch.com/2025/07/27/wizard-of-oz-blown-up-by-ai-for-giant-sphere-screen/?",
+			query:         "What is the sentiment of https://example.com/article1?",
 			expectedTask:  "get_sentiment",
 			shouldContain: []string{"sentiment", "positive"},
 		},
 		{
 			name:          "Compare multiple articles",
-			query:         "Compare https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/ and https://techcrunch.com/2025/07/26/allianz-life-says-majority-of-customers-personal-data-stolen-in-cyberattack/",
+			query:         "Compare https://example.com/article1 and https://example.com/article2",
 			expectedTask:  "compare_articles",
-			shouldContain: []string{"article", "data breach", "cyberattack", "hackers", "personal data"},
+			shouldContain: []string{"both articles", "policy", "markets"},
 		},
 		{
 			name:          "Tone differences between sources",
-			query:         "What are the key differences in tone between https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/ and https://techcrunch.com/2025/07/26/allianz-life-says-majority-of-customers-personal-data-stolen-in-cyberattack/",
+			query:         "What are the key differences in tone between https://example.com/article1 and https://example.com/article2",
 			expectedTask:  "ton_key_differences",
-			shouldContain: []string{"tone", "serious", "urgent"},
+			shouldContain: []string{"analytical", "conversational"},
 		},
 		{
 			name:          "Articles discussing economic trends",
 			query:         "What articles discuss economic trends?",
 			expectedTask:  "filter_by_specific_topic",
-			shouldContain: []string{"economic trends", "articles"},
+			shouldContain: []string{"economic trends", "articles", "astronomer"},
 		},
 		{
 			name:          "Most positive article about AI regulation",
@@ -94,7 +96,7 @@ func TestE2EAllQueries(t *testing.T) {
 			name:          "Most commonly discussed entities",
 			query:         "What are the most commonly discussed entities across the articles?",
 			expectedTask:  "get_top_entities",
-			shouldContain: []string{"entities", "OpenAI", "Meta", "Intel"},
+			shouldContain: []string{"entities", "OpenAI"},
 		},
 	}
 
@@ -179,10 +181,6 @@ func TestE2EAllQueries(t *testing.T) {
 
 // TestE2EServerHealth tests server health endpoint
 func TestE2EServerHealth(t *testing.T) {
-	if !isServerRunning() {
-		t.Skip("Server not running, skipping health test")
-	}
-
 	client := &http.Client{Timeout: timeout}
 	resp, err := client.Get(baseURL + "/health")
 	if err != nil {
@@ -258,13 +256,9 @@ func sendChatRequest(query string) (*ChatResponse, error) {
 	return &response, nil
 }
 
-// TestE2EIngest tests article ingestion
+// TestE2EIngest tests article ingestion of a page the test serves
 func TestE2EIngest(t *testing.T) {
-	if !isServerRunning() {
-		t.Skip("Server not running, skipping ingest test")
-	}
-
-	testURL := "https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/"
+	testURL := articlePage(t)
 
 	client := &http.Client{Timeout: timeout}
 	reqBody := map[string]string{"url": testURL}
@@ -298,15 +292,151 @@ func TestE2EIngest(t *testing.T) {
 	t.Log("✅ Article ingestion test passed")
 }
 
-// BenchmarkE2EQueries benchmarks the 8 main queries
-func BenchmarkE2EQueries(b *testing.B) {
-	if !isServerRunning() {
-		b.Skip("Server not running, skipping benchmark")
+// TestE2EIngestBatch queues two served pages and a URL the policy refuses in one request
+func TestE2EIngestBatch(t *testing.T) {
+	urls := []string{articlePage(t), "ftp://example.com/file", articlePage(t)}
+
+	client := &http.Client{Timeout: timeout}
+	jsonData, err := json.Marshal(api.IngestBatchRequest{URLs: urls})
+	if err != nil {
+		t.Fatalf("Failed to marshal batch request: %v", err)
+	}
+	resp, err := client.Post(baseURL+"/ingest/batch", "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatalf("Failed to send batch request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Batch request returned status %d, expected 202", resp.StatusCode)
 	}
 
+	var result api.IngestBatchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode batch response: %v", err)
+	}
+	if result.Accepted != 2 || result.Rejected != 1 || len(result.Items) != len(urls) {
+		t.Fatalf("Expected 2 accepted and 1 rejected, got %+v", result)
+	}
+	if item := result.Items[1]; item.URL != urls[1] || item.Code != domain.ErrCodeURLNotAllowed {
+		t.Errorf("Expected the ftp URL to be refused, got %+v", item)
+	}
+
+	for _, i := range []int{0, 2} {
+		item := result.Items[i]
+		if item.URL != urls[i] || item.StatusURL == "" {
+			t.Fatalf("Expected item %d to be queued, got %+v", i, item)
+		}
+		var status processing.Status
+		for deadline := time.Now().Add(10 * time.Second); status.State != processing.StatusComplete; time.Sleep(200 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Ingest of %s did not complete, last status %+v", item.URL, status)
+			}
+			statusResp, err := client.Get(baseURL + item.StatusURL)
+			if err != nil {
+				t.Fatalf("Failed to get ingest status: %v", err)
+			}
+			json.NewDecoder(statusResp.Body).Decode(&status)
+			statusResp.Body.Close()
+			if status.State == processing.StatusFailed {
+				t.Fatalf("Ingest of %s failed: %s", item.URL, status.Error)
+			}
+		}
+	}
+}
+
+// TestE2EChatAutoIngestNeedsEditor asks about a page that is not stored: a reader key gets no
+// ingestion, while an editor key has the page fetched and answered from
+func TestE2EChatAutoIngestNeedsEditor(t *testing.T) {
+	if !startedServer {
+		t.Skip("Auto-ingest and API keys are only configured on the server the tests start")
+	}
+	var hits atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, articleHTML, server.URL)
+	}))
+	defer server.Close()
+	query := fmt.Sprintf("Give me a summary of %s/2025/07/25/intel/", server.URL)
+
+	chat := func(key string) domain.ChatResponse {
+		t.Helper()
+		body, _ := json.Marshal(domain.ChatRequest{Query: query})
+		req, _ := http.NewRequest("POST", baseURL+"/chat", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		resp, err := (&http.Client{Timeout: timeout}).Do(req)
+		if err != nil {
+			t.Fatalf("Failed to call chat API: %v", err)
+		}
+		defer resp.Body.Close()
+		var out domain.ChatResponse
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("Failed to decode chat response: %v", err)
+		}
+		return out
+	}
+
+	if answer := chat(readerKey).Answer; !strings.Contains(answer, "not found") {
+		t.Errorf("Expected the reader to be told the article is not found, got: %s", answer)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Fatalf("Expected no fetch for a reader key, the page was fetched %d times", n)
+	}
+
+	if answer := chat(editorKey).Answer; !strings.Contains(answer, "Intel") {
+		t.Errorf("Expected the editor's query to be answered from the ingested page, got: %s", answer)
+	}
+	if hits.Load() == 0 {
+		t.Error("Expected the page to be fetched for an editor key")
+	}
+}
+
+// TestE2EImportRejectsScriptURLs checks that /import refuses to store a javascript: URL
+func TestE2EImportRejectsScriptURLs(t *testing.T) {
+	body, _ := json.Marshal(map[string]string{"url": "javascript:alert(document.cookie)", "title": "Memo", "text": "Revenue grew."})
+	resp, err := (&http.Client{Timeout: timeout}).Post(baseURL+"/import", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to call import API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var apiErr domain.APIError
+	json.NewDecoder(resp.Body).Decode(&apiErr)
+	if resp.StatusCode != http.StatusBadRequest || apiErr.Code != domain.ErrCodeURLNotAllowed {
+		t.Errorf("Expected 400 %s, got %d %+v", domain.ErrCodeURLNotAllowed, resp.StatusCode, apiErr)
+	}
+}
+
+// TestE2EMetricsHideTenants checks that the open /metrics names no tenant without the operator key
+func TestE2EMetricsHideTenants(t *testing.T) {
+	if !startedServer {
+		t.Skip("API keys are only configured on the server the tests start")
+	}
+	resp, err := (&http.Client{Timeout: timeout}).Get(baseURL + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "worker_queue_depth") {
+		t.Fatalf("Expected the deployment-wide metrics, got %d", resp.StatusCode)
+	}
+	if strings.Contains(string(body), "tenant=") {
+		t.Errorf("Expected no tenant-labelled series without the operator key:\n%s", body)
+	}
+}
+
+// BenchmarkE2EQueries benchmarks the 8 main queries
+func BenchmarkE2EQueries(b *testing.B) {
 	queries := []string{
-		"Give me a summary of https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/",
-		"What is the sentiment of https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/",
+		"Give me a summary of https://example.com/article1",
+		"What is the sentiment of https://example.com/article1",
 		"What articles discuss economic trends?",
 		"Which article is more positive about the topic of AI regulation?",
 		"What are the most commonly discussed entities across the articles?",
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,12 +21,37 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func TestE2EIngestion(t *testing.T) {
-	// Skip if no API key provided
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		t.Skip("Skipping e2e test: OPENAI_API_KEY not set")
-	}
+const articleHTML = `<!DOCTYPE html>
+<html>
+<head><title>Intel is spinning off its network and edge group</title></head>
+<body>
+  <article>
+    <p>Published at %s</p>
+    <h1>Intel is spinning off its network and edge group</h1>
+    <p>Intel plans to spin off its network and edge group into a standalone company, the chipmaker said on Friday.</p>
+    <p>The unit builds chips for telecom equipment and edge computing, and Intel will keep a stake after the separation.</p>
+  </article>
+</body>
+</html>`
+
+// articlePage serves a news article on loopback for the test and returns its URL. Each page
+// opens with its own address, so pages are not linked as duplicates of each other.
+func articlePage(t *testing.T) string {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, articleHTML, server.URL)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/2025/07/25/intel-is-spinning-off-its-network-and-edge-group/"
+}
 
+func TestE2EIngestion(t *testing.T) {
 	// Test database connection
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -36,22 +63,25 @@ func TestE2EIngestion(t *testing.T) {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
+	if err := db.Ping(); err != nil {
+		t.Skipf("Skipping e2e test: database unavailable: %v", err)
+	}
 
-	// Initialize components
+	// Initialize components; the scripted LLM stands in for a provider
 	repo := repository.NewRepo(db)
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	llmClient := llm.New(apiKey, "gpt-3.5-turbo")
+	llmClient, err := llm.LoadScenario(scenarioFile)
+	if err != nil {
+		t.Fatalf("Failed to load scenario: %v", err)
+	}
 
 	ingestService := &ingest.Service{
 		Repo: repo,
 		LLM:  llmClient,
+		// The test pages listen on loopback
+		Client: ingest.NewFetchClient(ingest.FetchOptions{AllowPrivate: true}, ingest.PolitenessOptions{CrawlDelay: -1}),
 	}
 
-	// Test URLs - just a couple for e2e testing
-	testURLs := []string{
-		"https://techcrunch.com/2025/07/26/astronomer-winks-at-viral-notoriety-with-temporary-spokesperson-gwyneth-paltrow/",
-		"https://techcrunch.com/2025/07/26/allianz-life-says-majority-of-customers-personal-data-stolen-in-cyberattack/",
-	}
+	testURLs := []string{articlePage(t), articlePage(t)}
 
 	ctx := context.Background()
 	successCount := 0
@@ -91,67 +121,21 @@ func TestE2EIngestion(t *testing.T) {
 		}
 	})
 
-	// Test ingestion via API endpoint
-	t.Run("Test_Ingestion_API", func(t *testing.T) {
-		// Test URL for API ingestion
-		testAPIURL := "https://techcrunch.com/2025/07/27/itch-io-is-the-latest-marketplace-to-crack-down-on-adult-games/"
-
-		// Prepare request
-		reqBody := map[string]string{"url": testAPIURL}
-		jsonData, err := json.Marshal(reqBody)
-		if err != nil {
-			t.Fatalf("Failed to marshal request: %v", err)
-		}
-
-		// Make API call
-		resp, err := http.Post("http://localhost:8080/ingest", "application/json", bytes.NewBuffer(jsonData))
-		if err != nil {
-			t.Fatalf("Failed to call ingestion API: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			t.Errorf("Ingestion API returned status %d, expected 200", resp.StatusCode)
-		}
-
-		// Verify article was ingested
-		articles, err := repo.GetArticlesByURLs(ctx, []string{testAPIURL})
-		if err != nil {
-			t.Errorf("Failed to retrieve API-ingested article: %v", err)
-		} else if len(articles) == 0 {
-			t.Error("API-ingested article not found in database")
-		} else {
-			t.Logf("✅ API-ingested article found: %s", articles[0].Title)
-		}
-	})
+	if n := llmClient.CallCount("Embed"); n == 0 {
+		t.Error("Expected the ingests to embed with the scenario LLM")
+	}
+	for _, url := range testURLs {
+		db.Exec("DELETE FROM articles WHERE url = $1", url)
+	}
 
 	t.Logf("📊 E2E Ingestion Test Summary: ✅ %d success, ❌ %d errors", successCount, errorCount)
 }
 
 func TestE2EIngestionWithServer(t *testing.T) {
-	// Skip if no API key provided
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		t.Skip("Skipping e2e test: OPENAI_API_KEY not set")
-	}
-
-	// Wait for server to be ready
-	timeout := 30 * time.Second
-	start := time.Now()
-
-	for time.Since(start) < timeout {
-		resp, err := http.Get("http://localhost:8080/health")
-		if err == nil && resp.StatusCode == 200 {
-			resp.Body.Close()
-			break
-		}
-		if resp != nil {
-			resp.Body.Close()
-		}
-		time.Sleep(1 * time.Second)
-	}
+	waitForServer(t)
 
 	// Test ingestion via API
-	testURL := "https://techcrunch.com/2025/07/25/intel-is-spinning-off-its-network-and-edge-group/"
+	testURL := articlePage(t)
 
 	reqBody := map[string]string{"url": testURL}
 	jsonData, err := json.Marshal(reqBody)
@@ -159,7 +143,7 @@ func TestE2EIngestionWithServer(t *testing.T) {
 		t.Fatalf("Failed to marshal request: %v", err)
 	}
 
-	resp, err := http.Post("http://localhost:8080/ingest", "application/json", bytes.NewBuffer(jsonData))
+	resp, err := http.Post(baseURL+"/ingest", "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
 		t.Fatalf("Failed to call ingestion API: %v", err)
 	}
@@ -169,7 +153,7 @@ func TestE2EIngestionWithServer(t *testing.T) {
 		t.Errorf("Ingestion API returned status %d, expected 200", resp.StatusCode)
 	}
 
-	// Test chat query to verify article was ingested
+	// Test chat query to verify article was ingested; enrichment finishes in the background
 	chatReq := domain.ChatRequest{
 		Query: fmt.Sprintf("Give me a summary of %s", testURL),
 	}
@@ -179,23 +163,25 @@ func TestE2EIngestionWithServer(t *testing.T) {
 		t.Fatalf("Failed to marshal chat request: %v", err)
 	}
 
-	chatResp, err := http.Post("http://localhost:8080/chat", "application/json", bytes.NewBuffer(chatJSON))
-	if err != nil {
-		t.Fatalf("Failed to call chat API: %v", err)
-	}
-	defer chatResp.Body.Close()
-
-	if chatResp.StatusCode != http.StatusOK {
-		t.Errorf("Chat API returned status %d, expected 200", chatResp.StatusCode)
-	}
-
 	var chatResponse domain.ChatResponse
-	if err := json.NewDecoder(chatResp.Body).Decode(&chatResponse); err != nil {
-		t.Fatalf("Failed to decode chat response: %v", err)
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(200 * time.Millisecond) {
+		chatResp, err := http.Post(baseURL+"/chat", "application/json", bytes.NewBuffer(chatJSON))
+		if err != nil {
+			t.Fatalf("Failed to call chat API: %v", err)
+		}
+		chatResponse = domain.ChatResponse{}
+		err = json.NewDecoder(chatResp.Body).Decode(&chatResponse)
+		chatResp.Body.Close()
+		if err != nil {
+			t.Fatalf("Failed to decode chat response: %v", err)
+		}
+		if chatResp.StatusCode == http.StatusOK && strings.Contains(chatResponse.Answer, "Intel") || time.Now().After(deadline) {
+			break
+		}
 	}
 
-	if chatResponse.Answer == "" {
-		t.Error("Chat response is empty")
+	if !strings.Contains(chatResponse.Answer, "Intel") {
+		t.Errorf("Chat response should summarize the ingested article, got: %s", chatResponse.Answer)
 	} else {
 		t.Logf("✅ Chat response received: %s", chatResponse.Answer)
 	}
@@ -0,0 +1,148 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// scenarioFile scripts the server's LLM, so the suite needs no provider key
+const scenarioFile = "../testdata/scenarios/default.yaml"
+
+// seedArticles are imported before the tests run; the scenario's plans name their URLs
+var seedArticles = []map[string]string{
+	{
+		"url":   "https://example.com/article1",
+		"title": "Astronomer hires Gwyneth Paltrow as spokesperson",
+		"text":  "Astronomer hired Gwyneth Paltrow as a temporary spokesperson after a viral moment. The data company leaned into the attention with a light-hearted video about artificial intelligence and economic trends.",
+	},
+	{
+		"url":   "https://example.com/article2",
+		"title": "Allianz Life says personal data stolen in cyberattack",
+		"text":  "Allianz Life said hackers stole the personal data of most customers in a cyberattack. The data breach is serious and urgent, and AI regulation may tighten as a result.",
+	},
+}
+
+// API keys of the started server; requests without a key of their own send editorKey
+const (
+	editorKey = "e2e-editor"
+	readerKey = "e2e-reader"
+)
+
+// startedServer reports whether TestMain started the server, with the settings tests may rely on
+var startedServer bool
+
+// keyTransport adds editorKey to requests that carry no API key
+type keyTransport struct{ next http.RoundTripper }
+
+func (t keyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("X-API-Key") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("X-API-Key", editorKey)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// TestMain starts the server with the scenario LLM provider and the in-memory store, unless
+// one is already listening on baseURL, and imports the seed articles
+func TestMain(m *testing.M) {
+	if isServerRunning() {
+		fmt.Println("Using the server already running at " + baseURL + "; it must use LLM_PROVIDER=scenario")
+		os.Exit(m.Run())
+	}
+
+	http.DefaultTransport = keyTransport{next: http.DefaultTransport}
+	startedServer = true
+
+	stop, err := startServer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to start the server: %v\n", err)
+		os.Exit(1)
+	}
+	code := 1
+	if err = seed(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to seed articles: %v\n", err)
+	} else {
+		code = m.Run()
+	}
+	stop()
+	os.Exit(code)
+}
+
+// startServer builds cmd/server and runs it from an empty directory, so no startup articles
+// are fetched. Fetching from loopback is allowed for the ingestion tests' local pages, and
+// chat auto-ingests them for editor keys.
+func startServer() (func(), error) {
+	dir, err := os.MkdirTemp("", "article-assistant-e2e")
+	if err != nil {
+		return nil, err
+	}
+	scenario, err := filepath.Abs(scenarioFile)
+	if err != nil {
+		return nil, err
+	}
+	binary := filepath.Join(dir, "server")
+	build := exec.Command("go", "build", "-o", binary, "../../cmd/server")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("build: %w", err)
+	}
+
+	var logs bytes.Buffer
+	cmd := exec.Command(binary)
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + os.Getenv("HOME"),
+		"LLM_PROVIDER=scenario",
+		"LLM_SCENARIO=" + scenario,
+		"DATABASE_DRIVER=memory",
+		"FETCH_ALLOW_PRIVATE=true",
+		"AUTO_INGEST=true",
+		"API_KEYS=e2e:" + editorKey + ":editor,e2e:" + readerKey + ":reader",
+	}
+	cmd.Stdout, cmd.Stderr = &logs, &logs
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	stop := func() {
+		cmd.Process.Signal(os.Interrupt)
+		cmd.Wait()
+		os.RemoveAll(dir)
+	}
+
+	for deadline := time.Now().Add(30 * time.Second); time.Now().Before(deadline); time.Sleep(200 * time.Millisecond) {
+		if isServerRunning() {
+			return stop, nil
+		}
+	}
+	stop()
+	return nil, fmt.Errorf("server not ready after 30 seconds:\n%s", logs.String())
+}
+
+func seed() error {
+	client := &http.Client{Timeout: timeout}
+	for _, article := range seedArticles {
+		body, err := json.Marshal(article)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Post(baseURL+"/import", "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("import of %s returned status %d", article["url"], resp.StatusCode)
+		}
+	}
+	return nil
+}
@@ -5,10 +5,15 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
 	"article-assistant/internal/repository"
 
 	"github.com/google/uuid"
@@ -97,6 +102,278 @@ func TestUpsertArticle(t *testing.T) {
 	t.Log("✅ UpsertArticle test passed")
 }
 
+func TestGetArticleContentsByURLs(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+
+	withContent := generateUniqueTestURL("content")
+	withoutContent := generateUniqueTestURL("no-content")
+	for _, a := range []*domain.Article{
+		{ID: uuid.New().String(), URL: withContent, Title: "Full text", Summary: "Short", Content: "The full article body", URLHash: generateURLHash(withContent)},
+		{ID: uuid.New().String(), URL: withoutContent, Title: "Summary only", Summary: "Short", URLHash: generateURLHash(withoutContent)},
+	} {
+		require.NoError(t, repo.UpsertArticle(ctx, a))
+	}
+
+	contents, err := repo.GetArticleContentsByURLs(ctx, []string{withContent, withoutContent})
+	require.NoError(t, err)
+	assert.Equal(t, "The full article body", contents[withContent])
+	_, ok := contents[withoutContent]
+	assert.False(t, ok, "articles without content should be omitted")
+
+	article, err := repo.GetArticleByURL(ctx, withContent)
+	require.NoError(t, err)
+	assert.Equal(t, "The full article body", article.Content)
+}
+
+// A fetched article waiting for enrichment has no embedding; reading it must not fail on the NULL
+func TestGetArticleByURLReadsPendingArticle(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+
+	url := generateUniqueTestURL("pending")
+	require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+		ID:      uuid.New().String(),
+		URL:     url,
+		Title:   "Fetched, not enriched",
+		Content: "The fetched article body",
+		URLHash: generateURLHash(url),
+		Status:  domain.ArticlePending,
+	}))
+
+	var embeddingIsNull bool
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT embedding IS NULL FROM articles WHERE url = $1", url).Scan(&embeddingIsNull))
+	require.True(t, embeddingIsNull, "pending articles should be stored without an embedding")
+
+	article, err := repo.GetArticleByURL(ctx, url)
+	require.NoError(t, err)
+	require.NotNil(t, article)
+	assert.Equal(t, domain.ArticlePending, article.Status)
+	assert.Equal(t, "The fetched article body", article.Content)
+	assert.Empty(t, article.Embedding)
+}
+
+func TestDeleteArticleByURL(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	url := generateUniqueTestURL("delete")
+	require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+		ID: uuid.New().String(), URL: url, Title: "To delete", URLHash: generateURLHash(url),
+	}))
+
+	deleted, err := repo.DeleteArticleByURL(ctx, url)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	article, err := repo.GetArticleByURL(ctx, url)
+	require.NoError(t, err)
+	assert.Nil(t, article)
+
+	deleted, err = repo.DeleteArticleByURL(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, deleted, "deleting a missing article should report false")
+}
+
+func TestGetExistingURLs(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	stored := generateUniqueTestURL("existing")
+	missing := generateUniqueTestURL("missing")
+	require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+		ID: uuid.New().String(), URL: stored, Title: "Existing", URLHash: generateURLHash(stored),
+	}))
+
+	existing, err := repo.GetExistingURLs(ctx, []string{stored, missing})
+	require.NoError(t, err)
+	assert.True(t, existing[stored])
+	assert.False(t, existing[missing])
+}
+
+func TestNearDuplicates(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	canonicalURL := generateUniqueTestURL("canonical")
+	dupURL := generateUniqueTestURL("syndicated")
+	canonical := &domain.Article{
+		ID: uuid.New().String(), URL: canonicalURL, Title: "Original", URLHash: generateURLHash(canonicalURL),
+		Embedding: generateTestEmbedding(1536),
+	}
+	require.NoError(t, repo.UpsertArticle(ctx, canonical))
+
+	found, sim, err := repo.FindNearDuplicate(ctx, generateTestEmbedding(1536), dupURL, 0.97)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, canonical.ID, found.ID)
+	assert.InDelta(t, 1.0, sim, 0.001)
+
+	found, _, err = repo.FindNearDuplicate(ctx, generateTestEmbedding(1536), canonicalURL, 0.97)
+	require.NoError(t, err)
+	if found != nil {
+		assert.NotEqual(t, canonicalURL, found.URL, "the article itself must be excluded")
+	}
+
+	require.NoError(t, repo.AddDuplicate(ctx, &domain.Article{URL: dupURL, Title: "Copy", CanonicalID: canonical.ID}, sim))
+
+	dups, err := repo.GetDuplicates(ctx, canonical.ID)
+	require.NoError(t, err)
+	require.Len(t, dups, 1)
+	assert.Equal(t, dupURL, dups[0].URL)
+	assert.Equal(t, canonical.ID, dups[0].CanonicalID)
+
+	existing, err := repo.GetExistingURLs(ctx, []string{dupURL})
+	require.NoError(t, err)
+	assert.True(t, existing[dupURL], "duplicate URLs count as ingested")
+}
+
+func TestChatCacheTTL(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	live := "test-ttl-" + uuid.New().String()
+	expired := "test-ttl-" + uuid.New().String()
+	defer db.Exec("DELETE FROM chat_cache WHERE request_hash IN ($1, $2)", live, expired)
+
+	response := domain.ChatResponse{Answer: "cached answer"}
+	require.NoError(t, repo.SetChatCache(ctx, live, map[string]string{"query": "q"}, response, time.Hour))
+	require.NoError(t, repo.SetChatCache(ctx, expired, map[string]string{"query": "q"}, response, -time.Second))
+
+	entry, err := repo.GetChatCache(ctx, live)
+	require.NoError(t, err)
+	assert.NotNil(t, entry)
+
+	entry, err = repo.GetChatCache(ctx, expired)
+	require.NoError(t, err)
+	assert.Nil(t, entry, "expired entries must not be returned")
+}
+
+func TestGetArticlesByHybridSearch(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	textOnly := generateUniqueTestURL("hybrid-text")
+	require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+		ID: uuid.New().String(), URL: textOnly, URLHash: generateURLHash(textOnly),
+		Title: "Quantum annealing breakthrough", Summary: "Researchers report a quantum annealing speedup.",
+	}))
+
+	articles, total, err := repo.GetArticlesByHybridSearch(ctx, generateTestEmbedding(1536), "quantum annealing", 50, 0, []string{textOnly}, domain.ArticleFilter{}, 0.5)
+	require.NoError(t, err)
+	require.Len(t, articles, 1, "full-text match should be found without an embedding")
+	assert.Equal(t, textOnly, articles[0].URL)
+	assert.Equal(t, 1, total)
+	assert.Greater(t, articles[0].Score, 0.0, "results should carry their hybrid score")
+
+	articles, total, err = repo.GetArticlesByHybridSearch(ctx, generateTestEmbedding(1536), "quantum annealing", 50, 1, []string{textOnly}, domain.ArticleFilter{}, 0.5)
+	require.NoError(t, err)
+	assert.Empty(t, articles, "offset past the last match should return an empty page")
+	assert.Equal(t, 1, total)
+
+	_, _, err = repo.GetArticlesByHybridSearch(ctx, generateTestEmbedding(1536), "quantum", 5, 0, nil, domain.ArticleFilter{}, 1.5)
+	assert.Error(t, err)
+}
+
+func TestHybridSearchCountsOnlyMatches(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	axis := func(i int) []float32 {
+		e := make([]float32, 1536)
+		e[i] = 1
+		return e
+	}
+	near, unrelated := generateUniqueTestURL("hybrid-near"), generateUniqueTestURL("hybrid-unrelated")
+	for _, a := range []*domain.Article{
+		{URL: near, Title: "Grid storage", Summary: "Batteries for the grid.", Embedding: axis(0)},
+		{URL: unrelated, Title: "Football results", Summary: "Scores from the weekend.", Embedding: axis(1)},
+	} {
+		a.ID, a.URLHash = uuid.New().String(), generateURLHash(a.URL)
+		require.NoError(t, repo.UpsertArticle(ctx, a))
+	}
+
+	articles, total, err := repo.GetArticlesByHybridSearch(ctx, axis(0), "energy", 10, 0, []string{near, unrelated}, domain.ArticleFilter{}, 0.7)
+	require.NoError(t, err)
+	require.Len(t, articles, 1, "an embedding below the minimum similarity without matching terms is not a match")
+	assert.Equal(t, near, articles[0].URL)
+	assert.Equal(t, 1, total, "total must count matches, not every embedded article")
+}
+
+func TestListArticles(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	for i, score := range []float64{0.2, 0.9, 0.5} {
+		url := generateUniqueTestURL(fmt.Sprintf("list-%d", i))
+		require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+			ID: uuid.New().String(), URL: url, Title: "List", URLHash: generateURLHash(url),
+			SentimentScore: score, Embedding: generateTestEmbedding(1536),
+		}))
+	}
+
+	items, total, err := repo.ListArticles(ctx, domain.ArticleFilter{}, 2, 0, "sentiment_score", true)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, total, 3)
+	require.Len(t, items, 2)
+	assert.GreaterOrEqual(t, items[0].SentimentScore, items[1].SentimentScore)
+
+	_, _, err = repo.ListArticles(ctx, domain.ArticleFilter{}, 10, 0, "title; DROP TABLE articles", false)
+	assert.Error(t, err, "unknown sort keys must be rejected")
+}
+
+func TestListAllArticlesSelectsFields(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	url := generateUniqueTestURL("list-all")
+	require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+		ID: uuid.New().String(), URL: url, Title: "List all", Summary: "Summary", Content: "Body", URLHash: generateURLHash(url),
+		Author: "Jane Doe", Topics: []domain.SemanticTopic{{Name: "AI"}}, Embedding: generateTestEmbedding(1536),
+	}))
+
+	filter := domain.ArticleFilter{Author: "jane doe"}
+	articles, err := repo.ListAllArticles(ctx, domain.ArticleListOptions{Filter: filter, Fields: []string{"url", "summary", "topics"}})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	a := articles[0]
+	assert.NotEmpty(t, a.ID, "the ID is always selected")
+	assert.Equal(t, url, a.URL)
+	assert.Equal(t, "Summary", a.Summary)
+	require.Len(t, a.Topics, 1)
+	assert.Empty(t, a.Title, "unselected fields stay empty")
+	assert.Empty(t, a.Content)
+	assert.Nil(t, a.Embedding)
+
+	total, err := repo.CountArticles(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	_, err = repo.ListAllArticles(ctx, domain.ArticleListOptions{Fields: []string{"embedding"}})
+	assert.Error(t, err, "the embedding cannot be selected")
+}
+
 func TestGetSummaryByID(t *testing.T) {
 	db, repo := setupTestDB(t)
 	defer db.Close()
@@ -276,28 +553,31 @@ func TestGetTopEntities(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test GetTopEntities with URL filter - should get entities from our test data only
-	entities, err := repo.GetTopEntities(ctx, 5, []string{url1, url2, url3})
+	entities, err := repo.GetTopEntities(ctx, 5, []string{url1, url2, url3}, "")
 	require.NoError(t, err)
 	require.Len(t, entities, 4) // AI, Technology, Machine Learning, Innovation from our test data
 
-	// Find AI entity (should be most common)
-	var aiEntity *domain.SemanticEntity
-	for _, entity := range entities {
-		if entity.Name == "AI" {
-			aiEntity = &entity
-			break
-		}
-	}
-	require.NotNil(t, aiEntity)
+	// AI and Technology are both in two articles at 0.85; the name breaks the tie
+	aiEntity := entities[0]
 	assert.Equal(t, "AI", aiEntity.Name)
-	assert.Greater(t, aiEntity.Confidence, 0.0)
+	assert.Equal(t, "technology", aiEntity.Category)
+	assert.Equal(t, 2, aiEntity.Count)
+	assert.InDelta(t, 0.85, aiEntity.AvgConfidence, 0.001)
+	assert.InDelta(t, 1.7, aiEntity.Score, 0.001)
+	assert.Equal(t, "Technology", entities[1].Name)
 
 	// Test GetTopEntities with URL filter
 	urls := []string{url1, url2}
-	entities, err = repo.GetTopEntities(ctx, 5, urls)
+	entities, err = repo.GetTopEntities(ctx, 5, urls, "")
 	require.NoError(t, err)
 	require.Len(t, entities, 3) // AI, Technology, Machine Learning
 
+	// A category counts only the mentions with it
+	entities, err = repo.GetTopEntities(ctx, 5, []string{url1, url2, url3}, "concept")
+	require.NoError(t, err)
+	require.Len(t, entities, 2) // Technology, Innovation
+	assert.Equal(t, "Technology", entities[0].Name)
+
 	t.Log("✅ GetTopEntities test passed")
 }
 
@@ -444,7 +724,7 @@ func TestRepositoryIntegration(t *testing.T) {
 	assert.Equal(t, 0.85, positiveArticle.SentimentScore)
 
 	// 4. Test GetTopEntities - filter by our test URL to avoid startup data interference
-	entities, err := repo.GetTopEntities(ctx, 5, []string{url})
+	entities, err := repo.GetTopEntities(ctx, 5, []string{url}, "")
 	require.NoError(t, err)
 	require.Len(t, entities, 3) // AI, Technology, Innovation
 
@@ -466,3 +746,426 @@ func TestRepositoryIntegration(t *testing.T) {
 
 	t.Log("✅ Complete repository integration test passed")
 }
+
+func TestStaleArticleRefresh(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+
+	page := "<html><head><title>Chips</title></head><body><article><p>AI chip demand keeps rising across data centers worldwide.</p></article></body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+	defer db.Exec("DELETE FROM articles WHERE url = $1", server.URL)
+
+	ctx := context.Background()
+	checked := time.Now().Add(-48 * time.Hour)
+	text := ingest.ExtractArticleText(page)
+	require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+		ID: uuid.New().String(), URL: server.URL, Title: "Chips", Content: text, URLHash: generateURLHash(server.URL),
+		Embedding: generateTestEmbedding(1536), ContentHash: ingest.ContentHash(text), LastRefreshedAt: &checked,
+	}))
+
+	stale, err := repo.GetStaleArticles(ctx, time.Now().Add(-24*time.Hour), 100)
+	require.NoError(t, err)
+	var article *domain.Article
+	for i := range stale {
+		if stale[i].URL == server.URL {
+			article = &stale[i]
+		}
+	}
+	require.NotNil(t, article, "article checked 48h ago must be stale")
+	assert.Equal(t, ingest.ContentHash(text), article.ContentHash)
+
+	service := &ingest.Service{Repo: repo, LLM: llm.NewMockClient()}
+	changed, err := service.Refresh(ctx, article)
+	require.NoError(t, err)
+	assert.False(t, changed, "unchanged content must not be re-analyzed")
+
+	refreshed, err := repo.GetArticleByURL(ctx, server.URL)
+	require.NoError(t, err)
+	require.NotNil(t, refreshed.LastRefreshedAt)
+	assert.True(t, refreshed.LastRefreshedAt.After(checked))
+
+	page = "<html><head><title>Chips</title></head><body><article><p>AI chip demand cooled sharply this quarter as orders slowed.</p></article></body></html>"
+	changed, err = service.Refresh(ctx, refreshed)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	refreshed, err = repo.GetArticleByURL(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, ingest.ContentHash(ingest.ExtractArticleText(page)), refreshed.ContentHash)
+}
+
+func TestIngestURLUsesContentHash(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+
+	page := "<html><head><title>Rates</title></head><body><article><p>The central bank held interest rates steady on Tuesday.</p></article></body></html>"
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	mirror := httptest.NewServer(handler)
+	defer mirror.Close()
+	defer db.Exec("DELETE FROM articles WHERE url = $1", server.URL)
+	defer db.Exec("DELETE FROM article_duplicates WHERE url = $1", mirror.URL)
+
+	ctx := context.Background()
+	var changes int
+	service := &ingest.Service{Repo: repo, LLM: llm.NewMockClient(),
+		OnContentChange: func(ctx context.Context, url string) { changes++ }}
+	require.NoError(t, service.IngestURL(ctx, server.URL))
+	first, err := repo.GetArticleByURL(ctx, server.URL)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+
+	// Unchanged content is a no-op
+	require.NoError(t, service.IngestURL(ctx, server.URL))
+	again, err := repo.GetArticleByURL(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, first.ContentHash, again.ContentHash)
+	assert.True(t, first.UpdatedAt.Equal(again.UpdatedAt), "unchanged content must not be re-analyzed")
+	assert.Zero(t, changes)
+
+	// The same text at another URL is linked instead of stored
+	require.NoError(t, service.IngestURL(ctx, mirror.URL))
+	dup, err := repo.GetDuplicateByURL(ctx, mirror.URL)
+	require.NoError(t, err)
+	require.NotNil(t, dup)
+	assert.Equal(t, first.ID, dup.CanonicalID)
+
+	// Changed content is re-analyzed in place
+	page = "<html><head><title>Rates</title></head><body><article><p>The central bank cut interest rates by half a point.</p></article></body></html>"
+	require.NoError(t, service.IngestURL(ctx, server.URL))
+	updated, err := repo.GetArticleByURL(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, updated.ID)
+	assert.Equal(t, ingest.ContentHash(ingest.ExtractArticleText(page)), updated.ContentHash)
+	assert.Equal(t, 1, changes)
+}
+
+func TestGetSentimentTrend(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -3)
+	for i, a := range []struct {
+		published time.Time
+		sentiment string
+		score     float64
+	}{
+		{day, "positive", 0.8},
+		{day.Add(2 * time.Hour), "neutral", 0.6},
+		{day.AddDate(0, 0, 1), "negative", 0.2},
+	} {
+		url := generateUniqueTestURL(fmt.Sprintf("trend-%d", i))
+		published := a.published
+		require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+			ID: uuid.New().String(), URL: url, Title: "Zyxwv trend article", URLHash: generateURLHash(url),
+			Embedding: generateTestEmbedding(1536), Sentiment: a.sentiment, SentimentScore: a.score, PublishedAt: &published,
+		}))
+	}
+
+	buckets, err := repo.GetSentimentTrend(ctx, "zyxwv", nil, day.AddDate(0, 0, -1), "day")
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, 2, buckets[0].Articles)
+	assert.InDelta(t, 0.7, buckets[0].AverageScore, 0.001)
+	assert.Equal(t, 1, buckets[0].Positive)
+	assert.Equal(t, 1, buckets[0].Neutral)
+	assert.Equal(t, 1, buckets[1].Negative)
+
+	_, err = repo.GetSentimentTrend(ctx, "zyxwv", nil, day, "hour")
+	assert.Error(t, err)
+}
+
+func TestGetEntityGraph(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	var urls []string
+	for _, names := range [][]string{{"OpenAI", "Sam Altman"}, {"openai", "Sam Altman", "Microsoft"}, {"Microsoft"}} {
+		url := generateUniqueTestURL("graph")
+		urls = append(urls, url)
+		var entities []domain.SemanticEntity
+		for _, n := range names {
+			entities = append(entities, domain.SemanticEntity{Name: n, Category: "organization", Confidence: 0.9})
+		}
+		require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+			ID: uuid.New().String(), URL: url, Title: "Graph article", URLHash: generateURLHash(url),
+			Embedding: generateTestEmbedding(1536), Entities: entities,
+		}))
+	}
+
+	graph, err := repo.GetEntityGraph(ctx, 10, 1, urls)
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 3)
+	assert.Equal(t, "openai", graph.Nodes[0].ID, "names are grouped case-insensitively")
+	assert.Equal(t, 2, graph.Nodes[0].Articles)
+
+	shared := map[string]int{}
+	for _, e := range graph.Edges {
+		shared[e.Source+"|"+e.Target] = e.Articles
+	}
+	assert.Equal(t, 2, shared["openai|sam altman"])
+	assert.Equal(t, 1, shared["microsoft|openai"])
+
+	graph, err = repo.GetEntityGraph(ctx, 10, 2, urls)
+	require.NoError(t, err)
+	assert.Len(t, graph.Edges, 1)
+}
+
+func TestArticleChunks(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	url := generateUniqueTestURL("chunks")
+	article := &domain.Article{
+		ID: uuid.New().String(), URL: url, Title: "Chunked", URLHash: generateURLHash(url),
+		Embedding: generateTestEmbedding(1536),
+	}
+	require.NoError(t, repo.UpsertArticle(ctx, article))
+	storedID := article.ID
+
+	// Re-upserting keeps the stored row's ID even if the caller generated a new one
+	article.ID = uuid.New().String()
+	require.NoError(t, repo.UpsertArticle(ctx, article))
+	assert.Equal(t, storedID, article.ID)
+
+	near := generateTestEmbedding(1536)
+	far := make([]float32, 1536)
+	for i := range far {
+		far[i] = float32(1536-i) * 0.001
+	}
+	require.NoError(t, repo.ReplaceArticleChunks(ctx, article.ID, []string{"first passage", "second passage"}, [][]float32{far, near}))
+
+	chunks, err := repo.SearchArticleChunks(ctx, near, 5, []string{url})
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "second passage", chunks[0].Text)
+	assert.Equal(t, 1, chunks[0].Index)
+	assert.Equal(t, url, chunks[0].URL)
+
+	// Replacing drops the previous passages
+	require.NoError(t, repo.ReplaceArticleChunks(ctx, article.ID, []string{"only passage"}, [][]float32{near}))
+	chunks, err = repo.SearchArticleChunks(ctx, near, 5, []string{url})
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "only passage", chunks[0].Text)
+}
+
+func TestGetArticlesBySentiment(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	var urls []string
+	for _, score := range []float64{0.1, 0.5, 0.9} {
+		url := generateUniqueTestURL("sentiment")
+		urls = append(urls, url)
+		require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+			ID: uuid.New().String(), URL: url, Title: "Sentiment article", URLHash: generateURLHash(url),
+			Embedding: generateTestEmbedding(1536), SentimentScore: score,
+		}))
+	}
+
+	positive, err := repo.GetArticlesBySentiment(ctx, nil, 0, urls, domain.ArticleFilter{}, domain.SentimentQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, positive, 1)
+	assert.Equal(t, 0.9, positive[0].SentimentScore)
+
+	negative, err := repo.GetArticlesBySentiment(ctx, generateTestEmbedding(1536), 10, urls, domain.ArticleFilter{}, domain.SentimentQuery{Negative: true, Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, negative, 3)
+	assert.Equal(t, 0.1, negative[0].SentimentScore)
+	assert.Equal(t, 0.9, negative[2].SentimentScore)
+
+	lo, hi := 0.3, 0.95
+	ranged, err := repo.GetArticlesBySentiment(ctx, nil, 0, urls, domain.ArticleFilter{}, domain.SentimentQuery{Negative: true, MinScore: &lo, MaxScore: &hi, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, ranged, 2)
+	assert.Equal(t, 0.5, ranged[0].SentimentScore)
+}
+
+func TestArticlesByTone(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	var urls []string
+	for _, tone := range []string{"Critical", "critical", "analytical"} {
+		url := generateUniqueTestURL("tone")
+		urls = append(urls, url)
+		require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+			ID: uuid.New().String(), URL: url, Title: "Tone article", URLHash: generateURLHash(url),
+			Embedding: generateTestEmbedding(1536), Tone: tone,
+		}))
+	}
+
+	articles, err := repo.GetArticlesByTone(ctx, "critical", urls, 10)
+	require.NoError(t, err)
+	assert.Len(t, articles, 2, "tone matching is case-insensitive")
+
+	counts, err := repo.GetToneCounts(ctx, urls)
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, domain.ToneCount{Tone: "critical", Articles: 2}, counts[0])
+	assert.Equal(t, domain.ToneCount{Tone: "analytical", Articles: 1}, counts[1])
+}
+
+func TestReindexSwitchesMethod(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	repo.VectorIndex.Method = repository.IndexHNSW
+	require.NoError(t, repo.Reindex(ctx))
+	indexes, err := repo.VectorIndexes(ctx)
+	require.NoError(t, err)
+	require.Len(t, indexes, 2)
+	for _, idx := range indexes {
+		assert.Equal(t, repository.IndexHNSW, idx.Method, idx.Name)
+	}
+
+	// Restore the schema's ivfflat indexes for the other tests
+	repo.VectorIndex = repository.DefaultVectorIndexConfig()
+	require.NoError(t, repo.Reindex(ctx))
+	indexes, err = repo.VectorIndexes(ctx)
+	require.NoError(t, err)
+	for _, idx := range indexes {
+		assert.Equal(t, repository.IndexIVFFlat, idx.Method, idx.Name)
+	}
+}
+
+func TestEntityCountsFollowArticles(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+	ctx := context.Background()
+
+	name := fmt.Sprintf("Entity Count %d", time.Now().UnixNano())
+	key := strings.ToLower(strings.ReplaceAll(name, " ", ""))
+	// An article is counted once per entity, however many spellings it uses
+	counted := func() int {
+		var n int
+		require.NoError(t, db.QueryRow(`SELECT COALESCE(SUM(articles), 0) FROM entity_articles WHERE entity_key = $1 AND category = ''`, key).Scan(&n))
+		return n
+	}
+
+	url := generateUniqueTestURL("entity-counts")
+	article := &domain.Article{
+		ID: uuid.New().String(), URL: url, URLHash: generateURLHash(url), Title: "Counted",
+		Embedding: generateTestEmbedding(1536),
+		Entities:  []domain.SemanticEntity{{Name: name, Confidence: 0.9}},
+	}
+	require.NoError(t, repo.UpsertArticle(ctx, article))
+	assert.Equal(t, 1, counted())
+
+	// Re-storing the article with a second spelling replaces its count instead of adding to it
+	article.Entities = append(article.Entities, domain.SemanticEntity{Name: strings.ToUpper(name), Confidence: 0.5})
+	require.NoError(t, repo.UpsertArticle(ctx, article))
+	assert.Equal(t, 1, counted())
+
+	// The corpus-wide ranking agrees with a ranking scoped to the article
+	corpus, err := repo.GetTopEntities(ctx, 1000, nil, "")
+	require.NoError(t, err)
+	scoped, err := repo.GetTopEntities(ctx, 10, []string{url}, "")
+	require.NoError(t, err)
+	require.Len(t, scoped, 1)
+	assert.Equal(t, 1, scoped[0].Count)
+	found := false
+	for _, e := range corpus {
+		if strings.EqualFold(e.Name, name) {
+			found = true
+			assert.Equal(t, scoped[0].Count, e.Count)
+		}
+	}
+	assert.True(t, found, "expected the entity in the corpus-wide ranking")
+
+	_, err = repo.DeleteArticleByURL(ctx, url)
+	require.NoError(t, err)
+	assert.Equal(t, 0, counted())
+}
+
+func TestArticleTagsAndNotes(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+	ctx := context.Background()
+
+	url := generateUniqueTestURL("tags")
+	article := &domain.Article{
+		ID: uuid.New().String(), URL: url, URLHash: generateURLHash(url), Title: "Tagged",
+		Embedding: generateTestEmbedding(1536),
+	}
+	require.NoError(t, repo.UpsertArticle(ctx, article))
+	tag := fmt.Sprintf("tag-%d", time.Now().UnixNano())
+	require.NoError(t, repo.AddArticleTags(ctx, article.ID, []string{tag, tag}))
+
+	urls, err := repo.GetArticleURLs(ctx, nil, domain.ArticleFilter{Tag: tag})
+	require.NoError(t, err)
+	assert.Equal(t, []string{url}, urls)
+
+	note := &domain.ArticleNote{ArticleID: article.ID, Text: "Check the figures"}
+	require.NoError(t, repo.AddArticleNote(ctx, note))
+	assert.NotEmpty(t, note.ID)
+	notes, err := repo.ListArticleNotes(ctx, article.ID)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "Check the figures", notes[0].Text)
+
+	// Tags and notes go with their article
+	_, err = repo.DeleteArticleByURL(ctx, url)
+	require.NoError(t, err)
+	tags, err := repo.GetArticleTags(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+	notes, err = repo.ListArticleNotes(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+}
+
+func TestCollectionFilter(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+	ctx := context.Background()
+
+	url := generateUniqueTestURL("collection")
+	article := &domain.Article{
+		ID: uuid.New().String(), URL: url, URLHash: generateURLHash(url), Title: "Collected",
+		Embedding: generateTestEmbedding(1536),
+	}
+	require.NoError(t, repo.UpsertArticle(ctx, article))
+
+	collection := &domain.Collection{Name: fmt.Sprintf("Collection %d", time.Now().UnixNano())}
+	require.NoError(t, repo.CreateCollection(ctx, collection))
+	defer repo.DeleteCollection(ctx, collection.ID)
+	assert.ErrorIs(t, repo.CreateCollection(ctx, &domain.Collection{Name: strings.ToUpper(collection.Name)}), repository.ErrCollectionExists)
+
+	require.NoError(t, repo.AddCollectionArticles(ctx, collection.ID, []string{article.ID, article.ID}))
+	for _, key := range []string{collection.ID, strings.ToLower(collection.Name)} {
+		urls, err := repo.GetArticleURLs(ctx, nil, domain.ArticleFilter{Collection: key})
+		require.NoError(t, err)
+		assert.Equal(t, []string{url}, urls)
+	}
+
+	got, err := repo.GetCollection(ctx, collection.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 1, got.Articles)
+
+	removed, err := repo.RemoveCollectionArticle(ctx, collection.ID, article.ID)
+	require.NoError(t, err)
+	assert.True(t, removed)
+}
@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScenarioPipeline runs planner + executor end to end against the database
+// using the shared scripted LLM instead of a live provider.
+func TestScenarioPipeline(t *testing.T) {
+	db, repo := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestData(t, db)
+
+	ctx := context.Background()
+	client, err := llm.LoadScenario("../testdata/scenarios/default.yaml")
+	require.NoError(t, err)
+
+	urls := []string{generateUniqueTestURL("scenario-a"), generateUniqueTestURL("scenario-b")}
+	for i, u := range urls {
+		emb, err := client.Embed(ctx, u)
+		require.NoError(t, err)
+		require.NoError(t, repo.UpsertArticle(ctx, &domain.Article{
+			ID:             uuid.New().String(),
+			URL:            u,
+			Title:          "Scenario article",
+			Summary:        "Summary of scenario article",
+			Embedding:      emb,
+			Sentiment:      "positive",
+			SentimentScore: 0.5 + float64(i)/10,
+			URLHash:        generateURLHash(u),
+		}))
+	}
+
+	ex := executor.NewExecutorWithCommands(repo, client)
+
+	plan, err := client.PlanQuery(ctx, "Compare these two articles")
+	require.NoError(t, err)
+	plan.Args["urls"] = []interface{}{urls[0], urls[1]}
+
+	resp, err := ex.Execute(ctx, plan, "Compare these two articles")
+	require.NoError(t, err)
+	assert.Equal(t, "compare_articles", resp.Task)
+	assert.Contains(t, resp.Answer, "Both articles cover the same event")
+	assert.Len(t, resp.Sources, 2)
+	assert.Equal(t, 1, client.CallCount("GenerateText"))
+}
@@ -0,0 +1,136 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"article-assistant/internal/alerts"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/repository"
+	"article-assistant/internal/webhook"
+)
+
+func TestAlertMatches(t *testing.T) {
+	low, high := 0.2, 0.8
+	article := domain.Article{
+		Title:          "Quarterly results",
+		SentimentScore: 0.1,
+		Entities:       []domain.SemanticEntity{{Name: "OpenAI"}},
+	}
+	cases := []struct {
+		name   string
+		search domain.SavedSearch
+		want   bool
+	}{
+		{"entity match", domain.SavedSearch{Topic: "openai"}, true},
+		{"no mention", domain.SavedSearch{Topic: "climate"}, false},
+		{"below minimum", domain.SavedSearch{Topic: "openai", MinSentiment: &low}, false},
+		{"within maximum", domain.SavedSearch{Topic: "OpenAI", MaxSentiment: &high}, true},
+		{"empty topic", domain.SavedSearch{}, false},
+	}
+	for _, c := range cases {
+		if got := alerts.Matches(c.search, article); got != c.want {
+			t.Errorf("%s: Matches() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestEvaluatorRecordsNewMatchesOnce(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/before", Title: "Climate talks"})
+
+	maxScore := 0.4
+	search := &domain.SavedSearch{Topic: "climate", MaxSentiment: &maxScore}
+	if err := store.CreateSavedSearch(ctx, search); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, a := range []*domain.Article{
+		{URL: "https://example.com/negative", Title: "Climate damage grows", Sentiment: "negative", SentimentScore: 0.1},
+		{URL: "https://example.com/positive", Title: "Climate deal reached", SentimentScore: 0.9},
+		{URL: "https://example.com/other", Title: "Chip shortage", SentimentScore: 0.1},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	var pushed []webhook.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhook.Payload
+		json.NewDecoder(r.Body).Decode(&p)
+		pushed = append(pushed, p)
+	}))
+	defer server.Close()
+
+	evaluator := alerts.NewEvaluator(store)
+	evaluator.OnMatch = webhook.AlertHook(webhook.NewNotifier([]string{server.URL}, ""))
+	matched, err := evaluator.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if matched != 1 {
+		t.Fatalf("expected 1 new alert, got %d", matched)
+	}
+	found, _ := store.ListAlerts(ctx, search.ID, 10)
+	if len(found) != 1 || found[0].URL != "https://example.com/negative" || found[0].Sentiment != "negative" {
+		t.Errorf("unexpected alerts: %+v", found)
+	}
+	if len(pushed) != 1 || pushed[0].Event != webhook.EventAlertMatched || pushed[0].SearchID != search.ID || pushed[0].SearchTopic != "climate" {
+		t.Errorf("unexpected webhook payloads: %+v", pushed)
+	}
+
+	if matched, _ := evaluator.RunOnce(ctx); matched != 0 {
+		t.Errorf("expected no new alerts on the second run, got %d", matched)
+	}
+
+	if deleted, _ := store.DeleteSavedSearch(ctx, search.ID); !deleted {
+		t.Fatal("expected the saved search to be deleted")
+	}
+	if found, _ := store.ListAlerts(ctx, "", 10); len(found) != 0 {
+		t.Errorf("expected alerts to be deleted with their search, got %+v", found)
+	}
+}
+
+func TestSQLiteStorePersistsSavedSearches(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	store, err := repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	search := &domain.SavedSearch{Topic: "climate"}
+	store.CreateSavedSearch(ctx, search)
+	alert := &domain.Alert{SearchID: search.ID, ArticleID: "a1", URL: "https://example.com/a"}
+	if added, err := store.AddAlert(ctx, alert); err != nil || !added {
+		t.Fatalf("add alert: %v, %v", added, err)
+	}
+	if added, _ := store.AddAlert(ctx, &domain.Alert{SearchID: search.ID, ArticleID: "a1"}); added {
+		t.Error("expected a repeated match to be ignored")
+	}
+	db.Close()
+
+	db, err = sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	store, err = repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	searches, _ := store.ListSavedSearches(ctx)
+	if len(searches) != 1 || searches[0].ID != search.ID || searches[0].Topic != "climate" {
+		t.Errorf("expected saved search to survive reopen, got %+v", searches)
+	}
+	if found, _ := store.ListAlerts(ctx, search.ID, 10); len(found) != 1 || found[0].ID != alert.ID {
+		t.Errorf("expected alert to survive reopen, got %+v", found)
+	}
+}
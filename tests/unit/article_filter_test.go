@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/repository"
+)
+
+func TestSourceDomain(t *testing.T) {
+	cases := map[string]string{
+		"https://www.TechCrunch.com/2025/01/01/story": "techcrunch.com",
+		"http://news.bbc.co.uk/article":               "news.bbc.co.uk",
+		"https://example.com:8443/a":                  "example.com",
+		"not a url":                                   "",
+	}
+	for in, want := range cases {
+		if got := ingest.SourceDomain(in); got != want {
+			t.Errorf("SourceDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"today":      time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		"last_week":  now.AddDate(0, 0, -7),
+		"3d":         now.AddDate(0, 0, -3),
+		"2025-01-31": time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		got, ok := executor.ParseDate(in, now)
+		if !ok || !got.Equal(want) {
+			t.Errorf("ParseDate(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	if _, ok := executor.ParseDate("someday", now); ok {
+		t.Error("expected an unknown value to be rejected")
+	}
+}
+
+func TestMemoryStoreFiltersByPublicationAndSource(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	jan := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	for _, a := range []*domain.Article{
+		{URL: "https://techcrunch.com/jan", SourceDomain: "techcrunch.com", PublishedAt: &jan, Tone: "analytical"},
+		{URL: "https://techcrunch.com/mar", SourceDomain: "techcrunch.com", PublishedAt: &mar, Tone: "alarmist"},
+		{URL: "https://bbc.co.uk/mar", SourceDomain: "bbc.co.uk", PublishedAt: &mar, Tone: "neutral"},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	urls, err := store.GetArticleURLs(ctx, nil, domain.ArticleFilter{SourceDomain: "techcrunch", PublishedAfter: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("get urls: %v", err)
+	}
+	if len(urls) != 1 || urls[0] != "https://techcrunch.com/mar" {
+		t.Errorf("expected only the March TechCrunch article, got %v", urls)
+	}
+	before, _ := store.GetArticleURLs(ctx, []string{"https://techcrunch.com/jan", "https://bbc.co.uk/mar"}, domain.ArticleFilter{PublishedBefore: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)})
+	if len(before) != 1 || before[0] != "https://techcrunch.com/jan" {
+		t.Errorf("expected the date range to apply within the given URLs, got %v", before)
+	}
+
+	cmd := &executor.FilterByToneCommand{Repo: store, ResponseGenerator: executor.NewResponseGenerator(store)}
+	resp, err := cmd.Execute(ctx, &domain.Plan{Command: "filter_by_tone", Args: map[string]interface{}{"source": "bbc.co.uk"}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	counts, _ := resp.Data.([]domain.ToneCount)
+	if len(counts) != 1 || counts[0].Tone != "neutral" {
+		t.Errorf("expected tones of BBC articles only, got %+v", resp.Data)
+	}
+
+	resp, _ = cmd.Execute(ctx, &domain.Plan{Command: "filter_by_tone", Args: map[string]interface{}{"source": "reuters.com"}}, "")
+	if resp.Answer != "No articles match the given date, source, author or section filters" {
+		t.Errorf("expected a no-match answer, got %q", resp.Answer)
+	}
+}
@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/ingest"
+)
+
+func TestChunkTextKeepsParagraphsTogether(t *testing.T) {
+	para := strings.Repeat("word ", 50) // 250 chars
+	text := strings.Join([]string{para, para, para, para, para}, "\n\n")
+
+	chunks := ingest.ChunkText(text, 600)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if len(c) > 600 {
+			t.Errorf("chunk %d has %d chars, limit 600", i, len(c))
+		}
+	}
+	if !strings.Contains(chunks[0], "\n\n") {
+		t.Error("expected the first chunk to combine two paragraphs")
+	}
+}
+
+func TestChunkTextSplitsLongParagraphs(t *testing.T) {
+	sentence := "The committee approved the budget after a long debate. "
+	chunks := ingest.ChunkText(strings.Repeat(sentence, 40), 500)
+	for i, c := range chunks {
+		if len(c) > 500 {
+			t.Errorf("chunk %d has %d chars, limit 500", i, len(c))
+		}
+		if !strings.HasSuffix(c, ".") {
+			t.Errorf("chunk %d should end at a sentence boundary: %q", i, c[len(c)-20:])
+		}
+	}
+
+	// Text without spaces or sentence punctuation is cut on rune boundaries
+	for i, c := range ingest.ChunkText(strings.Repeat("政府宣布", 200), 100) {
+		if !utf8.ValidString(c) || len(c) > 100 {
+			t.Errorf("chunk %d is invalid or too long (%d bytes)", i, len(c))
+		}
+	}
+}
+
+func TestAskPromptNumbersPassages(t *testing.T) {
+	prompt := executor.AskPrompt("What did Sam Altman say about confidentiality?", []domain.ArticleChunk{
+		{URL: "https://example.com/a", Title: "Altman interview", Text: "Altman said conversations should stay private."},
+		{URL: "https://example.com/b", Title: "Policy update", Text: "The company updated its privacy policy."},
+	})
+	for _, want := range []string{"[1] Altman interview (https://example.com/a)", "[2] Policy update", "Question: What did Sam Altman say"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected %q in prompt:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestAskPromptNumbersPassagesByArticle(t *testing.T) {
+	passages := []domain.ArticleChunk{
+		{ArticleID: "a", URL: "https://example.com/a", Title: "Altman interview", Text: "First passage."},
+		{ArticleID: "b", URL: "https://example.com/b", Title: "Policy update", Text: "Second passage."},
+		{ArticleID: "a", URL: "https://example.com/a", Title: "Altman interview", Text: "Third passage."},
+	}
+	prompt := executor.AskPrompt("What changed?", passages)
+	if !strings.Contains(prompt, "[1] Altman interview (https://example.com/a)\nThird passage.") || strings.Contains(prompt, "[3]") {
+		t.Errorf("expected passages of one article to share its number:\n%s", prompt)
+	}
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"article-assistant/internal/budget"
+)
+
+func TestBudgetAllowsStepsWithTimeLeft(t *testing.T) {
+	ctx, cancel := budget.NewContext(context.Background(), time.Minute)
+	defer cancel()
+
+	if !budget.Allow(ctx, budget.StepTopicValidation, 10*time.Second) {
+		t.Error("expected a step needing 10s to run with a minute left")
+	}
+	if budget.Allow(ctx, budget.StepClusterLabels, 2*time.Minute) {
+		t.Error("expected a step needing 2m to be skipped with a minute left")
+	}
+	budget.Skip(ctx, budget.StepClusterLabels)
+	if got := budget.Skipped(ctx); len(got) != 1 || got[0] != budget.StepClusterLabels {
+		t.Errorf("expected one skipped step recorded once, got %v", got)
+	}
+}
+
+func TestBudgetKeepsEarlierParentDeadline(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	ctx, cancel := budget.NewContext(parent, time.Hour)
+	defer cancel()
+
+	if left, ok := budget.Remaining(ctx); !ok || left > time.Second {
+		t.Errorf("expected the parent's 1s deadline, got %v", left)
+	}
+}
+
+func TestBudgetWithoutDeadlineAllowsEverything(t *testing.T) {
+	ctx := context.Background()
+	if !budget.Allow(ctx, budget.StepPlanner, time.Hour) {
+		t.Error("expected no deadline to allow every step")
+	}
+	if got := budget.Skipped(ctx); got != nil {
+		t.Errorf("expected no budget to record nothing, got %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/cache"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+)
+
+// memoryBackend is an in-memory cache.Backend
+type memoryBackend struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{entries: make(map[string][]byte)}
+}
+
+func (b *memoryBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.entries[key], nil
+}
+
+func (b *memoryBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[key] = value
+	return nil
+}
+
+func (b *memoryBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for k := range b.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(b.entries, k)
+		}
+	}
+	return nil
+}
+
+func (b *memoryBackend) CleanExpired(ctx context.Context) error { return nil }
+
+// countingEmbedder counts Embed calls reaching the provider
+type countingEmbedder struct {
+	llm.Client
+	EmbedCallCount int
+}
+
+func (c *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	c.EmbedCallCount++
+	return c.Client.Embed(ctx, text)
+}
+
+func TestCacheServiceRoundTripsThroughBackend(t *testing.T) {
+	ctx := context.Background()
+	svc := cache.NewService(newMemoryBackend())
+	req := domain.ChatRequest{Query: "Top entities"}
+
+	if got, err := svc.GetCachedResponse(ctx, req); err != nil || got != nil {
+		t.Fatalf("expected a miss, got %v, %v", got, err)
+	}
+	if err := svc.SetCachedResponse(ctx, req, &domain.ChatResponse{Answer: "OpenAI", Task: "get_top_entities"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := svc.GetCachedResponse(ctx, req)
+	if err != nil || got == nil || got.Answer != "OpenAI" {
+		t.Fatalf("expected cached response, got %+v, %v", got, err)
+	}
+}
+
+func TestInvalidateAllKeepsEmbeddings(t *testing.T) {
+	ctx := context.Background()
+	backend := newMemoryBackend()
+	svc := cache.NewService(backend)
+	inner := &countingEmbedder{Client: llm.NewMockClient()}
+	client := cache.WrapEmbeddings(inner, backend, time.Hour)
+
+	req := domain.ChatRequest{Query: "What articles discuss AI?"}
+	svc.SetCachedResponse(ctx, req, &domain.ChatResponse{Answer: "cached"})
+	client.Embed(ctx, "AI")
+
+	if err := svc.InvalidateAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := svc.GetCachedResponse(ctx, req); got != nil {
+		t.Error("expected chat responses to be cleared")
+	}
+	client.Embed(ctx, "AI")
+	if inner.EmbedCallCount != 1 {
+		t.Errorf("expected embeddings to survive invalidation, got %d provider calls", inner.EmbedCallCount)
+	}
+}
+
+func TestEmbeddingCacheSkipsLongTexts(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingEmbedder{Client: llm.NewMockClient()}
+	client := cache.WrapEmbeddings(inner, newMemoryBackend(), time.Hour)
+
+	long := strings.Repeat("article body ", 100)
+	client.Embed(ctx, long)
+	client.Embed(ctx, long)
+	if inner.EmbedCallCount != 2 {
+		t.Errorf("expected long texts to bypass the cache, got %d provider calls", inner.EmbedCallCount)
+	}
+}
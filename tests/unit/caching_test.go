@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
 )
 
 // MockLLMClient for testing caching functionality
@@ -16,7 +17,7 @@ type MockLLMClient struct {
 	ShouldFail               bool
 }
 
-func (m *MockLLMClient) Summarize(ctx context.Context, text string) (string, error) {
+func (m *MockLLMClient) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
 	m.SummarizeCallCount++
 	if m.ShouldFail {
 		return "", fmt.Errorf("mock error")
@@ -95,7 +96,7 @@ func (m *MockRepo) GetMostPositiveByTopic(ctx context.Context, topic string, url
 	return nil, nil
 }
 
-func (m *MockRepo) GetTopEntities(ctx context.Context, limit int, urls []string) ([]domain.SemanticEntity, error) {
+func (m *MockRepo) GetTopEntities(ctx context.Context, limit int, urls []string, category string) ([]domain.TopEntity, error) {
 	return nil, nil
 }
 
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"article-assistant/internal/chaos"
+	"article-assistant/internal/llm"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestChaosLLMWrapper(t *testing.T) {
+	injector := chaos.NewInjector()
+	client := chaos.WrapLLM(llm.NewMockClient(), injector)
+	ctx := context.Background()
+
+	// No failures configured: calls pass through
+	if _, err := client.PlanQuery(ctx, "summary of https://example.com"); err != nil {
+		t.Fatalf("unexpected error with chaos disabled: %v", err)
+	}
+
+	injector.SetConfig(chaos.Config{LLMErrorRate: 1})
+	if _, err := client.Summarize(ctx, "text", llm.SummaryOptions{}); !errors.Is(err, chaos.ErrInjected) {
+		t.Errorf("expected injected error, got %v", err)
+	}
+
+	injector.SetConfig(chaos.Config{LLMMalformedJSON: true})
+	if _, err := client.PlanQuery(ctx, "summary"); !errors.Is(err, chaos.ErrInjected) {
+		t.Errorf("expected malformed plan error, got %v", err)
+	}
+
+	injector.SetConfig(chaos.Config{LLMLatencyMS: 20})
+	start := time.Now()
+	if _, err := client.GenerateText(ctx, "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected injected latency, call took %v", elapsed)
+	}
+
+	stats := injector.Stats()
+	if stats.LLMErrors != 1 || stats.LLMMalformed != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	injector.Reset()
+	if stats := injector.Stats(); stats != (chaos.Stats{}) {
+		t.Errorf("expected reset stats, got %+v", stats)
+	}
+}
+
+func TestChaosIngestAndDBHooks(t *testing.T) {
+	injector := chaos.NewInjector()
+	if err := injector.IngestHook(context.Background(), "https://example.com"); err != nil {
+		t.Errorf("expected no ingest failure by default, got %v", err)
+	}
+	if err := injector.DBError(); err != nil {
+		t.Errorf("expected no DB failure by default, got %v", err)
+	}
+
+	injector.SetConfig(chaos.Config{IngestFailureRate: 1, DBErrorRate: 1})
+	if err := injector.IngestHook(context.Background(), "https://example.com"); !errors.Is(err, chaos.ErrInjected) {
+		t.Errorf("expected ingest failure, got %v", err)
+	}
+	if err := injector.DBError(); !errors.Is(err, chaos.ErrInjected) {
+		t.Errorf("expected DB failure, got %v", err)
+	}
+}
+
+func TestChaosDriverFailsPreparedStatements(t *testing.T) {
+	base, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer base.Close()
+
+	injector := chaos.NewInjector()
+	chaos.RegisterDriver("sqlite-chaos-test", base.Driver(), injector)
+	db, err := sql.Open("sqlite-chaos-test", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	stmt, err := db.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	defer stmt.Close()
+	var n int
+	if err := stmt.QueryRow().Scan(&n); err != nil || n != 1 {
+		t.Fatalf("expected 1 from the prepared statement, got %d, %v", n, err)
+	}
+
+	// A statement prepared before failures were switched on must fail too
+	injector.SetConfig(chaos.Config{DBErrorRate: 1})
+	if err := stmt.QueryRow().Scan(&n); !errors.Is(err, chaos.ErrInjected) {
+		t.Errorf("expected injected failure from the prepared statement, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestRequestArgsMapsStructuredFields(t *testing.T) {
+	args, err := executor.RequestArgs(domain.ChatRequest{
+		URLs: []string{"https://example.com/a"}, Topic: " rates ", DateFrom: "2025-07-01", DateTo: "yesterday", Limit: 5,
+	})
+	if err != nil {
+		t.Fatalf("request args: %v", err)
+	}
+	if urls, _ := args["urls"].([]interface{}); len(urls) != 1 || urls[0] != "https://example.com/a" {
+		t.Errorf("unexpected urls %v", args["urls"])
+	}
+	if args["filter"] != "rates" || args["published_after"] != "2025-07-01" || args["published_before"] != "yesterday" || args["limit"] != 5.0 {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	if _, err := executor.RequestArgs(domain.ChatRequest{DateFrom: "next tuesday"}); err == nil || !strings.Contains(err.Error(), "date_from") {
+		t.Errorf("expected an invalid date_from error, got %v", err)
+	}
+}
+
+func TestRequestPlanSkipsThePlanner(t *testing.T) {
+	args, _ := executor.RequestArgs(domain.ChatRequest{URLs: []string{"https://example.com/a", "https://example.com/b"}})
+	plan, err := executor.RequestPlan("compare", args)
+	if err != nil {
+		t.Fatalf("request plan: %v", err)
+	}
+	if plan.Command != "compare_articles" || plan.Router != domain.RouterRequest {
+		t.Errorf("expected a compare_articles plan from the request, got %+v", plan)
+	}
+
+	if _, err := executor.RequestPlan("filter_by_specific_topic", map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), "topic") {
+		t.Errorf("expected a missing topic error, got %v", err)
+	}
+	if _, err := executor.RequestPlan("no_such_command", args); err == nil {
+		t.Error("expected an unknown task error")
+	}
+}
+
+func TestApplyArgsOverridesPlannerArgs(t *testing.T) {
+	plan := &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "rates", "limit": 2.0}}
+	executor.ApplyArgs(plan, map[string]interface{}{"limit": 10.0})
+	if plan.Args["filter"] != "rates" || plan.Args["limit"] != 10.0 {
+		t.Errorf("expected the request limit to replace the planner's, got %v", plan.Args)
+	}
+}
+
+func TestValidateQueryAllowsOrdinaryText(t *testing.T) {
+	for _, q := range []string{
+		"What's OpenAI's strategy?",
+		`Articles mentioning "select committee"`,
+		"SELECT * FROM articles; DROP TABLE articles--",
+		"Résumé of the EU–US deal\nin two lines",
+	} {
+		if err := executor.ValidateQuery(q); err != nil {
+			t.Errorf("ValidateQuery(%q) = %v, want nil", q, err)
+		}
+	}
+	for _, q := range []string{
+		strings.Repeat("a", executor.MaxQueryLength+1),
+		"rates\x00",
+		"rates\x1b[2J",
+		"\xff\xfe",
+	} {
+		if err := executor.ValidateQuery(q); err == nil {
+			t.Errorf("ValidateQuery(%.20q) = nil, want an error", q)
+		}
+	}
+	if err := executor.ValidateQuery(strings.Repeat("é", executor.MaxQueryLength)); err != nil {
+		t.Errorf("expected the limit to count characters, not bytes, got %v", err)
+	}
+}
+
+func TestRequestArgsRejectsInvalidQueryAndTopic(t *testing.T) {
+	if _, err := executor.RequestArgs(domain.ChatRequest{Query: "rates\x00"}); err == nil || !strings.Contains(err.Error(), "invalid query") {
+		t.Errorf("expected an invalid query error, got %v", err)
+	}
+	if _, err := executor.RequestArgs(domain.ChatRequest{Topic: strings.Repeat("a", executor.MaxQueryLength+1)}); err == nil || !strings.Contains(err.Error(), "invalid topic") {
+		t.Errorf("expected an invalid topic error, got %v", err)
+	}
+	args, err := executor.RequestArgs(domain.ChatRequest{Query: "Who's quoted?", Topic: "O'Brien's \"select\" panel"})
+	if err != nil || args["filter"] != `O'Brien's "select" panel` {
+		t.Errorf("RequestArgs() = %v, %v; want the topic kept as written", args, err)
+	}
+}
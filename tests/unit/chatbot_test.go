@@ -0,0 +1,290 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/chatbot"
+	"article-assistant/internal/domain"
+)
+
+// fakeChatter records chat requests and answers each with one source
+type fakeChatter struct {
+	mu   sync.Mutex
+	reqs []domain.ChatRequest
+}
+
+func (f *fakeChatter) Chat(ctx context.Context, req domain.ChatRequest) (*domain.ChatResponse, error) {
+	f.mu.Lock()
+	f.reqs = append(f.reqs, req)
+	f.mu.Unlock()
+	return &domain.ChatResponse{
+		Answer:  "Answer to " + req.Query + " <b>",
+		Sources: []domain.Source{{URL: "https://example.com/a?x=1&y=2", Title: "Article [A]"}},
+	}, nil
+}
+
+func (f *fakeChatter) requests() []domain.ChatRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]domain.ChatRequest(nil), f.reqs...)
+}
+
+func TestTelegramAdapterAnswersQuestions(t *testing.T) {
+	updates := `[
+		{"update_id": 1, "message": {"message_id": 10, "from": {"id": 7}, "chat": {"id": 42, "type": "private"}, "text": "What is new?"}},
+		{"update_id": 2, "message": {"message_id": 11, "from": {"id": 8}, "chat": {"id": -100, "type": "group"}, "text": "/ask@assistant_bot top entities"}},
+		{"update_id": 3, "message": {"message_id": 12, "from": {"id": 8}, "chat": {"id": -100, "type": "group"}, "text": "/ask@other_bot hello"}},
+		{"update_id": 4, "message": {"message_id": 13, "from": {"id": 8}, "chat": {"id": -100, "type": "group"}, "text": "just chatting"}},
+		{"update_id": 5, "message": {"message_id": 14, "from": {"id": 7}, "chat": {"id": 42, "type": "private"}, "text": "/start"}}
+	]`
+
+	var mu sync.Mutex
+	var sent []map[string]interface{}
+	var offsets []float64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&params)
+		result := "true"
+		switch strings.TrimPrefix(r.URL.Path, "/bottest-token/") {
+		case "getMe":
+			result = `{"id": 1, "username": "assistant_bot"}`
+		case "getUpdates":
+			mu.Lock()
+			offsets = append(offsets, params["offset"].(float64))
+			first := len(offsets) == 1
+			mu.Unlock()
+			result = "[]"
+			if first {
+				result = updates
+			} else {
+				time.Sleep(10 * time.Millisecond)
+			}
+		case "sendChatAction":
+		case "sendMessage":
+			mu.Lock()
+			sent = append(sent, params)
+			if len(sent) == 3 {
+				defer cancel()
+			}
+			mu.Unlock()
+		default:
+			w.Write([]byte(`{"ok": false, "description": "Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "result": ` + result + `}`))
+		w.(http.Flusher).Flush()
+	}))
+	defer srv.Close()
+
+	chat := &fakeChatter{}
+	adapter := &chatbot.TelegramAdapter{Token: "test-token", APIURL: srv.URL}
+	done := make(chan error, 1)
+	go func() { done <- adapter.Run(ctx, chatbot.New(adapter, chat).Handle) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Run = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("adapter did not send three answers")
+	}
+
+	reqs := chat.requests()
+	if len(reqs) != 2 {
+		t.Fatalf("chat requests = %+v, want the private question and the /ask command", reqs)
+	}
+	queries := map[string]string{}
+	for _, r := range reqs {
+		queries[r.Query] = r.SessionID
+	}
+	if queries["What is new?"] != "telegram:42" || queries["top entities"] != "telegram:-100" {
+		t.Errorf("chat requests = %+v, want per-chat sessions", reqs)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if offsets[len(offsets)-1] != 6 {
+		t.Errorf("last offset = %v, want 6 after the first batch", offsets[len(offsets)-1])
+	}
+	var help bool
+	for _, m := range sent {
+		text := m["text"].(string)
+		if m["parse_mode"] != "HTML" {
+			t.Errorf("sendMessage parse_mode = %v, want HTML", m["parse_mode"])
+		}
+		if strings.HasPrefix(text, "Ask me about the stored news articles") {
+			help = m["chat_id"] == "42" && m["reply_to_message_id"].(float64) == 14
+			continue
+		}
+		for _, want := range []string{"&lt;b&gt;", "<b>Sources</b>", `<a href="https://example.com/a?x=1&amp;y=2">Article [A]</a>`} {
+			if !strings.Contains(text, want) {
+				t.Errorf("answer %q missing %s", text, want)
+			}
+		}
+	}
+	if !help {
+		t.Errorf("/start was not answered with the help text: %+v", sent)
+	}
+}
+
+func TestDiscordAdapterAnswersSlashCommand(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edits := make(chan map[string]interface{}, 1)
+	var editPath string
+	discordAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		editPath = r.Method + " " + r.URL.Path
+		w.Write([]byte(`{}`))
+		w.(http.Flusher).Flush()
+		edits <- body
+	}))
+	defer discordAPI.Close()
+
+	chat := &fakeChatter{}
+	adapter := &chatbot.DiscordAdapter{ApplicationID: "app", PublicKey: hex.EncodeToString(pub), APIURL: discordAPI.URL}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoint, err := adapter.Endpoint(ctx, chatbot.New(adapter, chat).Handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(body string, key ed25519.PrivateKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("X-Signature-Timestamp", "1700000000")
+		req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte("1700000000"+body))))
+		rec := httptest.NewRecorder()
+		endpoint.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(`{"type": 1}`, priv); rec.Code != 200 || strings.TrimSpace(rec.Body.String()) != `{"type":1}` {
+		t.Errorf("PING = %d %s, want PONG", rec.Code, rec.Body.String())
+	}
+	_, other, _ := ed25519.GenerateKey(nil)
+	if rec := post(`{"type": 1}`, other); rec.Code != http.StatusUnauthorized {
+		t.Errorf("badly signed PING = %d, want 401", rec.Code)
+	}
+
+	command := `{"type": 2, "application_id": "app", "token": "tok", "channel_id": "55", "member": {"user": {"id": "9"}},
+		"data": {"name": "ask", "options": [{"name": "question", "type": 3, "value": "Summarize the news"}]}}`
+	if rec := post(command, priv); strings.TrimSpace(rec.Body.String()) != `{"type":5}` {
+		t.Fatalf("/ask = %d %s, want a deferred response", rec.Code, rec.Body.String())
+	}
+
+	var edit map[string]interface{}
+	select {
+	case edit = <-edits:
+	case <-time.After(5 * time.Second):
+		t.Fatal("answer was not sent")
+	}
+	if editPath != "PATCH /webhooks/app/tok/messages/@original" {
+		t.Errorf("answer sent with %s, want the original response edited", editPath)
+	}
+	if reqs := chat.requests(); len(reqs) != 1 || reqs[0].Query != "Summarize the news" || reqs[0].SessionID != "discord:55" {
+		t.Errorf("chat requests = %+v", reqs)
+	}
+	embed := edit["embeds"].([]interface{})[0].(map[string]interface{})
+	if !strings.HasPrefix(embed["description"].(string), "Answer to Summarize the news") {
+		t.Errorf("embed description = %v", embed["description"])
+	}
+	field := embed["fields"].([]interface{})[0].(map[string]interface{})
+	if field["name"] != "Sources" || field["value"] != "1. [Article (A)](https://example.com/a?x=1&y=2)" {
+		t.Errorf("sources field = %v", field)
+	}
+}
+
+func TestDiscordAdapterRejectsInvalidPublicKey(t *testing.T) {
+	adapter := &chatbot.DiscordAdapter{PublicKey: "not-hex"}
+	if _, err := adapter.Endpoint(context.Background(), nil); err == nil {
+		t.Error("Endpoint accepted an invalid public key")
+	}
+}
+
+func TestChatbotRequestAndReply(t *testing.T) {
+	req := chatbot.ChatRequest(chatbot.Message{Platform: "telegram", ChatID: "42", Text: "  What happened?  "})
+	if req.Query != "What happened?" || req.SessionID != "telegram:42" {
+		t.Errorf("ChatRequest = %+v", req)
+	}
+
+	bot := chatbot.New(nil, &fakeChatter{})
+	resp, err := bot.Handle(context.Background(), chatbot.Message{Platform: "discord", ChatID: "1", Text: " "})
+	if err != nil || resp.Answer != chatbot.HelpText {
+		t.Errorf("empty question = %+v, %v; want the help text", resp, err)
+	}
+
+	sources := make([]domain.Source, 8)
+	for i := range sources {
+		sources[i] = domain.Source{URL: "https://example.com/" + string(rune('a'+i))}
+	}
+	sources[2].URL = ""
+	r := chatbot.NewReply(&domain.ChatResponse{
+		Answer:    "  The answer.  ",
+		Sources:   sources,
+		Grounding: &domain.Grounding{Score: 0.4, Low: true},
+		Degraded:  []string{"rerank"},
+	}, nil)
+	if r.Text != "The answer." || r.Failed || len(r.Sources) != chatbot.MaxSources || r.Sources[2].URL != "https://example.com/d" {
+		t.Errorf("NewReply = %+v", r)
+	}
+	if len(r.Notes) != 2 || !strings.Contains(r.Notes[0], "40%") {
+		t.Errorf("notes = %q, want low grounding and partial answer", r.Notes)
+	}
+
+	failed := chatbot.NewReply(nil, &api.Error{StatusCode: 503, APIError: domain.APIError{Code: domain.ErrCodeLLMBusy}})
+	if !failed.Failed || !strings.Contains(failed.Text, "busy") {
+		t.Errorf("busy reply = %+v", failed)
+	}
+}
+
+func TestChatbotErrorText(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{context.DeadlineExceeded, "took too long"},
+		{&api.Error{StatusCode: 400, APIError: domain.APIError{Code: domain.ErrCodeBadRequest, Message: "invalid query"}}, "invalid query"},
+		{&api.Error{StatusCode: 401, APIError: domain.APIError{Code: domain.ErrCodeUnauthorized, Message: "secret detail"}}, "API key"},
+		{&api.Error{StatusCode: 500, APIError: domain.APIError{Code: domain.ErrCodeInternal, Message: "db down"}}, "something went wrong"},
+		{io.ErrUnexpectedEOF, "something went wrong"},
+	} {
+		if got := chatbot.ErrorText(tc.err); !strings.Contains(got, tc.want) || strings.Contains(got, "db down") {
+			t.Errorf("ErrorText(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestTelegramHTMLFitsMessageLimit(t *testing.T) {
+	long := strings.Repeat("é", 5000)
+	out := chatbot.TelegramHTML(chatbot.Reply{Text: long, Notes: []string{"a & b"}})
+	if n := len([]rune(out)); n > 4096 {
+		t.Errorf("message has %d characters, want at most 4096", n)
+	}
+	if !strings.HasSuffix(out, "<i>a &amp; b</i>") || !strings.Contains(out, "…") {
+		t.Errorf("message tail = %q", out[len(out)-40:])
+	}
+	if embed, _ := json.Marshal(chatbot.DiscordEmbed(chatbot.Reply{Text: long, Failed: true})); !bytes.Contains(embed, []byte(`"parse":[]`)) {
+		t.Errorf("Discord embed allows mentions: %s", embed)
+	}
+}
@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+)
+
+func TestSuggestCommands(t *testing.T) {
+	tests := []struct {
+		query, unknown string
+		first          string
+	}{
+		{"What's up with https://example.com/a?", "", "summary"},
+		{"https://example.com/a vs https://example.com/b", "", "compare_articles"},
+		{"how positive is https://example.com/a", "rate_article", "get_sentiment"},
+		{"overview of everything", "", "cluster_articles"},
+	}
+	for _, tt := range tests {
+		got := executor.SuggestCommands(tt.query, tt.unknown)
+		if len(got) == 0 || got[0].Command != tt.first {
+			t.Errorf("SuggestCommands(%q, %q) = %+v; want %s first", tt.query, tt.unknown, got, tt.first)
+		}
+		if len(got) > 3 {
+			t.Errorf("SuggestCommands(%q) returned %d suggestions; want at most 3", tt.query, len(got))
+		}
+	}
+
+	// A single URL never suggests a multi-article comparison
+	for _, s := range executor.SuggestCommands("https://example.com/a", "") {
+		if s.Command == "compare_articles" {
+			t.Error("compare_articles suggested for a single URL")
+		}
+	}
+}
+
+func TestClarify(t *testing.T) {
+	resp := executor.Clarify("Compare https://example.com/a and https://example.com/b", "contrast")
+	if resp.ResponseType != domain.ResponseClarification {
+		t.Errorf("ResponseType = %q, want %q", resp.ResponseType, domain.ResponseClarification)
+	}
+	c, ok := resp.Data.(executor.Clarification)
+	if !ok {
+		t.Fatalf("Data = %T, want executor.Clarification", resp.Data)
+	}
+	if !strings.HasPrefix(c.Question, "I couldn't tell what you're asking for. Did you mean a comparison") || c.UnknownCommand != "contrast" {
+		t.Errorf("unexpected clarification: %+v", c)
+	}
+}
+
+func TestParsePlanCallUnknownCommandIsClarifiable(t *testing.T) {
+	_, err := llm.ParsePlanCall(`{"command": "translate", "args": {}}`)
+	if !errors.Is(err, llm.ErrUnknownCommand) {
+		t.Errorf("expected ErrUnknownCommand, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/cli"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/middleware"
+	"article-assistant/internal/processing"
+	"article-assistant/internal/repository"
+)
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+func runCLI(t *testing.T, offline cli.Backend, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := cli.Run(context.Background(), args, &stdout, &stderr, func(context.Context) (cli.Backend, io.Closer, error) {
+		return offline, nopCloser{}, nil
+	})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLIListAndChatAgainstServer(t *testing.T) {
+	var listQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/articles":
+			listQuery = r.URL.RawQuery
+			json.NewEncoder(w).Encode(api.ArticlePage{
+				Articles: []domain.ArticleListItem{{ID: "a1", Title: "Rates rise", URL: "https://example.com/a", Sentiment: "negative", SentimentScore: 0.2}},
+				Total:    1, Limit: 20,
+			})
+		case "/chat":
+			var req domain.ChatRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			json.NewEncoder(w).Encode(domain.ChatResponse{
+				Answer:  "Answer to: " + req.Query,
+				Sources: []domain.Source{{Title: "Rates rise", URL: "https://example.com/a"}},
+			})
+		default:
+			middleware.WriteError(w, r, 404, domain.ErrCodeNotFound, "Not found")
+		}
+	}))
+	defer srv.Close()
+
+	code, out, errOut := runCLI(t, nil, "-server", srv.URL, "list", "-sort", "sentiment_score")
+	if code != 0 {
+		t.Fatalf("list exited %d: %s", code, errOut)
+	}
+	if !strings.Contains(out, "Rates rise") || !strings.Contains(out, "1-1 of 1") {
+		t.Errorf("unexpected list output:\n%s", out)
+	}
+	if !strings.Contains(listQuery, "sort=sentiment_score") {
+		t.Errorf("expected sort to be forwarded, got %s", listQuery)
+	}
+
+	code, out, _ = runCLI(t, nil, "-server", srv.URL, "chat", "what", "changed?")
+	if code != 0 || !strings.Contains(out, "Answer to: what changed?") || !strings.Contains(out, "https://example.com/a") {
+		t.Errorf("unexpected chat output (%d):\n%s", code, out)
+	}
+
+	code, out, _ = runCLI(t, nil, "-server", srv.URL, "-json", "list")
+	var page api.ArticlePage
+	if code != 0 || json.Unmarshal([]byte(out), &page) != nil || page.Total != 1 {
+		t.Errorf("expected JSON page, got %d %q", code, out)
+	}
+}
+
+func TestCLIUsageErrors(t *testing.T) {
+	for _, args := range [][]string{{}, {"frobnicate"}, {"chat"}, {"list", "-limit", "500"}, {"export", "-format", "xml"}} {
+		if code, _, _ := runCLI(t, nil, args...); code != 2 {
+			t.Errorf("%v: expected exit code 2, got %d", args, code)
+		}
+	}
+}
+
+func TestCLIReportsServerErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.WriteErrorDetails(w, r, 500, domain.ErrCodeIngestFailed, "Failed to ingest URL: blocked", map[string]string{"error_category": "blocked"})
+	}))
+	defer srv.Close()
+
+	code, out, errOut := runCLI(t, nil, "-server", srv.URL, "ingest", "https://example.com/a")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(out, "INGEST_FAILED") || !strings.Contains(errOut, "1 of 1 URLs failed") {
+		t.Errorf("unexpected output:\n%s\n%s", out, errOut)
+	}
+}
+
+func TestRemoteIngestPollsUntilComplete(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ingest":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(api.IngestAccepted{Status: processing.StatusProcessing, ID: "job-1"})
+		case "/ingest/status":
+			polls++
+			state := processing.StatusProcessing
+			if polls >= 2 {
+				state = processing.StatusComplete
+			}
+			json.NewEncoder(w).Encode(processing.Status{ID: "job-1", State: state, Attempts: 1})
+		}
+	}))
+	defer srv.Close()
+
+	remote := cli.NewRemote(api.NewClient(srv.URL))
+	remote.PollInterval = time.Millisecond
+	outcome, err := remote.Ingest(context.Background(), "https://example.com/a")
+	if err != nil || !strings.Contains(outcome, "ingested") {
+		t.Fatalf("expected ingested outcome, got %q, %v", outcome, err)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 status polls, got %d", polls)
+	}
+}
+
+func TestCLIOfflineListAndExport(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	for _, a := range []domain.Article{
+		{ID: "a1", URL: "https://example.com/a", Title: "First", Sentiment: "positive", SentimentScore: 0.8},
+		{ID: "a2", URL: "https://example.com/b", Title: "Second", Sentiment: "neutral", SentimentScore: 0.5},
+	} {
+		if err := store.UpsertArticle(ctx, &a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	offline := &cli.Offline{Repo: store, LLM: llm.NewMockClient()}
+
+	code, out, errOut := runCLI(t, offline, "-offline", "list")
+	if code != 0 || !strings.Contains(out, "First") || !strings.Contains(out, "Second") {
+		t.Fatalf("unexpected offline list (%d):\n%s%s", code, out, errOut)
+	}
+
+	code, out, errOut = runCLI(t, offline, "-offline", "export")
+	if code != 0 {
+		t.Fatalf("export exited %d: %s", code, errOut)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 {
+		t.Errorf("expected 2 JSON lines, got %d:\n%s", len(lines), out)
+	}
+}
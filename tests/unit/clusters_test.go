@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/cluster"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestKMeansSeparatesGroups(t *testing.T) {
+	vectors := [][]float32{
+		{1, 0.1, 0}, {0.9, 0, 0.1}, {1, 0, 0},
+		{0, 1, 0.1}, {0.1, 0.9, 0},
+	}
+	assign := cluster.KMeans(vectors, 2, 20, 1)
+
+	if assign[0] != assign[1] || assign[1] != assign[2] {
+		t.Errorf("expected first three vectors together, got %v", assign)
+	}
+	if assign[3] != assign[4] || assign[3] == assign[0] {
+		t.Errorf("expected last two vectors in their own cluster, got %v", assign)
+	}
+}
+
+func TestDefaultK(t *testing.T) {
+	tests := map[int]int{2: 2, 8: 2, 50: 5, 1000: 10}
+	for n, want := range tests {
+		if got := cluster.DefaultK(n); got != want {
+			t.Errorf("DefaultK(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestGroupArticlesLargestFirst(t *testing.T) {
+	articles := []domain.Article{
+		{ID: "a", Title: "Rates", Embedding: []float32{0, 1}},
+		{ID: "b", Title: "Chips", Embedding: []float32{1, 0}},
+		{ID: "c", Title: "GPUs", Embedding: []float32{0.95, 0.05}},
+		{ID: "d", Title: "Foundries", Embedding: []float32{0.9, 0.1}},
+	}
+	clusters := executor.GroupArticles(articles, 2)
+
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+	if clusters[0].Size != 3 || clusters[1].Size != 1 || clusters[1].Articles[0].ID != "a" {
+		t.Errorf("unexpected clusters: %+v", clusters)
+	}
+}
+
+func TestParseClusterLabels(t *testing.T) {
+	labels, err := executor.ParseClusterLabels("```json\n[\" Semiconductors \", \"Interest rates\"]\n```", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if labels[0] != "Semiconductors" || labels[1] != "Interest rates" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+
+	if _, err := executor.ParseClusterLabels(`["only one"]`, 2); err == nil {
+		t.Error("expected error for a label count mismatch")
+	}
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+func TestCollectionsScopeArticles(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{ID: "a", URL: "https://example.com/a", Title: "A"},
+		{ID: "b", URL: "https://example.com/b", Title: "B"},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	policy := &domain.Collection{Name: "AI policy"}
+	if err := store.CreateCollection(ctx, policy); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := store.CreateCollection(ctx, &domain.Collection{Name: "ai POLICY"}); !errors.Is(err, repository.ErrCollectionExists) {
+		t.Errorf("expected names to be unique in any case, got %v", err)
+	}
+	store.AddCollectionArticles(ctx, policy.ID, []string{"a", "a"})
+
+	for _, key := range []string{policy.ID, "ai policy"} {
+		urls, _ := store.GetArticleURLs(ctx, nil, domain.ArticleFilter{Collection: key})
+		if len(urls) != 1 || urls[0] != "https://example.com/a" {
+			t.Errorf("collection %q: expected only article a, got %v", key, urls)
+		}
+	}
+	if n, _ := store.CountArticles(ctx, domain.ArticleFilter{Collection: "unknown"}); n != 0 {
+		t.Errorf("expected an unknown collection to match nothing, got %d", n)
+	}
+	if got, _ := store.GetCollection(ctx, policy.ID); got == nil || got.Articles != 1 {
+		t.Errorf("expected 1 article in the collection, got %+v", got)
+	}
+
+	store.DeleteArticleByURL(ctx, "https://example.com/a")
+	if got, _ := store.GetCollection(ctx, policy.ID); got.Articles != 0 {
+		t.Errorf("expected the deleted article to leave the collection, got %d", got.Articles)
+	}
+	if deleted, _ := store.DeleteCollection(ctx, policy.ID); !deleted {
+		t.Error("expected the collection to be deleted")
+	}
+	if n, _ := store.CountArticles(ctx, domain.ArticleFilter{}); n != 1 {
+		t.Errorf("expected deleting a collection to keep its articles, got %d", n)
+	}
+}
+
+func TestUpdateCollectionRejectsTakenName(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	first, second := &domain.Collection{Name: "Climate"}, &domain.Collection{Name: "Energy"}
+	store.CreateCollection(ctx, first)
+	store.CreateCollection(ctx, second)
+
+	second.Name = "climate"
+	if _, err := store.UpdateCollection(ctx, second); !errors.Is(err, repository.ErrCollectionExists) {
+		t.Errorf("expected a name conflict, got %v", err)
+	}
+	first.Name = "CLIMATE"
+	if updated, err := store.UpdateCollection(ctx, first); !updated || err != nil {
+		t.Errorf("expected a collection to change the case of its own name, got %v, %v", updated, err)
+	}
+	if list, _ := store.ListCollections(ctx); len(list) != 2 || list[0].Name != "CLIMATE" {
+		t.Errorf("expected collections by name, got %+v", list)
+	}
+}
+
+func TestSQLiteStorePersistsCollections(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	db, _ := sql.Open("sqlite", path)
+	store, err := repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	article := &domain.Article{URL: "https://example.com/a", Title: "Collected"}
+	store.UpsertArticle(ctx, article)
+	collection := &domain.Collection{Name: "Reading list"}
+	store.CreateCollection(ctx, collection)
+	store.AddCollectionArticles(ctx, collection.ID, []string{article.ID})
+	db.Close()
+
+	db, _ = sql.Open("sqlite", path)
+	defer db.Close()
+	if store, err = repository.NewSQLiteStore(ctx, db); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, _ := store.GetCollection(ctx, collection.ID)
+	if got == nil || got.Name != "Reading list" || got.Articles != 1 {
+		t.Errorf("expected the collection and its article to survive reopen, got %+v", got)
+	}
+}
+
+func TestCollectionPlanArgScopesCommands(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	store.UpsertArticle(ctx, &domain.Article{ID: "a", URL: "https://example.com/a", Title: "A", Tone: "critical"})
+	store.UpsertArticle(ctx, &domain.Article{ID: "b", URL: "https://example.com/b", Title: "B", Tone: "critical"})
+	collection := &domain.Collection{Name: "AI policy"}
+	store.CreateCollection(ctx, collection)
+	store.AddCollectionArticles(ctx, collection.ID, []string{"b"})
+
+	ex := executor.NewExecutorWithCommands(store, llm.NewMockClient())
+	resp, err := ex.Execute(ctx, &domain.Plan{Command: "filter_by_tone", Args: map[string]interface{}{"tone": "critical", "collection": "AI Policy"}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(resp.Sources) != 1 || resp.Sources[0].URL != "https://example.com/b" {
+		t.Errorf("expected only the collection's article, got %+v", resp.Sources)
+	}
+}
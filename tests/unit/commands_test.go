@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"article-assistant/internal/commands"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/prompts"
+)
+
+func TestExecutorImplementsEveryRegisteredCommand(t *testing.T) {
+	registered := executor.NewExecutorWithCommands(nil, llm.NewMockClient()).Names()
+	want := commands.Names()
+	if len(registered) != len(want) {
+		t.Fatalf("executor has %v, registry has %v", registered, want)
+	}
+	for _, name := range want {
+		found := false
+		for _, r := range registered {
+			found = found || r == name
+		}
+		if !found {
+			t.Errorf("command %s is registered but not implemented", name)
+		}
+	}
+}
+
+func TestRegistryArgs(t *testing.T) {
+	seen := map[string]bool{}
+	for _, c := range commands.Registry {
+		if seen[c.Name] {
+			t.Errorf("command %s is registered twice", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Description == "" {
+			t.Errorf("command %s has no description", c.Name)
+		}
+		args := map[string]bool{}
+		for _, a := range c.Args {
+			if args[a.Name] {
+				t.Errorf("command %s lists arg %s twice", c.Name, a.Name)
+			}
+			args[a.Name] = true
+			switch a.Type {
+			case "string", "number", "integer", "array":
+			default:
+				t.Errorf("command %s arg %s has type %q", c.Name, a.Name, a.Type)
+			}
+		}
+	}
+	if c, ok := commands.Lookup("summary"); !ok || len(c.Args) == 0 || c.Args[0].Name != "urls" || !c.Args[0].Required {
+		t.Errorf("expected summary to require urls, got %+v", c)
+	}
+}
+
+func TestPlannerPromptListsRegisteredCommands(t *testing.T) {
+	fsys := fstest.MapFS{
+		"planner/v1.yaml": {Data: []byte(`text: '{{range .Commands}}|{{.Name}}: {{.Description}} ({{len .Examples}}){{end}}'
+commands:
+  - name: ask
+    description: Answer anything
+    examples:
+      - query: "Why?"
+        plan: '{"command": "ask", "args": {}}'
+`)},
+	}
+	f, err := prompts.NewFactory(prompts.NewLoader(fsys), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt, err := f.Planner(context.Background(), "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := strings.Split(strings.TrimPrefix(prompt, "|"), "|")
+	if len(entries) != len(commands.Registry) {
+		t.Fatalf("expected an entry per registered command, got %q", prompt)
+	}
+	summary, _ := commands.Lookup("summary")
+	if entries[0] != "summary: "+summary.Description+" (0)" {
+		t.Errorf("expected the registry description for summary, got %q", entries[0])
+	}
+	if !strings.Contains(prompt, "|ask: Answer anything (1)") {
+		t.Errorf("expected the version's description and example for ask, got %q", prompt)
+	}
+}
+
+func TestPlannerPromptRejectsUnknownCommands(t *testing.T) {
+	fsys := fstest.MapFS{
+		"planner/v1.yaml": {Data: []byte("text: '{{.Query}}'\ncommands:\n  - name: summarise\n")},
+	}
+	if _, err := prompts.NewLoader(fsys).Load(prompts.Planner, "v1"); err == nil || !strings.Contains(err.Error(), "summarise") {
+		t.Errorf("expected an unknown command error, got %v", err)
+	}
+}
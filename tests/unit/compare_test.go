@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+func TestComparePromptNumbersEveryArticle(t *testing.T) {
+	prompt := llm.ComparePrompt([]string{"first", "second", "third"})
+	for _, want := range []string{"[1] first", "[2] second", "[3] third", "common_themes", "disagreements"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestParseComparison(t *testing.T) {
+	articles := []domain.Article{
+		{Title: "A", URL: "https://a.com/1"},
+		{Title: "B", URL: "https://b.com/2"},
+		{Title: "C", URL: "https://c.com/3"},
+	}
+	raw := "```json\n" + `{
+  "summary": "All three cover the summit.",
+  "common_themes": ["trade"],
+  "unique_points": [{"citation": 3, "points": ["tariff figures"]}, {"citation": 9, "points": ["bogus"]}],
+  "disagreements": [{"issue": "outcome", "positions": [{"citation": 1, "position": "success"}, {"citation": 2, "position": "failure"}, {"citation": 0, "position": "bogus"}]}]
+}` + "\n```"
+
+	c, err := executor.ParseComparison(raw, articles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.UniquePoints) != 1 || c.UniquePoints[0].URL != "https://c.com/3" {
+		t.Errorf("unique points = %+v; want only article 3 with its URL", c.UniquePoints)
+	}
+	if len(c.Disagreements) != 1 || len(c.Disagreements[0].Positions) != 2 {
+		t.Errorf("disagreements = %+v; want one issue with 2 valid positions", c.Disagreements)
+	}
+
+	text := executor.FormatComparison(c)
+	for _, want := range []string{"All three cover the summit.", "Common themes:\n- trade", "[3] C\n  - tariff figures", "- outcome\n  [1] success\n  [2] failure"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatComparison() missing %q in:\n%s", want, text)
+		}
+	}
+
+	if _, err := executor.ParseComparison("Both articles cover the same event.", articles); err == nil {
+		t.Error("expected error for free-form reply")
+	}
+}
+
+// partialStore holds two of the four URLs partialPlan names
+func partialStore() (*repository.MemoryStore, *domain.Plan) {
+	store := repository.NewMemoryStore()
+	for i, u := range []string{"https://example.com/a", "https://example.com/b"} {
+		store.UpsertArticle(context.Background(), &domain.Article{URL: u, Title: u, Summary: "summary", SentimentScore: 0.2 + 0.6*float64(i)})
+	}
+	plan := &domain.Plan{Args: map[string]interface{}{"urls": []interface{}{
+		"https://example.com/a", "https://example.com/b", "https://example.com/missing", "https://example.com/broken",
+	}}}
+	return store, plan
+}
+
+func TestCompareAnswersWithTheArticlesFound(t *testing.T) {
+	store, plan := partialStore()
+	plan.Command = "compare_articles"
+	cmd := &executor.CompareCommand{Repo: store, LLM: llm.NewMockClient(), ResponseGenerator: executor.NewResponseGenerator(store)}
+
+	ctx := executor.WithFetchErrors(context.Background(), map[string]string{"https://example.com/broken": "status 404"})
+	resp, err := cmd.Execute(ctx, plan, "compare")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if resp.Error != nil || len(resp.Sources) != 2 {
+		t.Fatalf("expected a comparison of the 2 stored articles, got %+v", resp)
+	}
+	data, ok := resp.Data.(executor.URLResults)
+	if !ok {
+		t.Fatalf("expected per-URL results, got %T", resp.Data)
+	}
+	want := map[string]domain.URLStatus{
+		"https://example.com/a":       {Status: domain.URLFound},
+		"https://example.com/b":       {Status: domain.URLFound},
+		"https://example.com/missing": {Status: domain.URLMissing},
+		"https://example.com/broken":  {Status: domain.URLFetchError, Error: "status 404"},
+	}
+	for u, st := range want {
+		if data.URLs[u] != st {
+			t.Errorf("%s: expected %+v, got %+v", u, st, data.URLs[u])
+		}
+	}
+	if !strings.Contains(resp.Answer, "https://example.com/missing: not stored") || !strings.Contains(resp.Answer, "status 404") {
+		t.Errorf("expected the answer to list the URLs left out, got %q", resp.Answer)
+	}
+}
+
+func TestCompareWithOneArticleFoundReportsEachURL(t *testing.T) {
+	store, _ := partialStore()
+	plan := &domain.Plan{Command: "compare_articles", Args: map[string]interface{}{"urls": []interface{}{
+		"https://example.com/a", "https://example.com/missing",
+	}}}
+	cmd := &executor.CompareCommand{Repo: store, LLM: llm.NewMockClient(), ResponseGenerator: executor.NewResponseGenerator(store)}
+
+	resp, err := cmd.Execute(context.Background(), plan, "compare")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	data, _ := resp.Data.(executor.URLResults)
+	if resp.Error == nil || data.URLs["https://example.com/missing"].Status != domain.URLMissing {
+		t.Errorf("expected an error answer with per-URL statuses, got %+v", resp)
+	}
+}
+
+func TestSentimentAnswersWithTheArticlesFound(t *testing.T) {
+	store, plan := partialStore()
+	plan.Command = "get_sentiment"
+	cmd := &executor.FetchSentimentCommand{Repo: store, ResponseGenerator: executor.NewResponseGenerator(store)}
+
+	resp, err := cmd.Execute(context.Background(), plan, "sentiment")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	data, ok := resp.Data.(executor.SentimentResult)
+	if !ok {
+		t.Fatalf("expected sentiment data, got %T", resp.Data)
+	}
+	if len(data.Articles) != 2 || data.Overall != "neutral" {
+		t.Errorf("expected a neutral average over 2 articles, got %+v", data)
+	}
+	if data.URLs["https://example.com/broken"].Status != domain.URLMissing || data.URLs["https://example.com/a"].Status != domain.URLFound {
+		t.Errorf("unexpected per-URL statuses %+v", data.URLs)
+	}
+}
@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFileWithEnvOverride(t *testing.T) {
+	path := writeConfig(t, "openai_model: gpt-4\nHYBRID_VECTOR_WEIGHT: 0.5\nAUTO_INGEST: true\n")
+	t.Setenv("OPENAI_MODEL", "gpt-3.5-turbo")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Get("OPENAI_MODEL"); got != "gpt-3.5-turbo" {
+		t.Errorf("OPENAI_MODEL = %q, want the environment value", got)
+	}
+	if got := cfg.Get("HYBRID_VECTOR_WEIGHT"); got != "0.5" {
+		t.Errorf("HYBRID_VECTOR_WEIGHT = %q, want 0.5", got)
+	}
+	if got := cfg.Get("AUTO_INGEST"); got != "true" {
+		t.Errorf("AUTO_INGEST = %q, want true", got)
+	}
+}
+
+func TestConfigRejectsNestedValues(t *testing.T) {
+	if _, err := config.Load(writeConfig(t, "search:\n  weight: 0.5\n")); err == nil {
+		t.Error("expected error for a nested setting")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	cfg, err := config.Load(writeConfig(t, "HYBRID_VECTOR_WEIGHT: 1.5\nCHAT_CACHE_TTL: soon\nLLM_RETRY_MAX_ATTEMPTS: 0\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"HYBRID_VECTOR_WEIGHT", "CHAT_CACHE_TTL", "LLM_RETRY_MAX_ATTEMPTS", "OPENAI_API_KEY is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("validation error missing %q: %v", want, err)
+		}
+	}
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	cfg, _ = config.Load(writeConfig(t, "HYBRID_VECTOR_WEIGHT: 0.3\nLOG_LEVEL: debug\n"))
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func TestConfigChangedKeys(t *testing.T) {
+	before, _ := config.Load(writeConfig(t, "LOG_LEVEL: info\nOPENAI_MODEL: gpt-4\nCACHE_BACKEND: redis\n"))
+	after, _ := config.Load(writeConfig(t, "LOG_LEVEL: debug\nOPENAI_MODEL: gpt-4\nHYBRID_VECTOR_WEIGHT: 0.4\n"))
+
+	want := []string{"CACHE_BACKEND", "HYBRID_VECTOR_WEIGHT", "LOG_LEVEL"}
+	if got := before.ChangedKeys(after); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedKeys() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,308 @@
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/consumer"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/processing"
+)
+
+type recordingQueue struct {
+	mu   sync.Mutex
+	urls []string
+	err  error
+}
+
+func (q *recordingQueue) Enqueue(ctx context.Context, url string) (processing.Status, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return processing.Status{}, q.err
+	}
+	q.urls = append(q.urls, url)
+	return processing.Status{URL: url}, nil
+}
+
+func (q *recordingQueue) queued() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]string(nil), q.urls...)
+}
+
+type recordingImporter struct {
+	mu       sync.Mutex
+	articles []ingest.ImportedArticle
+	err      error
+}
+
+func (i *recordingImporter) Import(ctx context.Context, in ingest.ImportedArticle) (*domain.Article, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.err != nil {
+		return nil, i.err
+	}
+	i.articles = append(i.articles, in)
+	return &domain.Article{URL: in.URL, Title: in.Title}, nil
+}
+
+// staticSource names a source for tests that call Handle directly
+type staticSource string
+
+func (s staticSource) Name() string { return string(s) }
+func (s staticSource) Run(ctx context.Context, handle func(ctx context.Context, data []byte) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestConsumerHandlesURLsAndArticlePayloads(t *testing.T) {
+	ctx := context.Background()
+	queue, importer := &recordingQueue{}, &recordingImporter{}
+	c := consumer.New(staticSource("nats:crawler.urls"), queue, importer)
+	imports := 0
+	c.OnImport = func(context.Context) { imports++ }
+
+	messages := []string{
+		" https://example.com/a\n",
+		`{"url": "https://example.com/b"}`,
+		`{"url": "https://example.com/c", "title": "C", "text": "Body of c.", "summary": "C in short."}`,
+		"not a url",
+		`{"url": "ftp://example.com/d"}`,
+		`{"url": "https://example.com/e", "text": "No title."}`,
+		`{"url": `,
+	}
+	for _, m := range messages {
+		if err := c.Handle(ctx, []byte(m)); err != nil {
+			t.Errorf("Handle(%q): %v", m, err)
+		}
+	}
+
+	if got := queue.queued(); len(got) != 2 || got[0] != "https://example.com/a" || got[1] != "https://example.com/b" {
+		t.Errorf("queued = %v, want the two URL messages", got)
+	}
+	if len(importer.articles) != 1 || importer.articles[0].Title != "C" || importer.articles[0].Summary != "C in short." {
+		t.Errorf("imported = %+v, want the article payload", importer.articles)
+	}
+	if imports != 1 {
+		t.Errorf("OnImport ran %d times, want 1", imports)
+	}
+
+	importer.err = errors.New("store unavailable")
+	if err := c.Handle(ctx, []byte(`{"url": "https://example.com/f", "title": "F", "text": "Body."}`)); err != nil {
+		t.Errorf("expected a failed import to be dropped, got %v", err)
+	}
+	queue.err = errors.New("shutting down")
+	if err := c.Handle(ctx, []byte("https://example.com/g")); err == nil {
+		t.Error("expected a URL the queue refused to be an error, so it is not acknowledged")
+	}
+
+	var buf bytes.Buffer
+	if err := consumer.WritePrometheus(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`ingest_consumer_messages_total{source="nats:crawler.urls",result="queued"} 2`,
+		`ingest_consumer_messages_total{source="nats:crawler.urls",result="imported"} 1`,
+		`ingest_consumer_messages_total{source="nats:crawler.urls",result="invalid"} 4`,
+		`ingest_consumer_messages_total{source="nats:crawler.urls",result="failed"} 1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+// fakeNATS accepts one client, checks its CONNECT and SUB, then publishes messages
+func fakeNATS(t *testing.T, messages []string) (url string, subscribed <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	subs := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				var opts map[string]interface{}
+				if json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &opts) != nil || opts["auth_token"] != "s3cret" {
+					fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+					return
+				}
+			case line == "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			case strings.HasPrefix(line, "SUB "):
+				subs <- line
+				fmt.Fprint(conn, "PING\r\n")
+				for _, m := range messages {
+					fmt.Fprintf(conn, "MSG crawler.urls 1 %d\r\n%s\r\n", len(m), m)
+				}
+			case line == "PONG":
+			}
+		}
+	}()
+	return "nats://s3cret@" + ln.Addr().String(), subs
+}
+
+func TestNATSSourceDeliversMessages(t *testing.T) {
+	url, subscribed := fakeNATS(t, []string{"https://example.com/a", `{"url": "https://example.com/b"}`})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []string
+	src := &consumer.NATSSource{URL: url, Subject: "crawler.urls", Queue: "article-assistant"}
+	err := src.Run(ctx, func(ctx context.Context, data []byte) error {
+		if got = append(got, string(data)); len(got) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+	if sub := <-subscribed; sub != "SUB crawler.urls article-assistant 1" {
+		t.Errorf("subscription = %q", sub)
+	}
+	if len(got) != 2 || got[0] != "https://example.com/a" || got[1] != `{"url": "https://example.com/b"}` {
+		t.Errorf("messages = %q", got)
+	}
+}
+
+func TestNATSSourceReportsRefusedConnection(t *testing.T) {
+	url, _ := fakeNATS(t, nil)
+	src := &consumer.NATSSource{URL: strings.Replace(url, "s3cret", "wrong", 1), Subject: "crawler.urls"}
+	err := src.Run(context.Background(), func(context.Context, []byte) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("Run() = %v, want the server's authorization error", err)
+	}
+}
+
+// fakeKafkaREST serves one batch of records, records the consumer's calls and closes
+// committed when offsets are committed
+func fakeKafkaREST(t *testing.T, values []string) (srv *httptest.Server, calls *[]string, committed <-chan struct{}) {
+	t.Helper()
+	var mu sync.Mutex
+	calls = new([]string)
+	commits := make(chan struct{})
+	served := false
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		*calls = append(*calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/consumers/crawlers":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["format"] != "binary" || body["auto.commit.enable"] != "false" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{
+				"instance_id": body["name"],
+				"base_uri":    srv.URL + "/consumers/crawlers/instances/" + body["name"],
+			})
+		case strings.HasSuffix(r.URL.Path, "/subscription"):
+			var body struct{ Topics []string }
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Topics) != 1 || body.Topics[0] != "crawler-urls" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/records"):
+			var records []map[string]interface{}
+			if !served {
+				for i, v := range values {
+					records = append(records, map[string]interface{}{
+						"topic": "crawler-urls", "partition": 0, "offset": i,
+						"value": base64.StdEncoding.EncodeToString([]byte(v)),
+					})
+				}
+				served = true
+			}
+			json.NewEncoder(w).Encode(records)
+		case strings.HasSuffix(r.URL.Path, "/offsets"):
+			close(commits)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, calls, commits
+}
+
+func TestKafkaSourceCommitsAfterBatch(t *testing.T) {
+	srv, calls, committed := fakeKafkaREST(t, []string{"https://example.com/a", "https://example.com/b"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		<-committed
+		cancel()
+	}()
+
+	var got []string
+	src := &consumer.KafkaSource{URL: srv.URL, Topic: "crawler-urls", Group: "crawlers", PollTimeout: 10 * time.Millisecond}
+	err := src.Run(ctx, func(ctx context.Context, data []byte) error {
+		got = append(got, string(data))
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want it to stop with the context", err)
+	}
+	if len(got) != 2 || got[0] != "https://example.com/a" || got[1] != "https://example.com/b" {
+		t.Errorf("records = %q", got)
+	}
+
+	seq := strings.Join(*calls, "\n")
+	commit := strings.Index(seq, "/offsets")
+	if commit < 0 || !strings.Contains(seq[:commit], "/records") {
+		t.Errorf("expected offsets to be committed after the batch, calls:\n%s", seq)
+	}
+	if !strings.HasPrefix((*calls)[len(*calls)-1], "DELETE /consumers/crawlers/instances/") {
+		t.Errorf("expected the consumer instance to be deleted on return, calls:\n%s", seq)
+	}
+}
+
+func TestKafkaSourceDoesNotCommitFailedBatch(t *testing.T) {
+	srv, calls, _ := fakeKafkaREST(t, []string{"https://example.com/a"})
+	src := &consumer.KafkaSource{URL: srv.URL, Topic: "crawler-urls", Group: "crawlers", PollTimeout: 10 * time.Millisecond}
+	refused := errors.New("queue closed")
+	err := src.Run(context.Background(), func(context.Context, []byte) error { return refused })
+	if !errors.Is(err, refused) {
+		t.Errorf("Run() = %v, want the handler error", err)
+	}
+	if seq := strings.Join(*calls, "\n"); strings.Contains(seq, "/offsets") {
+		t.Errorf("expected no commit for an unhandled batch, calls:\n%s", seq)
+	}
+}
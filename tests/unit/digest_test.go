@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+// digestLLM answers every prompt with a fixed digest and records the prompt
+type digestLLM struct {
+	*llm.MockClient
+	prompt string
+}
+
+func (d *digestLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	d.prompt = prompt
+	return `{"intro": "Chips dominated the week.", "sections": [{"heading": "Supply", "highlights": [{"text": "Fabs expand", "citations": [1, 7]}]}], "articles": [{"citation": 1, "one_liner": "New fab announced"}]}`, nil
+}
+
+func TestParseDigest(t *testing.T) {
+	articles := []domain.Article{{URL: "https://a.com/1", Title: "One"}, {URL: "https://b.com/2", Title: "Two"}}
+	raw := "```json\n{\"intro\": \"Overview\", \"sections\": [{\"heading\": \"Markets\", \"highlights\": [{\"text\": \"Stocks rose\", \"citations\": [2, 5]}, {\"text\": \" \"}]}], \"articles\": [{\"citation\": 2, \"one_liner\": \"Rally\"}]}\n```"
+
+	digest, err := executor.ParseDigest(raw, articles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(digest.Sections) != 1 || len(digest.Sections[0].Highlights) != 1 {
+		t.Fatalf("expected one section with one highlight, got %+v", digest.Sections)
+	}
+	if c := digest.Sections[0].Highlights[0].Citations; len(c) != 1 || c[0] != 2 {
+		t.Errorf("expected out-of-range citations to be dropped, got %v", c)
+	}
+	if len(digest.Articles) != 2 || digest.Articles[0].OneLiner != "" || digest.Articles[1].OneLiner != "Rally" || digest.Articles[1].URL != "https://b.com/2" {
+		t.Errorf("expected an entry per article, got %+v", digest.Articles)
+	}
+
+	text := executor.FormatDigest(digest)
+	for _, want := range []string{"Overview", "- Stocks rose [2]", "[2] Two — Rally", "https://b.com/2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	if _, err := executor.ParseDigest("not json", articles); err == nil {
+		t.Error("expected error for non-JSON reply")
+	}
+}
+
+func TestDigestCommandByDateRange(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	recent := time.Now().Add(-24 * time.Hour)
+	old := time.Now().AddDate(0, -2, 0)
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://chips.com/fab", Title: "Fab announced", Summary: "A new fab", PublishedAt: &recent})
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://chips.com/old", Title: "Old news", PublishedAt: &old})
+
+	client := &digestLLM{MockClient: llm.NewMockClient()}
+	cmd := &executor.DigestCommand{Repo: store, LLM: client, ResponseGenerator: executor.NewResponseGenerator(store)}
+
+	resp, err := cmd.Execute(ctx, &domain.Plan{Command: "digest", Args: map[string]interface{}{"published_after": "last_week"}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(resp.Sources) != 1 || resp.Sources[0].URL != "https://chips.com/fab" {
+		t.Fatalf("expected only the recent article, got %+v", resp.Sources)
+	}
+	if !strings.Contains(client.prompt, "Fab announced") || strings.Contains(client.prompt, "Old news") || !strings.Contains(client.prompt, "recent news published since") {
+		t.Errorf("unexpected prompt:\n%s", client.prompt)
+	}
+	if !strings.Contains(resp.Answer, "[1] Fab announced — New fab announced") {
+		t.Errorf("expected a formatted digest, got:\n%s", resp.Answer)
+	}
+
+	resp, _ = cmd.Execute(ctx, &domain.Plan{Command: "digest", Args: map[string]interface{}{}}, "")
+	if !strings.Contains(resp.Answer, "topic or date range is required") {
+		t.Errorf("expected a missing-scope error, got %q", resp.Answer)
+	}
+}
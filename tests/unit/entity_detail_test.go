@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/entity"
+	"article-assistant/internal/repository"
+)
+
+func TestEntityDetail(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	day := func(d int) *time.Time {
+		at := time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC)
+		return &at
+	}
+	articles := []struct {
+		url       string
+		sentiment string
+		score     float64
+		published *time.Time
+		entities  []string
+	}{
+		{"https://example.com/a", "positive", 0.8, day(3), []string{"OpenAI", "Microsoft"}},
+		{"https://example.com/b", "negative", 0.2, day(4), []string{"Open AI", "Microsoft", "Google"}},
+		{"https://example.com/c", "positive", 0.9, day(12), []string{"openai", "Google", "Microsoft"}},
+		{"https://example.com/d", "neutral", 0.5, day(13), []string{"Google"}},
+	}
+	for _, a := range articles {
+		var entities []domain.SemanticEntity
+		for _, name := range a.entities {
+			entities = append(entities, domain.SemanticEntity{Name: name, Category: "organization"})
+		}
+		store.UpsertArticle(ctx, &domain.Article{URL: a.url, Title: a.url, Sentiment: a.sentiment, SentimentScore: a.score, PublishedAt: a.published, Entities: entities})
+	}
+
+	detail, err := store.GetEntityDetail(ctx, entity.Key("OPEN AI"), "week", 1)
+	if err != nil {
+		t.Fatalf("entity detail: %v", err)
+	}
+	if detail == nil || detail.Articles != 3 || detail.Positive != 2 || detail.Negative != 1 || detail.Category != "organization" {
+		t.Fatalf("unexpected detail %+v", detail)
+	}
+	if got := detail.AverageScore; got < 0.633 || got > 0.634 {
+		t.Errorf("expected average score 0.633, got %v", got)
+	}
+	if len(detail.CoOccurring) != 1 || detail.CoOccurring[0].Name != "Microsoft" || detail.CoOccurring[0].Articles != 3 {
+		t.Errorf("expected Microsoft as the top co-occurring entity, got %+v", detail.CoOccurring)
+	}
+	if len(detail.Timeline) != 2 || detail.Timeline[0].Articles != 2 || detail.Timeline[1].Articles != 1 {
+		t.Errorf("expected two weekly buckets, got %+v", detail.Timeline)
+	}
+
+	items, total, err := store.ListEntityArticles(ctx, entity.Key("openai"), 2, 0)
+	if err != nil {
+		t.Fatalf("entity articles: %v", err)
+	}
+	if total != 3 || len(items) != 2 || items[0].URL != "https://example.com/c" || items[1].URL != "https://example.com/b" {
+		t.Errorf("expected newest mentioning articles first, got %d %+v", total, items)
+	}
+
+	if missing, _ := store.GetEntityDetail(ctx, "nvidia", "week", 10); missing != nil {
+		t.Errorf("expected no detail for an unmentioned entity, got %+v", missing)
+	}
+}
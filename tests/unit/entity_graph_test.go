@@ -0,0 +1,35 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestFormatEntityGraph(t *testing.T) {
+	graph := &domain.EntityGraph{
+		Nodes: []domain.EntityNode{
+			{ID: "openai", Name: "OpenAI", Category: "organization", Articles: 4},
+			{ID: "sam altman", Name: "Sam Altman", Category: "person", Articles: 3},
+			{ID: "microsoft", Name: "Microsoft", Category: "organization", Articles: 2},
+		},
+		Edges: []domain.EntityEdge{
+			{Source: "openai", Target: "sam altman", Articles: 3},
+			{Source: "microsoft", Target: "openai", Articles: 2},
+		},
+	}
+
+	answer := executor.FormatEntityGraph(graph)
+	for _, want := range []string{"3 entities, 2 connections", "1. OpenAI — Sam Altman (3 shared articles)", "2. Microsoft — OpenAI"} {
+		if !strings.Contains(answer, want) {
+			t.Errorf("expected %q in answer:\n%s", want, answer)
+		}
+	}
+
+	graph.Edges = nil
+	if answer := executor.FormatEntityGraph(graph); strings.Contains(answer, "Strongest") {
+		t.Errorf("expected no connection list without edges, got:\n%s", answer)
+	}
+}
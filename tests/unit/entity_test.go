@@ -0,0 +1,146 @@
+package unit
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/entity"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/repository"
+)
+
+// fixedResolver canonicalizes from a map and counts calls
+type fixedResolver struct {
+	names map[string]string
+	calls int
+}
+
+func (f *fixedResolver) Resolve(ctx context.Context, names []string) (map[string]string, error) {
+	f.calls++
+	return f.names, nil
+}
+
+func TestEntityNormalizer(t *testing.T) {
+	if entity.Key("Open AI") != entity.Key("openai") || entity.Key("U.S.") != "us" {
+		t.Fatalf("expected spacing, case and punctuation to be folded")
+	}
+
+	resolver := &fixedResolver{names: map[string]string{"Alphabet's Google": "Google"}}
+	n := entity.NewNormalizer(map[string][]string{"United States": {"US", "USA"}})
+	n.Resolver = resolver
+
+	got := n.Normalize(context.Background(), []domain.SemanticEntity{
+		{Name: " U.S. ", Confidence: 0.6},
+		{Name: "United States", Confidence: 0.9},
+		{Name: "Alphabet's Google", Confidence: 0.8},
+		{Name: "Open AI", Confidence: 0.7},
+	})
+	if len(got) != 3 {
+		t.Fatalf("expected aliases within an article to be merged, got %+v", got)
+	}
+	if got[0].Name != "U.S." || got[0].CanonicalName != "United States" || got[0].Confidence != 0.9 {
+		t.Errorf("unexpected alias resolution: %+v", got[0])
+	}
+	if got[1].CanonicalName != "Google" || got[2].CanonicalName != "Open AI" {
+		t.Errorf("unexpected resolver results: %+v", got[1:])
+	}
+
+	// Resolved names are remembered
+	n.Normalize(context.Background(), []domain.SemanticEntity{{Name: "Alphabet's Google"}, {Name: "Google"}})
+	if resolver.calls != 1 {
+		t.Errorf("expected known names not to be resolved again, got %d calls", resolver.calls)
+	}
+}
+
+func TestTopEntitiesGroupsSpellingVariants(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for i, names := range [][]string{{"OpenAI", "Microsoft"}, {"Open AI"}, {"openai", "Microsoft"}, {"OpenAI"}} {
+		var entities []domain.SemanticEntity
+		for _, name := range names {
+			entities = append(entities, domain.SemanticEntity{Name: name, CanonicalName: name, Confidence: 0.8})
+		}
+		store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/" + string(rune('a'+i)), Entities: entities})
+	}
+	// Articles stored before normalization only have raw names
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/old", Entities: []domain.SemanticEntity{{Name: "OPENAI"}}})
+
+	top, err := store.GetTopEntities(ctx, 10, nil, "")
+	if err != nil {
+		t.Fatalf("top entities: %v", err)
+	}
+	if len(top) != 2 || top[0].Name != "OpenAI" || top[1].Name != "Microsoft" {
+		t.Fatalf("expected OpenAI variants counted together, got %+v", top)
+	}
+
+	graph, _ := store.GetEntityGraph(ctx, 10, 1, nil)
+	if len(graph.Nodes) != 2 || graph.Nodes[0].Articles != 5 {
+		t.Errorf("expected the graph to group variants too, got %+v", graph.Nodes)
+	}
+}
+
+func TestTopEntitiesCountAnArticleOncePerEntity(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/a", Entities: []domain.SemanticEntity{
+		{Name: "OpenAI", Category: "organization", Confidence: 0.9},
+		{Name: "Open AI", Category: "company", Confidence: 0.7},
+	}})
+
+	for _, urls := range [][]string{nil, {"https://example.com/a"}} {
+		top, err := store.GetTopEntities(ctx, 10, urls, "")
+		if err != nil {
+			t.Fatalf("top entities: %v", err)
+		}
+		if len(top) != 1 || top[0].Count != 1 {
+			t.Errorf("expected one article for both spellings with urls %v, got %+v", urls, top)
+		}
+	}
+}
+
+func TestTopEntitiesWeighByConfidence(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for i, entities := range [][]domain.SemanticEntity{
+		{{Name: "Vague Corp", Category: "organization", Confidence: 0.3}, {Name: "Ada Lovelace", Category: "person", Confidence: 0.9}},
+		{{Name: "Vague Corp", Category: "organization", Confidence: 0.3}, {Name: "Ada Lovelace", Category: "Person", Confidence: 0.9}},
+		{{Name: "Vague Corp", Category: "organization", Confidence: 0.3}, {Name: "London", Category: "location", Confidence: 0.8}},
+	} {
+		store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/" + string(rune('a'+i)), Entities: entities})
+	}
+
+	top, err := store.GetTopEntities(ctx, 10, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ada Lovelace: 2 × 0.9 outranks Vague Corp: 3 × 0.3
+	if len(top) != 3 || top[0].Name != "Ada Lovelace" || top[1].Name != "Vague Corp" || top[2].Name != "London" {
+		t.Fatalf("expected confidence-weighted ranking, got %+v", top)
+	}
+	if ada := top[0]; ada.Category != "person" || ada.Count != 2 || math.Abs(ada.AvgConfidence-0.9) > 1e-9 || math.Abs(ada.Score-1.8) > 1e-9 {
+		t.Errorf("unexpected entity %+v", ada)
+	}
+
+	people, _ := store.GetTopEntities(ctx, 10, nil, "person")
+	if len(people) != 1 || people[0].Name != "Ada Lovelace" {
+		t.Errorf("expected only people, got %+v", people)
+	}
+
+	cmd := &executor.FetchTopEntitiesFromDBCommand{Repo: store, ResponseGenerator: executor.NewResponseGenerator(store)}
+	resp, err := cmd.Execute(ctx, &domain.Plan{Command: "get_top_entities", Args: map[string]interface{}{"category": "Organizations"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orgs, _ := resp.Data.([]domain.TopEntity)
+	if len(orgs) != 1 || orgs[0].Name != "Vague Corp" || orgs[0].Count != 3 || !strings.Contains(resp.Answer, "Top organization entities") {
+		t.Errorf("expected organizations as data, got %q, %+v", resp.Answer, resp.Data)
+	}
+
+	resp, _ = cmd.Execute(ctx, &domain.Plan{Command: "get_top_entities", Args: map[string]interface{}{"category": "vehicles"}}, "")
+	if resp.Data != nil || !strings.Contains(resp.Answer, "Unknown entity category") {
+		t.Errorf("expected an unknown category to be refused, got %q", resp.Answer)
+	}
+}
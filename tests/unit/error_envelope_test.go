@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/middleware"
+)
+
+func TestWriteErrorCarriesCodeAndRequestID(t *testing.T) {
+	h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.WriteErrorDetails(w, r, http.StatusInternalServerError, domain.ErrCodeIngestFailed,
+			"Failed to ingest URL: blocked", map[string]string{"error_category": "blocked"})
+	}))
+
+	req := httptest.NewRequest("POST", "/ingest", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	var body struct {
+		Code      string            `json:"code"`
+		Message   string            `json:"message"`
+		Details   map[string]string `json:"details"`
+		RequestID string            `json:"request_id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+	}
+	if body.Code != domain.ErrCodeIngestFailed || body.Message == "" {
+		t.Errorf("unexpected envelope: %+v", body)
+	}
+	if body.Details["error_category"] != "blocked" {
+		t.Errorf("expected details to carry the category, got %v", body.Details)
+	}
+	if body.RequestID != "req-42" {
+		t.Errorf("expected request_id req-42, got %q", body.RequestID)
+	}
+}
+
+func TestWriteErrorOmitsEmptyDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	middleware.WriteError(rec, httptest.NewRequest("GET", "/articles", nil), http.StatusBadRequest,
+		domain.ErrCodeBadRequest, "limit must be between 1 and 100")
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if body["code"] != domain.ErrCodeBadRequest {
+		t.Errorf("expected BAD_REQUEST, got %v", body["code"])
+	}
+	if _, ok := body["details"]; ok {
+		t.Errorf("expected no details key, got %v", body)
+	}
+}
+
+func TestCreateErrorResponseSetsNoAnswerCode(t *testing.T) {
+	rg := executor.NewResponseGenerator(nil)
+	resp := rg.CreateErrorResponse("search", "No articles found")
+
+	if resp.Error == nil || resp.Error.Code != domain.ErrCodeNoAnswer {
+		t.Fatalf("expected NO_ANSWER error, got %+v", resp.Error)
+	}
+	if resp.Answer != "No articles found" {
+		t.Errorf("expected answer text to be kept, got %q", resp.Answer)
+	}
+}
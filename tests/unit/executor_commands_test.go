@@ -17,14 +17,14 @@ func TestExecutorCommandPattern(t *testing.T) {
 		t.Fatal("executor should not be nil")
 	}
 
-	// Test that unknown command returns error message
+	// Test that unknown command asks the user to clarify
 	plan := &domain.Plan{Command: "unknown_command"}
 	resp, err := ex.Execute(context.Background(), plan, "test query")
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	if !strings.Contains(resp.Answer, "Command not supported") {
-		t.Errorf("expected error message for unknown command, got: %s", resp.Answer)
+	if resp.ResponseType != domain.ResponseClarification || !strings.Contains(resp.Answer, "Did you mean") {
+		t.Errorf("expected clarification for unknown command, got %s: %s", resp.ResponseType, resp.Answer)
 	}
 }
 
@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/export"
+	"article-assistant/internal/repository"
+)
+
+func TestExportJSONL(t *testing.T) {
+	article := domain.Article{ID: "a1", URL: "https://example.com/a", Title: "A", Content: "full text", Embedding: []float32{0.5, 1}}
+
+	for _, withEmbeddings := range []bool{false, true} {
+		var buf bytes.Buffer
+		w, err := export.NewWriter(&buf, export.FormatJSONL, withEmbeddings)
+		if err != nil {
+			t.Fatalf("new writer: %v", err)
+		}
+		w.Write(article)
+		w.Write(article)
+		w.Flush()
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if _, ok := record["content"]; ok {
+			t.Error("expected full text to be left out")
+		}
+		if entities, ok := record["entities"].([]interface{}); !ok || len(entities) != 0 {
+			t.Errorf("expected missing entities as an empty list, got %v", record["entities"])
+		}
+		if _, ok := record["embedding"]; ok != withEmbeddings {
+			t.Errorf("embeddings=%v: unexpected embedding presence in %s", withEmbeddings, lines[0])
+		}
+	}
+
+	if _, err := export.NewWriter(&bytes.Buffer{}, "xml", false); err == nil {
+		t.Error("expected an unsupported format to be rejected")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := export.NewWriter(&buf, export.FormatCSV, true)
+	w.Write(domain.Article{
+		ID: "a1", URL: "https://example.com/a", Title: "Commas, \"quotes\"", SentimentScore: 0.25,
+		Topics: []domain.SemanticTopic{{Name: "AI"}}, Embedding: []float32{1, 2},
+	})
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected header and one row, got %d rows", len(rows))
+	}
+	header, row := rows[0], rows[1]
+	col := func(name string) string {
+		for i, h := range header {
+			if h == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("missing column %s", name)
+		return ""
+	}
+	if col("title") != "Commas, \"quotes\"" || col("sentiment_score") != "0.25" || col("embedding") != "[1,2]" {
+		t.Errorf("unexpected row: %v", row)
+	}
+	if !strings.Contains(col("topics"), `"name":"AI"`) || col("entities") != "[]" {
+		t.Errorf("expected list columns as JSON, got topics=%s entities=%s", col("topics"), col("entities"))
+	}
+
+	// An empty export still has a header
+	var empty bytes.Buffer
+	w, _ = export.NewWriter(&empty, export.FormatCSV, false)
+	w.Flush()
+	if strings.TrimSpace(empty.String()) != "id,url,title,source_domain,author,section,language,published_at,created_at,summary,sentiment,sentiment_score,tone,entities,keywords,topics" {
+		t.Errorf("unexpected empty export: %q", empty.String())
+	}
+}
+
+func TestMemoryStoreStreamArticlesByTopic(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{URL: "https://example.com/1", Topics: []domain.SemanticTopic{{Name: "Artificial Intelligence"}}, Embedding: []float32{1}},
+		{URL: "https://example.com/2", Topics: []domain.SemanticTopic{{Name: "Climate"}}},
+		{URL: "https://example.com/3", Topics: []domain.SemanticTopic{{Name: "AI intelligence race"}}},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	var got []domain.Article
+	err := store.StreamArticles(ctx, domain.ArticleFilter{Topic: "intelligence"}, false, func(a domain.Article) error {
+		got = append(got, a)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if len(got) != 2 || got[0].URL != "https://example.com/1" || got[1].URL != "https://example.com/3" {
+		t.Fatalf("expected matching articles oldest first, got %+v", got)
+	}
+	if got[0].Embedding != nil {
+		t.Error("expected embeddings to be left out")
+	}
+}
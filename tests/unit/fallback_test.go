@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/repository"
+)
+
+func TestFallbackUsesAMPCopyOfPaywalledPage(t *testing.T) {
+	fullText := strings.Repeat("The central bank held rates steady as inflation cooled. ", 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/story":
+			fmt.Fprint(w, `<html><head><title>Rates hold</title><link rel="amphtml" href="/story/amp"></head>
+<body><article><p>The central bank held rates.</p><div class="paywall-banner">Subscribe to continue reading</div></article></body></html>`)
+		case "/cache":
+			fmt.Fprint(w, `<html><body><div id="paywall">Subscriber-only content</div></body></html>`)
+		case "/story/amp":
+			fmt.Fprintf(w, `<html><head><title>Rates hold</title></head><body><article><p>%s</p></article></body></html>`, fullText)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	svc := newFetchTestService(store)
+	svc.Fallback = ingest.NewFallback(ingest.GoogleCacheStrategy{Endpoint: server.URL + "/cache"}, ingest.AMPStrategy{})
+
+	if err := svc.IngestURL(ctx, server.URL+"/story"); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	a, _ := store.GetArticleByURL(ctx, server.URL+"/story")
+	if a == nil || !strings.Contains(a.Content, "inflation cooled") || a.Title != "Rates hold" {
+		t.Fatalf("expected the AMP copy to be stored, got %+v", a)
+	}
+
+	stats := map[string]ingest.StrategyStats{}
+	for _, st := range svc.Fallback.Stats() {
+		stats[st.Strategy] = st
+	}
+	if s := stats["google_cache"]; s.Attempts != 1 || s.Successes != 0 {
+		t.Errorf("expected the paywalled cache copy to count as a failure, got %+v", s)
+	}
+	if s := stats["amp"]; s.Attempts != 1 || s.Successes != 1 {
+		t.Errorf("expected one AMP success, got %+v", s)
+	}
+
+	var metrics strings.Builder
+	svc.Fallback.WritePrometheus(&metrics)
+	if !strings.Contains(metrics.String(), `fetch_fallback_successes_total{strategy="amp"} 1`) {
+		t.Errorf("unexpected metrics:\n%s", metrics.String())
+	}
+}
+
+func TestFallbackSkipsCompletePages(t *testing.T) {
+	f := ingest.NewFallback(ingest.AMPStrategy{})
+	text := strings.Repeat("word ", 200)
+	if f.NeedsFallback("<html><body>"+text+"</body></html>", text) {
+		t.Error("expected a long free page to be kept")
+	}
+	if !f.NeedsFallback(`<script type="application/ld+json">{"isAccessibleForFree": "False"}</script>`, text) {
+		t.Error("expected isAccessibleForFree=false to mark a paywall")
+	}
+	if !f.NeedsFallback("<p>Teaser</p>", "Teaser") {
+		t.Error("expected a short page to need a fallback")
+	}
+}
+
+func TestArchiveStrategyRequestsRawSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("url") != "https://example.com/a" {
+			t.Errorf("unexpected lookup %s", r.URL)
+		}
+		fmt.Fprint(w, `{"archived_snapshots": {"closest": {"available": true, "timestamp": "20250102030405", "url": "http://web.archive.org/web/20250102030405/https://example.com/a"}}}`)
+	}))
+	defer server.Close()
+
+	alt, err := ingest.ArchiveStrategy{Endpoint: server.URL}.AlternateURL(context.Background(), http.DefaultClient, "https://example.com/a", "")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if alt != "http://web.archive.org/web/20250102030405id_/https://example.com/a" {
+		t.Errorf("unexpected snapshot URL %s", alt)
+	}
+}
@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/processing"
+	"article-assistant/internal/repository"
+)
+
+func newFetchTestService(store repository.ArticleStore) *ingest.Service {
+	client := ingest.NewFetchClient(
+		// Test servers listen on loopback
+		ingest.FetchOptions{Timeout: 100 * time.Millisecond, MaxRedirects: 2, Backoff: time.Millisecond, AllowPrivate: true},
+		ingest.PolitenessOptions{CrawlDelay: -1},
+	)
+	return &ingest.Service{Repo: store, LLM: llm.NewMockClient(), Client: client}
+}
+
+func TestFetchRetriesAndDecodesCharset(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		w.Write([]byte("<html><head><title>Caf\xe9 prices rise</title></head><body><p>Prices at the caf\xe9 rose again.</p></body></html>"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	if err := newFetchTestService(store).IngestURL(ctx, server.URL+"/story"); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected one retry after the 503, got %d calls", calls)
+	}
+	a, _ := store.GetArticleByURL(ctx, server.URL+"/story")
+	if a == nil || a.Title != "Café prices rise" {
+		t.Fatalf("expected the title decoded from ISO-8859-1, got %+v", a)
+	}
+}
+
+func TestFetchErrorCategoriesInStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /members\n"))
+		case "/missing":
+			http.NotFound(w, r)
+		case "/slow":
+			time.Sleep(300 * time.Millisecond)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/paywall":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	facade := newTestFacade(newFetchTestService(repository.NewMemoryStore()))
+	cases := map[string]string{
+		"/missing":    ingest.FetchNotFound,
+		"/slow":       ingest.FetchTimeout,
+		"/loop":       ingest.FetchTooManyRedirects,
+		"/paywall":    ingest.FetchBlocked,
+		"/members/ab": ingest.FetchBlocked,
+	}
+	for path, want := range cases {
+		status, finished := facade.AddNewArticle(context.Background(), server.URL+path, 5*time.Second)
+		if !finished || status.State != processing.StatusFailed {
+			t.Errorf("%s: expected failed status, got %+v", path, status)
+			continue
+		}
+		if status.ErrorCategory != want {
+			t.Errorf("%s: expected category %s, got %q (%s)", path, want, status.ErrorCategory, status.Error)
+		}
+		if status.Attempts != 1 {
+			t.Errorf("%s: expected the fetcher's retries to stand in for the facade's, got %d attempts", path, status.Attempts)
+		}
+	}
+}
+
+func TestFetchURLStoresTextUntilEnriched(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<html><head><title>Harbour reopens</title></head><body><p>The harbour reopened on Monday.</p></body></html>"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	svc := newFetchTestService(store)
+	url := server.URL + "/story"
+
+	pending, err := svc.FetchURL(ctx, url)
+	if err != nil || !pending {
+		t.Fatalf("expected the fetched text to await enrichment, got %v, %v", pending, err)
+	}
+	if a, _ := store.GetArticleByURL(ctx, url); a == nil || a.Status != domain.ArticlePending || a.Summary != "" {
+		t.Fatalf("expected a pending article before enrichment, got %+v", a)
+	}
+	if p, _ := store.GetPendingArticle(ctx, url); p == nil || p.Title != "Harbour reopens" || p.Content == "" {
+		t.Fatalf("expected the fetched text to be stored, got %+v", p)
+	}
+
+	if err := svc.EnrichURL(ctx, url); err != nil {
+		t.Fatalf("enrich: %v", err)
+	}
+	if a, _ := store.GetArticleByURL(ctx, url); a == nil || a.Status != domain.ArticleEnriched || a.Summary == "" || a.Title != "Harbour reopens" {
+		t.Fatalf("expected an enriched article, got %+v", a)
+	}
+	if urls, _ := store.ListPendingArticleURLs(ctx); len(urls) != 0 {
+		t.Errorf("expected nothing left pending, got %v", urls)
+	}
+	if pending, err := svc.FetchURL(ctx, url); err != nil || pending {
+		t.Errorf("expected a stored article not to be fetched again, got %v, %v", pending, err)
+	}
+}
+
+func TestFetchRefusesNonPublicAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	client := ingest.NewFetchClient(ingest.FetchOptions{Timeout: 100 * time.Millisecond, Backoff: time.Millisecond}, ingest.PolitenessOptions{CrawlDelay: -1})
+	svc := &ingest.Service{Repo: repository.NewMemoryStore(), LLM: llm.NewMockClient(), Client: client}
+	for _, url := range []string{
+		server.URL + "/story",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.8/admin",
+		"http://[::1]:8080/",
+		"http://[::ffff:127.0.0.1]/",
+		"http://100.64.0.1/",
+	} {
+		_, err := svc.FetchURL(context.Background(), url)
+		if ingest.FetchErrorCategory(err) != ingest.FetchNotAllowed || !errors.Is(err, ingest.ErrPrivateAddress) {
+			t.Errorf("%s: expected a %s fetch error for a non-public address, got %v", url, ingest.FetchNotAllowed, err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("expected no request to reach the loopback server, got %d", n)
+	}
+}
+
+func TestFetchCapsPageSizeAndRedirectSchemes(t *testing.T) {
+	page := "<html><body><p>" + strings.Repeat("a", 2048) + "</p></body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.NotFound(w, r)
+		case "/big":
+			w.Write([]byte(page))
+		case "/streamed":
+			// Flushing first sends the body chunked, without a Content-Length
+			w.(http.Flusher).Flush()
+			w.Write([]byte(page))
+		case "/small":
+			w.Write([]byte("<html><body><p>Short.</p></body></html>"))
+		case "/file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	client := ingest.NewFetchClient(ingest.FetchOptions{Timeout: time.Second, Backoff: time.Millisecond, MaxBytes: 1024, AllowPrivate: true}, ingest.PolitenessOptions{CrawlDelay: -1})
+	svc := &ingest.Service{Repo: repository.NewMemoryStore(), LLM: llm.NewMockClient(), Client: client}
+	cases := map[string]string{
+		"/big":      ingest.FetchTooLarge,
+		"/streamed": ingest.FetchTooLarge,
+		"/file":     ingest.FetchNotAllowed,
+		"/small":    "",
+	}
+	for path, want := range cases {
+		_, err := svc.FetchURL(context.Background(), server.URL+path)
+		if got := ingest.FetchErrorCategory(err); got != want || (want == "" && err != nil) {
+			t.Errorf("%s: expected category %q, got %q (%v)", path, want, got, err)
+		}
+	}
+}
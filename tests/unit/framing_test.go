@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestOnePerSource(t *testing.T) {
+	articles := []domain.Article{
+		{URL: "https://www.cnn.com/a"},
+		{URL: "https://cnn.com/b"},
+		{URL: "https://bbc.co.uk/c"},
+		{URL: "https://reuters.com/d"},
+	}
+
+	got := executor.OnePerSource(articles, 2)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(got))
+	}
+	if got[0].URL != "https://www.cnn.com/a" || got[1].URL != "https://bbc.co.uk/c" {
+		t.Errorf("unexpected selection: %s, %s", got[0].URL, got[1].URL)
+	}
+}
+
+func TestParseFramingNotes(t *testing.T) {
+	articles := []domain.Article{
+		{URL: "https://www.cnn.com/a"},
+		{URL: "https://bbc.co.uk/b"},
+	}
+	raw := "```json\n[{\"citation\": 2, \"emphasis\": \"costs\", \"loaded_language\": [\"slammed\"], \"summary\": \"critical\"}, {\"citation\": 9, \"summary\": \"neutral\"}]\n```"
+
+	notes, err := executor.ParseFramingNotes(raw, articles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %d", len(notes))
+	}
+	if notes[0].Source != "bbc.co.uk" || notes[0].URL != "https://bbc.co.uk/b" {
+		t.Errorf("citation 2 should map to bbc.co.uk, got %+v", notes[0])
+	}
+	// Out-of-range citations fall back to position
+	if notes[1].Citation != 2 || notes[1].Source != "bbc.co.uk" {
+		t.Errorf("expected positional fallback, got %+v", notes[1])
+	}
+
+	if _, err := executor.ParseFramingNotes("not json", articles); err == nil {
+		t.Error("expected error for non-JSON reply")
+	}
+}
@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+// wordsLLM embeds text as a bag of words, so sentences sharing words are similar, and answers
+// every prompt with verdict
+type wordsLLM struct {
+	*llm.MockClient
+	verdict string
+	prompts []string
+}
+
+func (w *wordsLLM) Embed(ctx context.Context, text string) ([]float32, error) {
+	embedding := make([]float32, 64)
+	for _, word := range strings.Fields(strings.ToLower(strings.Trim(text, ".!? "))) {
+		h := fnv.New32a()
+		h.Write([]byte(strings.Trim(word, ".,!?")))
+		embedding[h.Sum32()%64]++
+	}
+	return embedding, nil
+}
+
+func (w *wordsLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	w.prompts = append(w.prompts, prompt)
+	return w.verdict, nil
+}
+
+func groundingStore(t *testing.T) repository.ArticleStore {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	if err := store.UpsertArticle(context.Background(), &domain.Article{ID: "a", URL: "https://example.com/a", Title: "Rates",
+		Summary: "The central bank raised interest rates to fight inflation."}); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestGroundingEmbeddingFlagsUnsupportedSentences(t *testing.T) {
+	checker := &executor.GroundingChecker{Repo: groundingStore(t), LLM: &wordsLLM{MockClient: llm.NewMockClient()},
+		Method: executor.GroundingEmbedding, MinScore: 0.6}
+	resp := &domain.ChatResponse{
+		Answer:  "The central bank raised interest rates to fight inflation [1]. Astronauts planted potatoes on Mars yesterday.",
+		Sources: []domain.Source{{ID: "a", URL: "https://example.com/a"}},
+	}
+	g, err := checker.Check(context.Background(), resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Method != executor.GroundingEmbedding || g.Score < 0.45 || g.Score > 0.55 || !g.Low {
+		t.Errorf("expected half the answer supported and flagged low, got %+v", g)
+	}
+	if len(g.Unsupported) != 1 || !strings.HasPrefix(g.Unsupported[0], "Astronauts") {
+		t.Errorf("expected the invented sentence to be unsupported, got %q", g.Unsupported)
+	}
+}
+
+func TestGroundingLLMVerdict(t *testing.T) {
+	stub := &wordsLLM{MockClient: llm.NewMockClient(), verdict: "```json\n{\"score\": 0.9, \"unsupported\": [\" \"]}\n```"}
+	checker := &executor.GroundingChecker{Repo: groundingStore(t), LLM: stub, Method: executor.GroundingLLM, MinScore: 0.5}
+	ex := executor.NewExecutor()
+	ex.Register("ask", askStub{"Rates went up [1]."})
+	ex.Grounding = checker
+
+	resp, err := ex.Execute(context.Background(), &domain.Plan{Command: "ask"}, "What did the bank do?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := resp.Grounding; g == nil || g.Method != executor.GroundingLLM || g.Score != 0.9 || g.Low || len(g.Unsupported) != 0 {
+		t.Errorf("unexpected grounding %+v", g)
+	}
+	if len(stub.prompts) != 1 || !strings.Contains(stub.prompts[0], "The central bank raised interest rates") || strings.Contains(stub.prompts[0], "[1].") {
+		t.Errorf("expected the prompt to hold the source summary and the answer without markers:\n%s", stub.prompts)
+	}
+
+	ex.Register("get_top_entities", askStub{"OpenAI (3 articles)"})
+	if resp, _ := ex.Execute(context.Background(), &domain.Plan{Command: "get_top_entities"}, ""); resp.Grounding != nil {
+		t.Errorf("expected listings not to be checked, got %+v", resp.Grounding)
+	}
+}
+
+func TestParseGrounding(t *testing.T) {
+	g, err := executor.ParseGrounding(`{"score": 1.7, "unsupported": ["Rates doubled."]}`)
+	if err != nil || g.Score != 1 || len(g.Unsupported) != 1 {
+		t.Errorf("ParseGrounding() = %+v, %v; want the score clamped to 1", g, err)
+	}
+	for _, raw := range []string{"Looks grounded to me.", `{"unsupported": []}`} {
+		if _, err := executor.ParseGrounding(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+// askStub answers with a fixed text citing example.com/a
+type askStub struct{ answer string }
+
+func (s askStub) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	return &domain.ChatResponse{Answer: s.answer, Task: plan.Command, ResponseType: domain.ResponseText,
+		Sources: []domain.Source{{ID: "a", URL: "https://example.com/a", Title: "Rates"}}}, nil
+}
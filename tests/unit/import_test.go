@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+// noSummaryLLM fails if asked to summarize, so tests can check a supplied summary is used
+type noSummaryLLM struct {
+	*llm.MockClient
+}
+
+func (n *noSummaryLLM) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
+	return "", errors.New("unexpected summarize call")
+}
+
+func TestImportUsesSuppliedAnalysis(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	svc := &ingest.Service{Repo: store, LLM: &noSummaryLLM{MockClient: llm.NewMockClient()}}
+
+	published := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	a, err := svc.Import(ctx, ingest.ImportedArticle{
+		URL: "https://intranet.example.com/memo", Title: "Quarterly memo", Text: "Revenue grew across all regions.",
+		Summary: "Revenue grew.", PublishedAt: &published,
+		Analysis: &domain.SemanticAnalysis{Sentiment: "positive", SentimentScore: 0.9, Topics: []domain.SemanticTopic{{Name: "Finance"}}},
+	})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if a.CanonicalID != "" {
+		t.Fatalf("expected the article to be stored, got duplicate of %s", a.CanonicalID)
+	}
+
+	stored, _ := store.GetArticleByURL(ctx, "https://intranet.example.com/memo")
+	if stored == nil || !stored.Imported || stored.Summary != "Revenue grew." || stored.Sentiment != "positive" || len(stored.Topics) != 1 {
+		t.Fatalf("unexpected stored article: %+v", stored)
+	}
+	if stored.Embedding == nil || stored.SourceDomain != "intranet.example.com" || stored.Entities == nil {
+		t.Errorf("expected embedding, source and empty entities to be filled in, got %+v", stored)
+	}
+
+	// Imported articles are never re-fetched: not by ingest, nor by refresh
+	if err := svc.IngestURL(ctx, "https://intranet.example.com/memo"); err != nil {
+		t.Errorf("expected ingesting an imported URL to be a no-op, got %v", err)
+	}
+	if stale, _ := store.GetStaleArticles(ctx, time.Now().Add(time.Hour), 10); len(stale) != 0 {
+		t.Errorf("expected imported articles to be skipped by refresh, got %d", len(stale))
+	}
+
+	if _, err := svc.Import(ctx, ingest.ImportedArticle{URL: "https://example.com/x", Title: " "}); err == nil {
+		t.Error("expected missing title and text to be rejected")
+	}
+}
+
+func TestImportRejectsURLsThatAreNotWebAddresses(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	policy, err := ingest.ParseURLPolicy("", "blocked.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := &ingest.Service{Repo: store, LLM: llm.NewMockClient(), URLPolicy: policy}
+
+	for _, url := range []string{
+		"javascript:alert(document.cookie)",
+		"JavaScript:alert(1)",
+		"data:text/html,<script>alert(1)</script>",
+		"ftp://example.com/memo",
+		"https:///memo",
+		"https://blocked.example.com/memo",
+	} {
+		_, err := svc.Import(ctx, ingest.ImportedArticle{URL: url, Title: "Memo", Text: "Revenue grew across all regions."})
+		if !errors.Is(err, ingest.ErrURLNotAllowed) {
+			t.Errorf("Import(%q): expected ErrURLNotAllowed, got %v", url, err)
+		}
+		if a, _ := store.GetArticleByURL(ctx, url); a != nil {
+			t.Errorf("Import(%q): expected nothing to be stored", url)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/processing"
+	"article-assistant/internal/repository"
+)
+
+func TestRecordIngestFailureMergesByURL(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+
+	first := &domain.IngestFailure{URL: "https://example.com/a", Error: "status 503", ErrorCategory: "server_error", Attempts: 3}
+	if err := store.RecordIngestFailure(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	second := &domain.IngestFailure{URL: "https://example.com/a", Error: "timed out", ErrorCategory: "timeout", Attempts: 3}
+	store.RecordIngestFailure(ctx, second)
+	store.RecordIngestFailure(ctx, &domain.IngestFailure{URL: "https://example.com/b", Error: "status 404", ErrorCategory: "not_found", Attempts: 1})
+
+	if second.ID != first.ID || second.Attempts != 6 || second.Failures != 2 || !second.FirstFailedAt.Equal(first.FirstFailedAt) {
+		t.Errorf("expected the second failure to extend the first, got %+v", second)
+	}
+	if second.Error != "timed out" || second.ErrorCategory != "timeout" {
+		t.Errorf("expected the latest error to be kept, got %+v", second)
+	}
+
+	all, _ := store.ListIngestFailures(ctx, "", 10)
+	if len(all) != 2 || all[0].URL != "https://example.com/b" {
+		t.Errorf("expected 2 failures, most recent first, got %+v", all)
+	}
+	if found, _ := store.ListIngestFailures(ctx, "timeout", 10); len(found) != 1 || found[0].URL != "https://example.com/a" {
+		t.Errorf("expected category filter to match one failure, got %+v", found)
+	}
+	if got, _ := store.GetIngestFailure(ctx, first.ID); got == nil || got.URL != "https://example.com/a" {
+		t.Errorf("expected failure by ID, got %+v", got)
+	}
+
+	if resolved, _ := store.ResolveIngestFailure(ctx, "https://example.com/a"); !resolved {
+		t.Error("expected the failure to be resolved")
+	}
+	if got, _ := store.GetIngestFailure(ctx, first.ID); got != nil {
+		t.Errorf("expected resolved failure to be gone, got %+v", got)
+	}
+}
+
+func TestFailureHookRecordsAndResolves(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	hook := processing.FailureHook(store)
+
+	hook(ctx, processing.Status{URL: "https://example.com/a", State: processing.StatusFailed, Error: "blocked", ErrorCategory: "blocked", Attempts: 1})
+	hook(ctx, processing.Status{URL: "https://example.com/b", State: processing.StatusFailed, Error: "ingest queue full"})
+
+	found, _ := store.ListIngestFailures(ctx, "", 10)
+	if len(found) != 1 || found[0].URL != "https://example.com/a" || found[0].ErrorCategory != "blocked" || found[0].Attempts != 1 {
+		t.Fatalf("expected only the attempted ingest to be recorded, got %+v", found)
+	}
+
+	hook(ctx, processing.Status{URL: "https://example.com/a", State: processing.StatusComplete, Attempts: 1})
+	if found, _ := store.ListIngestFailures(ctx, "", 10); len(found) != 0 {
+		t.Errorf("expected a successful ingest to clear its failure, got %+v", found)
+	}
+}
+
+func TestFailureHookMarksFailedEnrichment(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/a", Title: "Fetched", Status: domain.ArticlePending})
+
+	processing.FailureHook(store)(ctx, processing.Status{URL: "https://example.com/a", State: processing.StatusFailed,
+		Phase: processing.PhaseEnrich, Error: "failed to summarize: invalid request", Attempts: 1, EnrichAttempts: 1})
+
+	items, total, _ := store.ListArticles(ctx, domain.ArticleFilter{Status: domain.ArticleFailed}, 10, 0, "", true)
+	if total != 1 || items[0].Status != domain.ArticleFailed || items[0].StatusError != "failed to summarize: invalid request" {
+		t.Errorf("expected the article to be listed as failed with its reason, got %+v", items)
+	}
+}
+
+func TestSQLiteStorePersistsIngestFailures(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	store, err := repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	failure := &domain.IngestFailure{URL: "https://example.com/a", Error: "status 503", Attempts: 3}
+	store.RecordIngestFailure(ctx, failure)
+	store.RecordIngestFailure(ctx, &domain.IngestFailure{URL: "https://example.com/a", Error: "status 503", Attempts: 3})
+	store.RecordIngestFailure(ctx, &domain.IngestFailure{URL: "https://example.com/b", Error: "status 404", Attempts: 1})
+	store.ResolveIngestFailure(ctx, "https://example.com/b")
+	db.Close()
+
+	db, err = sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	store, err = repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	found, _ := store.ListIngestFailures(ctx, "", 10)
+	if len(found) != 1 || found[0].ID != failure.ID || found[0].Attempts != 6 || found[0].Failures != 2 {
+		t.Errorf("expected the merged failure to survive reopen, got %+v", found)
+	}
+}
@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/repository"
+)
+
+func TestKeywordsAndTopicsRankBySummedRelevance(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	articles := []*domain.Article{
+		{URL: "https://example.com/a", Keywords: []domain.SemanticKeyword{{Term: "Inflation", Relevance: 0.9}, {Term: "jobs", Relevance: 0.3}},
+			Topics: []domain.SemanticTopic{{Name: "Economy", Score: 0.8}}},
+		{URL: "https://example.com/b", Keywords: []domain.SemanticKeyword{{Term: "inflation ", Relevance: 0.7}, {Term: "Inflation", Relevance: 0.2}, {Term: "jobs", Relevance: 0.4}},
+			Topics: []domain.SemanticTopic{{Name: "economy", Score: 0.6}, {Name: "Labor", Score: 0.9}}},
+		{URL: "https://example.com/c", Keywords: []domain.SemanticKeyword{{Term: "jobs", Relevance: 0.5}}},
+	}
+	var urls []string
+	for _, a := range articles {
+		store.UpsertArticle(ctx, a)
+		urls = append(urls, a.URL)
+	}
+
+	keywords, topics, err := store.GetKeywordsAndTopics(ctx, urls, 5)
+	if err != nil {
+		t.Fatalf("keywords and topics: %v", err)
+	}
+	if len(keywords) != 2 || keywords[0].Term != "Inflation" || keywords[0].Articles != 2 || keywords[1].Term != "jobs" || keywords[1].Articles != 3 {
+		t.Fatalf("expected merged spellings ranked by relevance, got %+v", keywords)
+	}
+	// A term repeated in one article counts its best relevance once
+	if r := keywords[0].Relevance; r < 1.59 || r > 1.61 {
+		t.Errorf("expected inflation relevance 1.6, got %v", r)
+	}
+	if len(topics) != 2 || topics[0].Term != "Economy" || topics[0].Articles != 2 || topics[1].Term != "Labor" {
+		t.Errorf("unexpected topics %+v", topics)
+	}
+
+	cmd := &executor.FetchKeywordsOrTopicsCommand{Repo: store, ResponseGenerator: executor.NewResponseGenerator(store)}
+	resp, err := cmd.Execute(ctx, &domain.Plan{Command: "keywords_or_topics", Args: map[string]interface{}{"urls": []interface{}{urls[0], urls[1]}}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(resp.Answer, "1. Inflation (relevance 1.60, 2 articles)") {
+		t.Errorf("expected the ranking numbers in the answer, got %q", resp.Answer)
+	}
+	data, ok := resp.Data.(executor.KeywordsAndTopics)
+	if !ok || len(data.Keywords) != 2 || data.Topics[0].Relevance < 1.39 {
+		t.Errorf("expected aggregates in the response data, got %+v", resp.Data)
+	}
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/language"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The company said that it was not planning to raise prices this year, and analysts have welcomed the decision from the board.", "en"},
+		{"spanish", "El gobierno anunció que la nueva ley de inteligencia artificial entrará en vigor el próximo año, según los ministros de la región.", "es"},
+		{"french", "Le gouvernement a annoncé que la nouvelle loi sur l'intelligence artificielle sera appliquée dans les entreprises pour protéger les citoyens.", "fr"},
+		{"german", "Die Regierung hat angekündigt, dass das neue Gesetz über künstliche Intelligenz im nächsten Jahr in Kraft treten wird und für alle Firmen gilt.", "de"},
+		{"russian", "Правительство заявило, что новый закон об искусственном интеллекте вступит в силу в следующем году, и это было важно для компаний.", "ru"},
+		{"japanese", "政府は来年、人工知能に関する新しい法律を施行すると発表しました。", "ja"},
+		{"chinese", "政府宣布关于人工智能的新法律将于明年生效。", "zh"},
+		{"too short", "OpenAI", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := language.Detect(tt.text); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for input, want := range map[string]string{"Spanish": "es", "es": "es", "español": "es", " FRENCH ": "fr", "Deutsch": "de"} {
+		if got, ok := language.Normalize(input); !ok || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := language.Normalize("Klingon"); ok {
+		t.Error("expected unsupported language to be rejected")
+	}
+	if language.Name("es") != "Spanish" {
+		t.Errorf("unexpected name %q", language.Name("es"))
+	}
+}
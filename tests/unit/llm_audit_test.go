@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"article-assistant/internal/llm"
+	"article-assistant/internal/llmaudit"
+	"article-assistant/internal/tenant"
+	"article-assistant/internal/usage"
+)
+
+// auditedLLM reports token usage like the OpenAI client and fails sentiment calls
+type auditedLLM struct {
+	*llm.MockClient
+}
+
+func (auditedLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	usage.Record(ctx, nil, "gpt-4o-mini", 100, 20)
+	return strings.Repeat("é", 30), nil
+}
+
+func (auditedLLM) SentimentScore(ctx context.Context, text string) (float64, error) {
+	return 0, errors.New("status code: 503")
+}
+
+func TestAuditClientRecordsCalls(t *testing.T) {
+	sink, err := llmaudit.OpenFile(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	client := llmaudit.Wrap(auditedLLM{llm.NewMockClient()}, sink, "openai", "gpt-4o-mini")
+	client.ResponseChars = 10
+
+	tracker := usage.NewTracker()
+	ctx := usage.NewContext(context.Background(), tracker)
+	client.GenerateText(ctx, "hello")
+	client.GenerateText(ctx, "hello")
+	client.SentimentScore(ctx, "text")
+	client.PlanQuery(ctx, "summarize https://example.com/a")
+
+	if u := tracker.Usage(); u.PromptTokens != 200 || u.CompletionTokens != 40 {
+		t.Errorf("expected audited calls to still count toward the request, got %+v", u)
+	}
+
+	all, err := sink.List(ctx, llmaudit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].Method != "PlanQuery" || !strings.Contains(all[0].Response, `"command"`) {
+		t.Fatalf("expected 4 entries, newest first, got %+v", all)
+	}
+
+	generated, _ := sink.List(ctx, llmaudit.Filter{Method: "GenerateText"})
+	if len(generated) != 2 || generated[0].PromptHash != generated[1].PromptHash || len(generated[0].PromptHash) != 64 {
+		t.Fatalf("expected 2 GenerateText entries with the same prompt hash, got %+v", generated)
+	}
+	e := generated[0]
+	if e.Response != strings.Repeat("é", 10) || !e.Truncated {
+		t.Errorf("expected response cut to 10 characters, got %q (truncated=%v)", e.Response, e.Truncated)
+	}
+	if e.PromptTokens != 100 || e.CompletionTokens != 20 || e.Cost == 0 || e.Model != "gpt-4o-mini" || e.Provider != "openai" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if all[0].PromptHash == e.PromptHash {
+		t.Error("expected different inputs to hash differently")
+	}
+
+	failed, _ := sink.List(ctx, llmaudit.Filter{Method: "SentimentScore"})
+	if len(failed) != 1 || failed[0].Error != "status code: 503" || failed[0].Response != "" {
+		t.Errorf("expected the failed call with its error, got %+v", failed)
+	}
+
+	if limited, _ := sink.List(ctx, llmaudit.Filter{Limit: 1}); len(limited) != 1 || limited[0].Method != "PlanQuery" {
+		t.Errorf("expected only the newest entry, got %+v", limited)
+	}
+	if later, _ := sink.List(ctx, llmaudit.Filter{Since: time.Now().Add(time.Hour)}); len(later) != 0 {
+		t.Errorf("expected no entries after since, got %+v", later)
+	}
+}
+
+func TestAuditEntriesAreFilteredByTenant(t *testing.T) {
+	sink, err := llmaudit.OpenFile(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	client := llmaudit.Wrap(llm.NewMockClient(), sink, "openai", "gpt-4o-mini")
+
+	ctx := context.Background()
+	client.GenerateText(tenant.NewContext(ctx, "newsroom"), "newsroom prompt")
+	client.GenerateText(tenant.NewContext(ctx, "research"), "research prompt")
+	client.GenerateText(tenant.NewContext(ctx, "research"), "another research prompt")
+
+	newsroom, _ := sink.List(ctx, llmaudit.Filter{Tenant: "newsroom"})
+	if len(newsroom) != 1 || newsroom[0].Tenant != "newsroom" {
+		t.Errorf("expected only the newsroom entry, got %+v", newsroom)
+	}
+	if research, _ := sink.List(ctx, llmaudit.Filter{Tenant: "research"}); len(research) != 2 {
+		t.Errorf("expected the 2 research entries, got %+v", research)
+	}
+	if all, _ := sink.List(ctx, llmaudit.Filter{}); len(all) != 3 {
+		t.Errorf("expected every entry without a tenant filter, got %d", len(all))
+	}
+}
@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"article-assistant/internal/llm"
+)
+
+func TestLLMClientFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     llm.Config
+		wantErr bool
+	}{
+		{"default provider is openai", llm.Config{APIKey: "sk-test"}, false},
+		{"openai", llm.Config{Provider: llm.ProviderOpenAI, APIKey: "sk-test", Model: "gpt-4"}, false},
+		{"anthropic", llm.Config{Provider: llm.ProviderAnthropic, APIKey: "sk-ant-test"}, false},
+		{"gemini", llm.Config{Provider: llm.ProviderGemini, APIKey: "gm-test"}, false},
+		{"missing key", llm.Config{Provider: llm.ProviderAnthropic}, true},
+		{"gemini missing key", llm.Config{Provider: llm.ProviderGemini}, true},
+		{"unknown provider", llm.Config{Provider: "nope", APIKey: "x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := llm.NewClient(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil || client == nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnthropicEmbedRequiresEmbedder(t *testing.T) {
+	client := llm.NewAnthropic("sk-ant-test", "", nil)
+	if _, err := client.Embed(context.Background(), "text"); err == nil {
+		t.Error("expected error when no embedder is configured")
+	}
+
+	withEmbedder := llm.NewAnthropic("sk-ant-test", "", llm.NewMockClient())
+	emb, err := withEmbedder.Embed(context.Background(), "text")
+	if err != nil || len(emb) != 1536 {
+		t.Errorf("expected delegated 1536-dim embedding, got %d dims, err %v", len(emb), err)
+	}
+}
+
+func TestAllowsModel(t *testing.T) {
+	cfg := llm.Config{
+		Model:         "gpt-4-turbo",
+		TaskModels:    map[string]string{llm.TaskSummarize: "gpt-4o-mini", llm.TaskEmbedding: "text-embedding-3-large"},
+		AllowedModels: []string{"gpt-4"},
+	}
+	for model, want := range map[string]bool{
+		"gpt-4-turbo":            true,
+		"gpt-4o-mini":            true,
+		"gpt-4":                  true,
+		"text-embedding-3-large": false, // Embedding models cannot answer chat calls
+		"gpt-5":                  false,
+		"":                       false,
+	} {
+		if got := cfg.AllowsModel(model); got != want {
+			t.Errorf("AllowsModel(%q) = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestIngestionModels(t *testing.T) {
+	cfg := llm.Config{Model: "gpt-4-turbo", TaskModels: map[string]string{llm.TaskSummarize: "gpt-4o-mini"}}
+	if got := cfg.SummaryModel(); got != "gpt-4o-mini" {
+		t.Errorf("SummaryModel() = %q, want the summarize task model", got)
+	}
+	if got := cfg.SemanticsModel(); got != "gpt-4-turbo" {
+		t.Errorf("SemanticsModel() = %q, want the default model", got)
+	}
+	// Task models only apply to OpenAI
+	gemini := llm.Config{Provider: llm.ProviderGemini, TaskModels: cfg.TaskModels}
+	if got := gemini.SummaryModel(); got != llm.ModelGemini15Flash {
+		t.Errorf("Gemini SummaryModel() = %q, want %q", got, llm.ModelGemini15Flash)
+	}
+}
+
+func TestWithModel(t *testing.T) {
+	if got := llm.ModelFromContext(context.Background()); got != "" {
+		t.Errorf("expected no model, got %q", got)
+	}
+	if got := llm.ModelFromContext(llm.WithModel(context.Background(), "gpt-4")); got != "gpt-4" {
+		t.Errorf("expected gpt-4, got %q", got)
+	}
+}
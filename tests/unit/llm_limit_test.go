@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"article-assistant/internal/llm"
+	"article-assistant/internal/processing"
+)
+
+// blockingLLM holds every GenerateText call until release is closed
+type blockingLLM struct {
+	llm.MockClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return "done", nil
+}
+
+func TestLimiterRejectsAfterMaxWait(t *testing.T) {
+	inner := &blockingLLM{started: make(chan struct{}, 1), release: make(chan struct{})}
+	client := llm.WithLimit(inner, llm.NewLimiter(llm.LimitConfig{MaxConcurrency: 1, MaxWait: 20 * time.Millisecond}))
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GenerateText(context.Background(), "first")
+		done <- err
+	}()
+	<-inner.started
+
+	if _, err := client.GenerateText(context.Background(), "second"); !errors.Is(err, llm.ErrBusy) {
+		t.Fatalf("expected ErrBusy while the only slot is taken, got %v", err)
+	}
+	if !processing.IsTransient(llm.ErrBusy) {
+		t.Error("expected ingests to retry a full limiter")
+	}
+
+	close(inner.release)
+	if err := <-done; err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if _, err := client.GenerateText(context.Background(), "third"); err != nil {
+		t.Errorf("expected the freed slot to be reused, got %v", err)
+	}
+}
+
+func TestLimiterQueuesWithinMaxWait(t *testing.T) {
+	inner := &blockingLLM{started: make(chan struct{}, 2), release: make(chan struct{})}
+	client := llm.WithLimit(inner, llm.NewLimiter(llm.LimitConfig{MaxConcurrency: 1, MaxWait: time.Second}))
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.GenerateText(context.Background(), "query")
+			errs <- err
+		}()
+	}
+	<-inner.started
+	select {
+	case <-inner.started:
+		t.Fatal("second call ran while the first held the only slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(inner.release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("queued call failed: %v", err)
+		}
+	}
+}
+
+func TestNoLimiterWhenDisabled(t *testing.T) {
+	inner := &llm.MockClient{}
+	if got := llm.WithLimit(inner, llm.NewLimiter(llm.LimitConfig{})); got != llm.Client(inner) {
+		t.Errorf("expected the client unwrapped when the limit is 0, got %T", got)
+	}
+}
@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"article-assistant/internal/llm"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func fastRetryConfig() llm.RetryConfig {
+	return llm.RetryConfig{
+		MaxAttempts:      3,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       2 * time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  30 * time.Millisecond,
+	}
+}
+
+func TestRetrierRetriesTransientErrors(t *testing.T) {
+	r := llm.NewRetrier(fastRetryConfig())
+	calls := 0
+	err := r.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return &openai.APIError{HTTPStatusCode: 429, Message: "Rate limit reached"}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success on the third attempt, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestRetrierDoesNotRetryClientErrors(t *testing.T) {
+	r := llm.NewRetrier(fastRetryConfig())
+	calls := 0
+	err := r.Do(context.Background(), func() error {
+		calls++
+		return &openai.APIError{HTTPStatusCode: 400, Message: "bad request"}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("expected a single failed attempt, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	r := llm.NewRetrier(fastRetryConfig())
+	down := &openai.APIError{HTTPStatusCode: 503, Message: "service unavailable"}
+	failing := func() error { return down }
+
+	for i := 0; i < 2; i++ {
+		if err := r.Do(context.Background(), failing); !errors.Is(err, down) {
+			t.Fatalf("call %d: expected provider error, got %v", i, err)
+		}
+	}
+
+	calls := 0
+	err := r.Do(context.Background(), func() error { calls++; return nil })
+	if !errors.Is(err, llm.ErrCircuitOpen) || calls != 0 {
+		t.Fatalf("expected fast failure while open, got err=%v calls=%d", err, calls)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if err := r.Do(context.Background(), func() error { calls++; return nil }); err != nil || calls != 1 {
+		t.Fatalf("expected trial call after cooldown to succeed, got err=%v calls=%d", err, calls)
+	}
+	if err := r.Do(context.Background(), func() error { return nil }); err != nil {
+		t.Errorf("expected circuit closed after a successful trial, got %v", err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&openai.APIError{HTTPStatusCode: 429}, true},
+		{&openai.APIError{HTTPStatusCode: 502}, true},
+		{fmt.Errorf("wrapped: %w", &openai.RequestError{HTTPStatusCode: 500, Err: errors.New("boom")}), true},
+		{&openai.APIError{HTTPStatusCode: 401}, false},
+		{context.Canceled, false},
+		{llm.ErrCircuitOpen, false},
+		{errors.New("failed to parse plan JSON"), false},
+	}
+	for _, tt := range tests {
+		if got := llm.IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/llm"
+)
+
+func TestParsePlanCall(t *testing.T) {
+	plan, err := llm.ParsePlanCall(`{"command": "simplify", "args": {"urls": ["https://example.com/"], "filter": null, "author": null, "section": null, "level": "eli5", "since": null, "k": null}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Command != "simplify" || plan.Args["level"] != "eli5" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	urls, ok := plan.Args["urls"].([]interface{})
+	if !ok || len(urls) != 1 || urls[0] != "https://example.com/" {
+		t.Errorf("expected urls as a JSON-decoded list, got %#v", plan.Args["urls"])
+	}
+	if _, ok := plan.Args["filter"]; ok {
+		t.Error("expected null arguments to be omitted")
+	}
+}
+
+func TestParsePlanCallKeepsNumbersAsFloat(t *testing.T) {
+	plan, err := llm.ParsePlanCall(`{"command": "cluster_articles", "args": {"k": 4}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if k, ok := plan.Args["k"].(float64); !ok || k != 4 {
+		t.Errorf("expected k=4 as float64, got %#v", plan.Args["k"])
+	}
+}
+
+func TestParsePlanCallReadsConfidence(t *testing.T) {
+	plan, err := llm.ParsePlanCall(`{"command": "get_top_entities", "args": {}, "confidence": 0.35}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Confidence == nil || *plan.Confidence != 0.35 {
+		t.Errorf("expected confidence 0.35, got %v", plan.Confidence)
+	}
+
+	plan, err = llm.ParsePlanCall(`{"command": "get_top_entities", "args": {}, "confidence": null}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Confidence != nil {
+		t.Errorf("expected no confidence, got %v", *plan.Confidence)
+	}
+}
+
+func TestParsePlanCallReadsModel(t *testing.T) {
+	plan, err := llm.ParsePlanCall(`{"command": "compare_articles", "args": {"urls": ["https://a.com/", "https://b.com/"], "model": "gpt-4"}, "confidence": 0.9}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := llm.RequestedModel(plan); got != "gpt-4" {
+		t.Errorf("expected gpt-4, got %q", got)
+	}
+
+	plan, err = llm.ParsePlanCall(`{"command": "get_top_entities", "args": {"model": null}, "confidence": null}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := llm.RequestedModel(plan); got != "" {
+		t.Errorf("expected no model, got %q", got)
+	}
+}
+
+func TestParsePlanCallRejectsInvalidArguments(t *testing.T) {
+	for _, args := range []string{
+		`{"command": "drop_tables", "args": {}}`,
+		`{"command": "summary", "args": {"urls": ["https://example.com/"], "extra": true}}`,
+		`{"command": "summary", "args": {"urls": "https://example.com/"}}`,
+		`not json`,
+	} {
+		if _, err := llm.ParsePlanCall(args); err == nil {
+			t.Errorf("expected error for %s", args)
+		}
+	}
+}
+
+func TestParseSemanticsCall(t *testing.T) {
+	analysis, err := llm.ParseSemanticsCall(`{"entities": [{"name": "OpenAI", "category": "organization", "confidence": 0.9}], "keywords": [], "topics": [], "sentiment": "positive", "sentiment_score": 0.8, "tone": "analytical"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analysis.Entities) != 1 || analysis.Sentiment != "positive" || analysis.Topics == nil {
+		t.Errorf("unexpected analysis: %+v", analysis)
+	}
+
+	if _, err := llm.ParseSemanticsCall(`{"entities": "none"}`); err == nil {
+		t.Error("expected error for a malformed entities field")
+	}
+}
@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/llm"
+	"article-assistant/internal/llmhealth"
+)
+
+func TestClassifyLLMErrors(t *testing.T) {
+	tests := map[string]string{
+		"error, status code: 429, message: Rate limit reached": llmhealth.ClassRateLimit,
+		"error, status code: 401, message: Incorrect API key":  llmhealth.ClassAuth,
+		"Post \"https://api\": net/http: request timed out":    llmhealth.ClassTimeout,
+		"failed to parse plan JSON: invalid character":         llmhealth.ClassMalformed,
+		"anthropic API error (status=529): overloaded_error":   llmhealth.ClassServer,
+		"something odd": llmhealth.ClassOther,
+	}
+	for msg, want := range tests {
+		if got := llmhealth.Classify(errors.New(msg)); got != want {
+			t.Errorf("Classify(%q) = %s, want %s", msg, got, want)
+		}
+	}
+	if got := llmhealth.Classify(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)); got != llmhealth.ClassTimeout {
+		t.Errorf("deadline exceeded should be a timeout, got %s", got)
+	}
+}
+
+func TestMonitorAlertsOnErrorRate(t *testing.T) {
+	m := llmhealth.NewMonitor(llmhealth.AlertConfig{ErrorRate: 0.5, Window: time.Minute, MinCalls: 4, Cooldown: time.Hour})
+
+	var mu sync.Mutex
+	var alerts []llmhealth.Alert
+	done := make(chan struct{}, 10)
+	m.OnAlert(func(a llmhealth.Alert) {
+		mu.Lock()
+		alerts = append(alerts, a)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	rateLimited := errors.New("status code: 429")
+	m.Observe("openai", "gpt-4", "PlanQuery", nil)
+	m.Observe("openai", "gpt-4", "PlanQuery", rateLimited)
+	m.Observe("openai", "gpt-4", "Embed", rateLimited)
+	m.Observe("openai", "gpt-4", "Embed", rateLimited) // 3/4 errors: fires
+	m.Observe("openai", "gpt-4", "Embed", rateLimited) // Within cooldown: no second alert
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected an alert")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(alerts) != 1 {
+		t.Fatalf("expected exactly 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Errors[llmhealth.ClassRateLimit] != 3 || alerts[0].Calls != 4 {
+		t.Errorf("unexpected alert: %+v", alerts[0])
+	}
+
+	health := m.Health()
+	if len(health) != 1 || health[0].Status != "degraded" || health[0].Calls != 5 {
+		t.Errorf("unexpected health: %+v", health)
+	}
+}
+
+func TestWrappedClientExportsPrometheusCounters(t *testing.T) {
+	m := llmhealth.NewMonitor(llmhealth.DefaultAlertConfig())
+	client := llmhealth.Wrap(llm.NewMockClient(), m, "mock", "mock-1")
+
+	if _, err := client.GenerateText(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.Observe("mock", "mock-1", "PlanQuery", errors.New("failed to parse plan JSON"))
+
+	var b strings.Builder
+	if err := m.WritePrometheus(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`llm_requests_total{provider="mock",model="mock-1",method="GenerateText"} 1`,
+		`llm_errors_total{provider="mock",model="mock-1",class="malformed_output"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"article-assistant/internal/logging"
+	"article-assistant/internal/middleware"
+)
+
+func TestRequestIDMiddlewareReusesHeader(t *testing.T) {
+	var seen string
+	h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest("POST", "/chat", nil)
+	req.Header.Set(logging.RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if seen != "abc-123" {
+		t.Errorf("expected request ID in context, got %q", seen)
+	}
+	if got := rec.Header().Get(logging.RequestIDHeader); got != "abc-123" {
+		t.Errorf("expected request ID echoed on response, got %q", got)
+	}
+}
+
+func TestRequestIDMiddlewareGeneratesID(t *testing.T) {
+	var seen string
+	h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+	if seen == "" || rec.Header().Get(logging.RequestIDHeader) != seen {
+		t.Errorf("expected generated ID %q to be echoed, got %q", seen, rec.Header().Get(logging.RequestIDHeader))
+	}
+}
+
+func TestLoggerFromContextIncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	ctx := logging.WithRequestID(context.Background(), "req-42")
+	logging.FromContext(ctx).Info("hello", "command", "summary")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected JSON log line, got %q", buf.String())
+	}
+	if line["request_id"] != "req-42" || line["command"] != "summary" {
+		t.Errorf("unexpected log fields: %v", line)
+	}
+}
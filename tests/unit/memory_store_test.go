@@ -0,0 +1,416 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"article-assistant/internal/cache"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMemoryStoreUpsertKeepsID(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+
+	first := &domain.Article{ID: "a1", URL: "https://example.com/a", Title: "First", Content: "body"}
+	if err := store.UpsertArticle(ctx, first); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	second := &domain.Article{ID: "other", URL: "https://example.com/a", Title: "Second"}
+	if err := store.UpsertArticle(ctx, second); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if second.ID != "a1" {
+		t.Errorf("expected upsert to keep ID a1, got %s", second.ID)
+	}
+
+	got, err := store.GetArticleByURL(ctx, "https://example.com/a")
+	if err != nil || got == nil {
+		t.Fatalf("get: %v, %v", got, err)
+	}
+	if got.Title != "Second" {
+		t.Errorf("expected updated title, got %q", got.Title)
+	}
+
+	byID, _ := store.GetArticleByID(ctx, "a1")
+	if byID == nil || byID.Content != "" {
+		t.Errorf("expected article by ID without content, got %+v", byID)
+	}
+	if missing, _ := store.GetArticleByURL(ctx, "https://example.com/missing"); missing != nil {
+		t.Errorf("expected nil for unknown URL, got %+v", missing)
+	}
+}
+
+func TestMemoryStoreVectorSearchOrdersBySimilarity(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{URL: "https://example.com/far", Embedding: []float32{0, 1}},
+		{URL: "https://example.com/near", Embedding: []float32{1, 0.1}},
+		{URL: "https://example.com/none"},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	results, err := store.GetArticlesByVectorSearchWithFilter(ctx, []float32{1, 0}, 5, nil, domain.ArticleFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 articles with embeddings, got %d", len(results))
+	}
+	if results[0].URL != "https://example.com/near" {
+		t.Errorf("expected nearest article first, got %s", results[0].URL)
+	}
+	if results[0].Embedding != nil {
+		t.Error("expected search results without embeddings")
+	}
+
+	scoped, _ := store.GetArticlesByVectorSearchWithFilter(ctx, []float32{1, 0}, 5, []string{"https://example.com/far"}, domain.ArticleFilter{})
+	if len(scoped) != 1 || scoped[0].URL != "https://example.com/far" {
+		t.Errorf("expected URL filter to apply, got %+v", scoped)
+	}
+
+	if _, _, err := store.GetArticlesByHybridSearch(ctx, []float32{1, 0}, "x", 5, 0, nil, domain.ArticleFilter{}, 1.5); err == nil {
+		t.Error("expected an error for a vector weight above 1")
+	}
+
+	// "far" is orthogonal to the query and does not mention it, so it is not a match
+	matches, total, _ := store.GetArticlesByHybridSearch(ctx, []float32{1, 0}, "nothing-matches", 5, 0, nil, domain.ArticleFilter{}, 0.7)
+	if len(matches) != 1 || matches[0].URL != "https://example.com/near" || total != 1 {
+		t.Errorf("expected only the similar article counted as a match, got %d: %+v", total, matches)
+	}
+}
+
+func TestMemoryStoreSearchesSkipUnenrichedArticles(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/done", Title: "Rates rise", Summary: "Rates", Embedding: []float32{1, 0}})
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/pending", Title: "Rates fall", Content: "Rates", Status: domain.ArticlePending})
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/failed", Title: "Rates hold", Embedding: []float32{1, 0}, Status: domain.ArticleFailed})
+
+	if got, _ := store.GetArticlesByVectorSearchWithFilter(ctx, []float32{1, 0}, 5, nil, domain.ArticleFilter{}); len(got) != 1 || got[0].URL != "https://example.com/done" {
+		t.Errorf("expected vector search to return only the enriched article, got %+v", got)
+	}
+	if _, total, _ := store.GetArticlesByHybridSearch(ctx, []float32{1, 0}, "rates", 5, 0, nil, domain.ArticleFilter{}, 0.5); total != 1 {
+		t.Errorf("expected hybrid search to rank only the enriched article, got %d", total)
+	}
+	if existing, _ := store.GetExistingURLs(ctx, []string{"https://example.com/done", "https://example.com/pending"}); len(existing) != 1 {
+		t.Errorf("expected only the enriched article to count as stored, got %v", existing)
+	}
+
+	items, total, _ := store.ListArticles(ctx, domain.ArticleFilter{}, 10, 0, "", true)
+	if total != 3 {
+		t.Fatalf("expected every article to be listed, got %d", total)
+	}
+	statuses := map[string]string{}
+	for _, item := range items {
+		statuses[item.URL] = item.Status
+	}
+	if statuses["https://example.com/done"] != domain.ArticleEnriched || statuses["https://example.com/pending"] != domain.ArticlePending {
+		t.Errorf("expected listed statuses, got %v", statuses)
+	}
+	if _, total, _ := store.ListArticles(ctx, domain.ArticleFilter{Status: domain.ArticlePending}, 10, 0, "", true); total != 1 {
+		t.Errorf("expected the status filter to keep one article, got %d", total)
+	}
+}
+
+func TestMemoryStoreDeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+
+	canonical := &domain.Article{URL: "https://example.com/canonical", Embedding: []float32{1, 0}}
+	store.UpsertArticle(ctx, canonical)
+	store.ReplaceArticleChunks(ctx, canonical.ID, []string{"passage"}, [][]float32{{1, 0}})
+	store.SetSimplifiedSummary(ctx, canonical.ID, "child", "simple")
+	store.AddDuplicate(ctx, &domain.Article{URL: "https://example.com/copy", CanonicalID: canonical.ID}, 0.99)
+
+	existing, _ := store.GetExistingURLs(ctx, []string{"https://example.com/canonical", "https://example.com/copy", "https://example.com/new"})
+	if !existing["https://example.com/canonical"] || !existing["https://example.com/copy"] || existing["https://example.com/new"] {
+		t.Errorf("unexpected existing URLs: %v", existing)
+	}
+
+	deleted, err := store.DeleteArticleByURL(ctx, "https://example.com/canonical")
+	if err != nil || !deleted {
+		t.Fatalf("expected delete to succeed, got %v, %v", deleted, err)
+	}
+	if dup, _ := store.GetDuplicateByURL(ctx, "https://example.com/copy"); dup != nil {
+		t.Error("expected duplicate to be deleted with its canonical article")
+	}
+	if chunks, _ := store.SearchArticleChunks(ctx, []float32{1, 0}, 5, nil); len(chunks) != 0 {
+		t.Errorf("expected passages to be deleted, got %d", len(chunks))
+	}
+	if text, _ := store.GetSimplifiedSummary(ctx, canonical.ID, "child"); text != "" {
+		t.Error("expected rewrites to be deleted")
+	}
+	if deleted, _ := store.DeleteArticleByURL(ctx, "https://example.com/canonical"); deleted {
+		t.Error("expected second delete to report nothing deleted")
+	}
+}
+
+func TestMemoryStoreChatCacheExpires(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	backend := cache.NewPostgresBackend(store)
+
+	if err := backend.Set(ctx, "chat:a", []byte(`{"answer":"yes"}`), time.Hour); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	value, err := backend.Get(ctx, "chat:a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var decoded map[string]string
+	if err := json.Unmarshal(value, &decoded); err != nil || decoded["answer"] != "yes" {
+		t.Errorf("expected cached value to round-trip, got %s (%v)", value, err)
+	}
+
+	store.SetChatCache(ctx, "chat:old", nil, "stale", -time.Minute)
+	if entry, _ := store.GetChatCache(ctx, "chat:old"); entry != nil {
+		t.Error("expected expired entry to be ignored")
+	}
+
+	if err := backend.DeletePrefix(ctx, "chat:"); err != nil {
+		t.Fatalf("delete prefix: %v", err)
+	}
+	if entry, _ := store.GetChatCache(ctx, "chat:a"); entry != nil {
+		t.Error("expected entry to be cleared by prefix")
+	}
+}
+
+func TestMemoryStoreEntityGraphAndTones(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{URL: "https://example.com/1", Tone: "Analytical", Entities: []domain.SemanticEntity{{Name: "OpenAI", Category: "org"}, {Name: "Microsoft", Category: "org"}}},
+		{URL: "https://example.com/2", Tone: "analytical", Entities: []domain.SemanticEntity{{Name: "openai", Category: "org"}, {Name: "Microsoft", Category: "org"}}},
+		{URL: "https://example.com/3", Tone: "alarmist", Entities: []domain.SemanticEntity{{Name: "OpenAI", Category: "org"}}},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	graph, err := store.GetEntityGraph(ctx, 10, 2, nil)
+	if err != nil {
+		t.Fatalf("entity graph: %v", err)
+	}
+	if len(graph.Nodes) != 2 || graph.Nodes[0].ID != "openai" || graph.Nodes[0].Name != "OpenAI" || graph.Nodes[0].Articles != 3 {
+		t.Fatalf("unexpected nodes: %+v", graph.Nodes)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].Articles != 2 {
+		t.Errorf("expected one edge shared by 2 articles, got %+v", graph.Edges)
+	}
+
+	tones, _ := store.GetToneCounts(ctx, nil)
+	if len(tones) != 2 || tones[0].Tone != "analytical" || tones[0].Articles != 2 {
+		t.Errorf("unexpected tone counts: %+v", tones)
+	}
+}
+
+func TestSQLiteStorePersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	open := func() (*sql.DB, *repository.SQLiteStore) {
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		store, err := repository.NewSQLiteStore(ctx, db)
+		if err != nil {
+			t.Fatalf("new store: %v", err)
+		}
+		return db, store
+	}
+
+	db, store := open()
+	article := &domain.Article{URL: "https://example.com/a", Title: "Stored", Content: "body", Embedding: []float32{1, 0}, ETag: `"v1"`}
+	if err := store.UpsertArticle(ctx, article); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	store.ReplaceArticleChunks(ctx, article.ID, []string{"passage"}, [][]float32{{1, 0}})
+	store.AddDuplicate(ctx, &domain.Article{URL: "https://example.com/copy", CanonicalID: article.ID}, 0.99)
+	store.SetSimplifiedSummary(ctx, article.ID, "child", "simple")
+	db.Close()
+
+	db, store = open()
+	defer db.Close()
+	got, _ := store.GetArticleByURL(ctx, "https://example.com/a")
+	if got == nil || got.ID != article.ID || got.Content != "body" || got.ETag != `"v1"` {
+		t.Fatalf("expected article to survive reopen, got %+v", got)
+	}
+	results, _ := store.GetArticlesByVectorSearchWithFilter(ctx, []float32{1, 0}, 5, nil, domain.ArticleFilter{})
+	if len(results) != 1 {
+		t.Errorf("expected vector search over reloaded embeddings, got %d results", len(results))
+	}
+	if chunks, _ := store.SearchArticleChunks(ctx, []float32{1, 0}, 5, nil); len(chunks) != 1 || chunks[0].Text != "passage" {
+		t.Errorf("expected passage to survive reopen, got %+v", chunks)
+	}
+	if dup, _ := store.GetDuplicateByURL(ctx, "https://example.com/copy"); dup == nil || dup.CanonicalID != article.ID {
+		t.Errorf("expected duplicate to survive reopen, got %+v", dup)
+	}
+	if text, _ := store.GetSimplifiedSummary(ctx, article.ID, "child"); text != "simple" {
+		t.Errorf("expected rewrite to survive reopen, got %q", text)
+	}
+
+	if deleted, err := store.DeleteArticleByURL(ctx, "https://example.com/a"); err != nil || !deleted {
+		t.Fatalf("delete: %v, %v", deleted, err)
+	}
+	db.Close()
+	db, store = open()
+	defer db.Close()
+	if got, _ := store.GetArticleByURL(ctx, "https://example.com/a"); got != nil {
+		t.Error("expected deleted article to stay deleted after reopen")
+	}
+	if dup, _ := store.GetDuplicateByURL(ctx, "https://example.com/copy"); dup != nil {
+		t.Error("expected duplicate to be deleted with its canonical article")
+	}
+}
+
+func TestSQLiteStorePersistsPendingArticles(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	db, _ := sql.Open("sqlite", path)
+	store, err := repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	fetchedAt := time.Now().UTC().Truncate(time.Second)
+	for _, url := range []string{"https://example.com/a", "https://example.com/b"} {
+		store.SavePendingArticle(ctx, &domain.PendingArticle{URL: url, Title: "Fetched", Content: "body", FetchedAt: fetchedAt})
+	}
+	store.DeletePendingArticle(ctx, "https://example.com/b")
+	db.Close()
+
+	db, _ = sql.Open("sqlite", path)
+	defer db.Close()
+	if store, err = repository.NewSQLiteStore(ctx, db); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if urls, _ := store.ListPendingArticleURLs(ctx); len(urls) != 1 || urls[0] != "https://example.com/a" {
+		t.Fatalf("expected only the undeleted article to stay pending, got %v", urls)
+	}
+	got, _ := store.GetPendingArticle(ctx, "https://example.com/a")
+	if got == nil || got.Content != "body" || !got.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("expected the fetched text to survive reopen, got %+v", got)
+	}
+}
+
+func TestReembedMigratesMixedCorpus(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{ID: "a", URL: "https://example.com/a", Summary: "A", Content: "Body of a."},
+		{ID: "b", URL: "https://example.com/b", Summary: "B", Content: "Body of b.", EmbeddingModel: "text-embedding-ada-002"},
+		{ID: "c", URL: "https://example.com/c", Summary: "C", Content: "Body of c.", EmbeddingModel: "text-embedding-3-small"},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	counts, _ := store.CountEmbeddingModels(ctx)
+	if len(counts) != 3 {
+		t.Fatalf("expected three embedding models, got %+v", counts)
+	}
+
+	ingester := &ingest.Service{Repo: store, LLM: llm.NewMockClient(), EmbeddingModel: "text-embedding-3-small"}
+	var migrated []string
+	for afterID := ""; ; {
+		batch, err := store.ListArticlesToReembed(ctx, ingester.EmbeddingModel, afterID, 1)
+		if err != nil || len(batch) == 0 {
+			break
+		}
+		if err := ingester.Reembed(ctx, &batch[0]); err != nil {
+			t.Fatalf("reembed: %v", err)
+		}
+		migrated = append(migrated, batch[0].ID)
+		afterID = batch[0].ID
+	}
+	if len(migrated) != 2 || migrated[0] != "a" || migrated[1] != "b" {
+		t.Errorf("expected a and b to be re-embedded in ID order, got %v", migrated)
+	}
+
+	counts, _ = store.CountEmbeddingModels(ctx)
+	if len(counts) != 1 || counts[0].Model != "text-embedding-3-small" || counts[0].Articles != 3 {
+		t.Errorf("expected every article on the new model, got %+v", counts)
+	}
+	if got, _ := store.GetArticleByURL(ctx, "https://example.com/a"); got == nil || len(got.Embedding) == 0 {
+		t.Errorf("expected a new embedding, got %+v", got)
+	}
+	if chunks, _ := store.SearchArticleChunks(ctx, make([]float32, 1536), 5, []string{"https://example.com/a"}); len(chunks) != 1 {
+		t.Errorf("expected the article's passages to be re-indexed, got %+v", chunks)
+	}
+}
+
+func TestSQLiteStorePersistsEmbeddingModel(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	db, _ := sql.Open("sqlite", path)
+	store, err := repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	article := &domain.Article{URL: "https://example.com/a", Title: "Stored", Content: "body", Embedding: []float32{1, 0}}
+	store.UpsertArticle(ctx, article)
+	if err := store.SetArticleEmbedding(ctx, article.ID, []float32{0, 1}, "text-embedding-3-large"); err != nil {
+		t.Fatalf("set embedding: %v", err)
+	}
+	db.Close()
+
+	db, _ = sql.Open("sqlite", path)
+	defer db.Close()
+	if store, err = repository.NewSQLiteStore(ctx, db); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, _ := store.GetArticleByURL(ctx, "https://example.com/a")
+	if got == nil || got.EmbeddingModel != "text-embedding-3-large" || len(got.Embedding) != 2 || got.Embedding[1] != 1 || got.Content != "body" {
+		t.Errorf("expected the new embedding and model to survive reopen, got %+v", got)
+	}
+}
+
+func TestMemoryStoreListAllArticles(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{ID: "a", URL: "https://example.com/a", Title: "A", Content: "Body", Author: "Jane Doe", SentimentScore: 0.2, Embedding: []float32{1}},
+		{ID: "b", URL: "https://example.com/b", Title: "B", Author: "Jane Doe", SentimentScore: 0.9},
+		{ID: "c", URL: "https://example.com/c", Title: "C", Author: "John Roe", SentimentScore: 0.5},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+
+	filter := domain.ArticleFilter{Author: "jane"}
+	articles, err := store.ListAllArticles(ctx, domain.ArticleListOptions{Filter: filter, Fields: []string{"title", "content"}, Sort: "sentiment_score", Desc: true})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(articles) != 2 || articles[0].ID != "b" || articles[1].ID != "a" {
+		t.Fatalf("expected b then a, got %+v", articles)
+	}
+	if a := articles[1]; a.Title != "A" || a.Content != "Body" || a.URL != "" || a.Author != "" || a.Embedding != nil {
+		t.Errorf("expected only the ID and selected fields, got %+v", a)
+	}
+	if n, _ := store.CountArticles(ctx, filter); n != 2 {
+		t.Errorf("expected 2 matching articles, got %d", n)
+	}
+
+	// Every selectable field can be selected
+	all, err := store.ListAllArticles(ctx, domain.ArticleListOptions{Fields: repository.ArticleFields, Limit: 1, Offset: 2})
+	if err != nil || len(all) != 1 || all[0].Title == "" || all[0].Embedding != nil {
+		t.Errorf("expected one full article without its embedding, got %+v, %v", all, err)
+	}
+	if _, err := store.ListAllArticles(ctx, domain.ArticleListOptions{Fields: []string{"embedding"}}); err == nil {
+		t.Error("expected the embedding to be rejected")
+	}
+
+	items, total, err := store.ListArticles(ctx, filter, 1, 0, "", true)
+	if err != nil || total != 2 || len(items) != 1 || items[0].Author != "Jane Doe" {
+		t.Errorf("expected one page of Jane Doe's articles, got %+v, %d, %v", items, total, err)
+	}
+}
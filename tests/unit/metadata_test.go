@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/ingest"
+)
+
+const metadataHTML = `<html><head>
+<title>Heuristic Title | Site</title>
+<meta property="og:title" content="OpenGraph Title">
+<meta property="og:description" content="OG description &amp; more">
+<meta property="article:section" content="Technology">
+<meta property="article:published_time" content="2024-03-01T10:00:00Z">
+<meta name="author" content="Jane Doe">
+<script type="application/ld+json">
+{"@context":"https://schema.org","@graph":[
+  {"@type":"WebPage","name":"Page"},
+  {"@type":"NewsArticle","headline":"JSON-LD Headline","author":[{"@type":"Person","name":"John Smith"},{"@type":"Person","name":"Ann Lee"}],"articleSection":["Science","Space"],"datePublished":"2024-02-28"}
+]}
+</script>
+</head><body></body></html>`
+
+func TestMetadataExtractionPriority(t *testing.T) {
+	meta := ingest.ExtractMetadata(metadataHTML, ingest.DefaultMetadataExtractors())
+
+	if meta.Title != "JSON-LD Headline" {
+		t.Errorf("expected JSON-LD headline to win, got %q", meta.Title)
+	}
+	if meta.Author != "John Smith, Ann Lee" {
+		t.Errorf("expected JSON-LD authors, got %q", meta.Author)
+	}
+	if meta.Section != "Science" {
+		t.Errorf("expected first JSON-LD section, got %q", meta.Section)
+	}
+	if meta.PublishedAt == nil || meta.PublishedAt.Format("2006-01-02") != "2024-02-28" {
+		t.Errorf("expected JSON-LD publish date, got %v", meta.PublishedAt)
+	}
+	// JSON-LD has no description, so OpenGraph fills it in
+	if meta.Description != "OG description & more" {
+		t.Errorf("expected OpenGraph description, got %q", meta.Description)
+	}
+}
+
+func TestMetadataExtractionFallbacks(t *testing.T) {
+	og := ingest.OpenGraphExtractor{}.Extract(metadataHTML)
+	if og.Title != "OpenGraph Title" || og.Author != "Jane Doe" || og.Section != "Technology" {
+		t.Errorf("unexpected OpenGraph metadata: %+v", og)
+	}
+
+	plain := `<html><head><title>Only Title</title></head></html>`
+	meta := ingest.ExtractMetadata(plain, ingest.DefaultMetadataExtractors())
+	if meta.Title != "Only Title" {
+		t.Errorf("expected <title> fallback, got %q", meta.Title)
+	}
+	if meta.Author != "" || meta.PublishedAt != nil {
+		t.Errorf("expected empty metadata, got %+v", meta)
+	}
+}
@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/api"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/middleware"
+)
+
+func TestGeneratedAPIFilesAreUpToDate(t *testing.T) {
+	for name, want := range map[string][]byte{
+		"../../api/openapi.json": api.SpecJSON(),
+		"../../api/types.ts":     api.TypeScript(),
+	} {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is stale; run go run ./cmd/openapi", name)
+		}
+	}
+}
+
+func TestSpecCoversEveryRoute(t *testing.T) {
+	src, err := os.ReadFile("../../cmd/server/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := api.Spec()["paths"].(map[string]interface{})
+
+	routes := regexp.MustCompile(`http\.HandleFunc\("([^"]+)"`).FindAllStringSubmatch(string(src), -1)
+	if len(routes) == 0 {
+		t.Fatal("no routes found in main.go")
+	}
+	for _, m := range routes {
+		route, found := m[1], false
+		for path := range paths {
+			// Subtree routes such as /articles/ serve parameterized paths
+			if path == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(path, route) && strings.Contains(path, "{")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("route %s is not described in the OpenAPI spec", route)
+		}
+	}
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(api.SpecJSON(), &doc); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("expected OpenAPI 3.0.3, got %v", doc["openapi"])
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"ChatRequest", "ChatResponse", "APIError", "ArticlePage", "IngestStatus"} {
+		if schemas[name] == nil {
+			t.Errorf("expected component schema %s", name)
+		}
+	}
+
+	refs := regexp.MustCompile(`"\$ref": "#/components/schemas/(\w+)"`).FindAllStringSubmatch(string(api.SpecJSON()), -1)
+	for _, m := range refs {
+		if schemas[m[1]] == nil {
+			t.Errorf("unresolved reference to %s", m[1])
+		}
+	}
+}
+
+func TestOpenAPIHandlerServesSpec(t *testing.T) {
+	rec := httptest.NewRecorder()
+	api.Handler()(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	if rec.Code != 200 || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected 200 JSON, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !bytes.Equal(rec.Body.Bytes(), api.SpecJSON()) {
+		t.Error("handler body differs from SpecJSON")
+	}
+}
+
+func TestClientDecodesTypedResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/articles":
+			if r.URL.Query().Get("limit") != "5" || r.URL.Query().Get("order") != "asc" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(api.ArticlePage{
+				Articles: []domain.ArticleListItem{{ID: "a1", Title: "Rates"}},
+				Total:    1, Limit: 5,
+			})
+		case "/ingest":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(api.IngestAccepted{Status: "processing", ID: "job-1", StatusURL: "/ingest/status?id=job-1"})
+		default:
+			middleware.WriteError(w, r, 404, domain.ErrCodeArticleNotFound, "Article not found")
+		}
+	}))
+	defer srv.Close()
+
+	client := api.NewClient(srv.URL)
+	ctx := context.Background()
+
+	page, err := client.ListArticles(ctx, 5, 0, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 || len(page.Articles) != 1 || page.Articles[0].Title != "Rates" {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	accepted, err := client.Ingest(ctx, "https://example.com/a")
+	if err != nil || accepted == nil || accepted.ID != "job-1" {
+		t.Errorf("expected accepted ingest job-1, got %+v, %v", accepted, err)
+	}
+
+	summary, err := client.ArticleSummary(ctx, "missing", "")
+	var apiErr *api.Error
+	if summary != nil || !errors.As(err, &apiErr) {
+		t.Fatalf("expected *api.Error, got %v, %v", summary, err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Code != domain.ErrCodeArticleNotFound {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/classify"
+	"article-assistant/internal/domain"
+)
+
+func confidence(c float64) *float64 { return &c }
+
+func TestRouteKeepsConfidentPlans(t *testing.T) {
+	for _, c := range []*float64{nil, confidence(0.5), confidence(0.95)} {
+		plan := &domain.Plan{Command: "ask", Args: map[string]interface{}{}, Confidence: c}
+		routed := classify.Route(plan, "Summarize https://example.com/", 0.5)
+		if routed != plan || routed.Router != domain.RouterLLM {
+			t.Errorf("confidence %v: expected the planner's plan, got %+v", c, routed)
+		}
+	}
+}
+
+func TestRouteFallsBackToClassifier(t *testing.T) {
+	plan := &domain.Plan{Command: "ask", Args: map[string]interface{}{}, Confidence: confidence(0.2)}
+	routed := classify.Route(plan, "Give me a summary of https://example.com/news/1.", 0.5)
+	if routed.Command != "summary" || routed.Router != domain.RouterClassifier {
+		t.Fatalf("expected a classifier summary plan, got %+v", routed)
+	}
+	urls, ok := routed.Args["urls"].([]interface{})
+	if !ok || len(urls) != 1 || urls[0] != "https://example.com/news/1" {
+		t.Errorf("expected the query URL without trailing punctuation, got %#v", routed.Args["urls"])
+	}
+	if routed.Confidence == nil || *routed.Confidence != 0.2 {
+		t.Errorf("expected the planner's confidence to be kept, got %v", routed.Confidence)
+	}
+
+	routed = classify.Route(&domain.Plan{Command: "ask", Confidence: confidence(0.1)}, "What articles discuss economic trends?", 0.5)
+	if routed.Command != "filter_by_specific_topic" || routed.Args["filter"] != "economic trends" {
+		t.Errorf("expected a topic search for economic trends, got %+v", routed)
+	}
+}
+
+func TestRouteKeepsPlanWhenClassifierDoesNotKnow(t *testing.T) {
+	plan := &domain.Plan{Command: "ask", Args: map[string]interface{}{}, Confidence: confidence(0.1)}
+	routed := classify.Route(plan, "Who won the match?", 0.5)
+	if routed != plan || routed.Router != domain.RouterLLM {
+		t.Errorf("expected the planner's plan, got %+v", routed)
+	}
+}
+
+func TestFastPlanMatchesPlainURLQueries(t *testing.T) {
+	cases := []struct {
+		query   string
+		command string
+		urls    []string
+	}{
+		{"Summary of https://example.com/", "summary", []string{"https://example.com/"}},
+		{"summarize https://Example.com/News/1?id=2.", "summary", []string{"https://Example.com/News/1?id=2"}},
+		{"What are the main keywords of https://example.com/?", "keywords_or_topics", []string{"https://example.com/"}},
+		{"Is https://example.com/ positive or negative?", "get_sentiment", []string{"https://example.com/"}},
+		{"Compare https://a.com/1, https://b.com/2 and https://c.com/3", "compare_articles", []string{"https://a.com/1", "https://b.com/2", "https://c.com/3"}},
+		{"Compare the tone of https://a.com/1 and https://b.com/2", "ton_key_differences", []string{"https://a.com/1", "https://b.com/2"}},
+	}
+	for _, c := range cases {
+		plan := classify.FastPlan(c.query)
+		if plan == nil {
+			t.Errorf("%q: expected a fast-path plan", c.query)
+			continue
+		}
+		if plan.Command != c.command || plan.Router != domain.RouterFastPath {
+			t.Errorf("%q: expected %s from the fast path, got %+v", c.query, c.command, plan)
+		}
+		urls, _ := plan.Args["urls"].([]interface{})
+		if len(urls) != len(c.urls) {
+			t.Errorf("%q: expected urls %v, got %#v", c.query, c.urls, plan.Args["urls"])
+			continue
+		}
+		for i, u := range c.urls {
+			if urls[i] != u {
+				t.Errorf("%q: expected url %s, got %v", c.query, u, urls[i])
+			}
+		}
+	}
+}
+
+func TestFastPlanLeavesOtherQueriesToThePlanner(t *testing.T) {
+	for _, query := range []string{
+		"Summarize https://example.com/ in Spanish",
+		"Give me a 3-bullet summary of https://example.com/",
+		"Compare https://a.com/1",
+		"What articles discuss AI?",
+		"Why did the EU delay the trade deal in https://example.com/?",
+	} {
+		if plan := classify.FastPlan(query); plan != nil {
+			t.Errorf("%q: expected no fast-path plan, got %+v", query, plan)
+		}
+	}
+}
+
+func TestRouteKeepsFastPathPlans(t *testing.T) {
+	plan := classify.FastPlan("Summary of https://example.com/")
+	if routed := classify.Route(plan, "Summary of https://example.com/", 0.5); routed != plan || routed.Router != domain.RouterFastPath {
+		t.Errorf("expected the fast-path plan unchanged, got %+v", routed)
+	}
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"article-assistant/internal/ingest"
+)
+
+func TestPoliteHonorsRobotsTxt(t *testing.T) {
+	var robotsFetches, pageFetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&robotsFetches, 1)
+			fmt.Fprint(w, "User-agent: *\nDisallow: /\n\nUser-agent: ArticleAssistant\nDisallow: /private\nAllow: /private/press\nDisallow: /*.pdf$\n")
+			return
+		}
+		atomic.AddInt32(&pageFetches, 1)
+		fmt.Fprint(w, "<html><title>ok</title></html>")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: ingest.Polite(nil, ingest.PolitenessOptions{CrawlDelay: -1})}
+	cases := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/news/story", true},
+		{"/private/notes", false},
+		{"/private/press/release", true},
+		{"/files/report.pdf", false},
+		{"/files/report.pdf?download=1", true},
+	}
+	for _, c := range cases {
+		resp, err := client.Get(server.URL + c.path)
+		if c.allowed {
+			if err != nil {
+				t.Errorf("%s: expected fetch to be allowed, got %v", c.path, err)
+				continue
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else if !errors.Is(err, ingest.ErrDisallowed) {
+			t.Errorf("%s: expected ErrDisallowed, got %v", c.path, err)
+		}
+	}
+	if robotsFetches != 1 {
+		t.Errorf("expected robots.txt to be fetched once and cached, got %d fetches", robotsFetches)
+	}
+	if pageFetches != 3 {
+		t.Errorf("expected only allowed pages to be requested, got %d", pageFetches)
+	}
+
+	// Hosts on the override list skip robots.txt
+	host, _ := url.Parse(server.URL)
+	client = &http.Client{Transport: ingest.Polite(nil, ingest.PolitenessOptions{Overrides: []string{host.Hostname()}})}
+	resp, err := client.Get(server.URL + "/private/notes")
+	if err != nil {
+		t.Fatalf("expected override to allow fetch, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestPoliteSpacesRequestsPerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	delay := 50 * time.Millisecond
+	client := &http.Client{Transport: ingest.Polite(nil, ingest.PolitenessOptions{CrawlDelay: delay})}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL + "/page")
+		if err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 2*delay {
+		t.Errorf("expected requests to be spaced by %v, three took %v", delay, elapsed)
+	}
+}
@@ -0,0 +1,25 @@
+package unit
+
+import (
+	"database/sql"
+	"testing"
+
+	"article-assistant/internal/repository"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestPoolConfigApply(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cfg := repository.DefaultPoolConfig()
+	cfg.MaxOpenConns = 3
+	cfg.Apply(db)
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("expected 3 max open connections, got %d", got)
+	}
+}
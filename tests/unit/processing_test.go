@@ -0,0 +1,233 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/processing"
+)
+
+// scriptedIngester returns queued errors in order, then succeeds
+type scriptedIngester struct {
+	mu    sync.Mutex
+	errs  []error
+	calls int
+	delay time.Duration
+}
+
+func (s *scriptedIngester) IngestURL(ctx context.Context, url string) error {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func newTestFacade(ing processing.Ingester) *processing.Facade {
+	f := processing.NewFacade(ing)
+	f.BaseBackoff = time.Millisecond
+	return f
+}
+
+func TestFacadeRetriesTransientErrors(t *testing.T) {
+	ing := &scriptedIngester{errs: []error{
+		errors.New("error, status code: 429, message: rate limit"),
+		errors.New("read tcp: connection reset by peer"),
+	}}
+	f := newTestFacade(ing)
+
+	status, finished := f.AddNewArticle(context.Background(), "https://example.com/a", time.Second)
+	if !finished {
+		t.Fatal("expected processing to finish within wait")
+	}
+	if status.State != processing.StatusComplete || status.Attempts != 3 {
+		t.Errorf("expected complete after 3 attempts, got %+v", status)
+	}
+}
+
+func TestFacadeFailsFastOnPermanentError(t *testing.T) {
+	ing := &scriptedIngester{errs: []error{errors.New("failed to summarize: invalid request")}}
+	f := newTestFacade(ing)
+
+	status, finished := f.AddNewArticle(context.Background(), "https://example.com/b", time.Second)
+	if !finished || status.State != processing.StatusFailed {
+		t.Fatalf("expected failed status, got %+v (finished=%v)", status, finished)
+	}
+	if status.Attempts != 1 || status.Error == "" {
+		t.Errorf("expected single attempt with reason, got %+v", status)
+	}
+}
+
+func TestFacadeReturnsPendingStatusAfterThreshold(t *testing.T) {
+	ing := &scriptedIngester{delay: 50 * time.Millisecond}
+	f := newTestFacade(ing)
+
+	status, finished := f.AddNewArticle(context.Background(), "https://example.com/c", time.Millisecond)
+	if finished || status.State != processing.StatusProcessing {
+		t.Fatalf("expected pending status, got %+v (finished=%v)", status, finished)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if st, ok := f.Status(status.ID); ok && st.State == processing.StatusComplete {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expected status to become complete")
+}
+
+func TestFacadeEnqueueProcessesInBackground(t *testing.T) {
+	ing := &scriptedIngester{delay: 10 * time.Millisecond}
+	f := newTestFacade(ing)
+
+	var ids []string
+	for _, url := range []string{"https://example.com/d", "https://example.com/e", "https://example.com/f"} {
+		st, err := f.Enqueue(context.Background(), url)
+		if err != nil || st.State != processing.StatusProcessing {
+			t.Fatalf("expected processing state on enqueue, got %+v (err=%v)", st, err)
+		}
+		ids = append(ids, st.ID)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for _, id := range ids {
+		for {
+			if st, ok := f.Status(id); ok && st.State == processing.StatusComplete {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %s to complete", id)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
+
+// stagedEnricher fetches at once and enriches once release is closed, failing with the queued
+// enrichment errors first
+type stagedEnricher struct {
+	scriptedIngester
+	release    chan struct{}
+	enrichErrs []error
+	fetches    int
+}
+
+func (e *stagedEnricher) FetchURL(ctx context.Context, url string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fetches++
+	return true, nil
+}
+
+func (e *stagedEnricher) EnrichURL(ctx context.Context, url string) error {
+	<-e.release
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.enrichErrs) == 0 {
+		return nil
+	}
+	err := e.enrichErrs[0]
+	e.enrichErrs = e.enrichErrs[1:]
+	return err
+}
+
+func TestFacadeFetchArticleReturnsBeforeEnrichment(t *testing.T) {
+	ing := &stagedEnricher{release: make(chan struct{}), enrichErrs: []error{errors.New("error, status code: 503, message: overloaded")}}
+	f := newTestFacade(ing)
+
+	status, fetched := f.FetchArticle(context.Background(), "https://example.com/g", time.Second)
+	if !fetched || status.State != processing.StatusProcessing || status.Phase != processing.PhaseEnrich {
+		t.Fatalf("expected processing in the enrich phase once fetched, got %+v (fetched=%v)", status, fetched)
+	}
+	close(ing.release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		st, _ := f.Status(status.ID)
+		if st.State == processing.StatusComplete {
+			if st.Attempts != 1 || st.EnrichAttempts != 2 || ing.fetches != 1 {
+				t.Errorf("expected enrichment retried without refetching, got %+v after %d fetches", st, ing.fetches)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected enrichment to complete, got %+v", st)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFacadeResumeEnrichment(t *testing.T) {
+	ing := &stagedEnricher{release: make(chan struct{})}
+	close(ing.release)
+	f := newTestFacade(ing)
+	finished := make(chan processing.Status, 1)
+	f.OnFinish(func(ctx context.Context, st processing.Status) { finished <- st })
+
+	if err := f.ResumeEnrichment(context.Background(), []string{"https://example.com/h"}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	select {
+	case st := <-finished:
+		if st.State != processing.StatusComplete || st.Phase != processing.PhaseEnrich || st.EnrichAttempts != 1 || ing.fetches != 0 {
+			t.Errorf("expected enrichment to complete without fetching, got %+v after %d fetches", st, ing.fetches)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected resumed enrichment to finish")
+	}
+}
+
+// storedURLs reports a fixed set of URLs as already stored
+type storedURLs map[string]bool
+
+func (s storedURLs) GetExistingURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	return s, nil
+}
+
+func TestAutoIngesterIngestsOnlyMissingURLs(t *testing.T) {
+	ing := &scriptedIngester{}
+	a := processing.NewAutoIngester(newTestFacade(ing), storedURLs{"https://example.com/stored": true})
+
+	statuses, err := a.Ensure(context.Background(), []string{
+		"https://example.com/stored", "https://example.com/new", "https://example.com/new", "not-a-url",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].URL != "https://example.com/new" || statuses[0].State != processing.StatusComplete {
+		t.Errorf("expected only the new URL ingested, got %+v", statuses)
+	}
+	if ing.calls != 1 {
+		t.Errorf("expected 1 ingest call, got %d", ing.calls)
+	}
+	if msg := processing.Unfinished(statuses); msg != "" {
+		t.Errorf("expected no unfinished message, got %q", msg)
+	}
+}
+
+func TestAutoIngesterReportsUnfinished(t *testing.T) {
+	ing := &scriptedIngester{delay: 200 * time.Millisecond}
+	a := processing.NewAutoIngester(newTestFacade(ing), storedURLs{})
+	a.Wait = 10 * time.Millisecond
+
+	statuses, err := a.Ensure(context.Background(), []string{"https://example.com/slow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].State != processing.StatusProcessing {
+		t.Fatalf("expected the URL still processing, got %+v", statuses)
+	}
+	if msg := processing.Unfinished(statuses); !strings.Contains(msg, "still being ingested") {
+		t.Errorf("unexpected unfinished message: %q", msg)
+	}
+}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"article-assistant/internal/llm"
+	"article-assistant/internal/prompts"
+)
+
+func TestPlannerPromptHasExamplesForEveryCommand(t *testing.T) {
+	loader := prompts.EmbeddedLoader()
+	versions, err := loader.Versions(prompts.Planner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range versions {
+		tmpl, err := loader.Load(prompts.Planner, version)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		described := map[string]bool{}
+		for _, cmd := range tmpl.Commands {
+			described[cmd.Name] = true
+			if len(cmd.Examples) == 0 {
+				t.Errorf("%s: command %s has no examples", version, cmd.Name)
+			}
+			for _, ex := range cmd.Examples {
+				plan, err := llm.ParsePlanCall(ex.Plan)
+				if err != nil {
+					t.Errorf("%s: example %q: %v", version, ex.Query, err)
+				} else if plan.Command != cmd.Name {
+					t.Errorf("%s: example %q plans %s under command %s", version, ex.Query, plan.Command, cmd.Name)
+				}
+			}
+		}
+		for _, name := range llm.PlanCommands {
+			if !described[name] {
+				t.Errorf("%s: command %s is not described", version, name)
+			}
+		}
+	}
+}
+
+func TestFactoryRendersPlannerPrompt(t *testing.T) {
+	prompt, err := prompts.Default().Planner(context.Background(), "Summary of https://example.com/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(prompt, "Query: Summary of https://example.com/a/") {
+		t.Errorf("expected the query at the end, got %q", prompt[max(0, len(prompt)-80):])
+	}
+	for _, want := range []string{
+		"- ask: Answer an open-ended factual question",
+		`- "Top entities" → {"command": "get_top_entities", "args": {}}`,
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q", want)
+		}
+	}
+}
+
+func TestLoaderPinsAndOrdersVersions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"planner/v2.yaml":  {Data: []byte("text: 'v2 {{.Query}}'")},
+		"planner/v10.yaml": {Data: []byte("text: 'v10 {{.Query}}'")},
+		"planner/README":   {Data: []byte("not a template")},
+	}
+	loader := prompts.NewLoader(fsys)
+	ctx := context.Background()
+
+	versions, err := loader.Versions(prompts.Planner)
+	if err != nil || strings.Join(versions, ",") != "v2,v10" {
+		t.Fatalf("expected v2,v10, got %v, %v", versions, err)
+	}
+
+	latest, _ := prompts.NewFactory(loader, nil)
+	if got, _ := latest.Planner(ctx, "q"); got != "v10 q" || latest.PlannerTemplate().Version != "v10" {
+		t.Errorf("expected the latest version, got %q", got)
+	}
+	pinned, _ := prompts.NewFactory(loader, map[string]string{prompts.Planner: "v2"})
+	if got, _ := pinned.Planner(ctx, "q"); got != "v2 q" {
+		t.Errorf("expected the pinned version, got %q", got)
+	}
+	if got, _ := pinned.Planner(prompts.WithVersion(ctx, prompts.Planner, "v10"), "q"); got != "v10 q" {
+		t.Errorf("expected the version from the context, got %q", got)
+	}
+	if _, err := prompts.NewFactory(loader, map[string]string{prompts.Planner: "v3"}); err == nil {
+		t.Error("expected an error for a missing version")
+	}
+}
+
+func TestChoosePlannerPinsAndSplits(t *testing.T) {
+	fsys := fstest.MapFS{
+		"planner/v1.yaml": {Data: []byte("text: 'v1'")},
+		"planner/v2.yaml": {Data: []byte("text: 'v2'")},
+	}
+	factory, err := prompts.NewFactory(prompts.NewLoader(fsys), map[string]string{prompts.Planner: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := factory.ChoosePlanner("", ""); v != "v1" {
+		t.Errorf("expected the default without a split, got %s", v)
+	}
+	if _, err := factory.ChoosePlanner("v9", ""); err == nil {
+		t.Error("expected an error for an unknown pinned version")
+	}
+
+	weights, err := prompts.ParseWeights("v1=1, v2=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := factory.SplitPlanner(weights); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for range 2000 {
+		v, _ := factory.ChoosePlanner("", "")
+		counts[v]++
+	}
+	if counts["v1"] < 300 || counts["v2"] < 1300 {
+		t.Errorf("expected roughly a 1:3 split, got %v", counts)
+	}
+	first, _ := factory.ChoosePlanner("", "session-1")
+	for range 20 {
+		if v, _ := factory.ChoosePlanner("", "session-1"); v != first {
+			t.Fatalf("expected a session to keep version %s, got %s", first, v)
+		}
+	}
+	if v, _ := factory.ChoosePlanner("v1", "session-1"); v != "v1" {
+		t.Errorf("expected a pinned version to override the split, got %s", v)
+	}
+
+	for _, bad := range []string{"v1", "v1=x", "=3"} {
+		if _, err := prompts.ParseWeights(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+	if err := factory.SplitPlanner(map[string]int{"v3": 1}); err == nil {
+		t.Error("expected a split with an unknown version to be rejected")
+	}
+}
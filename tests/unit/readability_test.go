@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"article-assistant/internal/ingest"
+)
+
+func TestExtractArticleTextKeepsBodyDropsBoilerplate(t *testing.T) {
+	raw, err := os.ReadFile("testdata/news_page.html")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	text := ingest.ExtractArticleText(string(raw))
+
+	for _, want := range []string{
+		"raised its benchmark interest rate by a quarter point",
+		"two members argued for a pause",
+		"at least one more increase",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected body text %q in:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"Home", "mattress", "penguins", "Great article", "Copyright", "Markets slide"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("boilerplate %q should be removed:\n%s", unwanted, text)
+		}
+	}
+	if strings.Count(text, "\n\n") < 2 {
+		t.Errorf("expected paragraphs separated by blank lines, got:\n%s", text)
+	}
+}
+
+func TestExtractArticleTextFallsBackForShortPages(t *testing.T) {
+	got := ingest.ExtractArticleText("<p>Hello <strong>world</strong>!</p>")
+	if got != "Hello world!" {
+		t.Errorf("expected StripHTMLBasic fallback, got %q", got)
+	}
+}
+
+func TestTimeTagExtractorReadsPublishedDate(t *testing.T) {
+	raw, err := os.ReadFile("testdata/news_page.html")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	meta := ingest.ExtractMetadata(string(raw), ingest.DefaultMetadataExtractors())
+	want := time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC)
+	if meta.PublishedAt == nil || !meta.PublishedAt.Equal(want) {
+		t.Errorf("expected published date %v, got %v", want, meta.PublishedAt)
+	}
+	if meta.Title != "Central Bank Raises Rates Again" {
+		t.Errorf("expected og:title, got %q", meta.Title)
+	}
+}
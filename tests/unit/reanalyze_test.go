@@ -0,0 +1,189 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+// flakySemanticsLLM fails extraction for summaries containing "fail"
+type flakySemanticsLLM struct {
+	*llm.MockClient
+}
+
+func (f *flakySemanticsLLM) ExtractAllSemantics(ctx context.Context, text string) (*domain.SemanticAnalysis, error) {
+	if strings.Contains(text, "fail") {
+		return nil, errors.New("extraction failed")
+	}
+	return f.MockClient.ExtractAllSemantics(ctx, text)
+}
+
+func reanalysisCorpus(t *testing.T) *repository.MemoryStore {
+	t.Helper()
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{ID: "a", URL: "https://example.com/a", Summary: "A", Sentiment: "neutral"},
+		{ID: "b", URL: "https://example.com/b", Summary: "B", AnalysisVersion: "v0"},
+		{ID: "c", URL: "https://example.com/c", Summary: "C", AnalysisVersion: llm.SemanticsVersion},
+		{ID: "d", URL: "https://example.com/d", Summary: "D", AnalysisVersion: domain.AnalysisSupplied, Sentiment: "negative"},
+		{ID: "e", URL: "https://example.com/e", Status: domain.ArticlePending},
+		{ID: "f", URL: "https://example.com/f", Summary: "This one will fail", AnalysisVersion: "v0"},
+	} {
+		if err := store.UpsertArticle(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestReanalyzerUpdatesOutdatedArticles(t *testing.T) {
+	ctx := context.Background()
+	store := reanalysisCorpus(t)
+	r := &ingest.Reanalyzer{Service: &ingest.Service{Repo: store, LLM: &flakySemanticsLLM{llm.NewMockClient()}}, Batch: 2}
+
+	pending, counts, err := r.Pending(ctx)
+	if err != nil || pending != 3 || len(counts) != 4 {
+		t.Fatalf("Pending() = %d, %+v, %v; want 3 outdated articles among four versions", pending, counts, err)
+	}
+
+	var reports []ingest.ReanalyzeProgress
+	final, err := r.Run(ctx, func(p ingest.ReanalyzeProgress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if final.Total != 3 || final.Done != 2 || final.Failed != 1 || final.Running || final.FinishedAt == nil {
+		t.Errorf("unexpected final progress %+v", final)
+	}
+	if len(reports) < 3 || !reports[0].Running || reports[len(reports)-1].Running {
+		t.Errorf("expected progress from the start, after each batch and at the end, got %+v", reports)
+	}
+
+	a, _ := store.GetArticleByURL(ctx, "https://example.com/a")
+	if a.AnalysisVersion != llm.SemanticsVersion || a.Sentiment != "positive" || a.Tone != "professional" || len(a.Entities) == 0 || len(a.Topics) == 0 {
+		t.Errorf("expected a to carry the new analysis, got %+v", a)
+	}
+	if d, _ := store.GetArticleByURL(ctx, "https://example.com/d"); d.Sentiment != "negative" {
+		t.Errorf("expected the supplied analysis to be kept, got %+v", d)
+	}
+	if f, _ := store.GetArticleByURL(ctx, "https://example.com/f"); f.AnalysisVersion != "v0" {
+		t.Errorf("expected the failed article to keep its version for the next run, got %q", f.AnalysisVersion)
+	}
+	if pending, _, _ := r.Pending(ctx); pending != 1 {
+		t.Errorf("expected only the failed article to remain, got %d", pending)
+	}
+}
+
+func TestReanalyzerRateLimits(t *testing.T) {
+	store := reanalysisCorpus(t)
+	r := &ingest.Reanalyzer{Service: &ingest.Service{Repo: store, LLM: llm.NewMockClient()}, Rate: 50}
+
+	start := time.Now()
+	final, err := r.Run(context.Background(), nil)
+	if err != nil || final.Done != 3 {
+		t.Fatalf("Run() = %+v, %v", final, err)
+	}
+	// Three articles at 50 per second wait two 20ms intervals
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected the rate limit to space out articles, took %v", elapsed)
+	}
+}
+
+func TestReanalyzeJobsRunOncePerTenant(t *testing.T) {
+	store := reanalysisCorpus(t)
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	finished := make(chan struct{})
+	jobs := &ingest.ReanalyzeJobs{
+		Go: func(fn func(ctx context.Context)) bool {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-release
+				fn(context.Background())
+			}()
+			return true
+		},
+		OnFinish: func(context.Context) { close(finished) },
+	}
+	r := &ingest.Reanalyzer{Service: &ingest.Service{Repo: store, LLM: llm.NewMockClient()}}
+
+	if p, ok := jobs.Start(context.Background(), r); !ok || !p.Running {
+		t.Fatalf("expected the first run to start, got %+v", p)
+	}
+	if p, ok := jobs.Start(context.Background(), r); ok || !p.Running {
+		t.Errorf("expected a second run to be refused while the first runs, got %+v, %v", p, ok)
+	}
+
+	close(release)
+	wg.Wait()
+	<-finished
+	p, ok := jobs.Progress("default")
+	if !ok || p.Running || p.Done != 3 {
+		t.Errorf("expected the finished run's progress, got %+v", p)
+	}
+	if _, ok := jobs.Start(context.Background(), r); !ok {
+		t.Error("expected a new run to start once the last one finished")
+	}
+}
+
+func TestImportRecordsAnalysisVersion(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	// The mock embeds every summary alike; keep both articles instead of linking duplicates
+	svc := &ingest.Service{Repo: store, LLM: llm.NewMockClient(), DuplicateThreshold: 2,
+		SummaryModel: "gpt-4o-mini", AnalysisModel: "gpt-4-turbo"}
+
+	if _, err := svc.Import(ctx, ingest.ImportedArticle{URL: "https://example.com/x", Title: "X", Text: "Body of x."}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Import(ctx, ingest.ImportedArticle{URL: "https://example.com/y", Title: "Y", Text: "Body of y.",
+		Summary: "Y in short.", Analysis: &domain.SemanticAnalysis{Sentiment: "negative", SentimentScore: 0.2}}); err != nil {
+		t.Fatal(err)
+	}
+	x, _ := store.GetArticleByURL(ctx, "https://example.com/x")
+	if x.AnalysisVersion != llm.SemanticsVersion || x.AnalysisModel != "gpt-4-turbo" {
+		t.Errorf("expected the extracted analysis to record %s and its model, got %q, %q", llm.SemanticsVersion, x.AnalysisVersion, x.AnalysisModel)
+	}
+	if x.SummaryVersion != llm.SummaryVersion || x.SummaryModel != "gpt-4o-mini" {
+		t.Errorf("expected the generated summary to record %s and its model, got %q, %q", llm.SummaryVersion, x.SummaryVersion, x.SummaryModel)
+	}
+	y, _ := store.GetArticleByURL(ctx, "https://example.com/y")
+	if y.AnalysisVersion != domain.AnalysisSupplied || y.SummaryVersion != domain.AnalysisSupplied || y.AnalysisModel != "" || y.SummaryModel != "" {
+		t.Errorf("expected the supplied analysis and summary to be marked without a model, got %+v", y)
+	}
+}
+
+func TestListArticlesFiltersByAnalysisVersion(t *testing.T) {
+	ctx := context.Background()
+	store := reanalysisCorpus(t)
+	r := &ingest.Reanalyzer{Service: &ingest.Service{Repo: store, LLM: llm.NewMockClient(), AnalysisModel: "gpt-4-turbo"}}
+
+	items, total, err := store.ListArticles(ctx, domain.ArticleFilter{AnalysisVersion: "v0"}, 10, 0, "", true)
+	if err != nil || total != 2 || len(items) != 2 || items[0].AnalysisVersion != "v0" {
+		t.Fatalf("ListArticles(v0) = %+v, %d, %v; want b and f", items, total, err)
+	}
+	if _, err := r.Run(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, total, _ := store.ListArticles(ctx, domain.ArticleFilter{AnalysisVersion: "v0"}, 10, 0, "", true); total != 0 {
+		t.Errorf("expected no v0 articles after re-analysis, got %d", total)
+	}
+	items, total, _ = store.ListArticles(ctx, domain.ArticleFilter{AnalysisVersion: llm.SemanticsVersion}, 10, 0, "", true)
+	if total != 4 {
+		t.Fatalf("expected four articles at %s, got %d", llm.SemanticsVersion, total)
+	}
+	for _, it := range items {
+		if it.ID != "c" && it.AnalysisModel != "gpt-4-turbo" {
+			t.Errorf("expected re-analyzed %s to record its model, got %+v", it.ID, it)
+		}
+	}
+}
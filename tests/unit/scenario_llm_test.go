@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"article-assistant/internal/llm"
+)
+
+const defaultScenario = "../testdata/scenarios/default.yaml"
+
+func TestScenarioClientPlans(t *testing.T) {
+	client, err := llm.LoadScenario(defaultScenario)
+	if err != nil {
+		t.Fatalf("failed to load scenario: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		query   string
+		command string
+	}{
+		{"Give me a summary of https://example.com/article1", "summary"},
+		{"What are the key differences in tone between two sources?", "ton_key_differences"},
+		{"Compare these two articles", "compare_articles"},
+		{"Extract keywords from this article", "keywords_or_topics"},
+		{"What is the sentiment of this article?", "get_sentiment"},
+		{"Which article is more positive about AI regulation?", "most_positive_article_for_filter"},
+		{"What are the most commonly discussed entities?", "get_top_entities"},
+		{"What articles discuss economic trends?", "filter_by_specific_topic"},
+	}
+
+	for _, tt := range tests {
+		plan, err := client.PlanQuery(ctx, tt.query)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.query, err)
+			continue
+		}
+		if plan.Command != tt.command {
+			t.Errorf("%q: expected %s, got %s", tt.query, tt.command, plan.Command)
+		}
+		// URL lists must have the same shape the real planner produces after JSON decoding
+		if urls, ok := plan.Args["urls"]; ok {
+			if _, ok := urls.([]interface{}); !ok {
+				t.Errorf("%q: expected []interface{} urls, got %T", tt.query, urls)
+			}
+		}
+	}
+
+	if got := client.CallCount("PlanQuery"); got != len(tests) {
+		t.Errorf("expected %d PlanQuery calls, got %d", len(tests), got)
+	}
+	if _, err := client.PlanQuery(ctx, "something unscripted"); err == nil {
+		t.Error("expected error for unscripted query")
+	}
+}
+
+func TestScenarioClientDeterminism(t *testing.T) {
+	a, _ := llm.LoadScenario(defaultScenario)
+	b, _ := llm.LoadScenario(defaultScenario)
+	ctx := context.Background()
+
+	e1, _ := a.Embed(ctx, "economic trends")
+	e2, _ := b.Embed(ctx, "economic trends")
+	e3, _ := a.Embed(ctx, "AI regulation")
+	if !reflect.DeepEqual(e1, e2) {
+		t.Error("expected identical embeddings for the same seed and text")
+	}
+	if reflect.DeepEqual(e1, e3) {
+		t.Error("expected different embeddings for different text")
+	}
+
+	analysis, _ := a.ExtractAllSemantics(ctx, "a very negative story")
+	if analysis.Sentiment != "negative" || analysis.SentimentScore != 0.2 {
+		t.Errorf("unexpected analysis: %+v", analysis)
+	}
+	text, _ := a.GenerateText(ctx, "Which of these articles explicitly discuss AI?")
+	if text != "[0, 1, 2, 3, 4, 5, 6, 7, 8, 9]" {
+		t.Errorf("expected scripted relevant articles, got %q", text)
+	}
+}
+
+func TestScenarioClientExpectations(t *testing.T) {
+	client, err := llm.NewScenarioClient(llm.Scenario{
+		Texts:  []llm.TextRule{{Match: "", Response: "ok"}},
+		Expect: map[string]int{"GenerateText": 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.GenerateText(context.Background(), "one")
+	if err := client.Verify(); err == nil {
+		t.Error("expected unmet expectation")
+	}
+	client.GenerateText(context.Background(), "two")
+	if err := client.Verify(); err != nil {
+		t.Errorf("unexpected verify error: %v", err)
+	}
+}
+
+func TestNewClientScenarioProvider(t *testing.T) {
+	client, err := llm.NewClient(llm.Config{Provider: llm.ProviderScenario, Scenario: defaultScenario})
+	if err != nil {
+		t.Fatalf("failed to create scenario client: %v", err)
+	}
+	if _, ok := client.(*llm.ScenarioClient); !ok {
+		t.Fatalf("expected a *llm.ScenarioClient, got %T", client)
+	}
+
+	if _, err := llm.NewClient(llm.Config{Provider: llm.ProviderScenario}); err == nil {
+		t.Error("expected an error without a scenario file")
+	}
+}
@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestParseSentimentQuery(t *testing.T) {
+	sq, err := executor.ParseSentimentQuery(map[string]interface{}{})
+	if err != nil || sq.Negative || sq.Limit != 1 || sq.MinScore != nil || sq.MaxScore != nil {
+		t.Errorf("defaults = %+v, %v; want positive, limit 1, no range", sq, err)
+	}
+
+	sq, err = executor.ParseSentimentQuery(map[string]interface{}{"direction": "Critical", "max_score": 0.3})
+	if err != nil || !sq.Negative || sq.MaxScore == nil || *sq.MaxScore != 0.3 || sq.Limit != 5 {
+		t.Errorf("range query = %+v, %v; want negative, max 0.3, limit 5", sq, err)
+	}
+
+	sq, err = executor.ParseSentimentQuery(map[string]interface{}{"limit": float64(50)})
+	if err != nil || sq.Limit != 10 {
+		t.Errorf("limit = %d, %v; want capped at 10", sq.Limit, err)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"direction": "sideways"},
+		{"min_score": 1.5},
+		{"min_score": 0.8, "max_score": 0.2},
+	} {
+		if _, err := executor.ParseSentimentQuery(args); err == nil {
+			t.Errorf("ParseSentimentQuery(%v) succeeded; want error", args)
+		}
+	}
+}
+
+func TestFormatSentimentFilter(t *testing.T) {
+	hi := 0.3
+	got := executor.FormatSentimentFilter("Meta", domain.SentimentQuery{Negative: true, MaxScore: &hi, Limit: 5}, []domain.Article{
+		{Title: "Meta fined", URL: "https://example.com/a", Sentiment: "negative", SentimentScore: 0.1},
+		{Title: "Meta layoffs", URL: "https://example.com/b", Sentiment: "negative", SentimentScore: 0.2},
+	})
+	for _, want := range []string{"Most negative articles about 'Meta' with sentiment score at most 0.30", "1. Meta fined", "Sentiment: negative (0.20)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatSentimentFilter() missing %q in:\n%s", want, got)
+		}
+	}
+}
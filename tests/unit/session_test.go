@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"article-assistant/internal/session"
+)
+
+func TestSessionMemo(t *testing.T) {
+	memo := session.NewMemo()
+
+	if _, ok := memo.Get(session.KindEmbedding, "ai"); ok {
+		t.Fatal("expected miss on empty memo")
+	}
+
+	memo.Set(session.KindEmbedding, "ai", []float32{0.1, 0.2})
+	v, ok := memo.Get(session.KindEmbedding, "ai")
+	if !ok {
+		t.Fatal("expected hit after Set")
+	}
+	if emb := v.([]float32); len(emb) != 2 {
+		t.Errorf("expected 2-dim embedding, got %d", len(emb))
+	}
+
+	// Same key under a different kind must not collide
+	if _, ok := memo.Get(session.KindText, "ai"); ok {
+		t.Error("expected kinds to be isolated")
+	}
+
+	hits, misses := memo.Stats()
+	if hits != 1 || misses != 2 {
+		t.Errorf("expected 1 hit / 2 misses, got %d / %d", hits, misses)
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	store := session.NewStore(time.Millisecond)
+
+	a := store.Memo("a")
+	if store.Memo("a") != a {
+		t.Error("expected same memo for same session")
+	}
+	if store.Memo("b") == a {
+		t.Error("expected distinct memos for distinct sessions")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if n := store.Evict(); n != 2 {
+		t.Errorf("expected 2 evicted sessions, got %d", n)
+	}
+
+	ctx := session.NewContext(context.Background(), a)
+	if session.FromContext(ctx) != a {
+		t.Error("expected memo to round-trip through context")
+	}
+	if session.FromContext(context.Background()) != nil {
+		t.Error("expected nil memo for bare context")
+	}
+}
+
+func TestSessionMemoBounds(t *testing.T) {
+	memo := session.NewMemo()
+	memo.MaxEntries = 2
+	memo.Set(session.KindText, "a", "1")
+	time.Sleep(time.Millisecond)
+	memo.Set(session.KindText, "b", "2")
+	time.Sleep(time.Millisecond)
+	memo.Set(session.KindText, "c", "3")
+	if _, ok := memo.Get(session.KindText, "a"); ok || memo.Len() != 2 {
+		t.Errorf("expected the oldest entry dropped beyond MaxEntries, have %d entries", memo.Len())
+	}
+
+	memo.EntryTTL = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := memo.Get(session.KindText, "c"); ok {
+		t.Error("expected an expired entry to miss")
+	}
+}
+
+func TestSessionStoreInvalidate(t *testing.T) {
+	store := session.NewStore(time.Hour)
+	memo := store.Memo("tenant/chat")
+	asked := time.Date(2024, 5, 9, 8, 0, 0, 0, time.UTC)
+	memo.MarkAsked("ai", asked)
+	memo.Set(session.KindRetrieval, "ai", []string{"https://ai.com/a"})
+
+	store.Invalidate()
+	if memo.Len() != 0 {
+		t.Errorf("expected no memoized results after a corpus change, have %d", memo.Len())
+	}
+	if at, ok := memo.LastAsked("ai"); !ok || !at.Equal(asked) {
+		t.Errorf("expected the session's question history kept, got %v, %v", at, ok)
+	}
+}
@@ -0,0 +1,30 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/executor"
+)
+
+func TestNormalizeLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"", executor.LevelELI5, true},
+		{"ELI5", executor.LevelELI5, true},
+		{"simple", executor.LevelELI5, true},
+		{"high-school", executor.LevelHighSchool, true},
+		{"high_school", executor.LevelHighSchool, true},
+		{"Expert", executor.LevelExpert, true},
+		{"phd", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := executor.NormalizeLevel(tt.input)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("NormalizeLevel(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
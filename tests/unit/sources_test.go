@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+func TestFinalizeSourcesDedupesOrdersAndAnnotates(t *testing.T) {
+	plan := &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "AI", "urls": []interface{}{"https://example.com/c"}}}
+	resp := &domain.ChatResponse{Sources: []domain.Source{
+		{ID: "a", URL: "https://example.com/a", Score: 0.4},
+		{ID: "b", URL: "https://example.com/b", Score: 0.9},
+		{ID: "a", URL: "https://example.com/a", Score: 0.7},
+		{ID: "c", URL: "https://example.com/c"},
+	}}
+	executor.FinalizeSources(plan, resp)
+
+	want := []struct {
+		id     string
+		score  float64
+		reason string
+	}{
+		{"b", 0.9, domain.SourceMatchedTopic},
+		{"a", 0.7, domain.SourceMatchedTopic},
+		{"c", 0, domain.SourceRequested},
+	}
+	if len(resp.Sources) != len(want) {
+		t.Fatalf("expected %d sources, got %+v", len(want), resp.Sources)
+	}
+	for i, w := range want {
+		if s := resp.Sources[i]; s.ID != w.id || s.Score != w.score || s.Reason != w.reason {
+			t.Errorf("source %d = %+v, want %s scored %v for %s", i, s, w.id, w.score, w.reason)
+		}
+	}
+}
+
+func TestFinalizeSourcesKeepsCitedOrder(t *testing.T) {
+	plan := &domain.Plan{Command: "compare_articles"}
+	resp := &domain.ChatResponse{Sources: []domain.Source{
+		{ID: "a", URL: "https://example.com/a", Score: 0.1},
+		{ID: "b", URL: "https://example.com/b", Score: 0.9},
+		{ID: "a", URL: "https://example.com/a"},
+	}}
+	executor.FinalizeSources(plan, resp)
+	if len(resp.Sources) != 2 || resp.Sources[0].ID != "a" || resp.Sources[1].ID != "b" {
+		t.Fatalf("expected the cited order without duplicates, got %+v", resp.Sources)
+	}
+	for _, s := range resp.Sources {
+		if s.Reason != domain.SourceCompared {
+			t.Errorf("expected %s to be marked compared, got %q", s.ID, s.Reason)
+		}
+	}
+}
+
+func TestExecutorAnnotatesSources(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	store.UpsertArticle(ctx, &domain.Article{ID: "a", URL: "https://example.com/a", Title: "A", Tone: "critical"})
+
+	ex := executor.NewExecutorWithCommands(store, llm.NewMockClient())
+	resp, err := ex.Execute(ctx, &domain.Plan{Command: "filter_by_tone", Args: map[string]interface{}{"tone": "critical"}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(resp.Sources) != 1 || resp.Sources[0].Reason != domain.SourceMatchedTone {
+		t.Errorf("expected the article to be marked as matching the tone, got %+v", resp.Sources)
+	}
+}
+
+func TestRepairCitations(t *testing.T) {
+	identity := func(sources int) func(int) int {
+		return func(n int) int {
+			if n < 1 || n > sources {
+				return 0
+			}
+			return n
+		}
+	}
+	cases := []struct{ in, want string }{
+		{"Rates rose [1] and fell [2].", "Rates rose [1] and fell [2]."},
+		{"Both agree [1, 2].", "Both agree [1][2]."},
+		{"All three [1-3].", "All three [1][2][3]."},
+		{"Per [Source 2], prices fell.", "Per [2], prices fell."},
+		{"Repeated [1][1] and [1,1].", "Repeated [1] and [1]."},
+		{"Invented [7] and zero [0].", "Invented and zero."},
+		{"Mixed [2, 9].", "Mixed [2]."},
+	}
+	for _, c := range cases {
+		if got := executor.RepairCitations(c.in, identity(3)); got != c.want {
+			t.Errorf("RepairCitations(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestFinalizeSourcesRenumbersCitations(t *testing.T) {
+	plan := &domain.Plan{Command: "ask"}
+	resp := &domain.ChatResponse{
+		Answer: "Altman spoke [1], the policy changed [2], and he repeated it [3]. See also [4].",
+		Sources: []domain.Source{
+			{ID: "a", URL: "https://example.com/a"},
+			{ID: "b", URL: "https://example.com/b"},
+			{ID: "a", URL: "https://example.com/a"},
+		},
+	}
+	executor.FinalizeSources(plan, resp)
+	want := "Altman spoke [1], the policy changed [2], and he repeated it [1]. See also."
+	if resp.Answer != want || len(resp.Sources) != 2 {
+		t.Errorf("got %q with %d sources, want %q with 2", resp.Answer, len(resp.Sources), want)
+	}
+}
@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+)
+
+func TestParseSummaryOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want llm.SummaryOptions
+	}{
+		{"none", map[string]interface{}{}, llm.SummaryOptions{}},
+		{"bullet count implies bullets", map[string]interface{}{"bullets": float64(3)}, llm.SummaryOptions{Style: "bullets", Bullets: 3}},
+		{"alias", map[string]interface{}{"style": "One-Liner"}, llm.SummaryOptions{Style: "one_liner"}},
+		{"word limit", map[string]interface{}{"style": "executive", "max_words": float64(100)}, llm.SummaryOptions{Style: "executive", MaxWords: 100}},
+		{"tiny word limit raised", map[string]interface{}{"max_words": float64(2)}, llm.SummaryOptions{MaxWords: 10}},
+		{"bullet count capped", map[string]interface{}{"bullets": float64(50)}, llm.SummaryOptions{Style: "bullets", Bullets: 10}},
+	}
+	for _, tt := range tests {
+		got, err := executor.ParseSummaryOptions(tt.args)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: ParseSummaryOptions() = %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := executor.ParseSummaryOptions(map[string]interface{}{"style": "haiku"}); err == nil {
+		t.Error("expected error for unsupported style")
+	}
+}
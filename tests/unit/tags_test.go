@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	if got := domain.NormalizeTag("  Machine   Learning "); got != "machine learning" {
+		t.Errorf("expected a trimmed lowercase tag, got %q", got)
+	}
+}
+
+func TestTagsFilterArticles(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for _, a := range []*domain.Article{
+		{ID: "a", URL: "https://example.com/a", Title: "A"},
+		{ID: "b", URL: "https://example.com/b", Title: "B"},
+	} {
+		store.UpsertArticle(ctx, a)
+	}
+	store.AddArticleTags(ctx, "a", []string{"research", "later"})
+	store.AddArticleTags(ctx, "b", []string{"research"})
+
+	if n, _ := store.CountArticles(ctx, domain.ArticleFilter{Tag: "later"}); n != 1 {
+		t.Errorf("expected 1 article tagged later, got %d", n)
+	}
+	tags, _ := store.ListTags(ctx)
+	if len(tags) != 2 || tags[0] != (domain.TagCount{Tag: "research", Articles: 2}) {
+		t.Errorf("expected research first with 2 articles, got %+v", tags)
+	}
+
+	if removed, _ := store.RemoveArticleTag(ctx, "a", "later"); !removed {
+		t.Error("expected the tag to be removed")
+	}
+	if removed, _ := store.RemoveArticleTag(ctx, "a", "later"); removed {
+		t.Error("expected removing a missing tag to report false")
+	}
+	if got, _ := store.GetArticleTags(ctx, "a"); len(got) != 1 || got[0] != "research" {
+		t.Errorf("expected only research left, got %v", got)
+	}
+
+	store.DeleteArticleByURL(ctx, "https://example.com/b")
+	if n, _ := store.CountArticles(ctx, domain.ArticleFilter{Tag: "research"}); n != 1 {
+		t.Errorf("expected the deleted article's tags to go with it, got %d", n)
+	}
+}
+
+func TestArticleNotes(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	first := &domain.ArticleNote{ArticleID: "a", Text: "Check the figures"}
+	store.AddArticleNote(ctx, first)
+	store.AddArticleNote(ctx, &domain.ArticleNote{ArticleID: "a", Text: "Quote in paragraph 3"})
+
+	if first.ID == "" || first.CreatedAt.IsZero() {
+		t.Fatalf("expected an ID and creation time, got %+v", first)
+	}
+	if deleted, _ := store.DeleteArticleNote(ctx, "other", first.ID); deleted {
+		t.Error("expected a note to be deleted only through its own article")
+	}
+	store.DeleteArticleNote(ctx, "a", first.ID)
+	if notes, _ := store.ListArticleNotes(ctx, "a"); len(notes) != 1 || notes[0].Text != "Quote in paragraph 3" {
+		t.Errorf("expected one note left, got %+v", notes)
+	}
+}
+
+func TestSQLiteStorePersistsTagsAndNotes(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.db")
+	db, _ := sql.Open("sqlite", path)
+	store, err := repository.NewSQLiteStore(ctx, db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	article := &domain.Article{URL: "https://example.com/a", Title: "Tagged"}
+	store.UpsertArticle(ctx, article)
+	store.AddArticleTags(ctx, article.ID, []string{"research"})
+	store.AddArticleNote(ctx, &domain.ArticleNote{ArticleID: article.ID, Text: "Follow up"})
+	db.Close()
+
+	db, _ = sql.Open("sqlite", path)
+	defer db.Close()
+	if store, err = repository.NewSQLiteStore(ctx, db); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if urls, _ := store.GetArticleURLs(ctx, nil, domain.ArticleFilter{Tag: "research"}); len(urls) != 1 {
+		t.Errorf("expected the tag to survive reopen, got %v", urls)
+	}
+	if notes, _ := store.ListArticleNotes(ctx, article.ID); len(notes) != 1 || notes[0].Text != "Follow up" {
+		t.Errorf("expected the note to survive reopen, got %+v", notes)
+	}
+}
+
+func TestTagPlanArgFiltersCommands(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	store.UpsertArticle(ctx, &domain.Article{ID: "a", URL: "https://example.com/a", Title: "A", Tone: "critical"})
+	store.UpsertArticle(ctx, &domain.Article{ID: "b", URL: "https://example.com/b", Title: "B", Tone: "critical"})
+	store.AddArticleTags(ctx, "b", []string{"research"})
+
+	ex := executor.NewExecutorWithCommands(store, llm.NewMockClient())
+	resp, err := ex.Execute(ctx, &domain.Plan{Command: "filter_by_tone", Args: map[string]interface{}{"tone": "critical", "tag": "Research"}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(resp.Sources) != 1 || resp.Sources[0].URL != "https://example.com/b" {
+		t.Errorf("expected only the tagged article, got %+v", resp.Sources)
+	}
+}
@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"article-assistant/internal/analysis"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/ingest"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+// topicMatchLLM answers topic matching with fixed matches and counts the calls
+type topicMatchLLM struct {
+	*llm.MockClient
+	calls int
+}
+
+func (m *topicMatchLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	m.calls++
+	return "```json\n{\"matches\": [{\"topic\": \"large language models\", \"score\": 0.9}, {\"topic\": \"Markets\", \"score\": 0.2}]}\n```", nil
+}
+
+func TestImportMapsTopicsToTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	matcher := &topicMatchLLM{MockClient: llm.NewMockClient()}
+	svc := &ingest.Service{Repo: store, LLM: matcher, TopicMatcher: analysis.NewAnalysisService(matcher), DuplicateThreshold: -1}
+	imported := func(url string) ingest.ImportedArticle {
+		return ingest.ImportedArticle{URL: url, Title: "Model release", Text: "A new model was released.", Summary: "A new model.",
+			Analysis: &domain.SemanticAnalysis{Sentiment: "neutral", SentimentScore: 0.5, Topics: []domain.SemanticTopic{{Name: "LLMs"}}}}
+	}
+
+	// An empty taxonomy costs no LLM call
+	if _, err := svc.Import(ctx, imported("https://example.com/before")); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if matcher.calls != 0 {
+		t.Fatalf("expected no matching without a taxonomy, got %d calls", matcher.calls)
+	}
+
+	for _, topic := range []domain.TaxonomyTopic{
+		{ID: "ai", Name: "Artificial Intelligence"},
+		{ID: "llm", Name: "Large Language Models", ParentID: "ai"},
+		{ID: "markets", Name: "Markets"},
+	} {
+		if err := store.CreateTaxonomyTopic(ctx, &topic); err != nil {
+			t.Fatalf("create topic: %v", err)
+		}
+	}
+	if err := store.CreateTaxonomyTopic(ctx, &domain.TaxonomyTopic{ID: "ai", Name: "AI"}); !errors.Is(err, repository.ErrTopicExists) {
+		t.Errorf("expected a duplicate ID to be rejected, got %v", err)
+	}
+
+	if _, err := svc.Import(ctx, imported("https://example.com/after")); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	stored, _ := store.GetArticleByURL(ctx, "https://example.com/after")
+	if len(stored.TopicIDs) != 1 || stored.TopicIDs[0] != "llm" {
+		t.Fatalf("expected only the high-scoring match to be mapped, got %v", stored.TopicIDs)
+	}
+
+	// Filtering by a topic includes its subtopics
+	for id, want := range map[string]int{"ai": 1, "llm": 1, "markets": 0} {
+		urls, _ := store.GetArticleURLs(ctx, nil, domain.ArticleFilter{TopicID: id})
+		if len(urls) != want {
+			t.Errorf("topic %s: expected %d articles, got %v", id, want, urls)
+		}
+	}
+
+	if deleted, _ := store.DeleteTaxonomyTopic(ctx, "llm"); !deleted {
+		t.Fatal("expected the topic to be deleted")
+	}
+	stored, _ = store.GetArticleByURL(ctx, "https://example.com/after")
+	if len(stored.TopicIDs) != 0 {
+		t.Errorf("expected the deleted topic to be removed from articles, got %v", stored.TopicIDs)
+	}
+}
+
+func TestCheckTopicParent(t *testing.T) {
+	taxonomy := []domain.TaxonomyTopic{{ID: "ai"}, {ID: "llm", ParentID: "ai"}, {ID: "agents", ParentID: "llm"}}
+	if err := ingest.CheckTopicParent(taxonomy, "new", "llm"); err != nil {
+		t.Errorf("expected an existing parent to be accepted, got %v", err)
+	}
+	if err := ingest.CheckTopicParent(taxonomy, "new", "missing"); err == nil {
+		t.Error("expected an unknown parent to be rejected")
+	}
+	if err := ingest.CheckTopicParent(taxonomy, "ai", "agents"); err == nil {
+		t.Error("expected a cycle to be rejected")
+	}
+}
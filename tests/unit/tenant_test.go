@@ -0,0 +1,388 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"article-assistant/internal/cache"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/middleware"
+	"article-assistant/internal/processing"
+	"article-assistant/internal/repository"
+	"article-assistant/internal/tenant"
+	"article-assistant/internal/usage"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys, err := tenant.ParseKeys(" newsroom:k1, research:k2,research:k3 ")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if keys["k1"].Tenant != "newsroom" || keys["k2"].Tenant != "research" || keys["k3"].Tenant != "research" {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+	if keys["k1"].Role != tenant.RoleAdmin {
+		t.Errorf("expected keys without a role to be admin keys, got %v", keys["k1"].Role)
+	}
+	if ids := tenant.IDs(keys); len(ids) != 2 || ids[0] != "newsroom" || ids[1] != "research" {
+		t.Errorf("expected the sorted tenants, got %v", ids)
+	}
+	if ids := tenant.IDs(nil); len(ids) != 1 || ids[0] != tenant.Default {
+		t.Errorf("expected only the default tenant without keys, got %v", ids)
+	}
+
+	for _, bad := range []string{"newsroom", "newsroom:", "News Room:k1", "a:k1,b:k1", "a:k1:owner", "a::reader"} {
+		if _, err := tenant.ParseKeys(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	keys := map[string]tenant.Key{"k1": {Tenant: "newsroom", Role: tenant.RoleAdmin}, "k2": {Tenant: "research", Role: tenant.RoleAdmin}}
+	var seen string
+	handler := middleware.Tenant(keys, nil, []string{"/health"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = tenant.FromContext(r.Context())
+	}))
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		seen = ""
+		req := httptest.NewRequest("GET", path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve("/articles", nil); rec.Code != 401 || !strings.Contains(rec.Body.String(), domain.ErrCodeUnauthorized) {
+		t.Errorf("expected 401 without a key, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve("/articles", http.Header{"Authorization": {"Bearer nope"}}); rec.Code != 401 {
+		t.Errorf("expected 401 for an unknown key, got %d", rec.Code)
+	}
+	if rec := serve("/articles", http.Header{"Authorization": {"Bearer k1"}}); rec.Code != 200 || seen != "newsroom" {
+		t.Errorf("expected the bearer key's tenant, got %d %q", rec.Code, seen)
+	}
+	if rec := serve("/articles", http.Header{"X-Api-Key": {"k2"}}); rec.Code != 200 || seen != "research" {
+		t.Errorf("expected the X-API-Key tenant, got %d %q", rec.Code, seen)
+	}
+	if rec := serve("/health", nil); rec.Code != 200 {
+		t.Errorf("expected open paths to need no key, got %d", rec.Code)
+	}
+
+	// Without keys every request is the default tenant
+	open := middleware.Tenant(nil, nil, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = tenant.FromContext(r.Context())
+	}))
+	open.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/articles", nil))
+	if seen != tenant.Default {
+		t.Errorf("expected the default tenant, got %q", seen)
+	}
+}
+
+func TestTenantRateLimit(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	limiter := tenant.NewRateLimiter(2)
+	limiter.SetClock(func() time.Time { return now })
+	handler := middleware.Tenant(map[string]tenant.Key{"k1": {Tenant: "newsroom"}, "k2": {Tenant: "research"}}, limiter, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/articles", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := call("k1"); rec.Code != 200 {
+			t.Fatalf("request %d: expected 200, got %d", i, rec.Code)
+		}
+	}
+	rec := call("k1")
+	if rec.Code != 429 || rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("expected 429 with Retry-After 30, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	// Other tenants have their own allowance
+	if rec := call("k2"); rec.Code != 200 {
+		t.Errorf("expected another tenant to be allowed, got %d", rec.Code)
+	}
+	// Allowance refills over time
+	now = now.Add(30 * time.Second)
+	if rec := call("k1"); rec.Code != 200 {
+		t.Errorf("expected a refilled request to be allowed, got %d", rec.Code)
+	}
+
+	var metrics strings.Builder
+	if err := limiter.WritePrometheus(&metrics); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(metrics.String(), `tenant_requests_total{tenant="newsroom",result="rejected"} 1`) {
+		t.Errorf("expected the rejection to be counted:\n%s", metrics.String())
+	}
+	if tenant.NewRateLimiter(0) != nil {
+		t.Error("expected no limiter for a zero limit")
+	}
+}
+
+func TestTenantStoreIsolatesArticles(t *testing.T) {
+	newsroom, research := repository.NewMemoryStore(), repository.NewMemoryStore()
+	store := repository.ForTenants(map[string]repository.ArticleStore{"newsroom": newsroom, "research": research})
+	newsroomCtx := tenant.NewContext(context.Background(), "newsroom")
+	researchCtx := tenant.NewContext(context.Background(), "research")
+
+	if err := store.UpsertArticle(newsroomCtx, &domain.Article{ID: "a", URL: "https://example.com/a", Title: "A"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if a, _ := store.GetArticleByURL(newsroomCtx, "https://example.com/a"); a == nil {
+		t.Error("expected the article in its tenant's store")
+	}
+	if a, _ := store.GetArticleByURL(researchCtx, "https://example.com/a"); a != nil {
+		t.Error("expected another tenant not to see the article")
+	}
+	if n, _ := research.CountArticles(context.Background(), domain.ArticleFilter{}); n != 0 {
+		t.Errorf("expected nothing written to the other store, got %d", n)
+	}
+
+	if _, err := store.CountArticles(context.Background(), domain.ArticleFilter{}); err == nil {
+		t.Error("expected an error for a tenant with no store")
+	}
+	if single := repository.ForTenants(map[string]repository.ArticleStore{"newsroom": newsroom}); single != repository.ArticleStore(newsroom) {
+		t.Error("expected a single store to be used as is")
+	}
+}
+
+func TestChatCacheIsPerTenant(t *testing.T) {
+	backend := newMemoryBackend()
+	svc := cache.NewService(backend)
+	req := domain.ChatRequest{Query: "What is new?"}
+	newsroomCtx := tenant.NewContext(context.Background(), "newsroom")
+	researchCtx := tenant.NewContext(context.Background(), "research")
+
+	if err := svc.SetCachedResponse(newsroomCtx, req, &domain.ChatResponse{Answer: "newsroom answer"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetCachedResponse(researchCtx, req, &domain.ChatResponse{Answer: "research answer"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := svc.GetCachedResponse(researchCtx, req); resp == nil || resp.Answer != "research answer" {
+		t.Errorf("expected the tenant's own answer, got %+v", resp)
+	}
+	if resp, _ := svc.GetCachedResponse(context.Background(), req); resp != nil {
+		t.Errorf("expected no answer for the default tenant, got %+v", resp)
+	}
+
+	// Clearing one tenant's answers keeps the others'
+	if err := svc.InvalidateAll(newsroomCtx); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := svc.GetCachedResponse(newsroomCtx, req); resp != nil {
+		t.Error("expected the tenant's answers to be cleared")
+	}
+	if resp, _ := svc.GetCachedResponse(researchCtx, req); resp == nil {
+		t.Error("expected other tenants' answers to be kept")
+	}
+}
+
+func TestUsageIsPerTenant(t *testing.T) {
+	ledger := usage.NewLedger(30)
+	usage.Record(tenant.NewContext(context.Background(), "newsroom"), ledger, "gpt-4-turbo", 100, 50)
+	usage.Record(tenant.NewContext(context.Background(), "research"), ledger, "gpt-4-turbo", 10, 0)
+
+	if days := ledger.Daily("newsroom"); len(days) != 1 || days[0].Tokens != 150 {
+		t.Errorf("unexpected newsroom usage: %+v", days)
+	}
+	if days := ledger.Daily("research"); len(days) != 1 || days[0].Tokens != 10 {
+		t.Errorf("unexpected research usage: %+v", days)
+	}
+	if days := ledger.Daily(tenant.Default); len(days) != 0 {
+		t.Errorf("expected no default tenant usage, got %+v", days)
+	}
+
+	var metrics strings.Builder
+	if err := ledger.WritePrometheus(&metrics); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(metrics.String(), `tenant_llm_tokens_total{tenant="newsroom"} 150`) {
+		t.Errorf("expected per-tenant token totals:\n%s", metrics.String())
+	}
+}
+
+func TestIngestRunsAsRequestTenant(t *testing.T) {
+	ingester := &tenantIngester{}
+	facade := processing.NewFacade(ingester)
+	ctx := tenant.NewContext(context.Background(), "research")
+
+	st, done := facade.AddNewArticle(ctx, "https://example.com/a", 5*time.Second)
+	if !done || st.State != processing.StatusComplete {
+		t.Fatalf("expected the ingest to complete, got %+v", st)
+	}
+	if ingester.tenant != "research" || st.Tenant != "research" {
+		t.Errorf("expected the ingest to run as the request's tenant, got %q and %q", ingester.tenant, st.Tenant)
+	}
+}
+
+// tenantIngester records the tenant it ingests for
+type tenantIngester struct {
+	tenant string
+}
+
+func (i *tenantIngester) IngestURL(ctx context.Context, url string) error {
+	i.tenant = tenant.FromContext(ctx)
+	return nil
+}
+
+func TestParseKeyRoles(t *testing.T) {
+	keys, err := tenant.ParseKeys("newsroom:k1:reader,newsroom:k2:editor,newsroom:k3:admin")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if keys["k1"].Role != tenant.RoleReader || keys["k2"].Role != tenant.RoleEditor || keys["k3"].Role != tenant.RoleAdmin {
+		t.Errorf("unexpected roles: %+v", keys)
+	}
+	if !tenant.RoleEditor.Allows(tenant.RoleReader) || tenant.RoleEditor.Allows(tenant.RoleAdmin) {
+		t.Error("expected each role to allow only itself and lower roles")
+	}
+	if tenant.RoleFromContext(context.Background()) != tenant.RoleAdmin {
+		t.Error("expected requests without keys to be allowed everything")
+	}
+}
+
+func TestAuthorizeChecksRouteRole(t *testing.T) {
+	keys, err := tenant.ParseKeys("newsroom:r:reader,newsroom:e:editor,newsroom:a:admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server's policy: /admin needs admin, reads and chat need reader, other changes editor
+	policy := func(r *http.Request) tenant.Role {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/admin/"):
+			return tenant.RoleAdmin
+		case r.URL.Path == "/chat", r.Method == "GET":
+			return tenant.RoleReader
+		default:
+			return tenant.RoleEditor
+		}
+	}
+	handler := middleware.Tenant(keys, nil, nil, middleware.Authorize(policy, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	for _, tc := range []struct {
+		key, method, path string
+		want              int
+	}{
+		{"r", "POST", "/chat", 200},
+		{"r", "GET", "/articles", 200},
+		{"r", "POST", "/ingest", 403},
+		{"r", "DELETE", "/articles", 403},
+		{"e", "POST", "/ingest", 200},
+		{"e", "DELETE", "/articles", 200},
+		{"e", "GET", "/admin/failures", 403},
+		{"a", "GET", "/admin/failures", 200},
+		{"a", "POST", "/ingest", 200},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tc.key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s %s with %s key: expected %d, got %d", tc.method, tc.path, tc.key, tc.want, rec.Code)
+		}
+		if rec.Code == 403 && !strings.Contains(rec.Body.String(), domain.ErrCodeForbidden) {
+			t.Errorf("expected a FORBIDDEN error, got %s", rec.Body.String())
+		}
+	}
+}
+
+func TestOperatorRoutesNeedTheOperatorKey(t *testing.T) {
+	keys, err := tenant.ParseKeys("newsroom:a:admin,research:r:reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	serve := func(handler http.Handler, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/admin/chaos", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	handler := middleware.Operator("ops", keys, ok)
+	if rec := serve(handler, "ops"); rec.Code != 200 {
+		t.Errorf("expected the operator key to be accepted, got %d", rec.Code)
+	}
+	if rec := serve(handler, "a"); rec.Code != 403 || !strings.Contains(rec.Body.String(), domain.ErrCodeForbidden) {
+		t.Errorf("expected 403 for a tenant's admin key, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(handler, ""); rec.Code != 401 {
+		t.Errorf("expected 401 without a key, got %d", rec.Code)
+	}
+	if rec := serve(handler, "nope"); rec.Code != 401 {
+		t.Errorf("expected 401 for an unknown key, got %d", rec.Code)
+	}
+
+	// Tenant keys without an operator key close the routes; no keys at all leave them open
+	if rec := serve(middleware.Operator("", keys, ok), "a"); rec.Code != 403 {
+		t.Errorf("expected 403 without an operator key, got %d", rec.Code)
+	}
+	if rec := serve(middleware.Operator("", nil, ok), ""); rec.Code != 200 {
+		t.Errorf("expected open routes without any keys, got %d", rec.Code)
+	}
+}
+
+func TestTenantRateLimitClockIsSafeToSwap(t *testing.T) {
+	limiter := tenant.NewRateLimiter(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				limiter.Allow("newsroom")
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		limiter.SetClock(time.Now)
+	}
+	wg.Wait()
+}
+
+func TestIsOperatorNeedsTheOperatorKeyOnceKeysAreSet(t *testing.T) {
+	keys, err := tenant.ParseKeys("newsroom:a:admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := func(key string) *http.Request {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		return req
+	}
+
+	if !middleware.IsOperator("ops", keys, request("ops")) {
+		t.Error("expected the operator key to be recognised")
+	}
+	for _, key := range []string{"", "a", "nope"} {
+		if middleware.IsOperator("ops", keys, request(key)) {
+			t.Errorf("expected key %q not to count as the operator", key)
+		}
+		if middleware.IsOperator("", keys, request(key)) {
+			t.Errorf("expected key %q not to count as the operator without an operator key", key)
+		}
+	}
+	if !middleware.IsOperator("", nil, request("")) {
+		t.Error("expected every request to count as the operator without any keys")
+	}
+}
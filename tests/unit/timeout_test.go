@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"article-assistant/internal/middleware"
+)
+
+func TestTimeoutReturnsJSON504(t *testing.T) {
+	h := middleware.Timeout(20*time.Millisecond, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.Write([]byte("too late"))
+	})
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest("POST", "/chat", nil))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+		t.Errorf("expected JSON error body, got %q", rec.Body.String())
+	}
+}
+
+func TestTimeoutPassesThroughFastResponses(t *testing.T) {
+	h := middleware.Timeout(time.Second, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest("GET", "/health", nil))
+
+	if rec.Code != http.StatusAccepted || rec.Body.String() != `{"ok":true}` || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected response: %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
+
+func TestTimeoutKeepsStreamedResponse(t *testing.T) {
+	h := middleware.Timeout(20*time.Millisecond, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest("POST", "/chat", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Errorf("expected committed stream to be kept, got %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,16 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestFormatToneCounts(t *testing.T) {
+	got := executor.FormatToneCounts([]domain.ToneCount{{Tone: "critical", Articles: 3}, {Tone: "analytical", Articles: 1}})
+	want := "Tone of 4 articles:\n- critical: 3\n- analytical: 1"
+	if got != want {
+		t.Errorf("FormatToneCounts() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"article-assistant/internal/budget"
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+)
+
+// confirmingLLM confirms every article in topic verification
+type confirmingLLM struct{ *llm.MockClient }
+
+func (c *confirmingLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return "[0, 1, 2, 3, 4, 5, 6, 7, 8, 9]", nil
+}
+
+func TestTopicSearchPaginatesWithScores(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		store.UpsertArticle(ctx, &domain.Article{
+			URL:     fmt.Sprintf("https://example.com/%d", i),
+			Title:   fmt.Sprintf("Climate report %d", i),
+			Summary: strings.Repeat("climate ", i),
+		})
+	}
+	cmd := &executor.FetchArticlesDiscussingSpecificTopic{
+		Repo: store, LLM: &confirmingLLM{llm.NewMockClient()}, ResponseGenerator: executor.NewResponseGenerator(store), VectorWeight: 0.5,
+	}
+
+	first, err := cmd.Execute(ctx, &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "climate", "limit": float64(2)}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if first.Pagination == nil || first.Pagination.Total != 5 || first.Pagination.Limit != 2 || first.Pagination.Offset != 0 {
+		t.Fatalf("unexpected pagination: %+v", first.Pagination)
+	}
+	if len(first.Sources) != 2 || first.Sources[0].Score <= 0 || first.Sources[0].Score <= first.Sources[1].Score {
+		t.Fatalf("expected 2 sources with descending scores, got %+v", first.Sources)
+	}
+	if !strings.Contains(first.Answer, "Showing results 1-2 of 5") {
+		t.Errorf("expected a next-page hint, got:\n%s", first.Answer)
+	}
+
+	last, err := cmd.Execute(ctx, &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "climate", "limit": float64(2), "offset": float64(4)}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(last.Sources) != 1 || last.Pagination.Offset != 4 || strings.Contains(last.Answer, "Showing results") {
+		t.Errorf("expected the final page with one article, got %+v\n%s", last.Sources, last.Answer)
+	}
+	for _, s := range first.Sources {
+		if s.URL == last.Sources[0].URL {
+			t.Errorf("pages overlap on %s", s.URL)
+		}
+	}
+
+	beyond, _ := cmd.Execute(ctx, &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "climate", "offset": float64(10)}}, "")
+	if !strings.Contains(beyond.Answer, "No more articles") {
+		t.Errorf("expected a no-more-results answer, got %q", beyond.Answer)
+	}
+}
+
+// relevanceLLM answers the batched topic validation with the numbers of the candidates whose
+// title is not "Off-topic", or fails when failing is set
+type relevanceLLM struct {
+	*llm.MockClient
+	calls   int
+	failing bool
+}
+
+func (c *relevanceLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.failing {
+		return "", fmt.Errorf("provider unavailable")
+	}
+	var relevant []string
+	for i := 0; strings.Contains(prompt, fmt.Sprintf("[%d] Title: ", i)); i++ {
+		if !strings.Contains(prompt, fmt.Sprintf("[%d] Title: Off-topic", i)) {
+			relevant = append(relevant, strconv.Itoa(i))
+		}
+	}
+	return "```json\n[" + strings.Join(relevant, ", ") + "]\n```", nil
+}
+
+func TestTopicValidationUsesOneCall(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	titles := []string{"Climate report 0", "Off-topic climate 1", "Climate report 2", "Off-topic climate 3", "Climate report 4"}
+	for i, title := range titles {
+		store.UpsertArticle(ctx, &domain.Article{
+			URL:     fmt.Sprintf("https://example.com/%d", i),
+			Title:   title,
+			Summary: strings.Repeat("climate ", 5-i),
+		})
+	}
+	fake := &relevanceLLM{MockClient: llm.NewMockClient()}
+	cmd := &executor.FetchArticlesDiscussingSpecificTopic{
+		Repo: store, LLM: fake, ResponseGenerator: executor.NewResponseGenerator(store), VectorWeight: 0.5,
+	}
+	plan := &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "climate", "limit": float64(5)}}
+
+	resp, err := cmd.Execute(ctx, plan, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if fake.calls != 1 {
+		t.Errorf("expected one validation call, got %d", fake.calls)
+	}
+	if len(resp.Sources) != 3 {
+		t.Fatalf("expected the off-topic articles to be dropped, got %+v", resp.Sources)
+	}
+	for i, want := range []string{"Climate report 0", "Climate report 2", "Climate report 4"} {
+		if resp.Sources[i].Title != want {
+			t.Errorf("expected %s at %d in ranked order, got %+v", want, i, resp.Sources)
+		}
+	}
+
+	fake.failing = true
+	resp, err = cmd.Execute(ctx, plan, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(resp.Sources) != 5 {
+		t.Errorf("expected every article to be kept when validation fails, got %+v", resp.Sources)
+	}
+}
+
+func TestParseRelevantIndexes(t *testing.T) {
+	for response, want := range map[string][]int{
+		"[0, 2]":                          {0, 2},
+		"```json\n[1]\n```":               {1},
+		"Articles [3] and [4] do: [3, 4]": {3, 4},
+		"[]":                              {},
+	} {
+		got, err := executor.ParseRelevantIndexes(response)
+		if err != nil {
+			t.Errorf("%q: %v", response, err)
+			continue
+		}
+		if len(got) != len(want) {
+			t.Errorf("%q: expected %v, got %v", response, want, got)
+		}
+		for _, i := range want {
+			if !got[i] {
+				t.Errorf("%q: expected %d to be relevant", response, i)
+			}
+		}
+	}
+	for _, response := range []string{"YES", "[first, second]"} {
+		if _, err := executor.ParseRelevantIndexes(response); err == nil {
+			t.Errorf("%q: expected an error", response)
+		}
+	}
+}
+
+func TestTopicValidationSkippedWhenBudgetLow(t *testing.T) {
+	store := repository.NewMemoryStore()
+	for i, title := range []string{"Climate report 0", "Off-topic climate 1"} {
+		store.UpsertArticle(context.Background(), &domain.Article{
+			URL: fmt.Sprintf("https://example.com/%d", i), Title: title, Summary: "climate",
+		})
+	}
+	fake := &relevanceLLM{MockClient: llm.NewMockClient()}
+	cmd := &executor.FetchArticlesDiscussingSpecificTopic{
+		Repo: store, LLM: fake, ResponseGenerator: executor.NewResponseGenerator(store), VectorWeight: 0.5,
+	}
+
+	ctx, cancel := budget.NewContext(context.Background(), 2*time.Second)
+	defer cancel()
+	resp, err := cmd.Execute(ctx, &domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "climate"}}, "")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if fake.calls != 0 || len(resp.Sources) != 2 {
+		t.Errorf("expected both articles kept without a validation call, got %d calls and %+v", fake.calls, resp.Sources)
+	}
+	if got := budget.Skipped(ctx); len(got) != 1 || got[0] != budget.StepTopicValidation {
+		t.Errorf("expected topic validation recorded as skipped, got %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/middleware"
+	"article-assistant/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// installRecorder routes spans to an in-memory recorder for the duration of a test
+func installRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+type stubCommand struct{}
+
+func (stubCommand) Execute(ctx context.Context, plan *domain.Plan, query string) (*domain.ChatResponse, error) {
+	return &domain.ChatResponse{Answer: "ok", Task: plan.Command}, nil
+}
+
+func TestTracingSpansFollowChatPipeline(t *testing.T) {
+	recorder := installRecorder(t)
+
+	client := tracing.WrapLLM(llm.NewMockClient(), "mock", "mock-model")
+	exec := executor.NewExecutor()
+	exec.Register("summary", stubCommand{})
+
+	h := middleware.Trace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plan := &domain.Plan{Command: "summary"}
+		if _, err := client.GenerateText(r.Context(), "plan this"); err != nil {
+			t.Fatalf("GenerateText failed: %v", err)
+		}
+		if _, err := exec.Execute(r.Context(), plan, "summarize"); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/chat", nil))
+
+	spans := recorder.Ended()
+	byName := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+	root, ok := byName["POST /chat"]
+	if !ok {
+		t.Fatalf("expected HTTP server span, got %d spans", len(spans))
+	}
+	for _, name := range []string{"llm.GenerateText", "executor.summary"} {
+		s, ok := byName[name]
+		if !ok {
+			t.Fatalf("expected span %s", name)
+		}
+		if s.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("expected %s to be a child of the HTTP span", name)
+		}
+	}
+}
+
+func TestTraceMiddlewareContinuesIncomingTrace(t *testing.T) {
+	recorder := installRecorder(t)
+	tracing.Setup(context.Background()) // Installs the W3C propagator; no exporter without an endpoint
+
+	h := middleware.Trace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected incoming trace ID to be continued, got %s", got)
+	}
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+)
+
+func TestTrendInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		window   time.Duration
+		want     string
+		ok       bool
+	}{
+		{"", 30 * 24 * time.Hour, "day", true},
+		{"", 90 * 24 * time.Hour, "week", true},
+		{"Weekly", 7 * 24 * time.Hour, "week", true},
+		{"day", 90 * 24 * time.Hour, "day", true},
+		{"hour", time.Hour, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := executor.TrendInterval(tt.interval, tt.window)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("TrendInterval(%q, %v) = %q, %v; want %q, %v", tt.interval, tt.window, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTrendDirection(t *testing.T) {
+	bucket := func(score float64) domain.SentimentBucket {
+		return domain.SentimentBucket{Articles: 1, AverageScore: score}
+	}
+	tests := []struct {
+		name    string
+		buckets []domain.SentimentBucket
+		want    string
+	}{
+		{"rising", []domain.SentimentBucket{bucket(0.4), bucket(0.5), bucket(0.7)}, "rising"},
+		{"falling", []domain.SentimentBucket{bucket(0.8), bucket(0.6)}, "falling"},
+		{"small move", []domain.SentimentBucket{bucket(0.5), bucket(0.52)}, "stable"},
+		{"single bucket", []domain.SentimentBucket{bucket(0.9)}, "stable"},
+	}
+	for _, tt := range tests {
+		if got := executor.TrendDirection(tt.buckets); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSentimentTrend(t *testing.T) {
+	week := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	answer := executor.FormatSentimentTrend(executor.SentimentTrend{
+		Topic:     "AI",
+		Interval:  "week",
+		Since:     week.AddDate(0, 0, -7),
+		Direction: "falling",
+		Buckets: []domain.SentimentBucket{
+			{Start: week.AddDate(0, 0, -7), Articles: 2, AverageScore: 0.8, Positive: 2},
+			{Start: week, Articles: 3, AverageScore: 0.3, Negative: 2, Neutral: 1},
+		},
+	})
+	for _, want := range []string{"'AI'", "falling", "5 articles", "0.80 → 0.30", "week of 2024-05-06"} {
+		if !strings.Contains(answer, want) {
+			t.Errorf("expected %q in answer:\n%s", want, answer)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/tenant"
+	"article-assistant/internal/usage"
+)
+
+func TestEstimateCostUsesLongestModelPrefix(t *testing.T) {
+	mini := usage.EstimateCost("gpt-4o-mini-2024-07-18", 1_000_000, 0)
+	full := usage.EstimateCost("gpt-4o", 1_000_000, 0)
+	if math.Abs(mini-0.15) > 1e-9 || math.Abs(full-2.50) > 1e-9 {
+		t.Errorf("unexpected prices: mini=%v full=%v", mini, full)
+	}
+	if usage.EstimateCost("unknown-model", 1000, 1000) != 0 {
+		t.Error("unknown models should cost 0")
+	}
+}
+
+func TestRecordAggregatesRequestAndDailyUsage(t *testing.T) {
+	ledger := usage.NewLedger(2)
+	day := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	ledger.SetClock(func() time.Time { return day })
+
+	tracker := usage.NewTracker()
+	ctx := usage.NewContext(context.Background(), tracker)
+	usage.Record(ctx, ledger, "gpt-4-turbo", 100, 50)
+	usage.Record(ctx, ledger, "text-embedding-3-small", 10, 0)
+	// Background work without a request tracker still counts toward daily totals
+	usage.Record(context.Background(), ledger, "gpt-4-turbo", 1000, 0)
+
+	u := tracker.Usage()
+	if u.PromptTokens != 110 || u.CompletionTokens != 50 || u.Tokens != 160 || u.Cost <= 0 {
+		t.Errorf("unexpected request usage: %+v", u)
+	}
+	if len(u.Models) != 2 || u.Models[0] != "gpt-4-turbo" || u.Models[1] != "text-embedding-3-small" {
+		t.Errorf("expected the sorted models called, got %v", u.Models)
+	}
+
+	daily := ledger.Daily(tenant.Default)
+	if len(daily) != 1 || daily[0].Date != "2024-05-10" || daily[0].Calls != 3 || daily[0].Tokens != 1160 {
+		t.Fatalf("unexpected daily totals: %+v", daily)
+	}
+
+	// Days outside retention are dropped when a new day starts
+	ledger.SetClock(func() time.Time { return day.AddDate(0, 0, 2) })
+	usage.Record(context.Background(), ledger, "gpt-4-turbo", 1, 1)
+	daily = ledger.Daily(tenant.Default)
+	if len(daily) != 1 || daily[0].Date != "2024-05-12" {
+		t.Errorf("expected only the latest day, got %+v", daily)
+	}
+}
+
+func TestTrackerMergesModels(t *testing.T) {
+	tracker := usage.NewTracker()
+	tracker.Add("gpt-4o", 10, 5)
+	tracker.AddUsage(domain.Usage{Tokens: 3, Models: []string{"gpt-4", "gpt-4o"}})
+	tracker.Add("gpt-4", 1, 1)
+
+	u := tracker.Usage()
+	if len(u.Models) != 2 || u.Models[0] != "gpt-4" || u.Models[1] != "gpt-4o" {
+		t.Fatalf("expected each model once, got %v", u.Models)
+	}
+	u.Models[0] = "changed"
+	if tracker.Usage().Models[0] != "gpt-4" {
+		t.Error("expected Usage to return a copy of the models")
+	}
+}
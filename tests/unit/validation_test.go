@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"article-assistant/internal/domain"
+	"article-assistant/internal/llm"
 )
 
 // validationLLM simulates OpenAI responses for testing the 8 supported queries
@@ -78,7 +79,7 @@ func (f *validationLLM) PlanQuery(_ context.Context, query string) (*domain.Plan
 }
 
 // Mock implementations for other LLM methods (not used in validation tests)
-func (f *validationLLM) Summarize(ctx context.Context, text string) (string, error) {
+func (f *validationLLM) Summarize(ctx context.Context, text string, opts llm.SummaryOptions) (string, error) {
 	return "Mock summary", nil
 }
 
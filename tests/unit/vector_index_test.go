@@ -0,0 +1,28 @@
+package unit
+
+import (
+	"testing"
+
+	"article-assistant/internal/repository"
+)
+
+func TestIVFFlatListsScaleWithRows(t *testing.T) {
+	for _, tc := range []struct{ rows, want int }{
+		{0, 1},
+		{300, 1},
+		{5_000, 5},
+		{1_000_000, 1000},
+		{4_000_000, 2000},
+	} {
+		if got := repository.IVFFlatLists(tc.rows); got != tc.want {
+			t.Errorf("IVFFlatLists(%d) = %d, want %d", tc.rows, got, tc.want)
+		}
+	}
+}
+
+func TestDefaultVectorIndexConfig(t *testing.T) {
+	cfg := repository.DefaultVectorIndexConfig()
+	if cfg.Method != repository.IndexIVFFlat || cfg.Lists != 0 {
+		t.Errorf("expected ivfflat with lists sized from rows, got %+v", cfg)
+	}
+}
@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/repository"
+)
+
+// fakeWeaviate records indexed objects and answers nearVector queries with a fixed ranking
+type fakeWeaviate struct {
+	mu      sync.Mutex
+	classes []string
+	objects map[string]string // ID -> URL
+	deleted []string
+	queries []string
+	ranking []string // URLs returned by every query
+}
+
+func (f *fakeWeaviate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/schema/"):
+		http.NotFound(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/schema":
+		var class struct {
+			Class string `json:"class"`
+		}
+		json.NewDecoder(r.Body).Decode(&class)
+		f.classes = append(f.classes, class.Class)
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/batch/objects":
+		var batch struct {
+			Objects []struct {
+				ID         string            `json:"id"`
+				Properties map[string]string `json:"properties"`
+			} `json:"objects"`
+		}
+		json.NewDecoder(r.Body).Decode(&batch)
+		for _, o := range batch.Objects {
+			f.objects[o.ID] = o.Properties["url"]
+		}
+		w.Write([]byte(`[]`))
+	case r.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/graphql":
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.queries = append(f.queries, body.Query)
+		var hits []map[string]string
+		for _, u := range f.ranking {
+			hits = append(hits, map[string]string{"url": u})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"Get": map[string]interface{}{"Article": hits}}})
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestWeaviateStoreIndexesAndSearches(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeWeaviate{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	base := repository.NewMemoryStore()
+	before := &domain.Article{URL: "https://example.com/old", Embedding: []float32{0, 1}}
+	base.UpsertArticle(ctx, before)
+
+	store, err := repository.NewWeaviateStore(ctx, base, server.URL, "")
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	if len(fake.classes) != 1 || fake.classes[0] != repository.DefaultWeaviateClass {
+		t.Errorf("expected missing class to be created, got %v", fake.classes)
+	}
+
+	article := &domain.Article{URL: "https://example.com/new", Title: "New", Embedding: []float32{1, 0}}
+	if err := store.UpsertArticle(ctx, article); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if fake.objects[article.ID] != article.URL {
+		t.Errorf("expected upsert to index the article, got %v", fake.objects)
+	}
+	if n, err := store.Backfill(ctx); err != nil || n != 2 || fake.objects[before.ID] != before.URL {
+		t.Errorf("expected backfill to index both articles, got %d, %v, %v", n, err, fake.objects)
+	}
+
+	// Results follow Weaviate's ranking, not the wrapped store's
+	fake.ranking = []string{"https://example.com/old", "https://example.com/new", "https://example.com/gone"}
+	results, err := store.GetArticlesByVectorSearchWithFilter(ctx, []float32{1, 0}, 5, []string{"https://example.com/old", "https://example.com/new"}, domain.ArticleFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 || results[0].URL != "https://example.com/old" || results[1].URL != "https://example.com/new" {
+		t.Errorf("expected Weaviate ranking without unknown URLs, got %+v", results)
+	}
+	if len(fake.queries) != 1 || !strings.Contains(fake.queries[0], "ContainsAny") || !strings.Contains(fake.queries[0], "limit: 5") {
+		t.Errorf("unexpected query: %v", fake.queries)
+	}
+
+	// Metadata filters are answered by the wrapped store
+	filtered, err := store.GetArticlesByVectorSearchWithFilter(ctx, []float32{1, 0}, 5, nil, domain.ArticleFilter{Author: "nobody"})
+	if err != nil || len(filtered) != 0 || len(fake.queries) != 1 {
+		t.Errorf("expected filtered search to skip Weaviate, got %+v, %v", filtered, err)
+	}
+
+	if deleted, err := store.DeleteArticleByURL(ctx, article.URL); err != nil || !deleted {
+		t.Fatalf("delete: %v, %v", deleted, err)
+	}
+	if len(fake.deleted) != 1 || !strings.HasSuffix(fake.deleted[0], "/"+article.ID) {
+		t.Errorf("expected the Weaviate object to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestWeaviateStoreReportsRejectedObjects(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/batch/objects" {
+			w.Write([]byte(`[{"result":{"errors":{"error":[{"message":"vector dimension mismatch"}]}}}]`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	store, err := repository.NewWeaviateStore(ctx, repository.NewMemoryStore(), server.URL, "")
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	err = store.UpsertArticle(ctx, &domain.Article{URL: "https://example.com/a", Embedding: []float32{1}})
+	if err == nil || !strings.Contains(err.Error(), "vector dimension mismatch") {
+		t.Errorf("expected the rejection to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/web"
+)
+
+func TestDashboardServesPageAndAssets(t *testing.T) {
+	handler := web.Handler()
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	page := serve("GET", "/")
+	if page.Code != 200 || !strings.HasPrefix(page.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET / = %d %s, want the HTML page", page.Code, page.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{`id="chat-form"`, `id="ingest-form"`, `id="article-list"`, `src="/ui/app.js"`} {
+		if !strings.Contains(page.Body.String(), want) {
+			t.Errorf("page missing %s", want)
+		}
+	}
+	if csp := page.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "default-src 'self'") {
+		t.Errorf("expected a same-origin content security policy, got %q", csp)
+	}
+
+	script := serve("GET", "/ui/app.js")
+	if script.Code != 200 || !strings.Contains(script.Header().Get("Content-Type"), "javascript") || !strings.Contains(script.Body.String(), `"/chat"`) {
+		t.Errorf("GET /ui/app.js = %d %s", script.Code, script.Header().Get("Content-Type"))
+	}
+	if css := serve("GET", "/ui/style.css"); css.Code != 200 || !strings.HasPrefix(css.Header().Get("Content-Type"), "text/css") {
+		t.Errorf("GET /ui/style.css = %d %s", css.Code, css.Header().Get("Content-Type"))
+	}
+
+	for _, path := range []string{"/nope", "/ui/missing.js", "/ui/", "/ui/index.html", "/ui/../web.go"} {
+		rec := serve("GET", path)
+		var body domain.APIError
+		if rec.Code != 404 || json.Unmarshal(rec.Body.Bytes(), &body) != nil || body.Code != domain.ErrCodeNotFound {
+			t.Errorf("GET %s = %d %s, want a NOT_FOUND error", path, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := serve("POST", "/"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST / = %d, want 405", rec.Code)
+	}
+}
+
+func TestDashboardPathsAreOpen(t *testing.T) {
+	paths := web.Paths()
+	for _, want := range []string{"/", "/ui/app.js", "/ui/style.css"} {
+		if !slices.Contains(paths, want) {
+			t.Errorf("Paths() = %v, missing %s", paths, want)
+		}
+	}
+	if slices.Contains(paths, "/ui/index.html") {
+		t.Error("expected the page to be served only at /")
+	}
+}
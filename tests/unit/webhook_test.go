@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/processing"
+	"article-assistant/internal/tenant"
+	"article-assistant/internal/webhook"
+)
+
+// stubLookup serves a fixed article
+type stubLookup struct {
+	article *domain.Article
+}
+
+func (s *stubLookup) GetArticleByURL(ctx context.Context, url string) (*domain.Article, error) {
+	return s.article, nil
+}
+
+func (s *stubLookup) GetDuplicateByURL(ctx context.Context, url string) (*domain.Article, error) {
+	return nil, nil
+}
+
+func TestWebhookSendsSignedPayload(t *testing.T) {
+	var got webhook.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("secret", body, r.Header.Get(webhook.SignatureHeader)) {
+			t.Error("signature did not verify")
+		}
+		if r.Header.Get(webhook.EventHeader) != webhook.EventIngestCompleted {
+			t.Errorf("unexpected event header %q", r.Header.Get(webhook.EventHeader))
+		}
+		json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+
+	lookup := &stubLookup{article: &domain.Article{
+		ID: "a1", Title: "AI chips", Sentiment: "positive", SentimentScore: 0.8,
+		Topics: []domain.SemanticTopic{{Name: "AI"}, {Name: "Hardware"}},
+	}}
+	st := processing.Status{ID: "s1", URL: "https://example.com/a", State: processing.StatusComplete, UpdatedAt: time.Now()}
+
+	ctx := tenant.NewContext(context.Background(), "newsroom")
+	n := webhook.NewNotifier([]string{server.URL}, "secret")
+	if err := n.Send(ctx, webhook.IngestPayload(ctx, lookup, st)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ArticleID != "a1" || got.Title != "AI chips" || got.Sentiment != "positive" || len(got.Topics) != 2 {
+		t.Errorf("unexpected payload %+v", got)
+	}
+	if got.Tenant != "newsroom" {
+		t.Errorf("expected the payload to name the context's tenant, got %q", got.Tenant)
+	}
+}
+
+func TestAlertWebhookNamesTenant(t *testing.T) {
+	var got webhook.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	hook := webhook.AlertHook(webhook.NewNotifier([]string{server.URL}, ""))
+	search := domain.SavedSearch{ID: "s1", Topic: "AI"}
+	alert := domain.Alert{ArticleID: "a1", URL: "https://example.com/a", CreatedAt: time.Now()}
+
+	hook(tenant.NewContext(context.Background(), "research"), search, alert)
+	if got.Event != webhook.EventAlertMatched || got.SearchID != "s1" || got.Tenant != "research" {
+		t.Errorf("expected an alert payload for the research tenant, got %+v", got)
+	}
+	// Without API keys everything belongs to the default tenant
+	hook(context.Background(), search, alert)
+	if got.Tenant != tenant.Default {
+		t.Errorf("expected the default tenant, got %q", got.Tenant)
+	}
+}
+
+func TestWebhookFailurePayload(t *testing.T) {
+	st := processing.Status{ID: "s2", URL: "https://example.com/b", State: processing.StatusFailed, Error: "failed to fetch content"}
+	p := webhook.IngestPayload(context.Background(), &stubLookup{}, st)
+	if p.Event != webhook.EventIngestFailed || p.Error == "" || p.ArticleID != "" {
+		t.Errorf("unexpected failure payload %+v", p)
+	}
+}
+
+func TestWebhookRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	n := webhook.NewNotifier([]string{server.URL}, "")
+	n.BaseBackoff = time.Millisecond
+	if err := n.Send(context.Background(), webhook.Payload{Event: webhook.EventIngestCompleted}); err != nil {
+		t.Fatalf("expected delivery after retry, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestFacadeCallsFinishHooks(t *testing.T) {
+	f := newTestFacade(&scriptedIngester{errs: []error{errors.New("failed to summarize: invalid request")}})
+	finished := make(chan processing.Status, 1)
+	f.OnFinish(func(ctx context.Context, st processing.Status) { finished <- st })
+
+	f.AddNewArticle(context.Background(), "https://example.com/c", time.Second)
+	select {
+	case st := <-finished:
+		if st.State != processing.StatusFailed {
+			t.Errorf("expected failed status, got %+v", st)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("finish hook was not called")
+	}
+}
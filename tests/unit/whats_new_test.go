@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"article-assistant/internal/domain"
+	"article-assistant/internal/executor"
+	"article-assistant/internal/llm"
+	"article-assistant/internal/repository"
+	"article-assistant/internal/session"
+)
+
+func TestResolveSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	lastAsked := time.Date(2024, 5, 9, 8, 0, 0, 0, time.UTC)
+
+	memo := session.NewMemo()
+	memo.MarkAsked("AI Regulation", lastAsked)
+
+	tests := []struct {
+		name     string
+		since    string
+		memo     *session.Memo
+		expected time.Time
+	}{
+		{"today", "today", nil, time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", "yesterday", nil, time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{"days", "3d", nil, now.AddDate(0, 0, -3)},
+		{"last month", "last_month", nil, time.Date(2024, 4, 10, 15, 30, 0, 0, time.UTC)},
+		{"hours", "6h", nil, now.Add(-6 * time.Hour)},
+		{"date", "2024-05-01", nil, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"last asked from session", "last_asked", memo, lastAsked},
+		{"empty uses session", "", memo, lastAsked},
+		{"no session falls back", "", nil, now.Add(-24 * time.Hour)},
+		{"garbage falls back", "whenever", nil, now.Add(-24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := executor.ResolveSince(tt.since, now, tt.memo, "ai regulation")
+			if !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// updateLLM fails GenerateText while failing is set
+type updateLLM struct {
+	*llm.MockClient
+	failing bool
+}
+
+func (u *updateLLM) GenerateText(ctx context.Context, prompt string) (string, error) {
+	if u.failing {
+		return "", errors.New("provider unavailable")
+	}
+	return "An update [1]", nil
+}
+
+func TestWhatsNewMarksAskedOnlyAfterAnswering(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := &updateLLM{MockClient: llm.NewMockClient(), failing: true}
+	embedding, _ := client.Embed(context.Background(), "ai")
+	store.UpsertArticle(context.Background(), &domain.Article{URL: "https://ai.com/new", Title: "New AI rules", Status: domain.ArticleEnriched, Embedding: embedding})
+
+	memo := session.NewMemo()
+	ctx := session.NewContext(context.Background(), memo)
+	cmd := &executor.WhatsNewCommand{Repo: store, LLM: client, ResponseGenerator: executor.NewResponseGenerator(store)}
+	plan := &domain.Plan{Command: "whats_new", Args: map[string]interface{}{"filter": "ai"}}
+
+	if resp, err := cmd.Execute(ctx, plan, ""); err != nil || resp.Error == nil {
+		t.Fatalf("expected an error response while the LLM fails, got %+v, %v", resp, err)
+	}
+	if at, ok := memo.LastAsked("ai"); ok {
+		t.Fatalf("a failed answer moved the reference point to %v", at)
+	}
+
+	client.failing = false
+	if resp, err := cmd.Execute(ctx, plan, ""); err != nil || len(resp.Sources) != 1 {
+		t.Fatalf("expected the new article, got %+v, %v", resp, err)
+	}
+	if _, ok := memo.LastAsked("ai"); !ok {
+		t.Error("an answered question did not move the reference point")
+	}
+}
+
+func TestWhatsNewIncludesRefreshedArticles(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	client := &updateLLM{MockClient: llm.NewMockClient()}
+	embedding, _ := client.Embed(ctx, "ai")
+	refreshed := &domain.Article{URL: "https://ai.com/rules", Title: "AI rules", Summary: "Draft rules", Status: domain.ArticleEnriched, Embedding: embedding}
+	store.UpsertArticle(ctx, refreshed)
+	store.UpsertArticle(ctx, &domain.Article{URL: "https://ai.com/old", Title: "Old AI news", Status: domain.ArticleEnriched, Embedding: embedding})
+
+	memo := session.NewMemo()
+	memo.MarkAsked("ai", time.Now())
+	time.Sleep(time.Millisecond)
+	// A refresh that finds changed content stores the article again
+	refreshed.Summary = "Final rules"
+	store.UpsertArticle(ctx, refreshed)
+
+	cmd := &executor.WhatsNewCommand{Repo: store, LLM: client, ResponseGenerator: executor.NewResponseGenerator(store)}
+	plan := &domain.Plan{Command: "whats_new", Args: map[string]interface{}{"filter": "ai"}}
+	resp, err := cmd.Execute(session.NewContext(ctx, memo), plan, "")
+	if err != nil || len(resp.Sources) != 1 || resp.Sources[0].URL != refreshed.URL {
+		t.Fatalf("expected only the refreshed article, got %+v, %v", resp, err)
+	}
+}
+
+func TestTimeRelative(t *testing.T) {
+	for _, tc := range []struct {
+		plan domain.Plan
+		want bool
+	}{
+		{domain.Plan{Command: "whats_new", Args: map[string]interface{}{"filter": "ai", "since": "2024-05-01"}}, true},
+		{domain.Plan{Command: "sentiment_trend"}, true},
+		{domain.Plan{Command: "digest", Args: map[string]interface{}{"published_after": "last_week"}}, true},
+		{domain.Plan{Command: "digest", Args: map[string]interface{}{"published_after": "2024-05-01", "published_before": "2024-06-01T00:00:00Z"}}, false},
+		{domain.Plan{Command: "filter_by_specific_topic", Args: map[string]interface{}{"filter": "ai"}}, false},
+	} {
+		if got := executor.TimeRelative(&tc.plan); got != tc.want {
+			t.Errorf("TimeRelative(%s %v) = %v, want %v", tc.plan.Command, tc.plan.Args, got, tc.want)
+		}
+	}
+}
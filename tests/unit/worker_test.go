@@ -0,0 +1,196 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"article-assistant/internal/processing"
+	"article-assistant/internal/worker"
+)
+
+func TestManagerDrainWaitsForRunningWork(t *testing.T) {
+	m := worker.NewManager(context.Background())
+	var finished atomic.Bool
+	m.Go(func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		finished.Store(true)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Drain(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Drain returned before work finished")
+	}
+	if m.Go(func(ctx context.Context) {}) {
+		t.Error("expected Go to refuse work after Drain")
+	}
+}
+
+func TestManagerDrainCancelsAtDeadline(t *testing.T) {
+	m := worker.NewManager(context.Background())
+	var cancelled atomic.Bool
+	m.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		cancelled.Store(true)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !cancelled.Load() {
+		t.Error("expected work context to be cancelled at the deadline")
+	}
+}
+
+func TestManagerStopEndsPeriodicTasks(t *testing.T) {
+	m := worker.NewManager(context.Background())
+	m.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Stop(ctx); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPoolRejectsWhenQueueIsFull(t *testing.T) {
+	p := worker.NewPool(context.Background(), "test", 1, 1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.TrySubmit(func(ctx context.Context) { close(started); <-release }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-started
+	if err := p.TrySubmit(func(ctx context.Context) {}); err != nil {
+		t.Fatalf("expected the job to be queued, got %v", err)
+	}
+	if depth := p.QueueDepth(); depth != 1 {
+		t.Errorf("QueueDepth() = %d, want 1", depth)
+	}
+	if err := p.TrySubmit(func(ctx context.Context) {}); !errors.Is(err, worker.ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+
+	var metrics strings.Builder
+	p.WritePrometheus(&metrics)
+	for _, want := range []string{`worker_queue_depth{pool="test"} 1`, `worker_active{pool="test"} 1`} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, metrics.String())
+		}
+	}
+	close(release)
+}
+
+func TestPoolAppliesJobTimeout(t *testing.T) {
+	p := worker.NewPool(context.Background(), "test", 1, 1, 10*time.Millisecond)
+	result := make(chan error, 1)
+	p.TrySubmit(func(ctx context.Context) {
+		<-ctx.Done()
+		result <- ctx.Err()
+	})
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job was not cancelled at its timeout")
+	}
+}
+
+func TestPoolDrainReleasesBlockedSubmit(t *testing.T) {
+	p := worker.NewPool(context.Background(), "test", 1, 1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.TrySubmit(func(ctx context.Context) { close(started); <-release })
+	<-started
+	p.TrySubmit(func(ctx context.Context) {})
+
+	submitted := make(chan error, 1)
+	go func() { submitted <- p.Submit(context.Background(), func(ctx context.Context) {}) }()
+	time.Sleep(10 * time.Millisecond)
+
+	drained := make(chan error, 1)
+	go func() { drained <- p.Drain(context.Background()) }()
+	if err := <-submitted; !errors.Is(err, worker.ErrClosed) {
+		t.Errorf("expected ErrClosed from blocked Submit, got %v", err)
+	}
+	close(release)
+	if err := <-drained; err != nil {
+		t.Errorf("unexpected drain error: %v", err)
+	}
+}
+
+func TestFacadeFinishesInFlightIngestsOnDrain(t *testing.T) {
+	ing := &scriptedIngester{delay: 30 * time.Millisecond}
+	f := newTestFacade(ing)
+	f.Pool = processing.NewIngestPool(context.Background())
+
+	st, err := f.Enqueue(context.Background(), "https://example.com/drain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := f.Pool.Drain(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := f.Status(st.ID); got.State != processing.StatusComplete {
+		t.Errorf("expected in-flight ingest to complete, got %+v", got)
+	}
+
+	late, _ := f.AddNewArticle(context.Background(), "https://example.com/late", time.Second)
+	if !late.Rejected() || late.Error != worker.ErrClosed.Error() {
+		t.Errorf("expected ingest after shutdown to be rejected, got %+v", late)
+	}
+}
+
+func TestFacadeSubmitRejectsWhenQueueIsFull(t *testing.T) {
+	f := newTestFacade(&scriptedIngester{})
+	f.Pool = worker.NewPool(context.Background(), "ingest", 1, 1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	f.Pool.TrySubmit(func(ctx context.Context) { close(started); <-release })
+	<-started
+
+	queued := f.Submit(context.Background(), "https://example.com/queued")
+	if queued.State != processing.StatusProcessing || queued.ID == "" {
+		t.Fatalf("expected the first URL to be queued, got %+v", queued)
+	}
+	full := f.Submit(context.Background(), "https://example.com/full")
+	if !full.Rejected() || full.Error != worker.ErrQueueFull.Error() {
+		t.Errorf("expected the second URL to be rejected, got %+v", full)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if st, _ := f.Status(queued.ID); st.State == processing.StatusComplete {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the queued URL to complete")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}